@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/admin"
+	userStorage "github.com/kuvalkin/gophkeeper/internal/server/storage/user"
+)
+
+func newAdminCommand(config *viper.Viper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Manage the users of the server",
+		Long:  "Inspect, disable, log out and delete the users. The commands work with the database directly, the server may be running.",
+	}
+
+	cmd.AddCommand(newAdminUsersCommand(config))
+	cmd.AddCommand(newAdminDisableCommand(config, true))
+	cmd.AddCommand(newAdminDisableCommand(config, false))
+	cmd.AddCommand(newAdminResetSessionsCommand(config))
+	cmd.AddCommand(newAdminDeleteCommand(config))
+
+	return cmd
+}
+
+func newAdminUsersCommand(config *viper.Viper) *cobra.Command {
+	return &cobra.Command{
+		Use:   "users",
+		Short: "List the users with their storage usage",
+		Long:  "Lists the users with the size and the number of their entries, including the ones of the vaults they own, as counted against the quotas.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withAdminService(cmd.Context(), config, func(service admin.Service) error {
+				users, err := service.ListUsers(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("error listing users: %w", err)
+				}
+
+				if len(users) == 0 {
+					cmd.Println("No users found")
+
+					return nil
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "LOGIN\tSTATUS\tENTRIES\tBYTES\tREGISTERED\tSESSIONS RESET")
+
+				for _, u := range users {
+					status := "active"
+					if u.Disabled {
+						status = "disabled"
+					}
+
+					resetAt := "never"
+					if !u.SessionsResetAt.IsZero() {
+						resetAt = u.SessionsResetAt.Local().Format(time.DateTime)
+					}
+
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n", u.Login, status, u.Usage.Entries, u.Usage.Bytes, u.CreatedAt.Local().Format(time.DateTime), resetAt)
+				}
+
+				err = w.Flush()
+				if err != nil {
+					return fmt.Errorf("error printing users: %w", err)
+				}
+
+				return nil
+			})
+		},
+	}
+}
+
+func newAdminDisableCommand(config *viper.Viper, disabled bool) *cobra.Command {
+	use, short, done := "enable <login>", "Let a disabled user log in again", "enabled"
+	if disabled {
+		use, short, done = "disable <login>", "Keep a user from logging in and reject the user's tokens", "disabled"
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  short + ". The running servers apply the change once their cache of the user expires, see TOKEN_USER_CACHE_TTL.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withAdminService(cmd.Context(), config, func(service admin.Service) error {
+				err := service.SetDisabled(cmd.Context(), args[0], disabled)
+				if err != nil {
+					return fmt.Errorf("error changing user: %w", err)
+				}
+
+				cmd.Printf("User %s %s\n", args[0], done)
+
+				return nil
+			})
+		},
+	}
+}
+
+func newAdminResetSessionsCommand(config *viper.Viper) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset-sessions <login>",
+		Short: "Log a user out everywhere",
+		Long: "Rejects all the tokens issued to the user so far, the user has to log in again on every device. " +
+			"The running servers reject them once their cache of the user expires, see TOKEN_USER_CACHE_TTL.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withAdminService(cmd.Context(), config, func(service admin.Service) error {
+				err := service.ResetSessions(cmd.Context(), args[0])
+				if err != nil {
+					return fmt.Errorf("error resetting sessions: %w", err)
+				}
+
+				cmd.Printf("Sessions of %s reset\n", args[0])
+
+				return nil
+			})
+		},
+	}
+}
+
+func newAdminDeleteCommand(config *viper.Viper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <login>",
+		Short: "Delete a user with everything the user stores",
+		Long: "Deletes the user along with the entries, their history, the trash, the shares by and with the user, " +
+			"the vault memberships and the emergency grants. The vaults the user is the only member of are deleted with their entries. " +
+			"A user who is the last owner of a vault with other members can't be deleted, make someone else an owner first. " +
+			"It can't be undone.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			yes, err := cmd.Flags().GetBool("yes")
+			if err != nil {
+				return fmt.Errorf("error getting yes flag: %w", err)
+			}
+
+			if !yes {
+				return fmt.Errorf("deleting %s can't be undone, pass --yes to confirm", args[0])
+			}
+
+			return withAdminService(cmd.Context(), config, func(service admin.Service) error {
+				result, err := service.DeleteUser(cmd.Context(), args[0])
+				if errors.Is(err, admin.ErrLastOwner) {
+					return fmt.Errorf("%s is the last owner of a vault with other members, make someone else an owner first", args[0])
+				}
+				if err != nil {
+					return fmt.Errorf("error deleting user: %w", err)
+				}
+
+				cmd.Printf("User %s deleted, %d blobs removed\n", args[0], result.Blobs)
+				if len(result.Vaults) > 0 {
+					cmd.Printf("Deleted vaults: %s\n", strings.Join(result.Vaults, ", "))
+				}
+
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().Bool("yes", false, "Confirm the deletion")
+
+	return cmd
+}
+
+// withAdminService connects to the database and the blob store, and calls f with the admin service.
+func withAdminService(ctx context.Context, config *viper.Viper, f func(service admin.Service) error) error {
+	db, err := initDB(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	service, err := initAdminService(ctx, config, db)
+	if err != nil {
+		return fmt.Errorf("failed to initialize services: %w", err)
+	}
+
+	return f(service)
+}
+
+func initAdminService(ctx context.Context, config *viper.Viper, db *sql.DB) (admin.Service, error) {
+	services, err := initServices(ctx, config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return admin.New(userStorage.NewDatabaseRepository(db), services.Entry), nil
+}
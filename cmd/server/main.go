@@ -3,19 +3,24 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	stdLog "log"
 	"net"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
 	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
 	entryStorage "github.com/kuvalkin/gophkeeper/internal/server/storage/entry"
 	userStorage "github.com/kuvalkin/gophkeeper/internal/server/storage/user"
+	vaultStorage "github.com/kuvalkin/gophkeeper/internal/server/storage/vault"
 	"github.com/kuvalkin/gophkeeper/internal/server/support/database"
 	"github.com/kuvalkin/gophkeeper/internal/server/transport"
 	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
@@ -40,6 +45,25 @@ func main() {
 
 	config := newConfig()
 
+	rootCmd := &cobra.Command{
+		Use:          "server",
+		Short:        "Gophkeeper server",
+		SilenceUsage: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			runServer(cmd.Context(), config)
+		},
+	}
+	rootCmd.AddCommand(newReconcileCommand(config))
+	rootCmd.AddCommand(newRewrapCommand(config))
+	rootCmd.AddCommand(newAdminCommand(config))
+
+	err = rootCmd.ExecuteContext(ctx)
+	if err != nil {
+		log.Logger().Fatalw("command failed", "error", err)
+	}
+}
+
+func runServer(ctx context.Context, config *viper.Viper) {
 	db, err := initDB(ctx, config)
 	if err != nil {
 		log.Logger().Fatalw("failed to initialize database", "error", err)
@@ -55,6 +79,17 @@ func main() {
 		log.Logger().Fatalw("failed to initialize server", "error", err)
 	}
 
+	if interval := config.GetDuration("reconcile.interval"); interval > 0 {
+		go reconcilePeriodically(ctx, services.Entry, interval, entry.ReconcileOptions{
+			Repair:      config.GetBool("reconcile.repair"),
+			GracePeriod: config.GetDuration("reconcile.grace_period"),
+		})
+	}
+
+	if interval := config.GetDuration("trash.purge_interval"); interval > 0 {
+		go purgeTrashPeriodically(ctx, services.Entry, interval)
+	}
+
 	serve(ctx, config.GetString("address"), server)
 
 	// if we are here, the server has been stopped
@@ -70,15 +105,69 @@ func newConfig() *viper.Viper {
 	config.MustBindEnv("token.secret", "TOKEN_SECRET")
 	config.SetDefault("token.expiration", "720h")
 	config.MustBindEnv("token.expiration", "TOKEN_EXPIRATION")
+	// the tokens are checked against the user on every request, which is cached for this long to save a query each,
+	// so disabling a user or resetting the sessions takes up to this long to reject the tokens, zero disables the cache
+	config.SetDefault("token.user_cache_ttl", "10s")
+	config.MustBindEnv("token.user_cache_ttl", "TOKEN_USER_CACHE_TTL")
 
 	config.MustBindEnv("password.salt", "PASSWORD_SALT")
 
 	config.MustBindEnv("database.dsn", "DATABASE_DSN")
 
+	// "file", "s3" or "database"
+	config.SetDefault("blob.backend", "file")
+	config.MustBindEnv("blob.backend", "BLOB_BACKEND")
 	config.MustBindEnv("blob.path", "BLOB_PATH")
+	config.MustBindEnv("blob.s3.endpoint", "BLOB_S3_ENDPOINT")
+	config.MustBindEnv("blob.s3.region", "BLOB_S3_REGION")
+	config.MustBindEnv("blob.s3.bucket", "BLOB_S3_BUCKET")
+	config.MustBindEnv("blob.s3.access_key", "BLOB_S3_ACCESS_KEY")
+	config.MustBindEnv("blob.s3.secret_key", "BLOB_S3_SECRET_KEY")
+	config.SetDefault("blob.s3.use_ssl", true)
+	config.MustBindEnv("blob.s3.use_ssl", "BLOB_S3_USE_SSL")
+	// base64 encoded 32 bytes keys, blobs are not encrypted at rest if not set
+	config.MustBindEnv("blob.encryption.key", "BLOB_ENCRYPTION_KEY")
+	// comma separated, still used to read blobs until they are re-wrapped
+	config.MustBindEnv("blob.encryption.old_keys", "BLOB_ENCRYPTION_OLD_KEYS")
+	// only while migrating, blobs stored before the encryption was enabled are read as is until they are re-wrapped
+	config.SetDefault("blob.encryption.allow_plaintext", false)
+	config.MustBindEnv("blob.encryption.allow_plaintext", "BLOB_ENCRYPTION_ALLOW_PLAINTEXT")
 	config.SetDefault("blob.chunk_size", 1024*1024) // 1MB
 	config.MustBindEnv("blob.chunk_size", "BLOB_CHUNK_SIZE")
 
+	config.SetDefault("entry.max_versions", 10)
+	config.MustBindEnv("entry.max_versions", "ENTRY_MAX_VERSIONS")
+	// per user quotas, zero means unlimited
+	config.SetDefault("entry.max_bytes", 0)
+	config.MustBindEnv("entry.max_bytes", "ENTRY_MAX_BYTES")
+	config.SetDefault("entry.max_entries", 0)
+	config.MustBindEnv("entry.max_entries", "ENTRY_MAX_ENTRIES")
+	// how often the watched change logs are polled for the changes made through other instances
+	config.SetDefault("entry.watch_interval", "2s")
+	config.MustBindEnv("entry.watch_interval", "ENTRY_WATCH_INTERVAL")
+	// how long an upload session stays busy if the instance writing to it dies
+	config.SetDefault("entry.upload_lease", "1m")
+	config.MustBindEnv("entry.upload_lease", "ENTRY_UPLOAD_LEASE")
+
+	// zero means unlimited
+	config.SetDefault("vault.max_personal", 10)
+	config.MustBindEnv("vault.max_personal", "VAULT_MAX_PERSONAL")
+
+	// deleted entries are kept in the trash for this long, zero disables the trash
+	config.SetDefault("trash.retention", "720h")
+	config.MustBindEnv("trash.retention", "TRASH_RETENTION")
+	// zero interval disables purging of the expired entries
+	config.SetDefault("trash.purge_interval", "1h")
+	config.MustBindEnv("trash.purge_interval", "TRASH_PURGE_INTERVAL")
+
+	// zero interval disables periodic reconciliation
+	config.SetDefault("reconcile.interval", 0)
+	config.MustBindEnv("reconcile.interval", "RECONCILE_INTERVAL")
+	config.SetDefault("reconcile.repair", false)
+	config.MustBindEnv("reconcile.repair", "RECONCILE_REPAIR")
+	config.SetDefault("reconcile.grace_period", "1h")
+	config.MustBindEnv("reconcile.grace_period", "RECONCILE_GRACE_PERIOD")
+
 	return config
 }
 
@@ -98,12 +187,25 @@ func initDB(ctx context.Context, config *viper.Viper) (*sql.DB, error) {
 	return db, nil
 }
 
-func initServices(_ context.Context, config *viper.Viper, db *sql.DB) (transport.Services, error) {
-	br, err := blob.NewFileBlobRepository(config.GetString("blob.path"))
+func initServices(ctx context.Context, config *viper.Viper, db *sql.DB) (transport.Services, error) {
+	br, err := initBlobRepository(ctx, config, db)
 	if err != nil {
 		return transport.Services{}, fmt.Errorf("failed to create blob repository: %w", err)
 	}
 
+	entries := entry.New(
+		entryStorage.NewDatabaseMetadataRepository(db, string(vault.RoleOwner)),
+		br,
+		entry.Options{
+			MaxVersions:    config.GetInt("entry.max_versions"),
+			MaxBytes:       config.GetInt64("entry.max_bytes"),
+			MaxEntries:     config.GetInt64("entry.max_entries"),
+			TrashRetention: config.GetDuration("trash.retention"),
+			WatchInterval:  config.GetDuration("entry.watch_interval"),
+			UploadLease:    config.GetDuration("entry.upload_lease"),
+		},
+	)
+
 	return transport.Services{
 		User: user.NewService(
 			userStorage.NewDatabaseRepository(db),
@@ -111,15 +213,82 @@ func initServices(_ context.Context, config *viper.Viper, db *sql.DB) (transport
 				TokenSecret:           []byte(config.GetString("token.secret")),
 				PasswordSalt:          config.GetString("password.salt"),
 				TokenExpirationPeriod: config.GetDuration("token.expiration"),
+				UserCacheTTL:          config.GetDuration("token.user_cache_ttl"),
 			},
 		),
-		Entry: entry.New(
-			entryStorage.NewDatabaseMetadataRepository(db),
-			br,
+		Entry: entries,
+		Vault: vault.New(
+			vaultStorage.NewDatabaseRepository(db),
+			entries,
+			vault.Options{
+				MaxPersonalVaults: config.GetInt("vault.max_personal"),
+			},
 		),
 	}, nil
 }
 
+func initBlobRepository(ctx context.Context, config *viper.Viper, db *sql.DB) (blob.Repository, error) {
+	br, err := initBlobBackend(ctx, config, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.GetString("blob.encryption.key") == "" {
+		return br, nil
+	}
+
+	masterKey, err := base64.StdEncoding.DecodeString(config.GetString("blob.encryption.key"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	var oldKeys [][]byte
+	for _, encoded := range strings.Split(config.GetString("blob.encryption.old_keys"), ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+
+		oldKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid old encryption key: %w", err)
+		}
+
+		oldKeys = append(oldKeys, oldKey)
+	}
+
+	encrypted, err := blob.NewEncryptedBlobRepository(br, masterKey, oldKeys...)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.GetBool("blob.encryption.allow_plaintext") {
+		encrypted.AllowPlaintext()
+	}
+
+	return encrypted, nil
+}
+
+func initBlobBackend(ctx context.Context, config *viper.Viper, db *sql.DB) (blob.Repository, error) {
+	switch backend := config.GetString("blob.backend"); backend {
+	case "file":
+		return blob.NewFileBlobRepository(config.GetString("blob.path"))
+	case "s3":
+		return blob.NewS3BlobRepository(ctx, blob.S3Options{
+			Endpoint:  config.GetString("blob.s3.endpoint"),
+			Region:    config.GetString("blob.s3.region"),
+			Bucket:    config.GetString("blob.s3.bucket"),
+			AccessKey: config.GetString("blob.s3.access_key"),
+			SecretKey: config.GetString("blob.s3.secret_key"),
+			UseSSL:    config.GetBool("blob.s3.use_ssl"),
+		})
+	case "database":
+		return blob.NewDatabaseBlobRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unknown blob backend %q", backend)
+	}
+}
+
 func serve(ctx context.Context, addr string, server *grpc.Server) {
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+func newReconcileCommand(config *viper.Viper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Find and repair inconsistencies between entries metadata and blobs",
+		Long: "Compares the entries metadata with the blob store and reports blobs no entry refers to, " +
+			"and entries whose blobs are missing. With --repair the orphan blobs and the unreadable entries are deleted.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repair, err := cmd.Flags().GetBool("repair")
+			if err != nil {
+				return fmt.Errorf("error getting repair flag: %w", err)
+			}
+
+			gracePeriod, err := cmd.Flags().GetDuration("grace-period")
+			if err != nil {
+				return fmt.Errorf("error getting grace period flag: %w", err)
+			}
+
+			db, err := initDB(cmd.Context(), config)
+			if err != nil {
+				return fmt.Errorf("failed to initialize database: %w", err)
+			}
+			defer db.Close()
+
+			services, err := initServices(cmd.Context(), config, db)
+			if err != nil {
+				return fmt.Errorf("failed to initialize services: %w", err)
+			}
+
+			report, err := services.Entry.Reconcile(cmd.Context(), entry.ReconcileOptions{
+				Repair:      repair,
+				GracePeriod: gracePeriod,
+			})
+			if err != nil {
+				return fmt.Errorf("reconciliation failed: %w", err)
+			}
+
+			printReport(cmd, report, repair)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("repair", false, "Delete the found orphans instead of only reporting them")
+	cmd.Flags().Duration("grace-period", config.GetDuration("reconcile.grace_period"), "Blobs modified more recently than this are never considered orphans")
+
+	return cmd
+}
+
+func printReport(cmd *cobra.Command, report entry.ReconcileReport, repair bool) {
+	cmd.Printf("Checked %d blobs referenced by metadata against %d stored blobs\n", report.Refs, report.Blobs)
+
+	cmd.Printf("Orphan blobs: %d\n", len(report.OrphanBlobs))
+	for _, key := range report.OrphanBlobs {
+		cmd.Printf("  %s\n", key)
+	}
+
+	cmd.Printf("Missing blobs: %d\n", len(report.MissingBlobs))
+	for _, ref := range report.MissingBlobs {
+		cmd.Printf("  %s of user %s, key %s, version %d\n", ref.Kind, ref.UserID, ref.Key, ref.Version)
+	}
+
+	if repair {
+		cmd.Printf("Repaired: %d\n", report.Repaired)
+	}
+}
+
+// reconcilePeriodically runs the reconciliation every interval until the context is done.
+// The results are only logged.
+func reconcilePeriodically(ctx context.Context, service entry.Service, interval time.Duration, opts entry.ReconcileOptions) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := service.Reconcile(ctx, opts)
+			if err != nil {
+				log.Logger().Errorw("periodic reconciliation failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+)
+
+func newRewrapCommand(config *viper.Viper) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rewrap-blobs",
+		Short: "Re-wrap the blobs data keys with the current master key",
+		Long: "Wraps the data keys of the blobs encrypted with the old master keys with the current one, " +
+			"and encrypts the blobs stored before the encryption was enabled if blob.encryption.allow_plaintext is set. " +
+			"After it the old keys can be removed from the config, and plaintext blobs disallowed again. " +
+			"It must be run while the servers are stopped.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := initDB(cmd.Context(), config)
+			if err != nil {
+				return fmt.Errorf("failed to initialize database: %w", err)
+			}
+			defer db.Close()
+
+			br, err := initBlobRepository(cmd.Context(), config, db)
+			if err != nil {
+				return fmt.Errorf("failed to create blob repository: %w", err)
+			}
+
+			encrypted, ok := br.(*blob.EncryptedBlobRepository)
+			if !ok {
+				return fmt.Errorf("blob encryption is not configured")
+			}
+
+			rewrapped, err := encrypted.RewrapBlobs(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("rewrap failed: %w", err)
+			}
+
+			cmd.Printf("Rewrapped %d blobs\n", rewrapped)
+
+			return nil
+		},
+	}
+}
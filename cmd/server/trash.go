@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+// purgeTrashPeriodically purges the expired entries from the trash every interval until the context is done.
+func purgeTrashPeriodically(ctx context.Context, service entry.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := service.PurgeExpiredTrash(ctx)
+			if err != nil {
+				log.Logger().Errorw("periodic trash purge failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+)
+
+func newChangesCommand(container container.Container) *cobra.Command {
+	changes := &cobra.Command{
+		Use:   "changes",
+		Short: "Show changes made in the cloud",
+		Long: "Show entries added, updated or deleted in the cloud, e.g. from other devices. " +
+			"Only the latest change of every entry is shown. Pass the printed cursor with --since next time to see only newer changes",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			since, err := cmd.Flags().GetInt64("since")
+			if err != nil {
+				return fmt.Errorf("error getting since flag: %w", err)
+			}
+
+			if since < 0 {
+				return fmt.Errorf("cursor must not be negative")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			changes, cursor, err := service.ListChanges(ctxWithToken, since)
+			if err != nil {
+				return fmt.Errorf("error listing changes: %w", err)
+			}
+
+			if len(changes) == 0 {
+				cmd.Println("No changes found")
+			} else {
+				w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "CHANGED\tACTION\tNAME\tTYPE\tVERSION")
+
+				for _, change := range changes {
+					action, name, entryType := describeChange(change)
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", change.ChangedAt.Local().Format(time.DateTime), action, name, entryType, change.Metadata.Version)
+				}
+
+				err = w.Flush()
+				if err != nil {
+					return fmt.Errorf("error printing changes: %w", err)
+				}
+			}
+
+			cmd.Printf("Cursor: %d\n", cursor)
+
+			return nil
+		},
+	}
+
+	changes.Flags().Int64("since", 0, "Show only changes made after this cursor, printed by the previous run")
+
+	return changes
+}
+
+// describeChange returns what happened to the entry, its name and its type in a human-readable form.
+// Names of deleted entries are unknown, their keys are returned instead.
+func describeChange(change entry.Change) (string, string, string) {
+	if change.Deleted {
+		return "deleted", change.Metadata.Key, "-"
+	}
+
+	action := "updated"
+	if change.Metadata.Version == 1 {
+		action = "added"
+	}
+
+	return action, change.Metadata.Name, detectEntryType(change.Metadata)
+}
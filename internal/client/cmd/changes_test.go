@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestChanges(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestChangesCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newChangesCommand(container)
+		// gkeep changes {args}
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := mocks.NewMockEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		entryService.EXPECT().ListChanges(authCtx, int64(10)).Return([]entry.Change{
+			{Metadata: entry.Metadata{Key: "key1", Name: "github", Type: "login", Version: 1}, ChangedAt: changedAt},
+			{Metadata: entry.Metadata{Key: "key2", Name: "diary", Type: "text", Version: 3}, ChangedAt: changedAt},
+			{Metadata: entry.Metadata{Key: "key3", Version: 2}, Deleted: true, ChangedAt: changedAt},
+		}, int64(15), nil)
+
+		cmd, out := newTestChangesCommand(container, "--since", "10")
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		outString := out.String()
+		changed := changedAt.Local().Format(time.DateTime)
+		require.Regexp(t, changed+`\s+added\s+github\s+login\s+1`, outString)
+		require.Regexp(t, changed+`\s+updated\s+diary\s+text\s+3`, outString)
+		require.Regexp(t, changed+`\s+deleted\s+key3\s+-\s+2`, outString)
+		require.Contains(t, outString, "Cursor: 15")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := mocks.NewMockEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListChanges(authCtx, int64(0)).Return([]entry.Change{}, int64(0), nil)
+
+		cmd, out := newTestChangesCommand(container)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "No changes found")
+		require.Contains(t, out.String(), "Cursor: 0")
+	})
+
+	t.Run("negative cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		cmd, _ := newTestChangesCommand(container, "--since", "-1")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("error listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := mocks.NewMockEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListChanges(authCtx, int64(0)).Return(nil, int64(0), errors.New("err"))
+
+		cmd, _ := newTestChangesCommand(container)
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
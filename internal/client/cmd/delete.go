@@ -2,18 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/spf13/cobra"
 
 	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
-	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
 func newDeleteCommand(container container.Container) *cobra.Command {
 	deleteCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Remove value",
-		Long:  "Remove value from the cloud. This operation is final and can't be undone",
+		Long:  "Remove value from the cloud. Several values of the same type can be removed at once, either all of them or none. Removed values are kept in the trash for a while and can be restored, see trash command",
 	}
 
 	deleteCmd.AddCommand(newDeleteLoginCommand(container))
@@ -26,11 +26,11 @@ func newDeleteCommand(container container.Container) *cobra.Command {
 
 func newDeleteLoginCommand(container container.Container) *cobra.Command {
 	deleteLogin := &cobra.Command{
-		Use:   "login",
+		Use:   "login <name>...",
 		Short: "Delete login and password pair",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return deleteEntry(cmd, container, args[0], "login")
+			return deleteEntries(cmd, container, args, "login")
 		},
 	}
 
@@ -39,11 +39,11 @@ func newDeleteLoginCommand(container container.Container) *cobra.Command {
 
 func newDeleteFileCommand(container container.Container) *cobra.Command {
 	deleteFile := &cobra.Command{
-		Use:   "file",
+		Use:   "file <name>...",
 		Short: "Delete file",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return deleteEntry(cmd, container, args[0], "file")
+			return deleteEntries(cmd, container, args, "file")
 		},
 	}
 
@@ -52,11 +52,11 @@ func newDeleteFileCommand(container container.Container) *cobra.Command {
 
 func newDeleteCardCommand(container container.Container) *cobra.Command {
 	deleteCard := &cobra.Command{
-		Use:   "card",
+		Use:   "card <name>...",
 		Short: "Delete bank card info",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return deleteEntry(cmd, container, args[0], "card")
+			return deleteEntries(cmd, container, args, "card")
 		},
 	}
 
@@ -65,21 +65,21 @@ func newDeleteCardCommand(container container.Container) *cobra.Command {
 
 func newDeleteTextCommand(container container.Container) *cobra.Command {
 	deleteText := &cobra.Command{
-		Use:   "text",
+		Use:   "text <name>...",
 		Short: "Delete text",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return deleteEntry(cmd, container, args[0], "text")
+			return deleteEntries(cmd, container, args, "text")
 		},
 	}
 
 	return deleteText
 }
 
-// deleteEntry handles the deletion of an entry by interacting with the container's services.
-// It confirms the action with the user before proceeding.
-func deleteEntry(cmd *cobra.Command, container container.Container, name string, entryType string) error {
-	if name == "" {
+// deleteEntries handles the deletion of entries by interacting with the container's services.
+// It confirms the action with the user before proceeding. Several entries are deleted in one request.
+func deleteEntries(cmd *cobra.Command, container container.Container, names []string, entryType string) error {
+	if slices.Contains(names, "") {
 		return fmt.Errorf("name is empty")
 	}
 
@@ -88,11 +88,16 @@ func deleteEntry(cmd *cobra.Command, container container.Container, name string,
 		return fmt.Errorf("cant get prompter: %w", err)
 	}
 
-	if !prompter.Confirm(cmd.Context(), fmt.Sprintf("Are you sure you want to delete %s?", entryType)) {
+	what := entryType
+	if len(names) > 1 {
+		what = fmt.Sprintf("%d %s entries", len(names), entryType)
+	}
+
+	if !prompter.Confirm(cmd.Context(), fmt.Sprintf("Are you sure you want to delete %s?", what)) {
 		return nil
 	}
 
-	cmd.Printf("Deleting %s...\n", entryType)
+	cmd.Printf("Deleting %s...\n", what)
 
 	service, err := container.GetEntryService(cmd.Context())
 	if err != nil {
@@ -109,9 +114,16 @@ func deleteEntry(cmd *cobra.Command, container container.Container, name string,
 		return fmt.Errorf("error setting token: %w", err)
 	}
 
-	key := utils.GetEntryKey(entryType, name)
+	if len(names) == 1 {
+		err = service.DeleteEntry(ctxWithToken, service.EntryKey(entryType, names[0]), 0)
+	} else {
+		keys := make([]string, 0, len(names))
+		for _, name := range names {
+			keys = append(keys, service.EntryKey(entryType, name))
+		}
 
-	err = service.DeleteEntry(ctxWithToken, key)
+		err = service.DeleteEntries(ctxWithToken, keys)
+	}
 	if err != nil {
 		return fmt.Errorf("error deleting entry: %w", err)
 	}
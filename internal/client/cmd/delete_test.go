@@ -18,6 +18,19 @@ import (
 
 type testCtxKey string
 
+// testEntryKey stands in for the entry keys derived by the entry service.
+func testEntryKey(entryType string, name string) string {
+	return "key:" + entryType + ":" + name
+}
+
+// newTestEntryService returns a mock entry service deriving entry keys with testEntryKey.
+func newTestEntryService(ctrl *gomock.Controller) *mocks.MockEntryService {
+	entryService := mocks.NewMockEntryService(ctrl)
+	entryService.EXPECT().EntryKey(gomock.Any(), gomock.Any()).DoAndReturn(testEntryKey).AnyTimes()
+
+	return entryService
+}
+
 func TestDeleteLogin(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
@@ -38,7 +51,7 @@ func TestDeleteLogin(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
 
@@ -51,19 +64,51 @@ func TestDeleteLogin(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().DeleteEntry(authCtx, utils.GetEntryKey("login", "name")).Return(nil)
+		entryService.EXPECT().DeleteEntry(authCtx, testEntryKey("login", "name"), int64(0)).Return(nil)
 
 		cmd := newTestDeleteLoginCommand(container, "name")
 		err := cmd.Execute()
 		require.NoError(t, err)
 	})
 
+	t.Run("several names", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+		prompter := mocks.NewMockPrompter(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
+
+		prompter.EXPECT().Confirm(ctx, "Are you sure you want to delete 2 login entries?").Return(true)
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().DeleteEntries(authCtx, []string{testEntryKey("login", "first"), testEntryKey("login", "second")}).Return(nil)
+
+		cmd := newDeleteCommand(container)
+		// gkeep delete login first second
+		cmd.SetArgs([]string{"login", "first", "second"})
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetContext(ctx)
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+	})
+
 	t.Run("confirm returns false", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
 
@@ -125,7 +170,7 @@ func TestDeleteLogin(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -144,7 +189,7 @@ func TestDeleteLogin(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -166,7 +211,7 @@ func TestDeleteLogin(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -178,7 +223,7 @@ func TestDeleteLogin(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().DeleteEntry(authCtx, utils.GetEntryKey("login", "name")).Return(errors.New("err"))
+		entryService.EXPECT().DeleteEntry(authCtx, testEntryKey("login", "name"), int64(0)).Return(errors.New("err"))
 
 		cmd := newTestDeleteLoginCommand(container, "name")
 		err := cmd.Execute()
@@ -206,7 +251,7 @@ func TestDeleteFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
 
@@ -219,7 +264,7 @@ func TestDeleteFile(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().DeleteEntry(authCtx, utils.GetEntryKey("file", "name")).Return(nil)
+		entryService.EXPECT().DeleteEntry(authCtx, testEntryKey("file", "name"), int64(0)).Return(nil)
 
 		cmd := newTestDeleteFileCommand(container, "name")
 		err := cmd.Execute()
@@ -247,7 +292,7 @@ func TestDeleteCard(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
 
@@ -260,7 +305,7 @@ func TestDeleteCard(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().DeleteEntry(authCtx, utils.GetEntryKey("card", "name")).Return(nil)
+		entryService.EXPECT().DeleteEntry(authCtx, testEntryKey("card", "name"), int64(0)).Return(nil)
 
 		cmd := newTestDeleteCardCommand(container, "name")
 		err := cmd.Execute()
@@ -288,7 +333,7 @@ func TestDeleteText(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
 
@@ -301,7 +346,7 @@ func TestDeleteText(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().DeleteEntry(authCtx, utils.GetEntryKey("text", "name")).Return(nil)
+		entryService.EXPECT().DeleteEntry(authCtx, testEntryKey("text", "name"), int64(0)).Return(nil)
 
 		cmd := newTestDeleteTextCommand(container, "name")
 		err := cmd.Execute()
@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+)
+
+func newEmergencyCommand(container container.Container) *cobra.Command {
+	emergencyCmd := &cobra.Command{
+		Use:   "emergency",
+		Short: "Manage emergency access to vaults",
+		Long:  "Owners of a vault can name trusted contacts who get access to it if the owners are unavailable, and so can every user for their own entries with --own. A contact requests the access and can open it once the waiting period has passed, unless an owner rejects the request in the meantime. The contact then becomes a viewer of the vault, the own entries show up as a personal vault named after their owner",
+	}
+
+	emergencyCmd.AddCommand(newEmergencyGrantCommand(container))
+	emergencyCmd.AddCommand(newEmergencyRevokeCommand(container))
+	emergencyCmd.AddCommand(newEmergencyListCommand(container))
+	emergencyCmd.AddCommand(newEmergencyRequestCommand(container))
+	emergencyCmd.AddCommand(newEmergencyRejectCommand(container))
+	emergencyCmd.AddCommand(newEmergencyOpenCommand(container))
+
+	return emergencyCmd
+}
+
+func newEmergencyGrantCommand(container container.Container) *cobra.Command {
+	grant := &cobra.Command{
+		Use:   "grant [<vault>] <login>",
+		Short: "Add emergency contact",
+		Long:  "Make the user with the given login an emergency contact of the vault with the given name or id, or of your own entries with --own. Only owners can grant. The user needs to run shared list once before being granted",
+		Args:  grantArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName, login, err := grantTarget(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			wait, err := cmd.Flags().GetDuration("wait")
+			if err != nil {
+				return fmt.Errorf("error getting wait flag: %w", err)
+			}
+
+			if wait < time.Hour {
+				return fmt.Errorf("waiting period must be at least an hour")
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			_, err = service.GrantEmergencyAccess(ctxWithToken, vaultName, login, wait)
+			if err != nil {
+				switch {
+				case errors.Is(err, vault.ErrUserNotFound):
+					return fmt.Errorf("user %q not found or hasn't set up sharing yet, ask them to run shared list once", login)
+				case errors.Is(err, vault.ErrGrantExists):
+					cmd.Printf("%s is an emergency contact already\n", login)
+
+					return nil
+				case errors.Is(err, vault.ErrSecretNotSet):
+					return fmt.Errorf("secret is not set, set it first")
+				default:
+					return vaultError(err)
+				}
+			}
+
+			if vaultName == "" {
+				cmd.Printf("%s can open your entries %s after requesting access\n", login, wait)
+			} else {
+				cmd.Printf("%s can open the vault %s after requesting access\n", login, wait)
+			}
+
+			return nil
+		},
+	}
+
+	grant.Flags().Duration("wait", 72*time.Hour, "Waiting period between the request and the access, at least an hour")
+	grant.Flags().Bool("own", false, "Grant your own entries instead of a vault")
+
+	return grant
+}
+
+func newEmergencyRevokeCommand(container container.Container) *cobra.Command {
+	revoke := &cobra.Command{
+		Use:   "revoke [<vault>] <login>",
+		Short: "Remove emergency contact",
+		Long:  "Remove the user with the given login from the emergency contacts of the vault with the given name or id, or of your own entries with --own. Only owners can revoke",
+		Args:  grantArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName, login, err := grantTarget(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.RevokeEmergencyAccess(ctxWithToken, vaultName, login)
+			if err != nil {
+				if errors.Is(err, vault.ErrGrantNotFound) {
+					cmd.Printf("%s isn't an emergency contact\n", login)
+
+					return nil
+				}
+
+				return vaultError(err)
+			}
+
+			cmd.Println("Emergency access revoked!")
+
+			return nil
+		},
+	}
+
+	revoke.Flags().Bool("own", false, "Revoke the access to your own entries instead of a vault")
+
+	return revoke
+}
+
+func newEmergencyListCommand(container container.Container) *cobra.Command {
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List emergency contacts",
+		Long:  "List emergency contacts of the vaults you own, or with --incoming, the vaults you are an emergency contact of",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			incoming, err := cmd.Flags().GetBool("incoming")
+			if err != nil {
+				return fmt.Errorf("error getting incoming flag: %w", err)
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			grants, err := service.ListEmergencyGrants(ctxWithToken, incoming)
+			if err != nil {
+				return fmt.Errorf("error listing emergency contacts: %w", err)
+			}
+
+			if len(grants) == 0 {
+				cmd.Println("No emergency contacts found")
+
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			if incoming {
+				_, _ = fmt.Fprintln(w, "VAULT\tGRANTED BY\tWAIT\tSTATUS")
+			} else {
+				_, _ = fmt.Fprintln(w, "VAULT\tCONTACT\tWAIT\tSTATUS")
+			}
+
+			for _, grant := range grants {
+				login := grant.ContactLogin
+				if incoming {
+					login = grant.OwnerLogin
+				}
+
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", grant.VaultName, login, grant.Wait, grantStatus(grant))
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing emergency contacts: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	list.Flags().Bool("incoming", false, "List the vaults you are an emergency contact of")
+
+	return list
+}
+
+func newEmergencyRequestCommand(container container.Container) *cobra.Command {
+	request := &cobra.Command{
+		Use:   "request <vault>",
+		Short: "Request emergency access",
+		Long:  "Request access to the vault with the given name or id you are an emergency contact of. Requesting again doesn't restart the waiting period",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName := args[0]
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			grant, err := service.RequestEmergencyAccess(ctxWithToken, vaultName)
+			if err != nil {
+				return emergencyError(err)
+			}
+
+			cmd.Printf("Access requested, open it with emergency open after %s unless it's rejected\n", grant.AvailableAt.Local().Format(time.DateTime))
+
+			return nil
+		},
+	}
+
+	return request
+}
+
+func newEmergencyRejectCommand(container container.Container) *cobra.Command {
+	reject := &cobra.Command{
+		Use:   "reject [<vault>] <login>",
+		Short: "Reject emergency access request",
+		Long:  "Reject the request of the user with the given login to the vault with the given name or id, or to your own entries with --own. The user stays an emergency contact and can request again, use emergency revoke to remove the user. Only owners can reject",
+		Args:  grantArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName, login, err := grantTarget(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.RejectEmergencyAccess(ctxWithToken, vaultName, login)
+			if err != nil {
+				if errors.Is(err, vault.ErrGrantNotFound) {
+					return fmt.Errorf("%s isn't an emergency contact", login)
+				}
+
+				return vaultError(err)
+			}
+
+			cmd.Println("Request rejected!")
+
+			return nil
+		},
+	}
+
+	reject.Flags().Bool("own", false, "Reject the request to your own entries instead of a vault")
+
+	return reject
+}
+
+func newEmergencyOpenCommand(container container.Container) *cobra.Command {
+	open := &cobra.Command{
+		Use:   "open <vault>",
+		Short: "Open emergency access",
+		Long:  "Become a viewer of the vault with the given name or id once the waiting period of your request has passed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName := args[0]
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			v, err := service.OpenEmergencyAccess(ctxWithToken, vaultName)
+			if err != nil {
+				return emergencyError(err)
+			}
+
+			cmd.Printf("You are a %s of vault %q now, see vault use command\n", v.Role, v.Name)
+
+			return nil
+		},
+	}
+
+	return open
+}
+
+// grantArgs accepts the vault and the login, or only the login if the own entries are managed with --own.
+func grantArgs(cmd *cobra.Command, args []string) error {
+	own, err := cmd.Flags().GetBool("own")
+	if err != nil {
+		return fmt.Errorf("error getting own flag: %w", err)
+	}
+
+	if own {
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+
+	return cobra.ExactArgs(2)(cmd, args)
+}
+
+// grantTarget returns the vault and the login of the arguments accepted by grantArgs, the vault is empty for the own entries.
+func grantTarget(cmd *cobra.Command, args []string) (string, string, error) {
+	own, err := cmd.Flags().GetBool("own")
+	if err != nil {
+		return "", "", fmt.Errorf("error getting own flag: %w", err)
+	}
+
+	vaultName, login := "", args[0]
+	if !own {
+		vaultName, login = args[0], args[1]
+
+		if vaultName == "" {
+			return "", "", fmt.Errorf("vault is empty")
+		}
+	}
+
+	if login == "" {
+		return "", "", fmt.Errorf("login is empty")
+	}
+
+	return vaultName, login, nil
+}
+
+// grantStatus describes the state of the request of the grant.
+func grantStatus(grant vault.EmergencyGrant) string {
+	switch {
+	case grant.RequestedAt.IsZero():
+		return "not requested"
+	case time.Now().Before(grant.AvailableAt):
+		return fmt.Sprintf("requested, available at %s", grant.AvailableAt.Local().Format(time.DateTime))
+	default:
+		return "available"
+	}
+}
+
+// emergencyError converts the errors of the emergency access to the user-friendly ones.
+func emergencyError(err error) error {
+	switch {
+	case errors.Is(err, vault.ErrGrantNotFound):
+		return fmt.Errorf("no emergency access to the vault, see emergency list command")
+	case errors.Is(err, vault.ErrNotRequested):
+		return fmt.Errorf("access wasn't requested or the request was rejected, see emergency request command")
+	case errors.Is(err, vault.ErrWaitingPeriod):
+		return fmt.Errorf("waiting period hasn't passed yet, see emergency list --incoming command")
+	default:
+		return vaultError(err)
+	}
+}
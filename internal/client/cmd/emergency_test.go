@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestEmergency(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestEmergencyCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newEmergencyCommand(container)
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	setup := func(t *testing.T) (*mocks.MockContainer, *mocks.MockVaultService, context.Context) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		container := mocks.NewMockContainer(ctrl)
+		vaultService := mocks.NewMockVaultService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaultService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil).AnyTimes()
+
+		return container, vaultService, authCtx
+	}
+
+	t.Run("grant", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().GrantEmergencyAccess(authCtx, "team", "bob", 48*time.Hour).Return(vault.EmergencyGrant{}, nil)
+
+		cmd, out := newTestEmergencyCommand(container, "grant", "team", "bob", "--wait", "48h")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "bob can open the vault 48h0m0s after requesting access")
+	})
+
+	t.Run("grant with short wait", func(t *testing.T) {
+		container, _, _ := setup(t)
+
+		cmd, _ := newTestEmergencyCommand(container, "grant", "team", "bob", "--wait", "5m")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "at least an hour")
+	})
+
+	t.Run("grant to user without key pair", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().GrantEmergencyAccess(authCtx, "team", "bob", 72*time.Hour).Return(vault.EmergencyGrant{}, vault.ErrUserNotFound)
+
+		cmd, _ := newTestEmergencyCommand(container, "grant", "team", "bob")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "hasn't set up sharing yet")
+	})
+
+	t.Run("grant own entries", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().GrantEmergencyAccess(authCtx, "", "bob", 72*time.Hour).Return(vault.EmergencyGrant{}, nil)
+
+		cmd, out := newTestEmergencyCommand(container, "grant", "--own", "bob")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "bob can open your entries 72h0m0s after requesting access")
+	})
+
+	t.Run("grant own entries with vault", func(t *testing.T) {
+		container, _, _ := setup(t)
+
+		cmd, _ := newTestEmergencyCommand(container, "grant", "--own", "team", "bob")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "accepts 1 arg(s)")
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().RevokeEmergencyAccess(authCtx, "team", "bob").Return(nil)
+
+		cmd, out := newTestEmergencyCommand(container, "revoke", "team", "bob")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Emergency access revoked!")
+	})
+
+	t.Run("revoke own entries", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().RevokeEmergencyAccess(authCtx, "", "bob").Return(nil)
+
+		cmd, out := newTestEmergencyCommand(container, "revoke", "--own", "bob")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Emergency access revoked!")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().ListEmergencyGrants(authCtx, false).Return([]vault.EmergencyGrant{
+			{VaultName: "team", ContactLogin: "bob", OwnerLogin: "alice", Wait: 72 * time.Hour},
+			{VaultName: "family", ContactLogin: "carol", OwnerLogin: "alice", Wait: time.Hour, RequestedAt: time.Now().Add(-2 * time.Hour), AvailableAt: time.Now().Add(-time.Hour)},
+		}, nil)
+
+		cmd, out := newTestEmergencyCommand(container, "list")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(t, `team\s+bob\s+72h0m0s\s+not requested`, out.String())
+		require.Regexp(t, `family\s+carol\s+1h0m0s\s+available`, out.String())
+	})
+
+	t.Run("list incoming", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().ListEmergencyGrants(authCtx, true).Return([]vault.EmergencyGrant{
+			{VaultName: "team", ContactLogin: "bob", OwnerLogin: "alice", Wait: time.Hour, RequestedAt: time.Now(), AvailableAt: time.Now().Add(time.Hour)},
+		}, nil)
+
+		cmd, out := newTestEmergencyCommand(container, "list", "--incoming")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(t, `GRANTED BY`, out.String())
+		require.Regexp(t, `team\s+alice\s+1h0m0s\s+requested, available at`, out.String())
+	})
+
+	t.Run("request", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		availableAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+		vaultService.EXPECT().RequestEmergencyAccess(authCtx, "team").Return(vault.EmergencyGrant{AvailableAt: availableAt}, nil)
+
+		cmd, out := newTestEmergencyCommand(container, "request", "team")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "after 2025-03-01 12:00:00")
+	})
+
+	t.Run("request without grant", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().RequestEmergencyAccess(authCtx, "team").Return(vault.EmergencyGrant{}, vault.ErrGrantNotFound)
+
+		cmd, _ := newTestEmergencyCommand(container, "request", "team")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "no emergency access to the vault")
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().RejectEmergencyAccess(authCtx, "team", "bob").Return(nil)
+
+		cmd, out := newTestEmergencyCommand(container, "reject", "team", "bob")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Request rejected!")
+	})
+
+	t.Run("open", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().OpenEmergencyAccess(authCtx, "team").Return(vault.Vault{ID: "id", Name: "team", Role: vault.RoleViewer}, nil)
+
+		cmd, out := newTestEmergencyCommand(container, "open", "team")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `You are a viewer of vault "team" now`)
+	})
+
+	t.Run("open too early", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().OpenEmergencyAccess(authCtx, "team").Return(vault.Vault{}, vault.ErrWaitingPeriod)
+
+		cmd, _ := newTestEmergencyCommand(container, "open", "team")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "waiting period hasn't passed yet")
+	})
+}
@@ -6,11 +6,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/kuvalkin/gophkeeper/internal/client/cmd/entries"
 	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
@@ -21,6 +23,8 @@ func newGetCommand(container container.Container) *cobra.Command {
 		Long:  "Get value from the cloud, decrypt it and show it",
 	}
 
+	set.PersistentFlags().Int64("version", 0, "Get a previous version of the entry instead of the current one. See history command for available versions")
+
 	set.AddCommand(newGetLoginCommand(container))
 	set.AddCommand(newGetFileCommand(container))
 	set.AddCommand(newGetCardCommand(container))
@@ -42,7 +46,12 @@ func newGetLoginCommand(container container.Container) *cobra.Command {
 
 			cmd.Println("Getting login...")
 
-			notes, content, exists, err := get(cmd.Context(), container, utils.GetEntryKey("login", name))
+			version, err := cmd.Flags().GetInt64("version")
+			if err != nil {
+				return fmt.Errorf("error getting version flag: %w", err)
+			}
+
+			md, content, exists, err := get(cmd.Context(), container, "login", name, version)
 			if err != nil {
 				return fmt.Errorf("error getting login: %w", err)
 			}
@@ -61,7 +70,8 @@ func newGetLoginCommand(container container.Container) *cobra.Command {
 				return fmt.Errorf("error unmarshaling login: %w", err)
 			}
 
-			cmd.Printf("Login: %s\nPassword: %s\nNotes: %s\n", entry.Login, entry.Password, notes)
+			cmd.Printf("Login: %s\nPassword: %s\nNotes: %s\n", entry.Login, entry.Password, md.Notes)
+			printEntryInfo(cmd, md)
 
 			return nil
 		},
@@ -89,7 +99,12 @@ func newGetFileCommand(container container.Container) *cobra.Command {
 			cmd.Println("Getting file...")
 			cmd.Println("It may take a while depending on the file size")
 
-			notes, content, exists, err := get(cmd.Context(), container, utils.GetEntryKey("file", name))
+			version, err := cmd.Flags().GetInt64("version")
+			if err != nil {
+				return fmt.Errorf("error getting version flag: %w", err)
+			}
+
+			md, content, exists, err := get(cmd.Context(), container, "file", name, version)
 			if err != nil {
 				return fmt.Errorf("error getting file: %w", err)
 			}
@@ -103,7 +118,8 @@ func newGetFileCommand(container container.Container) *cobra.Command {
 			defer utils.CloseAndLogError(content, nil)
 
 			cmd.Println("Downloaded entry from server")
-			cmd.Println("Your notes:", notes)
+			cmd.Println("Your notes:", md.Notes)
+			printEntryInfo(cmd, md)
 
 			cmd.Println("Storing file...")
 
@@ -144,7 +160,12 @@ func newGetCardCommand(container container.Container) *cobra.Command {
 
 			cmd.Println("Getting bank card...")
 
-			notes, content, exists, err := get(cmd.Context(), container, utils.GetEntryKey("card", name))
+			version, err := cmd.Flags().GetInt64("version")
+			if err != nil {
+				return fmt.Errorf("error getting version flag: %w", err)
+			}
+
+			md, content, exists, err := get(cmd.Context(), container, "card", name, version)
 			if err != nil {
 				return fmt.Errorf("error getting card: %w", err)
 			}
@@ -169,7 +190,8 @@ func newGetCardCommand(container container.Container) *cobra.Command {
 			cmd.Println("Expiration Month:", entry.ExpirationDate.Month)
 			cmd.Println("CVV:", entry.CVV)
 			cmd.Println("")
-			cmd.Println("Notes:", notes)
+			cmd.Println("Notes:", md.Notes)
+			printEntryInfo(cmd, md)
 
 			return nil
 		},
@@ -191,7 +213,12 @@ func newGetTextCommand(container container.Container) *cobra.Command {
 
 			cmd.Println("Getting text...")
 
-			notes, content, exists, err := get(cmd.Context(), container, utils.GetEntryKey("text", name))
+			version, err := cmd.Flags().GetInt64("version")
+			if err != nil {
+				return fmt.Errorf("error getting version flag: %w", err)
+			}
+
+			md, content, exists, err := get(cmd.Context(), container, "text", name, version)
 			if err != nil {
 				return fmt.Errorf("error getting text: %w", err)
 			}
@@ -212,7 +239,8 @@ func newGetTextCommand(container container.Container) *cobra.Command {
 			cmd.Println("Text:")
 			cmd.Println(string(text))
 			cmd.Println("")
-			cmd.Println("Notes:", notes)
+			cmd.Println("Notes:", md.Notes)
+			printEntryInfo(cmd, md)
 
 			return nil
 		},
@@ -221,23 +249,47 @@ func newGetTextCommand(container container.Container) *cobra.Command {
 	return getLogin
 }
 
-// get retrieves an entry from the cloud by its key.
-// It returns the entry's notes, content, and existence status.
-func get(ctx context.Context, container container.Container, key string) (string, io.ReadCloser, bool, error) {
+// get retrieves an entry of the given type from the cloud by its name.
+// If version is not zero, the previous version with this number is retrieved instead of the current one.
+// It returns the entry's metadata, content, and existence status.
+func get(ctx context.Context, container container.Container, entryType string, name string, version int64) (entry.Metadata, io.ReadCloser, bool, error) {
 	service, err := container.GetEntryService(ctx)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("error getting entry service: %w", err)
+		return entry.Metadata{}, nil, false, fmt.Errorf("error getting entry service: %w", err)
 	}
 
 	authService, err := container.GetAuthService(ctx)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("error getting auth service: %w", err)
+		return entry.Metadata{}, nil, false, fmt.Errorf("error getting auth service: %w", err)
 	}
 
 	ctxWithToken, err := authService.AddAuthorizationHeader(ctx)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("error setting token: %w", err)
+		return entry.Metadata{}, nil, false, fmt.Errorf("error setting token: %w", err)
+	}
+
+	key := service.EntryKey(entryType, name)
+	if version != 0 {
+		return service.GetVersion(ctxWithToken, key, version)
 	}
 
 	return service.GetEntry(ctxWithToken, key)
 }
+
+// printEntryInfo prints the version of the entry, when it was created and updated and the size of its encrypted content.
+// Values unknown to the server are skipped.
+func printEntryInfo(cmd *cobra.Command, md entry.Metadata) {
+	if md.Version != 0 {
+		cmd.Println("Version:", md.Version)
+	}
+
+	if !md.CreatedAt.IsZero() {
+		cmd.Println("Created:", md.CreatedAt.Local().Format(time.DateTime))
+	}
+
+	if !md.UpdatedAt.IsZero() {
+		cmd.Println("Updated:", md.UpdatedAt.Local().Format(time.DateTime))
+	}
+
+	cmd.Printf("Size: %d bytes\n", md.Size)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/kuvalkin/gophkeeper/internal/client/cmd/entries"
 	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
@@ -41,7 +43,7 @@ func TestGetLogin(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -57,7 +59,13 @@ func TestGetLogin(t *testing.T) {
 		content, err := pair.Marshal()
 		require.NoError(t, err)
 
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("login", "name")).Return("mynotes", content, true, nil)
+		md := entry.Metadata{
+			Notes:     "mynotes",
+			CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC),
+			Size:      123,
+		}
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("login", "name")).Return(md, content, true, nil)
 
 		cmd, out := newTestGetLoginCommand(container, "name")
 		err = cmd.Execute()
@@ -66,6 +74,9 @@ func TestGetLogin(t *testing.T) {
 		require.Contains(t, outString, "mylogin")
 		require.Contains(t, outString, "mypassword")
 		require.Contains(t, outString, "mynotes")
+		require.Contains(t, outString, "Created: "+md.CreatedAt.Local().Format(time.DateTime))
+		require.Contains(t, outString, "Updated: "+md.UpdatedAt.Local().Format(time.DateTime))
+		require.Contains(t, outString, "Size: 123 bytes")
 	})
 
 	t.Run("no name", func(t *testing.T) {
@@ -84,7 +95,7 @@ func TestGetLogin(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -93,7 +104,7 @@ func TestGetLogin(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("login", "name")).Return("", nil, false, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("login", "name")).Return(entry.Metadata{}, nil, false, nil)
 
 		cmd, out := newTestGetLoginCommand(container, "name")
 		err := cmd.Execute()
@@ -102,12 +113,44 @@ func TestGetLogin(t *testing.T) {
 		require.Contains(t, outString, "not found")
 	})
 
+	t.Run("previous version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		pair := &entries.LoginPasswordPair{
+			Login:    "mylogin",
+			Password: "oldpassword",
+		}
+		content, err := pair.Marshal()
+		require.NoError(t, err)
+
+		entryService.EXPECT().GetVersion(authCtx, testEntryKey("login", "name"), int64(2)).Return(entry.Metadata{Version: 2}, content, true, nil)
+
+		cmd, out := newTestGetLoginCommand(container, "name")
+		cmd.SetArgs([]string{"login", "name", "--version", "2"})
+		err = cmd.Execute()
+		require.NoError(t, err)
+		outString := out.String()
+		require.Contains(t, outString, "oldpassword")
+		require.Contains(t, outString, "Version: 2")
+	})
+
 	t.Run("service returns invalid data", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -117,7 +160,7 @@ func TestGetLogin(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("invalid data"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("login", "name")).Return("mynotes", content, true, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("login", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, nil)
 
 		cmd, _ := newTestGetLoginCommand(container, "name")
 		err := cmd.Execute()
@@ -156,7 +199,7 @@ func TestGetLogin(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -193,7 +236,7 @@ func TestGetFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -203,7 +246,7 @@ func TestGetFile(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("file content"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("file", "name")).Return("mynotes", content, true, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("file", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, nil)
 
 		file, err := os.CreateTemp("", "test-get-file-*")
 		require.NoError(t, err)
@@ -249,7 +292,7 @@ func TestGetFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -258,7 +301,7 @@ func TestGetFile(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("file", "name")).Return("", nil, false, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("file", "name")).Return(entry.Metadata{}, nil, false, nil)
 
 		file, err := os.CreateTemp("", "test-get-file-*")
 		require.NoError(t, err)
@@ -279,7 +322,7 @@ func TestGetFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -289,7 +332,7 @@ func TestGetFile(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("file content"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("file", "name")).Return("mynotes", content, true, errors.New("error"))
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("file", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, errors.New("error"))
 
 		file, err := os.CreateTemp("", "test-get-file-*")
 		require.NoError(t, err)
@@ -308,7 +351,7 @@ func TestGetFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -318,7 +361,7 @@ func TestGetFile(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("file content"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("file", "name")).Return("mynotes", content, true, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("file", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, nil)
 
 		// path is directory
 		cmd, _ := newTestGetFileCommand(container, "name", os.TempDir())
@@ -350,7 +393,7 @@ func TestGetCard(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -371,7 +414,7 @@ func TestGetCard(t *testing.T) {
 		content, err := card.Marshal()
 		require.NoError(t, err)
 
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("card", "name")).Return("mynotes", content, true, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("card", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, nil)
 
 		cmd, out := newTestGetCardCommand(container, "name")
 		err = cmd.Execute()
@@ -401,7 +444,7 @@ func TestGetCard(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -410,7 +453,7 @@ func TestGetCard(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("card", "name")).Return("", nil, false, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("card", "name")).Return(entry.Metadata{}, nil, false, nil)
 
 		cmd, out := newTestGetCardCommand(container, "name")
 		err := cmd.Execute()
@@ -424,7 +467,7 @@ func TestGetCard(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -434,7 +477,7 @@ func TestGetCard(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("file content"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("card", "name")).Return("mynotes", content, true, errors.New("error"))
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("card", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, errors.New("error"))
 
 		cmd, _ := newTestGetCardCommand(container, "name")
 		err := cmd.Execute()
@@ -446,7 +489,7 @@ func TestGetCard(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -456,7 +499,7 @@ func TestGetCard(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("invalid data"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("card", "name")).Return("mynotes", content, true, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("card", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, nil)
 
 		cmd, _ := newTestGetCardCommand(container, "name")
 		err := cmd.Execute()
@@ -487,7 +530,7 @@ func TestGetText(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -497,7 +540,7 @@ func TestGetText(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("text content"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("text", "name")).Return("mynotes", content, true, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("text", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, nil)
 
 		cmd, out := newTestGetTextCommand(container, "name")
 		err := cmd.Execute()
@@ -523,7 +566,7 @@ func TestGetText(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -532,7 +575,7 @@ func TestGetText(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("text", "name")).Return("", nil, false, nil)
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("text", "name")).Return(entry.Metadata{}, nil, false, nil)
 
 		cmd, out := newTestGetTextCommand(container, "name")
 		err := cmd.Execute()
@@ -546,7 +589,7 @@ func TestGetText(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -556,7 +599,7 @@ func TestGetText(t *testing.T) {
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
 		content := io.NopCloser(bytes.NewBufferString("file content"))
-		entryService.EXPECT().GetEntry(authCtx, utils.GetEntryKey("text", "name")).Return("mynotes", content, true, errors.New("error"))
+		entryService.EXPECT().GetEntry(authCtx, testEntryKey("text", "name")).Return(entry.Metadata{Notes: "mynotes"}, content, true, errors.New("error"))
 
 		cmd, _ := newTestGetTextCommand(container, "name")
 		err := cmd.Execute()
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+)
+
+func newHistoryCommand(container container.Container) *cobra.Command {
+	history := &cobra.Command{
+		Use:   "history <type> <name>",
+		Short: "Show previous versions of entry",
+		Long:  "Show previous versions of entry kept in the cloud. Any of them can be viewed with get --version or brought back with restore",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, name := args[0], args[1]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			mds, err := service.ListVersions(ctxWithToken, service.EntryKey(entryType, name))
+			if err != nil {
+				return fmt.Errorf("error listing versions: %w", err)
+			}
+
+			if len(mds) == 0 {
+				cmd.Println("No previous versions found")
+
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tSTORED\tSIZE\tNOTES")
+
+			for _, md := range mds {
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", md.Version, md.UpdatedAt.Local().Format(time.DateTime), md.Size, previewNotes(md.Notes))
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing versions: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return history
+}
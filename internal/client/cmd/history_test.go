@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestHistory(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestHistoryCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newHistoryCommand(container)
+		// gkeep history {args}
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		storedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		entryService.EXPECT().ListVersions(authCtx, testEntryKey("login", "github")).Return([]entry.Metadata{
+			{Version: 2, UpdatedAt: storedAt, Size: 100, Notes: "new account"},
+			{Version: 1, UpdatedAt: storedAt, Size: 90},
+		}, nil)
+
+		cmd, out := newTestHistoryCommand(container, "login", "github")
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		outString := out.String()
+		require.Regexp(t, `2\s+`+storedAt.Local().Format(time.DateTime)+`\s+100\s+new account`, outString)
+		require.Regexp(t, `1\s+`+storedAt.Local().Format(time.DateTime)+`\s+90`, outString)
+	})
+
+	t.Run("no versions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListVersions(authCtx, testEntryKey("card", "visa")).Return([]entry.Metadata{}, nil)
+
+		cmd, out := newTestHistoryCommand(container, "card", "visa")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "No previous versions found")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		cmd, _ := newTestHistoryCommand(container, "unknown", "name")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("error listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListVersions(authCtx, gomock.Any()).Return(nil, errors.New("err"))
+
+		cmd, _ := newTestHistoryCommand(container, "text", "diary")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
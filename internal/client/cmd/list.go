@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+// entryTypes lists all the entry types the client can store.
+var entryTypes = []string{"login", "file", "card", "text"}
+
+// notesPreviewLength is the maximum number of characters of notes shown in the list.
+const notesPreviewLength = 40
+
+func newListCommand(container container.Container) *cobra.Command {
+	list := &cobra.Command{
+		Use:   "list [name...]",
+		Short: "List entries",
+		Long:  "List all entries stored in the cloud with their names, types and notes preview. If names are given, only the entries with these names are looked up",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := cmd.Flags().GetString("type")
+			if err != nil {
+				return fmt.Errorf("error getting type flag: %w", err)
+			}
+
+			if entryType != "" {
+				err = validateEntryType(entryType)
+				if err != nil {
+					return err
+				}
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			var mds []entry.Metadata
+			if len(args) > 0 {
+				mds, err = service.GetMetadata(ctxWithToken, getLookupKeys(service, args, entryType))
+			} else {
+				mds, err = service.ListEntries(ctxWithToken)
+			}
+			if err != nil {
+				return fmt.Errorf("error listing entries: %w", err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tTYPE\tNOTES")
+
+			found := 0
+			for _, md := range mds {
+				mdType := detectEntryType(md)
+				if entryType != "" && mdType != entryType {
+					continue
+				}
+
+				found++
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", md.Name, mdType, previewNotes(md.Notes))
+			}
+
+			if found == 0 {
+				cmd.Println("No entries found")
+
+				return nil
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing entries: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	list.Flags().String("type", "", fmt.Sprintf("Show only entries of this type (%s)", strings.Join(entryTypes, ", ")))
+
+	return list
+}
+
+// getLookupKeys returns keys of the entries with the given names, either of the given type or of any known type.
+func getLookupKeys(service entry.Service, names []string, entryType string) []string {
+	types := entryTypes
+	if entryType != "" {
+		types = []string{entryType}
+	}
+
+	keys := make([]string, 0, len(names)*len(types))
+	for _, name := range names {
+		for _, t := range types {
+			keys = append(keys, service.EntryKey(t, name))
+		}
+	}
+
+	return keys
+}
+
+// validateEntryType checks that entryType is one of the known entry types.
+func validateEntryType(entryType string) error {
+	if !slices.Contains(entryTypes, entryType) {
+		return fmt.Errorf("unknown entry type %q, must be one of: %s", entryType, strings.Join(entryTypes, ", "))
+	}
+
+	return nil
+}
+
+// detectEntryType returns the type stored along with the entry.
+// Entries stored before the server kept types have none, for them the type is found out
+// by matching the key against legacy keys of all known types.
+// Returns "unknown" if none of them match.
+func detectEntryType(md entry.Metadata) string {
+	if md.Type != "" {
+		return md.Type
+	}
+
+	for _, t := range entryTypes {
+		if utils.GetEntryKey(t, md.Name) == md.Key {
+			return t
+		}
+	}
+
+	return "unknown"
+}
+
+// previewNotes returns the first line of notes shortened to notesPreviewLength characters.
+func previewNotes(notes string) string {
+	preview, _, multiline := strings.Cut(notes, "\n")
+
+	runes := []rune(preview)
+	if len(runes) > notesPreviewLength {
+		return string(runes[:notesPreviewLength]) + "..."
+	}
+
+	if multiline {
+		return preview + "..."
+	}
+
+	return preview
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestList(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestListCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newListCommand(container)
+		// gkeep list {args}
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	mds := []entry.Metadata{
+		{Key: utils.GetEntryKey("login", "github"), Name: "github", Notes: "work account\nsecond line"},
+		{Key: utils.GetEntryKey("card", "visa"), Name: "visa"},
+		{Key: "some-key", Name: "mystery"},
+		{Key: "other-key", Name: "diary", Type: "text"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListEntries(authCtx).Return(mds, nil)
+
+		cmd, out := newTestListCommand(container)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		outString := out.String()
+		require.Regexp(t, `github\s+login\s+work account\.\.\.`, outString)
+		require.Regexp(t, `visa\s+card`, outString)
+		require.Regexp(t, `mystery\s+unknown`, outString)
+		require.Regexp(t, `diary\s+text`, outString)
+		require.NotContains(t, outString, "second line")
+	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListEntries(authCtx).Return(mds, nil)
+
+		cmd, out := newTestListCommand(container, "--type", "card")
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		outString := out.String()
+		require.Contains(t, outString, "visa")
+		require.NotContains(t, outString, "github")
+		require.NotContains(t, outString, "mystery")
+	})
+
+	t.Run("by names", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		keys := make([]string, 0)
+		for _, name := range []string{"github", "visa"} {
+			for _, entryType := range entryTypes {
+				keys = append(keys, testEntryKey(entryType, name))
+			}
+		}
+
+		entryService.EXPECT().GetMetadata(authCtx, keys).Return(mds[:2], nil)
+
+		cmd, out := newTestListCommand(container, "github", "visa")
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		outString := out.String()
+		require.Regexp(t, `github\s+login`, outString)
+		require.Regexp(t, `visa\s+card`, outString)
+		require.NotContains(t, outString, "diary")
+	})
+
+	t.Run("by names of type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().GetMetadata(authCtx, []string{testEntryKey("card", "visa")}).Return([]entry.Metadata{}, nil)
+
+		cmd, out := newTestListCommand(container, "--type", "card", "visa")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "No entries found")
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListEntries(authCtx).Return(mds, nil)
+
+		cmd, out := newTestListCommand(container, "--type", "file")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "No entries found")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		cmd, _ := newTestListCommand(container, "--type", "unknown")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("error getting entry service", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(nil, errors.New("err")).AnyTimes()
+
+		cmd, _ := newTestListCommand(container)
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("error listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListEntries(authCtx).Return(nil, errors.New("err"))
+
+		cmd, _ := newTestListCommand(container)
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
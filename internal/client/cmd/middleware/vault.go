@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+)
+
+// EnsureNoActiveVault ensures that no team vault is active before executing the provided CobraRunE function.
+// It's used by the commands working with the user's own entries only, e.g. sharing.
+func EnsureNoActiveVault(container container.Container) MW {
+	return func(f CobraRunE) CobraRunE {
+		return func(cmd *cobra.Command, args []string) error {
+			vaults, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cant get vault service: %w", err)
+			}
+
+			active, ok, err := vaults.GetActiveVault(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cant check if vault is active: %w", err)
+			}
+
+			if ok {
+				return fmt.Errorf("not available in vault %q, switch back to your own values with vault use first", active.Name)
+			}
+
+			if f == nil {
+				return nil
+			}
+
+			return f(cmd, args)
+		}
+	}
+}
+
+// SelectVault makes the vault given with the --vault flag active for the command, overriding the one chosen with vault use.
+// Without the flag, it just executes the provided CobraRunE function.
+func SelectVault(container container.Container) MW {
+	return func(f CobraRunE) CobraRunE {
+		return func(cmd *cobra.Command, args []string) error {
+			name, err := cmd.Flags().GetString("vault")
+			if err != nil {
+				return fmt.Errorf("error getting vault flag: %w", err)
+			}
+
+			if name != "" {
+				vaults, err := container.GetVaultService(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("cant get vault service: %w", err)
+				}
+
+				auth, err := container.GetAuthService(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("cant get auth service: %w", err)
+				}
+
+				ctxWithToken, err := auth.AddAuthorizationHeader(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("error setting token: %w", err)
+				}
+
+				_, err = vaults.SelectVault(ctxWithToken, name)
+				if err != nil {
+					return fmt.Errorf("cant select vault %q: %w", name, err)
+				}
+			}
+
+			if f == nil {
+				return nil
+			}
+
+			return f(cmd, args)
+		}
+	}
+}
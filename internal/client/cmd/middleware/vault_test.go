@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/cmd/middleware"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestEnsureNoActiveVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestCommand := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{})
+		cmd.SetContext(ctx)
+		return cmd
+	}
+
+	t.Run("no vault active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaults, nil).AnyTimes()
+		vaults.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{}, false, nil)
+
+		mw := middleware.EnsureNoActiveVault(container)
+		wrapped := mw(nil)
+		err := wrapped(newTestCommand(), []string{})
+		require.NoError(t, err)
+	})
+
+	t.Run("vault active", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaults, nil).AnyTimes()
+		vaults.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{ID: "vault", Name: "ops"}, true, nil)
+
+		mw := middleware.EnsureNoActiveVault(container)
+		wrapped := mw(nil)
+		err := wrapped(newTestCommand(), []string{})
+		require.ErrorContains(t, err, "ops")
+	})
+
+	t.Run("error getting active vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaults, nil).AnyTimes()
+		vaults.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{}, false, errors.New("error"))
+
+		mw := middleware.EnsureNoActiveVault(container)
+		wrapped := mw(nil)
+		err := wrapped(newTestCommand(), []string{})
+		require.Error(t, err)
+	})
+}
+
+func TestSelectVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestCommand := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("vault", "", "")
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetContext(ctx)
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	t.Run("no flag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		mw := middleware.SelectVault(container)
+		wrapped := mw(nil)
+		err := wrapped(newTestCommand(), []string{})
+		require.NoError(t, err)
+	})
+
+	t.Run("vault selected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaults, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(ctx, nil)
+		vaults.EXPECT().SelectVault(ctx, "work").Return(vault.ActiveVault{ID: "vault", Name: "work"}, nil)
+
+		called := false
+		mw := middleware.SelectVault(container)
+		wrapped := mw(func(cmd *cobra.Command, args []string) error {
+			called = true
+			return nil
+		})
+		err := wrapped(newTestCommand("--vault", "work"), []string{})
+		require.NoError(t, err)
+		require.True(t, called)
+	})
+
+	t.Run("unknown vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaults, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(ctx, nil)
+		vaults.EXPECT().SelectVault(ctx, "work").Return(vault.ActiveVault{}, vault.ErrVaultNotFound)
+
+		mw := middleware.SelectVault(container)
+		wrapped := mw(nil)
+		err := wrapped(newTestCommand("--vault", "work"), []string{})
+		require.ErrorIs(t, err, vault.ErrVaultNotFound)
+	})
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func newRekeyCommand(container container.Container) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rekey",
+		Short: "Move entries to keys derived from the secret and encrypt their names",
+		Long:  "Move entries stored under the legacy keys, which anyone with access to the server can guess from the names, to the keys derived from the secret. The names and types older versions stored in plain text on the server are replaced by encrypted ones along the way, for the previous versions of the entries as well. The content isn't downloaded or uploaded again, and the history is kept. Entries not found by the other commands after the upgrade need to be moved once with this command. Deleted entries can't be moved, so the ones under the legacy keys must be restored or purged from the trash first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			// the trash can't be moved, and a legacy entry restored afterwards would be stored under the old key again
+			trash, err := service.ListTrash(ctxWithToken)
+			if err != nil {
+				return fmt.Errorf("error listing trash: %w", err)
+			}
+
+			legacyTrash := 0
+			for _, item := range trash {
+				if _, ok := legacyEntryType(item.Metadata); ok {
+					legacyTrash++
+				}
+			}
+
+			if legacyTrash > 0 {
+				return fmt.Errorf("%d deleted entries are stored under the legacy keys, restore or purge them first, see trash command", legacyTrash)
+			}
+
+			mds, err := service.ListEntries(ctxWithToken)
+			if err != nil {
+				return fmt.Errorf("error listing entries: %w", err)
+			}
+
+			cmd.Println("Moving entries...")
+
+			moved := 0
+			for _, md := range mds {
+				entryType, ok := legacyEntryType(md)
+				if !ok {
+					continue
+				}
+
+				err = service.RenameEntry(ctxWithToken, md.Key, entry.Metadata{
+					Key:     service.EntryKey(entryType, md.Name),
+					Name:    md.Name,
+					Type:    entryType,
+					Version: md.Version,
+				})
+				if err != nil {
+					if errors.Is(err, entry.ErrEntryExists) {
+						cmd.Printf("Skipped %s %q: another entry with this name is stored under the new key\n", entryType, md.Name)
+
+						continue
+					}
+
+					if errors.Is(err, entry.ErrEntryNotFound) || errors.Is(err, entry.ErrConflict) {
+						cmd.Printf("Skipped %s %q: it was changed in the meantime, run the command again\n", entryType, md.Name)
+
+						continue
+					}
+
+					return fmt.Errorf("error moving %s %q: %w", entryType, md.Name, err)
+				}
+
+				moved++
+			}
+
+			cmd.Printf("Moved %d of %d entries\n", moved, len(mds))
+
+			return nil
+		},
+	}
+}
+
+// legacyEntryType returns the type of an entry stored under the legacy key, and whether it is stored so.
+func legacyEntryType(md entry.Metadata) (string, bool) {
+	entryType := detectEntryType(md)
+	if entryType == "unknown" {
+		return "", false
+	}
+
+	return entryType, utils.GetEntryKey(entryType, md.Name) == md.Key
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestRekey(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestRekeyCommand := func(container container.Container) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newRekeyCommand(container)
+		// gkeep rekey
+		cmd.SetArgs([]string{})
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	mds := []entry.Metadata{
+		// stored before the server kept types
+		{Key: utils.GetEntryKey("login", "github"), Name: "github", Version: 2},
+		{Key: utils.GetEntryKey("card", "visa"), Name: "visa", Type: "card", Version: 1},
+		// already moved
+		{Key: testEntryKey("text", "diary"), Name: "diary", Type: "text", Version: 1},
+		{Key: "some-key", Name: "mystery"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListTrash(authCtx).Return([]entry.TrashItem{{Metadata: mds[2]}}, nil)
+		entryService.EXPECT().ListEntries(authCtx).Return(mds, nil)
+		entryService.EXPECT().RenameEntry(
+			authCtx,
+			utils.GetEntryKey("login", "github"),
+			entry.Metadata{Key: testEntryKey("login", "github"), Name: "github", Type: "login", Version: 2},
+		).Return(nil)
+		entryService.EXPECT().RenameEntry(
+			authCtx,
+			utils.GetEntryKey("card", "visa"),
+			entry.Metadata{Key: testEntryKey("card", "visa"), Name: "visa", Type: "card", Version: 1},
+		).Return(nil)
+
+		cmd, out := newTestRekeyCommand(container)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Moved 2 of 4 entries")
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListTrash(authCtx).Return([]entry.TrashItem{{Metadata: mds[2]}}, nil)
+		entryService.EXPECT().ListEntries(authCtx).Return(mds[:2], nil)
+		entryService.EXPECT().RenameEntry(authCtx, utils.GetEntryKey("login", "github"), gomock.Any()).Return(entry.ErrEntryExists)
+		entryService.EXPECT().RenameEntry(authCtx, utils.GetEntryKey("card", "visa"), gomock.Any()).Return(entry.ErrConflict)
+
+		cmd, out := newTestRekeyCommand(container)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `Skipped login "github": another entry with this name is stored under the new key`)
+		require.Contains(t, out.String(), `Skipped card "visa": it was changed in the meantime, run the command again`)
+		require.Contains(t, out.String(), "Moved 0 of 2 entries")
+	})
+
+	t.Run("rename error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListTrash(authCtx).Return([]entry.TrashItem{{Metadata: mds[2]}}, nil)
+		entryService.EXPECT().ListEntries(authCtx).Return(mds, nil)
+		entryService.EXPECT().RenameEntry(authCtx, gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+		cmd, _ := newTestRekeyCommand(container)
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("legacy entries in trash", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListTrash(authCtx).Return([]entry.TrashItem{{Metadata: mds[0]}, {Metadata: mds[2]}}, nil)
+
+		cmd, _ := newTestRekeyCommand(container)
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "1 deleted entries are stored under the legacy keys")
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().ListTrash(authCtx).Return([]entry.TrashItem{{Metadata: mds[2]}}, nil)
+		entryService.EXPECT().ListEntries(authCtx).Return(nil, errors.New("error"))
+
+		cmd, _ := newTestRekeyCommand(container)
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
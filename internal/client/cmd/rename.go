@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+)
+
+func newRenameCommand(container container.Container) *cobra.Command {
+	rename := &cobra.Command{
+		Use:   "rename <type> <name> <new name>",
+		Short: "Rename entry",
+		Long:  "Rename entry stored in the cloud. The content isn't downloaded or uploaded again, and the history is kept",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, oldName, newName := args[0], args[1], args[2]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if oldName == "" || newName == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			if oldName == newName {
+				return fmt.Errorf("new name is the same as the old one")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			cmd.Printf("Renaming %s...\n", entryType)
+
+			err = service.RenameEntry(ctxWithToken, service.EntryKey(entryType, oldName), entry.Metadata{
+				Key:  service.EntryKey(entryType, newName),
+				Name: newName,
+				Type: entryType,
+			})
+			if err != nil {
+				if errors.Is(err, entry.ErrEntryNotFound) {
+					cmd.Println("Entry not found")
+
+					return nil
+				}
+
+				if errors.Is(err, entry.ErrEntryExists) {
+					return fmt.Errorf("%s %q already exists", entryType, newName)
+				}
+
+				return fmt.Errorf("error renaming entry: %w", err)
+			}
+
+			cmd.Println("Successfully renamed!")
+
+			return nil
+		},
+	}
+
+	return rename
+}
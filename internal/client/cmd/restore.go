@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+)
+
+func newRestoreCommand(container container.Container) *cobra.Command {
+	restore := &cobra.Command{
+		Use:   "restore <type> <name> <version>",
+		Short: "Restore previous version of entry",
+		Long:  "Make a previous version of entry the current one. The current version is kept in the history, so restore can be undone. See history command for available versions",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, name := args[0], args[1]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			version, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil || version <= 0 {
+				return fmt.Errorf("version must be a positive number")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			cmd.Printf("Restoring version %d of %s...\n", version, entryType)
+
+			err = service.RestoreVersion(ctxWithToken, service.EntryKey(entryType, name), version)
+			if err != nil {
+				if errors.Is(err, entry.ErrVersionNotFound) {
+					cmd.Println("Version not found")
+
+					return nil
+				}
+
+				return fmt.Errorf("error restoring version: %w", err)
+			}
+
+			cmd.Println("Version restored successfully!")
+
+			return nil
+		},
+	}
+
+	return restore
+}
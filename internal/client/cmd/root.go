@@ -54,6 +54,7 @@ func NewRootCommand(container container.Container) *cobra.Command {
 		},
 	}
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output. Useful for debugging")
+	rootCmd.PersistentFlags().String("vault", "", "Name or id of the vault to work with instead of the one chosen with vault use")
 
 	secret := newSecretCommand(container)
 	rootCmd.AddCommand(secret)
@@ -72,19 +73,75 @@ func NewRootCommand(container container.Container) *cobra.Command {
 
 	ensureSecretSet := middleware.EnsureSecretSet(container)
 	ensureLoggedIn := middleware.EnsureLoggedIn(container)
+	selectVault := middleware.SelectVault(container)
 
 	set := newSetCommand(container)
-	set.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(set.PersistentPreRunE)))
+	set.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(set.PersistentPreRunE))))
 	rootCmd.AddCommand(set)
 
 	getCmd := newGetCommand(container)
-	getCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(getCmd.PersistentPreRunE)))
+	getCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(getCmd.PersistentPreRunE))))
 	rootCmd.AddCommand(getCmd)
 
 	deleteCmd := newDeleteCommand(container)
-	deleteCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureLoggedIn(deleteCmd.PersistentPreRunE))
+	deleteCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(deleteCmd.PersistentPreRunE))))
 	rootCmd.AddCommand(deleteCmd)
 
+	listCmd := newListCommand(container)
+	listCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(listCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(listCmd)
+
+	historyCmd := newHistoryCommand(container)
+	historyCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(historyCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(historyCmd)
+
+	restoreCmd := newRestoreCommand(container)
+	restoreCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(restoreCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(restoreCmd)
+
+	renameCmd := newRenameCommand(container)
+	renameCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(renameCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(renameCmd)
+
+	trashCmd := newTrashCommand(container)
+	trashCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(trashCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(trashCmd)
+
+	changesCmd := newChangesCommand(container)
+	changesCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(changesCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(changesCmd)
+
+	watchCmd := newWatchCommand(container)
+	watchCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(watchCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(watchCmd)
+
+	usageCmd := newUsageCommand(container)
+	usageCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureLoggedIn(selectVault(usageCmd.PersistentPreRunE)))
+	rootCmd.AddCommand(usageCmd)
+
+	rekeyCmd := newRekeyCommand(container)
+	rekeyCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(rekeyCmd.PersistentPreRunE))))
+	rootCmd.AddCommand(rekeyCmd)
+
+	// sharing works with the user's own values and key pair only
+	ensureNoActiveVault := middleware.EnsureNoActiveVault(container)
+
+	shareCmd := newShareCommand(container)
+	shareCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(ensureNoActiveVault(shareCmd.PersistentPreRunE)))))
+	rootCmd.AddCommand(shareCmd)
+
+	sharedCmd := newSharedCommand(container)
+	sharedCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(selectVault(ensureNoActiveVault(sharedCmd.PersistentPreRunE)))))
+	rootCmd.AddCommand(sharedCmd)
+
+	vaultCmd := newVaultCommand(container)
+	vaultCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(vaultCmd.PersistentPreRunE)))
+	rootCmd.AddCommand(vaultCmd)
+
+	emergencyCmd := newEmergencyCommand(container)
+	emergencyCmd.PersistentPreRunE = middleware.Combine(rootCmd.PersistentPreRunE, ensureSecretSet(ensureLoggedIn(emergencyCmd.PersistentPreRunE)))
+	rootCmd.AddCommand(emergencyCmd)
+
 	rootCmd.AddCommand(newConfigPathCommand())
 
 	return rootCmd
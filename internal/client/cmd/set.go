@@ -12,8 +12,8 @@ import (
 
 	"github.com/kuvalkin/gophkeeper/internal/client/cmd/entries"
 	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/client/tui/prompts"
-	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
 func newSetCommand(container container.Container) *cobra.Command {
@@ -296,7 +296,14 @@ func store(ctx context.Context, container container.Container, entryType string,
 		return fmt.Errorf("error getting entry service: %w", err)
 	}
 
-	err = service.SetEntry(ctxWithToken, utils.GetEntryKey(entryType, name), name, notes, content, func() bool {
+	md := entry.Metadata{
+		Key:   service.EntryKey(entryType, name),
+		Name:  name,
+		Notes: notes,
+		Type:  entryType,
+	}
+
+	err = service.SetEntry(ctxWithToken, md, content, func() bool {
 		prompter, err := container.GetPrompter(ctx)
 		if err != nil {
 			return false
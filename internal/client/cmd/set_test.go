@@ -13,6 +13,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
 	"github.com/kuvalkin/gophkeeper/internal/client/tui/prompts"
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
@@ -40,7 +41,7 @@ func TestSetLogin(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -53,7 +54,12 @@ func TestSetLogin(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("login", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(nil)
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("login", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "login",
+		}, gomock.Any(), gomock.Any()).Return(nil)
 
 		cmd := newTestSetLoginCommand(container, "name", "notes")
 		err := cmd.Execute()
@@ -219,7 +225,7 @@ func TestSetLogin(t *testing.T) {
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
 		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
@@ -231,7 +237,12 @@ func TestSetLogin(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("login", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(errors.New("error"))
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("login", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "login",
+		}, gomock.Any(), gomock.Any()).Return(errors.New("error"))
 
 		cmd := newTestSetLoginCommand(container, "name", "notes")
 		err := cmd.Execute()
@@ -246,7 +257,7 @@ func TestSetLogin(t *testing.T) {
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
 			container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
@@ -258,8 +269,13 @@ func TestSetLogin(t *testing.T) {
 			authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 			authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-			entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("login", "name"), "name", "notes", gomock.Any(), gomock.Any()).Do(
-				func(ctx context.Context, key string, name string, notes string, content io.ReadCloser, onOverwrite func() bool) {
+			entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+				Key:   testEntryKey("login", "name"),
+				Name:  "name",
+				Notes: "notes",
+				Type:  "login",
+			}, gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, md entry.Metadata, content io.ReadCloser, onOverwrite func() bool) {
 					require.NotNil(t, onOverwrite)
 					require.True(t, onOverwrite())
 				},
@@ -278,7 +294,7 @@ func TestSetLogin(t *testing.T) {
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
 			container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
@@ -290,8 +306,13 @@ func TestSetLogin(t *testing.T) {
 			authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 			authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-			entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("login", "name"), "name", "notes", gomock.Any(), gomock.Any()).Do(
-				func(ctx context.Context, key string, name string, notes string, content io.ReadCloser, onOverwrite func() bool) {
+			entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+				Key:   testEntryKey("login", "name"),
+				Name:  "name",
+				Notes: "notes",
+				Type:  "login",
+			}, gomock.Any(), gomock.Any()).Do(
+				func(ctx context.Context, md entry.Metadata, content io.ReadCloser, onOverwrite func() bool) {
 					require.NotNil(t, onOverwrite)
 					require.False(t, onOverwrite())
 				},
@@ -310,7 +331,7 @@ func TestSetLogin(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -331,7 +352,7 @@ func TestSetLogin(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -368,7 +389,7 @@ func TestSetFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -377,7 +398,12 @@ func TestSetFile(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("file", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(nil)
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("file", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "file",
+		}, gomock.Any(), gomock.Any()).Return(nil)
 
 		file, err := os.CreateTemp("", "set-file-test-*")
 		require.NoError(t, err)
@@ -431,7 +457,7 @@ func TestSetFile(t *testing.T) {
 		defer ctrl.Finish()
 
 		container := mocks.NewMockContainer(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
@@ -440,7 +466,12 @@ func TestSetFile(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("file", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(errors.New("error"))
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("file", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "file",
+		}, gomock.Any(), gomock.Any()).Return(errors.New("error"))
 
 		file, err := os.CreateTemp("", "set-file-test-*")
 		require.NoError(t, err)
@@ -479,7 +510,7 @@ func TestSetCard(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -495,7 +526,12 @@ func TestSetCard(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("card", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(nil)
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("card", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "card",
+		}, gomock.Any(), gomock.Any()).Return(nil)
 
 		cmd := newTestSetCardCommand(container, "name", "notes")
 		err := cmd.Execute()
@@ -520,7 +556,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -540,7 +576,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -560,7 +596,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -581,7 +617,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -603,7 +639,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -642,7 +678,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -661,7 +697,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -681,7 +717,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -702,7 +738,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -724,7 +760,7 @@ func TestSetCard(t *testing.T) {
 
 			container := mocks.NewMockContainer(ctrl)
 			prompter := mocks.NewMockPrompter(ctrl)
-			entryService := mocks.NewMockEntryService(ctrl)
+			entryService := newTestEntryService(ctrl)
 			authService := mocks.NewMockAuthService(ctrl)
 
 			container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -749,7 +785,7 @@ func TestSetCard(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -765,7 +801,12 @@ func TestSetCard(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("card", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(errors.New("error"))
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("card", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "card",
+		}, gomock.Any(), gomock.Any()).Return(errors.New("error"))
 
 		cmd := newTestSetCardCommand(container, "name", "notes")
 		err := cmd.Execute()
@@ -778,7 +819,7 @@ func TestSetCard(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -802,7 +843,7 @@ func TestSetCard(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -843,7 +884,7 @@ func TestSetText(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -855,7 +896,12 @@ func TestSetText(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("text", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(nil)
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("text", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "text",
+		}, gomock.Any(), gomock.Any()).Return(nil)
 
 		cmd := newTestSetTextCommand(container, "name", "notes")
 		err := cmd.Execute()
@@ -892,7 +938,7 @@ func TestSetText(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -912,7 +958,7 @@ func TestSetText(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -932,7 +978,7 @@ func TestSetText(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
@@ -944,7 +990,12 @@ func TestSetText(t *testing.T) {
 		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
 		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
 
-		entryService.EXPECT().SetEntry(authCtx, utils.GetEntryKey("text", "name"), "name", "notes", gomock.Any(), gomock.Any()).Return(errors.New("error"))
+		entryService.EXPECT().SetEntry(authCtx, entry.Metadata{
+			Key:   testEntryKey("text", "name"),
+			Name:  "name",
+			Notes: "notes",
+			Type:  "text",
+		}, gomock.Any(), gomock.Any()).Return(errors.New("error"))
 
 		cmd := newTestSetTextCommand(container, "name", "notes")
 		err := cmd.Execute()
@@ -957,7 +1008,7 @@ func TestSetText(t *testing.T) {
 
 		container := mocks.NewMockContainer(ctrl)
 		prompter := mocks.NewMockPrompter(ctrl)
-		entryService := mocks.NewMockEntryService(ctrl)
+		entryService := newTestEntryService(ctrl)
 		authService := mocks.NewMockAuthService(ctrl)
 
 		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
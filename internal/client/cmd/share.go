@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/cmd/entries"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func newShareCommand(container container.Container) *cobra.Command {
+	share := &cobra.Command{
+		Use:   "share <type> <name> <login>",
+		Short: "Share value with another user",
+		Long:  "Store a copy of the value encrypted so only the user with the given login can read it. Later changes of the value aren't shared until it's shared again, and the copy counts towards your storage quota. The user needs to run shared list once before anything can be shared with them",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, name, login := args[0], args[1], args[2]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			if login == "" {
+				return fmt.Errorf("login is empty")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			cmd.Printf("Sharing %s with %s...\n", entryType, login)
+
+			_, err = service.ShareEntry(ctxWithToken, service.EntryKey(entryType, name), login)
+			if err != nil {
+				switch {
+				case errors.Is(err, entry.ErrEntryNotFound):
+					cmd.Println("Entry not found")
+
+					return nil
+				case errors.Is(err, entry.ErrRecipientNotFound):
+					return fmt.Errorf("user %q not found or hasn't set up sharing yet, ask them to run shared list once", login)
+				case errors.Is(err, entry.ErrShareWithSelf):
+					return fmt.Errorf("cant share entry with yourself")
+				case errors.Is(err, entry.ErrQuotaExceeded):
+					return fmt.Errorf("storage quota exceeded, see usage command")
+				default:
+					return fmt.Errorf("error sharing entry: %w", err)
+				}
+			}
+
+			cmd.Println("Successfully shared!")
+
+			return nil
+		},
+	}
+
+	return share
+}
+
+func newSharedCommand(container container.Container) *cobra.Command {
+	shared := &cobra.Command{
+		Use:   "shared",
+		Short: "Manage shared values",
+		Long:  "Values shared with you by other users, and the ones you shared with them",
+	}
+
+	shared.AddCommand(newSharedListCommand(container))
+	shared.AddCommand(newSharedGetCommand(container))
+	shared.AddCommand(newSharedRevokeCommand(container))
+
+	return shared
+}
+
+func newSharedListCommand(container container.Container) *cobra.Command {
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List shared values",
+		Long:  "List values shared with you, or the ones you shared with others. Listing values shared with you for the first time also lets others share values with you",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outgoing, err := cmd.Flags().GetBool("outgoing")
+			if err != nil {
+				return fmt.Errorf("error getting outgoing flag: %w", err)
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			shares, err := service.ListShares(ctxWithToken, !outgoing)
+			if err != nil {
+				return fmt.Errorf("error listing shared entries: %w", err)
+			}
+
+			if len(shares) == 0 {
+				cmd.Println("No shared entries found")
+
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			if outgoing {
+				_, _ = fmt.Fprintln(w, "NAME\tTYPE\tSHARED WITH\tSHARED")
+			} else {
+				_, _ = fmt.Fprintln(w, "ID\tNAME\tTYPE\tSHARED BY\tSHARED\tNOTES")
+			}
+
+			for _, share := range shares {
+				if outgoing {
+					name, entryType := share.Metadata.Name, share.Metadata.Type
+					if name == "" {
+						// the entry was deleted or renamed since, but the copy is still there
+						name, entryType = "(gone)", "unknown"
+					}
+
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, entryType, share.Recipient, share.CreatedAt.Local().Format(time.DateTime))
+
+					continue
+				}
+
+				_, _ = fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\t%s\n",
+					share.ID,
+					share.Metadata.Name,
+					share.Metadata.Type,
+					share.Owner,
+					share.CreatedAt.Local().Format(time.DateTime),
+					previewNotes(share.Metadata.Notes),
+				)
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing shared entries: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	list.Flags().Bool("outgoing", false, "List values you shared with others instead of the ones shared with you")
+
+	return list
+}
+
+func newSharedGetCommand(container container.Container) *cobra.Command {
+	get := &cobra.Command{
+		Use:   "get <id> [path]",
+		Short: "Get value shared with you",
+		Long:  "Get value shared with you, decrypt it and show it. Shared files are stored in a provided path, if the file in path already exists, it will be overwritten. See shared list command for the ids",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			if id == "" {
+				return fmt.Errorf("id is empty")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			cmd.Println("Getting shared entry...")
+
+			share, content, exists, err := service.GetSharedEntry(ctxWithToken, id)
+			if err != nil {
+				return fmt.Errorf("error getting shared entry: %w", err)
+			}
+
+			if !exists {
+				cmd.Println("Shared entry not found")
+
+				return nil
+			}
+
+			defer utils.CloseAndLogError(content, nil)
+
+			md := share.Metadata
+			cmd.Printf("Shared by %s: %s %q\n", share.Owner, md.Type, md.Name)
+
+			switch md.Type {
+			case "login":
+				entry := &entries.LoginPasswordPair{}
+				err = entry.Unmarshal(content)
+				if err != nil {
+					return fmt.Errorf("error unmarshaling login: %w", err)
+				}
+
+				cmd.Printf("Login: %s\nPassword: %s\n", entry.Login, entry.Password)
+			case "card":
+				entry := &entries.BankCard{}
+				err = entry.Unmarshal(content)
+				if err != nil {
+					return fmt.Errorf("error unmarshaling card: %w", err)
+				}
+
+				cmd.Println("Number:", entry.Number)
+				cmd.Println("Holder:", entry.HolderName)
+				cmd.Println("Expiration Year:", entry.ExpirationDate.Year)
+				cmd.Println("Expiration Month:", entry.ExpirationDate.Month)
+				cmd.Println("CVV:", entry.CVV)
+			case "file":
+				if len(args) < 2 || args[1] == "" {
+					return fmt.Errorf("path is required to store the shared file")
+				}
+
+				err = storeSharedFile(cmd, content, args[1])
+				if err != nil {
+					return err
+				}
+			default:
+				text, err := io.ReadAll(content)
+				if err != nil {
+					return fmt.Errorf("error reading text: %w", err)
+				}
+
+				cmd.Println("Text:")
+				cmd.Println(string(text))
+			}
+
+			cmd.Println("")
+			cmd.Println("Notes:", md.Notes)
+			cmd.Println("Shared:", share.CreatedAt.Local().Format(time.DateTime))
+
+			return nil
+		},
+	}
+
+	return get
+}
+
+// storeSharedFile writes the content of the shared file to the path, overwriting the file if it exists.
+func storeSharedFile(cmd *cobra.Command, content io.Reader, pathToDst string) error {
+	cmd.Println("Storing file...")
+
+	err := os.MkdirAll(filepath.Dir(pathToDst), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	dst, err := os.OpenFile(pathToDst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer utils.CloseAndLogError(dst, nil)
+
+	_, err = utils.CopyContext(cmd.Context(), dst, content)
+	if err != nil {
+		return fmt.Errorf("error copying file: %w", err)
+	}
+
+	cmd.Println("File stored successfully!")
+
+	return nil
+}
+
+func newSharedRevokeCommand(container container.Container) *cobra.Command {
+	revoke := &cobra.Command{
+		Use:   "revoke <type> <name> <login>",
+		Short: "Stop sharing value with another user",
+		Long:  "Delete the copy of the value shared with the user with the given login. Keep in mind the user could have saved the value while it was shared",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, name, login := args[0], args[1], args[2]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			if login == "" {
+				return fmt.Errorf("login is empty")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.RevokeShare(ctxWithToken, service.EntryKey(entryType, name), login)
+			if err != nil {
+				if errors.Is(err, entry.ErrShareNotFound) {
+					cmd.Printf("%s %q isn't shared with %s\n", entryType, name, login)
+
+					return nil
+				}
+
+				return fmt.Errorf("error revoking share: %w", err)
+			}
+
+			cmd.Println("Successfully revoked!")
+
+			return nil
+		},
+	}
+
+	return revoke
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/cmd/entries"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestShareCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newShareCommand(container)
+		// gkeep share {args}
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	newMocks := func(ctrl *gomock.Controller) (*mocks.MockContainer, *mocks.MockEntryService, context.Context) {
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil).AnyTimes()
+
+		return container, entryService, authCtx
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ShareEntry(authCtx, testEntryKey("login", "github"), "alice").Return(entry.Share{ID: "share"}, nil)
+
+		cmd, out := newTestShareCommand(container, "login", "github", "alice")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Successfully shared!")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ShareEntry(authCtx, gomock.Any(), "alice").Return(entry.Share{}, entry.ErrEntryNotFound)
+
+		cmd, out := newTestShareCommand(container, "text", "name", "alice")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Entry not found")
+	})
+
+	for name, tc := range map[string]struct {
+		err      error
+		contains string
+	}{
+		"recipient not found": {err: entry.ErrRecipientNotFound, contains: "shared list"},
+		"share with self":     {err: entry.ErrShareWithSelf, contains: "yourself"},
+		"quota exceeded":      {err: entry.ErrQuotaExceeded, contains: "quota"},
+		"error sharing":       {err: errors.New("err"), contains: "error sharing entry"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			container, entryService, authCtx := newMocks(ctrl)
+
+			entryService.EXPECT().ShareEntry(authCtx, gomock.Any(), "alice").Return(entry.Share{}, tc.err)
+
+			cmd, _ := newTestShareCommand(container, "text", "name", "alice")
+			err := cmd.Execute()
+			require.ErrorContains(t, err, tc.contains)
+		})
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		cmd, _ := newTestShareCommand(container, "unknown", "name", "alice")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("no login", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		cmd, _ := newTestShareCommand(container, "text", "name", "")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
+
+func TestShared(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestSharedCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newSharedCommand(container)
+		// gkeep shared {args}
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	newMocks := func(ctrl *gomock.Controller) (*mocks.MockContainer, *mocks.MockEntryService, context.Context) {
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil).AnyTimes()
+
+		return container, entryService, authCtx
+	}
+
+	sharedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ListShares(authCtx, true).Return([]entry.Share{
+			{
+				ID:        "share",
+				Owner:     "bob",
+				Metadata:  entry.Metadata{Name: "github", Type: "login", Notes: "work account"},
+				CreatedAt: sharedAt,
+			},
+		}, nil)
+
+		cmd, out := newTestSharedCommand(container, "list")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(t, `share\s+github\s+login\s+bob\s+`+sharedAt.Local().Format(time.DateTime)+`\s+work account`, out.String())
+	})
+
+	t.Run("list outgoing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ListShares(authCtx, false).Return([]entry.Share{
+			{Recipient: "alice", Metadata: entry.Metadata{Name: "github", Type: "login"}, CreatedAt: sharedAt},
+			{Recipient: "carol", CreatedAt: sharedAt},
+		}, nil)
+
+		cmd, out := newTestSharedCommand(container, "list", "--outgoing")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(t, `github\s+login\s+alice\s+`+sharedAt.Local().Format(time.DateTime), out.String())
+		require.Regexp(t, `\(gone\)\s+unknown\s+carol`, out.String())
+	})
+
+	t.Run("list empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ListShares(authCtx, true).Return([]entry.Share{}, nil)
+
+		cmd, out := newTestSharedCommand(container, "list")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "No shared entries found")
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ListShares(authCtx, true).Return(nil, errors.New("err"))
+
+		cmd, _ := newTestSharedCommand(container, "list")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("get login", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		pair := &entries.LoginPasswordPair{
+			Login:    "mylogin",
+			Password: "mypassword",
+		}
+		content, err := pair.Marshal()
+		require.NoError(t, err)
+
+		share := entry.Share{
+			ID:        "share",
+			Owner:     "bob",
+			Metadata:  entry.Metadata{Name: "github", Type: "login", Notes: "mynotes"},
+			CreatedAt: sharedAt,
+		}
+		entryService.EXPECT().GetSharedEntry(authCtx, "share").Return(share, content, true, nil)
+
+		cmd, out := newTestSharedCommand(container, "get", "share")
+		err = cmd.Execute()
+		require.NoError(t, err)
+		outString := out.String()
+		require.Contains(t, outString, "Shared by bob")
+		require.Contains(t, outString, "mylogin")
+		require.Contains(t, outString, "mypassword")
+		require.Contains(t, outString, "mynotes")
+	})
+
+	t.Run("get text", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		share := entry.Share{ID: "share", Owner: "bob", Metadata: entry.Metadata{Name: "note", Type: "text"}}
+		content := io.NopCloser(bytes.NewBufferString("mytext"))
+		entryService.EXPECT().GetSharedEntry(authCtx, "share").Return(share, content, true, nil)
+
+		cmd, out := newTestSharedCommand(container, "get", "share")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "mytext")
+	})
+
+	t.Run("get file", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		share := entry.Share{ID: "share", Owner: "bob", Metadata: entry.Metadata{Name: "doc", Type: "file"}}
+		content := io.NopCloser(bytes.NewBufferString("file content"))
+		entryService.EXPECT().GetSharedEntry(authCtx, "share").Return(share, content, true, nil)
+
+		path := filepath.Join(t.TempDir(), "doc")
+
+		cmd, out := newTestSharedCommand(container, "get", "share", path)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "File stored successfully!")
+		written, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "file content", string(written))
+	})
+
+	t.Run("get file without path", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		share := entry.Share{ID: "share", Owner: "bob", Metadata: entry.Metadata{Name: "doc", Type: "file"}}
+		content := io.NopCloser(bytes.NewBufferString("file content"))
+		entryService.EXPECT().GetSharedEntry(authCtx, "share").Return(share, content, true, nil)
+
+		cmd, _ := newTestSharedCommand(container, "get", "share")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "path is required")
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().GetSharedEntry(authCtx, "share").Return(entry.Share{}, nil, false, nil)
+
+		cmd, out := newTestSharedCommand(container, "get", "share")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Shared entry not found")
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().GetSharedEntry(authCtx, "share").Return(entry.Share{}, nil, false, errors.New("err"))
+
+		cmd, _ := newTestSharedCommand(container, "get", "share")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().RevokeShare(authCtx, testEntryKey("login", "github"), "alice").Return(nil)
+
+		cmd, out := newTestSharedCommand(container, "revoke", "login", "github", "alice")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Successfully revoked!")
+	})
+
+	t.Run("revoke not shared", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().RevokeShare(authCtx, testEntryKey("login", "github"), "alice").Return(entry.ErrShareNotFound)
+
+		cmd, out := newTestSharedCommand(container, "revoke", "login", "github", "alice")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "isn't shared with alice")
+	})
+
+	t.Run("revoke error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().RevokeShare(authCtx, gomock.Any(), "alice").Return(errors.New("err"))
+
+		cmd, _ := newTestSharedCommand(container, "revoke", "text", "name", "alice")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
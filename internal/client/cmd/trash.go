@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+)
+
+func newTrashCommand(container container.Container) *cobra.Command {
+	trash := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage deleted values",
+		Long:  "Deleted values are kept in the trash for a while before they are purged for good. Until then they can be restored",
+	}
+
+	trash.AddCommand(newTrashListCommand(container))
+	trash.AddCommand(newTrashRestoreCommand(container))
+	trash.AddCommand(newTrashPurgeCommand(container))
+
+	return trash
+}
+
+func newTrashListCommand(container container.Container) *cobra.Command {
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List deleted values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			items, err := service.ListTrash(ctxWithToken)
+			if err != nil {
+				return fmt.Errorf("error listing trash: %w", err)
+			}
+
+			if len(items) == 0 {
+				cmd.Println("Trash is empty")
+
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tTYPE\tDELETED\tPURGED AFTER\tNOTES")
+
+			for _, item := range items {
+				_, _ = fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\n",
+					item.Metadata.Name,
+					detectEntryType(item.Metadata),
+					item.DeletedAt.Local().Format(time.DateTime),
+					item.ExpiresAt.Local().Format(time.DateTime),
+					previewNotes(item.Metadata.Notes),
+				)
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing trash: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return list
+}
+
+func newTrashRestoreCommand(container container.Container) *cobra.Command {
+	restore := &cobra.Command{
+		Use:   "restore <type> <name>",
+		Short: "Restore deleted value",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, name := args[0], args[1]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			cmd.Printf("Restoring %s...\n", entryType)
+
+			err = service.RestoreEntry(ctxWithToken, service.EntryKey(entryType, name))
+			if err != nil {
+				switch {
+				case errors.Is(err, entry.ErrEntryNotFound):
+					cmd.Println("Entry not found in trash")
+
+					return nil
+				case errors.Is(err, entry.ErrEntryExists):
+					return fmt.Errorf("%s %q was stored again after the deletion, delete or rename it first", entryType, name)
+				case errors.Is(err, entry.ErrQuotaExceeded):
+					return fmt.Errorf("storage quota exceeded, see usage command")
+				default:
+					return fmt.Errorf("error restoring entry: %w", err)
+				}
+			}
+
+			cmd.Println("Successfully restored!")
+
+			return nil
+		},
+	}
+
+	return restore
+}
+
+func newTrashPurgeCommand(container container.Container) *cobra.Command {
+	purge := &cobra.Command{
+		Use:   "purge <type> <name>",
+		Short: "Delete value from trash",
+		Long:  "Delete value from trash right away instead of waiting for it to expire. This operation is final and can't be undone",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, name := args[0], args[1]
+
+			err := validateEntryType(entryType)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			prompter, err := container.GetPrompter(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cant get prompter: %w", err)
+			}
+
+			if !prompter.Confirm(cmd.Context(), fmt.Sprintf("Are you sure you want to purge %s? It can't be restored afterwards", entryType)) {
+				return nil
+			}
+
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.PurgeEntry(ctxWithToken, service.EntryKey(entryType, name))
+			if err != nil {
+				if errors.Is(err, entry.ErrEntryNotFound) {
+					cmd.Println("Entry not found in trash")
+
+					return nil
+				}
+
+				return fmt.Errorf("error purging entry: %w", err)
+			}
+
+			cmd.Println("Successfully purged!")
+
+			return nil
+		},
+	}
+
+	return purge
+}
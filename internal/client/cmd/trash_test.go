@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestTrash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestTrashCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newTrashCommand(container)
+		// gkeep trash {args}
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	newMocks := func(ctrl *gomock.Controller) (*mocks.MockContainer, *mocks.MockEntryService, context.Context) {
+		container := mocks.NewMockContainer(ctrl)
+		entryService := newTestEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil).AnyTimes()
+
+		return container, entryService, authCtx
+	}
+
+	t.Run("list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		expiresAt := deletedAt.Add(30 * 24 * time.Hour)
+		entryService.EXPECT().ListTrash(authCtx).Return([]entry.TrashItem{
+			{
+				Metadata:  entry.Metadata{Key: testEntryKey("login", "github"), Name: "github", Type: "login", Notes: "work account"},
+				DeletedAt: deletedAt,
+				ExpiresAt: expiresAt,
+			},
+		}, nil)
+
+		cmd, out := newTestTrashCommand(container, "list")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(
+			t,
+			`github\s+login\s+`+deletedAt.Local().Format(time.DateTime)+`\s+`+expiresAt.Local().Format(time.DateTime)+`\s+work account`,
+			out.String(),
+		)
+	})
+
+	t.Run("list empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ListTrash(authCtx).Return(nil, nil)
+
+		cmd, out := newTestTrashCommand(container, "list")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Trash is empty")
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().ListTrash(authCtx).Return(nil, errors.New("err"))
+
+		cmd, _ := newTestTrashCommand(container, "list")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().RestoreEntry(authCtx, testEntryKey("login", "github")).Return(nil)
+
+		cmd, out := newTestTrashCommand(container, "restore", "login", "github")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Successfully restored!")
+	})
+
+	t.Run("restore not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().RestoreEntry(authCtx, testEntryKey("login", "github")).Return(entry.ErrEntryNotFound)
+
+		cmd, out := newTestTrashCommand(container, "restore", "login", "github")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Entry not found in trash")
+	})
+
+	t.Run("restore exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+
+		entryService.EXPECT().RestoreEntry(authCtx, testEntryKey("login", "github")).Return(entry.ErrEntryExists)
+
+		cmd, _ := newTestTrashCommand(container, "restore", "login", "github")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "was stored again")
+	})
+
+	t.Run("restore unknown type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+
+		cmd, _ := newTestTrashCommand(container, "restore", "unknown", "name")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+		prompter := mocks.NewMockPrompter(ctrl)
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
+
+		prompter.EXPECT().Confirm(ctx, gomock.Any()).Return(true)
+		entryService.EXPECT().PurgeEntry(authCtx, testEntryKey("card", "visa")).Return(nil)
+
+		cmd, out := newTestTrashCommand(container, "purge", "card", "visa")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Successfully purged!")
+	})
+
+	t.Run("purge not confirmed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, _, _ := newMocks(ctrl)
+		prompter := mocks.NewMockPrompter(ctrl)
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
+
+		prompter.EXPECT().Confirm(ctx, gomock.Any()).Return(false)
+
+		cmd, _ := newTestTrashCommand(container, "purge", "card", "visa")
+		err := cmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("purge not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+		prompter := mocks.NewMockPrompter(ctrl)
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
+
+		prompter.EXPECT().Confirm(ctx, gomock.Any()).Return(true)
+		entryService.EXPECT().PurgeEntry(authCtx, testEntryKey("card", "visa")).Return(entry.ErrEntryNotFound)
+
+		cmd, out := newTestTrashCommand(container, "purge", "card", "visa")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Entry not found in trash")
+	})
+
+	t.Run("purge error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container, entryService, authCtx := newMocks(ctrl)
+		prompter := mocks.NewMockPrompter(ctrl)
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil).AnyTimes()
+
+		prompter.EXPECT().Confirm(ctx, gomock.Any()).Return(true)
+		entryService.EXPECT().PurgeEntry(authCtx, gomock.Any()).Return(errors.New("err"))
+
+		cmd, _ := newTestTrashCommand(container, "purge", "card", "visa")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+)
+
+func newUsageCommand(container container.Container) *cobra.Command {
+	usage := &cobra.Command{
+		Use:   "usage",
+		Short: "Show storage usage",
+		Long:  "Show how much storage is used in the cloud and the limits set by the server. Kept versions and unfinished uploads count towards the usage",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			u, err := service.GetUsage(ctxWithToken)
+			if err != nil {
+				return fmt.Errorf("error getting usage: %w", err)
+			}
+
+			cmd.Printf("Storage: %s\n", formatUsage(u.Bytes, u.MaxBytes, "bytes"))
+			cmd.Printf("Entries: %s\n", formatUsage(u.Entries, u.MaxEntries, "entries"))
+
+			return nil
+		},
+	}
+
+	return usage
+}
+
+// formatUsage formats the used amount along with the limit, zero limit means unlimited.
+func formatUsage(used int64, limit int64, unit string) string {
+	if limit == 0 {
+		return fmt.Sprintf("%d %s (unlimited)", used, unit)
+	}
+
+	return fmt.Sprintf("%d of %d %s", used, limit, unit)
+}
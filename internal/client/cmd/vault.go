@@ -0,0 +1,483 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+)
+
+func newVaultCommand(container container.Container) *cobra.Command {
+	vaultCmd := &cobra.Command{
+		Use:   "vault",
+		Short: "Manage vaults",
+		Long:  "Vaults keep separate sets of values, each encrypted with its own secret. Team vaults are shared by several users: owners manage the members, editors can change the values and viewers can only read them. Personal vaults, e.g. for work, are only yours. Once a vault is selected with vault use, or with the --vault flag for one command, the other commands work with its values instead of your own ones",
+	}
+
+	vaultCmd.AddCommand(newVaultCreateCommand(container))
+	vaultCmd.AddCommand(newVaultListCommand(container))
+	vaultCmd.AddCommand(newVaultRenameCommand(container))
+	vaultCmd.AddCommand(newVaultDeleteCommand(container))
+	vaultCmd.AddCommand(newVaultInviteCommand(container))
+	vaultCmd.AddCommand(newVaultMembersCommand(container))
+	vaultCmd.AddCommand(newVaultRemoveCommand(container))
+	vaultCmd.AddCommand(newVaultUseCommand(container))
+
+	return vaultCmd
+}
+
+func newVaultCreateCommand(container container.Container) *cobra.Command {
+	create := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create vault",
+		Long:  "Create a vault with a new secret, you become its owner. Personal vaults can't have other members",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if name == "" {
+				return fmt.Errorf("name is empty")
+			}
+
+			personal, err := cmd.Flags().GetBool("personal")
+			if err != nil {
+				return fmt.Errorf("error getting personal flag: %w", err)
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			v, err := service.CreateVault(ctxWithToken, name, personal)
+			if err != nil {
+				return fmt.Errorf("error creating vault: %w", err)
+			}
+
+			cmd.Printf("Vault %q created, id %s\n", v.Name, v.ID)
+
+			return nil
+		},
+	}
+
+	create.Flags().Bool("personal", false, "Create a personal vault, which can't have other members")
+
+	return create
+}
+
+func newVaultListCommand(container container.Container) *cobra.Command {
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List vaults",
+		Long:  "List vaults you are a member of. The active one is marked with an asterisk",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			vaults, err := service.ListVaults(ctxWithToken)
+			if err != nil {
+				return fmt.Errorf("error listing vaults: %w", err)
+			}
+
+			if len(vaults) == 0 {
+				cmd.Println("No vaults found")
+
+				return nil
+			}
+
+			active, _, err := service.GetActiveVault(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting active vault: %w", err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "\tNAME\tKIND\tROLE\tCREATED\tID")
+
+			for _, v := range vaults {
+				mark := ""
+				if v.ID == active.ID {
+					mark = "*"
+				}
+
+				kind := "team"
+				if v.Personal {
+					kind = "personal"
+				}
+
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", mark, v.Name, kind, v.Role, v.CreatedAt.Local().Format(time.DateTime), v.ID)
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing vaults: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return list
+}
+
+func newVaultRenameCommand(container container.Container) *cobra.Command {
+	rename := &cobra.Command{
+		Use:   "rename <vault> <new-name>",
+		Short: "Rename vault",
+		Long:  "Change the name of the vault with the given name or id. Only owners can rename",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName, newName := args[0], args[1]
+
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			if newName == "" {
+				return fmt.Errorf("new name is empty")
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.RenameVault(ctxWithToken, vaultName, newName)
+			if err != nil {
+				return vaultError(err)
+			}
+
+			cmd.Printf("Vault renamed to %q\n", newName)
+
+			return nil
+		},
+	}
+
+	return rename
+}
+
+func newVaultDeleteCommand(container container.Container) *cobra.Command {
+	deleteCmd := &cobra.Command{
+		Use:   "delete <vault>",
+		Short: "Delete vault",
+		Long:  "Delete the vault with the given name or id. Only owners can delete, and only empty vaults: delete the values and purge them from the trash first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName := args[0]
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			prompter, err := container.GetPrompter(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cant get prompter: %w", err)
+			}
+
+			if !prompter.Confirm(cmd.Context(), fmt.Sprintf("Are you sure you want to delete vault %s?", vaultName)) {
+				return nil
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.DeleteVault(ctxWithToken, vaultName)
+			if err != nil {
+				if errors.Is(err, vault.ErrVaultNotEmpty) {
+					return fmt.Errorf("vault still has values, delete them with --vault %s and purge them from the trash first", vaultName)
+				}
+
+				return vaultError(err)
+			}
+
+			cmd.Println("Vault deleted!")
+
+			return nil
+		},
+	}
+
+	return deleteCmd
+}
+
+func newVaultInviteCommand(container container.Container) *cobra.Command {
+	invite := &cobra.Command{
+		Use:   "invite <vault> <login>",
+		Short: "Add user to vault",
+		Long:  "Give the user with the given login access to the vault with the given name or id. Only owners can invite. The user needs to run shared list once before being invited",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName, login := args[0], args[1]
+
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			if login == "" {
+				return fmt.Errorf("login is empty")
+			}
+
+			role, err := cmd.Flags().GetString("role")
+			if err != nil {
+				return fmt.Errorf("error getting role flag: %w", err)
+			}
+
+			switch role {
+			case vault.RoleOwner, vault.RoleEditor, vault.RoleViewer:
+			default:
+				return fmt.Errorf("unknown role %q, use one of: %s, %s, %s", role, vault.RoleOwner, vault.RoleEditor, vault.RoleViewer)
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.AddMember(ctxWithToken, vaultName, login, role)
+			if err != nil {
+				switch {
+				case errors.Is(err, vault.ErrUserNotFound):
+					return fmt.Errorf("user %q not found or hasn't set up sharing yet, ask them to run shared list once", login)
+				case errors.Is(err, vault.ErrMemberExists):
+					cmd.Printf("%s is a member of the vault already\n", login)
+
+					return nil
+				case errors.Is(err, vault.ErrPersonalVault):
+					return fmt.Errorf("personal vaults can't have other members, create a team vault instead")
+				default:
+					return vaultError(err)
+				}
+			}
+
+			cmd.Printf("%s added as %s!\n", login, role)
+
+			return nil
+		},
+	}
+
+	invite.Flags().String("role", vault.RoleViewer, "Role of the user in the vault: owner, editor or viewer")
+
+	return invite
+}
+
+func newVaultMembersCommand(container container.Container) *cobra.Command {
+	members := &cobra.Command{
+		Use:   "members <vault>",
+		Short: "List members of vault",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName := args[0]
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			list, err := service.ListMembers(ctxWithToken, vaultName)
+			if err != nil {
+				return vaultError(err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "LOGIN\tROLE\tADDED")
+
+			for _, member := range list {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", member.Login, member.Role, member.AddedAt.Local().Format(time.DateTime))
+			}
+
+			err = w.Flush()
+			if err != nil {
+				return fmt.Errorf("error printing members: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return members
+}
+
+func newVaultRemoveCommand(container container.Container) *cobra.Command {
+	remove := &cobra.Command{
+		Use:   "remove <vault> <login>",
+		Short: "Remove user from vault",
+		Long:  "Remove the user with the given login from the vault with the given name or id. Owners can remove anyone, other members can only leave. Keep in mind the user could have saved the values and the secret of the vault while being a member",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName, login := args[0], args[1]
+
+			if vaultName == "" {
+				return fmt.Errorf("vault is empty")
+			}
+
+			if login == "" {
+				return fmt.Errorf("login is empty")
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			err = service.RemoveMember(ctxWithToken, vaultName, login)
+			if err != nil {
+				switch {
+				case errors.Is(err, vault.ErrMemberNotFound):
+					cmd.Printf("%s isn't a member of the vault\n", login)
+
+					return nil
+				case errors.Is(err, vault.ErrLastOwner):
+					return fmt.Errorf("%s is the last owner of the vault, add another owner first", login)
+				default:
+					return vaultError(err)
+				}
+			}
+
+			cmd.Printf("%s removed!\n", login)
+
+			return nil
+		},
+	}
+
+	return remove
+}
+
+func newVaultUseCommand(container container.Container) *cobra.Command {
+	use := &cobra.Command{
+		Use:   "use [vault]",
+		Short: "Switch active vault",
+		Long:  "Make the vault with the given name or id active, so the other commands work with its values. Without a vault, switch back to your own values",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultName := ""
+			if len(args) > 0 {
+				vaultName = args[0]
+			}
+
+			service, err := container.GetVaultService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting vault service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			active, err := service.UseVault(ctxWithToken, vaultName)
+			if err != nil {
+				return vaultError(err)
+			}
+
+			if vaultName == "" {
+				cmd.Println("Using your own values")
+
+				return nil
+			}
+
+			cmd.Printf("Using vault %q as %s\n", active.Name, active.Role)
+
+			return nil
+		},
+	}
+
+	return use
+}
+
+// vaultError converts the errors of finding the vault and checking the role in it to the user-friendly ones.
+func vaultError(err error) error {
+	switch {
+	case errors.Is(err, vault.ErrVaultNotFound):
+		return fmt.Errorf("vault not found, see vault list command")
+	case errors.Is(err, vault.ErrAmbiguousName):
+		return fmt.Errorf("several vaults with this name, use the id instead, see vault list command")
+	case errors.Is(err, vault.ErrPermissionDenied):
+		return fmt.Errorf("your role in the vault doesn't allow it")
+	default:
+		return fmt.Errorf("vault error: %w", err)
+	}
+}
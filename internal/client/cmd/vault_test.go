@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestVaultCommand := func(container container.Container, args ...string) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newVaultCommand(container)
+		cmd.SetArgs(args)
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	setup := func(t *testing.T) (*mocks.MockContainer, *mocks.MockVaultService, context.Context) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		container := mocks.NewMockContainer(ctrl)
+		vaultService := mocks.NewMockVaultService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetVaultService(ctx).Return(vaultService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil).AnyTimes()
+
+		return container, vaultService, authCtx
+	}
+
+	t.Run("create", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().CreateVault(authCtx, "team", false).Return(vault.Vault{ID: "id", Name: "team"}, nil)
+
+		cmd, out := newTestVaultCommand(container, "create", "team")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `Vault "team" created, id id`)
+	})
+
+	t.Run("create personal", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().CreateVault(authCtx, "work", true).Return(vault.Vault{ID: "id", Name: "work", Personal: true}, nil)
+
+		cmd, _ := newTestVaultCommand(container, "create", "work", "--personal")
+		err := cmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().RenameVault(authCtx, "work", "job").Return(nil)
+
+		cmd, out := newTestVaultCommand(container, "rename", "work", "job")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `Vault renamed to "job"`)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		prompter := mocks.NewMockPrompter(gomock.NewController(t))
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil)
+		prompter.EXPECT().Confirm(ctx, "Are you sure you want to delete vault work?").Return(true)
+		vaultService.EXPECT().DeleteVault(authCtx, "work").Return(nil)
+
+		cmd, out := newTestVaultCommand(container, "delete", "work")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Vault deleted!")
+	})
+
+	t.Run("delete not confirmed", func(t *testing.T) {
+		container, _, _ := setup(t)
+
+		prompter := mocks.NewMockPrompter(gomock.NewController(t))
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil)
+		prompter.EXPECT().Confirm(ctx, "Are you sure you want to delete vault work?").Return(false)
+
+		cmd, _ := newTestVaultCommand(container, "delete", "work")
+		err := cmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("delete vault with values", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		prompter := mocks.NewMockPrompter(gomock.NewController(t))
+		container.EXPECT().GetPrompter(ctx).Return(prompter, nil)
+		prompter.EXPECT().Confirm(ctx, "Are you sure you want to delete vault work?").Return(true)
+		vaultService.EXPECT().DeleteVault(authCtx, "work").Return(vault.ErrVaultNotEmpty)
+
+		cmd, _ := newTestVaultCommand(container, "delete", "work")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "vault still has values")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		vaultService.EXPECT().ListVaults(authCtx).Return([]vault.Vault{
+			{ID: "first", Name: "family", Role: vault.RoleOwner, Personal: true, CreatedAt: createdAt},
+			{ID: "second", Name: "team", Role: vault.RoleViewer, CreatedAt: createdAt},
+		}, nil)
+		vaultService.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{ID: "second"}, true, nil)
+
+		cmd, out := newTestVaultCommand(container, "list")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(t, `\*\s+team\s+team\s+viewer`, out.String())
+		require.Regexp(t, `family\s+personal\s+owner`, out.String())
+		require.NotRegexp(t, `\*\s+family`, out.String())
+	})
+
+	t.Run("invite", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().AddMember(authCtx, "team", "bob", vault.RoleEditor).Return(nil)
+
+		cmd, out := newTestVaultCommand(container, "invite", "team", "bob", "--role", "editor")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "bob added as editor!")
+	})
+
+	t.Run("invite with unknown role", func(t *testing.T) {
+		container, _, _ := setup(t)
+
+		cmd, _ := newTestVaultCommand(container, "invite", "team", "bob", "--role", "admin")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "unknown role")
+	})
+
+	t.Run("invite user without key pair", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().AddMember(authCtx, "team", "bob", vault.RoleViewer).Return(vault.ErrUserNotFound)
+
+		cmd, _ := newTestVaultCommand(container, "invite", "team", "bob")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "hasn't set up sharing yet")
+	})
+
+	t.Run("members", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().ListMembers(authCtx, "team").Return([]vault.Member{
+			{Login: "alice", Role: vault.RoleOwner},
+			{Login: "bob", Role: vault.RoleViewer},
+		}, nil)
+
+		cmd, out := newTestVaultCommand(container, "members", "team")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Regexp(t, `alice\s+owner`, out.String())
+		require.Regexp(t, `bob\s+viewer`, out.String())
+	})
+
+	t.Run("members of unknown vault", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().ListMembers(authCtx, "team").Return(nil, vault.ErrVaultNotFound)
+
+		cmd, _ := newTestVaultCommand(container, "members", "team")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "vault not found")
+	})
+
+	t.Run("remove last owner", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().RemoveMember(authCtx, "team", "alice").Return(vault.ErrLastOwner)
+
+		cmd, _ := newTestVaultCommand(container, "remove", "team", "alice")
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "last owner")
+	})
+
+	t.Run("use", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().UseVault(authCtx, "team").Return(vault.ActiveVault{ID: "id", Name: "team", Role: vault.RoleEditor}, nil)
+
+		cmd, out := newTestVaultCommand(container, "use", "team")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `Using vault "team" as editor`)
+	})
+
+	t.Run("use own values", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().UseVault(authCtx, "").Return(vault.ActiveVault{}, nil)
+
+		cmd, out := newTestVaultCommand(container, "use")
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Using your own values")
+	})
+
+	t.Run("use error", func(t *testing.T) {
+		container, vaultService, authCtx := setup(t)
+
+		vaultService.EXPECT().UseVault(authCtx, "team").Return(vault.ActiveVault{}, errors.New("error"))
+
+		cmd, _ := newTestVaultCommand(container, "use", "team")
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
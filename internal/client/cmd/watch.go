@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+)
+
+func newWatchCommand(container container.Container) *cobra.Command {
+	watch := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch changes made in the cloud",
+		Long:  "Print entries added, updated or deleted in the cloud, e.g. from other devices, as soon as it happens. Press Ctrl+C to stop",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, err := container.GetEntryService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting entry service: %w", err)
+			}
+
+			authService, err := container.GetAuthService(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error getting auth service: %w", err)
+			}
+
+			ctxWithToken, err := authService.AddAuthorizationHeader(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error setting token: %w", err)
+			}
+
+			// stop watching gracefully on Ctrl+C
+			ctxWithToken, stop := signal.NotifyContext(ctxWithToken, os.Interrupt)
+			defer stop()
+
+			cmd.Println("Watching for changes...")
+
+			var cursor int64
+			err = service.WatchChanges(ctxWithToken, func(change entry.Change) {
+				action, name, entryType := describeChange(change)
+				cmd.Printf("%s %s %s %s (version %d)\n", change.ChangedAt.Local().Format(time.DateTime), action, entryType, name, change.Metadata.Version)
+
+				cursor = change.Cursor
+			})
+			if errors.Is(err, entry.ErrWatchDropped) {
+				cmd.Printf("Too many changes at once, run changes --since %d to see the rest\n", cursor)
+
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("error watching changes: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return watch
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/container"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newTestWatchCommand := func(container container.Container) (*cobra.Command, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+
+		cmd := newWatchCommand(container)
+		// gkeep watch
+		cmd.SetArgs([]string{})
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(bytes.NewBuffer(nil))
+		cmd.SetContext(ctx)
+
+		return cmd, out
+	}
+
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("prints changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := mocks.NewMockEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().WatchChanges(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, onChange func(entry.Change)) error {
+			onChange(entry.Change{Metadata: entry.Metadata{Key: "key1", Name: "github", Type: "login", Version: 1}, ChangedAt: changedAt, Cursor: 3})
+			onChange(entry.Change{Metadata: entry.Metadata{Key: "key2", Version: 2}, Deleted: true, ChangedAt: changedAt, Cursor: 4})
+
+			return nil
+		})
+
+		cmd, out := newTestWatchCommand(container)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		outString := out.String()
+		changed := changedAt.Local().Format(time.DateTime)
+		require.Contains(t, outString, changed+" added login github (version 1)")
+		require.Contains(t, outString, changed+" deleted - key2 (version 2)")
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := mocks.NewMockEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().WatchChanges(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, onChange func(entry.Change)) error {
+			onChange(entry.Change{Metadata: entry.Metadata{Key: "key1", Name: "github", Type: "login", Version: 2}, ChangedAt: changedAt, Cursor: 7})
+
+			return entry.ErrWatchDropped
+		})
+
+		cmd, out := newTestWatchCommand(container)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "changes --since 7")
+	})
+
+	t.Run("error watching", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		container := mocks.NewMockContainer(ctrl)
+		entryService := mocks.NewMockEntryService(ctrl)
+		authService := mocks.NewMockAuthService(ctrl)
+
+		container.EXPECT().GetEntryService(ctx).Return(entryService, nil).AnyTimes()
+		container.EXPECT().GetAuthService(ctx).Return(authService, nil).AnyTimes()
+
+		authCtx := context.WithValue(ctx, testCtxKey("test"), "test")
+		authService.EXPECT().AddAuthorizationHeader(ctx).Return(authCtx, nil)
+
+		entryService.EXPECT().WatchChanges(gomock.Any(), gomock.Any()).Return(errors.New("err"))
+
+		cmd, _ := newTestWatchCommand(container)
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
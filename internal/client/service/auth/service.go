@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -104,3 +105,27 @@ func (s *service) AddAuthorizationHeader(ctx context.Context) (context.Context,
 
 	return metadata.NewOutgoingContext(ctx, md), nil
 }
+
+// GetUserID returns the ID of the logged in user. The token is a JWT issued by the server with the user ID as the subject,
+// it isn't verified here, as only the server can do it.
+func (s *service) GetUserID(ctx context.Context) (string, error) {
+	token, ok, err := s.repo.GetToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error getting token: %w", err)
+	}
+	if !ok {
+		return "", fmt.Errorf("token not found")
+	}
+
+	claims := new(jwt.RegisteredClaims)
+	_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil {
+		return "", fmt.Errorf("error parsing token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return "", fmt.Errorf("token has no subject")
+	}
+
+	return claims.Subject, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
 	gomock "go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
@@ -273,3 +274,48 @@ func TestService_AddAuthorizationHeader(t *testing.T) {
 		require.Nil(t, ctxWithToken)
 	})
 }
+
+func TestService_GetUserID(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuthRepository(ctrl)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "user id"}).SignedString([]byte("server secret"))
+		require.NoError(t, err)
+		repo.EXPECT().GetToken(ctx).Return(token, true, nil)
+
+		service := auth.New(mocks.NewMockAuthServiceClient(ctrl), repo)
+		userID, err := service.GetUserID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "user id", userID)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuthRepository(ctrl)
+		repo.EXPECT().GetToken(ctx).Return("", false, nil)
+
+		service := auth.New(mocks.NewMockAuthServiceClient(ctrl), repo)
+		_, err := service.GetUserID(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockAuthRepository(ctrl)
+		repo.EXPECT().GetToken(ctx).Return("token", true, nil)
+
+		service := auth.New(mocks.NewMockAuthServiceClient(ctrl), repo)
+		_, err := service.GetUserID(ctx)
+		require.Error(t, err)
+	})
+}
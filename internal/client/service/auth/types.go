@@ -29,6 +29,9 @@ type Service interface {
 
 	// Logout logs the user out by deleting the stored token.
 	Logout(ctx context.Context) error
+
+	// GetUserID returns the ID of the logged in user, taken from the stored token.
+	GetUserID(ctx context.Context) (string, error)
 }
 
 // Repository defines the interface for token storage operations.
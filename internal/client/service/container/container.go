@@ -17,16 +17,19 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/kuvalkin/gophkeeper/internal/client/service/auth"
 	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/client/service/secret"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
 	keyringStorage "github.com/kuvalkin/gophkeeper/internal/client/storage/keyring"
 	"github.com/kuvalkin/gophkeeper/internal/client/support/crypt"
 	"github.com/kuvalkin/gophkeeper/internal/client/tui/prompts"
 	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
 	authpb "github.com/kuvalkin/gophkeeper/pkg/proto/auth/v1"
 	entypb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+	vaultpb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
 )
 
 // Container defines the interface for the application's service container.
@@ -37,8 +40,10 @@ type Container interface {
 	GetSecretService(ctx context.Context) (secret.Service, error)
 	// GetAuthService retrieves the authentication service, which handles user authentication.
 	GetAuthService(ctx context.Context) (auth.Service, error)
-	// GetEntryService retrieves the entry service, which manages data entries.
+	// GetEntryService retrieves the entry service, which manages data entries of the active vault, or the user's own ones.
 	GetEntryService(ctx context.Context) (entry.Service, error)
+	// GetVaultService retrieves the vault service, which manages team vaults and the active one.
+	GetVaultService(ctx context.Context) (vault.Service, error)
 	// GetPrompter retrieves the prompter, which handles user interactions via the terminal.
 	GetPrompter(ctx context.Context) (prompts.Prompter, error)
 }
@@ -56,20 +61,38 @@ func New(conf *viper.Viper) (Container, error) {
 type container struct {
 	conf *viper.Viper
 
+	// the initialization errors are kept along with the values, so every call returns the error, not only the first one
+
 	initConnection sync.Once
 	connection     *grpc.ClientConn
+	connectionErr  error
 
 	initSecretService sync.Once
 	secretService     secret.Service
 
 	initAuthService sync.Once
 	authService     auth.Service
+	authServiceErr  error
 
 	initEntryService sync.Once
 	entryService     entry.Service
+	entryServiceErr  error
+
+	initPersonalEntryService sync.Once
+	personalEntryService     entry.Service
+	personalEntryServiceErr  error
+
+	initVaultService sync.Once
+	vaultService     vault.Service
+	vaultServiceErr  error
 
 	initCrypter sync.Once
 	crypter     *crypt.AgeCrypter
+	crypterErr  error
+
+	initBlobRepository sync.Once
+	blobRepository     blob.Repository
+	blobRepositoryErr  error
 
 	initPrompter sync.Once
 	prompter     prompts.Prompter
@@ -121,75 +144,179 @@ func (c *container) GetSecretService(_ context.Context) (secret.Service, error)
 }
 
 // GetEntryService initializes and retrieves the entry service, which manages data entries.
-// It sets up a gRPC connection, a crypter for encryption, and a temporary directory for file storage.
+// If a vault is active, the entries of the vault are managed, encrypted with its secret, otherwise the user's own ones.
 func (c *container) GetEntryService(ctx context.Context) (entry.Service, error) {
-	var outErr error //todo will there be error on second pass?
-
 	c.initEntryService.Do(func() {
-		conn, err := c.getConnection()
-		if err != nil {
-			outErr = fmt.Errorf("cant get grpc connection: %w", err)
-			return
-		}
+		c.entryService, c.entryServiceErr = c.newEntryService(ctx)
+	})
 
-		crypter, err := c.getCrypter(ctx)
-		if err != nil {
-			outErr = fmt.Errorf("cant create crypter: %w", err)
-			return
-		}
+	return c.entryService, c.entryServiceErr
+}
 
-		c.tempDir, err = os.MkdirTemp("", "gophkeeper-*")
-		if err != nil {
-			outErr = fmt.Errorf("cant create temp dir: %w", err)
-			return
-		}
+func (c *container) newEntryService(ctx context.Context) (entry.Service, error) {
+	vs, err := c.GetVaultService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get vault service: %w", err)
+	}
 
-		br, err := blob.NewFileBlobRepository(c.tempDir)
-		if err != nil {
-			outErr = fmt.Errorf("cant create blob repository: %w", err)
-			return
-		}
+	active, ok, err := vs.GetActiveVault(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get active vault: %w", err)
+	}
 
-		c.entryService = entry.New(
-			crypter,
-			entypb.NewEntryServiceClient(conn),
-			br,
-			c.conf.GetInt64("stream.chunk_size"),
-		)
+	if !ok {
+		return c.getPersonalEntryService(ctx)
+	}
+
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, fmt.Errorf("cant get grpc connection: %w", err)
+	}
+
+	crypter, err := crypt.NewAgeCrypter(active.Secret, active.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cant create vault crypter: %w", err)
+	}
+
+	br, err := c.getBlobRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.New(
+		crypter,
+		entypb.NewEntryServiceClient(&vaultConn{ClientConnInterface: conn, vaultID: active.ID}),
+		br,
+		c.conf.GetInt64("stream.chunk_size"),
+	), nil
+}
+
+// GetVaultService initializes and retrieves the vault service, which manages team vaults
+// using a gRPC connection, the user's own key pair and a keyring-based storage repository for the active vault.
+func (c *container) GetVaultService(ctx context.Context) (vault.Service, error) {
+	c.initVaultService.Do(func() {
+		c.vaultService, c.vaultServiceErr = c.newVaultService(ctx)
+	})
+
+	return c.vaultService, c.vaultServiceErr
+}
+
+func (c *container) newVaultService(ctx context.Context) (vault.Service, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, fmt.Errorf("cant get grpc connection: %w", err)
+	}
+
+	personal, err := c.getPersonalEntryService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	crypter, err := c.getCrypter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant create crypter: %w", err)
+	}
+
+	secrets, err := c.GetSecretService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get secret service: %w", err)
+	}
+
+	return vault.New(
+		vaultpb.NewVaultServiceClient(conn),
+		personal,
+		crypter,
+		secrets,
+		keyringStorage.NewRepository(),
+	), nil
+}
+
+// getPersonalEntryService initializes and retrieves the entry service managing the user's own entries,
+// encrypted with the user's secret, regardless of the active vault.
+func (c *container) getPersonalEntryService(ctx context.Context) (entry.Service, error) {
+	c.initPersonalEntryService.Do(func() {
+		c.personalEntryService, c.personalEntryServiceErr = c.newPersonalEntryService(ctx)
+	})
+
+	return c.personalEntryService, c.personalEntryServiceErr
+}
+
+func (c *container) newPersonalEntryService(ctx context.Context) (entry.Service, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, fmt.Errorf("cant get grpc connection: %w", err)
+	}
+
+	crypter, err := c.getCrypter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant create crypter: %w", err)
+	}
+
+	br, err := c.getBlobRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.New(
+		crypter,
+		entypb.NewEntryServiceClient(conn),
+		br,
+		c.conf.GetInt64("stream.chunk_size"),
+	), nil
+}
+
+// getBlobRepository creates a temporary directory for file storage and a blob repository in it, shared by the entry services.
+func (c *container) getBlobRepository() (blob.Repository, error) {
+	c.initBlobRepository.Do(func() {
+		c.blobRepository, c.blobRepositoryErr = c.newBlobRepository()
 	})
 
-	return c.entryService, outErr
+	return c.blobRepository, c.blobRepositoryErr
+}
+
+func (c *container) newBlobRepository() (blob.Repository, error) {
+	var err error
+	c.tempDir, err = os.MkdirTemp("", "gophkeeper-*")
+	if err != nil {
+		return nil, fmt.Errorf("cant create temp dir: %w", err)
+	}
+
+	br, err := blob.NewFileBlobRepository(c.tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("cant create blob repository: %w", err)
+	}
+
+	return br, nil
 }
 
 // GetAuthService initializes and retrieves the authentication service, which handles user authentication
 // using a gRPC connection and a keyring-based storage repository.
 func (c *container) GetAuthService(_ context.Context) (auth.Service, error) {
-	var outErr error
-
 	c.initAuthService.Do(func() {
-		conn, err := c.getConnection()
-		if err != nil {
-			outErr = fmt.Errorf("cant get grpc connection: %w", err)
-			return
-		}
-
-		c.authService = auth.New(
-			authpb.NewAuthServiceClient(conn),
-			keyringStorage.NewRepository(),
-		)
+		c.authService, c.authServiceErr = c.newAuthService()
 	})
 
-	return c.authService, outErr
+	return c.authService, c.authServiceErr
 }
 
-func (c *container) getConnection() (*grpc.ClientConn, error) {
-	var err error
+func (c *container) newAuthService() (auth.Service, error) {
+	conn, err := c.getConnection()
+	if err != nil {
+		return nil, fmt.Errorf("cant get grpc connection: %w", err)
+	}
 
+	return auth.New(
+		authpb.NewAuthServiceClient(conn),
+		keyringStorage.NewRepository(),
+	), nil
+}
+
+func (c *container) getConnection() (*grpc.ClientConn, error) {
 	c.initConnection.Do(func() {
-		c.connection, err = c.newConnection()
+		c.connection, c.connectionErr = c.newConnection()
 	})
 
-	return c.connection, err
+	return c.connection, c.connectionErr
 }
 
 func (c *container) newConnection() (*grpc.ClientConn, error) {
@@ -212,32 +339,62 @@ func (c *container) newConnection() (*grpc.ClientConn, error) {
 }
 
 func (c *container) getCrypter(ctx context.Context) (*crypt.AgeCrypter, error) {
-	var outErr error
-
 	c.initCrypter.Do(func() {
-		ss, err := c.GetSecretService(ctx)
-		if err != nil {
-			outErr = fmt.Errorf("cant get secret service: %w", err)
-			return
-		}
+		c.crypter, c.crypterErr = c.newCrypter(ctx)
+	})
 
-		s, ok, err := ss.GetSecret(ctx)
-		if err != nil {
-			outErr = fmt.Errorf("cant get secret from keyring: %w", err)
-			return
-		}
+	return c.crypter, c.crypterErr
+}
 
-		if !ok {
-			outErr = errors.New("secret not found")
-			return
-		}
+func (c *container) newCrypter(ctx context.Context) (*crypt.AgeCrypter, error) {
+	ss, err := c.GetSecretService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get secret service: %w", err)
+	}
 
-		c.crypter, err = crypt.NewAgeCrypter(s)
-		if err != nil {
-			outErr = fmt.Errorf("cant create crypter: %w", err)
-			return
-		}
-	})
+	s, ok, err := ss.GetSecret(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get secret from keyring: %w", err)
+	}
+
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+
+	as, err := c.GetAuthService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get auth service: %w", err)
+	}
+
+	// the own entries are the store of the user, emergency contacts see them as a vault with the user's ID
+	userID, err := as.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get user id: %w", err)
+	}
+
+	crypter, err := crypt.NewAgeCrypter(s, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cant create crypter: %w", err)
+	}
+
+	return crypter, nil
+}
+
+// vaultConn adds the ID of the active vault to the metadata of every call made through the connection,
+// so the server manages the entries of the vault instead of the user's own ones.
+type vaultConn struct {
+	grpc.ClientConnInterface
+	vaultID string
+}
+
+func (v *vaultConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	return v.ClientConnInterface.Invoke(v.withVaultID(ctx), method, args, reply, opts...)
+}
+
+func (v *vaultConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return v.ClientConnInterface.NewStream(v.withVaultID(ctx), desc, method, opts...)
+}
 
-	return c.crypter, outErr
+func (v *vaultConn) withVaultID(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-vault-id", v.vaultID)
 }
@@ -0,0 +1,36 @@
+package entry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// envelope holds the metadata the server must not learn, it's sent to the server encrypted.
+type envelope struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (s *service) encryptEnvelope(name string, entryType string) ([]byte, error) {
+	plain, err := json.Marshal(envelope{Name: name, Type: entryType})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal envelope: %w", err)
+	}
+
+	return s.encryptBytes(plain)
+}
+
+func (s *service) decryptEnvelope(encrypted []byte) (string, string, error) {
+	plain, err := s.decryptBytes(encrypted)
+	if err != nil {
+		return "", "", err
+	}
+
+	var env envelope
+	err = json.Unmarshal(plain, &env)
+	if err != nil {
+		return "", "", fmt.Errorf("could not unmarshal envelope: %w", err)
+	}
+
+	return env.Name, env.Type, nil
+}
@@ -3,9 +3,13 @@ package entry
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -44,101 +48,115 @@ type service struct {
 	log       *zap.SugaredLogger
 }
 
-// SetEntry creates or updates an entry with the given key, name, notes, and content.
-// It encrypts the content and uploads it to the server. If the entry already exists,
-// the onOverwrite callback determines whether to overwrite it.
-func (s *service) SetEntry(ctx context.Context, key string, name string, notes string, content io.ReadCloser, onOverwrite func() bool) error {
-	llog := s.log.WithLazy("key", key, "name", name)
+// EntryKey returns the hex encoded MAC of the type and name, separated so different pairs can't produce the same input.
+func (s *service) EntryKey(entryType string, name string) string {
+	return hex.EncodeToString(s.crypt.MAC([]byte(entryType + "\x00" + name)))
+}
+
+// SetEntry creates or updates an entry with the key, name, type and notes from the metadata, and the given content.
+// It encrypts the content and uploads it to the server in an upload session, resuming the upload if it's interrupted.
+// If the entry already exists, the onOverwrite callback determines whether to overwrite it.
+// Timestamps and size in the metadata are ignored, they are set by the server.
+// A non-zero version is sent as the expected one, so the server rejects the write if the entry was changed since.
+func (s *service) SetEntry(ctx context.Context, md Metadata, content io.ReadCloser, onOverwrite func() bool) error {
+	key := md.Key
+	llog := s.log.WithLazy("key", key, "name", md.Name, "type", md.Type)
 
 	llog.Debug("encrypting entry content")
-	err := s.encryptBlob(ctx, content, key)
+	err := s.encryptBlob(ctx, content, key, s.crypt.Encrypt)
 	if err != nil {
 		return fmt.Errorf("error encrypting entry: %w", err)
 	}
 
 	var encNotes []byte
-	if notes != "" {
+	if md.Notes != "" {
 		llog.Debug("encrypting notes")
-		encNotes, err = s.encryptNotes(notes)
+		encNotes, err = s.encryptBytes([]byte(md.Notes))
 		if err != nil {
 			return fmt.Errorf("error encrypting notes: %w", err)
 		}
 	}
 
-	reader, ok, err := s.blobRepo.OpenBlobReader(key)
+	llog.Debug("encrypting envelope")
+	env, err := s.encryptEnvelope(md.Name, md.Type)
 	if err != nil {
-		return fmt.Errorf("error initializing reader for the encrypted blob: %w", err)
-	}
-	if !ok {
-		return errors.New("encrypted blob that we've just written wasn't found")
+		return fmt.Errorf("error encrypting envelope: %w", err)
 	}
-	defer utils.CloseAndLogError(reader, llog)
 
-	llog.Debug("opening grpc steam")
-	stream, err := s.client.SetEntry(ctx)
+	size, ok, err := s.blobRepo.GetBlobSize(ctx, key)
 	if err != nil {
-		return fmt.Errorf("cant start streaming encrypted blob to server: %w", err)
+		return fmt.Errorf("error getting size of the encrypted blob: %w", err)
+	}
+	if !ok {
+		return errors.New("encrypted blob that we've just written wasn't found")
 	}
-	defer func() {
-		err = stream.CloseSend()
-		if err != nil {
-			llog.Errorw("error closing stream", "err", err)
-		}
-	}()
 
-	llog.Debug("sending metadata")
-	err = s.sendMetadata(stream, &pb.Entry{
-		Key:   key,
-		Name:  name,
-		Notes: encNotes,
+	llog.Debug("creating upload session")
+	session, err := s.createUploadSession(ctx, &pb.CreateUploadSessionRequest{
+		Entry: &pb.Entry{
+			Key:      key,
+			Notes:    encNotes,
+			Envelope: env,
+		},
+		Size:            size,
+		ExpectedVersion: md.Version,
 	}, onOverwrite)
 	if err != nil {
-		return fmt.Errorf("error sending metadata to the server: %w", err)
+		return err
 	}
 
+	llog = llog.WithLazy("uploadID", session.UploadId)
+
 	llog.Debug("uploading encrypted content")
-	err = s.uploadBlob(ctx, reader, stream)
+	err = s.uploadBlob(ctx, key, session, llog)
 	if err != nil {
 		return fmt.Errorf("error uploading encrypted blob to server: %w", err)
 	}
 
-	llog.Debug("upload done")
-	err = stream.CloseSend()
+	llog.Debug("finalizing upload")
+	_, err = s.client.FinalizeUpload(ctx, &pb.FinalizeUploadRequest{UploadId: session.UploadId})
 	if err != nil {
-		return fmt.Errorf("error finishing upload process: %w", err)
-	}
+		if isConflict(err) {
+			return ErrConflict
+		}
 
-	llog.Debug("getting server acknowledgement")
-	_, err = stream.Recv()
-	if err != nil && !errors.Is(err, io.EOF) {
-		return fmt.Errorf("error getting acknowledgement from server: %w", err)
+		return fmt.Errorf("error finalizing upload: %w", err)
 	}
 
 	return nil
 }
 
-func (s *service) encryptNotes(notes string) (b []byte, err error) {
+// encryptFunc creates a writer that encrypts data written to it to the destination.
+type encryptFunc func(dst io.Writer) (io.WriteCloser, error)
+
+func (s *service) encryptBytes(plain []byte) ([]byte, error) {
+	return encryptBytesWith(plain, s.crypt.Encrypt)
+}
+
+func encryptBytesWith(plain []byte, encrypt encryptFunc) ([]byte, error) {
 	var buf bytes.Buffer
-	encryptWriter, err := s.crypt.Encrypt(&buf)
+	encryptWriter, err := encrypt(&buf)
 	if err != nil {
 		return nil, fmt.Errorf("could not create encrypt writer: %w", err)
 	}
-	defer func() {
-		closeErr := encryptWriter.Close()
-		if err == nil && closeErr != nil {
-			err = fmt.Errorf("error closing encrypt writer: %w", err)
-		}
-	}()
 
-	_, err = io.WriteString(encryptWriter, notes)
+	_, err = encryptWriter.Write(plain)
 	if err != nil {
-		return nil, fmt.Errorf("could not write notes to encrypt writer: %w", err)
+		_ = encryptWriter.Close()
+
+		return nil, fmt.Errorf("could not write to encrypt writer: %w", err)
+	}
+
+	// the writer flushes the last chunk on close, so the buffer is complete only after it
+	err = encryptWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing encrypt writer: %w", err)
 	}
 
 	return buf.Bytes(), nil
 }
 
-func (s *service) encryptBlob(ctx context.Context, content io.ReadCloser, key string) (err error) {
+func (s *service) encryptBlob(ctx context.Context, content io.ReadCloser, key string, encrypt encryptFunc) (err error) {
 	defer func() {
 		closeErr := content.Close()
 		if err == nil && closeErr != nil {
@@ -146,7 +164,7 @@ func (s *service) encryptBlob(ctx context.Context, content io.ReadCloser, key st
 		}
 	}()
 
-	dst, err := s.blobRepo.OpenBlobWriter(key)
+	dst, err := s.blobRepo.OpenBlobWriter(ctx, key)
 	if err != nil {
 		return fmt.Errorf("cant create blob to store entry: %w", err)
 	}
@@ -157,7 +175,7 @@ func (s *service) encryptBlob(ctx context.Context, content io.ReadCloser, key st
 		}
 	}()
 
-	encrypter, err := s.crypt.Encrypt(dst)
+	encrypter, err := encrypt(dst)
 	if err != nil {
 		return fmt.Errorf("cant start encrypting entry: %w", err)
 	}
@@ -177,85 +195,231 @@ func (s *service) encryptBlob(ctx context.Context, content io.ReadCloser, key st
 	return nil
 }
 
-func (s *service) sendMetadata(stream grpc.BidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse], entry *pb.Entry, onOverwrite func() bool) error {
-	err := stream.Send(&pb.SetEntryRequest{
-		Entry: entry,
-	})
-	if err != nil {
-		return fmt.Errorf("error sending initial request: %w", err)
+// createUploadSession creates an upload session on the server.
+// If the entry already exists, the onOverwrite callback determines whether to overwrite it.
+func (s *service) createUploadSession(ctx context.Context, request *pb.CreateUploadSessionRequest, onOverwrite func() bool) (*pb.UploadSession, error) {
+	session, err := s.client.CreateUploadSession(ctx, request)
+	if err == nil {
+		return session, nil
 	}
 
-	resp, err := stream.Recv()
-	if err != nil {
-		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.AlreadyExists {
-			// errors means that stream is closed, no sense in continuing
-			return ErrEntryExists
-		}
+	if isConflict(err) {
+		return nil, ErrConflict
+	}
 
-		return fmt.Errorf("error receiving response: %w", err)
+	if isQuotaExceeded(err) {
+		return nil, ErrQuotaExceeded
 	}
 
-	if !resp.AlreadyExists {
-		return nil
+	if stErr, ok := status.FromError(err); !ok || stErr.Code() != codes.AlreadyExists {
+		return nil, fmt.Errorf("error creating upload session: %w", err)
 	}
 
 	if onOverwrite == nil || !onOverwrite() {
-		return ErrEntryExists
+		return nil, ErrEntryExists
 	}
 
-	err = stream.Send(&pb.SetEntryRequest{
-		Overwrite: true,
-	})
+	request.Overwrite = true
+
+	session, err = s.client.CreateUploadSession(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error sending overwrite signal to the server: %w", err)
+		if isConflict(err) {
+			return nil, ErrConflict
+		}
+
+		if isQuotaExceeded(err) {
+			return nil, ErrQuotaExceeded
+		}
+
+		return nil, fmt.Errorf("error creating upload session with overwrite: %w", err)
 	}
 
-	return nil
+	return session, nil
 }
 
-func (s *service) uploadBlob(
-	ctx context.Context,
-	blob io.Reader,
-	stream grpc.BidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse],
-) error {
-	buffer := make([]byte, s.chunkSize)
+// maxResumeRetries is the number of times in a row an interrupted upload or download is resumed without any progress before giving up.
+const maxResumeRetries = 3
 
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("uploading was interrupted: %w", ctx.Err())
-		default:
-			n, err := blob.Read(buffer)
+// resumeRetryDelay is multiplied by the number of retries without progress to get the delay before resuming.
+const resumeRetryDelay = time.Second
 
-			if errors.Is(err, io.EOF) {
-				return nil
+// uploadBlob uploads the blob to the upload session. If the upload is interrupted,
+// it's resumed from the offset committed by the server, so already uploaded chunks aren't sent again.
+func (s *service) uploadBlob(ctx context.Context, key string, session *pb.UploadSession, llog *zap.SugaredLogger) error {
+	offset := session.CommittedOffset
+	retries := 0
+
+	for offset < session.Size {
+		committed, err := s.uploadChunks(ctx, key, session.UploadId, offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("uploading was interrupted: %w", ctx.Err())
 			}
 
-			if err != nil {
-				return fmt.Errorf("error reading encrypted blob chunk: %w", err)
+			llog.Warnw("upload interrupted", "offset", offset, "err", err)
+
+			current, getErr := s.client.GetUploadSession(ctx, &pb.GetUploadSessionRequest{UploadId: session.UploadId})
+			if getErr != nil {
+				return fmt.Errorf("cant resume upload interrupted with %v: %w", err, getErr)
 			}
 
-			err = stream.Send(&pb.SetEntryRequest{
-				Entry: &pb.Entry{
-					Content: buffer[:n],
-				},
-			})
-			if err != nil {
-				return fmt.Errorf("error sending encrypted blob chunk to server: %w", err)
+			committed = current.CommittedOffset
+		}
+
+		if committed > offset {
+			retries = 0
+		} else {
+			retries++
+			if retries > maxResumeRetries {
+				if err != nil {
+					return fmt.Errorf("upload doesn't progress: %w", err)
+				}
+
+				return errors.New("upload doesn't progress")
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("uploading was interrupted: %w", ctx.Err())
+			case <-time.After(time.Duration(retries) * resumeRetryDelay):
 			}
 		}
+
+		llog.Debugw("resuming upload", "offset", committed)
+		offset = committed
+	}
+
+	return nil
+}
+
+// uploadChunks sends the blob from the offset to the upload session in one stream.
+// It returns the offset committed by the server.
+func (s *service) uploadChunks(ctx context.Context, key string, uploadID string, offset int64) (int64, error) {
+	blob, ok, err := s.blobRepo.OpenBlobReader(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("error initializing reader for the encrypted blob: %w", err)
+	}
+	if !ok {
+		return 0, errors.New("encrypted blob wasn't found")
+	}
+	defer utils.CloseAndLogError(blob, s.log)
+
+	err = skip(blob, offset)
+	if err != nil {
+		return 0, fmt.Errorf("error skipping uploaded part of the blob: %w", err)
+	}
+
+	stream, err := s.client.UploadChunks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("cant start streaming encrypted blob to server: %w", err)
+	}
+
+	err = stream.Send(&pb.UploadChunkRequest{
+		UploadId: uploadID,
+		Offset:   offset,
+	})
+	if err != nil {
+		return 0, streamError(stream, fmt.Errorf("error sending upload id: %w", err))
+	}
+
+	buffer := make([]byte, s.chunkSize)
+
+	for {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("uploading was interrupted: %w", ctx.Err())
+		}
+
+		n, err := blob.Read(buffer)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error reading encrypted blob chunk: %w", err)
+		}
+
+		err = stream.Send(&pb.UploadChunkRequest{
+			Content: buffer[:n],
+		})
+		if err != nil {
+			return 0, streamError(stream, fmt.Errorf("error sending encrypted blob chunk to server: %w", err))
+		}
+	}
+
+	session, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, fmt.Errorf("error getting committed offset: %w", err)
+	}
+
+	return session.CommittedOffset, nil
+}
+
+// streamError returns the status the server ended the stream with if sending failed because of it, or err otherwise.
+func streamError[Req any, Res any](stream grpc.ClientStreamingClient[Req, Res], err error) error {
+	if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	_, recvErr := stream.CloseAndRecv()
+	if recvErr != nil {
+		return fmt.Errorf("server ended the upload: %w", recvErr)
+	}
+
+	return err
+}
+
+// skip skips the first n bytes of the reader, seeking if possible.
+func skip(r io.Reader, n int64) error {
+	if n == 0 {
+		return nil
 	}
+
+	if seeker, ok := r.(io.Seeker); ok {
+		_, err := seeker.Seek(n, io.SeekStart)
+
+		return err
+	}
+
+	_, err := io.CopyN(io.Discard, r, n)
+
+	return err
 }
 
-// GetEntry retrieves an entry by its key. It decrypts the notes and content and returns them.
+// GetEntry retrieves an entry by its key. It decrypts the notes and content and returns them along with the rest of the metadata.
+// If the download is interrupted, it's resumed from the already received part of the content.
+// ErrConflict is returned if the entry was changed on the server before the download could be resumed.
 // The boolean indicates whether the entry exists, and an error is returned if any issues occur.
-func (s *service) GetEntry(ctx context.Context, key string) (string, io.ReadCloser, bool, error) {
+func (s *service) GetEntry(ctx context.Context, key string) (Metadata, io.ReadCloser, bool, error) {
 	stream, err := s.client.GetEntry(ctx, &pb.GetEntryRequest{Key: key})
 	if err != nil {
-		return "", nil, false, fmt.Errorf("cant start downloading entry: %w", err)
+		return Metadata{}, nil, false, fmt.Errorf("cant start downloading entry: %w", err)
 	}
+
+	resume := func(offset int64) (grpc.ServerStreamingClient[pb.Entry], error) {
+		return s.client.GetEntry(ctx, &pb.GetEntryRequest{Key: key, Offset: offset})
+	}
+
+	return s.receiveEntry(ctx, key, stream, resume)
+}
+
+// GetVersion retrieves a previous version of an entry, the same way as GetEntry does, but without resuming the download.
+func (s *service) GetVersion(ctx context.Context, key string, version int64) (Metadata, io.ReadCloser, bool, error) {
+	stream, err := s.client.GetEntryVersion(ctx, &pb.GetEntryVersionRequest{Key: key, Version: version})
+	if err != nil {
+		return Metadata{}, nil, false, fmt.Errorf("cant start downloading version: %w", err)
+	}
+
+	return s.receiveEntry(ctx, key, stream, nil)
+}
+
+// resumeFunc requests the content of the entry again, starting from the offset.
+type resumeFunc func(offset int64) (grpc.ServerStreamingClient[pb.Entry], error)
+
+// receiveEntry reads the metadata and then the content of the entry from the stream.
+// The content is stored to the temporary blob and decrypted on read.
+// If resume isn't nil, it's used to continue an interrupted download.
+func (s *service) receiveEntry(ctx context.Context, key string, stream grpc.ServerStreamingClient[pb.Entry], resume resumeFunc) (Metadata, io.ReadCloser, bool, error) {
 	defer func() {
-		err = stream.CloseSend()
+		err := stream.CloseSend()
 		if err != nil {
 			s.log.Errorw("error closing stream", "err", err)
 		}
@@ -264,75 +428,183 @@ func (s *service) GetEntry(ctx context.Context, key string) (string, io.ReadClos
 	resp, err := stream.Recv()
 	if err != nil {
 		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
-			return "", nil, false, nil
+			return Metadata{}, nil, false, nil
 		}
 
-		return "", nil, false, fmt.Errorf("error getting metadata: %w", err)
+		return Metadata{}, nil, false, fmt.Errorf("error getting metadata: %w", err)
 	}
 
-	var notes string
-	if resp.Notes != nil {
-		notes, err = s.decryptNotes(resp.Notes)
-		if err != nil {
-			return "", nil, false, fmt.Errorf("error decrypting notes: %w", err)
-		}
+	md, err := s.toMetadata(resp)
+	if err != nil {
+		return Metadata{}, nil, false, err
 	}
 
-	content, err := s.downloadBlob(key, stream)
+	content, err := s.downloadBlob(ctx, key, md, stream, resume)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("error downloading entry: %w", err)
+		return Metadata{}, nil, false, fmt.Errorf("error downloading entry: %w", err)
 	}
 
 	decr, err := s.crypt.Decrypt(content)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("could not create decrypt reader: %w", err)
+		return Metadata{}, nil, false, fmt.Errorf("could not create decrypt reader: %w", err)
+	}
+
+	return md, &combinedRC{reader: decr, closer: content}, true, nil
+}
+
+// toMetadata converts the entry received from the server to metadata, decrypting the envelope and the notes.
+// Entries stored before envelopes were introduced have the name and type in plain text.
+func (s *service) toMetadata(e *pb.Entry) (Metadata, error) {
+	md := Metadata{
+		Key:      e.Key,
+		Name:     e.Name,
+		Type:     e.Type,
+		Size:     e.Size,
+		Version:  e.Version,
+		Checksum: e.Checksum,
+	}
+
+	if e.CreatedAt != nil {
+		md.CreatedAt = e.CreatedAt.AsTime()
+	}
+
+	if e.UpdatedAt != nil {
+		md.UpdatedAt = e.UpdatedAt.AsTime()
+	}
+
+	if e.Envelope != nil {
+		var err error
+		md.Name, md.Type, err = s.decryptEnvelope(e.Envelope)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("error decrypting envelope of %s: %w", e.Key, err)
+		}
 	}
 
-	return notes, &combinedRC{reader: decr, closer: content}, true, nil
+	if e.Notes != nil {
+		notes, err := s.decryptBytes(e.Notes)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("error decrypting notes of %s: %w", md.Name, err)
+		}
+
+		md.Notes = string(notes)
+	}
+
+	return md, nil
 }
 
-func (s *service) decryptNotes(encNotes []byte) (string, error) {
-	dec, err := s.crypt.Decrypt(bytes.NewReader(encNotes))
+func (s *service) decryptBytes(encrypted []byte) ([]byte, error) {
+	dec, err := s.crypt.Decrypt(bytes.NewReader(encrypted))
 	if err != nil {
-		return "", fmt.Errorf("could not create decrypt reader: %w", err)
+		return nil, fmt.Errorf("could not create decrypt reader: %w", err)
 	}
 
-	notes, err := io.ReadAll(dec)
+	plain, err := io.ReadAll(dec)
 	if err != nil {
-		return "", fmt.Errorf("could not read decrypted notes: %w", err)
+		return nil, fmt.Errorf("could not read decrypted data: %w", err)
 	}
 
-	return string(notes), nil
+	return plain, nil
 }
 
-func (s *service) downloadBlob(key string, stream grpc.ServerStreamingClient[pb.Entry]) (io.ReadCloser, error) {
-	dst, err := s.blobRepo.OpenBlobWriter(key)
+// downloadBlob writes the content received from the stream to the temporary blob.
+// If the stream is interrupted and resume isn't nil, the rest of the content is requested again,
+// as long as the entry still has the same version.
+// The content is verified against the checksum from the metadata, ErrChecksumMismatch is returned if it doesn't match.
+func (s *service) downloadBlob(ctx context.Context, key string, md Metadata, stream grpc.ServerStreamingClient[pb.Entry], resume resumeFunc) (io.ReadCloser, error) {
+	llog := s.log.WithLazy("key", key, "version", md.Version, "method", "downloadBlob")
+
+	dst, err := s.blobRepo.OpenBlobWriter(ctx, key)
 	if err != nil {
 		return nil, fmt.Errorf("cant create blob to temporary store entry: %w", err)
 	}
-	defer utils.CloseAndLogError(dst, s.log)
+
+	// incomplete or corrupted content is never kept
+	completed := false
+	defer func() {
+		if !completed {
+			s.discardBlob(ctx, dst, key)
+		}
+	}()
+
+	var written, resumedAt int64
+	retries := 0
+	hash := sha256.New()
 
 	for {
-		response, err := stream.Recv()
+		if stream == nil {
+			if written > resumedAt {
+				retries = 0
+			} else {
+				retries++
+				if retries > maxResumeRetries {
+					return nil, fmt.Errorf("download doesn't progress: %w", err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil, fmt.Errorf("downloading was interrupted: %w", ctx.Err())
+				case <-time.After(time.Duration(retries) * resumeRetryDelay):
+				}
+			}
+
+			llog.Debugw("resuming download", "offset", written)
+			resumedAt = written
+
+			stream, err = s.resumeDownload(md.Version, written, resume)
+			if errors.Is(err, ErrConflict) {
+				return nil, err
+			}
+			if err != nil {
+				llog.Warnw("cant resume download", "offset", written, "err", err)
+
+				continue
+			}
+		}
+
+		var response *pb.Entry
+		response, err = stream.Recv()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error reading entry: %w", err)
+			if resume == nil {
+				return nil, fmt.Errorf("error reading entry: %w", err)
+			}
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("downloading was interrupted: %w", ctx.Err())
+			}
+
+			llog.Warnw("download interrupted", "offset", written, "err", err)
+			stream = nil
+
+			continue
 		}
 
-		_, err = dst.Write(response.Content)
-		if err != nil {
-			return nil, fmt.Errorf("error writing entry: %w", err)
+		n, writeErr := dst.Write(response.Content)
+		if writeErr != nil {
+			return nil, fmt.Errorf("error writing entry: %w", writeErr)
 		}
+
+		written += int64(n)
+		// hash.Hash never returns an error
+		hash.Write(response.Content[:n])
 	}
 
+	// entries stored before checksums were introduced don't have one
+	if len(md.Checksum) > 0 && !bytes.Equal(hash.Sum(nil), md.Checksum) {
+		llog.Errorw("downloaded content is corrupted", "size", written)
+
+		return nil, ErrChecksumMismatch
+	}
+
+	completed = true
+
 	err = dst.Close()
 	if err != nil {
 		return nil, fmt.Errorf("error closing temporary blob: %w", err)
 	}
 
-	reader, exists, err := s.blobRepo.OpenBlobReader(key)
+	reader, exists, err := s.blobRepo.OpenBlobReader(ctx, key)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing reader for the encrypted blob: %w", err)
 	}
@@ -344,15 +616,379 @@ func (s *service) downloadBlob(key string, stream grpc.ServerStreamingClient[pb.
 }
 
 // DeleteEntry removes an entry by its key. Returns an error if the deletion fails.
-func (s *service) DeleteEntry(ctx context.Context, name string) error {
-	_, err := s.client.DeleteEntry(ctx, &pb.DeleteEntryRequest{Key: name})
+// A non-zero expected version makes the server reject the deletion if the entry was changed since.
+func (s *service) DeleteEntry(ctx context.Context, name string, expectedVersion int64) error {
+	_, err := s.client.DeleteEntry(ctx, &pb.DeleteEntryRequest{Key: name, ExpectedVersion: expectedVersion})
 	if err != nil {
+		if isConflict(err) {
+			return ErrConflict
+		}
+
 		return fmt.Errorf("cant delete entry: %w", err)
 	}
 
 	return nil
 }
 
+// RenameEntry moves an entry to the new key on the server, sending the new name and type in an encrypted envelope.
+func (s *service) RenameEntry(ctx context.Context, key string, md Metadata) error {
+	env, err := s.encryptEnvelope(md.Name, md.Type)
+	if err != nil {
+		return fmt.Errorf("error encrypting envelope: %w", err)
+	}
+
+	_, err = s.client.RenameEntry(ctx, &pb.RenameEntryRequest{
+		Key:             key,
+		NewKey:          md.Key,
+		NewEnvelope:     env,
+		ExpectedVersion: md.Version,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return ErrEntryNotFound
+		case codes.AlreadyExists:
+			return ErrEntryExists
+		case codes.FailedPrecondition:
+			return ErrConflict
+		default:
+			return fmt.Errorf("cant rename entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// maxBatchSize is the maximum number of entries the server accepts in one batch request.
+const maxBatchSize = 1000
+
+// DeleteEntries removes the entries with the given keys in one request, either all of them or none.
+func (s *service) DeleteEntries(ctx context.Context, keys []string) error {
+	if len(keys) > maxBatchSize {
+		return fmt.Errorf("at most %d entries can be deleted at once", maxBatchSize)
+	}
+
+	request := &pb.BatchDeleteEntriesRequest{Entries: make([]*pb.DeleteEntryRequest, 0, len(keys))}
+	for _, key := range keys {
+		request.Entries = append(request.Entries, &pb.DeleteEntryRequest{Key: key})
+	}
+
+	_, err := s.client.BatchDeleteEntries(ctx, request)
+	if err != nil {
+		return fmt.Errorf("cant delete entries: %w", err)
+	}
+
+	return nil
+}
+
+// GetMetadata retrieves metadata of the entries with the given keys, in batches of maxBatchSize.
+// Notes of every entry are decrypted.
+func (s *service) GetMetadata(ctx context.Context, keys []string) ([]Metadata, error) {
+	mds := make([]Metadata, 0, len(keys))
+
+	for batch := range slices.Chunk(keys, maxBatchSize) {
+		resp, err := s.client.BatchGetMetadata(ctx, &pb.BatchGetMetadataRequest{Keys: batch})
+		if err != nil {
+			return nil, fmt.Errorf("cant get metadata: %w", err)
+		}
+
+		for _, e := range resp.Entries {
+			md, err := s.toMetadata(e)
+			if err != nil {
+				return nil, err
+			}
+
+			mds = append(mds, md)
+		}
+	}
+
+	return mds, nil
+}
+
+// ListEntries retrieves metadata of all the entries stored in the cloud page by page.
+// Notes of every entry are decrypted.
+func (s *service) ListEntries(ctx context.Context) ([]Metadata, error) {
+	mds := make([]Metadata, 0)
+	pageToken := ""
+
+	for {
+		resp, err := s.client.ListEntries(ctx, &pb.ListEntriesRequest{PageToken: pageToken})
+		if err != nil {
+			return nil, fmt.Errorf("cant list entries: %w", err)
+		}
+
+		for _, e := range resp.Entries {
+			md, err := s.toMetadata(e)
+			if err != nil {
+				return nil, err
+			}
+
+			mds = append(mds, md)
+		}
+
+		if resp.NextPageToken == "" {
+			return mds, nil
+		}
+
+		pageToken = resp.NextPageToken
+	}
+}
+
+// ListChanges retrieves the changes made after the cursor page by page.
+// Notes of every changed entry are decrypted.
+func (s *service) ListChanges(ctx context.Context, cursor int64) ([]Change, int64, error) {
+	changes := make([]Change, 0)
+
+	for {
+		resp, err := s.client.ListChanges(ctx, &pb.ListChangesRequest{SinceCursor: cursor})
+		if err != nil {
+			return nil, 0, fmt.Errorf("cant list changes: %w", err)
+		}
+
+		for _, c := range resp.Changes {
+			change, err := s.toChange(c)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			changes = append(changes, change)
+		}
+
+		cursor = resp.NextCursor
+
+		if !resp.HasMore {
+			return changes, cursor, nil
+		}
+	}
+}
+
+// WatchChanges streams the changes from the server and calls onChange for each of them.
+func (s *service) WatchChanges(ctx context.Context, onChange func(Change)) error {
+	stream, err := s.client.WatchEntries(ctx, &pb.WatchEntriesRequest{})
+	if err != nil {
+		return fmt.Errorf("cant start watching changes: %w", err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if stErr, ok := status.FromError(err); ok {
+				switch stErr.Code() {
+				case codes.Canceled:
+					return nil
+				case codes.Aborted:
+					return ErrWatchDropped
+				}
+			}
+
+			return fmt.Errorf("error receiving change: %w", err)
+		}
+
+		change, err := s.toChange(resp)
+		if err != nil {
+			return err
+		}
+
+		onChange(change)
+	}
+}
+
+// toChange converts the change received from the server, decrypting the notes of the entry.
+func (s *service) toChange(c *pb.Change) (Change, error) {
+	change := Change{
+		Deleted: c.Deleted,
+		Cursor:  c.Cursor,
+	}
+
+	if c.Entry != nil {
+		md, err := s.toMetadata(c.Entry)
+		if err != nil {
+			return Change{}, err
+		}
+
+		change.Metadata = md
+	}
+
+	if c.ChangedAt != nil {
+		change.ChangedAt = c.ChangedAt.AsTime()
+	}
+
+	return change, nil
+}
+
+// ListVersions retrieves metadata of the previous versions of an entry, newest first.
+// Notes of every version are decrypted.
+func (s *service) ListVersions(ctx context.Context, key string) ([]Metadata, error) {
+	resp, err := s.client.ListEntryVersions(ctx, &pb.ListEntryVersionsRequest{Key: key})
+	if err != nil {
+		return nil, fmt.Errorf("cant list versions: %w", err)
+	}
+
+	mds := make([]Metadata, 0, len(resp.Versions))
+	for _, e := range resp.Versions {
+		md, err := s.toMetadata(e)
+		if err != nil {
+			return nil, err
+		}
+
+		mds = append(mds, md)
+	}
+
+	return mds, nil
+}
+
+// RestoreVersion makes a previous version of an entry the current one.
+func (s *service) RestoreVersion(ctx context.Context, key string, version int64) error {
+	_, err := s.client.RestoreEntryVersion(ctx, &pb.RestoreEntryVersionRequest{Key: key, Version: version})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
+			return ErrVersionNotFound
+		}
+
+		if isConflict(err) {
+			return ErrConflict
+		}
+
+		return fmt.Errorf("cant restore version: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsage retrieves the storage used by the user along with the limits.
+func (s *service) GetUsage(ctx context.Context) (Usage, error) {
+	resp, err := s.client.GetUsage(ctx, &pb.GetUsageRequest{})
+	if err != nil {
+		return Usage{}, fmt.Errorf("cant get usage: %w", err)
+	}
+
+	return Usage{
+		Bytes:      resp.Bytes,
+		Entries:    resp.Entries,
+		MaxBytes:   resp.MaxBytes,
+		MaxEntries: resp.MaxEntries,
+	}, nil
+}
+
+// ListTrash retrieves the deleted entries kept in the trash. Notes of every entry are decrypted.
+func (s *service) ListTrash(ctx context.Context) ([]TrashItem, error) {
+	resp, err := s.client.ListTrash(ctx, &pb.ListTrashRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cant list trash: %w", err)
+	}
+
+	items := make([]TrashItem, 0, len(resp.Entries))
+	for _, e := range resp.Entries {
+		md, err := s.toMetadata(e.Entry)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, TrashItem{
+			Metadata:  md,
+			DeletedAt: e.DeletedAt.AsTime(),
+			ExpiresAt: e.ExpiresAt.AsTime(),
+		})
+	}
+
+	return items, nil
+}
+
+// RestoreEntry moves a deleted entry back from the trash.
+func (s *service) RestoreEntry(ctx context.Context, key string) error {
+	_, err := s.client.RestoreEntry(ctx, &pb.RestoreEntryRequest{Key: key})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return ErrEntryNotFound
+		case codes.AlreadyExists:
+			return ErrEntryExists
+		case codes.ResourceExhausted:
+			return ErrQuotaExceeded
+		default:
+			return fmt.Errorf("cant restore entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// PurgeEntry deletes an entry from the trash for good.
+func (s *service) PurgeEntry(ctx context.Context, key string) error {
+	_, err := s.client.PurgeEntry(ctx, &pb.PurgeEntryRequest{Key: key})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ErrEntryNotFound
+		}
+
+		return fmt.Errorf("cant purge entry: %w", err)
+	}
+
+	return nil
+}
+
+// discardBlob drops the temporary blob being written. Writers implementing blob.Aborter are aborted,
+// so the content is never stored, otherwise the writer is closed and the blob is deleted.
+func (s *service) discardBlob(ctx context.Context, dst io.WriteCloser, key string) {
+	if a, ok := dst.(blob.Aborter); ok {
+		err := a.Abort()
+		if err != nil {
+			s.log.Errorw("cant abort temporary blob", "key", key, "err", err)
+		}
+
+		return
+	}
+
+	utils.CloseAndLogError(dst, s.log)
+
+	err := s.blobRepo.DeleteBlob(context.WithoutCancel(ctx), key)
+	if err != nil {
+		s.log.Errorw("cant delete temporary blob", "key", key, "err", err)
+	}
+}
+
+// resumeDownload requests the content from the offset and reads the metadata sent first.
+// ErrConflict is returned if the entry doesn't have the expected version anymore.
+func (s *service) resumeDownload(version int64, offset int64, resume resumeFunc) (grpc.ServerStreamingClient[pb.Entry], error) {
+	stream, err := resume(offset)
+	if err != nil {
+		return nil, fmt.Errorf("cant request the rest of the entry: %w", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && (stErr.Code() == codes.NotFound || stErr.Code() == codes.OutOfRange) {
+			return nil, fmt.Errorf("entry was changed during the download: %w", ErrConflict)
+		}
+
+		return nil, fmt.Errorf("error getting metadata: %w", err)
+	}
+
+	if resp.Version != version {
+		return nil, fmt.Errorf("entry was changed during the download: %w", ErrConflict)
+	}
+
+	return stream, nil
+}
+
+// isConflict reports whether the server rejected the request because the entry version didn't match.
+func isConflict(err error) bool {
+	stErr, ok := status.FromError(err)
+
+	return ok && stErr.Code() == codes.FailedPrecondition
+}
+
+// isQuotaExceeded reports whether the server rejected the request because the user's storage limits would be exceeded.
+func isQuotaExceeded(err error) bool {
+	stErr, ok := status.FromError(err)
+
+	return ok && stErr.Code() == codes.ResourceExhausted
+}
+
 type combinedRC struct {
 	reader io.Reader
 	closer io.Closer
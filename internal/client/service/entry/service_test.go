@@ -1,14 +1,21 @@
 package entry_test
 
 import (
+	"context"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	io "io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
@@ -18,6 +25,18 @@ import (
 
 const chunkSize = 1024
 
+func TestService_EntryKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	crypt := mocks.NewMockCrypt(ctrl)
+	crypt.EXPECT().MAC([]byte("login\x00name")).Return([]byte{0xde, 0xad, 0xbe, 0xef})
+
+	service := entry.New(crypt, mocks.NewMockEntryServiceClient(ctrl), mocks.NewMockBlobRepository(ctrl), chunkSize)
+
+	require.Equal(t, "deadbeef", service.EntryKey("login", "name"))
+}
+
 func TestService_Set(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
@@ -28,18 +47,8 @@ func TestService_Set(t *testing.T) {
 
 		crypt := mocks.NewMockCrypt(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
-		blobWriter := mocks.NewMockWriteCloser(ctrl)
-		encryptWriter := mocks.NewMockWriteCloser(ctrl)
 		rawContent := mocks.NewMockReadCloser(ctrl)
-
-		// encrypt blob
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-		crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-		rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-		rawContent.EXPECT().Close().Return(nil)
-		encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-		encryptWriter.EXPECT().Close().Return(nil)
-		blobWriter.EXPECT().Close().Return(nil)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
 		// encrypt notes
 		notesEncrypter := mocks.NewMockWriteCloser(ctrl)
@@ -47,37 +56,94 @@ func TestService_Set(t *testing.T) {
 		notesEncrypter.EXPECT().Write([]byte("notes")).Return(5, nil)
 		notesEncrypter.EXPECT().Close().Return(nil)
 
+		// encrypt envelope
+		envelopeEncrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().Encrypt(gomock.Any()).Return(envelopeEncrypter, nil)
+		envelopeEncrypter.EXPECT().Write([]byte(`{"name":"name","type":"login"}`)).Return(30, nil)
+		envelopeEncrypter.EXPECT().Close().Return(nil)
+
 		encryptedContent := mocks.NewMockReadCloser(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
-		stream := mocks.NewMockBidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		stream := mocks.NewMockClientStreamingClient[pb.UploadChunkRequest, pb.UploadSession](ctrl)
 
-		// send metadata
-		blobRepo.EXPECT().OpenBlobReader("key").Return(encryptedContent, true, nil)
-		client.EXPECT().SetEntry(ctx).Return(stream, nil)
-		stream.EXPECT().Send(&pb.SetEntryRequest{
+		// create session
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+		client.EXPECT().CreateUploadSession(ctx, &pb.CreateUploadSessionRequest{
 			Entry: &pb.Entry{
-				Key:     "key",
-				Name:    "name",
-				Notes:   nil, // encrypter will return nil bytes
-				Content: nil,
+				Key: "key",
+				// encrypters will return nil bytes
+				Notes:    nil,
+				Envelope: nil,
 			},
-		}).Return(nil)
-		stream.EXPECT().Recv().Return(&pb.SetEntryResponse{}, nil)
+			Size: 9,
+		}).Return(&pb.UploadSession{UploadId: "upload", Size: 9}, nil)
 
 		// upload
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(encryptedContent, true, nil)
+		client.EXPECT().UploadChunks(ctx).Return(stream, nil)
+		stream.EXPECT().Send(&pb.UploadChunkRequest{UploadId: "upload"}).Return(nil)
 		encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted")).Return(9, nil)
 		encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
 		encryptedContent.EXPECT().Close().Return(nil)
-		stream.EXPECT().Send(&pb.SetEntryRequest{
-			Entry: &pb.Entry{
-				Content: []byte("encrypted"),
-			},
-		}).Return(nil)
-		stream.EXPECT().CloseSend().Return(nil).MinTimes(1)
-		stream.EXPECT().Recv().Return(nil, io.EOF)
+		stream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("encrypted")}).Return(nil)
+		stream.EXPECT().CloseAndRecv().Return(&pb.UploadSession{UploadId: "upload", CommittedOffset: 9, Size: 9}, nil)
+
+		// finalize
+		client.EXPECT().FinalizeUpload(ctx, &pb.FinalizeUploadRequest{UploadId: "upload"}).Return(&emptypb.Empty{}, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Notes: "notes", Type: "login"}, rawContent, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("resumes interrupted upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		rawContent := mocks.NewMockReadCloser(ctrl)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
+
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectEnvelopeEncrypted(ctrl, crypt)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(18), true, nil)
+		client.EXPECT().CreateUploadSession(ctx, gomock.Any()).Return(&pb.UploadSession{UploadId: "upload", Size: 18}, nil)
+
+		// first attempt is interrupted after the first chunk is committed
+		firstContent := mocks.NewMockReadCloser(ctrl)
+		firstStream := mocks.NewMockClientStreamingClient[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(firstContent, true, nil)
+		client.EXPECT().UploadChunks(ctx).Return(firstStream, nil)
+		firstStream.EXPECT().Send(&pb.UploadChunkRequest{UploadId: "upload"}).Return(nil)
+		firstContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted")).Return(9, nil)
+		firstStream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("encrypted")}).Return(nil)
+		firstContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("more data")).Return(9, nil)
+		firstStream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("more data")}).Return(io.EOF)
+		firstStream.EXPECT().CloseAndRecv().Return(nil, status.Error(codes.Unavailable, "connection lost"))
+		firstContent.EXPECT().Close().Return(nil)
+
+		client.EXPECT().GetUploadSession(ctx, &pb.GetUploadSessionRequest{UploadId: "upload"}).
+			Return(&pb.UploadSession{UploadId: "upload", CommittedOffset: 9, Size: 18}, nil)
+
+		// second attempt continues from the committed offset, skipping the uploaded part
+		secondContent := mocks.NewMockReadCloser(ctrl)
+		secondStream := mocks.NewMockClientStreamingClient[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(secondContent, true, nil)
+		secondContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted")).Return(9, nil)
+		client.EXPECT().UploadChunks(ctx).Return(secondStream, nil)
+		secondStream.EXPECT().Send(&pb.UploadChunkRequest{UploadId: "upload", Offset: 9}).Return(nil)
+		secondContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("more data")).Return(9, nil)
+		secondStream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("more data")}).Return(nil)
+		secondContent.EXPECT().Read(gomock.Any()).Return(0, io.EOF)
+		secondStream.EXPECT().CloseAndRecv().Return(&pb.UploadSession{UploadId: "upload", CommittedOffset: 18, Size: 18}, nil)
+		secondContent.EXPECT().Close().Return(nil)
+
+		client.EXPECT().FinalizeUpload(ctx, &pb.FinalizeUploadRequest{UploadId: "upload"}).Return(&emptypb.Empty{}, nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "notes", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, nil)
 		require.NoError(t, err)
 	})
 
@@ -90,11 +156,11 @@ func TestService_Set(t *testing.T) {
 		rawContent := mocks.NewMockReadCloser(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
 
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(nil, errors.New("error"))
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(nil, errors.New("error"))
 		rawContent.EXPECT().Close().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "notes", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Notes: "notes"}, rawContent, nil)
 		require.Error(t, err)
 	})
 
@@ -108,13 +174,13 @@ func TestService_Set(t *testing.T) {
 		rawContent := mocks.NewMockReadCloser(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
 
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
 		crypt.EXPECT().Encrypt(blobWriter).Return(nil, errors.New("error"))
 		rawContent.EXPECT().Close().Return(nil)
 		blobWriter.EXPECT().Close().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "notes", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Notes: "notes"}, rawContent, nil)
 		require.Error(t, err)
 	})
 
@@ -129,7 +195,7 @@ func TestService_Set(t *testing.T) {
 		rawContent := mocks.NewMockReadCloser(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
 
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
 		crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
 		rawContent.EXPECT().Read(gomock.Any()).Return(0, errors.New("error"))
 		rawContent.EXPECT().Close().Return(nil)
@@ -137,7 +203,7 @@ func TestService_Set(t *testing.T) {
 		encryptWriter.EXPECT().Close().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "notes", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Notes: "notes"}, rawContent, nil)
 		require.Error(t, err)
 	})
 
@@ -152,7 +218,7 @@ func TestService_Set(t *testing.T) {
 		rawContent := mocks.NewMockReadCloser(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
 
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
 		crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
 		rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
 		rawContent.EXPECT().Close().Return(nil)
@@ -161,53 +227,54 @@ func TestService_Set(t *testing.T) {
 		blobWriter.EXPECT().Close().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "notes", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Notes: "notes"}, rawContent, nil)
 		require.Error(t, err)
 	})
 
+	t.Run("quota exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		rawContent := mocks.NewMockReadCloser(ctrl)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
+
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		expectEnvelopeEncrypted(ctrl, crypt)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+		client.EXPECT().CreateUploadSession(ctx, &pb.CreateUploadSessionRequest{
+			Entry: &pb.Entry{Key: "key", Envelope: []byte("envelope")},
+			Size:  9,
+		}).Return(nil, status.Error(codes.ResourceExhausted, "storage quota exceeded"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, nil)
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+	})
+
 	t.Run("already exists", func(t *testing.T) {
+		alreadyExists := status.Error(codes.AlreadyExists, "entry already exists")
+
 		t.Run("no callback", func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
 			crypt := mocks.NewMockCrypt(ctrl)
 			blobRepo := mocks.NewMockBlobRepository(ctrl)
-			blobWriter := mocks.NewMockWriteCloser(ctrl)
-			encryptWriter := mocks.NewMockWriteCloser(ctrl)
 			rawContent := mocks.NewMockReadCloser(ctrl)
+			expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
-			// encrypt blob
-			blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-			crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-			rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-			rawContent.EXPECT().Close().Return(nil)
-			encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-			encryptWriter.EXPECT().Close().Return(nil)
-			blobWriter.EXPECT().Close().Return(nil)
-
-			encryptedContent := mocks.NewMockReadCloser(ctrl)
 			client := mocks.NewMockEntryServiceClient(ctrl)
-			stream := mocks.NewMockBidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
-
-			// send metadata
-			blobRepo.EXPECT().OpenBlobReader("key").Return(encryptedContent, true, nil)
-			client.EXPECT().SetEntry(ctx).Return(stream, nil)
-			stream.EXPECT().Send(&pb.SetEntryRequest{
-				Entry: &pb.Entry{
-					Key:     "key",
-					Name:    "name",
-					Notes:   nil, // encrypter will return nil bytes
-					Content: nil,
-				},
-			}).Return(nil)
-			stream.EXPECT().Recv().Return(&pb.SetEntryResponse{
-				AlreadyExists: true,
-			}, nil)
-			stream.EXPECT().CloseSend().Return(nil)
-			encryptedContent.EXPECT().Close().Return(nil)
+			expectEnvelopeEncrypted(ctrl, crypt)
+			blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+			client.EXPECT().CreateUploadSession(ctx, &pb.CreateUploadSessionRequest{
+				Entry: &pb.Entry{Key: "key", Envelope: []byte("envelope")},
+				Size:  9,
+			}).Return(nil, alreadyExists)
 
 			service := entry.New(crypt, client, blobRepo, chunkSize)
-			err := service.SetEntry(ctx, "key", "name", "", rawContent, nil)
+			err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, nil)
 			require.ErrorIs(t, err, entry.ErrEntryExists)
 		})
 
@@ -217,42 +284,16 @@ func TestService_Set(t *testing.T) {
 
 			crypt := mocks.NewMockCrypt(ctrl)
 			blobRepo := mocks.NewMockBlobRepository(ctrl)
-			blobWriter := mocks.NewMockWriteCloser(ctrl)
-			encryptWriter := mocks.NewMockWriteCloser(ctrl)
 			rawContent := mocks.NewMockReadCloser(ctrl)
+			expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
-			// encrypt blob
-			blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-			crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-			rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-			rawContent.EXPECT().Close().Return(nil)
-			encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-			encryptWriter.EXPECT().Close().Return(nil)
-			blobWriter.EXPECT().Close().Return(nil)
-
-			encryptedContent := mocks.NewMockReadCloser(ctrl)
 			client := mocks.NewMockEntryServiceClient(ctrl)
-			stream := mocks.NewMockBidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
-
-			// send metadata
-			blobRepo.EXPECT().OpenBlobReader("key").Return(encryptedContent, true, nil)
-			client.EXPECT().SetEntry(ctx).Return(stream, nil)
-			stream.EXPECT().Send(&pb.SetEntryRequest{
-				Entry: &pb.Entry{
-					Key:     "key",
-					Name:    "name",
-					Notes:   nil, // encrypter will return nil bytes
-					Content: nil,
-				},
-			}).Return(nil)
-			stream.EXPECT().Recv().Return(&pb.SetEntryResponse{
-				AlreadyExists: true,
-			}, nil)
-			stream.EXPECT().CloseSend().Return(nil)
-			encryptedContent.EXPECT().Close().Return(nil)
+			expectEnvelopeEncrypted(ctrl, crypt)
+			blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+			client.EXPECT().CreateUploadSession(ctx, gomock.Any()).Return(nil, alreadyExists)
 
 			service := entry.New(crypt, client, blobRepo, chunkSize)
-			err := service.SetEntry(ctx, "key", "name", "", rawContent, func() bool {
+			err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, func() bool {
 				return false
 			})
 			require.ErrorIs(t, err, entry.ErrEntryExists)
@@ -264,177 +305,133 @@ func TestService_Set(t *testing.T) {
 
 			crypt := mocks.NewMockCrypt(ctrl)
 			blobRepo := mocks.NewMockBlobRepository(ctrl)
-			blobWriter := mocks.NewMockWriteCloser(ctrl)
-			encryptWriter := mocks.NewMockWriteCloser(ctrl)
 			rawContent := mocks.NewMockReadCloser(ctrl)
-
-			// encrypt blob
-			blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-			crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-			rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-			rawContent.EXPECT().Close().Return(nil)
-			encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-			encryptWriter.EXPECT().Close().Return(nil)
-			blobWriter.EXPECT().Close().Return(nil)
-
-			// encrypt notes
-			notesEncrypter := mocks.NewMockWriteCloser(ctrl)
-			crypt.EXPECT().Encrypt(gomock.Any()).Return(notesEncrypter, nil)
-			notesEncrypter.EXPECT().Write([]byte("notes")).Return(5, nil)
-			notesEncrypter.EXPECT().Close().Return(nil)
+			expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
 			encryptedContent := mocks.NewMockReadCloser(ctrl)
 			client := mocks.NewMockEntryServiceClient(ctrl)
-			stream := mocks.NewMockBidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+			stream := mocks.NewMockClientStreamingClient[pb.UploadChunkRequest, pb.UploadSession](ctrl)
 
-			// send metadata
-			blobRepo.EXPECT().OpenBlobReader("key").Return(encryptedContent, true, nil)
-			client.EXPECT().SetEntry(ctx).Return(stream, nil)
-			stream.EXPECT().Send(&pb.SetEntryRequest{
-				Entry: &pb.Entry{
-					Key:     "key",
-					Name:    "name",
-					Notes:   nil, // encrypter will return nil bytes
-					Content: nil,
-				},
-			}).Return(nil)
-			stream.EXPECT().Recv().Return(&pb.SetEntryResponse{
-				AlreadyExists: true,
-			}, nil)
+			expectEnvelopeEncrypted(ctrl, crypt)
+			blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+			client.EXPECT().CreateUploadSession(ctx, &pb.CreateUploadSessionRequest{
+				Entry: &pb.Entry{Key: "key", Envelope: []byte("envelope")},
+				Size:  9,
+			}).Return(nil, alreadyExists)
 
 			// confirm overwrite
-			stream.EXPECT().Send(&pb.SetEntryRequest{
+			client.EXPECT().CreateUploadSession(ctx, &pb.CreateUploadSessionRequest{
+				Entry:     &pb.Entry{Key: "key", Envelope: []byte("envelope")},
+				Size:      9,
 				Overwrite: true,
-			}).Return(nil)
+			}).Return(&pb.UploadSession{UploadId: "upload", Size: 9}, nil)
 
 			// upload
+			blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(encryptedContent, true, nil)
+			client.EXPECT().UploadChunks(ctx).Return(stream, nil)
+			stream.EXPECT().Send(&pb.UploadChunkRequest{UploadId: "upload"}).Return(nil)
 			encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted")).Return(9, nil)
 			encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
 			encryptedContent.EXPECT().Close().Return(nil)
-			stream.EXPECT().Send(&pb.SetEntryRequest{
-				Entry: &pb.Entry{
-					Content: []byte("encrypted"),
-				},
-			}).Return(nil)
-			stream.EXPECT().CloseSend().Return(nil).MinTimes(1)
-			stream.EXPECT().Recv().Return(nil, io.EOF)
+			stream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("encrypted")}).Return(nil)
+			stream.EXPECT().CloseAndRecv().Return(&pb.UploadSession{UploadId: "upload", CommittedOffset: 9, Size: 9}, nil)
+
+			client.EXPECT().FinalizeUpload(ctx, &pb.FinalizeUploadRequest{UploadId: "upload"}).Return(&emptypb.Empty{}, nil)
 
 			service := entry.New(crypt, client, blobRepo, chunkSize)
-			err := service.SetEntry(ctx, "key", "name", "notes", rawContent, func() bool {
+			err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, func() bool {
 				return true
 			})
 			require.NoError(t, err)
 		})
 
-		t.Run("callback returns true, error sending confirmation", func(t *testing.T) {
+		t.Run("callback returns true, error creating session", func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
 			crypt := mocks.NewMockCrypt(ctrl)
 			blobRepo := mocks.NewMockBlobRepository(ctrl)
-			blobWriter := mocks.NewMockWriteCloser(ctrl)
-			encryptWriter := mocks.NewMockWriteCloser(ctrl)
 			rawContent := mocks.NewMockReadCloser(ctrl)
+			expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
-			// encrypt blob
-			blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-			crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-			rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-			rawContent.EXPECT().Close().Return(nil)
-			encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-			encryptWriter.EXPECT().Close().Return(nil)
-			blobWriter.EXPECT().Close().Return(nil)
-
-			// encrypt notes
-			notesEncrypter := mocks.NewMockWriteCloser(ctrl)
-			crypt.EXPECT().Encrypt(gomock.Any()).Return(notesEncrypter, nil)
-			notesEncrypter.EXPECT().Write([]byte("notes")).Return(5, nil)
-			notesEncrypter.EXPECT().Close().Return(nil)
-
-			encryptedContent := mocks.NewMockReadCloser(ctrl)
 			client := mocks.NewMockEntryServiceClient(ctrl)
-			stream := mocks.NewMockBidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
-
-			// send metadata
-			blobRepo.EXPECT().OpenBlobReader("key").Return(encryptedContent, true, nil)
-			client.EXPECT().SetEntry(ctx).Return(stream, nil)
-			stream.EXPECT().Send(&pb.SetEntryRequest{
-				Entry: &pb.Entry{
-					Key:     "key",
-					Name:    "name",
-					Notes:   nil, // encrypter will return nil bytes
-					Content: nil,
-				},
-			}).Return(nil)
-			stream.EXPECT().Recv().Return(&pb.SetEntryResponse{
-				AlreadyExists: true,
-			}, nil)
-
-			// confirm overwrite
-			stream.EXPECT().Send(&pb.SetEntryRequest{
-				Overwrite: true,
-			}).Return(errors.New("error"))
-
-			encryptedContent.EXPECT().Close().Return(nil)
-			stream.EXPECT().CloseSend().Return(nil).MinTimes(1)
+			expectEnvelopeEncrypted(ctrl, crypt)
+			blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+			client.EXPECT().CreateUploadSession(ctx, gomock.Any()).Return(nil, alreadyExists)
+			client.EXPECT().CreateUploadSession(ctx, gomock.Any()).Return(nil, errors.New("error"))
 
 			service := entry.New(crypt, client, blobRepo, chunkSize)
-			err := service.SetEntry(ctx, "key", "name", "notes", rawContent, func() bool {
+			err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, func() bool {
 				return true
 			})
 			require.Error(t, err)
+			require.NotErrorIs(t, err, entry.ErrEntryExists)
 		})
 	})
 
-	t.Run("upload error", func(t *testing.T) {
+	t.Run("upload doesn't progress", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		crypt := mocks.NewMockCrypt(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
-		blobWriter := mocks.NewMockWriteCloser(ctrl)
-		encryptWriter := mocks.NewMockWriteCloser(ctrl)
 		rawContent := mocks.NewMockReadCloser(ctrl)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
-		// encrypt blob
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-		crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-		rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-		rawContent.EXPECT().Close().Return(nil)
-		encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-		encryptWriter.EXPECT().Close().Return(nil)
-		blobWriter.EXPECT().Close().Return(nil)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		// only one retry to not wait for long
+		retryCtx, retryCancel := context.WithTimeout(ctx, 1500*time.Millisecond)
+		defer retryCancel()
+
+		expectEnvelopeEncrypted(ctrl, crypt)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+		client.EXPECT().CreateUploadSession(retryCtx, gomock.Any()).Return(&pb.UploadSession{UploadId: "upload", Size: 9}, nil)
+
+		// the server rejects every attempt without committing anything
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").DoAndReturn(func(context.Context, string) (io.ReadCloser, bool, error) {
+			encryptedContent := mocks.NewMockReadCloser(ctrl)
+			encryptedContent.EXPECT().Close().Return(nil)
+
+			return encryptedContent, true, nil
+		}).Times(2)
+		client.EXPECT().UploadChunks(retryCtx).Return(nil, errors.New("error")).Times(2)
+		client.EXPECT().GetUploadSession(retryCtx, &pb.GetUploadSessionRequest{UploadId: "upload"}).
+			Return(&pb.UploadSession{UploadId: "upload", Size: 9}, nil).Times(2)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.SetEntry(retryCtx, entry.Metadata{Key: "key", Name: "name"}, rawContent, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("error getting committed offset", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		rawContent := mocks.NewMockReadCloser(ctrl)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
 		encryptedContent := mocks.NewMockReadCloser(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
-		stream := mocks.NewMockBidiStreamingClient[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		stream := mocks.NewMockClientStreamingClient[pb.UploadChunkRequest, pb.UploadSession](ctrl)
 
-		// send metadata
-		blobRepo.EXPECT().OpenBlobReader("key").Return(encryptedContent, true, nil)
-		client.EXPECT().SetEntry(ctx).Return(stream, nil)
-		stream.EXPECT().Send(&pb.SetEntryRequest{
-			Entry: &pb.Entry{
-				Key:     "key",
-				Name:    "name",
-				Notes:   nil,
-				Content: nil,
-			},
-		}).Return(nil)
-		stream.EXPECT().Recv().Return(&pb.SetEntryResponse{}, nil)
+		expectEnvelopeEncrypted(ctrl, crypt)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+		client.EXPECT().CreateUploadSession(ctx, gomock.Any()).Return(&pb.UploadSession{UploadId: "upload", Size: 9}, nil)
 
-		// upload
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(encryptedContent, true, nil)
+		client.EXPECT().UploadChunks(ctx).Return(stream, nil)
+		stream.EXPECT().Send(&pb.UploadChunkRequest{UploadId: "upload"}).Return(nil)
 		encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted")).Return(9, nil)
 		encryptedContent.EXPECT().Close().Return(nil)
-		stream.EXPECT().Send(&pb.SetEntryRequest{
-			Entry: &pb.Entry{
-				Content: []byte("encrypted"),
-			},
-		}).Return(errors.New("error"))
+		stream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("encrypted")}).Return(errors.New("error"))
 
-		stream.EXPECT().CloseSend().Return(nil).MinTimes(1)
+		client.EXPECT().GetUploadSession(ctx, &pb.GetUploadSessionRequest{UploadId: "upload"}).Return(nil, errors.New("error"))
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, nil)
 		require.Error(t, err)
 	})
 
@@ -444,28 +441,119 @@ func TestService_Set(t *testing.T) {
 
 		crypt := mocks.NewMockCrypt(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
-		blobWriter := mocks.NewMockWriteCloser(ctrl)
-		encryptWriter := mocks.NewMockWriteCloser(ctrl)
 		rawContent := mocks.NewMockReadCloser(ctrl)
-
-		// encrypt blob
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
-		crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
-		rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
-		rawContent.EXPECT().Close().Return(nil)
-		encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
-		encryptWriter.EXPECT().Close().Return(nil)
-		blobWriter.EXPECT().Close().Return(nil)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
 
 		// encrypt notes
 		crypt.EXPECT().Encrypt(gomock.Any()).Return(nil, errors.New("error"))
 
 		service := entry.New(crypt, mocks.NewMockEntryServiceClient(ctrl), blobRepo, chunkSize)
-		err := service.SetEntry(ctx, "key", "name", "notes", rawContent, nil)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Notes: "notes"}, rawContent, nil)
+		require.Error(t, err)
+	})
+	t.Run("error encrypting envelope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		rawContent := mocks.NewMockReadCloser(ctrl)
+		expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
+
+		// encrypt envelope
+		crypt.EXPECT().Encrypt(gomock.Any()).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, mocks.NewMockEntryServiceClient(ctrl), blobRepo, chunkSize)
+		err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name"}, rawContent, nil)
 		require.Error(t, err)
 	})
 }
 
+func TestService_Set_ExpectedVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	for name, failOnFinalize := range map[string]bool{"rejected right away": false, "rejected after upload": true} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			crypt := mocks.NewMockCrypt(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+			rawContent := mocks.NewMockReadCloser(ctrl)
+			expectContentEncrypted(ctx, ctrl, crypt, blobRepo, rawContent)
+
+			client := mocks.NewMockEntryServiceClient(ctrl)
+
+			// create session with the expected version
+			expectEnvelopeEncrypted(ctrl, crypt)
+			blobRepo.EXPECT().GetBlobSize(gomock.Any(), "key").Return(int64(9), true, nil)
+			request := &pb.CreateUploadSessionRequest{
+				Entry: &pb.Entry{
+					Key:      "key",
+					Envelope: []byte("envelope"),
+				},
+				Size:            9,
+				ExpectedVersion: 2,
+			}
+
+			conflict := status.Error(codes.FailedPrecondition, "entry version mismatch")
+			if failOnFinalize {
+				encryptedContent := mocks.NewMockReadCloser(ctrl)
+				stream := mocks.NewMockClientStreamingClient[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+
+				client.EXPECT().CreateUploadSession(ctx, request).Return(&pb.UploadSession{UploadId: "upload", Size: 9}, nil)
+
+				blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(encryptedContent, true, nil)
+				client.EXPECT().UploadChunks(ctx).Return(stream, nil)
+				stream.EXPECT().Send(&pb.UploadChunkRequest{UploadId: "upload"}).Return(nil)
+				encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted")).Return(9, nil)
+				encryptedContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
+				encryptedContent.EXPECT().Close().Return(nil)
+				stream.EXPECT().Send(&pb.UploadChunkRequest{Content: []byte("encrypted")}).Return(nil)
+				stream.EXPECT().CloseAndRecv().Return(&pb.UploadSession{UploadId: "upload", CommittedOffset: 9, Size: 9}, nil)
+
+				client.EXPECT().FinalizeUpload(ctx, &pb.FinalizeUploadRequest{UploadId: "upload"}).Return(nil, conflict)
+			} else {
+				client.EXPECT().CreateUploadSession(ctx, request).Return(nil, conflict)
+			}
+
+			service := entry.New(crypt, client, blobRepo, chunkSize)
+			err := service.SetEntry(ctx, entry.Metadata{Key: "key", Name: "name", Type: "login", Version: 2}, rawContent, nil)
+			require.ErrorIs(t, err, entry.ErrConflict)
+		})
+	}
+}
+
+// expectContentEncrypted sets up the mocks to encrypt "content" read from rawContent to the blob.
+func expectContentEncrypted(ctx context.Context, ctrl *gomock.Controller, crypt *mocks.MockCrypt, blobRepo *mocks.MockBlobRepository, rawContent *mocks.MockReadCloser) {
+	blobWriter := mocks.NewMockWriteCloser(ctrl)
+	encryptWriter := mocks.NewMockWriteCloser(ctrl)
+
+	blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
+	crypt.EXPECT().Encrypt(blobWriter).Return(encryptWriter, nil)
+	rawContent.EXPECT().Read(gomock.Any()).SetArg(0, []byte("content")).Return(7, io.EOF)
+	rawContent.EXPECT().Close().Return(nil)
+	encryptWriter.EXPECT().Write([]byte("content")).Return(7, nil)
+	encryptWriter.EXPECT().Close().Return(nil)
+	blobWriter.EXPECT().Close().Return(nil)
+}
+
+// expectEnvelopeEncrypted sets up the mocks to encrypt the envelope to "envelope".
+func expectEnvelopeEncrypted(ctrl *gomock.Controller, crypt *mocks.MockCrypt) {
+	envelopeEncrypter := mocks.NewMockWriteCloser(ctrl)
+
+	crypt.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(dst io.Writer) (io.WriteCloser, error) {
+		_, err := dst.Write([]byte("envelope"))
+
+		return envelopeEncrypter, err
+	})
+	envelopeEncrypter.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		return len(p), nil
+	})
+	envelopeEncrypter.EXPECT().Close().Return(nil)
+}
+
 func TestService_Get(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
@@ -485,11 +573,17 @@ func TestService_Get(t *testing.T) {
 		}).Return(stream, nil)
 
 		// receive metadata
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		updatedAt := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
 		stream.EXPECT().Recv().Return(&pb.Entry{
-			Key:     "key",
-			Name:    "name",
-			Notes:   []byte("encrypted notes"),
-			Content: nil,
+			Key:       "key",
+			Name:      "name",
+			Notes:     []byte("encrypted notes"),
+			Type:      "login",
+			CreatedAt: timestamppb.New(createdAt),
+			UpdatedAt: timestamppb.New(updatedAt),
+			Size:      16,
+			Content:   nil,
 		}, nil)
 
 		// decrypt notes
@@ -500,17 +594,17 @@ func TestService_Get(t *testing.T) {
 
 		// receive content
 		blobWriter := mocks.NewMockWriteCloser(ctrl)
-		blobRepo.EXPECT().OpenBlobWriter("key").Return(blobWriter, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
 		stream.EXPECT().Recv().Return(&pb.Entry{
 			Content: []byte("encrypted content"),
 		}, nil)
 		stream.EXPECT().Recv().Return(nil, io.EOF)
 		blobWriter.EXPECT().Write([]byte("encrypted content")).Return(16, nil)
-		blobWriter.EXPECT().Close().Return(nil).MinTimes(1)
+		blobWriter.EXPECT().Close().Return(nil)
 
 		// wrap in decrypt
 		blobReader := mocks.NewMockReadCloser(ctrl)
-		blobRepo.EXPECT().OpenBlobReader("key").Return(blobReader, true, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(blobReader, true, nil)
 		decryptReader := mocks.NewMockReadCloser(ctrl)
 		crypt.EXPECT().Decrypt(blobReader).Return(decryptReader, nil)
 
@@ -518,10 +612,18 @@ func TestService_Get(t *testing.T) {
 		stream.EXPECT().CloseSend().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		notes, content, found, err := service.GetEntry(ctx, "key")
+		md, content, found, err := service.GetEntry(ctx, "key")
 		require.NoError(t, err)
 		require.True(t, found)
-		require.Equal(t, "notes", notes)
+		require.Equal(t, entry.Metadata{
+			Key:       "key",
+			Name:      "name",
+			Notes:     "notes",
+			Type:      "login",
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+			Size:      16,
+		}, md)
 		require.NotNil(t, content)
 
 		// read is forwarded to decrypt
@@ -536,7 +638,7 @@ func TestService_Get(t *testing.T) {
 		require.NoError(t, content.Close())
 	})
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("resume interrupted download", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -544,61 +646,1050 @@ func TestService_Get(t *testing.T) {
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
 		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+		resumedStream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
 
-		// send request
 		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{
 			Key: "key",
 		}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Key:      "key",
+			Name:     "name",
+			Type:     "file",
+			Size:     17,
+			Version:  2,
+			Checksum: checksum("encrypted content"),
+		}, nil)
 
-		stream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "not found"))
+		// first part is received, then the stream breaks
+		blobWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Content: []byte("encrypted"),
+		}, nil)
+		blobWriter.EXPECT().Write([]byte("encrypted")).Return(9, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Unavailable, "connection lost"))
+
+		// the rest is requested from the received offset
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{
+			Key:    "key",
+			Offset: 9,
+		}).Return(resumedStream, nil)
+		resumedStream.EXPECT().Recv().Return(&pb.Entry{
+			Key:      "key",
+			Name:     "name",
+			Type:     "file",
+			Size:     17,
+			Version:  2,
+			Checksum: checksum("encrypted content"),
+		}, nil)
+		resumedStream.EXPECT().Recv().Return(&pb.Entry{
+			Content: []byte(" content"),
+		}, nil)
+		blobWriter.EXPECT().Write([]byte(" content")).Return(8, nil)
+		resumedStream.EXPECT().Recv().Return(nil, io.EOF)
+		blobWriter.EXPECT().Close().Return(nil)
+
+		blobReader := mocks.NewMockReadCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(blobReader, true, nil)
+		crypt.EXPECT().Decrypt(blobReader).Return(mocks.NewMockReadCloser(ctrl), nil)
 
-		// close stream
 		stream.EXPECT().CloseSend().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		notes, content, found, err := service.GetEntry(ctx, "key")
+		md, content, found, err := service.GetEntry(ctx, "key")
 		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, int64(2), md.Version)
+		require.Equal(t, checksum("encrypted content"), md.Checksum)
+		require.NotNil(t, content)
+	})
+
+	t.Run("entry changed during download", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+		resumedStream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{
+			Key: "key",
+		}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Key:     "key",
+			Size:    17,
+			Version: 2,
+		}, nil)
+
+		blobWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Content: []byte("encrypted"),
+		}, nil)
+		blobWriter.EXPECT().Write([]byte("encrypted")).Return(9, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Unavailable, "connection lost"))
+
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{
+			Key:    "key",
+			Offset: 9,
+		}).Return(resumedStream, nil)
+		resumedStream.EXPECT().Recv().Return(&pb.Entry{
+			Key:     "key",
+			Size:    20,
+			Version: 3,
+		}, nil)
+		// the incomplete content isn't kept
+		blobWriter.EXPECT().Close().Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "key").Return(nil)
+
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		_, content, found, err := service.GetEntry(ctx, "key")
+		require.ErrorIs(t, err, entry.ErrConflict)
 		require.False(t, found)
-		require.Empty(t, notes)
 		require.Nil(t, content)
 	})
-}
-
-func TestService_Delete(t *testing.T) {
-	ctx, cancel := utils.TestContext(t)
-	defer cancel()
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("checksum mismatch", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		crypt := mocks.NewMockCrypt(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
 
-		client.EXPECT().DeleteEntry(ctx, &pb.DeleteEntryRequest{
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{
 			Key: "key",
-		}).Return(nil, nil)
+		}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Key:      "key",
+			Size:     17,
+			Version:  1,
+			Checksum: checksum("encrypted content"),
+		}, nil)
+
+		// the corrupted content is never stored
+		blobWriter := &abortingWriter{MockWriteCloser: mocks.NewMockWriteCloser(ctrl)}
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Content: []byte("corrupted content"),
+		}, nil)
+		blobWriter.EXPECT().Write([]byte("corrupted content")).Return(17, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+
+		stream.EXPECT().CloseSend().Return(nil)
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.DeleteEntry(ctx, "key")
-		require.NoError(t, err)
+		_, content, found, err := service.GetEntry(ctx, "key")
+		require.ErrorIs(t, err, entry.ErrChecksumMismatch)
+		require.False(t, found)
+		require.Nil(t, content)
+		require.True(t, blobWriter.aborted)
 	})
 
-	t.Run("error", func(t *testing.T) {
+	t.Run("version download is not resumed", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		crypt := mocks.NewMockCrypt(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
 		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
 
-		client.EXPECT().DeleteEntry(ctx, &pb.DeleteEntryRequest{
+		client.EXPECT().GetEntryVersion(ctx, &pb.GetEntryVersionRequest{
+			Key:     "key",
+			Version: 1,
+		}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Key:     "key",
+			Size:    17,
+			Version: 1,
+		}, nil)
+
+		blobWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Unavailable, "connection lost"))
+		blobWriter.EXPECT().Close().Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "key").Return(nil)
+
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		_, content, found, err := service.GetVersion(ctx, "key", 1)
+		require.Error(t, err)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.False(t, found)
+		require.Nil(t, content)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+
+		// send request
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{
+			Key: "key",
+		}).Return(stream, nil)
+
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "not found"))
+
+		// close stream
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		md, content, found, err := service.GetEntry(ctx, "key")
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Empty(t, md)
+		require.Nil(t, content)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().DeleteEntry(ctx, &pb.DeleteEntryRequest{
+			Key: "key",
+		}).Return(nil, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.DeleteEntry(ctx, "key", 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().DeleteEntry(ctx, &pb.DeleteEntryRequest{
 			Key: "key",
 		}).Return(nil, errors.New("error"))
 
 		service := entry.New(crypt, client, blobRepo, chunkSize)
-		err := service.DeleteEntry(ctx, "key")
+		err := service.DeleteEntry(ctx, "key", 0)
+		require.Error(t, err)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().DeleteEntry(ctx, &pb.DeleteEntryRequest{
+			Key:             "key",
+			ExpectedVersion: 2,
+		}).Return(nil, status.Error(codes.FailedPrecondition, "entry version mismatch"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.DeleteEntry(ctx, "key", 2)
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+}
+
+func TestService_DeleteEntries(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().BatchDeleteEntries(ctx, &pb.BatchDeleteEntriesRequest{Entries: []*pb.DeleteEntryRequest{
+			{Key: "first"},
+			{Key: "second"},
+		}}).Return(nil, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.DeleteEntries(ctx, []string{"first", "second"})
+		require.NoError(t, err)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.DeleteEntries(ctx, make([]string, 1001))
+		require.Error(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().BatchDeleteEntries(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.DeleteEntries(ctx, []string{"first"})
+		require.Error(t, err)
+	})
+}
+
+func TestService_RenameEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectEnvelopeEncrypted(ctrl, crypt)
+		client.EXPECT().RenameEntry(ctx, &pb.RenameEntryRequest{
+			Key:         "key",
+			NewKey:      "new key",
+			NewEnvelope: []byte("envelope"),
+		}).Return(nil, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.RenameEntry(ctx, "key", entry.Metadata{Key: "new key", Name: "new name", Type: "login"})
+		require.NoError(t, err)
+	})
+
+	for code, expected := range map[codes.Code]error{
+		codes.NotFound:           entry.ErrEntryNotFound,
+		codes.AlreadyExists:      entry.ErrEntryExists,
+		codes.FailedPrecondition: entry.ErrConflict,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			crypt := mocks.NewMockCrypt(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+			client := mocks.NewMockEntryServiceClient(ctrl)
+
+			expectEnvelopeEncrypted(ctrl, crypt)
+			client.EXPECT().RenameEntry(ctx, &pb.RenameEntryRequest{
+				Key:             "key",
+				NewKey:          "new key",
+				NewEnvelope:     []byte("envelope"),
+				ExpectedVersion: 2,
+			}).Return(nil, status.Error(code, "error"))
+
+			service := entry.New(crypt, client, blobRepo, chunkSize)
+			err := service.RenameEntry(ctx, "key", entry.Metadata{Key: "new key", Name: "new name", Type: "login", Version: 2})
+			require.ErrorIs(t, err, expected)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectEnvelopeEncrypted(ctrl, crypt)
+		client.EXPECT().RenameEntry(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.RenameEntry(ctx, "key", entry.Metadata{Key: "new key", Name: "new name", Type: "login"})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+}
+
+func TestService_GetMetadata(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("in batches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		keys := make([]string, 1500)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key%d", i)
+		}
+
+		client.EXPECT().BatchGetMetadata(ctx, &pb.BatchGetMetadataRequest{Keys: keys[:1000]}).Return(&pb.BatchGetMetadataResponse{
+			Entries: []*pb.Entry{{Key: "key1", Name: "first", Version: 1}},
+		}, nil)
+		client.EXPECT().BatchGetMetadata(ctx, &pb.BatchGetMetadataRequest{Keys: keys[1000:]}).Return(&pb.BatchGetMetadataResponse{
+			Entries: []*pb.Entry{{Key: "key1200", Name: "second", Version: 3}},
+		}, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.GetMetadata(ctx, keys)
+		require.NoError(t, err)
+		require.Len(t, mds, 2)
+		require.Equal(t, "first", mds[0].Name)
+		require.Equal(t, int64(3), mds[1].Version)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().BatchGetMetadata(ctx, gomock.Any()).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		_, err := service.GetMetadata(ctx, []string{"key"})
+		require.Error(t, err)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		// first page
+		client.EXPECT().ListEntries(ctx, &pb.ListEntriesRequest{}).Return(&pb.ListEntriesResponse{
+			Entries: []*pb.Entry{
+				{Key: "key1", Name: "name1", Notes: []byte("encrypted notes")},
+			},
+			NextPageToken: "key1",
+		}, nil)
+
+		notesReader := mocks.NewMockReadCloser(ctrl)
+		crypt.EXPECT().Decrypt(gomock.Any()).Return(notesReader, nil)
+		notesReader.EXPECT().Read(gomock.Any()).SetArg(0, []byte("notes")).Return(5, nil)
+		notesReader.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
+
+		// second page, stored with the envelope
+		client.EXPECT().ListEntries(ctx, &pb.ListEntriesRequest{PageToken: "key1"}).Return(&pb.ListEntriesResponse{
+			Entries: []*pb.Entry{
+				{Key: "key2", Envelope: []byte("encrypted envelope"), Size: 42},
+			},
+		}, nil)
+		crypt.EXPECT().Decrypt(gomock.Any()).Return(strings.NewReader(`{"name":"name2","type":"card"}`), nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.ListEntries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []entry.Metadata{
+			{Key: "key1", Name: "name1", Notes: "notes"},
+			{Key: "key2", Name: "name2", Type: "card", Size: 42},
+		}, mds)
+	})
+
+	t.Run("error listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListEntries(ctx, &pb.ListEntriesRequest{}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.ListEntries(ctx)
+		require.Error(t, err)
+		require.Nil(t, mds)
+	})
+
+	t.Run("error decrypting notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListEntries(ctx, &pb.ListEntriesRequest{}).Return(&pb.ListEntriesResponse{
+			Entries: []*pb.Entry{
+				{Key: "key1", Name: "name1", Notes: []byte("encrypted notes")},
+			},
+		}, nil)
+		crypt.EXPECT().Decrypt(gomock.Any()).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.ListEntries(ctx)
+		require.Error(t, err)
+		require.Nil(t, mds)
+	})
+
+	t.Run("malformed envelope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListEntries(ctx, &pb.ListEntriesRequest{}).Return(&pb.ListEntriesResponse{
+			Entries: []*pb.Entry{
+				{Key: "key1", Envelope: []byte("encrypted envelope")},
+			},
+		}, nil)
+		crypt.EXPECT().Decrypt(gomock.Any()).Return(strings.NewReader("not json"), nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.ListEntries(ctx)
+		require.Error(t, err)
+		require.Nil(t, mds)
+	})
+}
+
+func TestService_ListChanges(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListChanges(ctx, &pb.ListChangesRequest{SinceCursor: 10}).Return(&pb.ListChangesResponse{
+			Changes: []*pb.Change{
+				{Entry: &pb.Entry{Key: "key1", Name: "name1", Version: 2}, ChangedAt: timestamppb.New(changedAt)},
+			},
+			NextCursor: 11,
+			HasMore:    true,
+		}, nil)
+		client.EXPECT().ListChanges(ctx, &pb.ListChangesRequest{SinceCursor: 11}).Return(&pb.ListChangesResponse{
+			Changes: []*pb.Change{
+				{Entry: &pb.Entry{Key: "key2", Version: 1}, Deleted: true, ChangedAt: timestamppb.New(changedAt)},
+			},
+			NextCursor: 12,
+		}, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		changes, cursor, err := service.ListChanges(ctx, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(12), cursor)
+		require.Equal(t, []entry.Change{
+			{Metadata: entry.Metadata{Key: "key1", Name: "name1", Version: 2}, ChangedAt: changedAt},
+			{Metadata: entry.Metadata{Key: "key2", Version: 1}, Deleted: true, ChangedAt: changedAt},
+		}, changes)
+	})
+
+	t.Run("error listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListChanges(ctx, &pb.ListChangesRequest{}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		changes, _, err := service.ListChanges(ctx, 0)
+		require.Error(t, err)
+		require.Nil(t, changes)
+	})
+}
+
+func TestService_WatchChanges(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Change](ctrl)
+
+		client.EXPECT().WatchEntries(ctx, &pb.WatchEntriesRequest{}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.Change{Entry: &pb.Entry{Key: "key1", Name: "name1", Version: 2}, ChangedAt: timestamppb.New(changedAt), Cursor: 5}, nil)
+		stream.EXPECT().Recv().Return(&pb.Change{Entry: &pb.Entry{Key: "key2", Version: 1}, Deleted: true, ChangedAt: timestamppb.New(changedAt), Cursor: 6}, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Canceled, "canceled"))
+
+		var changes []entry.Change
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.WatchChanges(ctx, func(change entry.Change) {
+			changes = append(changes, change)
+		})
+		require.NoError(t, err)
+		require.Equal(t, []entry.Change{
+			{Metadata: entry.Metadata{Key: "key1", Name: "name1", Version: 2}, ChangedAt: changedAt, Cursor: 5},
+			{Metadata: entry.Metadata{Key: "key2", Version: 1}, Deleted: true, ChangedAt: changedAt, Cursor: 6},
+		}, changes)
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Change](ctrl)
+
+		client.EXPECT().WatchEntries(ctx, &pb.WatchEntriesRequest{}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Aborted, "too many changes"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.WatchChanges(ctx, func(change entry.Change) {})
+		require.ErrorIs(t, err, entry.ErrWatchDropped)
+	})
+
+	t.Run("error receiving", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Change](ctrl)
+
+		client.EXPECT().WatchEntries(ctx, &pb.WatchEntriesRequest{}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Unavailable, "unavailable"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.WatchChanges(ctx, func(change entry.Change) {})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, entry.ErrWatchDropped)
+	})
+
+	t.Run("error starting", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().WatchEntries(ctx, &pb.WatchEntriesRequest{}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.WatchChanges(ctx, func(change entry.Change) {})
+		require.Error(t, err)
+	})
+}
+
+func TestService_ListVersions(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListEntryVersions(ctx, &pb.ListEntryVersionsRequest{Key: "key"}).Return(&pb.ListEntryVersionsResponse{
+			Versions: []*pb.Entry{
+				{Key: "key", Name: "name", Version: 2, Notes: []byte("encrypted notes")},
+				{Key: "key", Name: "name", Version: 1},
+			},
+		}, nil)
+
+		notesReader := mocks.NewMockReadCloser(ctrl)
+		crypt.EXPECT().Decrypt(gomock.Any()).Return(notesReader, nil)
+		notesReader.EXPECT().Read(gomock.Any()).SetArg(0, []byte("notes")).Return(5, nil)
+		notesReader.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.ListVersions(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []entry.Metadata{
+			{Key: "key", Name: "name", Version: 2, Notes: "notes"},
+			{Key: "key", Name: "name", Version: 1},
+		}, mds)
+	})
+
+	t.Run("error listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListEntryVersions(ctx, &pb.ListEntryVersionsRequest{Key: "key"}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		mds, err := service.ListVersions(ctx, "key")
+		require.Error(t, err)
+		require.Nil(t, mds)
+	})
+}
+
+func TestService_GetVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+
+		client.EXPECT().GetEntryVersion(ctx, &pb.GetEntryVersionRequest{
+			Key:     "key",
+			Version: 2,
+		}).Return(stream, nil)
+
+		// receive metadata
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Key:     "key",
+			Name:    "name",
+			Version: 2,
+		}, nil)
+
+		// receive content
+		blobWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(&pb.Entry{
+			Content: []byte("encrypted content"),
+		}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+		blobWriter.EXPECT().Write([]byte("encrypted content")).Return(16, nil)
+		blobWriter.EXPECT().Close().Return(nil)
+
+		// wrap in decrypt
+		blobReader := mocks.NewMockReadCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(blobReader, true, nil)
+		decryptReader := mocks.NewMockReadCloser(ctrl)
+		crypt.EXPECT().Decrypt(blobReader).Return(decryptReader, nil)
+
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		md, content, found, err := service.GetVersion(ctx, "key", 2)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, entry.Metadata{Key: "key", Name: "name", Version: 2}, md)
+		require.NotNil(t, content)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+
+		client.EXPECT().GetEntryVersion(ctx, &pb.GetEntryVersionRequest{
+			Key:     "key",
+			Version: 2,
+		}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "not found"))
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		_, content, found, err := service.GetVersion(ctx, "key", 2)
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Nil(t, content)
+	})
+}
+
+func TestService_RestoreVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().RestoreEntryVersion(ctx, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1}).Return(nil, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.RestoreVersion(ctx, "key", 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().RestoreEntryVersion(ctx, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1}).Return(nil, status.Error(codes.NotFound, "not found"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.RestoreVersion(ctx, "key", 1)
+		require.ErrorIs(t, err, entry.ErrVersionNotFound)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().RestoreEntryVersion(ctx, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.RestoreVersion(ctx, "key", 1)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, entry.ErrVersionNotFound)
+	})
+}
+
+// abortingWriter drops the content on Abort instead of storing it, like the file blob writer.
+type abortingWriter struct {
+	*mocks.MockWriteCloser
+	aborted bool
+}
+
+func (w *abortingWriter) Abort() error {
+	w.aborted = true
+
+	return nil
+}
+
+// checksum returns the SHA-256 checksum of the content.
+func checksum(content string) []byte {
+	sum := sha256.Sum256([]byte(content))
+
+	return sum[:]
+}
+
+func TestService_GetUsage(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().GetUsage(ctx, &pb.GetUsageRequest{}).Return(&pb.Usage{Bytes: 5, Entries: 1, MaxBytes: 10, MaxEntries: 2}, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		usage, err := service.GetUsage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, entry.Usage{Bytes: 5, Entries: 1, MaxBytes: 10, MaxEntries: 2}, usage)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().GetUsage(ctx, &pb.GetUsageRequest{}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		_, err := service.GetUsage(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestService_ListTrash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		client.EXPECT().ListTrash(ctx, &pb.ListTrashRequest{}).Return(&pb.ListTrashResponse{
+			Entries: []*pb.TrashedEntry{
+				{
+					Entry:     &pb.Entry{Key: "key", Name: "name", Type: "login", Version: 2},
+					DeletedAt: timestamppb.New(deletedAt),
+					ExpiresAt: timestamppb.New(deletedAt.Add(time.Hour)),
+				},
+			},
+		}, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		items, err := service.ListTrash(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []entry.TrashItem{
+			{
+				Metadata:  entry.Metadata{Key: "key", Name: "name", Type: "login", Version: 2},
+				DeletedAt: deletedAt,
+				ExpiresAt: deletedAt.Add(time.Hour),
+			},
+		}, items)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListTrash(ctx, &pb.ListTrashRequest{}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		items, err := service.ListTrash(ctx)
+		require.Error(t, err)
+		require.Nil(t, items)
+	})
+}
+
+func TestService_RestoreEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().RestoreEntry(ctx, &pb.RestoreEntryRequest{Key: "key"}).Return(nil, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.RestoreEntry(ctx, "key")
+		require.NoError(t, err)
+	})
+
+	for code, expected := range map[codes.Code]error{
+		codes.NotFound:          entry.ErrEntryNotFound,
+		codes.AlreadyExists:     entry.ErrEntryExists,
+		codes.ResourceExhausted: entry.ErrQuotaExceeded,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			crypt := mocks.NewMockCrypt(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+			client := mocks.NewMockEntryServiceClient(ctrl)
+
+			client.EXPECT().RestoreEntry(ctx, &pb.RestoreEntryRequest{Key: "key"}).Return(nil, status.Error(code, "error"))
+
+			service := entry.New(crypt, client, blobRepo, chunkSize)
+			err := service.RestoreEntry(ctx, "key")
+			require.ErrorIs(t, err, expected)
+		})
+	}
+}
+
+func TestService_PurgeEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().PurgeEntry(ctx, &pb.PurgeEntryRequest{Key: "key"}).Return(nil, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.PurgeEntry(ctx, "key")
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().PurgeEntry(ctx, &pb.PurgeEntryRequest{Key: "key"}).Return(nil, status.Error(codes.NotFound, "not found"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.PurgeEntry(ctx, "key")
+		require.ErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().PurgeEntry(ctx, &pb.PurgeEntryRequest{Key: "key"}).Return(nil, errors.New("error"))
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		err := service.PurgeEntry(ctx, "key")
 		require.Error(t, err)
+		require.NotErrorIs(t, err, entry.ErrEntryNotFound)
 	})
 }
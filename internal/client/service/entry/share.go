@@ -0,0 +1,445 @@
+package entry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+)
+
+// sharedEnvelope holds the metadata of a shared entry, it's sent to the server encrypted to the recipient's key pair.
+type sharedEnvelope struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Notes string `json:"notes,omitempty"`
+}
+
+// GetKeyPair retrieves the user's key pair from the server. If the user has none yet, a new one is generated
+// and published, with the private key encrypted with the secret, so the user can receive shared entries from now on.
+func (s *service) GetKeyPair(ctx context.Context) (KeyPair, error) {
+	resp, err := s.client.GetKeyPair(ctx, &pb.GetKeyPairRequest{})
+	if err == nil {
+		privateKey, err := s.decryptBytes(resp.EncryptedPrivateKey)
+		if err != nil {
+			return KeyPair{}, fmt.Errorf("error decrypting private key: %w", err)
+		}
+
+		return KeyPair{PublicKey: resp.PublicKey, PrivateKey: string(privateKey)}, nil
+	}
+
+	if stErr, ok := status.FromError(err); !ok || stErr.Code() != codes.NotFound {
+		return KeyPair{}, fmt.Errorf("cant get key pair: %w", err)
+	}
+
+	s.log.Debug("generating key pair")
+
+	publicKey, privateKey, err := s.crypt.GenerateKeyPair()
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("error generating key pair: %w", err)
+	}
+
+	encPrivateKey, err := s.encryptBytes([]byte(privateKey))
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("error encrypting private key: %w", err)
+	}
+
+	_, err = s.client.SetKeyPair(ctx, &pb.SetKeyPairRequest{
+		KeyPair: &pb.KeyPair{
+			PublicKey:           publicKey,
+			EncryptedPrivateKey: encPrivateKey,
+		},
+	})
+	if err != nil {
+		// another device published one in the meantime, it must be used instead
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.AlreadyExists {
+			return s.GetKeyPair(ctx)
+		}
+
+		return KeyPair{}, fmt.Errorf("cant set key pair: %w", err)
+	}
+
+	return KeyPair{PublicKey: publicKey, PrivateKey: privateKey}, nil
+}
+
+// GetPublicKey retrieves the public key of the user with the login. Returns ErrRecipientNotFound if there is no such user,
+// or the user has no key pair yet.
+func (s *service) GetPublicKey(ctx context.Context, login string) (string, error) {
+	resp, err := s.client.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
+			return "", ErrRecipientNotFound
+		}
+
+		return "", fmt.Errorf("cant get public key of the recipient: %w", err)
+	}
+
+	return resp.PublicKey, nil
+}
+
+// ShareEntry downloads and decrypts the entry, encrypts it and its metadata to the recipient's public key and uploads the copy.
+// The user's own key pair is set up first if needed, so the recipient can share entries back.
+func (s *service) ShareEntry(ctx context.Context, key string, recipient string) (Share, error) {
+	llog := s.log.WithLazy("key", key, "recipient", recipient, "method", "ShareEntry")
+
+	own, err := s.GetKeyPair(ctx)
+	if err != nil {
+		return Share{}, err
+	}
+
+	publicKey, err := s.GetPublicKey(ctx, recipient)
+	if err != nil {
+		return Share{}, err
+	}
+
+	if publicKey == own.PublicKey {
+		return Share{}, ErrShareWithSelf
+	}
+
+	encryptToRecipient := func(dst io.Writer) (io.WriteCloser, error) {
+		return s.crypt.EncryptTo(publicKey, dst)
+	}
+
+	llog.Debug("getting entry")
+	md, content, ok, err := s.GetEntry(ctx, key)
+	if err != nil {
+		return Share{}, fmt.Errorf("error getting entry: %w", err)
+	}
+	if !ok {
+		return Share{}, ErrEntryNotFound
+	}
+
+	blobKey := getShareBlobKey(key)
+
+	llog.Debug("encrypting copy")
+	err = s.encryptBlob(ctx, content, blobKey, encryptToRecipient)
+	if err != nil {
+		return Share{}, fmt.Errorf("error encrypting copy: %w", err)
+	}
+
+	plain, err := json.Marshal(sharedEnvelope{Name: md.Name, Type: md.Type, Notes: md.Notes})
+	if err != nil {
+		return Share{}, fmt.Errorf("could not marshal envelope: %w", err)
+	}
+
+	env, err := encryptBytesWith(plain, encryptToRecipient)
+	if err != nil {
+		return Share{}, fmt.Errorf("error encrypting envelope: %w", err)
+	}
+
+	llog.Debug("uploading copy")
+	share, err := s.uploadShare(ctx, blobKey, &pb.ShareEntryRequest{
+		RecipientLogin: recipient,
+		Key:            key,
+		Envelope:       env,
+	})
+	if err != nil {
+		stErr, ok := status.FromError(err)
+		if ok && stErr.Code() == codes.NotFound {
+			// the recipient was just found, so it's the entry that was deleted in the meantime
+			return Share{}, ErrEntryNotFound
+		}
+
+		if ok && stErr.Code() == codes.ResourceExhausted {
+			return Share{}, ErrQuotaExceeded
+		}
+
+		return Share{}, fmt.Errorf("error uploading copy: %w", err)
+	}
+
+	result := s.toShare(share)
+	result.Metadata.Name = md.Name
+	result.Metadata.Type = md.Type
+	result.Metadata.Notes = md.Notes
+
+	return result, nil
+}
+
+// uploadShare sends the share and then the encrypted copy from the blob to the server in one stream.
+func (s *service) uploadShare(ctx context.Context, blobKey string, request *pb.ShareEntryRequest) (*pb.Share, error) {
+	blob, ok, err := s.blobRepo.OpenBlobReader(ctx, blobKey)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing reader for the encrypted blob: %w", err)
+	}
+	if !ok {
+		return nil, errors.New("encrypted blob wasn't found")
+	}
+	defer utils.CloseAndLogError(blob, s.log)
+
+	stream, err := s.client.ShareEntry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant start streaming encrypted blob to server: %w", err)
+	}
+
+	err = stream.Send(request)
+	if err != nil {
+		return nil, streamError(stream, fmt.Errorf("error sending share: %w", err))
+	}
+
+	buffer := make([]byte, s.chunkSize)
+
+	for {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("uploading was interrupted: %w", ctx.Err())
+		}
+
+		n, err := blob.Read(buffer)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading encrypted blob chunk: %w", err)
+		}
+
+		err = stream.Send(&pb.ShareEntryRequest{
+			Content: buffer[:n],
+		})
+		if err != nil {
+			return nil, streamError(stream, fmt.Errorf("error sending encrypted blob chunk to server: %w", err))
+		}
+	}
+
+	share, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("server rejected the share: %w", err)
+	}
+
+	return share, nil
+}
+
+// ListShares retrieves the shares of the user. Incoming shares have the metadata decrypted with the user's private key.
+// The metadata of outgoing shares can't be decrypted, it's taken from the shared entries instead, if they still exist.
+func (s *service) ListShares(ctx context.Context, incoming bool) ([]Share, error) {
+	resp, err := s.client.ListShares(ctx, &pb.ListSharesRequest{Incoming: incoming})
+	if err != nil {
+		return nil, fmt.Errorf("cant list shares: %w", err)
+	}
+
+	shares := make([]Share, 0, len(resp.Shares))
+
+	if incoming {
+		// it's also where a new user gets the key pair, so others can share with them
+		own, err := s.GetKeyPair(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, share := range resp.Shares {
+			result, err := s.toIncomingShare(share, own.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+
+			shares = append(shares, result)
+		}
+
+		return shares, nil
+	}
+
+	keys := make([]string, 0, len(resp.Shares))
+	for _, share := range resp.Shares {
+		keys = append(keys, share.Key)
+	}
+
+	mds, err := s.GetMetadata(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]Metadata, len(mds))
+	for _, md := range mds {
+		byKey[md.Key] = md
+	}
+
+	for _, share := range resp.Shares {
+		result := s.toShare(share)
+
+		if md, ok := byKey[share.Key]; ok {
+			result.Metadata.Name = md.Name
+			result.Metadata.Type = md.Type
+			result.Metadata.Notes = md.Notes
+		}
+
+		shares = append(shares, result)
+	}
+
+	return shares, nil
+}
+
+// GetSharedEntry downloads the copy shared with the user and decrypts it and its metadata with the user's private key.
+func (s *service) GetSharedEntry(ctx context.Context, id string) (Share, io.ReadCloser, bool, error) {
+	own, err := s.GetKeyPair(ctx)
+	if err != nil {
+		return Share{}, nil, false, err
+	}
+
+	stream, err := s.client.GetSharedEntry(ctx, &pb.GetSharedEntryRequest{ShareId: id})
+	if err != nil {
+		return Share{}, nil, false, fmt.Errorf("cant start downloading shared entry: %w", err)
+	}
+	defer func() {
+		err := stream.CloseSend()
+		if err != nil {
+			s.log.Errorw("error closing stream", "err", err)
+		}
+	}()
+
+	resp, err := stream.Recv()
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
+			return Share{}, nil, false, nil
+		}
+
+		return Share{}, nil, false, fmt.Errorf("error getting share: %w", err)
+	}
+
+	share, err := s.toIncomingShare(resp.Share, own.PrivateKey)
+	if err != nil {
+		return Share{}, nil, false, err
+	}
+
+	content, err := s.downloadShare(ctx, getSharedBlobKey(id), resp.Share.Checksum, stream)
+	if err != nil {
+		return Share{}, nil, false, fmt.Errorf("error downloading shared entry: %w", err)
+	}
+
+	decr, err := s.crypt.DecryptWith(own.PrivateKey, content)
+	if err != nil {
+		utils.CloseAndLogError(content, s.log)
+
+		return Share{}, nil, false, fmt.Errorf("could not create decrypt reader: %w", err)
+	}
+
+	return share, &combinedRC{reader: decr, closer: content}, true, nil
+}
+
+// downloadShare writes the content received from the stream to the temporary blob and verifies it against the checksum.
+func (s *service) downloadShare(ctx context.Context, blobKey string, checksum []byte, stream grpc.ServerStreamingClient[pb.SharedEntry]) (io.ReadCloser, error) {
+	dst, err := s.blobRepo.OpenBlobWriter(ctx, blobKey)
+	if err != nil {
+		return nil, fmt.Errorf("cant create blob to temporary store shared entry: %w", err)
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			s.discardBlob(ctx, dst, blobKey)
+		}
+	}()
+
+	hash := sha256.New()
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading shared entry: %w", err)
+		}
+
+		_, err = dst.Write(resp.Content)
+		if err != nil {
+			return nil, fmt.Errorf("error writing shared entry: %w", err)
+		}
+
+		// hash.Hash never returns an error
+		hash.Write(resp.Content)
+	}
+
+	if !bytes.Equal(hash.Sum(nil), checksum) {
+		return nil, ErrChecksumMismatch
+	}
+
+	completed = true
+
+	err = dst.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing temporary blob: %w", err)
+	}
+
+	reader, exists, err := s.blobRepo.OpenBlobReader(ctx, blobKey)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing reader for the encrypted blob: %w", err)
+	}
+	if !exists {
+		return nil, errors.New("encrypted blob that we've just written wasn't found")
+	}
+
+	return reader, nil
+}
+
+// RevokeShare asks the server to delete the copy shared with the recipient.
+func (s *service) RevokeShare(ctx context.Context, key string, recipient string) error {
+	_, err := s.client.RevokeShare(ctx, &pb.RevokeShareRequest{Key: key, RecipientLogin: recipient})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
+			return ErrShareNotFound
+		}
+
+		return fmt.Errorf("cant revoke share: %w", err)
+	}
+
+	return nil
+}
+
+// toShare converts the share received from the server, leaving the encrypted metadata out.
+func (s *service) toShare(share *pb.Share) Share {
+	result := Share{
+		ID:        share.Id,
+		Owner:     share.OwnerLogin,
+		Recipient: share.RecipientLogin,
+		Metadata: Metadata{
+			Key:      share.Key,
+			Size:     share.Size,
+			Checksum: share.Checksum,
+		},
+	}
+
+	if share.CreatedAt != nil {
+		result.CreatedAt = share.CreatedAt.AsTime()
+	}
+
+	return result
+}
+
+// toIncomingShare converts the share received from the server, decrypting the metadata with the private key.
+func (s *service) toIncomingShare(share *pb.Share, privateKey string) (Share, error) {
+	result := s.toShare(share)
+
+	dec, err := s.crypt.DecryptWith(privateKey, bytes.NewReader(share.Envelope))
+	if err != nil {
+		return Share{}, fmt.Errorf("could not create decrypt reader for share %s: %w", share.Id, err)
+	}
+
+	var env sharedEnvelope
+	err = json.NewDecoder(dec).Decode(&env)
+	if err != nil {
+		return Share{}, fmt.Errorf("error decrypting envelope of share %s: %w", share.Id, err)
+	}
+
+	result.Metadata.Name = env.Name
+	result.Metadata.Type = env.Type
+	result.Metadata.Notes = env.Notes
+
+	return result, nil
+}
+
+// getShareBlobKey returns the key of the temporary blob for the copy of the entry being shared.
+func getShareBlobKey(key string) string {
+	return "shares/" + key
+}
+
+// getSharedBlobKey returns the key of the temporary blob for the copy shared with the user.
+func getSharedBlobKey(id string) string {
+	return "shared/" + id
+}
@@ -0,0 +1,407 @@
+package entry_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+)
+
+func TestService_ShareEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+		client.EXPECT().GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: "bob"}).Return(&pb.PublicKey{Login: "bob", PublicKey: "bob public"}, nil)
+
+		// get entry
+		entryStream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{Key: "key"}).Return(entryStream, nil)
+		entryStream.EXPECT().Recv().Return(&pb.Entry{Key: "key", Name: "github", Type: "login"}, nil)
+		entryStream.EXPECT().Recv().Return(&pb.Entry{Content: []byte("encrypted content")}, nil)
+		entryStream.EXPECT().Recv().Return(nil, io.EOF)
+		entryStream.EXPECT().CloseSend().Return(nil)
+
+		entryWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "key").Return(entryWriter, nil)
+		entryWriter.EXPECT().Write([]byte("encrypted content")).Return(17, nil)
+		entryWriter.EXPECT().Close().Return(nil)
+
+		entryReader := mocks.NewMockReadCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "key").Return(entryReader, true, nil)
+		crypt.EXPECT().Decrypt(entryReader).Return(strings.NewReader("content"), nil)
+		entryReader.EXPECT().Close().Return(nil)
+
+		// encrypt copy to the recipient
+		copyWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "shares/key").Return(copyWriter, nil)
+		copyEncrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("bob public", copyWriter).Return(copyEncrypter, nil)
+		copyEncrypter.EXPECT().Write([]byte("content")).Return(7, nil)
+		copyEncrypter.EXPECT().Close().Return(nil)
+		copyWriter.EXPECT().Close().Return(nil)
+
+		// encrypt envelope to the recipient
+		envelopeEncrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("bob public", gomock.Any()).Return(envelopeEncrypter, nil)
+		envelopeEncrypter.EXPECT().Write([]byte(`{"name":"github","type":"login"}`)).Return(32, nil)
+		envelopeEncrypter.EXPECT().Close().Return(nil)
+
+		// upload copy
+		copyReader := mocks.NewMockReadCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "shares/key").Return(copyReader, true, nil)
+		copyReader.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted copy")).Return(14, nil)
+		copyReader.EXPECT().Read(gomock.Any()).Return(0, io.EOF)
+		copyReader.EXPECT().Close().Return(nil)
+
+		shareStream := mocks.NewMockClientStreamingClient[pb.ShareEntryRequest, pb.Share](ctrl)
+		client.EXPECT().ShareEntry(ctx).Return(shareStream, nil)
+		// encrypters will return nil bytes
+		shareStream.EXPECT().Send(&pb.ShareEntryRequest{RecipientLogin: "bob", Key: "key"}).Return(nil)
+		shareStream.EXPECT().Send(&pb.ShareEntryRequest{Content: []byte("encrypted copy")}).Return(nil)
+
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		shareStream.EXPECT().CloseAndRecv().Return(&pb.Share{
+			Id:             "share",
+			OwnerLogin:     "alice",
+			RecipientLogin: "bob",
+			Key:            "key",
+			Size:           14,
+			CreatedAt:      timestamppb.New(createdAt),
+		}, nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		share, err := service.ShareEntry(ctx, "key", "bob")
+		require.NoError(t, err)
+		require.Equal(t, entry.Share{
+			ID:        "share",
+			Owner:     "alice",
+			Recipient: "bob",
+			Metadata:  entry.Metadata{Key: "key", Name: "github", Type: "login", Size: 14},
+			CreatedAt: createdAt,
+		}, share)
+	})
+
+	t.Run("recipient not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+		client.EXPECT().GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: "bob"}).Return(nil, status.Error(codes.NotFound, "not found"))
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		_, err := service.ShareEntry(ctx, "key", "bob")
+		require.ErrorIs(t, err, entry.ErrRecipientNotFound)
+	})
+
+	t.Run("share with self", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+		client.EXPECT().GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: "alice"}).Return(&pb.PublicKey{Login: "alice", PublicKey: "own public"}, nil)
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		_, err := service.ShareEntry(ctx, "key", "alice")
+		require.ErrorIs(t, err, entry.ErrShareWithSelf)
+	})
+
+	t.Run("entry not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+		client.EXPECT().GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: "bob"}).Return(&pb.PublicKey{Login: "bob", PublicKey: "bob public"}, nil)
+
+		entryStream := mocks.NewMockServerStreamingClient[pb.Entry](ctrl)
+		client.EXPECT().GetEntry(ctx, &pb.GetEntryRequest{Key: "key"}).Return(entryStream, nil)
+		entryStream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "not found"))
+		entryStream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		_, err := service.ShareEntry(ctx, "key", "bob")
+		require.ErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+}
+
+func TestService_ListShares(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("incoming", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListShares(ctx, &pb.ListSharesRequest{Incoming: true}).Return(&pb.ListSharesResponse{
+			Shares: []*pb.Share{
+				{Id: "share", OwnerLogin: "alice", RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope"), Size: 14},
+			},
+		}, nil)
+		expectKeyPair(ctx, client, crypt)
+		crypt.EXPECT().DecryptWith("private", gomock.Any()).Return(strings.NewReader(`{"name":"github","type":"login","notes":"notes"}`), nil)
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		shares, err := service.ListShares(ctx, true)
+		require.NoError(t, err)
+		require.Equal(t, []entry.Share{
+			{
+				ID:        "share",
+				Owner:     "alice",
+				Recipient: "bob",
+				Metadata:  entry.Metadata{Key: "key", Name: "github", Type: "login", Notes: "notes", Size: 14},
+			},
+		}, shares)
+	})
+
+	t.Run("outgoing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListShares(ctx, &pb.ListSharesRequest{}).Return(&pb.ListSharesResponse{
+			Shares: []*pb.Share{
+				{Id: "share", OwnerLogin: "alice", RecipientLogin: "bob", Key: "key"},
+				{Id: "gone", OwnerLogin: "alice", RecipientLogin: "bob", Key: "deleted"},
+			},
+		}, nil)
+		client.EXPECT().BatchGetMetadata(ctx, &pb.BatchGetMetadataRequest{Keys: []string{"key", "deleted"}}).Return(&pb.BatchGetMetadataResponse{
+			Entries: []*pb.Entry{{Key: "key", Name: "github", Type: "login"}},
+		}, nil)
+
+		service := entry.New(mocks.NewMockCrypt(ctrl), client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		shares, err := service.ListShares(ctx, false)
+		require.NoError(t, err)
+		require.Equal(t, []entry.Share{
+			{ID: "share", Owner: "alice", Recipient: "bob", Metadata: entry.Metadata{Key: "key", Name: "github", Type: "login"}},
+			{ID: "gone", Owner: "alice", Recipient: "bob", Metadata: entry.Metadata{Key: "deleted"}},
+		}, shares)
+	})
+
+	t.Run("generates key pair", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListShares(ctx, &pb.ListSharesRequest{Incoming: true}).Return(&pb.ListSharesResponse{}, nil)
+		client.EXPECT().GetKeyPair(ctx, &pb.GetKeyPairRequest{}).Return(nil, status.Error(codes.NotFound, "not found"))
+		crypt.EXPECT().GenerateKeyPair().Return("public", "private", nil)
+
+		privateKeyEncrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().Encrypt(gomock.Any()).Return(privateKeyEncrypter, nil)
+		privateKeyEncrypter.EXPECT().Write([]byte("private")).Return(7, nil)
+		privateKeyEncrypter.EXPECT().Close().Return(nil)
+
+		// encrypter will return nil bytes
+		client.EXPECT().SetKeyPair(ctx, &pb.SetKeyPairRequest{KeyPair: &pb.KeyPair{PublicKey: "public"}}).Return(&emptypb.Empty{}, nil)
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		shares, err := service.ListShares(ctx, true)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+	})
+
+	t.Run("key pair set concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+
+		client.EXPECT().ListShares(ctx, &pb.ListSharesRequest{Incoming: true}).Return(&pb.ListSharesResponse{}, nil)
+		client.EXPECT().GetKeyPair(ctx, &pb.GetKeyPairRequest{}).Return(nil, status.Error(codes.NotFound, "not found"))
+		crypt.EXPECT().GenerateKeyPair().Return("public", "private", nil)
+
+		privateKeyEncrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().Encrypt(gomock.Any()).Return(privateKeyEncrypter, nil)
+		privateKeyEncrypter.EXPECT().Write([]byte("private")).Return(7, nil)
+		privateKeyEncrypter.EXPECT().Close().Return(nil)
+
+		client.EXPECT().SetKeyPair(ctx, gomock.Any()).Return(nil, status.Error(codes.AlreadyExists, "exists"))
+		// the published one is used instead
+		expectKeyPair(ctx, client, crypt)
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		_, err := service.ListShares(ctx, true)
+		require.NoError(t, err)
+	})
+}
+
+func TestService_GetSharedEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.SharedEntry](ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+
+		client.EXPECT().GetSharedEntry(ctx, &pb.GetSharedEntryRequest{ShareId: "share"}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.SharedEntry{
+			Share: &pb.Share{Id: "share", OwnerLogin: "alice", Key: "key", Envelope: []byte("envelope"), Size: 14, Checksum: checksum("encrypted copy")},
+		}, nil)
+		crypt.EXPECT().DecryptWith("private", gomock.Any()).Return(strings.NewReader(`{"name":"github","type":"login"}`), nil)
+
+		// receive content
+		blobWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "shared/share").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(&pb.SharedEntry{Content: []byte("encrypted copy")}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+		blobWriter.EXPECT().Write([]byte("encrypted copy")).Return(14, nil)
+		blobWriter.EXPECT().Close().Return(nil)
+		stream.EXPECT().CloseSend().Return(nil)
+
+		// wrap in decrypt
+		blobReader := mocks.NewMockReadCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "shared/share").Return(blobReader, true, nil)
+		crypt.EXPECT().DecryptWith("private", blobReader).Return(strings.NewReader("content"), nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		share, content, found, err := service.GetSharedEntry(ctx, "share")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, entry.Share{
+			ID:       "share",
+			Owner:    "alice",
+			Metadata: entry.Metadata{Key: "key", Name: "github", Type: "login", Size: 14, Checksum: checksum("encrypted copy")},
+		}, share)
+
+		data, err := io.ReadAll(content)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(data))
+
+		blobReader.EXPECT().Close().Return(nil)
+		require.NoError(t, content.Close())
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.SharedEntry](ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+
+		client.EXPECT().GetSharedEntry(ctx, &pb.GetSharedEntryRequest{ShareId: "share"}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(&pb.SharedEntry{
+			Share: &pb.Share{Id: "share", Envelope: []byte("envelope"), Checksum: checksum("encrypted copy")},
+		}, nil)
+		crypt.EXPECT().DecryptWith("private", gomock.Any()).Return(strings.NewReader(`{"name":"github","type":"login"}`), nil)
+
+		blobWriter := mocks.NewMockWriteCloser(ctrl)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "shared/share").Return(blobWriter, nil)
+		stream.EXPECT().Recv().Return(&pb.SharedEntry{Content: []byte("corrupted copy")}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+		blobWriter.EXPECT().Write([]byte("corrupted copy")).Return(14, nil)
+		// the corrupted copy isn't kept
+		blobWriter.EXPECT().Close().Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "shared/share").Return(nil)
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, blobRepo, chunkSize)
+		_, _, _, err := service.GetSharedEntry(ctx, "share")
+		require.ErrorIs(t, err, entry.ErrChecksumMismatch)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		crypt := mocks.NewMockCrypt(ctrl)
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		stream := mocks.NewMockServerStreamingClient[pb.SharedEntry](ctrl)
+
+		expectKeyPair(ctx, client, crypt)
+
+		client.EXPECT().GetSharedEntry(ctx, &pb.GetSharedEntryRequest{ShareId: "share"}).Return(stream, nil)
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.NotFound, "not found"))
+		stream.EXPECT().CloseSend().Return(nil)
+
+		service := entry.New(crypt, client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		_, _, found, err := service.GetSharedEntry(ctx, "share")
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+}
+
+func TestService_RevokeShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		client.EXPECT().RevokeShare(ctx, &pb.RevokeShareRequest{Key: "key", RecipientLogin: "bob"}).Return(&emptypb.Empty{}, nil)
+
+		service := entry.New(mocks.NewMockCrypt(ctrl), client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		err := service.RevokeShare(ctx, "key", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockEntryServiceClient(ctrl)
+		client.EXPECT().RevokeShare(ctx, gomock.Any()).Return(nil, status.Error(codes.NotFound, "not found"))
+
+		service := entry.New(mocks.NewMockCrypt(ctrl), client, mocks.NewMockBlobRepository(ctrl), chunkSize)
+		err := service.RevokeShare(ctx, "key", "bob")
+		require.ErrorIs(t, err, entry.ErrShareNotFound)
+	})
+}
+
+// expectKeyPair expects the user's key pair with "own public" public key and "private" private key to be retrieved.
+func expectKeyPair(ctx context.Context, client *mocks.MockEntryServiceClient, crypt *mocks.MockCrypt) {
+	client.EXPECT().GetKeyPair(ctx, &pb.GetKeyPairRequest{}).Return(&pb.KeyPair{
+		PublicKey:           "own public",
+		EncryptedPrivateKey: []byte("encrypted private"),
+	}, nil)
+	crypt.EXPECT().Decrypt(gomock.Any()).Return(strings.NewReader("private"), nil)
+}
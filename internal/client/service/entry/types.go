@@ -7,22 +7,187 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 )
 
 // ErrEntryExists is returned when an entry with the same key already exists.
 var ErrEntryExists = errors.New("entry already exists")
 
+// ErrEntryNotFound is returned when the entry to change doesn't exist.
+var ErrEntryNotFound = errors.New("entry not found")
+
+// ErrConflict is returned when the entry was changed on the server by someone else, e.g. by another device,
+// so the write was rejected to not lose that change.
+var ErrConflict = errors.New("entry was changed concurrently")
+
+// ErrVersionNotFound is returned when the requested version of an entry doesn't exist.
+var ErrVersionNotFound = errors.New("version not found")
+
+// ErrChecksumMismatch is returned when the downloaded content doesn't match the checksum calculated by the server,
+// i.e. it was corrupted either in the storage or on the way.
+var ErrChecksumMismatch = errors.New("content checksum mismatch")
+
+// ErrQuotaExceeded is returned when the server rejected the write because it would exceed the user's storage limits.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// ErrRecipientNotFound is returned when there is no user to share the entry with,
+// or the user hasn't set up sharing yet, i.e. has no key pair.
+var ErrRecipientNotFound = errors.New("recipient not found")
+
+// ErrShareWithSelf is returned when the entry is shared with its owner.
+var ErrShareWithSelf = errors.New("cant share entry with yourself")
+
+// ErrShareNotFound is returned when the entry isn't shared with the user.
+var ErrShareNotFound = errors.New("share not found")
+
+// Metadata represents the decrypted metadata of an entry.
+type Metadata struct {
+	Key       string    // Key is the unique identifier of the entry.
+	Name      string    // Name is the human-readable name of the entry.
+	Notes     string    // Notes contains decrypted notes of the entry.
+	Type      string    // Type is the entry type, e.g. "login" or "file".
+	CreatedAt time.Time // CreatedAt is the time the entry was first stored. Set by the server.
+	UpdatedAt time.Time // UpdatedAt is the time the entry was last changed. Set by the server.
+	Size      int64     // Size is the size of the encrypted content in bytes. Set by the server.
+	Version   int64     // Version is the revision of the entry, incremented on every change. Set by the server.
+	Checksum  []byte    // Checksum is the SHA-256 of the encrypted content, empty for old entries. Set by the server.
+}
+
+// ErrWatchDropped is returned when the server stopped sending changes because the client didn't keep up with them.
+// The missed changes can be fetched with ListChanges.
+var ErrWatchDropped = errors.New("too many changes, watching stopped")
+
+// Change represents the latest change of an entry made on the server.
+type Change struct {
+	Metadata  Metadata  // Metadata of the entry after the change, with notes decrypted. Only Key and Version are set for deletions.
+	Deleted   bool      // Deleted is set if the entry was deleted.
+	ChangedAt time.Time // ChangedAt is the time of the change.
+	Cursor    int64     // Cursor is the position of the change in the server's change log, usable with ListChanges.
+}
+
+// TrashItem represents a deleted entry kept in the server's trash.
+type TrashItem struct {
+	Metadata  Metadata  // Metadata of the entry at the time it was deleted, with notes decrypted.
+	DeletedAt time.Time // DeletedAt is the time the entry was deleted.
+	ExpiresAt time.Time // ExpiresAt is the time the entry is purged from the trash for good.
+}
+
+// Usage represents the storage used by the user on the server.
+type Usage struct {
+	Bytes      int64 // Bytes is the size of the stored content, including kept versions, the trash and unfinished uploads.
+	Entries    int64 // Entries is the number of the stored entries.
+	MaxBytes   int64 // MaxBytes is the limit of Bytes, zero means unlimited.
+	MaxEntries int64 // MaxEntries is the limit of Entries, zero means unlimited.
+}
+
+// Share represents a copy of an entry shared by its owner with another user.
+// The copy is encrypted to the recipient's key pair, so only the recipient can read it.
+type Share struct {
+	ID        string    // ID identifies the share, it's used to get the shared entry.
+	Owner     string    // Owner is the login of the user who shared the entry.
+	Recipient string    // Recipient is the login of the user the entry is shared with.
+	Metadata  Metadata  // Metadata of the entry. Name, Type and Notes are known to the recipient, and to the owner while the entry exists.
+	CreatedAt time.Time // CreatedAt is the time the entry was last shared.
+}
+
+// KeyPair is the user's key pair for sharing, with the private key decrypted.
+type KeyPair struct {
+	PublicKey  string // PublicKey is published, so others can encrypt to it.
+	PrivateKey string // PrivateKey is stored on the server encrypted with the secret.
+}
+
 // Service defines the interface for managing entries, including creating, retrieving, and deleting them.
 type Service interface {
-	// SetEntry creates or updates an entry with the given key, name, notes, and content.
+	// EntryKey returns the key the entry with the given type and name is stored under on the server.
+	// The key is derived with the vault secret, so the server can't check guesses about the names.
+	EntryKey(entryType string, name string) string
+
+	// SetEntry creates or updates an entry with the key, name, type and notes from the metadata, and the given content.
+	// ErrQuotaExceeded is returned if the server has no room for it.
 	// If the entry already exists, the onOverwrite callback is invoked to determine whether to overwrite it.
-	SetEntry(ctx context.Context, key string, name string, notes string, content io.ReadCloser, onOverwrite func() bool) error
+	// If the metadata has a non-zero Version, e.g. as returned by GetEntry, the entry is overwritten only if it still has that version,
+	// and no callback is invoked. ErrConflict is returned if the entry was changed in the meantime.
+	SetEntry(ctx context.Context, md Metadata, content io.ReadCloser, onOverwrite func() bool) error
+
+	// GetEntry retrieves an entry by its key. It returns the metadata, content, a boolean indicating existence, and an error if any.
+	GetEntry(ctx context.Context, key string) (Metadata, io.ReadCloser, bool, error)
 
-	// GetEntry retrieves an entry by its key. It returns the notes, content, a boolean indicating existence, and an error if any.
-	GetEntry(ctx context.Context, key string) (string, io.ReadCloser, bool, error)
+	// DeleteEntry removes an entry by its key, the server keeps it in the trash for a while. Returns an error if the deletion fails.
+	// If expectedVersion is not zero, the entry is deleted only if it still has that version, otherwise ErrConflict is returned.
+	DeleteEntry(ctx context.Context, key string, expectedVersion int64) error
 
-	// DeleteEntry removes an entry by its key. Returns an error if the deletion fails.
-	DeleteEntry(ctx context.Context, key string) error
+	// RenameEntry moves an entry along with its history to the key, name and type from the metadata on the server, without re-uploading the content.
+	// Returns ErrEntryNotFound if there is no such entry and ErrEntryExists if there is an entry with the new key.
+	// If the metadata has a non-zero Version, the entry is renamed only if it still has that version, otherwise ErrConflict is returned.
+	RenameEntry(ctx context.Context, key string, md Metadata) error
+
+	// DeleteEntries removes the entries with the given keys at once: if any of them can't be deleted, none are.
+	// Missing entries are skipped.
+	DeleteEntries(ctx context.Context, keys []string) error
+
+	// GetMetadata retrieves metadata of the entries with the given keys, with notes decrypted.
+	// Missing entries are omitted, the rest are in the order of keys.
+	GetMetadata(ctx context.Context, keys []string) ([]Metadata, error)
+
+	// ListEntries retrieves metadata of all the entries stored in the cloud, with notes decrypted.
+	ListEntries(ctx context.Context) ([]Metadata, error)
+
+	// ListChanges retrieves the latest change of every entry changed on the server after the cursor, oldest first.
+	// Zero cursor means from the beginning. It returns the changes and the cursor to pass on the next sync.
+	ListChanges(ctx context.Context, cursor int64) ([]Change, int64, error)
+
+	// WatchChanges calls onChange for every change of the entries made on the server, e.g. from another device, as it happens.
+	// It blocks until the context is done, in which case nil is returned, or until an error occurs.
+	// ErrWatchDropped is returned if the server stopped sending changes, the missed ones can be fetched with ListChanges.
+	WatchChanges(ctx context.Context, onChange func(Change)) error
+
+	// ListVersions retrieves metadata of the previous versions of an entry kept by the server, newest first.
+	ListVersions(ctx context.Context, key string) ([]Metadata, error)
+
+	// GetVersion retrieves a previous version of an entry. It returns the metadata, content, a boolean indicating existence, and an error if any.
+	GetVersion(ctx context.Context, key string, version int64) (Metadata, io.ReadCloser, bool, error)
+
+	// RestoreVersion makes a previous version of an entry the current one. The replaced version is kept in the history.
+	// Returns ErrVersionNotFound if there is no such version and ErrConflict if the entry was changed during the restore.
+	RestoreVersion(ctx context.Context, key string, version int64) error
+
+	// GetUsage retrieves the storage used by the user along with the limits set by the server.
+	GetUsage(ctx context.Context) (Usage, error)
+
+	// ListTrash retrieves the deleted entries kept in the trash, most recently deleted first.
+	ListTrash(ctx context.Context) ([]TrashItem, error)
+
+	// RestoreEntry moves a deleted entry back from the trash.
+	// Returns ErrEntryNotFound if the entry isn't in the trash, ErrEntryExists if another entry with the same key was stored since,
+	// and ErrQuotaExceeded if the server has no room for it.
+	RestoreEntry(ctx context.Context, key string) error
+
+	// PurgeEntry deletes an entry from the trash for good. Returns ErrEntryNotFound if the entry isn't in the trash.
+	PurgeEntry(ctx context.Context, key string) error
+
+	// ShareEntry stores a copy of the entry with the given key encrypted to the recipient's key pair, replacing the previous one.
+	// Later changes of the entry aren't shared until it's shared again.
+	// Returns ErrEntryNotFound if there is no such entry, ErrRecipientNotFound if the recipient can't receive it,
+	// ErrShareWithSelf if the recipient is the user, and ErrQuotaExceeded if the server has no room for the copy.
+	ShareEntry(ctx context.Context, key string, recipient string) (Share, error)
+
+	// ListShares retrieves the entries shared with the user if incoming is set, otherwise the ones shared by the user.
+	// Listing the incoming ones sets up the user's key pair if needed, so others can share entries with the user.
+	ListShares(ctx context.Context, incoming bool) ([]Share, error)
+
+	// GetSharedEntry retrieves the entry shared with the user. It returns the share, content, a boolean indicating existence, and an error if any.
+	GetSharedEntry(ctx context.Context, id string) (Share, io.ReadCloser, bool, error)
+
+	// RevokeShare deletes the copy of the entry with the given key shared with the recipient.
+	// Returns ErrShareNotFound if the entry isn't shared with the recipient.
+	RevokeShare(ctx context.Context, key string, recipient string) error
+
+	// GetKeyPair retrieves the user's key pair, generating and publishing a new one if the user has none yet.
+	GetKeyPair(ctx context.Context) (KeyPair, error)
+
+	// GetPublicKey retrieves the public key of the user with the login.
+	// Returns ErrRecipientNotFound if there is no such user or the user has no key pair yet.
+	GetPublicKey(ctx context.Context, login string) (string, error)
 }
 
 // Crypt defines the interface for encryption and decryption operations.
@@ -32,4 +197,16 @@ type Crypt interface {
 
 	// Decrypt creates a reader that decrypts data read from the provided source.
 	Decrypt(src io.Reader) (io.Reader, error)
+
+	// MAC returns a keyed hash of data, which can't be calculated or verified without the key.
+	MAC(data []byte) []byte
+
+	// GenerateKeyPair generates a new key pair for sharing. It returns the public and the private key.
+	GenerateKeyPair() (string, string, error)
+
+	// EncryptTo creates a writer that encrypts data written to it so only the owner of the public key can decrypt it.
+	EncryptTo(publicKey string, dst io.Writer) (io.WriteCloser, error)
+
+	// DecryptWith creates a reader that decrypts data encrypted to the public key of the private key.
+	DecryptWith(privateKey string, src io.Reader) (io.Reader, error)
 }
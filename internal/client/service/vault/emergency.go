@@ -0,0 +1,246 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+// GrantEmergencyAccess decrypts the secret of the vault with the user's key pair, encrypts it to the key pair
+// of the contact and leaves it on the server, which releases it to the contact once the waiting period passes.
+// The own entries are granted with the user's secret instead, the server takes an empty vault ID for them.
+func (s *service) GrantEmergencyAccess(ctx context.Context, nameOrID string, login string, wait time.Duration) (EmergencyGrant, error) {
+	v, err := s.findGrantedVault(ctx, nameOrID)
+	if err != nil {
+		return EmergencyGrant{}, err
+	}
+
+	publicKey, err := s.keys.GetPublicKey(ctx, login)
+	if err != nil {
+		if errors.Is(err, entry.ErrRecipientNotFound) {
+			return EmergencyGrant{}, ErrUserNotFound
+		}
+
+		return EmergencyGrant{}, fmt.Errorf("cant get public key of the user: %w", err)
+	}
+
+	secret, err := s.grantedSecret(ctx, v)
+	if err != nil {
+		return EmergencyGrant{}, err
+	}
+
+	wrapped, err := s.wrapSecret(secret, publicKey)
+	if err != nil {
+		return EmergencyGrant{}, err
+	}
+
+	resp, err := s.client.GrantEmergencyAccess(ctx, &pb.GrantEmergencyAccessRequest{
+		VaultId:    v.Id,
+		Login:      login,
+		WrappedKey: wrapped,
+		Wait:       durationpb.New(wait),
+	})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok {
+			switch stErr.Code() {
+			case codes.NotFound:
+				return EmergencyGrant{}, ErrUserNotFound
+			case codes.AlreadyExists:
+				return EmergencyGrant{}, ErrGrantExists
+			case codes.PermissionDenied:
+				return EmergencyGrant{}, ErrPermissionDenied
+			}
+		}
+
+		return EmergencyGrant{}, fmt.Errorf("cant grant emergency access: %w", err)
+	}
+
+	return toGrant(resp), nil
+}
+
+// RevokeEmergencyAccess removes the contact from the vault or the own entries, the secret encrypted to it is deleted as well.
+func (s *service) RevokeEmergencyAccess(ctx context.Context, nameOrID string, login string) error {
+	v, err := s.findGrantedVault(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.RevokeEmergencyAccess(ctx, &pb.RevokeEmergencyAccessRequest{VaultId: v.Id, Login: login})
+	if err != nil {
+		return fromGrantStatusError(err, "cant revoke emergency access")
+	}
+
+	return nil
+}
+
+// ListEmergencyGrants retrieves the emergency grants, sorted by the vault name.
+func (s *service) ListEmergencyGrants(ctx context.Context, incoming bool) ([]EmergencyGrant, error) {
+	resp, err := s.client.ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: incoming})
+	if err != nil {
+		return nil, fmt.Errorf("cant list emergency grants: %w", err)
+	}
+
+	grants := make([]EmergencyGrant, 0, len(resp.Grants))
+	for _, grant := range resp.Grants {
+		grants = append(grants, toGrant(grant))
+	}
+
+	return grants, nil
+}
+
+// RequestEmergencyAccess requests the access to the vault, requesting it again doesn't restart the waiting period.
+func (s *service) RequestEmergencyAccess(ctx context.Context, nameOrID string) (EmergencyGrant, error) {
+	grant, err := s.findGrant(ctx, nameOrID)
+	if err != nil {
+		return EmergencyGrant{}, err
+	}
+
+	resp, err := s.client.RequestEmergencyAccess(ctx, &pb.RequestEmergencyAccessRequest{VaultId: grant.VaultID})
+	if err != nil {
+		return EmergencyGrant{}, fromGrantStatusError(err, "cant request emergency access")
+	}
+
+	return toGrant(resp), nil
+}
+
+// RejectEmergencyAccess cancels the request of the contact, the contact can request the access again.
+func (s *service) RejectEmergencyAccess(ctx context.Context, nameOrID string, login string) error {
+	v, err := s.findGrantedVault(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.RejectEmergencyAccess(ctx, &pb.RejectEmergencyAccessRequest{VaultId: v.Id, Login: login})
+	if err != nil {
+		return fromGrantStatusError(err, "cant reject emergency access")
+	}
+
+	return nil
+}
+
+// OpenEmergencyAccess checks the waiting period of the request and opens the access on the server.
+// Afterwards the vault is one of the user's and can be used like any other.
+func (s *service) OpenEmergencyAccess(ctx context.Context, nameOrID string) (Vault, error) {
+	grant, err := s.findGrant(ctx, nameOrID)
+	if err != nil {
+		return Vault{}, err
+	}
+
+	if grant.RequestedAt.IsZero() {
+		return Vault{}, ErrNotRequested
+	}
+
+	if time.Now().Before(grant.AvailableAt) {
+		return Vault{}, ErrWaitingPeriod
+	}
+
+	resp, err := s.client.OpenEmergencyAccess(ctx, &pb.OpenEmergencyAccessRequest{VaultId: grant.VaultID})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.FailedPrecondition {
+			// rejected in the meantime
+			return Vault{}, ErrNotRequested
+		}
+
+		return Vault{}, fromGrantStatusError(err, "cant open emergency access")
+	}
+
+	return toVault(resp), nil
+}
+
+// findGrantedVault returns the vault with the name or ID, or an empty one standing for the own entries if nameOrID is empty.
+func (s *service) findGrantedVault(ctx context.Context, nameOrID string) (*pb.Vault, error) {
+	if nameOrID == "" {
+		return &pb.Vault{}, nil
+	}
+
+	return s.findVault(ctx, nameOrID)
+}
+
+// grantedSecret returns the secret of the vault, or the user's own secret if the vault stands for the own entries.
+func (s *service) grantedSecret(ctx context.Context, v *pb.Vault) (string, error) {
+	if v.Id != "" {
+		return s.unwrapSecret(ctx, v.WrappedKey)
+	}
+
+	secret, ok, err := s.secrets.GetSecret(ctx)
+	if err != nil {
+		return "", fmt.Errorf("cant get secret: %w", err)
+	}
+
+	if !ok {
+		return "", ErrSecretNotSet
+	}
+
+	return secret, nil
+}
+
+// findGrant returns the grant of the vault with the ID, or the only one with the name, among the vaults the user is a contact of.
+func (s *service) findGrant(ctx context.Context, nameOrID string) (EmergencyGrant, error) {
+	grants, err := s.ListEmergencyGrants(ctx, true)
+	if err != nil {
+		return EmergencyGrant{}, err
+	}
+
+	var found *EmergencyGrant
+	for i, grant := range grants {
+		if grant.VaultID == nameOrID {
+			return grant, nil
+		}
+
+		if grant.VaultName == nameOrID {
+			if found != nil {
+				return EmergencyGrant{}, ErrAmbiguousName
+			}
+
+			found = &grants[i]
+		}
+	}
+
+	if found == nil {
+		return EmergencyGrant{}, ErrGrantNotFound
+	}
+
+	return *found, nil
+}
+
+// fromGrantStatusError converts the errors of the grant operations on the server to the service ones.
+// The vault is checked beforehand, so a missing one is reported as a missing grant.
+func fromGrantStatusError(err error, msg string) error {
+	if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
+		return ErrGrantNotFound
+	}
+
+	return fromStatusError(err, msg)
+}
+
+func toGrant(grant *pb.EmergencyGrant) EmergencyGrant {
+	result := EmergencyGrant{
+		VaultID:      grant.VaultId,
+		VaultName:    grant.VaultName,
+		ContactLogin: grant.ContactLogin,
+		OwnerLogin:   grant.OwnerLogin,
+		Wait:         grant.Wait.AsDuration(),
+	}
+
+	if grant.RequestedAt != nil {
+		result.RequestedAt = grant.RequestedAt.AsTime()
+	}
+
+	if grant.AvailableAt != nil {
+		result.AvailableAt = grant.AvailableAt.AsTime()
+	}
+
+	if grant.CreatedAt != nil {
+		result.CreatedAt = grant.CreatedAt.AsTime()
+	}
+
+	return result
+}
@@ -0,0 +1,361 @@
+package vault_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/crypt"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+func TestService_GrantEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	vaults := &pb.ListVaultsResponse{Vaults: []*pb.Vault{
+		{Id: "id", Name: "team", Role: "owner", WrappedKey: []byte("wrapped for own")},
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		keys.EXPECT().GetPublicKey(ctx, "bob").Return("bob public", nil)
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+		crypt.EXPECT().DecryptWith("own private", gomock.Any()).Return(strings.NewReader("secret"), nil)
+
+		encrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("bob public", gomock.Any()).Return(encrypter, nil)
+		encrypter.EXPECT().Write([]byte("secret")).Return(6, nil)
+		encrypter.EXPECT().Close().Return(nil)
+
+		client.EXPECT().GrantEmergencyAccess(ctx, gomock.Any()).DoAndReturn(func(_ any, req *pb.GrantEmergencyAccessRequest, _ ...any) (*pb.EmergencyGrant, error) {
+			require.Equal(t, "id", req.VaultId)
+			require.Equal(t, "bob", req.Login)
+			require.Equal(t, 48*time.Hour, req.Wait.AsDuration())
+
+			return &pb.EmergencyGrant{VaultId: "id", VaultName: "team", ContactLogin: "bob", Wait: req.Wait}, nil
+		})
+
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		grant, err := service.GrantEmergencyAccess(ctx, "team", "bob", 48*time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, vault.EmergencyGrant{VaultID: "id", VaultName: "team", ContactLogin: "bob", Wait: 48 * time.Hour}, grant)
+	})
+
+	t.Run("user has no key pair", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		keys.EXPECT().GetPublicKey(ctx, "bob").Return("", entry.ErrRecipientNotFound)
+
+		service := vault.New(client, keys, mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.GrantEmergencyAccess(ctx, "team", "bob", time.Hour)
+		require.ErrorIs(t, err, vault.ErrUserNotFound)
+	})
+
+	t.Run("own entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+		secrets := mocks.NewMockSecretService(ctrl)
+
+		keys.EXPECT().GetPublicKey(ctx, "bob").Return("bob public", nil)
+		secrets.EXPECT().GetSecret(ctx).Return("own secret", true, nil)
+
+		encrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("bob public", gomock.Any()).Return(encrypter, nil)
+		encrypter.EXPECT().Write([]byte("own secret")).Return(10, nil)
+		encrypter.EXPECT().Close().Return(nil)
+
+		client.EXPECT().GrantEmergencyAccess(ctx, gomock.Any()).DoAndReturn(func(_ any, req *pb.GrantEmergencyAccessRequest, _ ...any) (*pb.EmergencyGrant, error) {
+			require.Empty(t, req.VaultId)
+			require.Equal(t, "bob", req.Login)
+
+			return &pb.EmergencyGrant{VaultId: "alice id", VaultName: "alice", ContactLogin: "bob", Wait: req.Wait}, nil
+		})
+
+		service := vault.New(client, keys, crypt, secrets, mocks.NewMockVaultRepository(ctrl))
+		grant, err := service.GrantEmergencyAccess(ctx, "", "bob", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, vault.EmergencyGrant{VaultID: "alice id", VaultName: "alice", ContactLogin: "bob", Wait: time.Hour}, grant)
+	})
+
+	t.Run("own secret not set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		keys := mocks.NewMockVaultKeys(ctrl)
+		secrets := mocks.NewMockSecretService(ctrl)
+
+		keys.EXPECT().GetPublicKey(ctx, "bob").Return("bob public", nil)
+		secrets.EXPECT().GetSecret(ctx).Return("", false, nil)
+
+		service := vault.New(mocks.NewMockVaultServiceClient(ctrl), keys, mocks.NewMockVaultCrypt(ctrl), secrets, mocks.NewMockVaultRepository(ctrl))
+		_, err := service.GrantEmergencyAccess(ctx, "", "bob", time.Hour)
+		require.ErrorIs(t, err, vault.ErrSecretNotSet)
+	})
+}
+
+func TestService_EmergencyAccessToOwnEntries(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ownCrypter, err := crypt.NewAgeCrypter("own secret", "alice id")
+	require.NoError(t, err)
+
+	contactCrypter, err := crypt.NewAgeCrypter("bob secret", "bob id")
+	require.NoError(t, err)
+
+	contactPublic, contactPrivate, err := contactCrypter.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// an entry of the owner, encrypted with the owner's secret
+	var content bytes.Buffer
+	w, err := ownCrypter.Encrypt(&content)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("password"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	// the owner grants the own entries to the contact
+	ownerClient := mocks.NewMockVaultServiceClient(ctrl)
+	ownerKeys := mocks.NewMockVaultKeys(ctrl)
+	secrets := mocks.NewMockSecretService(ctrl)
+
+	ownerKeys.EXPECT().GetPublicKey(ctx, "bob").Return(contactPublic, nil)
+	secrets.EXPECT().GetSecret(ctx).Return("own secret", true, nil)
+
+	var wrapped []byte
+	ownerClient.EXPECT().GrantEmergencyAccess(ctx, gomock.Any()).DoAndReturn(func(_ any, req *pb.GrantEmergencyAccessRequest, _ ...any) (*pb.EmergencyGrant, error) {
+		wrapped = req.WrappedKey
+
+		return &pb.EmergencyGrant{VaultId: "alice id", VaultName: "alice", ContactLogin: "bob", Wait: req.Wait}, nil
+	})
+
+	owner := vault.New(ownerClient, ownerKeys, ownCrypter, secrets, mocks.NewMockVaultRepository(ctrl))
+	_, err = owner.GrantEmergencyAccess(ctx, "", "bob", time.Hour)
+	require.NoError(t, err)
+
+	// the server releases the key to the contact once the waiting period passes
+	released := &pb.Vault{Id: "alice id", Name: "alice", Role: "viewer", Personal: true, WrappedKey: wrapped}
+	requestedAt := time.Now().Add(-2 * time.Hour)
+
+	contactClient := mocks.NewMockVaultServiceClient(ctrl)
+	contactKeys := mocks.NewMockVaultKeys(ctrl)
+
+	contactClient.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(&pb.ListEmergencyGrantsResponse{Grants: []*pb.EmergencyGrant{{
+		VaultId:     "alice id",
+		VaultName:   "alice",
+		Wait:        durationpb.New(time.Hour),
+		RequestedAt: timestamppb.New(requestedAt),
+		AvailableAt: timestamppb.New(requestedAt.Add(time.Hour)),
+	}}}, nil)
+	contactClient.EXPECT().OpenEmergencyAccess(ctx, &pb.OpenEmergencyAccessRequest{VaultId: "alice id"}).Return(released, nil)
+	contactClient.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(&pb.ListVaultsResponse{Vaults: []*pb.Vault{released}}, nil)
+	contactKeys.EXPECT().GetKeyPair(ctx).Return(entry.KeyPair{PublicKey: contactPublic, PrivateKey: contactPrivate}, nil)
+
+	contact := vault.New(contactClient, contactKeys, contactCrypter, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+	_, err = contact.OpenEmergencyAccess(ctx, "alice")
+	require.NoError(t, err)
+
+	active, err := contact.SelectVault(ctx, "alice")
+	require.NoError(t, err)
+
+	// the contact decrypts the entry of the owner with the released secret
+	vaultCrypter, err := crypt.NewAgeCrypter(active.Secret, active.ID)
+	require.NoError(t, err)
+
+	// and finds it under the same key
+	require.Equal(t, ownCrypter.MAC([]byte("login\x00github")), vaultCrypter.MAC([]byte("login\x00github")))
+
+	r, err := vaultCrypter.Decrypt(&content)
+	require.NoError(t, err)
+
+	decrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "password", string(decrypted))
+}
+
+func TestService_RevokeEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	vaults := &pb.ListVaultsResponse{Vaults: []*pb.Vault{{Id: "id", Name: "team"}}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().RevokeEmergencyAccess(ctx, &pb.RevokeEmergencyAccessRequest{VaultId: "id", Login: "bob"}).Return(nil, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.RevokeEmergencyAccess(ctx, "team", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("own entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().RevokeEmergencyAccess(ctx, &pb.RevokeEmergencyAccessRequest{Login: "bob"}).Return(nil, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.RevokeEmergencyAccess(ctx, "", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("not a contact", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().RevokeEmergencyAccess(ctx, gomock.Any()).Return(nil, status.Error(codes.NotFound, "error"))
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.RevokeEmergencyAccess(ctx, "team", "bob")
+		require.ErrorIs(t, err, vault.ErrGrantNotFound)
+	})
+}
+
+func TestService_RequestEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	grants := &pb.ListEmergencyGrantsResponse{Grants: []*pb.EmergencyGrant{
+		{VaultId: "id", VaultName: "team", Wait: durationpb.New(time.Hour)},
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		requestedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(grants, nil)
+		client.EXPECT().RequestEmergencyAccess(ctx, &pb.RequestEmergencyAccessRequest{VaultId: "id"}).Return(&pb.EmergencyGrant{
+			VaultId:     "id",
+			VaultName:   "team",
+			Wait:        durationpb.New(time.Hour),
+			RequestedAt: timestamppb.New(requestedAt),
+			AvailableAt: timestamppb.New(requestedAt.Add(time.Hour)),
+		}, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		grant, err := service.RequestEmergencyAccess(ctx, "team")
+		require.NoError(t, err)
+		require.Equal(t, requestedAt, grant.RequestedAt)
+		require.Equal(t, requestedAt.Add(time.Hour), grant.AvailableAt)
+	})
+
+	t.Run("not a contact", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(grants, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.RequestEmergencyAccess(ctx, "family")
+		require.ErrorIs(t, err, vault.ErrGrantNotFound)
+	})
+}
+
+func TestService_OpenEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	newGrants := func(requestedAt time.Time) *pb.ListEmergencyGrantsResponse {
+		grant := &pb.EmergencyGrant{VaultId: "id", VaultName: "team", Wait: durationpb.New(time.Hour)}
+		if !requestedAt.IsZero() {
+			grant.RequestedAt = timestamppb.New(requestedAt)
+			grant.AvailableAt = timestamppb.New(requestedAt.Add(time.Hour))
+		}
+
+		return &pb.ListEmergencyGrantsResponse{Grants: []*pb.EmergencyGrant{grant}}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(newGrants(time.Now().Add(-2*time.Hour)), nil)
+		client.EXPECT().OpenEmergencyAccess(ctx, &pb.OpenEmergencyAccessRequest{VaultId: "id"}).Return(&pb.Vault{Id: "id", Name: "team", Role: "viewer"}, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		v, err := service.OpenEmergencyAccess(ctx, "team")
+		require.NoError(t, err)
+		require.Equal(t, vault.Vault{ID: "id", Name: "team", Role: vault.RoleViewer}, v)
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(newGrants(time.Time{}), nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.OpenEmergencyAccess(ctx, "team")
+		require.ErrorIs(t, err, vault.ErrNotRequested)
+	})
+
+	t.Run("waiting period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(newGrants(time.Now()), nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.OpenEmergencyAccess(ctx, "id")
+		require.ErrorIs(t, err, vault.ErrWaitingPeriod)
+	})
+
+	t.Run("rejected in the meantime", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListEmergencyGrants(ctx, &pb.ListEmergencyGrantsRequest{Incoming: true}).Return(newGrants(time.Now().Add(-2*time.Hour)), nil)
+		client.EXPECT().OpenEmergencyAccess(ctx, gomock.Any()).Return(nil, status.Error(codes.FailedPrecondition, "error"))
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.OpenEmergencyAccess(ctx, "team")
+		require.ErrorIs(t, err, vault.ErrNotRequested)
+	})
+}
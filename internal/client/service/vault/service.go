@@ -0,0 +1,433 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+// secretSize is the number of random bytes in a vault secret.
+const secretSize = 32
+
+// New creates a new instance of the vault service. The keys and the crypt must be the user's own ones,
+// not the ones of the active vault, as the vault secrets are encrypted to the key pairs of the members.
+// The secrets provide the user's own secret, which is handed to the emergency contacts of the own entries.
+func New(client pb.VaultServiceClient, keys Keys, crypt Crypt, secrets Secrets, repo Repository) Service {
+	return &service{
+		client:  client,
+		keys:    keys,
+		crypt:   crypt,
+		secrets: secrets,
+		repo:    repo,
+	}
+}
+
+type service struct {
+	client  pb.VaultServiceClient
+	keys    Keys
+	crypt   Crypt
+	secrets Secrets
+	repo    Repository
+
+	// selected is the vault chosen with SelectVault, it overrides the stored one
+	selected *ActiveVault
+}
+
+// CreateVault generates a new secret for the vault, encrypts it to the user's key pair and creates the vault on the server.
+func (s *service) CreateVault(ctx context.Context, name string, personal bool) (Vault, error) {
+	own, err := s.keys.GetKeyPair(ctx)
+	if err != nil {
+		return Vault{}, fmt.Errorf("cant get key pair: %w", err)
+	}
+
+	raw := make([]byte, secretSize)
+	// never returns an error
+	_, _ = rand.Read(raw)
+
+	wrapped, err := s.wrapSecret(hex.EncodeToString(raw), own.PublicKey)
+	if err != nil {
+		return Vault{}, err
+	}
+
+	resp, err := s.client.CreateVault(ctx, &pb.CreateVaultRequest{Name: name, WrappedKey: wrapped, Personal: personal})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.ResourceExhausted {
+			return Vault{}, ErrTooManyVaults
+		}
+
+		return Vault{}, fmt.Errorf("cant create vault: %w", err)
+	}
+
+	return toVault(resp), nil
+}
+
+// ListVaults retrieves the vaults the user is a member of, sorted by name.
+func (s *service) ListVaults(ctx context.Context) ([]Vault, error) {
+	resp, err := s.client.ListVaults(ctx, &pb.ListVaultsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cant list vaults: %w", err)
+	}
+
+	vaults := make([]Vault, 0, len(resp.Vaults))
+	for _, v := range resp.Vaults {
+		vaults = append(vaults, toVault(v))
+	}
+
+	return vaults, nil
+}
+
+// RenameVault changes the name of the vault on the server, and of the stored active vault if it's the one.
+func (s *service) RenameVault(ctx context.Context, nameOrID string, name string) error {
+	v, err := s.findVault(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.RenameVault(ctx, &pb.RenameVaultRequest{VaultId: v.Id, Name: name})
+	if err != nil {
+		return fromStatusError(err, "cant rename vault")
+	}
+
+	active, ok, err := s.repo.GetActiveVault(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting active vault: %w", err)
+	}
+
+	if !ok || active.ID != v.Id {
+		return nil
+	}
+
+	active.Name = name
+
+	err = s.repo.SetActiveVault(ctx, active)
+	if err != nil {
+		return fmt.Errorf("error saving active vault: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteVault deletes the vault on the server, and the stored active vault if it's the one.
+func (s *service) DeleteVault(ctx context.Context, nameOrID string) error {
+	v, err := s.findVault(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteVault(ctx, &pb.DeleteVaultRequest{VaultId: v.Id})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.FailedPrecondition {
+			return ErrVaultNotEmpty
+		}
+
+		return fromStatusError(err, "cant delete vault")
+	}
+
+	active, ok, err := s.repo.GetActiveVault(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting active vault: %w", err)
+	}
+
+	if !ok || active.ID != v.Id {
+		return nil
+	}
+
+	err = s.repo.DeleteActiveVault(ctx)
+	if err != nil {
+		return fmt.Errorf("error deleting active vault: %w", err)
+	}
+
+	return nil
+}
+
+// AddMember decrypts the secret of the vault with the user's key pair, encrypts it to the key pair
+// of the invited user and adds the user to the vault with the role.
+func (s *service) AddMember(ctx context.Context, nameOrID string, login string, role string) error {
+	v, err := s.findVault(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := s.keys.GetPublicKey(ctx, login)
+	if err != nil {
+		if errors.Is(err, entry.ErrRecipientNotFound) {
+			return ErrUserNotFound
+		}
+
+		return fmt.Errorf("cant get public key of the user: %w", err)
+	}
+
+	secret, err := s.unwrapSecret(ctx, v.WrappedKey)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := s.wrapSecret(secret, publicKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.AddMember(ctx, &pb.AddMemberRequest{
+		VaultId:    v.Id,
+		Login:      login,
+		Role:       role,
+		WrappedKey: wrapped,
+	})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok {
+			switch stErr.Code() {
+			case codes.NotFound:
+				return ErrUserNotFound
+			case codes.AlreadyExists:
+				return ErrMemberExists
+			case codes.PermissionDenied:
+				return ErrPermissionDenied
+			case codes.FailedPrecondition:
+				return ErrPersonalVault
+			}
+		}
+
+		return fmt.Errorf("cant add member: %w", err)
+	}
+
+	return nil
+}
+
+// ListMembers retrieves the members of the vault, sorted by login.
+func (s *service) ListMembers(ctx context.Context, nameOrID string) ([]Member, error) {
+	v, err := s.findVault(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.ListMembers(ctx, &pb.ListMembersRequest{VaultId: v.Id})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok && stErr.Code() == codes.NotFound {
+			return nil, ErrVaultNotFound
+		}
+
+		return nil, fmt.Errorf("cant list members: %w", err)
+	}
+
+	members := make([]Member, 0, len(resp.Members))
+	for _, m := range resp.Members {
+		member := Member{Login: m.Login, Role: m.Role}
+		if m.AddedAt != nil {
+			member.AddedAt = m.AddedAt.AsTime()
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// RemoveMember removes the user from the vault. Keep in mind the removed user could have saved the secret of the vault.
+func (s *service) RemoveMember(ctx context.Context, nameOrID string, login string) error {
+	v, err := s.findVault(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.RemoveMember(ctx, &pb.RemoveMemberRequest{VaultId: v.Id, Login: login})
+	if err != nil {
+		if stErr, ok := status.FromError(err); ok {
+			switch stErr.Code() {
+			case codes.NotFound:
+				return ErrMemberNotFound
+			case codes.FailedPrecondition:
+				return ErrLastOwner
+			case codes.PermissionDenied:
+				return ErrPermissionDenied
+			}
+		}
+
+		return fmt.Errorf("cant remove member: %w", err)
+	}
+
+	return nil
+}
+
+// UseVault decrypts the secret of the vault with the user's key pair and stores it as the active vault.
+// An empty nameOrID removes the active vault, so the user's own entries are managed again.
+func (s *service) UseVault(ctx context.Context, nameOrID string) (ActiveVault, error) {
+	if nameOrID == "" {
+		err := s.repo.DeleteActiveVault(ctx)
+		if err != nil {
+			return ActiveVault{}, fmt.Errorf("error deleting active vault: %w", err)
+		}
+
+		return ActiveVault{}, nil
+	}
+
+	active, err := s.openVault(ctx, nameOrID)
+	if err != nil {
+		return ActiveVault{}, err
+	}
+
+	err = s.repo.SetActiveVault(ctx, active)
+	if err != nil {
+		return ActiveVault{}, fmt.Errorf("error saving active vault: %w", err)
+	}
+
+	return active, nil
+}
+
+// SelectVault decrypts the secret of the vault with the user's key pair and keeps it in memory as the active vault,
+// the stored one is left as is.
+func (s *service) SelectVault(ctx context.Context, nameOrID string) (ActiveVault, error) {
+	active, err := s.openVault(ctx, nameOrID)
+	if err != nil {
+		return ActiveVault{}, err
+	}
+
+	s.selected = &active
+
+	return active, nil
+}
+
+// GetActiveVault retrieves the vault chosen with SelectVault, or the active vault from the repository.
+func (s *service) GetActiveVault(ctx context.Context) (ActiveVault, bool, error) {
+	if s.selected != nil {
+		return *s.selected, true, nil
+	}
+
+	active, ok, err := s.repo.GetActiveVault(ctx)
+	if err != nil {
+		return ActiveVault{}, false, fmt.Errorf("error getting active vault: %w", err)
+	}
+
+	return active, ok, nil
+}
+
+// openVault finds the vault and decrypts its secret with the user's key pair.
+func (s *service) openVault(ctx context.Context, nameOrID string) (ActiveVault, error) {
+	v, err := s.findVault(ctx, nameOrID)
+	if err != nil {
+		return ActiveVault{}, err
+	}
+
+	secret, err := s.unwrapSecret(ctx, v.WrappedKey)
+	if err != nil {
+		return ActiveVault{}, err
+	}
+
+	return ActiveVault{
+		ID:     v.Id,
+		Name:   v.Name,
+		Role:   v.Role,
+		Secret: secret,
+	}, nil
+}
+
+// fromStatusError converts the errors of checking the access to the vault on the server to the service ones.
+func fromStatusError(err error, msg string) error {
+	if stErr, ok := status.FromError(err); ok {
+		switch stErr.Code() {
+		case codes.NotFound:
+			return ErrVaultNotFound
+		case codes.PermissionDenied:
+			return ErrPermissionDenied
+		}
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// findVault returns the vault with the ID, or the only one with the name, among the vaults the user is a member of.
+func (s *service) findVault(ctx context.Context, nameOrID string) (*pb.Vault, error) {
+	resp, err := s.client.ListVaults(ctx, &pb.ListVaultsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cant list vaults: %w", err)
+	}
+
+	var found *pb.Vault
+	for _, v := range resp.Vaults {
+		if v.Id == nameOrID {
+			return v, nil
+		}
+
+		if v.Name == nameOrID {
+			if found != nil {
+				return nil, ErrAmbiguousName
+			}
+
+			found = v
+		}
+	}
+
+	if found == nil {
+		return nil, ErrVaultNotFound
+	}
+
+	return found, nil
+}
+
+// wrapSecret encrypts the secret of the vault to the public key.
+func (s *service) wrapSecret(secret string, publicKey string) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := s.crypt.EncryptTo(publicKey, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("could not create encrypt writer: %w", err)
+	}
+
+	_, err = w.Write([]byte(secret))
+	if err != nil {
+		_ = w.Close()
+
+		return nil, fmt.Errorf("could not write to encrypt writer: %w", err)
+	}
+
+	// the writer flushes the last chunk on close, so the buffer is complete only after it
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing encrypt writer: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// unwrapSecret decrypts the secret of the vault with the user's private key.
+func (s *service) unwrapSecret(ctx context.Context, wrapped []byte) (string, error) {
+	own, err := s.keys.GetKeyPair(ctx)
+	if err != nil {
+		return "", fmt.Errorf("cant get key pair: %w", err)
+	}
+
+	dec, err := s.crypt.DecryptWith(own.PrivateKey, bytes.NewReader(wrapped))
+	if err != nil {
+		return "", fmt.Errorf("could not create decrypt reader: %w", err)
+	}
+
+	secret, err := io.ReadAll(dec)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting vault secret: %w", err)
+	}
+
+	return string(secret), nil
+}
+
+func toVault(v *pb.Vault) Vault {
+	result := Vault{
+		ID:       v.Id,
+		Name:     v.Name,
+		Role:     v.Role,
+		Personal: v.Personal,
+	}
+
+	if v.CreatedAt != nil {
+		result.CreatedAt = v.CreatedAt.AsTime()
+	}
+
+	return result
+}
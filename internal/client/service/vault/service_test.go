@@ -0,0 +1,455 @@
+package vault_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/client/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+var ownKeys = entry.KeyPair{PublicKey: "own public", PrivateKey: "own private"}
+
+func TestService_CreateVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+
+		encrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("own public", gomock.Any()).Return(encrypter, nil)
+		// the secret is random, 32 bytes in hex
+		encrypter.EXPECT().Write(gomock.Len(64)).Return(64, nil)
+		encrypter.EXPECT().Close().Return(nil)
+
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		client.EXPECT().CreateVault(ctx, gomock.Any()).DoAndReturn(func(_ any, req *pb.CreateVaultRequest, _ ...any) (*pb.Vault, error) {
+			require.Equal(t, "work", req.Name)
+			require.True(t, req.Personal)
+
+			return &pb.Vault{Id: "id", Name: "work", Role: "owner", Personal: true, CreatedAt: timestamppb.New(createdAt)}, nil
+		})
+
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		v, err := service.CreateVault(ctx, "work", true)
+		require.NoError(t, err)
+		require.Equal(t, vault.Vault{ID: "id", Name: "work", Role: vault.RoleOwner, Personal: true, CreatedAt: createdAt}, v)
+	})
+
+	t.Run("key pair error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		keys := mocks.NewMockVaultKeys(ctrl)
+		keys.EXPECT().GetKeyPair(ctx).Return(entry.KeyPair{}, errors.New("error"))
+
+		service := vault.New(mocks.NewMockVaultServiceClient(ctrl), keys, mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.CreateVault(ctx, "team", false)
+		require.Error(t, err)
+	})
+
+	t.Run("too many personal vaults", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+
+		encrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("own public", gomock.Any()).Return(encrypter, nil)
+		encrypter.EXPECT().Write(gomock.Len(64)).Return(64, nil)
+		encrypter.EXPECT().Close().Return(nil)
+
+		client.EXPECT().CreateVault(ctx, gomock.Any()).Return(nil, status.Error(codes.ResourceExhausted, "too many personal vaults"))
+
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.CreateVault(ctx, "work", true)
+		require.ErrorIs(t, err, vault.ErrTooManyVaults)
+	})
+}
+
+func TestService_RenameVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	vaults := &pb.ListVaultsResponse{Vaults: []*pb.Vault{{Id: "id", Name: "work"}}}
+
+	t.Run("active vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		repo := mocks.NewMockVaultRepository(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().RenameVault(ctx, &pb.RenameVaultRequest{VaultId: "id", Name: "job"}).Return(nil, nil)
+		repo.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{ID: "id", Name: "work", Secret: "secret"}, true, nil)
+		repo.EXPECT().SetActiveVault(ctx, vault.ActiveVault{ID: "id", Name: "job", Secret: "secret"}).Return(nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), repo)
+		err := service.RenameVault(ctx, "work", "job")
+		require.NoError(t, err)
+	})
+
+	t.Run("other vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		repo := mocks.NewMockVaultRepository(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().RenameVault(ctx, &pb.RenameVaultRequest{VaultId: "id", Name: "job"}).Return(nil, nil)
+		repo.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{}, false, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), repo)
+		err := service.RenameVault(ctx, "id", "job")
+		require.NoError(t, err)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().RenameVault(ctx, gomock.Any()).Return(nil, status.Error(codes.PermissionDenied, "error"))
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.RenameVault(ctx, "work", "job")
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+}
+
+func TestService_DeleteVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	vaults := &pb.ListVaultsResponse{Vaults: []*pb.Vault{{Id: "id", Name: "work"}}}
+
+	t.Run("active vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		repo := mocks.NewMockVaultRepository(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().DeleteVault(ctx, &pb.DeleteVaultRequest{VaultId: "id"}).Return(nil, nil)
+		repo.EXPECT().GetActiveVault(ctx).Return(vault.ActiveVault{ID: "id"}, true, nil)
+		repo.EXPECT().DeleteActiveVault(ctx).Return(nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), repo)
+		err := service.DeleteVault(ctx, "work")
+		require.NoError(t, err)
+	})
+
+	t.Run("has entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().DeleteVault(ctx, &pb.DeleteVaultRequest{VaultId: "id"}).Return(nil, status.Error(codes.FailedPrecondition, "error"))
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.DeleteVault(ctx, "work")
+		require.ErrorIs(t, err, vault.ErrVaultNotEmpty)
+	})
+}
+
+func TestService_AddMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	vaults := &pb.ListVaultsResponse{Vaults: []*pb.Vault{
+		{Id: "id", Name: "team", Role: "owner", WrappedKey: []byte("wrapped for own")},
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		keys.EXPECT().GetPublicKey(ctx, "bob").Return("bob public", nil)
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+		crypt.EXPECT().DecryptWith("own private", gomock.Any()).Return(strings.NewReader("secret"), nil)
+
+		encrypter := mocks.NewMockWriteCloser(ctrl)
+		crypt.EXPECT().EncryptTo("bob public", gomock.Any()).Return(encrypter, nil)
+		encrypter.EXPECT().Write([]byte("secret")).Return(6, nil)
+		encrypter.EXPECT().Close().Return(nil)
+
+		// encrypter returns nil bytes
+		client.EXPECT().AddMember(ctx, &pb.AddMemberRequest{VaultId: "id", Login: "bob", Role: "editor"}).Return(&pb.Member{}, nil)
+
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.AddMember(ctx, "team", "bob", vault.RoleEditor)
+		require.NoError(t, err)
+	})
+
+	t.Run("vault not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.AddMember(ctx, "other", "bob", vault.RoleEditor)
+		require.ErrorIs(t, err, vault.ErrVaultNotFound)
+	})
+
+	t.Run("user has no key pair", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		keys.EXPECT().GetPublicKey(ctx, "bob").Return("", entry.ErrRecipientNotFound)
+
+		service := vault.New(client, keys, mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.AddMember(ctx, "team", "bob", vault.RoleEditor)
+		require.ErrorIs(t, err, vault.ErrUserNotFound)
+	})
+
+	errorCases := []struct {
+		name string
+		code codes.Code
+		err  error
+	}{
+		{"member exists", codes.AlreadyExists, vault.ErrMemberExists},
+		{"not an owner", codes.PermissionDenied, vault.ErrPermissionDenied},
+		{"user not found", codes.NotFound, vault.ErrUserNotFound},
+		{"personal vault", codes.FailedPrecondition, vault.ErrPersonalVault},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mocks.NewMockVaultServiceClient(ctrl)
+			keys := mocks.NewMockVaultKeys(ctrl)
+			crypt := mocks.NewMockVaultCrypt(ctrl)
+
+			client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+			keys.EXPECT().GetPublicKey(ctx, "bob").Return("bob public", nil)
+			keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+			crypt.EXPECT().DecryptWith("own private", gomock.Any()).Return(strings.NewReader("secret"), nil)
+
+			encrypter := mocks.NewMockWriteCloser(ctrl)
+			crypt.EXPECT().EncryptTo("bob public", gomock.Any()).Return(encrypter, nil)
+			encrypter.EXPECT().Write([]byte("secret")).Return(6, nil)
+			encrypter.EXPECT().Close().Return(nil)
+
+			client.EXPECT().AddMember(ctx, gomock.Any()).Return(nil, status.Error(tc.code, "error"))
+
+			service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+			err := service.AddMember(ctx, "team", "bob", vault.RoleEditor)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestService_ListMembers(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(&pb.ListVaultsResponse{Vaults: []*pb.Vault{{Id: "id", Name: "team"}}}, nil)
+
+		addedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		client.EXPECT().ListMembers(ctx, &pb.ListMembersRequest{VaultId: "id"}).Return(&pb.ListMembersResponse{Members: []*pb.Member{
+			{Login: "alice", Role: "owner", AddedAt: timestamppb.New(addedAt)},
+			{Login: "bob", Role: "viewer", AddedAt: timestamppb.New(addedAt)},
+		}}, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		members, err := service.ListMembers(ctx, "id")
+		require.NoError(t, err)
+		require.Equal(t, []vault.Member{
+			{Login: "alice", Role: vault.RoleOwner, AddedAt: addedAt},
+			{Login: "bob", Role: vault.RoleViewer, AddedAt: addedAt},
+		}, members)
+	})
+
+	t.Run("ambiguous name", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(&pb.ListVaultsResponse{Vaults: []*pb.Vault{
+			{Id: "id", Name: "team"},
+			{Id: "other", Name: "team"},
+		}}, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.ListMembers(ctx, "team")
+		require.ErrorIs(t, err, vault.ErrAmbiguousName)
+	})
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	vaults := &pb.ListVaultsResponse{Vaults: []*pb.Vault{{Id: "id", Name: "team"}}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+		client.EXPECT().RemoveMember(ctx, &pb.RemoveMemberRequest{VaultId: "id", Login: "bob"}).Return(nil, nil)
+
+		service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		err := service.RemoveMember(ctx, "team", "bob")
+		require.NoError(t, err)
+	})
+
+	errorCases := []struct {
+		name string
+		code codes.Code
+		err  error
+	}{
+		{"member not found", codes.NotFound, vault.ErrMemberNotFound},
+		{"last owner", codes.FailedPrecondition, vault.ErrLastOwner},
+		{"not an owner", codes.PermissionDenied, vault.ErrPermissionDenied},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mocks.NewMockVaultServiceClient(ctrl)
+			client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(vaults, nil)
+			client.EXPECT().RemoveMember(ctx, &pb.RemoveMemberRequest{VaultId: "id", Login: "bob"}).Return(nil, status.Error(tc.code, "error"))
+
+			service := vault.New(client, mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+			err := service.RemoveMember(ctx, "team", "bob")
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestService_UseVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+		repo := mocks.NewMockVaultRepository(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(&pb.ListVaultsResponse{Vaults: []*pb.Vault{
+			{Id: "id", Name: "team", Role: "editor", WrappedKey: []byte("wrapped")},
+		}}, nil)
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+		crypt.EXPECT().DecryptWith("own private", gomock.Any()).Return(strings.NewReader("secret"), nil)
+
+		expected := vault.ActiveVault{ID: "id", Name: "team", Role: vault.RoleEditor, Secret: "secret"}
+		repo.EXPECT().SetActiveVault(ctx, expected).Return(nil)
+
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), repo)
+		active, err := service.UseVault(ctx, "team")
+		require.NoError(t, err)
+		require.Equal(t, expected, active)
+	})
+
+	t.Run("own values", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().DeleteActiveVault(ctx).Return(nil)
+
+		service := vault.New(mocks.NewMockVaultServiceClient(ctrl), mocks.NewMockVaultKeys(ctrl), mocks.NewMockVaultCrypt(ctrl), mocks.NewMockSecretService(ctrl), repo)
+		active, err := service.UseVault(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, vault.ActiveVault{}, active)
+	})
+
+	t.Run("selected for the run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(&pb.ListVaultsResponse{Vaults: []*pb.Vault{
+			{Id: "id", Name: "work", Role: "owner", Personal: true, WrappedKey: []byte("wrapped")},
+		}}, nil)
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+		crypt.EXPECT().DecryptWith("own private", gomock.Any()).Return(strings.NewReader("secret"), nil)
+
+		// the stored active vault isn't touched
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		selected, err := service.SelectVault(ctx, "work")
+		require.NoError(t, err)
+
+		active, ok, err := service.GetActiveVault(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, selected, active)
+		require.Equal(t, vault.ActiveVault{ID: "id", Name: "work", Role: vault.RoleOwner, Secret: "secret"}, active)
+	})
+
+	t.Run("cant decrypt secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mocks.NewMockVaultServiceClient(ctrl)
+		keys := mocks.NewMockVaultKeys(ctrl)
+		crypt := mocks.NewMockVaultCrypt(ctrl)
+
+		client.EXPECT().ListVaults(ctx, &pb.ListVaultsRequest{}).Return(&pb.ListVaultsResponse{Vaults: []*pb.Vault{
+			{Id: "id", Name: "team", Role: "editor", WrappedKey: []byte("wrapped")},
+		}}, nil)
+		keys.EXPECT().GetKeyPair(ctx).Return(ownKeys, nil)
+		crypt.EXPECT().DecryptWith("own private", gomock.Any()).Return(nil, errors.New("error"))
+
+		service := vault.New(client, keys, crypt, mocks.NewMockSecretService(ctrl), mocks.NewMockVaultRepository(ctrl))
+		_, err := service.UseVault(ctx, "id")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,202 @@
+// Package vault provides the core business logic for vaults in the client application: team vaults shared
+// with other users and personal ones, each with its own secret. It handles creating vaults, managing their members
+// and switching the active vault, whose entries are managed by the entry service instead of the user's own ones.
+package vault
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+)
+
+// Roles of the vault members. Viewers can read the entries, editors can also change them,
+// and owners can also manage the members.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// ErrVaultNotFound is returned when there is no vault with the name or ID the user is a member of.
+var ErrVaultNotFound = errors.New("vault not found")
+
+// ErrAmbiguousName is returned when the user is a member of several vaults with the same name, the ID must be used instead.
+var ErrAmbiguousName = errors.New("several vaults with this name")
+
+// ErrUserNotFound is returned when there is no user to invite, or the user hasn't set up sharing yet, i.e. has no key pair.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrMemberExists is returned when the invited user is a member of the vault already.
+var ErrMemberExists = errors.New("user is a member already")
+
+// ErrMemberNotFound is returned when the user to remove isn't a member of the vault.
+var ErrMemberNotFound = errors.New("member not found")
+
+// ErrPermissionDenied is returned when the role of the user in the vault doesn't allow the operation.
+var ErrPermissionDenied = errors.New("not allowed by the role in the vault")
+
+// ErrLastOwner is returned when the last owner of the vault is removed from it.
+var ErrLastOwner = errors.New("cant remove the last owner")
+
+// ErrPersonalVault is returned when a member is added to a personal vault.
+var ErrPersonalVault = errors.New("personal vault can't have members")
+
+// ErrSecretNotSet is returned when the own entries are granted to a contact before the user's secret is set.
+var ErrSecretNotSet = errors.New("secret not set")
+
+// ErrTooManyVaults is returned when the user has reached the limit of personal vaults set by the server.
+var ErrTooManyVaults = errors.New("too many personal vaults")
+
+// ErrVaultNotEmpty is returned when a vault with entries, including the trashed ones, is deleted.
+var ErrVaultNotEmpty = errors.New("vault has entries")
+
+// ErrGrantNotFound is returned when the user isn't an emergency contact of the vault.
+var ErrGrantNotFound = errors.New("emergency grant not found")
+
+// ErrGrantExists is returned when the user is an emergency contact of the vault already.
+var ErrGrantExists = errors.New("user is an emergency contact already")
+
+// ErrNotRequested is returned when an emergency access is opened without requesting it first, or after the request was rejected.
+var ErrNotRequested = errors.New("emergency access not requested")
+
+// ErrWaitingPeriod is returned when an emergency access is opened before the waiting period has passed.
+var ErrWaitingPeriod = errors.New("waiting period hasn't passed")
+
+// Vault represents a vault the user is a member of.
+type Vault struct {
+	ID        string    // ID identifies the vault on the server.
+	Name      string    // Name of the vault, it isn't unique.
+	Role      string    // Role of the user in the vault.
+	Personal  bool      // Personal vaults can't have members besides the creator.
+	CreatedAt time.Time // CreatedAt is the time the vault was created.
+}
+
+// Member represents a member of a vault.
+type Member struct {
+	Login   string    // Login of the member.
+	Role    string    // Role of the member in the vault.
+	AddedAt time.Time // AddedAt is the time the member was added to the vault.
+}
+
+// EmergencyGrant represents a trusted contact who can get access to a vault after a waiting period,
+// unless an owner of the vault rejects the request.
+type EmergencyGrant struct {
+	VaultID      string        // VaultID is the ID of the vault.
+	VaultName    string        // VaultName is the name of the vault.
+	ContactLogin string        // ContactLogin is the login of the contact.
+	OwnerLogin   string        // OwnerLogin is the login of the owner who granted the access.
+	Wait         time.Duration // Wait is the time the request must stay unrejected before the access can be opened.
+	RequestedAt  time.Time     // RequestedAt is the time the contact requested the access, zero if not requested.
+	AvailableAt  time.Time     // AvailableAt is the time the contact can open the access, zero if not requested.
+	CreatedAt    time.Time     // CreatedAt is the time the access was granted.
+}
+
+// ActiveVault is the vault whose entries are managed instead of the user's own ones.
+type ActiveVault struct {
+	ID     string `json:"id"`     // ID of the vault.
+	Name   string `json:"name"`   // Name of the vault, for the messages only.
+	Role   string `json:"role"`   // Role of the user in the vault at the time it was selected.
+	Secret string `json:"secret"` // Secret of the vault, the entries are encrypted with it in place of the user's one.
+}
+
+// Service defines the interface for managing vaults.
+type Service interface {
+	// CreateVault creates a vault with a new secret, the user becomes its owner. Personal vaults can't have other members.
+	CreateVault(ctx context.Context, name string, personal bool) (Vault, error)
+
+	// ListVaults retrieves the vaults the user is a member of.
+	ListVaults(ctx context.Context) ([]Vault, error)
+
+	// RenameVault changes the name of the vault with the given name or ID.
+	RenameVault(ctx context.Context, nameOrID string, name string) error
+
+	// DeleteVault deletes the vault with the given name or ID. Returns ErrVaultNotEmpty if the vault still has entries.
+	// If the vault is active, the user's own entries are managed again.
+	DeleteVault(ctx context.Context, nameOrID string) error
+
+	// AddMember gives the user with the login the role in the vault with the given name or ID, along with the secret of the vault
+	// encrypted to the user's key pair. Returns ErrUserNotFound if the user can't be invited, ErrMemberExists if it's a member already
+	// and ErrPersonalVault if the vault is personal.
+	AddMember(ctx context.Context, nameOrID string, login string, role string) error
+
+	// ListMembers retrieves the members of the vault with the given name or ID.
+	ListMembers(ctx context.Context, nameOrID string) ([]Member, error)
+
+	// RemoveMember removes the user with the login from the vault with the given name or ID.
+	// Returns ErrMemberNotFound if it isn't a member and ErrLastOwner if it's the last owner of the vault.
+	RemoveMember(ctx context.Context, nameOrID string, login string) error
+
+	// UseVault makes the vault with the given name or ID active, or the user's own entries if nameOrID is empty.
+	UseVault(ctx context.Context, nameOrID string) (ActiveVault, error)
+
+	// SelectVault makes the vault with the given name or ID active until the application exits, without storing it.
+	SelectVault(ctx context.Context, nameOrID string) (ActiveVault, error)
+
+	// GetActiveVault retrieves the active vault. Returns the vault, a boolean indicating if any vault is active, and an error if any.
+	GetActiveVault(ctx context.Context) (ActiveVault, bool, error)
+
+	// GrantEmergencyAccess makes the user with the login an emergency contact of the vault with the given name or ID,
+	// along with the secret of the vault encrypted to the user's key pair. If nameOrID is empty, the contact gets the user's own
+	// entries along with the user's secret. The contact can open the access after requesting it, once the wait has passed
+	// without a rejection. Returns ErrUserNotFound if the user can't be a contact and ErrGrantExists if it's a contact already.
+	GrantEmergencyAccess(ctx context.Context, nameOrID string, login string, wait time.Duration) (EmergencyGrant, error)
+
+	// RevokeEmergencyAccess removes the user with the login from the emergency contacts of the vault with the given name or ID,
+	// or of the user's own entries if nameOrID is empty.
+	RevokeEmergencyAccess(ctx context.Context, nameOrID string, login string) error
+
+	// ListEmergencyGrants retrieves the emergency contacts of the vaults the user owns,
+	// or the vaults the user is an emergency contact of if incoming is true.
+	ListEmergencyGrants(ctx context.Context, incoming bool) ([]EmergencyGrant, error)
+
+	// RequestEmergencyAccess starts the waiting period for the vault with the given name or ID the user is an emergency contact of.
+	RequestEmergencyAccess(ctx context.Context, nameOrID string) (EmergencyGrant, error)
+
+	// RejectEmergencyAccess cancels the request of the user with the login to the vault with the given name or ID,
+	// or to the user's own entries if nameOrID is empty.
+	RejectEmergencyAccess(ctx context.Context, nameOrID string, login string) error
+
+	// OpenEmergencyAccess makes the user a viewer of the vault with the given name or ID once the waiting period has passed.
+	// Returns ErrNotRequested if the access wasn't requested and ErrWaitingPeriod if it's too early.
+	OpenEmergencyAccess(ctx context.Context, nameOrID string) (Vault, error)
+}
+
+// Keys defines the interface for retrieving the key pairs the vault secrets are encrypted to.
+type Keys interface {
+	// GetKeyPair retrieves the user's key pair, generating and publishing a new one if the user has none yet.
+	GetKeyPair(ctx context.Context) (entry.KeyPair, error)
+
+	// GetPublicKey retrieves the public key of the user with the login.
+	GetPublicKey(ctx context.Context, login string) (string, error)
+}
+
+// Crypt defines the interface for encrypting the vault secrets to the key pairs of the members.
+type Crypt interface {
+	// EncryptTo creates a writer that encrypts data written to it so only the owner of the public key can decrypt it.
+	EncryptTo(publicKey string, dst io.Writer) (io.WriteCloser, error)
+
+	// DecryptWith creates a reader that decrypts data encrypted to the public key of the private key.
+	DecryptWith(privateKey string, src io.Reader) (io.Reader, error)
+}
+
+// Secrets defines the interface for retrieving the user's own secret, the emergency contacts of the own entries get it.
+type Secrets interface {
+	// GetSecret retrieves the user's secret. Returns the secret, a boolean indicating if it's set, and an error if any.
+	GetSecret(ctx context.Context) (string, bool, error)
+}
+
+// Repository defines the interface for storing the active vault.
+type Repository interface {
+	// GetActiveVault retrieves the active vault from the repository.
+	// Returns the vault, a boolean indicating if it exists, and an error if any.
+	GetActiveVault(ctx context.Context) (ActiveVault, bool, error)
+
+	// SetActiveVault stores the active vault in the repository.
+	SetActiveVault(ctx context.Context, active ActiveVault) error
+
+	// DeleteActiveVault removes the active vault from the repository.
+	DeleteActiveVault(ctx context.Context) error
+}
@@ -1,10 +1,13 @@
 // Package keyring provides a repository for securely storing and retrieving
-// sensitive data such as tokens and secrets using the keyring support package.
+// sensitive data such as tokens, secrets and the active vault using the keyring support package.
 package keyring
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
+	"github.com/kuvalkin/gophkeeper/internal/client/service/vault"
 	"github.com/kuvalkin/gophkeeper/internal/client/support/keyring"
 )
 
@@ -46,3 +49,37 @@ func (d *Repository) GetSecret(_ context.Context) (string, bool, error) {
 func (d *Repository) SetSecret(_ context.Context, secret string) error {
 	return keyring.Set("secret", secret)
 }
+
+// GetActiveVault retrieves the stored active vault from the keyring.
+// Returns the vault, a boolean indicating if it exists, and an error if any.
+func (d *Repository) GetActiveVault(_ context.Context) (vault.ActiveVault, bool, error) {
+	value, ok, err := keyring.Get("vault")
+	if err != nil || !ok {
+		return vault.ActiveVault{}, false, err
+	}
+
+	var active vault.ActiveVault
+	err = json.Unmarshal([]byte(value), &active)
+	if err != nil {
+		return vault.ActiveVault{}, false, fmt.Errorf("error unmarshaling active vault: %w", err)
+	}
+
+	return active, true, nil
+}
+
+// SetActiveVault stores the given active vault in the keyring, its secret included.
+// Returns an error if the operation fails.
+func (d *Repository) SetActiveVault(_ context.Context, active vault.ActiveVault) error {
+	value, err := json.Marshal(active)
+	if err != nil {
+		return fmt.Errorf("error marshaling active vault: %w", err)
+	}
+
+	return keyring.Set("vault", string(value))
+}
+
+// DeleteActiveVault removes the stored active vault from the keyring.
+// Returns an error if the operation fails.
+func (d *Repository) DeleteActiveVault(_ context.Context) error {
+	return keyring.Delete("vault")
+}
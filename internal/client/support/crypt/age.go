@@ -2,16 +2,28 @@
 package crypt
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"io"
 
 	"filippo.io/age"
+	"golang.org/x/crypto/scrypt"
 )
 
+// macSaltPrefix separates the MAC key from anything else derived from the same secret.
+const macSaltPrefix = "gophkeeper entry keys\x00"
+
 // NewAgeCrypter creates a new AgeCrypter instance using the provided secret.
-// It initializes both the recipient and identity for encryption and decryption.
+// It initializes both the recipient and identity for encryption and decryption, and derives the MAC key.
+// The MAC key is salted with the ID of the store the entries belong to: the vault ID, or the user ID for the own entries.
+// So the same secret gives different MACs in different stores, while everyone with access to a store gets the same ones.
 // Returns an AgeCrypter or an error if initialization fails.
-func NewAgeCrypter(secret string) (*AgeCrypter, error) {
+func NewAgeCrypter(secret string, storeID string) (*AgeCrypter, error) {
+	if storeID == "" {
+		return nil, fmt.Errorf("store id is empty")
+	}
+
 	recipient, err := age.NewScryptRecipient(secret)
 	if err != nil {
 		return nil, fmt.Errorf("cant create age recipient: %w", err)
@@ -22,9 +34,16 @@ func NewAgeCrypter(secret string) (*AgeCrypter, error) {
 		return nil, fmt.Errorf("cant create age identity: %w", err)
 	}
 
+	// the secret is a passphrase, so it's stretched the same way age does it
+	macKey, err := scrypt.Key([]byte(secret), []byte(macSaltPrefix+storeID), 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cant derive mac key: %w", err)
+	}
+
 	return &AgeCrypter{
 		recipient: recipient,
 		identity:  identity,
+		macKey:    macKey,
 	}, nil
 }
 
@@ -33,6 +52,7 @@ func NewAgeCrypter(secret string) (*AgeCrypter, error) {
 type AgeCrypter struct {
 	recipient age.Recipient
 	identity  age.Identity
+	macKey    []byte
 }
 
 // Encrypt creates an io.WriteCloser that encrypts data written to it
@@ -48,3 +68,47 @@ func (a *AgeCrypter) Encrypt(dst io.Writer) (io.WriteCloser, error) {
 func (a *AgeCrypter) Decrypt(src io.Reader) (io.Reader, error) {
 	return age.Decrypt(src, a.identity)
 }
+
+// MAC returns the HMAC-SHA256 of data keyed with the key derived from the secret.
+// The same data always gets the same MAC, but it can't be calculated without the secret.
+func (a *AgeCrypter) MAC(data []byte) []byte {
+	mac := hmac.New(sha256.New, a.macKey)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// GenerateKeyPair generates a new X25519 key pair for sharing entries with other users.
+// Returns the public key, which is safe to publish, and the private key, which must be kept secret.
+func (a *AgeCrypter) GenerateKeyPair() (string, string, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", "", fmt.Errorf("cant generate identity: %w", err)
+	}
+
+	return identity.Recipient().String(), identity.String(), nil
+}
+
+// EncryptTo creates an io.WriteCloser that encrypts data written to it
+// so it can be decrypted only with the private key of the given public key.
+// Returns an io.WriteCloser or an error if the public key is invalid.
+func (a *AgeCrypter) EncryptTo(publicKey string, dst io.Writer) (io.WriteCloser, error) {
+	recipient, err := age.ParseX25519Recipient(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("cant parse public key: %w", err)
+	}
+
+	return age.Encrypt(dst, recipient)
+}
+
+// DecryptWith creates an io.Reader that decrypts data encrypted with EncryptTo
+// using the given private key.
+// Returns an io.Reader or an error if the private key is invalid or decryption setup fails.
+func (a *AgeCrypter) DecryptWith(privateKey string, src io.Reader) (io.Reader, error) {
+	identity, err := age.ParseX25519Identity(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("cant parse private key: %w", err)
+	}
+
+	return age.Decrypt(src, identity)
+}
@@ -2,6 +2,7 @@ package crypt_test
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,20 +12,26 @@ import (
 
 func Test_NewAgeCrypter(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		crypter, err := crypt.NewAgeCrypter("secret")
+		crypter, err := crypt.NewAgeCrypter("secret", "store")
 		require.NoError(t, err)
 		require.NotNil(t, crypter)
 	})
 
 	t.Run("invalid secret", func(t *testing.T) {
-		crypter, err := crypt.NewAgeCrypter("")
+		crypter, err := crypt.NewAgeCrypter("", "store")
+		require.Error(t, err)
+		require.Nil(t, crypter)
+	})
+
+	t.Run("no store", func(t *testing.T) {
+		crypter, err := crypt.NewAgeCrypter("secret", "")
 		require.Error(t, err)
 		require.Nil(t, crypter)
 	})
 }
 
 func Test_AgeCrypter_Encrypt(t *testing.T) {
-	crypter, err := crypt.NewAgeCrypter("secret")
+	crypter, err := crypt.NewAgeCrypter("secret", "store")
 	require.NoError(t, err)
 
 	dst := bytes.NewBuffer(nil)
@@ -35,7 +42,7 @@ func Test_AgeCrypter_Encrypt(t *testing.T) {
 }
 
 func Test_AgeCrypter_Decrypt(t *testing.T) {
-	crypter, err := crypt.NewAgeCrypter("secret")
+	crypter, err := crypt.NewAgeCrypter("secret", "store")
 	require.NoError(t, err)
 
 	t.Run("empty src", func(t *testing.T) {
@@ -45,3 +52,70 @@ func Test_AgeCrypter_Decrypt(t *testing.T) {
 		require.Nil(t, r)
 	})
 }
+
+func Test_AgeCrypter_MAC(t *testing.T) {
+	crypter, err := crypt.NewAgeCrypter("secret", "store")
+	require.NoError(t, err)
+
+	mac := crypter.MAC([]byte("data"))
+	require.Len(t, mac, 32)
+	require.Equal(t, mac, crypter.MAC([]byte("data")))
+	require.NotEqual(t, mac, crypter.MAC([]byte("other data")))
+
+	other, err := crypt.NewAgeCrypter("other secret", "store")
+	require.NoError(t, err)
+	require.NotEqual(t, mac, other.MAC([]byte("data")))
+
+	otherStore, err := crypt.NewAgeCrypter("secret", "other store")
+	require.NoError(t, err)
+	require.NotEqual(t, mac, otherStore.MAC([]byte("data")))
+
+	sameStore, err := crypt.NewAgeCrypter("secret", "store")
+	require.NoError(t, err)
+	require.Equal(t, mac, sameStore.MAC([]byte("data")))
+}
+
+func Test_AgeCrypter_KeyPair(t *testing.T) {
+	crypter, err := crypt.NewAgeCrypter("secret", "store")
+	require.NoError(t, err)
+
+	publicKey, privateKey, err := crypter.GenerateKeyPair()
+	require.NoError(t, err)
+
+	dst := bytes.NewBuffer(nil)
+	wc, err := crypter.EncryptTo(publicKey, dst)
+	require.NoError(t, err)
+	_, err = wc.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	t.Run("decrypt with private key", func(t *testing.T) {
+		r, err := crypter.DecryptWith(privateKey, bytes.NewReader(dst.Bytes()))
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), data)
+	})
+
+	t.Run("other private key", func(t *testing.T) {
+		_, otherKey, err := crypter.GenerateKeyPair()
+		require.NoError(t, err)
+
+		_, err = crypter.DecryptWith(otherKey, bytes.NewReader(dst.Bytes()))
+		require.Error(t, err)
+	})
+
+	t.Run("secret can't decrypt", func(t *testing.T) {
+		_, err := crypter.Decrypt(bytes.NewReader(dst.Bytes()))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := crypter.EncryptTo("invalid", bytes.NewBuffer(nil))
+		require.Error(t, err)
+
+		_, err = crypter.DecryptWith("invalid", bytes.NewReader(dst.Bytes()))
+		require.Error(t, err)
+	})
+}
@@ -55,6 +55,21 @@ func (mr *MockAuthServiceMockRecorder) AddAuthorizationHeader(ctx any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuthorizationHeader", reflect.TypeOf((*MockAuthService)(nil).AddAuthorizationHeader), ctx)
 }
 
+// GetUserID mocks base method.
+func (m *MockAuthService) GetUserID(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthServiceMockRecorder) GetUserID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuthService)(nil).GetUserID), ctx)
+}
+
 // IsLoggedIn mocks base method.
 func (m *MockAuthService) IsLoggedIn(ctx context.Context) (bool, error) {
 	m.ctrl.T.Helper()
@@ -10,9 +10,11 @@
 package mocks
 
 import (
+	context "context"
 	io "io"
 	reflect "reflect"
 
+	blob "github.com/kuvalkin/gophkeeper/internal/storage/blob"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,23 +43,68 @@ func (m *MockBlobRepository) EXPECT() *MockBlobRepositoryMockRecorder {
 }
 
 // DeleteBlob mocks base method.
-func (m *MockBlobRepository) DeleteBlob(key string) error {
+func (m *MockBlobRepository) DeleteBlob(ctx context.Context, key string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBlob", key)
+	ret := m.ctrl.Call(m, "DeleteBlob", ctx, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBlob indicates an expected call of DeleteBlob.
-func (mr *MockBlobRepositoryMockRecorder) DeleteBlob(key any) *gomock.Call {
+func (mr *MockBlobRepositoryMockRecorder) DeleteBlob(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlob", reflect.TypeOf((*MockBlobRepository)(nil).DeleteBlob), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlob", reflect.TypeOf((*MockBlobRepository)(nil).DeleteBlob), ctx, key)
+}
+
+// GetBlobSize mocks base method.
+func (m *MockBlobRepository) GetBlobSize(ctx context.Context, key string) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlobSize", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBlobSize indicates an expected call of GetBlobSize.
+func (mr *MockBlobRepositoryMockRecorder) GetBlobSize(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlobSize", reflect.TypeOf((*MockBlobRepository)(nil).GetBlobSize), ctx, key)
+}
+
+// MoveBlob mocks base method.
+func (m *MockBlobRepository) MoveBlob(ctx context.Context, srcKey, dstKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveBlob", ctx, srcKey, dstKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveBlob indicates an expected call of MoveBlob.
+func (mr *MockBlobRepositoryMockRecorder) MoveBlob(ctx, srcKey, dstKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveBlob", reflect.TypeOf((*MockBlobRepository)(nil).MoveBlob), ctx, srcKey, dstKey)
+}
+
+// OpenBlobAppender mocks base method.
+func (m *MockBlobRepository) OpenBlobAppender(ctx context.Context, key string) (io.WriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenBlobAppender", ctx, key)
+	ret0, _ := ret[0].(io.WriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenBlobAppender indicates an expected call of OpenBlobAppender.
+func (mr *MockBlobRepositoryMockRecorder) OpenBlobAppender(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlobAppender", reflect.TypeOf((*MockBlobRepository)(nil).OpenBlobAppender), ctx, key)
 }
 
 // OpenBlobReader mocks base method.
-func (m *MockBlobRepository) OpenBlobReader(key string) (io.ReadCloser, bool, error) {
+func (m *MockBlobRepository) OpenBlobReader(ctx context.Context, key string) (io.ReadCloser, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "OpenBlobReader", key)
+	ret := m.ctrl.Call(m, "OpenBlobReader", ctx, key)
 	ret0, _ := ret[0].(io.ReadCloser)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
@@ -65,22 +112,52 @@ func (m *MockBlobRepository) OpenBlobReader(key string) (io.ReadCloser, bool, er
 }
 
 // OpenBlobReader indicates an expected call of OpenBlobReader.
-func (mr *MockBlobRepositoryMockRecorder) OpenBlobReader(key any) *gomock.Call {
+func (mr *MockBlobRepositoryMockRecorder) OpenBlobReader(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlobReader", reflect.TypeOf((*MockBlobRepository)(nil).OpenBlobReader), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlobReader", reflect.TypeOf((*MockBlobRepository)(nil).OpenBlobReader), ctx, key)
+}
+
+// OpenBlobSeeker mocks base method.
+func (m *MockBlobRepository) OpenBlobSeeker(ctx context.Context, key string) (io.ReadSeekCloser, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenBlobSeeker", ctx, key)
+	ret0, _ := ret[0].(io.ReadSeekCloser)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenBlobSeeker indicates an expected call of OpenBlobSeeker.
+func (mr *MockBlobRepositoryMockRecorder) OpenBlobSeeker(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlobSeeker", reflect.TypeOf((*MockBlobRepository)(nil).OpenBlobSeeker), ctx, key)
 }
 
 // OpenBlobWriter mocks base method.
-func (m *MockBlobRepository) OpenBlobWriter(key string) (io.WriteCloser, error) {
+func (m *MockBlobRepository) OpenBlobWriter(ctx context.Context, key string) (io.WriteCloser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "OpenBlobWriter", key)
+	ret := m.ctrl.Call(m, "OpenBlobWriter", ctx, key)
 	ret0, _ := ret[0].(io.WriteCloser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // OpenBlobWriter indicates an expected call of OpenBlobWriter.
-func (mr *MockBlobRepositoryMockRecorder) OpenBlobWriter(key any) *gomock.Call {
+func (mr *MockBlobRepositoryMockRecorder) OpenBlobWriter(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlobWriter", reflect.TypeOf((*MockBlobRepository)(nil).OpenBlobWriter), ctx, key)
+}
+
+// WalkBlobs mocks base method.
+func (m *MockBlobRepository) WalkBlobs(ctx context.Context, fn func(blob.Info) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalkBlobs", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalkBlobs indicates an expected call of WalkBlobs.
+func (mr *MockBlobRepositoryMockRecorder) WalkBlobs(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenBlobWriter", reflect.TypeOf((*MockBlobRepository)(nil).OpenBlobWriter), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalkBlobs", reflect.TypeOf((*MockBlobRepository)(nil).WalkBlobs), ctx, fn)
 }
@@ -16,6 +16,7 @@ import (
 	auth "github.com/kuvalkin/gophkeeper/internal/client/service/auth"
 	entry "github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	secret "github.com/kuvalkin/gophkeeper/internal/client/service/secret"
+	vault "github.com/kuvalkin/gophkeeper/internal/client/service/vault"
 	prompts "github.com/kuvalkin/gophkeeper/internal/client/tui/prompts"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -117,3 +118,18 @@ func (mr *MockContainerMockRecorder) GetSecretService(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretService", reflect.TypeOf((*MockContainer)(nil).GetSecretService), ctx)
 }
+
+// GetVaultService mocks base method.
+func (m *MockContainer) GetVaultService(ctx context.Context) (vault.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultService", ctx)
+	ret0, _ := ret[0].(vault.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultService indicates an expected call of GetVaultService.
+func (mr *MockContainerMockRecorder) GetVaultService(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultService", reflect.TypeOf((*MockContainer)(nil).GetVaultService), ctx)
+}
@@ -55,6 +55,21 @@ func (mr *MockCryptMockRecorder) Decrypt(src any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockCrypt)(nil).Decrypt), src)
 }
 
+// DecryptWith mocks base method.
+func (m *MockCrypt) DecryptWith(privateKey string, src io.Reader) (io.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptWith", privateKey, src)
+	ret0, _ := ret[0].(io.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptWith indicates an expected call of DecryptWith.
+func (mr *MockCryptMockRecorder) DecryptWith(privateKey, src any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptWith", reflect.TypeOf((*MockCrypt)(nil).DecryptWith), privateKey, src)
+}
+
 // Encrypt mocks base method.
 func (m *MockCrypt) Encrypt(dst io.Writer) (io.WriteCloser, error) {
 	m.ctrl.T.Helper()
@@ -69,3 +84,48 @@ func (mr *MockCryptMockRecorder) Encrypt(dst any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockCrypt)(nil).Encrypt), dst)
 }
+
+// EncryptTo mocks base method.
+func (m *MockCrypt) EncryptTo(publicKey string, dst io.Writer) (io.WriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptTo", publicKey, dst)
+	ret0, _ := ret[0].(io.WriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptTo indicates an expected call of EncryptTo.
+func (mr *MockCryptMockRecorder) EncryptTo(publicKey, dst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptTo", reflect.TypeOf((*MockCrypt)(nil).EncryptTo), publicKey, dst)
+}
+
+// GenerateKeyPair mocks base method.
+func (m *MockCrypt) GenerateKeyPair() (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateKeyPair")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateKeyPair indicates an expected call of GenerateKeyPair.
+func (mr *MockCryptMockRecorder) GenerateKeyPair() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateKeyPair", reflect.TypeOf((*MockCrypt)(nil).GenerateKeyPair))
+}
+
+// MAC mocks base method.
+func (m *MockCrypt) MAC(data []byte) []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MAC", data)
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// MAC indicates an expected call of MAC.
+func (mr *MockCryptMockRecorder) MAC(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MAC", reflect.TypeOf((*MockCrypt)(nil).MAC), data)
+}
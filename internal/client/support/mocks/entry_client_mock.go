@@ -43,6 +43,66 @@ func (m *MockEntryServiceClient) EXPECT() *MockEntryServiceClientMockRecorder {
 	return m.recorder
 }
 
+// BatchDeleteEntries mocks base method.
+func (m *MockEntryServiceClient) BatchDeleteEntries(ctx context.Context, in *v1.BatchDeleteEntriesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchDeleteEntries", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDeleteEntries indicates an expected call of BatchDeleteEntries.
+func (mr *MockEntryServiceClientMockRecorder) BatchDeleteEntries(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteEntries", reflect.TypeOf((*MockEntryServiceClient)(nil).BatchDeleteEntries), varargs...)
+}
+
+// BatchGetMetadata mocks base method.
+func (m *MockEntryServiceClient) BatchGetMetadata(ctx context.Context, in *v1.BatchGetMetadataRequest, opts ...grpc.CallOption) (*v1.BatchGetMetadataResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetMetadata", varargs...)
+	ret0, _ := ret[0].(*v1.BatchGetMetadataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetMetadata indicates an expected call of BatchGetMetadata.
+func (mr *MockEntryServiceClientMockRecorder) BatchGetMetadata(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetMetadata", reflect.TypeOf((*MockEntryServiceClient)(nil).BatchGetMetadata), varargs...)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockEntryServiceClient) CreateUploadSession(ctx context.Context, in *v1.CreateUploadSessionRequest, opts ...grpc.CallOption) (*v1.UploadSession, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateUploadSession", varargs...)
+	ret0, _ := ret[0].(*v1.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockEntryServiceClientMockRecorder) CreateUploadSession(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockEntryServiceClient)(nil).CreateUploadSession), varargs...)
+}
+
 // DeleteEntry mocks base method.
 func (m *MockEntryServiceClient) DeleteEntry(ctx context.Context, in *v1.DeleteEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -63,6 +123,26 @@ func (mr *MockEntryServiceClientMockRecorder) DeleteEntry(ctx, in any, opts ...a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).DeleteEntry), varargs...)
 }
 
+// FinalizeUpload mocks base method.
+func (m *MockEntryServiceClient) FinalizeUpload(ctx context.Context, in *v1.FinalizeUploadRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FinalizeUpload", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalizeUpload indicates an expected call of FinalizeUpload.
+func (mr *MockEntryServiceClientMockRecorder) FinalizeUpload(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeUpload", reflect.TypeOf((*MockEntryServiceClient)(nil).FinalizeUpload), varargs...)
+}
+
 // GetEntry mocks base method.
 func (m *MockEntryServiceClient) GetEntry(ctx context.Context, in *v1.GetEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v1.Entry], error) {
 	m.ctrl.T.Helper()
@@ -83,6 +163,326 @@ func (mr *MockEntryServiceClientMockRecorder) GetEntry(ctx, in any, opts ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).GetEntry), varargs...)
 }
 
+// GetEntryVersion mocks base method.
+func (m *MockEntryServiceClient) GetEntryVersion(ctx context.Context, in *v1.GetEntryVersionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v1.Entry], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEntryVersion", varargs...)
+	ret0, _ := ret[0].(grpc.ServerStreamingClient[v1.Entry])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryVersion indicates an expected call of GetEntryVersion.
+func (mr *MockEntryServiceClientMockRecorder) GetEntryVersion(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryVersion", reflect.TypeOf((*MockEntryServiceClient)(nil).GetEntryVersion), varargs...)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockEntryServiceClient) GetKeyPair(ctx context.Context, in *v1.GetKeyPairRequest, opts ...grpc.CallOption) (*v1.KeyPair, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetKeyPair", varargs...)
+	ret0, _ := ret[0].(*v1.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockEntryServiceClientMockRecorder) GetKeyPair(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockEntryServiceClient)(nil).GetKeyPair), varargs...)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockEntryServiceClient) GetPublicKey(ctx context.Context, in *v1.GetPublicKeyRequest, opts ...grpc.CallOption) (*v1.PublicKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPublicKey", varargs...)
+	ret0, _ := ret[0].(*v1.PublicKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockEntryServiceClientMockRecorder) GetPublicKey(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockEntryServiceClient)(nil).GetPublicKey), varargs...)
+}
+
+// GetSharedEntry mocks base method.
+func (m *MockEntryServiceClient) GetSharedEntry(ctx context.Context, in *v1.GetSharedEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v1.SharedEntry], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetSharedEntry", varargs...)
+	ret0, _ := ret[0].(grpc.ServerStreamingClient[v1.SharedEntry])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedEntry indicates an expected call of GetSharedEntry.
+func (mr *MockEntryServiceClientMockRecorder) GetSharedEntry(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).GetSharedEntry), varargs...)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockEntryServiceClient) GetUploadSession(ctx context.Context, in *v1.GetUploadSessionRequest, opts ...grpc.CallOption) (*v1.UploadSession, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUploadSession", varargs...)
+	ret0, _ := ret[0].(*v1.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockEntryServiceClientMockRecorder) GetUploadSession(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockEntryServiceClient)(nil).GetUploadSession), varargs...)
+}
+
+// GetUsage mocks base method.
+func (m *MockEntryServiceClient) GetUsage(ctx context.Context, in *v1.GetUsageRequest, opts ...grpc.CallOption) (*v1.Usage, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUsage", varargs...)
+	ret0, _ := ret[0].(*v1.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockEntryServiceClientMockRecorder) GetUsage(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockEntryServiceClient)(nil).GetUsage), varargs...)
+}
+
+// ListChanges mocks base method.
+func (m *MockEntryServiceClient) ListChanges(ctx context.Context, in *v1.ListChangesRequest, opts ...grpc.CallOption) (*v1.ListChangesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListChanges", varargs...)
+	ret0, _ := ret[0].(*v1.ListChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChanges indicates an expected call of ListChanges.
+func (mr *MockEntryServiceClientMockRecorder) ListChanges(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChanges", reflect.TypeOf((*MockEntryServiceClient)(nil).ListChanges), varargs...)
+}
+
+// ListEntries mocks base method.
+func (m *MockEntryServiceClient) ListEntries(ctx context.Context, in *v1.ListEntriesRequest, opts ...grpc.CallOption) (*v1.ListEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEntries", varargs...)
+	ret0, _ := ret[0].(*v1.ListEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockEntryServiceClientMockRecorder) ListEntries(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockEntryServiceClient)(nil).ListEntries), varargs...)
+}
+
+// ListEntryVersions mocks base method.
+func (m *MockEntryServiceClient) ListEntryVersions(ctx context.Context, in *v1.ListEntryVersionsRequest, opts ...grpc.CallOption) (*v1.ListEntryVersionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEntryVersions", varargs...)
+	ret0, _ := ret[0].(*v1.ListEntryVersionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryVersions indicates an expected call of ListEntryVersions.
+func (mr *MockEntryServiceClientMockRecorder) ListEntryVersions(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryVersions", reflect.TypeOf((*MockEntryServiceClient)(nil).ListEntryVersions), varargs...)
+}
+
+// ListShares mocks base method.
+func (m *MockEntryServiceClient) ListShares(ctx context.Context, in *v1.ListSharesRequest, opts ...grpc.CallOption) (*v1.ListSharesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListShares", varargs...)
+	ret0, _ := ret[0].(*v1.ListSharesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockEntryServiceClientMockRecorder) ListShares(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockEntryServiceClient)(nil).ListShares), varargs...)
+}
+
+// ListTrash mocks base method.
+func (m *MockEntryServiceClient) ListTrash(ctx context.Context, in *v1.ListTrashRequest, opts ...grpc.CallOption) (*v1.ListTrashResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTrash", varargs...)
+	ret0, _ := ret[0].(*v1.ListTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockEntryServiceClientMockRecorder) ListTrash(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockEntryServiceClient)(nil).ListTrash), varargs...)
+}
+
+// PurgeEntry mocks base method.
+func (m *MockEntryServiceClient) PurgeEntry(ctx context.Context, in *v1.PurgeEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PurgeEntry", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeEntry indicates an expected call of PurgeEntry.
+func (mr *MockEntryServiceClientMockRecorder) PurgeEntry(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).PurgeEntry), varargs...)
+}
+
+// RenameEntry mocks base method.
+func (m *MockEntryServiceClient) RenameEntry(ctx context.Context, in *v1.RenameEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RenameEntry", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameEntry indicates an expected call of RenameEntry.
+func (mr *MockEntryServiceClientMockRecorder) RenameEntry(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).RenameEntry), varargs...)
+}
+
+// RestoreEntry mocks base method.
+func (m *MockEntryServiceClient) RestoreEntry(ctx context.Context, in *v1.RestoreEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreEntry", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEntry indicates an expected call of RestoreEntry.
+func (mr *MockEntryServiceClientMockRecorder) RestoreEntry(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).RestoreEntry), varargs...)
+}
+
+// RestoreEntryVersion mocks base method.
+func (m *MockEntryServiceClient) RestoreEntryVersion(ctx context.Context, in *v1.RestoreEntryVersionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreEntryVersion", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEntryVersion indicates an expected call of RestoreEntryVersion.
+func (mr *MockEntryServiceClientMockRecorder) RestoreEntryVersion(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntryVersion", reflect.TypeOf((*MockEntryServiceClient)(nil).RestoreEntryVersion), varargs...)
+}
+
+// RevokeShare mocks base method.
+func (m *MockEntryServiceClient) RevokeShare(ctx context.Context, in *v1.RevokeShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevokeShare", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockEntryServiceClientMockRecorder) RevokeShare(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockEntryServiceClient)(nil).RevokeShare), varargs...)
+}
+
 // SetEntry mocks base method.
 func (m *MockEntryServiceClient) SetEntry(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[v1.SetEntryRequest, v1.SetEntryResponse], error) {
 	m.ctrl.T.Helper()
@@ -102,3 +502,83 @@ func (mr *MockEntryServiceClientMockRecorder) SetEntry(ctx any, opts ...any) *go
 	varargs := append([]any{ctx}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).SetEntry), varargs...)
 }
+
+// SetKeyPair mocks base method.
+func (m *MockEntryServiceClient) SetKeyPair(ctx context.Context, in *v1.SetKeyPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetKeyPair", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKeyPair indicates an expected call of SetKeyPair.
+func (mr *MockEntryServiceClientMockRecorder) SetKeyPair(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyPair", reflect.TypeOf((*MockEntryServiceClient)(nil).SetKeyPair), varargs...)
+}
+
+// ShareEntry mocks base method.
+func (m *MockEntryServiceClient) ShareEntry(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[v1.ShareEntryRequest, v1.Share], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ShareEntry", varargs...)
+	ret0, _ := ret[0].(grpc.ClientStreamingClient[v1.ShareEntryRequest, v1.Share])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareEntry indicates an expected call of ShareEntry.
+func (mr *MockEntryServiceClientMockRecorder) ShareEntry(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareEntry", reflect.TypeOf((*MockEntryServiceClient)(nil).ShareEntry), varargs...)
+}
+
+// UploadChunks mocks base method.
+func (m *MockEntryServiceClient) UploadChunks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[v1.UploadChunkRequest, v1.UploadSession], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadChunks", varargs...)
+	ret0, _ := ret[0].(grpc.ClientStreamingClient[v1.UploadChunkRequest, v1.UploadSession])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadChunks indicates an expected call of UploadChunks.
+func (mr *MockEntryServiceClientMockRecorder) UploadChunks(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadChunks", reflect.TypeOf((*MockEntryServiceClient)(nil).UploadChunks), varargs...)
+}
+
+// WatchEntries mocks base method.
+func (m *MockEntryServiceClient) WatchEntries(ctx context.Context, in *v1.WatchEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v1.Change], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchEntries", varargs...)
+	ret0, _ := ret[0].(grpc.ServerStreamingClient[v1.Change])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchEntries indicates an expected call of WatchEntries.
+func (mr *MockEntryServiceClientMockRecorder) WatchEntries(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchEntries", reflect.TypeOf((*MockEntryServiceClient)(nil).WatchEntries), varargs...)
+}
@@ -14,6 +14,7 @@ import (
 	io "io"
 	reflect "reflect"
 
+	entry "github.com/kuvalkin/gophkeeper/internal/client/service/entry"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,25 +42,53 @@ func (m *MockEntryService) EXPECT() *MockEntryServiceMockRecorder {
 	return m.recorder
 }
 
+// DeleteEntries mocks base method.
+func (m *MockEntryService) DeleteEntries(ctx context.Context, keys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntries", ctx, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEntries indicates an expected call of DeleteEntries.
+func (mr *MockEntryServiceMockRecorder) DeleteEntries(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntries", reflect.TypeOf((*MockEntryService)(nil).DeleteEntries), ctx, keys)
+}
+
 // DeleteEntry mocks base method.
-func (m *MockEntryService) DeleteEntry(ctx context.Context, key string) error {
+func (m *MockEntryService) DeleteEntry(ctx context.Context, key string, expectedVersion int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntry", ctx, key)
+	ret := m.ctrl.Call(m, "DeleteEntry", ctx, key, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEntry indicates an expected call of DeleteEntry.
-func (mr *MockEntryServiceMockRecorder) DeleteEntry(ctx, key any) *gomock.Call {
+func (mr *MockEntryServiceMockRecorder) DeleteEntry(ctx, key, expectedVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockEntryService)(nil).DeleteEntry), ctx, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockEntryService)(nil).DeleteEntry), ctx, key, expectedVersion)
+}
+
+// EntryKey mocks base method.
+func (m *MockEntryService) EntryKey(entryType, name string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EntryKey", entryType, name)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// EntryKey indicates an expected call of EntryKey.
+func (mr *MockEntryServiceMockRecorder) EntryKey(entryType, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EntryKey", reflect.TypeOf((*MockEntryService)(nil).EntryKey), entryType, name)
 }
 
 // GetEntry mocks base method.
-func (m *MockEntryService) GetEntry(ctx context.Context, key string) (string, io.ReadCloser, bool, error) {
+func (m *MockEntryService) GetEntry(ctx context.Context, key string) (entry.Metadata, io.ReadCloser, bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetEntry", ctx, key)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(entry.Metadata)
 	ret1, _ := ret[1].(io.ReadCloser)
 	ret2, _ := ret[2].(bool)
 	ret3, _ := ret[3].(error)
@@ -72,16 +101,285 @@ func (mr *MockEntryServiceMockRecorder) GetEntry(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockEntryService)(nil).GetEntry), ctx, key)
 }
 
+// GetKeyPair mocks base method.
+func (m *MockEntryService) GetKeyPair(ctx context.Context) (entry.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx)
+	ret0, _ := ret[0].(entry.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockEntryServiceMockRecorder) GetKeyPair(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockEntryService)(nil).GetKeyPair), ctx)
+}
+
+// GetMetadata mocks base method.
+func (m *MockEntryService) GetMetadata(ctx context.Context, keys []string) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetadata", ctx, keys)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetadata indicates an expected call of GetMetadata.
+func (mr *MockEntryServiceMockRecorder) GetMetadata(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetadata", reflect.TypeOf((*MockEntryService)(nil).GetMetadata), ctx, keys)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockEntryService) GetPublicKey(ctx context.Context, login string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", ctx, login)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockEntryServiceMockRecorder) GetPublicKey(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockEntryService)(nil).GetPublicKey), ctx, login)
+}
+
+// GetSharedEntry mocks base method.
+func (m *MockEntryService) GetSharedEntry(ctx context.Context, id string) (entry.Share, io.ReadCloser, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedEntry", ctx, id)
+	ret0, _ := ret[0].(entry.Share)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetSharedEntry indicates an expected call of GetSharedEntry.
+func (mr *MockEntryServiceMockRecorder) GetSharedEntry(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedEntry", reflect.TypeOf((*MockEntryService)(nil).GetSharedEntry), ctx, id)
+}
+
+// GetUsage mocks base method.
+func (m *MockEntryService) GetUsage(ctx context.Context) (entry.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx)
+	ret0, _ := ret[0].(entry.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockEntryServiceMockRecorder) GetUsage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockEntryService)(nil).GetUsage), ctx)
+}
+
+// GetVersion mocks base method.
+func (m *MockEntryService) GetVersion(ctx context.Context, key string, version int64) (entry.Metadata, io.ReadCloser, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, key, version)
+	ret0, _ := ret[0].(entry.Metadata)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockEntryServiceMockRecorder) GetVersion(ctx, key, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockEntryService)(nil).GetVersion), ctx, key, version)
+}
+
+// ListChanges mocks base method.
+func (m *MockEntryService) ListChanges(ctx context.Context, cursor int64) ([]entry.Change, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChanges", ctx, cursor)
+	ret0, _ := ret[0].([]entry.Change)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListChanges indicates an expected call of ListChanges.
+func (mr *MockEntryServiceMockRecorder) ListChanges(ctx, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChanges", reflect.TypeOf((*MockEntryService)(nil).ListChanges), ctx, cursor)
+}
+
+// ListEntries mocks base method.
+func (m *MockEntryService) ListEntries(ctx context.Context) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockEntryServiceMockRecorder) ListEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockEntryService)(nil).ListEntries), ctx)
+}
+
+// ListShares mocks base method.
+func (m *MockEntryService) ListShares(ctx context.Context, incoming bool) ([]entry.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, incoming)
+	ret0, _ := ret[0].([]entry.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockEntryServiceMockRecorder) ListShares(ctx, incoming any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockEntryService)(nil).ListShares), ctx, incoming)
+}
+
+// ListTrash mocks base method.
+func (m *MockEntryService) ListTrash(ctx context.Context) ([]entry.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx)
+	ret0, _ := ret[0].([]entry.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockEntryServiceMockRecorder) ListTrash(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockEntryService)(nil).ListTrash), ctx)
+}
+
+// ListVersions mocks base method.
+func (m *MockEntryService) ListVersions(ctx context.Context, key string) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, key)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockEntryServiceMockRecorder) ListVersions(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockEntryService)(nil).ListVersions), ctx, key)
+}
+
+// PurgeEntry mocks base method.
+func (m *MockEntryService) PurgeEntry(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeEntry", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeEntry indicates an expected call of PurgeEntry.
+func (mr *MockEntryServiceMockRecorder) PurgeEntry(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeEntry", reflect.TypeOf((*MockEntryService)(nil).PurgeEntry), ctx, key)
+}
+
+// RenameEntry mocks base method.
+func (m *MockEntryService) RenameEntry(ctx context.Context, key string, md entry.Metadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameEntry", ctx, key, md)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameEntry indicates an expected call of RenameEntry.
+func (mr *MockEntryServiceMockRecorder) RenameEntry(ctx, key, md any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameEntry", reflect.TypeOf((*MockEntryService)(nil).RenameEntry), ctx, key, md)
+}
+
+// RestoreEntry mocks base method.
+func (m *MockEntryService) RestoreEntry(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEntry", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEntry indicates an expected call of RestoreEntry.
+func (mr *MockEntryServiceMockRecorder) RestoreEntry(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntry", reflect.TypeOf((*MockEntryService)(nil).RestoreEntry), ctx, key)
+}
+
+// RestoreVersion mocks base method.
+func (m *MockEntryService) RestoreVersion(ctx context.Context, key string, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVersion", ctx, key, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVersion indicates an expected call of RestoreVersion.
+func (mr *MockEntryServiceMockRecorder) RestoreVersion(ctx, key, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVersion", reflect.TypeOf((*MockEntryService)(nil).RestoreVersion), ctx, key, version)
+}
+
+// RevokeShare mocks base method.
+func (m *MockEntryService) RevokeShare(ctx context.Context, key, recipient string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShare", ctx, key, recipient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockEntryServiceMockRecorder) RevokeShare(ctx, key, recipient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockEntryService)(nil).RevokeShare), ctx, key, recipient)
+}
+
 // SetEntry mocks base method.
-func (m *MockEntryService) SetEntry(ctx context.Context, key, name, notes string, content io.ReadCloser, onOverwrite func() bool) error {
+func (m *MockEntryService) SetEntry(ctx context.Context, md entry.Metadata, content io.ReadCloser, onOverwrite func() bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetEntry", ctx, key, name, notes, content, onOverwrite)
+	ret := m.ctrl.Call(m, "SetEntry", ctx, md, content, onOverwrite)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetEntry indicates an expected call of SetEntry.
-func (mr *MockEntryServiceMockRecorder) SetEntry(ctx, key, name, notes, content, onOverwrite any) *gomock.Call {
+func (mr *MockEntryServiceMockRecorder) SetEntry(ctx, md, content, onOverwrite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntry", reflect.TypeOf((*MockEntryService)(nil).SetEntry), ctx, md, content, onOverwrite)
+}
+
+// ShareEntry mocks base method.
+func (m *MockEntryService) ShareEntry(ctx context.Context, key, recipient string) (entry.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareEntry", ctx, key, recipient)
+	ret0, _ := ret[0].(entry.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareEntry indicates an expected call of ShareEntry.
+func (mr *MockEntryServiceMockRecorder) ShareEntry(ctx, key, recipient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareEntry", reflect.TypeOf((*MockEntryService)(nil).ShareEntry), ctx, key, recipient)
+}
+
+// WatchChanges mocks base method.
+func (m *MockEntryService) WatchChanges(ctx context.Context, onChange func(entry.Change)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchChanges", ctx, onChange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchChanges indicates an expected call of WatchChanges.
+func (mr *MockEntryServiceMockRecorder) WatchChanges(ctx, onChange any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntry", reflect.TypeOf((*MockEntryService)(nil).SetEntry), ctx, key, name, notes, content, onOverwrite)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchChanges", reflect.TypeOf((*MockEntryService)(nil).WatchChanges), ctx, onChange)
 }
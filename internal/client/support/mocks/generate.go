@@ -15,3 +15,9 @@ package mocks
 //go:generate mockgen -destination=./auth_repository_mock.go -package=mocks -mock_names Repository=MockAuthRepository github.com/kuvalkin/gophkeeper/internal/client/service/auth Repository
 //go:generate mockgen -destination=./auth_client_mock.go -package=mocks github.com/kuvalkin/gophkeeper/pkg/proto/auth/v1 AuthServiceClient
 //go:generate mockgen -destination=./secret_repository_mock.go -package=mocks -mock_names Repository=MockSecretRepository github.com/kuvalkin/gophkeeper/internal/client/service/secret Repository
+//go:generate mockgen -destination=./vault_service_mock.go -package=mocks -mock_names Service=MockVaultService github.com/kuvalkin/gophkeeper/internal/client/service/vault Service
+//go:generate mockgen -destination=./vault_repository_mock.go -package=mocks -mock_names Repository=MockVaultRepository github.com/kuvalkin/gophkeeper/internal/client/service/vault Repository
+//go:generate mockgen -destination=./vault_client_mock.go -package=mocks github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1 VaultServiceClient
+//go:generate mockgen -destination=./vault_keys_mock.go -package=mocks -mock_names Keys=MockVaultKeys github.com/kuvalkin/gophkeeper/internal/client/service/vault Keys
+//go:generate mockgen -destination=./vault_crypt_mock.go -package=mocks -mock_names Crypt=MockVaultCrypt github.com/kuvalkin/gophkeeper/internal/client/service/vault Crypt
+//go:generate mockgen -destination=./client_stream_mock.go -package=mocks google.golang.org/grpc ClientStreamingClient
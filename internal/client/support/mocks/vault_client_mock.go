@@ -0,0 +1,304 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1 (interfaces: VaultServiceClient)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_client_mock.go -package=mocks github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1 VaultServiceClient
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+	gomock "go.uber.org/mock/gomock"
+	grpc "google.golang.org/grpc"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// MockVaultServiceClient is a mock of VaultServiceClient interface.
+type MockVaultServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultServiceClientMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultServiceClientMockRecorder is the mock recorder for MockVaultServiceClient.
+type MockVaultServiceClientMockRecorder struct {
+	mock *MockVaultServiceClient
+}
+
+// NewMockVaultServiceClient creates a new mock instance.
+func NewMockVaultServiceClient(ctrl *gomock.Controller) *MockVaultServiceClient {
+	mock := &MockVaultServiceClient{ctrl: ctrl}
+	mock.recorder = &MockVaultServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultServiceClient) EXPECT() *MockVaultServiceClientMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockVaultServiceClient) AddMember(ctx context.Context, in *v1.AddMemberRequest, opts ...grpc.CallOption) (*v1.Member, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddMember", varargs...)
+	ret0, _ := ret[0].(*v1.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockVaultServiceClientMockRecorder) AddMember(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockVaultServiceClient)(nil).AddMember), varargs...)
+}
+
+// CreateVault mocks base method.
+func (m *MockVaultServiceClient) CreateVault(ctx context.Context, in *v1.CreateVaultRequest, opts ...grpc.CallOption) (*v1.Vault, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateVault", varargs...)
+	ret0, _ := ret[0].(*v1.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVault indicates an expected call of CreateVault.
+func (mr *MockVaultServiceClientMockRecorder) CreateVault(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVault", reflect.TypeOf((*MockVaultServiceClient)(nil).CreateVault), varargs...)
+}
+
+// DeleteVault mocks base method.
+func (m *MockVaultServiceClient) DeleteVault(ctx context.Context, in *v1.DeleteVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteVault", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVault indicates an expected call of DeleteVault.
+func (mr *MockVaultServiceClientMockRecorder) DeleteVault(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVault", reflect.TypeOf((*MockVaultServiceClient)(nil).DeleteVault), varargs...)
+}
+
+// GrantEmergencyAccess mocks base method.
+func (m *MockVaultServiceClient) GrantEmergencyAccess(ctx context.Context, in *v1.GrantEmergencyAccessRequest, opts ...grpc.CallOption) (*v1.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GrantEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*v1.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantEmergencyAccess indicates an expected call of GrantEmergencyAccess.
+func (mr *MockVaultServiceClientMockRecorder) GrantEmergencyAccess(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantEmergencyAccess", reflect.TypeOf((*MockVaultServiceClient)(nil).GrantEmergencyAccess), varargs...)
+}
+
+// ListEmergencyGrants mocks base method.
+func (m *MockVaultServiceClient) ListEmergencyGrants(ctx context.Context, in *v1.ListEmergencyGrantsRequest, opts ...grpc.CallOption) (*v1.ListEmergencyGrantsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEmergencyGrants", varargs...)
+	ret0, _ := ret[0].(*v1.ListEmergencyGrantsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyGrants indicates an expected call of ListEmergencyGrants.
+func (mr *MockVaultServiceClientMockRecorder) ListEmergencyGrants(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyGrants", reflect.TypeOf((*MockVaultServiceClient)(nil).ListEmergencyGrants), varargs...)
+}
+
+// ListMembers mocks base method.
+func (m *MockVaultServiceClient) ListMembers(ctx context.Context, in *v1.ListMembersRequest, opts ...grpc.CallOption) (*v1.ListMembersResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListMembers", varargs...)
+	ret0, _ := ret[0].(*v1.ListMembersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockVaultServiceClientMockRecorder) ListMembers(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockVaultServiceClient)(nil).ListMembers), varargs...)
+}
+
+// ListVaults mocks base method.
+func (m *MockVaultServiceClient) ListVaults(ctx context.Context, in *v1.ListVaultsRequest, opts ...grpc.CallOption) (*v1.ListVaultsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListVaults", varargs...)
+	ret0, _ := ret[0].(*v1.ListVaultsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVaults indicates an expected call of ListVaults.
+func (mr *MockVaultServiceClientMockRecorder) ListVaults(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVaults", reflect.TypeOf((*MockVaultServiceClient)(nil).ListVaults), varargs...)
+}
+
+// OpenEmergencyAccess mocks base method.
+func (m *MockVaultServiceClient) OpenEmergencyAccess(ctx context.Context, in *v1.OpenEmergencyAccessRequest, opts ...grpc.CallOption) (*v1.Vault, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OpenEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*v1.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenEmergencyAccess indicates an expected call of OpenEmergencyAccess.
+func (mr *MockVaultServiceClientMockRecorder) OpenEmergencyAccess(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenEmergencyAccess", reflect.TypeOf((*MockVaultServiceClient)(nil).OpenEmergencyAccess), varargs...)
+}
+
+// RejectEmergencyAccess mocks base method.
+func (m *MockVaultServiceClient) RejectEmergencyAccess(ctx context.Context, in *v1.RejectEmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RejectEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectEmergencyAccess indicates an expected call of RejectEmergencyAccess.
+func (mr *MockVaultServiceClientMockRecorder) RejectEmergencyAccess(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectEmergencyAccess", reflect.TypeOf((*MockVaultServiceClient)(nil).RejectEmergencyAccess), varargs...)
+}
+
+// RemoveMember mocks base method.
+func (m *MockVaultServiceClient) RemoveMember(ctx context.Context, in *v1.RemoveMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveMember", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockVaultServiceClientMockRecorder) RemoveMember(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockVaultServiceClient)(nil).RemoveMember), varargs...)
+}
+
+// RenameVault mocks base method.
+func (m *MockVaultServiceClient) RenameVault(ctx context.Context, in *v1.RenameVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RenameVault", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameVault indicates an expected call of RenameVault.
+func (mr *MockVaultServiceClientMockRecorder) RenameVault(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameVault", reflect.TypeOf((*MockVaultServiceClient)(nil).RenameVault), varargs...)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockVaultServiceClient) RequestEmergencyAccess(ctx context.Context, in *v1.RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*v1.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*v1.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockVaultServiceClientMockRecorder) RequestEmergencyAccess(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockVaultServiceClient)(nil).RequestEmergencyAccess), varargs...)
+}
+
+// RevokeEmergencyAccess mocks base method.
+func (m *MockVaultServiceClient) RevokeEmergencyAccess(ctx context.Context, in *v1.RevokeEmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevokeEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeEmergencyAccess indicates an expected call of RevokeEmergencyAccess.
+func (mr *MockVaultServiceClientMockRecorder) RevokeEmergencyAccess(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeEmergencyAccess", reflect.TypeOf((*MockVaultServiceClient)(nil).RevokeEmergencyAccess), varargs...)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/internal/client/service/vault (interfaces: Crypt)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_crypt_mock.go -package=mocks -mock_names Crypt=MockVaultCrypt github.com/kuvalkin/gophkeeper/internal/client/service/vault Crypt
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVaultCrypt is a mock of Crypt interface.
+type MockVaultCrypt struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultCryptMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultCryptMockRecorder is the mock recorder for MockVaultCrypt.
+type MockVaultCryptMockRecorder struct {
+	mock *MockVaultCrypt
+}
+
+// NewMockVaultCrypt creates a new mock instance.
+func NewMockVaultCrypt(ctrl *gomock.Controller) *MockVaultCrypt {
+	mock := &MockVaultCrypt{ctrl: ctrl}
+	mock.recorder = &MockVaultCryptMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultCrypt) EXPECT() *MockVaultCryptMockRecorder {
+	return m.recorder
+}
+
+// DecryptWith mocks base method.
+func (m *MockVaultCrypt) DecryptWith(privateKey string, src io.Reader) (io.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptWith", privateKey, src)
+	ret0, _ := ret[0].(io.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptWith indicates an expected call of DecryptWith.
+func (mr *MockVaultCryptMockRecorder) DecryptWith(privateKey, src any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptWith", reflect.TypeOf((*MockVaultCrypt)(nil).DecryptWith), privateKey, src)
+}
+
+// EncryptTo mocks base method.
+func (m *MockVaultCrypt) EncryptTo(publicKey string, dst io.Writer) (io.WriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptTo", publicKey, dst)
+	ret0, _ := ret[0].(io.WriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptTo indicates an expected call of EncryptTo.
+func (mr *MockVaultCryptMockRecorder) EncryptTo(publicKey, dst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptTo", reflect.TypeOf((*MockVaultCrypt)(nil).EncryptTo), publicKey, dst)
+}
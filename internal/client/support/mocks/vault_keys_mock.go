@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/internal/client/service/vault (interfaces: Keys)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_keys_mock.go -package=mocks -mock_names Keys=MockVaultKeys github.com/kuvalkin/gophkeeper/internal/client/service/vault Keys
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entry "github.com/kuvalkin/gophkeeper/internal/client/service/entry"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVaultKeys is a mock of Keys interface.
+type MockVaultKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultKeysMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultKeysMockRecorder is the mock recorder for MockVaultKeys.
+type MockVaultKeysMockRecorder struct {
+	mock *MockVaultKeys
+}
+
+// NewMockVaultKeys creates a new mock instance.
+func NewMockVaultKeys(ctrl *gomock.Controller) *MockVaultKeys {
+	mock := &MockVaultKeys{ctrl: ctrl}
+	mock.recorder = &MockVaultKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultKeys) EXPECT() *MockVaultKeysMockRecorder {
+	return m.recorder
+}
+
+// GetKeyPair mocks base method.
+func (m *MockVaultKeys) GetKeyPair(ctx context.Context) (entry.KeyPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx)
+	ret0, _ := ret[0].(entry.KeyPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockVaultKeysMockRecorder) GetKeyPair(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockVaultKeys)(nil).GetKeyPair), ctx)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockVaultKeys) GetPublicKey(ctx context.Context, login string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", ctx, login)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockVaultKeysMockRecorder) GetPublicKey(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockVaultKeys)(nil).GetPublicKey), ctx, login)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/internal/client/service/vault (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_repository_mock.go -package=mocks -mock_names Repository=MockVaultRepository github.com/kuvalkin/gophkeeper/internal/client/service/vault Repository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	vault "github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVaultRepository is a mock of Repository interface.
+type MockVaultRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultRepositoryMockRecorder is the mock recorder for MockVaultRepository.
+type MockVaultRepositoryMockRecorder struct {
+	mock *MockVaultRepository
+}
+
+// NewMockVaultRepository creates a new mock instance.
+func NewMockVaultRepository(ctrl *gomock.Controller) *MockVaultRepository {
+	mock := &MockVaultRepository{ctrl: ctrl}
+	mock.recorder = &MockVaultRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultRepository) EXPECT() *MockVaultRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteActiveVault mocks base method.
+func (m *MockVaultRepository) DeleteActiveVault(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteActiveVault", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteActiveVault indicates an expected call of DeleteActiveVault.
+func (mr *MockVaultRepositoryMockRecorder) DeleteActiveVault(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActiveVault", reflect.TypeOf((*MockVaultRepository)(nil).DeleteActiveVault), ctx)
+}
+
+// GetActiveVault mocks base method.
+func (m *MockVaultRepository) GetActiveVault(ctx context.Context) (vault.ActiveVault, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveVault", ctx)
+	ret0, _ := ret[0].(vault.ActiveVault)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActiveVault indicates an expected call of GetActiveVault.
+func (mr *MockVaultRepositoryMockRecorder) GetActiveVault(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveVault", reflect.TypeOf((*MockVaultRepository)(nil).GetActiveVault), ctx)
+}
+
+// SetActiveVault mocks base method.
+func (m *MockVaultRepository) SetActiveVault(ctx context.Context, active vault.ActiveVault) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActiveVault", ctx, active)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActiveVault indicates an expected call of SetActiveVault.
+func (mr *MockVaultRepositoryMockRecorder) SetActiveVault(ctx, active any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActiveVault", reflect.TypeOf((*MockVaultRepository)(nil).SetActiveVault), ctx, active)
+}
@@ -0,0 +1,278 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/internal/client/service/vault (interfaces: Service)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_service_mock.go -package=mocks -mock_names Service=MockVaultService github.com/kuvalkin/gophkeeper/internal/client/service/vault Service
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	vault "github.com/kuvalkin/gophkeeper/internal/client/service/vault"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVaultService is a mock of Service interface.
+type MockVaultService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultServiceMockRecorder is the mock recorder for MockVaultService.
+type MockVaultServiceMockRecorder struct {
+	mock *MockVaultService
+}
+
+// NewMockVaultService creates a new mock instance.
+func NewMockVaultService(ctrl *gomock.Controller) *MockVaultService {
+	mock := &MockVaultService{ctrl: ctrl}
+	mock.recorder = &MockVaultServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultService) EXPECT() *MockVaultServiceMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockVaultService) AddMember(ctx context.Context, nameOrID, login, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, nameOrID, login, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockVaultServiceMockRecorder) AddMember(ctx, nameOrID, login, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockVaultService)(nil).AddMember), ctx, nameOrID, login, role)
+}
+
+// CreateVault mocks base method.
+func (m *MockVaultService) CreateVault(ctx context.Context, name string, personal bool) (vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVault", ctx, name, personal)
+	ret0, _ := ret[0].(vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVault indicates an expected call of CreateVault.
+func (mr *MockVaultServiceMockRecorder) CreateVault(ctx, name, personal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVault", reflect.TypeOf((*MockVaultService)(nil).CreateVault), ctx, name, personal)
+}
+
+// DeleteVault mocks base method.
+func (m *MockVaultService) DeleteVault(ctx context.Context, nameOrID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVault", ctx, nameOrID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVault indicates an expected call of DeleteVault.
+func (mr *MockVaultServiceMockRecorder) DeleteVault(ctx, nameOrID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVault", reflect.TypeOf((*MockVaultService)(nil).DeleteVault), ctx, nameOrID)
+}
+
+// GetActiveVault mocks base method.
+func (m *MockVaultService) GetActiveVault(ctx context.Context) (vault.ActiveVault, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveVault", ctx)
+	ret0, _ := ret[0].(vault.ActiveVault)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActiveVault indicates an expected call of GetActiveVault.
+func (mr *MockVaultServiceMockRecorder) GetActiveVault(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveVault", reflect.TypeOf((*MockVaultService)(nil).GetActiveVault), ctx)
+}
+
+// GrantEmergencyAccess mocks base method.
+func (m *MockVaultService) GrantEmergencyAccess(ctx context.Context, nameOrID, login string, wait time.Duration) (vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantEmergencyAccess", ctx, nameOrID, login, wait)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantEmergencyAccess indicates an expected call of GrantEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) GrantEmergencyAccess(ctx, nameOrID, login, wait any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).GrantEmergencyAccess), ctx, nameOrID, login, wait)
+}
+
+// ListEmergencyGrants mocks base method.
+func (m *MockVaultService) ListEmergencyGrants(ctx context.Context, incoming bool) ([]vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmergencyGrants", ctx, incoming)
+	ret0, _ := ret[0].([]vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyGrants indicates an expected call of ListEmergencyGrants.
+func (mr *MockVaultServiceMockRecorder) ListEmergencyGrants(ctx, incoming any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyGrants", reflect.TypeOf((*MockVaultService)(nil).ListEmergencyGrants), ctx, incoming)
+}
+
+// ListMembers mocks base method.
+func (m *MockVaultService) ListMembers(ctx context.Context, nameOrID string) ([]vault.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, nameOrID)
+	ret0, _ := ret[0].([]vault.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockVaultServiceMockRecorder) ListMembers(ctx, nameOrID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockVaultService)(nil).ListMembers), ctx, nameOrID)
+}
+
+// ListVaults mocks base method.
+func (m *MockVaultService) ListVaults(ctx context.Context) ([]vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVaults", ctx)
+	ret0, _ := ret[0].([]vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVaults indicates an expected call of ListVaults.
+func (mr *MockVaultServiceMockRecorder) ListVaults(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVaults", reflect.TypeOf((*MockVaultService)(nil).ListVaults), ctx)
+}
+
+// OpenEmergencyAccess mocks base method.
+func (m *MockVaultService) OpenEmergencyAccess(ctx context.Context, nameOrID string) (vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenEmergencyAccess", ctx, nameOrID)
+	ret0, _ := ret[0].(vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenEmergencyAccess indicates an expected call of OpenEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) OpenEmergencyAccess(ctx, nameOrID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).OpenEmergencyAccess), ctx, nameOrID)
+}
+
+// RejectEmergencyAccess mocks base method.
+func (m *MockVaultService) RejectEmergencyAccess(ctx context.Context, nameOrID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectEmergencyAccess", ctx, nameOrID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectEmergencyAccess indicates an expected call of RejectEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) RejectEmergencyAccess(ctx, nameOrID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).RejectEmergencyAccess), ctx, nameOrID, login)
+}
+
+// RemoveMember mocks base method.
+func (m *MockVaultService) RemoveMember(ctx context.Context, nameOrID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, nameOrID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockVaultServiceMockRecorder) RemoveMember(ctx, nameOrID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockVaultService)(nil).RemoveMember), ctx, nameOrID, login)
+}
+
+// RenameVault mocks base method.
+func (m *MockVaultService) RenameVault(ctx context.Context, nameOrID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameVault", ctx, nameOrID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameVault indicates an expected call of RenameVault.
+func (mr *MockVaultServiceMockRecorder) RenameVault(ctx, nameOrID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameVault", reflect.TypeOf((*MockVaultService)(nil).RenameVault), ctx, nameOrID, name)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockVaultService) RequestEmergencyAccess(ctx context.Context, nameOrID string) (vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", ctx, nameOrID)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) RequestEmergencyAccess(ctx, nameOrID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).RequestEmergencyAccess), ctx, nameOrID)
+}
+
+// RevokeEmergencyAccess mocks base method.
+func (m *MockVaultService) RevokeEmergencyAccess(ctx context.Context, nameOrID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeEmergencyAccess", ctx, nameOrID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeEmergencyAccess indicates an expected call of RevokeEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) RevokeEmergencyAccess(ctx, nameOrID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).RevokeEmergencyAccess), ctx, nameOrID, login)
+}
+
+// SelectVault mocks base method.
+func (m *MockVaultService) SelectVault(ctx context.Context, nameOrID string) (vault.ActiveVault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectVault", ctx, nameOrID)
+	ret0, _ := ret[0].(vault.ActiveVault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectVault indicates an expected call of SelectVault.
+func (mr *MockVaultServiceMockRecorder) SelectVault(ctx, nameOrID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectVault", reflect.TypeOf((*MockVaultService)(nil).SelectVault), ctx, nameOrID)
+}
+
+// UseVault mocks base method.
+func (m *MockVaultService) UseVault(ctx context.Context, nameOrID string) (vault.ActiveVault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseVault", ctx, nameOrID)
+	ret0, _ := ret[0].(vault.ActiveVault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseVault indicates an expected call of UseVault.
+func (mr *MockVaultServiceMockRecorder) UseVault(ctx, nameOrID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseVault", reflect.TypeOf((*MockVaultService)(nil).UseVault), ctx, nameOrID)
+}
@@ -0,0 +1,143 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+// New creates a new instance of the admin service with the given user repository and entry service.
+func New(users user.Repository, entries entry.Service) Service {
+	return &service{
+		users:   users,
+		entries: entries,
+		log:     log.Logger().Named("service.admin"),
+	}
+}
+
+type service struct {
+	users   user.Repository
+	entries entry.Service
+	log     *zap.SugaredLogger
+}
+
+func (s *service) ListUsers(ctx context.Context) ([]User, error) {
+	infos, err := s.users.ListUsers(ctx)
+	if err != nil {
+		s.log.Errorw("cant list users", "err", err)
+
+		return nil, ErrInternal
+	}
+
+	usages, err := s.entries.ListUsage(ctx)
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	users := make([]User, 0, len(infos))
+	for _, info := range infos {
+		// a user deleted after being listed has no usage
+		users = append(users, User{UserInfo: info, Usage: usages[info.ID]})
+	}
+
+	return users, nil
+}
+
+func (s *service) SetDisabled(ctx context.Context, login string, disabled bool) error {
+	found, err := s.users.SetDisabled(ctx, login, disabled)
+	if err != nil {
+		s.log.Errorw("cant set disabled", "login", login, "disabled", disabled, "err", err)
+
+		return ErrInternal
+	}
+
+	if !found {
+		return ErrUserNotFound
+	}
+
+	s.log.Infow("user disabled changed", "login", login, "disabled", disabled)
+
+	return nil
+}
+
+func (s *service) ResetSessions(ctx context.Context, login string) error {
+	found, err := s.users.ResetSessions(ctx, login, time.Now())
+	if err != nil {
+		s.log.Errorw("cant reset sessions", "login", login, "err", err)
+
+		return ErrInternal
+	}
+
+	if !found {
+		return ErrUserNotFound
+	}
+
+	s.log.Infow("user sessions reset", "login", login)
+
+	return nil
+}
+
+func (s *service) DeleteUser(ctx context.Context, login string) (DeleteResult, error) {
+	llog := s.log.WithLazy("login", login, "method", "DeleteUser")
+
+	info, found, err := s.users.FindUser(ctx, login)
+	if err != nil {
+		llog.Errorw("cant find user", "err", err)
+
+		return DeleteResult{}, ErrInternal
+	}
+
+	if !found {
+		return DeleteResult{}, ErrUserNotFound
+	}
+
+	memberships, err := s.users.ListMemberships(ctx, info.ID)
+	if err != nil {
+		llog.Errorw("cant list memberships", "err", err)
+
+		return DeleteResult{}, ErrInternal
+	}
+
+	// the owners are checked before anything is deleted
+	owners := []string{info.ID}
+	result := DeleteResult{Vaults: make([]string, 0)}
+	for _, membership := range memberships {
+		if membership.Members == 1 {
+			owners = append(owners, membership.VaultID)
+			result.Vaults = append(result.Vaults, membership.VaultName)
+
+			continue
+		}
+
+		if membership.Role == string(vault.RoleOwner) && membership.Owners == 1 {
+			return DeleteResult{}, ErrLastOwner
+		}
+	}
+
+	// the values go first, so if anything fails the user is still there for a retry
+	for _, ownerID := range owners {
+		purged, err := s.entries.PurgeOwner(ctx, ownerID)
+		if err != nil {
+			return DeleteResult{}, ErrInternal
+		}
+
+		result.Blobs += purged
+	}
+
+	_, err = s.users.DeleteUser(ctx, info.ID)
+	if err != nil {
+		llog.Errorw("cant delete user", "err", err)
+
+		return DeleteResult{}, ErrInternal
+	}
+
+	llog.Infow("user deleted", "vaults", len(result.Vaults), "blobs", result.Blobs)
+
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/admin"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestService_ListUsers(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		entries := mocks.NewMockEntryService(ctrl)
+
+		users.EXPECT().ListUsers(ctx).Return([]user.UserInfo{{ID: "first", Login: "alice"}, {ID: "second", Login: "bob", Disabled: true}}, nil)
+		// the usage of all the users is listed at once, a user without one gets the zero usage
+		entries.EXPECT().ListUsage(ctx).Return(map[string]entry.Usage{"first": {Bytes: 10, Entries: 1}}, nil)
+
+		s := admin.New(users, entries)
+		list, err := s.ListUsers(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []admin.User{
+			{UserInfo: user.UserInfo{ID: "first", Login: "alice"}, Usage: entry.Usage{Bytes: 10, Entries: 1}},
+			{UserInfo: user.UserInfo{ID: "second", Login: "bob", Disabled: true}},
+		}, list)
+	})
+
+	t.Run("usage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		entries := mocks.NewMockEntryService(ctrl)
+
+		users.EXPECT().ListUsers(ctx).Return([]user.UserInfo{{ID: "first", Login: "alice"}}, nil)
+		entries.EXPECT().ListUsage(ctx).Return(nil, entry.ErrInternal)
+
+		s := admin.New(users, entries)
+		list, err := s.ListUsers(ctx)
+		require.ErrorIs(t, err, admin.ErrInternal)
+		require.Nil(t, list)
+	})
+}
+
+func TestService_SetDisabled(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().SetDisabled(ctx, "alice", true).Return(true, nil)
+
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		require.NoError(t, s.SetDisabled(ctx, "alice", true))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().SetDisabled(ctx, "alice", false).Return(false, nil)
+
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		require.ErrorIs(t, s.SetDisabled(ctx, "alice", false), admin.ErrUserNotFound)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().SetDisabled(ctx, "alice", true).Return(false, errors.New("error"))
+
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		require.ErrorIs(t, s.SetDisabled(ctx, "alice", true), admin.ErrInternal)
+	})
+}
+
+func TestService_ResetSessions(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().ResetSessions(ctx, "alice", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, at time.Time) (bool, error) {
+			require.WithinDuration(t, time.Now(), at, time.Minute)
+
+			return true, nil
+		})
+
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		require.NoError(t, s.ResetSessions(ctx, "alice"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().ResetSessions(ctx, "alice", gomock.Any()).Return(false, nil)
+
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		require.ErrorIs(t, s.ResetSessions(ctx, "alice"), admin.ErrUserNotFound)
+	})
+}
+
+func TestService_DeleteUser(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		entries := mocks.NewMockEntryService(ctrl)
+
+		users.EXPECT().FindUser(ctx, "alice").Return(user.UserInfo{ID: "user", Login: "alice"}, true, nil)
+		users.EXPECT().ListMemberships(ctx, "user").Return([]user.Membership{
+			{VaultID: "personal", VaultName: "work", Role: "owner", Members: 1, Owners: 1},
+			{VaultID: "team", VaultName: "team", Role: "owner", Members: 3, Owners: 2},
+			{VaultID: "family", VaultName: "family", Role: "viewer", Members: 2, Owners: 1},
+		}, nil)
+		gomock.InOrder(
+			entries.EXPECT().PurgeOwner(ctx, "user").Return(5, nil),
+			entries.EXPECT().PurgeOwner(ctx, "personal").Return(2, nil),
+			users.EXPECT().DeleteUser(ctx, "user").Return(true, nil),
+		)
+
+		s := admin.New(users, entries)
+		result, err := s.DeleteUser(ctx, "alice")
+		require.NoError(t, err)
+		require.Equal(t, admin.DeleteResult{Vaults: []string{"work"}, Blobs: 7}, result)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().FindUser(ctx, "alice").Return(user.UserInfo{}, false, nil)
+
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		_, err := s.DeleteUser(ctx, "alice")
+		require.ErrorIs(t, err, admin.ErrUserNotFound)
+	})
+
+	t.Run("last owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		users.EXPECT().FindUser(ctx, "alice").Return(user.UserInfo{ID: "user", Login: "alice"}, true, nil)
+		users.EXPECT().ListMemberships(ctx, "user").Return([]user.Membership{
+			{VaultID: "team", VaultName: "team", Role: "owner", Members: 3, Owners: 1},
+		}, nil)
+
+		// nothing is deleted
+		s := admin.New(users, mocks.NewMockEntryService(ctrl))
+		_, err := s.DeleteUser(ctx, "alice")
+		require.ErrorIs(t, err, admin.ErrLastOwner)
+	})
+
+	t.Run("purge error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		entries := mocks.NewMockEntryService(ctrl)
+
+		users.EXPECT().FindUser(ctx, "alice").Return(user.UserInfo{ID: "user", Login: "alice"}, true, nil)
+		users.EXPECT().ListMemberships(ctx, "user").Return(nil, nil)
+		entries.EXPECT().PurgeOwner(ctx, "user").Return(0, entry.ErrInternal)
+
+		// the user is kept for a retry
+		s := admin.New(users, entries)
+		_, err := s.DeleteUser(ctx, "alice")
+		require.ErrorIs(t, err, admin.ErrInternal)
+	})
+}
@@ -0,0 +1,53 @@
+// Package admin provides core application logic for the operators of the server: inspecting the users,
+// disabling them, resetting their sessions and deleting them along with everything they store.
+// It isn't exposed over the network, the server binary calls it from its admin commands.
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+)
+
+// ErrInternal is returned when an internal error occurs.
+var ErrInternal = errors.New("internal error")
+
+// ErrUserNotFound is returned when there is no user with the given login.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrLastOwner is returned when the user to delete is the only owner of a vault with other members,
+// since the vault would be left without anyone to manage it.
+var ErrLastOwner = errors.New("user is the last owner of a shared vault")
+
+// User is a user of the server along with the storage charged to the user, for the own values and the vaults the user owns.
+type User struct {
+	user.UserInfo
+	Usage entry.Usage
+}
+
+// DeleteResult describes what was deleted along with the user.
+type DeleteResult struct {
+	Vaults []string // Vaults are the names of the vaults the user was the only member of.
+	Blobs  int      // Blobs is the number of the deleted blobs of the user and of those vaults.
+}
+
+// Service defines the interface for the user management by the operators.
+type Service interface {
+	// ListUsers returns all the users sorted by login, with their usage.
+	ListUsers(ctx context.Context) ([]User, error)
+
+	// SetDisabled disables or enables the user. A disabled user can't log in and the user's tokens are rejected.
+	// Returns ErrUserNotFound if there is no such user.
+	SetDisabled(ctx context.Context, login string, disabled bool) error
+
+	// ResetSessions makes all the tokens issued to the user so far invalid. Returns ErrUserNotFound if there is no such user.
+	ResetSessions(ctx context.Context, login string) error
+
+	// DeleteUser deletes the user with everything the user stores: the entries with their history, the trash, the uploads,
+	// the shares by and with the user, the key pair, the vault memberships and the emergency grants.
+	// The vaults the user is the only member of are deleted with their entries.
+	// Returns ErrUserNotFound if there is no such user, and ErrLastOwner if the user is the only owner of a vault with other members.
+	DeleteUser(ctx context.Context, login string) (DeleteResult, error)
+}
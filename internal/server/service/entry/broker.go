@@ -0,0 +1,187 @@
+package entry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// watcherBufferSize is the number of changes a watcher may lag behind before it's dropped.
+const watcherBufferSize = 64
+
+// defaultWatchInterval is the interval the change log is polled at if Options.WatchInterval isn't set.
+const defaultWatchInterval = 2 * time.Second
+
+// broker fans out changes of entries to the watchers of the same user.
+// The changes are read from the change log, so the watchers get the changes made through any server instance
+// sharing the database. The log of a user is polled while the user has watchers, every interval,
+// and right away when the broker is notified of a change made through this instance.
+type broker struct {
+	log      *zap.SugaredLogger
+	metaRepo MetadataRepository
+	interval time.Duration
+
+	mu    sync.Mutex
+	users map[string]*userWatchers
+}
+
+// userWatchers are the watchers of a user along with the poller of the user's change log.
+type userWatchers struct {
+	watchers map[chan Change]struct{}
+	wake     chan struct{}
+	stop     context.CancelFunc
+}
+
+func newBroker(metaRepo MetadataRepository, interval time.Duration, log *zap.SugaredLogger) *broker {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	return &broker{
+		log:      log,
+		metaRepo: metaRepo,
+		interval: interval,
+		users:    make(map[string]*userWatchers),
+	}
+}
+
+// subscribe registers a new watcher of the user's changes made after the call.
+func (b *broker) subscribe(ctx context.Context, userID string) (chan Change, error) {
+	// read before the watcher is registered, so no change made after the call is missed
+	cursor, err := b.metaRepo.GetLatestCursor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Change, watcherBufferSize)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	uw, ok := b.users[userID]
+	if !ok {
+		pollCtx, stop := context.WithCancel(context.Background())
+		uw = &userWatchers{
+			watchers: make(map[chan Change]struct{}),
+			wake:     make(chan struct{}, 1),
+			stop:     stop,
+		}
+		b.users[userID] = uw
+
+		go b.poll(pollCtx, userID, uw, cursor)
+	}
+
+	// a poller already running may be behind the cursor, the watcher gets a few earlier changes then
+	uw.watchers[ch] = struct{}{}
+
+	return ch, nil
+}
+
+// unsubscribe removes the watcher and closes its channel. It's safe to call it for an already removed watcher.
+func (b *broker) unsubscribe(userID string, ch chan Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(userID, ch)
+}
+
+// notify makes the poller of the user read the change log right away, without blocking.
+func (b *broker) notify(userID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	uw, ok := b.users[userID]
+	if !ok {
+		return
+	}
+
+	select {
+	case uw.wake <- struct{}{}:
+	default:
+	}
+}
+
+// poll reads the changes made after the cursor from the user's change log and fans them out, until ctx is done.
+func (b *broker) poll(ctx context.Context, userID string, uw *userWatchers, cursor int64) {
+	llog := b.log.WithLazy("userID", userID, "method", "poll")
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uw.wake:
+		}
+
+		for {
+			changes, err := b.metaRepo.ListChanges(ctx, userID, cursor, watcherBufferSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					llog.Errorw("cant list changes", "cursor", cursor, "err", err)
+				}
+
+				break
+			}
+
+			if len(changes) > 0 {
+				cursor = changes[len(changes)-1].Cursor
+				b.publish(userID, changes)
+			}
+
+			if len(changes) < watcherBufferSize {
+				break
+			}
+		}
+	}
+}
+
+// publish sends the changes to all the watchers of the user without blocking.
+// Watchers that don't keep up are removed and their channels are closed,
+// they are expected to catch up with the change log and watch again.
+func (b *broker) publish(userID string, changes []Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	uw, ok := b.users[userID]
+	if !ok {
+		return
+	}
+
+	for ch := range uw.watchers {
+		for _, change := range changes {
+			select {
+			case ch <- change:
+				continue
+			default:
+				b.remove(userID, ch)
+			}
+
+			break
+		}
+	}
+}
+
+// remove must be called with the mutex held. The poller is stopped along with the last watcher of the user.
+func (b *broker) remove(userID string, ch chan Change) {
+	uw, ok := b.users[userID]
+	if !ok {
+		return
+	}
+
+	if _, ok = uw.watchers[ch]; !ok {
+		return
+	}
+
+	delete(uw.watchers, ch)
+	close(ch)
+
+	if len(uw.watchers) == 0 {
+		uw.stop()
+		delete(b.users, userID)
+	}
+}
@@ -0,0 +1,184 @@
+package entry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+)
+
+func (s *service) Reconcile(ctx context.Context, opts ReconcileOptions) (ReconcileReport, error) {
+	llog := s.log.WithLazy("repair", opts.Repair, "method", "Reconcile")
+
+	// metadata is listed before the blobs: a blob written in between is only taken for an orphan,
+	// which is protected by the grace period, while the other way round a new entry would be taken for an unreadable one.
+	refs, err := s.metaRepo.ListBlobRefs(ctx)
+	if err != nil {
+		llog.Errorw("cant list blob refs", "err", err)
+
+		return ReconcileReport{}, ErrInternal
+	}
+
+	report := ReconcileReport{Refs: len(refs)}
+
+	referenced := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		referenced[s.getRefBlobKey(ref)] = struct{}{}
+	}
+
+	stored := make(map[string]struct{}, len(refs))
+	modifiedBefore := time.Now().Add(-opts.GracePeriod)
+
+	err = s.blobRepo.WalkBlobs(ctx, func(info blob.Info) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		report.Blobs++
+		stored[info.Key] = struct{}{}
+
+		if _, ok := referenced[info.Key]; !ok && info.ModifiedAt.Before(modifiedBefore) {
+			report.OrphanBlobs = append(report.OrphanBlobs, info.Key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		llog.Errorw("cant walk blobs", "err", err)
+
+		return ReconcileReport{}, ErrInternal
+	}
+
+	for _, ref := range refs {
+		// nothing may have been uploaded yet
+		if ref.Kind == BlobRefUpload {
+			continue
+		}
+
+		if _, ok := stored[s.getRefBlobKey(ref)]; !ok {
+			report.MissingBlobs = append(report.MissingBlobs, ref)
+		}
+	}
+
+	llog.Infow(
+		"reconciled metadata with blobs",
+		"refs", report.Refs,
+		"blobs", report.Blobs,
+		"orphanBlobs", len(report.OrphanBlobs),
+		"missingBlobs", len(report.MissingBlobs),
+	)
+
+	if !opts.Repair {
+		return report, nil
+	}
+
+	for _, key := range report.OrphanBlobs {
+		err = s.blobRepo.DeleteBlob(ctx, key)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			llog.Errorw("cant delete orphan blob", "blobKey", key, "err", err)
+
+			continue
+		}
+
+		report.Repaired++
+	}
+
+	for _, ref := range report.MissingBlobs {
+		if s.deleteUnreadable(ctx, ref, llog) {
+			report.Repaired++
+		}
+	}
+
+	// the temporary blobs are never walked, so the ones left by crashed writers are cleaned up separately
+	if cleaner, ok := s.blobRepo.(blob.TempCleaner); ok {
+		cleaned, err := cleaner.CleanTemp(ctx, modifiedBefore)
+		if err != nil {
+			llog.Errorw("cant clean temporary blobs", "err", err)
+		}
+
+		report.Repaired += cleaned
+	}
+
+	llog.Infow("repaired orphans", "repaired", report.Repaired)
+
+	return report, nil
+}
+
+// deleteUnreadable deletes the metadata whose blob is missing. It returns false if nothing was deleted.
+// The entry is only deleted if it still has the version seen during the reconciliation.
+func (s *service) deleteUnreadable(ctx context.Context, ref BlobRef, llog *zap.SugaredLogger) bool {
+	llog = llog.WithLazy("kind", ref.Kind, "userID", ref.UserID, "key", ref.Key, "version", ref.Version)
+
+	if ref.Kind == BlobRefVersion {
+		err := s.metaRepo.DeleteVersion(ctx, ref.UserID, ref.Key, ref.Version)
+		if err != nil {
+			llog.Errorw("cant delete unreadable version", "err", err)
+
+			return false
+		}
+
+		return true
+	}
+
+	if ref.Kind == BlobRefShare {
+		deleted, err := s.metaRepo.DeleteShare(ctx, ref.UserID, ref.RecipientID, ref.Key)
+		if err != nil {
+			llog.Errorw("cant delete unreadable share", "err", err)
+
+			return false
+		}
+
+		return deleted
+	}
+
+	if ref.Kind == BlobRefTrash {
+		// trashed again in the meantime if the version doesn't match
+		blobNames, deleted, err := s.metaRepo.PurgeTrash(ctx, ref.UserID, ref.Key, ref.Version)
+		if err != nil {
+			llog.Errorw("cant purge unreadable trash entry", "err", err)
+
+			return false
+		}
+
+		// the previous versions are purged along with the entry
+		for _, blobName := range blobNames {
+			s.discardBlob(ctx, ref.UserID, blobName, llog)
+		}
+
+		return deleted
+	}
+
+	_, deleted, err := s.metaRepo.DeleteMetadata(ctx, ref.UserID, ref.Key, ref.Version)
+	if errors.Is(err, ErrConflict) {
+		llog.Info("entry was changed during the reconciliation, skipping")
+
+		return false
+	}
+	if err != nil {
+		llog.Errorw("cant delete unreadable entry", "err", err)
+
+		return false
+	}
+
+	if deleted {
+		s.broker.notify(ref.UserID)
+	}
+
+	return deleted
+}
+
+// getRefBlobKey returns the blob key referenced by the metadata.
+func (s *service) getRefBlobKey(ref BlobRef) string {
+	switch ref.Kind {
+	case BlobRefUpload:
+		return s.getUploadBlobKey(ref.UserID, ref.UploadID)
+	case BlobRefShare:
+		return s.getShareBlobKey(ref.UserID, ref.RecipientID, ref.Key)
+	default:
+		return s.getBlobKey(ref.UserID, ref.BlobName)
+	}
+}
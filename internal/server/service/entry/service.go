@@ -2,22 +2,31 @@ package entry
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"math"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
 	"github.com/kuvalkin/gophkeeper/internal/support/log"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
 // New creates a new instance of the Service implementation.
 // This service encapsulates the core business logic for handling entries,
 // including metadata and blob storage management.
-func New(metaRepo MetadataRepository, blobRepo blob.Repository) Service {
+func New(metaRepo MetadataRepository, blobRepo blob.Repository, options Options) Service {
 	return &service{
 		metaRepo: metaRepo,
 		blobRepo: blobRepo,
+		options:  options,
+		broker:   newBroker(metaRepo, options.WatchInterval, log.Logger().Named("service.sync.broker")),
 		log:      log.Logger().Named("service.sync"),
 	}
 }
@@ -26,29 +35,36 @@ type service struct {
 	log      *zap.SugaredLogger
 	metaRepo MetadataRepository
 	blobRepo blob.Repository
+	options  Options
+	broker   *broker
 }
 
-func (s *service) SetEntry(ctx context.Context, userID string, md Metadata, overwrite bool) (chan<- UploadChunk, <-chan SetEntryResult, error) {
+func (s *service) SetEntry(ctx context.Context, userID string, md Metadata, overwrite bool, expectedVersion int64) (chan<- UploadChunk, <-chan SetEntryResult, error) {
 	llog := s.log.WithLazy("userID", userID, "key", md.Key, "method", "Set")
 
-	if !overwrite {
-		_, ok, err := s.metaRepo.GetMetadata(ctx, userID, md.Key)
-		if err != nil {
-			llog.Errorw("cant get metadata", "err", err)
+	current, exists, err := s.metaRepo.GetMetadata(ctx, userID, md.Key)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
 
-			return nil, nil, ErrInternal
-		}
+		return nil, nil, ErrInternal
+	}
 
-		if ok {
-			llog.Debug("entry already exists")
+	err = s.checkExisting(current, exists, overwrite, expectedVersion, llog)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			return nil, nil, ErrEntryExists
-		}
+	limit, err := s.getUploadLimit(ctx, userID, current, exists, llog)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	blobKey := s.getBlobKey(userID, md.Key)
+	// the content is written to a new blob, which the entry refers to only once the metadata is committed,
+	// so a conflicting write never replaces the content of the entry
+	md.BlobName = s.newContentBlobName()
+	blobKey := s.getBlobKey(userID, md.BlobName)
 
-	dst, err := s.blobRepo.OpenBlobWriter(blobKey)
+	dst, err := s.blobRepo.OpenBlobWriter(ctx, blobKey)
 	if err != nil {
 		llog.Errorw("cant get writer", "err", err)
 
@@ -62,9 +78,12 @@ func (s *service) SetEntry(ctx context.Context, userID string, md Metadata, over
 	go func() {
 		defer close(resultChan)
 
-		err = s.processUpload(ctx, uploadChan, dst, llog.Named("upload"))
+		md.Size, md.Checksum, err = s.processUpload(ctx, uploadChan, dst, limit, llog.Named("upload"))
+		if errors.Is(err, errLimitExceeded) {
+			err = ErrQuotaExceeded
+		}
 		if err != nil {
-			cderr := s.closeAndDelete(dst, blobKey, llog)
+			cderr := s.closeAndDelete(ctx, dst, blobKey, llog)
 			if cderr != nil {
 				resultChan <- SetEntryResult{Err: ErrInternal}
 				return
@@ -78,44 +97,490 @@ func (s *service) SetEntry(ctx context.Context, userID string, md Metadata, over
 		if err != nil {
 			llog.Errorw("cant close writer", "err", err)
 
-			err = s.blobRepo.DeleteBlob(blobKey)
-			if err != nil {
-				llog.Errorw("cant delete blob", "err", err)
+			// aborter discards the written data itself
+			if _, ok := dst.(blob.Aborter); !ok {
+				s.discardBlob(ctx, userID, md.BlobName, llog)
 			}
 
 			resultChan <- SetEntryResult{Err: ErrInternal}
 			return
 		}
 
-		err = s.metaRepo.SetMetadata(ctx, userID, md)
+		err = s.swapContent(ctx, userID, "", md, current, exists, llog)
 		if err != nil {
-			llog.Errorw("cant set metadata", "err", err)
-
-			err = s.blobRepo.DeleteBlob(blobKey)
-			if err != nil {
-				llog.Errorw("cant delete blob", "err", err)
-			}
+			s.discardBlob(ctx, userID, md.BlobName, llog)
 
-			resultChan <- SetEntryResult{Err: ErrInternal}
+			resultChan <- SetEntryResult{Err: err}
 			return
 		}
 
+		s.broker.notify(userID)
+
 		resultChan <- SetEntryResult{}
 	}()
 
 	return uploadChan, resultChan, nil
 }
 
-func (s *service) processUpload(ctx context.Context, uploadChan <-chan UploadChunk, dst io.WriteCloser, llog *zap.SugaredLogger) error {
+// getUploadLimit checks the user's quota before writing the entry and returns the maximum size of the content.
+// ErrQuotaExceeded is returned if nothing can be written.
+func (s *service) getUploadLimit(ctx context.Context, userID string, current Metadata, exists bool, llog *zap.SugaredLogger) (int64, error) {
+	if s.options.MaxBytes == 0 && s.options.MaxEntries == 0 {
+		return math.MaxInt64, nil
+	}
+
+	usage, err := s.metaRepo.GetUsage(ctx, userID)
+	if err != nil {
+		llog.Errorw("cant get usage", "err", err)
+
+		return 0, ErrInternal
+	}
+
+	if !exists && s.options.MaxEntries > 0 && usage.Entries >= s.options.MaxEntries {
+		llog.Debugw("entries quota exceeded", "entries", usage.Entries)
+
+		return 0, ErrQuotaExceeded
+	}
+
+	if s.options.MaxBytes == 0 {
+		return math.MaxInt64, nil
+	}
+
+	used := usage.Bytes
+	if exists && s.options.MaxVersions == 0 {
+		// the replaced content isn't kept as a version
+		used -= current.Size
+	}
+
+	if used >= s.options.MaxBytes {
+		llog.Debugw("bytes quota exceeded", "bytes", usage.Bytes)
+
+		return 0, ErrQuotaExceeded
+	}
+
+	return s.options.MaxBytes - used, nil
+}
+
+// checkExisting checks if the entry can be written given its current state.
+// It returns ErrConflict if the expected version is set and doesn't match the current one,
+// and ErrEntryExists if the entry exists and shouldn't be overwritten.
+func (s *service) checkExisting(current Metadata, exists bool, overwrite bool, expectedVersion int64, llog *zap.SugaredLogger) error {
+	if expectedVersion != 0 {
+		if !exists || current.Version != expectedVersion {
+			llog.Debugw("version mismatch", "expected", expectedVersion, "current", current.Version)
+
+			return ErrConflict
+		}
+	} else if exists && !overwrite {
+		llog.Debug("entry already exists")
+
+		return ErrEntryExists
+	}
+
+	return nil
+}
+
+func (s *service) CreateUploadSession(ctx context.Context, userID string, md Metadata, size int64, overwrite bool, expectedVersion int64) (UploadSession, error) {
+	llog := s.log.WithLazy("userID", userID, "key", md.Key, "method", "CreateUploadSession")
+
+	current, exists, err := s.metaRepo.GetMetadata(ctx, userID, md.Key)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
+
+		return UploadSession{}, ErrInternal
+	}
+
+	err = s.checkExisting(current, exists, overwrite, expectedVersion, llog)
+	if err != nil {
+		return UploadSession{}, err
+	}
+
+	limit, err := s.getUploadLimit(ctx, userID, current, exists, llog)
+	if err != nil {
+		return UploadSession{}, err
+	}
+	if size > limit {
+		llog.Debugw("upload is larger than the quota allows", "size", size, "limit", limit)
+
+		return UploadSession{}, ErrQuotaExceeded
+	}
+
+	session, err := s.metaRepo.CreateUploadSession(ctx, userID, UploadSession{
+		Metadata: md,
+		Size:     size,
+		// checked again on finalize, so concurrent writes aren't lost silently.
+		// zero for a new entry means that it still must not exist.
+		ExpectedVersion: current.Version,
+	})
+	if err != nil {
+		llog.Errorw("cant create upload session", "err", err)
+
+		return UploadSession{}, ErrInternal
+	}
+
+	llog.Debugw("upload session created", "uploadID", session.ID)
+
+	return session, nil
+}
+
+func (s *service) GetUploadSession(ctx context.Context, userID string, uploadID string) (UploadSession, bool, error) {
+	llog := s.log.WithLazy("userID", userID, "uploadID", uploadID, "method", "GetUploadSession")
+
+	session, ok, err := s.getUploadSession(ctx, userID, uploadID, llog)
+	if err != nil {
+		return UploadSession{}, false, ErrInternal
+	}
+
+	return session, ok, nil
+}
+
+func (s *service) AppendUpload(ctx context.Context, userID string, uploadID string, offset int64) (chan<- UploadChunk, <-chan UploadResult, error) {
+	llog := s.log.WithLazy("userID", userID, "uploadID", uploadID, "offset", offset, "method", "AppendUpload")
+
+	ctx, release, err := s.leaseUpload(ctx, userID, uploadID, llog)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, ok, err := s.getUploadSession(ctx, userID, uploadID, llog)
+	if err != nil {
+		release()
+
+		return nil, nil, ErrInternal
+	}
+	if !ok {
+		release()
+
+		return nil, nil, ErrUploadNotFound
+	}
+
+	if session.Offset != offset {
+		release()
+		llog.Debugw("offset mismatch", "committed", session.Offset)
+
+		return nil, nil, ErrOffsetMismatch
+	}
+
+	dst, err := s.blobRepo.OpenBlobAppender(ctx, s.getUploadBlobKey(userID, uploadID))
+	if err != nil {
+		release()
+		llog.Errorw("cant get appender", "err", err)
+
+		return nil, nil, ErrInternal
+	}
+
+	uploadChan := make(chan UploadChunk)
+	// don't wait for caller to read the result
+	resultChan := make(chan UploadResult, 1)
+
+	go func() {
+		defer close(resultChan)
+
+		// the checksum of a part isn't useful, the whole content is hashed on finalization.
+		// the declared size is what is counted in the usage, so nothing above it is accepted
+		written, _, err := s.processUpload(ctx, uploadChan, dst, session.Size-session.Offset, llog.Named("upload"))
+		// unlike SetEntry, whatever is written is kept, so the upload can be continued from it
+		session.Offset += written
+		if errors.Is(err, ErrNoUpload) {
+			err = nil
+		}
+		if errors.Is(err, errLimitExceeded) {
+			err = ErrSizeMismatch
+		}
+
+		closeErr := dst.Close()
+		// released before the result is sent, so the session can be continued as soon as the caller gets it
+		release()
+		if closeErr != nil {
+			llog.Errorw("cant close appender", "err", closeErr)
+
+			resultChan <- UploadResult{Session: session, Err: ErrInternal}
+			return
+		}
+
+		resultChan <- UploadResult{Session: session, Err: err}
+	}()
+
+	return uploadChan, resultChan, nil
+}
+
+func (s *service) FinalizeUpload(ctx context.Context, userID string, uploadID string) error {
+	llog := s.log.WithLazy("userID", userID, "uploadID", uploadID, "method", "FinalizeUpload")
+
+	ctx, release, err := s.leaseUpload(ctx, userID, uploadID, llog)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	session, ok, err := s.getUploadSession(ctx, userID, uploadID, llog)
+	if err != nil {
+		return ErrInternal
+	}
+	if !ok {
+		return ErrUploadNotFound
+	}
+
+	if session.Offset != session.Size {
+		llog.Debugw("upload is incomplete", "offset", session.Offset, "size", session.Size)
+
+		return ErrSizeMismatch
+	}
+
+	md := session.Metadata
+	md.Size = session.Size
+	llog = llog.WithLazy("key", md.Key)
+
+	current, exists, err := s.metaRepo.GetMetadata(ctx, userID, md.Key)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
+
+		return ErrInternal
+	}
+
+	if current.Version != session.ExpectedVersion {
+		llog.Warnw("entry was changed during the upload", "expected", session.ExpectedVersion, "current", current.Version)
+		// the upload can't be finalized anymore
+		s.deleteUploadSession(ctx, userID, uploadID, true, llog)
+
+		return ErrConflict
+	}
+
+	md.Checksum, err = s.checksumBlob(ctx, s.getUploadBlobKey(userID, uploadID))
+	if err != nil {
+		llog.Errorw("cant calculate checksum of uploaded blob", "err", err)
+
+		return ErrInternal
+	}
+
+	// the uploaded blob becomes the content of the entry only if the entry is still at the version the session expects.
+	// the session is deleted along with the commit, so it never refers to the content of the entry
+	md.BlobName = s.getUploadBlobName(uploadID)
+
+	err = s.swapContent(ctx, userID, uploadID, md, current, exists, llog)
+	if errors.Is(err, ErrConflict) {
+		// the upload can't be finalized anymore, the content of the entry is left alone
+		s.deleteUploadSession(ctx, userID, uploadID, true, llog)
+
+		return ErrConflict
+	}
+	if err != nil {
+		// the session and its blob are kept, so the finalization can be retried
+		return ErrInternal
+	}
+
+	s.broker.notify(userID)
+
+	return nil
+}
+
+// swapContent commits md, whose content is already written to the blob it names, over the current version of the entry.
+// Readers get either the old metadata with the old blob or the new metadata with the new one, as blobs are never changed.
+// ErrConflict is returned if the entry was changed since current was read, the entry is left as it is then.
+// The replaced content is kept as a previous version if the history is enabled, otherwise its blob is deleted.
+// If uploadID is set, the upload session is deleted along with the commit. The blob of md is never discarded here,
+// it is up to the caller.
+func (s *service) swapContent(ctx context.Context, userID string, uploadID string, md Metadata, current Metadata, exists bool, llog *zap.SugaredLogger) error {
+	keepVersion := exists && s.options.MaxVersions > 0
+
+	var err error
+
+	// the version we've seen before the upload, so concurrent writes aren't lost silently.
+	// zero for a new entry means that it still must not exist.
+	if uploadID != "" {
+		_, err = s.metaRepo.CommitUpload(ctx, userID, uploadID, md, current.Version, keepVersion)
+	} else {
+		_, err = s.metaRepo.SetMetadata(ctx, userID, md, current.Version, keepVersion)
+	}
+	if errors.Is(err, ErrConflict) {
+		llog.Warn("entry was changed during the upload")
+
+		return ErrConflict
+	}
+	if err != nil {
+		llog.Errorw("cant set metadata", "err", err)
+
+		return ErrInternal
+	}
+
+	if keepVersion {
+		s.pruneVersions(ctx, userID, md.Key, s.options.MaxVersions, llog)
+	} else if exists {
+		// nothing refers to the replaced content anymore
+		s.discardBlob(ctx, userID, current.BlobName, llog)
+	}
+
+	return nil
+}
+
+// discardBlob deletes the blob no longer referenced by the metadata, even if ctx is canceled.
+// Errors are only logged, the blobs left are orphans for the reconciler.
+func (s *service) discardBlob(ctx context.Context, userID string, blobName string, llog *zap.SugaredLogger) {
+	err := s.deleteBlob(ctx, userID, blobName)
+	if err != nil {
+		llog.Errorw("cant delete blob", "blobName", blobName, "err", err)
+	}
+}
+
+// deleteBlob deletes the blob no longer referenced by the metadata, even if ctx is canceled.
+// Deleting a missing blob, or no blob if blobName is empty, is not an error.
+func (s *service) deleteBlob(ctx context.Context, userID string, blobName string) error {
+	if blobName == "" {
+		return nil
+	}
+
+	err := s.blobRepo.DeleteBlob(context.WithoutCancel(ctx), s.getBlobKey(userID, blobName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// getUploadSession retrieves the session and sets its offset to the size of the uploaded blob.
+func (s *service) getUploadSession(ctx context.Context, userID string, uploadID string, llog *zap.SugaredLogger) (UploadSession, bool, error) {
+	session, ok, err := s.metaRepo.GetUploadSession(ctx, userID, uploadID)
+	if err != nil {
+		llog.Errorw("cant get upload session", "err", err)
+
+		return UploadSession{}, false, err
+	}
+	if !ok {
+		return UploadSession{}, false, nil
+	}
+
+	// nothing is uploaded yet if there is no blob
+	session.Offset, _, err = s.blobRepo.GetBlobSize(ctx, s.getUploadBlobKey(userID, uploadID))
+	if err != nil {
+		llog.Errorw("cant get uploaded blob size", "err", err)
+
+		return UploadSession{}, false, err
+	}
+
+	return session, true, nil
+}
+
+// deleteUploadSession deletes the session and, if asked, its blob. Errors are only logged.
+func (s *service) deleteUploadSession(ctx context.Context, userID string, uploadID string, withBlob bool, llog *zap.SugaredLogger) {
+	err := s.metaRepo.DeleteUploadSession(ctx, userID, uploadID)
+	if err != nil {
+		llog.Errorw("cant delete upload session", "err", err)
+	}
+
+	if !withBlob {
+		return
+	}
+
+	err = s.blobRepo.DeleteBlob(ctx, s.getUploadBlobKey(userID, uploadID))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		llog.Errorw("cant delete uploaded blob", "err", err)
+	}
+}
+
+// defaultUploadLease is the period an upload session is leased for if Options.UploadLease isn't set.
+const defaultUploadLease = time.Minute
+
+// leaseUpload leases the upload session in the database, so no other request, through any server instance,
+// writes to or finalizes it meanwhile. The lease is renewed until the returned func releases it.
+// The returned context is canceled if the lease is lost anyway, e.g. if it couldn't be renewed in time.
+// ErrUploadBusy is returned if the session is leased by another request.
+func (s *service) leaseUpload(ctx context.Context, userID string, uploadID string, llog *zap.SugaredLogger) (context.Context, func(), error) {
+	period := s.options.UploadLease
+	if period <= 0 {
+		period = defaultUploadLease
+	}
+
+	token := uuid.NewString()
+
+	ok, err := s.metaRepo.LeaseUploadSession(ctx, userID, uploadID, token, period)
+	if err != nil {
+		llog.Errorw("cant lease upload session", "err", err)
+
+		return nil, nil, ErrInternal
+	}
+	if !ok {
+		_, exists, err := s.metaRepo.GetUploadSession(ctx, userID, uploadID)
+		if err != nil {
+			llog.Errorw("cant get upload session", "err", err)
+
+			return nil, nil, ErrInternal
+		}
+		if !exists {
+			return nil, nil, ErrUploadNotFound
+		}
+
+		llog.Debug("upload is busy")
+
+		return nil, nil, ErrUploadBusy
+	}
+
+	leaseCtx, lose := context.WithCancel(ctx)
+	// the lease is renewed regardless of the request, it's the release that stops it
+	renewCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	renewed := make(chan struct{})
+
+	go func() {
+		defer close(renewed)
+
+		ticker := time.NewTicker(period / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			ok, err := s.metaRepo.LeaseUploadSession(renewCtx, userID, uploadID, token, period)
+			if err != nil {
+				if renewCtx.Err() == nil {
+					llog.Errorw("cant renew upload session lease", "err", err)
+				}
+
+				continue
+			}
+			if !ok {
+				llog.Warn("upload session lease is lost")
+				lose()
+
+				return
+			}
+		}
+	}()
+
+	release := func() {
+		stop()
+		<-renewed
+		lose()
+
+		err := s.metaRepo.ReleaseUploadSession(context.WithoutCancel(ctx), userID, uploadID, token)
+		if err != nil {
+			llog.Errorw("cant release upload session", "err", err)
+		}
+	}
+
+	return leaseCtx, release, nil
+}
+
+// errLimitExceeded is returned by processUpload when the upload exceeds the limit.
+var errLimitExceeded = errors.New("upload limit exceeded")
+
+// processUpload writes chunks from uploadChan to dst until the channel is closed.
+// It returns the number of bytes written and the SHA-256 checksum of them.
+// The upload is stopped with errLimitExceeded before a chunk that would make it larger than limit is written.
+func (s *service) processUpload(ctx context.Context, uploadChan <-chan UploadChunk, dst io.WriteCloser, limit int64, llog *zap.SugaredLogger) (int64, []byte, error) {
 	llog.Debug("waiting for chunks")
 
 	writtenAnything := false
+	written := int64(0)
+	hash := sha256.New()
 
 	for {
 		select {
 		case <-ctx.Done():
 			llog.Debug("context done")
-			return ctx.Err()
+			return written, nil, ctx.Err()
 
 		case chunk, ok := <-uploadChan:
 			if !ok {
@@ -124,25 +589,34 @@ func (s *service) processUpload(ctx context.Context, uploadChan <-chan UploadChu
 				if !writtenAnything {
 					llog.Error("upload closed with no data")
 
-					return ErrNoUpload
+					return written, nil, ErrNoUpload
 				}
 
-				return nil
+				return written, hash.Sum(nil), nil
 			}
 
 			if chunk.Err != nil {
 				llog.Errorw("received error from upload chunk chan", "err", chunk.Err)
 
-				return ErrUploadChunk
+				return written, nil, ErrUploadChunk
 			}
 
 			llog.Debug("received chunk")
 
-			_, err := dst.Write(chunk.Content)
+			if written+int64(len(chunk.Content)) > limit {
+				llog.Debugw("upload limit exceeded", "limit", limit)
+
+				return written, nil, errLimitExceeded
+			}
+
+			n, err := dst.Write(chunk.Content)
+			written += int64(n)
+			// hash.Hash never returns an error
+			hash.Write(chunk.Content[:n])
 			if err != nil {
 				llog.Errorw("write chunk error", "err", err)
 
-				return ErrInternal
+				return written, nil, ErrInternal
 			}
 
 			llog.Debug("chunk written")
@@ -151,7 +625,42 @@ func (s *service) processUpload(ctx context.Context, uploadChan <-chan UploadChu
 	}
 }
 
-func (s *service) closeAndDelete(c io.Closer, blobKey string, llog *zap.SugaredLogger) error {
+// checksumBlob calculates the SHA-256 checksum of the blob.
+func (s *service) checksumBlob(ctx context.Context, key string) ([]byte, error) {
+	src, ok, err := s.blobRepo.OpenBlobReader(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cant open blob: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("blob %s not found", key)
+	}
+	defer utils.CloseAndLogError(src, s.log)
+
+	hash := sha256.New()
+	_, err = utils.CopyContext(ctx, hash, src)
+	if err != nil {
+		return nil, fmt.Errorf("cant read blob: %w", err)
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// closeAndDelete discards the blob being written. Writers implementing blob.Aborter are aborted,
+// so the previous content of the blob is kept, otherwise the writer is closed and the blob is deleted.
+func (s *service) closeAndDelete(ctx context.Context, c io.Closer, blobKey string, llog *zap.SugaredLogger) error {
+	if a, ok := c.(blob.Aborter); ok {
+		llog.Debug("aborting blob write")
+
+		err := a.Abort()
+		if err != nil {
+			llog.Errorw("cant abort write", "err", err)
+
+			return err
+		}
+
+		return nil
+	}
+
 	llog.Debug("deleting blob")
 
 	err := c.Close()
@@ -159,7 +668,7 @@ func (s *service) closeAndDelete(c io.Closer, blobKey string, llog *zap.SugaredL
 		llog.Errorw("cant close writer", "err", err)
 	}
 
-	err = s.blobRepo.DeleteBlob(blobKey)
+	err = s.blobRepo.DeleteBlob(context.WithoutCancel(ctx), blobKey)
 	if err != nil {
 		llog.Errorw("cant delete blob", "err", err)
 
@@ -169,8 +678,8 @@ func (s *service) closeAndDelete(c io.Closer, blobKey string, llog *zap.SugaredL
 	return nil
 }
 
-func (s *service) GetEntry(ctx context.Context, userID string, key string) (Metadata, io.ReadCloser, bool, error) {
-	llog := s.log.WithLazy("userID", userID, "key", key, "method", "Get")
+func (s *service) GetEntry(ctx context.Context, userID string, key string, offset int64, length int64) (Metadata, io.ReadCloser, bool, error) {
+	llog := s.log.WithLazy("userID", userID, "key", key, "offset", offset, "length", length, "method", "Get")
 
 	md, ok, err := s.metaRepo.GetMetadata(ctx, userID, key)
 	if err != nil {
@@ -183,7 +692,13 @@ func (s *service) GetEntry(ctx context.Context, userID string, key string) (Meta
 		return Metadata{}, nil, false, nil
 	}
 
-	rc, ok, err := s.blobRepo.OpenBlobReader(s.getBlobKey(userID, key))
+	if offset > md.Size {
+		llog.Debugw("offset is out of range", "size", md.Size)
+
+		return Metadata{}, nil, false, ErrInvalidRange
+	}
+
+	rsc, ok, err := s.blobRepo.OpenBlobSeeker(ctx, s.getBlobKey(userID, md.BlobName))
 	if err != nil {
 		llog.Errorw("cant get blob reader", "err", err)
 
@@ -195,29 +710,485 @@ func (s *service) GetEntry(ctx context.Context, userID string, key string) (Meta
 		return Metadata{}, nil, false, ErrInternal
 	}
 
-	return md, rc, true, nil
+	if offset > 0 {
+		_, err = rsc.Seek(offset, io.SeekStart)
+		if err != nil {
+			llog.Errorw("cant seek blob", "err", err)
+			utils.CloseAndLogError(rsc, llog)
+
+			return Metadata{}, nil, false, ErrInternal
+		}
+	}
+
+	if length > 0 {
+		return md, &limitedReadCloser{Reader: io.LimitReader(rsc, length), Closer: rsc}, true, nil
+	}
+
+	return md, rsc, true, nil
+}
+
+// limitedReadCloser reads from the limited reader and closes the underlying one.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
 }
 
-func (s *service) DeleteEntry(ctx context.Context, userID string, key string) error {
+func (s *service) DeleteEntry(ctx context.Context, userID string, key string, expectedVersion int64) error {
 	llog := s.log.WithLazy("userID", userID, "key", key, "method", "Delete")
 
-	err := s.metaRepo.DeleteMetadata(ctx, userID, key)
+	var change Change
+	var deleted bool
+	var err error
+
+	if s.options.TrashRetention > 0 {
+		change, deleted, err = s.metaRepo.TrashMetadata(ctx, userID, key, expectedVersion)
+	} else {
+		change, deleted, err = s.metaRepo.DeleteMetadata(ctx, userID, key, expectedVersion)
+	}
+	if errors.Is(err, ErrConflict) {
+		llog.Debugw("version mismatch", "expected", expectedVersion)
+
+		return ErrConflict
+	}
 	if err != nil {
 		llog.Errorw("cant delete metadata", "err", err)
 
 		return ErrInternal
 	}
 
-	err = s.blobRepo.DeleteBlob(s.getBlobKey(userID, key))
+	if !deleted {
+		return nil
+	}
+
+	// the tombstone names the blob released by the deletion
+	err = s.deleteBlob(ctx, userID, change.Metadata.BlobName)
 	if err != nil {
 		llog.Errorw("cant delete blob", "err", err)
 
 		return ErrInternal
 	}
 
+	// the versions of a trashed entry are kept, so they are there once it's restored, and purged along with it
+	if s.options.TrashRetention <= 0 {
+		s.pruneVersions(ctx, userID, key, 0, llog)
+	}
+
+	s.broker.notify(userID)
+
+	return nil
+}
+
+func (s *service) RenameEntry(ctx context.Context, userID string, key string, newKey string, newName string, newEnvelope []byte, expectedVersion int64) error {
+	llog := s.log.WithLazy("userID", userID, "key", key, "newKey", newKey, "method", "Rename")
+
+	if key == newKey {
+		return ErrEntryExists
+	}
+
+	current, exists, err := s.metaRepo.GetMetadata(ctx, userID, key)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
+
+		return ErrInternal
+	}
+
+	if !exists && expectedVersion == 0 {
+		return ErrEntryNotFound
+	}
+
+	err = s.checkExisting(current, exists, true, expectedVersion, llog)
+	if err != nil {
+		return err
+	}
+
+	_, targetExists, err := s.metaRepo.GetMetadata(ctx, userID, newKey)
+	if err != nil {
+		llog.Errorw("cant get new key metadata", "err", err)
+
+		return ErrInternal
+	}
+	if targetExists {
+		llog.Debug("new key is taken")
+
+		return ErrEntryExists
+	}
+
+	// the version we've seen before, so concurrent writes aren't lost silently.
+	// the entry and its versions keep their blobs, so there is nothing to move
+	result, err := s.metaRepo.RenameMetadata(ctx, userID, key, newKey, newName, newEnvelope, current.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrConflict):
+			llog.Warn("entry was changed during the rename")
+
+			return ErrConflict
+		case errors.Is(err, ErrEntryExists):
+			llog.Debug("new key was taken during the rename")
+
+			return ErrEntryExists
+		default:
+			llog.Errorw("cant rename metadata", "err", err)
+
+			return ErrInternal
+		}
+	}
+
+	// the versions left from a deleted entry with the new key were replaced by the moved ones
+	for _, blobName := range result.StaleBlobs {
+		s.discardBlob(ctx, userID, blobName, llog)
+	}
+
+	s.broker.notify(userID)
+
+	return nil
+}
+
+func (s *service) BatchDeleteEntries(ctx context.Context, userID string, items []DeleteItem) error {
+	llog := s.log.WithLazy("userID", userID, "count", len(items), "method", "BatchDelete")
+
+	var changes []Change
+	var err error
+
+	if s.options.TrashRetention > 0 {
+		changes, err = s.metaRepo.TrashMetadataBatch(ctx, userID, items)
+	} else {
+		changes, err = s.metaRepo.DeleteMetadataBatch(ctx, userID, items)
+	}
+	if errors.Is(err, ErrConflict) {
+		llog.Debug("version mismatch")
+
+		return ErrConflict
+	}
+	if err != nil {
+		llog.Errorw("cant delete metadata", "err", err)
+
+		return ErrInternal
+	}
+
+	// the metadata is already gone, so try to delete all the blobs, the ones left are orphans for the reconciler
+	var blobErr error
+	for _, change := range changes {
+		err = s.deleteBlob(ctx, userID, change.Metadata.BlobName)
+		if err != nil {
+			llog.Errorw("cant delete blob", "key", change.Metadata.Key, "err", err)
+
+			blobErr = ErrInternal
+		}
+	}
+
+	if s.options.TrashRetention <= 0 {
+		for _, change := range changes {
+			s.pruneVersions(ctx, userID, change.Metadata.Key, 0, llog)
+		}
+	}
+
+	if len(changes) > 0 {
+		s.broker.notify(userID)
+	}
+
+	return blobErr
+}
+
+func (s *service) BatchGetMetadata(ctx context.Context, userID string, keys []string) ([]Metadata, error) {
+	llog := s.log.WithLazy("userID", userID, "count", len(keys), "method", "BatchGetMetadata")
+
+	mds, err := s.metaRepo.GetMetadataBatch(ctx, userID, keys)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
+
+		return nil, ErrInternal
+	}
+
+	return mds, nil
+}
+
+// defaultPageSize is used when the caller didn't specify the page size.
+const defaultPageSize = 100
+
+func (s *service) ListEntries(ctx context.Context, userID string, pageToken string, pageSize int) ([]Metadata, string, error) {
+	llog := s.log.WithLazy("userID", userID, "method", "List")
+
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	// fetch one more to know if there is a next page
+	mds, err := s.metaRepo.ListMetadata(ctx, userID, ListOptions{
+		After: pageToken,
+		Limit: pageSize + 1,
+	})
+	if err != nil {
+		llog.Errorw("cant list metadata", "err", err)
+
+		return nil, "", ErrInternal
+	}
+
+	if len(mds) <= pageSize {
+		return mds, "", nil
+	}
+
+	mds = mds[:pageSize]
+
+	return mds, mds[len(mds)-1].Key, nil
+}
+
+func (s *service) ListChanges(ctx context.Context, userID string, cursor int64, pageSize int) ([]Change, int64, bool, error) {
+	llog := s.log.WithLazy("userID", userID, "cursor", cursor, "method", "ListChanges")
+
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	// fetch one more to know if there are more changes
+	changes, err := s.metaRepo.ListChanges(ctx, userID, cursor, pageSize+1)
+	if err != nil {
+		llog.Errorw("cant list changes", "err", err)
+
+		return nil, 0, false, ErrInternal
+	}
+
+	hasMore := len(changes) > pageSize
+	if hasMore {
+		changes = changes[:pageSize]
+	}
+
+	if len(changes) > 0 {
+		cursor = changes[len(changes)-1].Cursor
+	}
+
+	return changes, cursor, hasMore, nil
+}
+
+func (s *service) WatchEntries(ctx context.Context, userID string) (<-chan Change, error) {
+	llog := s.log.WithLazy("userID", userID, "method", "Watch")
+
+	ch, err := s.broker.subscribe(ctx, userID)
+	if err != nil {
+		llog.Errorw("cant subscribe watcher", "err", err)
+
+		return nil, ErrInternal
+	}
+	llog.Debug("watcher subscribed")
+
+	go func() {
+		<-ctx.Done()
+
+		s.broker.unsubscribe(userID, ch)
+		llog.Debug("watcher unsubscribed")
+	}()
+
+	return ch, nil
+}
+
+func (s *service) ListEntryVersions(ctx context.Context, userID string, key string) ([]Metadata, error) {
+	llog := s.log.WithLazy("userID", userID, "key", key, "method", "ListVersions")
+
+	mds, err := s.metaRepo.ListVersions(ctx, userID, key)
+	if err != nil {
+		llog.Errorw("cant list versions", "err", err)
+
+		return nil, ErrInternal
+	}
+
+	return mds, nil
+}
+
+func (s *service) GetEntryVersion(ctx context.Context, userID string, key string, version int64) (Metadata, io.ReadCloser, bool, error) {
+	llog := s.log.WithLazy("userID", userID, "key", key, "version", version, "method", "GetVersion")
+
+	md, ok, err := s.metaRepo.GetVersion(ctx, userID, key, version)
+	if err != nil {
+		llog.Errorw("cant get version metadata", "err", err)
+
+		return Metadata{}, nil, false, ErrInternal
+	}
+
+	if !ok {
+		return Metadata{}, nil, false, nil
+	}
+
+	rc, ok, err := s.blobRepo.OpenBlobReader(ctx, s.getBlobKey(userID, md.BlobName))
+	if err != nil {
+		llog.Errorw("cant get blob reader", "err", err)
+
+		return Metadata{}, nil, false, ErrInternal
+	}
+	if !ok {
+		llog.Errorw("blob not found")
+
+		return Metadata{}, nil, false, ErrInternal
+	}
+
+	return md, rc, true, nil
+}
+
+func (s *service) RestoreEntryVersion(ctx context.Context, userID string, key string, version int64) error {
+	llog := s.log.WithLazy("userID", userID, "key", key, "version", version, "method", "RestoreVersion")
+
+	md, ok, err := s.metaRepo.GetVersion(ctx, userID, key, version)
+	if err != nil {
+		llog.Errorw("cant get version metadata", "err", err)
+
+		return ErrInternal
+	}
+
+	if !ok {
+		return ErrVersionNotFound
+	}
+
+	current, exists, err := s.metaRepo.GetMetadata(ctx, userID, key)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
+
+		return ErrInternal
+	}
+
+	// the copy of the version is counted in the usage like a new content
+	limit, err := s.getUploadLimit(ctx, userID, current, exists, llog)
+	if err != nil {
+		return err
+	}
+	if md.Size > limit {
+		llog.Debugw("version is larger than the quota allows", "size", md.Size, "limit", limit)
+
+		return ErrQuotaExceeded
+	}
+
+	// copied to a new blob, so the version is swapped in the same way as a new content and keeps its own one
+	versionBlobName := md.BlobName
+	md.BlobName = s.newContentBlobName()
+
+	ok, err = s.copyBlob(ctx, s.getBlobKey(userID, versionBlobName), s.getBlobKey(userID, md.BlobName))
+	if err != nil {
+		llog.Errorw("cant copy version blob", "err", err)
+		s.discardBlob(ctx, userID, md.BlobName, llog)
+
+		return ErrInternal
+	}
+	if !ok {
+		llog.Errorw("version blob not found")
+
+		return ErrInternal
+	}
+
+	err = s.swapContent(ctx, userID, "", md, current, exists, llog)
+	if err != nil {
+		s.discardBlob(ctx, userID, md.BlobName, llog)
+
+		return err
+	}
+
+	s.broker.notify(userID)
+
 	return nil
 }
 
-func (s *service) getBlobKey(userID string, key string) string {
-	return fmt.Sprintf("%s/%s", userID, key)
+func (s *service) GetUsage(ctx context.Context, userID string) (Usage, error) {
+	llog := s.log.WithLazy("userID", userID, "method", "GetUsage")
+
+	usage, err := s.metaRepo.GetUsage(ctx, userID)
+	if err != nil {
+		llog.Errorw("cant get usage", "err", err)
+
+		return Usage{}, ErrInternal
+	}
+
+	usage.MaxBytes = s.options.MaxBytes
+	usage.MaxEntries = s.options.MaxEntries
+
+	return usage, nil
+}
+
+func (s *service) ListUsage(ctx context.Context) (map[string]Usage, error) {
+	usages, err := s.metaRepo.ListUsage(ctx)
+	if err != nil {
+		s.log.Errorw("cant list usage", "err", err)
+
+		return nil, ErrInternal
+	}
+
+	for userID, usage := range usages {
+		usage.MaxBytes = s.options.MaxBytes
+		usage.MaxEntries = s.options.MaxEntries
+		usages[userID] = usage
+	}
+
+	return usages, nil
+}
+
+// pruneVersions deletes all but the newest keep versions of the entry along with their blobs.
+// Errors are only logged, the entry itself is not affected by them.
+func (s *service) pruneVersions(ctx context.Context, userID string, key string, keep int, llog *zap.SugaredLogger) {
+	pruned, err := s.metaRepo.PruneVersions(ctx, userID, key, keep)
+	if err != nil {
+		llog.Errorw("cant prune versions", "err", err)
+
+		return
+	}
+
+	for _, blobName := range pruned {
+		s.discardBlob(ctx, userID, blobName, llog)
+	}
+}
+
+// copyBlob copies the blob under srcKey to dstKey, overwriting it.
+// It returns false if the source blob doesn't exist.
+func (s *service) copyBlob(ctx context.Context, srcKey string, dstKey string) (bool, error) {
+	src, ok, err := s.blobRepo.OpenBlobReader(ctx, srcKey)
+	if err != nil {
+		return false, fmt.Errorf("cant open source blob: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	defer utils.CloseAndLogError(src, s.log)
+
+	dst, err := s.blobRepo.OpenBlobWriter(ctx, dstKey)
+	if err != nil {
+		return false, fmt.Errorf("cant open destination blob: %w", err)
+	}
+
+	_, err = utils.CopyContext(ctx, dst, src)
+	if err != nil {
+		// don't let the partial copy replace the destination
+		if a, ok := dst.(blob.Aborter); ok {
+			abortErr := a.Abort()
+			if abortErr != nil {
+				s.log.Errorw("cant abort copy", "err", abortErr)
+			}
+		} else {
+			utils.CloseAndLogError(dst, s.log)
+		}
+
+		return false, fmt.Errorf("cant copy blob: %w", err)
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return false, fmt.Errorf("cant close destination blob: %w", err)
+	}
+
+	return true, nil
+}
+
+// getBlobKey returns the key of the blob among the blobs of the user.
+func (s *service) getBlobKey(userID string, blobName string) string {
+	return fmt.Sprintf("%s/%s", userID, blobName)
+}
+
+// newContentBlobName returns the name of a new blob for the content of an entry.
+// Entry keys are hex hashes, so contents dir never clashes with the blobs stored under them before.
+func (s *service) newContentBlobName() string {
+	return "contents/" + uuid.NewString()
+}
+
+// getUploadBlobName returns the name of the blob of the content uploaded in the session.
+// Entry keys are hex hashes, so uploads dir never clashes with them.
+func (s *service) getUploadBlobName(uploadID string) string {
+	return "uploads/" + uploadID
+}
+
+// getUploadBlobKey returns the blob key of the content uploaded in the session.
+func (s *service) getUploadBlobKey(userID string, uploadID string) string {
+	return s.getBlobKey(userID, s.getUploadBlobName(uploadID))
 }
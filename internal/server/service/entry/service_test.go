@@ -3,18 +3,58 @@ package entry_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"io"
+	"io/fs"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
+var defaultOptions = entry.Options{
+	MaxVersions: 3,
+}
+
+// contentKey matches the keys of the new blobs the content of the entries of the user "user" is written to.
+var contentKey = gomock.Cond(func(key string) bool {
+	return strings.HasPrefix(key, "user/contents/")
+})
+
+// withContentBlob matches the metadata equal to md except for the name of a new content blob.
+func withContentBlob(md entry.Metadata) gomock.Matcher {
+	return gomock.Cond(func(got entry.Metadata) bool {
+		if !strings.HasPrefix(got.BlobName, "contents/") {
+			return false
+		}
+
+		got.BlobName = ""
+
+		return gomock.Eq(md).Matches(got)
+	})
+}
+
+// expectChangeLog makes the change log of the user, watched from an empty log, return the changes once.
+func expectChangeLog(metaRepo *mocks.MockMetadataRepository, userID string, changes ...entry.Change) {
+	metaRepo.EXPECT().GetLatestCursor(gomock.Any(), userID).Return(int64(0), nil).AnyTimes()
+	metaRepo.EXPECT().ListChanges(gomock.Any(), userID, int64(0), gomock.Any()).Return(changes, nil).MaxTimes(1)
+	metaRepo.EXPECT().ListChanges(gomock.Any(), userID, gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+}
+
+// expectLease makes the upload session of the user "user" leased and released once.
+func expectLease(metaRepo *mocks.MockMetadataRepository) {
+	metaRepo.EXPECT().LeaseUploadSession(gomock.Any(), "user", "upload", gomock.Any(), time.Minute).Return(true, nil)
+	metaRepo.EXPECT().ReleaseUploadSession(gomock.Any(), "user", "upload", gomock.Any()).Return(nil)
+}
+
 func TestService_Set(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
@@ -29,8 +69,8 @@ func TestService_Set(t *testing.T) {
 
 			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, true, nil)
 
-			s := entry.New(metaRepo, blobRepo)
-			upload, result, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key"}, false)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			upload, result, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key"}, false, 0)
 			require.ErrorIs(t, err, entry.ErrEntryExists)
 			require.Nil(t, upload)
 			require.Nil(t, result)
@@ -45,8 +85,8 @@ func TestService_Set(t *testing.T) {
 
 			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, errors.New("query error"))
 
-			s := entry.New(metaRepo, blobRepo)
-			upload, result, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key"}, false)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			upload, result, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key"}, false, 0)
 			require.ErrorIs(t, err, entry.ErrInternal)
 			require.Nil(t, upload)
 			require.Nil(t, result)
@@ -64,16 +104,24 @@ func TestService_Set(t *testing.T) {
 				Key:   "key",
 				Name:  "name",
 				Notes: []byte("notes"),
+				Type:  "login",
 			}
 
 			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
-			writer.EXPECT().Write([]byte("chunk")).Return(0, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+			writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
 			writer.EXPECT().Close().Return(nil)
-			metaRepo.EXPECT().SetMetadata(ctx, "user", md).Return(nil)
-
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, false)
+			metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{
+				Key:      "key",
+				Name:     "name",
+				Notes:    []byte("notes"),
+				Type:     "login",
+				Size:     5,
+				Checksum: checksum("chunk"),
+			}), int64(0), false).Return(entry.Change{}, nil)
+
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, false, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -98,15 +146,24 @@ func TestService_Set(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("notes"),
+			Type:  "file",
 		}
 
-		blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
-		writer.EXPECT().Write([]byte("chunk")).Return(0, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
 		writer.EXPECT().Close().Return(nil)
-		metaRepo.EXPECT().SetMetadata(ctx, "user", md).Return(nil)
-
-		s := entry.New(metaRepo, blobRepo)
-		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{
+			Key:      "key",
+			Name:     "name",
+			Notes:    []byte("notes"),
+			Type:     "file",
+			Size:     5,
+			Checksum: checksum("chunk"),
+		}), int64(0), false).Return(entry.Change{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 		require.NoError(t, err)
 		require.NotNil(t, uploadChan)
 		require.NotNil(t, resultChan)
@@ -133,14 +190,16 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Write([]byte("chunk")).Return(0, nil)
 			writer.EXPECT().Close().Return(nil)
-			metaRepo.EXPECT().SetMetadata(ctx, "user", md).Return(errors.New("query failed"))
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
+			metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Notes: []byte("notes"), Checksum: checksum("")}), int64(0), false).
+				Return(entry.Change{}, errors.New("query failed"))
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -166,14 +225,16 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Write([]byte("chunk")).Return(0, nil)
 			writer.EXPECT().Close().Return(nil)
-			metaRepo.EXPECT().SetMetadata(ctx, "user", md).Return(errors.New("query failed"))
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(errors.New("close fail"))
+			metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Notes: []byte("notes"), Checksum: checksum("")}), int64(0), false).
+				Return(entry.Change{}, errors.New("query failed"))
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(errors.New("close fail"))
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -201,13 +262,14 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Write([]byte("chunk")).Return(0, nil)
 			writer.EXPECT().Close().Return(errors.New("close failed"))
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -233,13 +295,14 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Write([]byte("chunk")).Return(0, nil)
 			writer.EXPECT().Close().Return(errors.New("close failed"))
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(errors.New("delete failed"))
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(errors.New("delete failed"))
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -265,10 +328,11 @@ func TestService_Set(t *testing.T) {
 			Notes: []byte("notes"),
 		}
 
-		blobRepo.EXPECT().OpenBlobWriter("user/key").Return(nil, errors.New("cant open writer"))
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(nil, errors.New("cant open writer"))
 
-		s := entry.New(metaRepo, blobRepo)
-		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 		require.ErrorIs(t, err, entry.ErrInternal)
 		require.Nil(t, uploadChan)
 		require.Nil(t, resultChan)
@@ -289,12 +353,13 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Close().Return(nil)
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -319,12 +384,13 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Close().Return(errors.New("close failed"))
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(errors.New("delete failed"))
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(errors.New("delete failed"))
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -349,16 +415,17 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
-			writer.EXPECT().Close().Return(nil)
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
-
 			localCtx, cancel := context.WithCancel(ctx)
 			// already closed
 			cancel()
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(localCtx, "user", md, true)
+			metaRepo.EXPECT().GetMetadata(localCtx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+			writer.EXPECT().Close().Return(nil)
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
+
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(localCtx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -383,12 +450,13 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Close().Return(nil)
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -414,13 +482,14 @@ func TestService_Set(t *testing.T) {
 				Notes: []byte("notes"),
 			}
 
-			blobRepo.EXPECT().OpenBlobWriter("user/key").Return(writer, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
 			writer.EXPECT().Write([]byte("chunk")).Return(0, errors.New("write failed"))
 			writer.EXPECT().Close().Return(nil)
-			blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
+			blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-			s := entry.New(metaRepo, blobRepo)
-			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true)
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
 			require.NoError(t, err)
 			require.NotNil(t, uploadChan)
 			require.NotNil(t, resultChan)
@@ -432,171 +501,2917 @@ func TestService_Set(t *testing.T) {
 			require.ErrorIs(t, result.Err, entry.ErrInternal)
 		})
 	})
+
+	t.Run("aborting writer", func(t *testing.T) {
+		md := entry.Metadata{
+			Key:   "key",
+			Name:  "name",
+			Notes: []byte("notes"),
+		}
+
+		t.Run("upload failed", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metaRepo := mocks.NewMockMetadataRepository(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+			writer := &abortingWriter{MockWriteCloser: mocks.NewMockWriteCloser(ctrl)}
+
+			// neither closed nor deleted, the writer discards the content itself
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+			writer.EXPECT().Write([]byte("chunk")).Return(0, errors.New("write failed"))
+
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
+			require.NoError(t, err)
+
+			uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+			close(uploadChan)
+
+			result := <-resultChan
+			require.ErrorIs(t, result.Err, entry.ErrInternal)
+			require.True(t, writer.aborted)
+		})
+
+		t.Run("abort err", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metaRepo := mocks.NewMockMetadataRepository(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+			writer := &abortingWriter{MockWriteCloser: mocks.NewMockWriteCloser(ctrl), abortErr: errors.New("abort failed")}
+
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
+			require.NoError(t, err)
+
+			close(uploadChan)
+
+			result := <-resultChan
+			require.ErrorIs(t, result.Err, entry.ErrInternal)
+			require.True(t, writer.aborted)
+		})
+
+		t.Run("close err", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metaRepo := mocks.NewMockMetadataRepository(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+			writer := &abortingWriter{MockWriteCloser: mocks.NewMockWriteCloser(ctrl)}
+
+			// failed close discards the data itself, the new blob isn't deleted
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+			blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+			writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+			writer.EXPECT().Close().Return(errors.New("close failed"))
+
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
+			require.NoError(t, err)
+
+			uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+			close(uploadChan)
+
+			result := <-resultChan
+			require.ErrorIs(t, result.Err, entry.ErrInternal)
+			require.False(t, writer.aborted)
+		})
+	})
 }
 
-func TestService_Get(t *testing.T) {
+func TestService_Set_Versions(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
 
-	t.Run("success", func(t *testing.T) {
+	current := entry.Metadata{
+		Key:      "key",
+		Name:     "old name",
+		Type:     "file",
+		Size:     3,
+		Version:  2,
+		BlobName: "contents/old",
+	}
+
+	t.Run("current version is kept", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
-		reader := io.NopCloser(bytes.NewBuffer(nil))
+		writer := mocks.NewMockWriteCloser(ctrl)
 
-		md := entry.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
-		}
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
 
-		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
-		blobRepo.EXPECT().OpenBlobReader("user/key").Return(reader, true, nil)
+		// upload
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		// the replaced metadata becomes a version along with the commit, keeping its blob
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Type: "file", Size: 5, Checksum: checksum("chunk")}), current.Version, true).Return(entry.Change{}, nil)
+
+		// prune
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "key", 3).Return([]string{"contents/first"}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/first").Return(nil)
 
-		s := entry.New(metaRepo, blobRepo)
-		meta, r, ok, err := s.GetEntry(ctx, "user", "key")
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name", Type: "file"}, true, 0)
 		require.NoError(t, err)
-		require.True(t, ok)
-		require.Equal(t, md, meta)
-		require.NotNil(t, r)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
 	})
 
-	t.Run("metadata not found", func(t *testing.T) {
+	t.Run("history disabled", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
 
-		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Size: 5, Checksum: checksum("chunk")}), current.Version, false).Return(entry.Change{}, nil)
+		// nothing refers to the replaced content anymore
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/old").Return(nil)
 
-		s := entry.New(metaRepo, blobRepo)
-		meta, r, ok, err := s.GetEntry(ctx, "user", "key")
+		s := entry.New(metaRepo, blobRepo, entry.Options{})
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, true, 0)
 		require.NoError(t, err)
-		require.False(t, ok)
-		require.Equal(t, entry.Metadata{}, meta)
-		require.Nil(t, r)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
 	})
 
-	t.Run("metadata repo err", func(t *testing.T) {
+	t.Run("replaced blob cant be deleted", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
 
-		md := entry.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
-		}
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Size: 5, Checksum: checksum("chunk")}), current.Version, false).Return(entry.Change{}, nil)
+		// left for the reconciler, the entry is already written
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/old").Return(errors.New("io error"))
 
-		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, false, errors.New("query failed"))
+		s := entry.New(metaRepo, blobRepo, entry.Options{})
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, true, 0)
+		require.NoError(t, err)
 
-		s := entry.New(metaRepo, blobRepo)
-		meta, r, ok, err := s.GetEntry(ctx, "user", "key")
-		require.ErrorIs(t, err, entry.ErrInternal)
-		require.False(t, ok)
-		require.Equal(t, entry.Metadata{}, meta)
-		require.Nil(t, r)
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
 	})
 
-	t.Run("blob repo err", func(t *testing.T) {
+	t.Run("failed upload keeps history", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
 
-		md := entry.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
-		}
+		// neither archived nor pruned
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Close().Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
-		blobRepo.EXPECT().OpenBlobReader("user/key").Return(nil, false, errors.New("query failed"))
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, true, 0)
+		require.NoError(t, err)
 
-		s := entry.New(metaRepo, blobRepo)
-		meta, r, ok, err := s.GetEntry(ctx, "user", "key")
-		require.ErrorIs(t, err, entry.ErrInternal)
-		require.False(t, ok)
-		require.Equal(t, entry.Metadata{}, meta)
-		require.Nil(t, r)
+		uploadChan <- entry.UploadChunk{Err: errors.New("connection lost")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrUploadChunk)
 	})
 
-	t.Run("blob not found", func(t *testing.T) {
+	t.Run("conflict keeps history", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
 
-		md := entry.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
-		}
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", gomock.Any(), current.Version, true).Return(entry.Change{}, entry.ErrConflict)
+		// only the new blob is deleted, the current one is left to the entry
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
 
-		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
-		blobRepo.EXPECT().OpenBlobReader("user/key").Return(nil, false, nil)
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, true, 0)
+		require.NoError(t, err)
 
-		s := entry.New(metaRepo, blobRepo)
-		meta, r, ok, err := s.GetEntry(ctx, "user", "key")
-		require.ErrorIs(t, err, entry.ErrInternal)
-		require.False(t, ok)
-		require.Equal(t, entry.Metadata{}, meta)
-		require.Nil(t, r)
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrConflict)
 	})
 }
 
-func TestService_Delete(t *testing.T) {
+func TestService_Set_ExpectedVersion(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
 
-	t.Run("success", func(t *testing.T) {
+	current := entry.Metadata{
+		Key:      "key",
+		Name:     "old name",
+		Version:  2,
+		BlobName: "contents/old",
+	}
+
+	t.Run("version matches", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
 
-		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key").Return(nil)
-		blobRepo.EXPECT().DeleteBlob("user/key").Return(nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Size: 5, Checksum: checksum("chunk")}), int64(2), false).Return(entry.Change{}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/old").Return(nil)
 
-		s := entry.New(metaRepo, blobRepo)
-		err := s.DeleteEntry(ctx, "user", "key")
+		s := entry.New(metaRepo, blobRepo, entry.Options{})
+		// overwrite flag is implied by the expected version
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, false, 2)
 		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
 	})
 
-	t.Run("metadata delete err", func(t *testing.T) {
+	t.Run("version mismatch", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
 
-		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key").Return(errors.New("query failed"))
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
 
-		s := entry.New(metaRepo, blobRepo)
-		err := s.DeleteEntry(ctx, "user", "key")
-		require.ErrorIs(t, err, entry.ErrInternal)
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, true, 1)
+		require.ErrorIs(t, err, entry.ErrConflict)
+		require.Nil(t, uploadChan)
+		require.Nil(t, resultChan)
 	})
 
-	t.Run("blob delete err", func(t *testing.T) {
+	t.Run("entry deleted", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		metaRepo := mocks.NewMockMetadataRepository(ctrl)
 		blobRepo := mocks.NewMockBlobRepository(ctrl)
 
-		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key").Return(nil)
-		blobRepo.EXPECT().DeleteBlob("user/key").Return(errors.New("query failed"))
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
 
-		s := entry.New(metaRepo, blobRepo)
-		err := s.DeleteEntry(ctx, "user", "key")
-		require.ErrorIs(t, err, entry.ErrInternal)
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, false, 2)
+		require.ErrorIs(t, err, entry.ErrConflict)
+		require.Nil(t, uploadChan)
+		require.Nil(t, resultChan)
+	})
+
+	t.Run("changed during upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		// created by someone else in the meantime, its content is left alone
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(entry.Metadata{Key: "key", Name: "name", Size: 5, Checksum: checksum("chunk")}), int64(0), false).Return(entry.Change{}, entry.ErrConflict)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", entry.Metadata{Key: "key", Name: "name"}, false, 0)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrConflict)
+	})
+}
+
+func TestService_Get(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		reader := readSeekNopCloser{bytes.NewReader(nil)}
+
+		md := entry.Metadata{
+			Key:      "key",
+			Name:     "name",
+			Notes:    []byte("notes"),
+			BlobName: "contents/1",
+		}
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
+		blobRepo.EXPECT().OpenBlobSeeker(gomock.Any(), "user/contents/1").Return(reader, true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		meta, r, ok, err := s.GetEntry(ctx, "user", "key", 0, 0)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, md, meta)
+		require.NotNil(t, r)
+	})
+
+	t.Run("range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		reader := readSeekNopCloser{bytes.NewReader([]byte("hello world"))}
+
+		md := entry.Metadata{Key: "key", Size: 11, BlobName: "contents/1"}
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
+		blobRepo.EXPECT().OpenBlobSeeker(gomock.Any(), "user/contents/1").Return(reader, true, nil).Times(2)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, r, ok, err := s.GetEntry(ctx, "user", "key", 6, 3)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "wor", string(content))
+
+		// up to the end
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
+		_, r, ok, err = s.GetEntry(ctx, "user", "key", 6, 0)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		content, err = io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "world", string(content))
+	})
+
+	t.Run("offset out of range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Size: 11, BlobName: "contents/1"}, true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, r, ok, err := s.GetEntry(ctx, "user", "key", 12, 0)
+		require.ErrorIs(t, err, entry.ErrInvalidRange)
+		require.False(t, ok)
+		require.Nil(t, r)
+	})
+
+	t.Run("cant seek", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		reader := mocks.NewMockReadSeekCloser(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Size: 11, BlobName: "contents/1"}, true, nil)
+		blobRepo.EXPECT().OpenBlobSeeker(gomock.Any(), "user/contents/1").Return(reader, true, nil)
+		reader.EXPECT().Seek(int64(6), io.SeekStart).Return(int64(0), errors.New("error"))
+		reader.EXPECT().Close().Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, r, ok, err := s.GetEntry(ctx, "user", "key", 6, 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.False(t, ok)
+		require.Nil(t, r)
+	})
+
+	t.Run("metadata not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		meta, r, ok, err := s.GetEntry(ctx, "user", "key", 0, 0)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Equal(t, entry.Metadata{}, meta)
+		require.Nil(t, r)
+	})
+
+	t.Run("metadata repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		md := entry.Metadata{
+			Key:      "key",
+			Name:     "name",
+			Notes:    []byte("notes"),
+			BlobName: "contents/1",
+		}
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, false, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		meta, r, ok, err := s.GetEntry(ctx, "user", "key", 0, 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.False(t, ok)
+		require.Equal(t, entry.Metadata{}, meta)
+		require.Nil(t, r)
+	})
+
+	t.Run("blob repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		md := entry.Metadata{
+			Key:      "key",
+			Name:     "name",
+			Notes:    []byte("notes"),
+			BlobName: "contents/1",
+		}
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
+		blobRepo.EXPECT().OpenBlobSeeker(gomock.Any(), "user/contents/1").Return(nil, false, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		meta, r, ok, err := s.GetEntry(ctx, "user", "key", 0, 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.False(t, ok)
+		require.Equal(t, entry.Metadata{}, meta)
+		require.Nil(t, r)
+	})
+
+	t.Run("blob not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		md := entry.Metadata{
+			Key:      "key",
+			Name:     "name",
+			Notes:    []byte("notes"),
+			BlobName: "contents/1",
+		}
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(md, true, nil)
+		blobRepo.EXPECT().OpenBlobSeeker(gomock.Any(), "user/contents/1").Return(nil, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		meta, r, ok, err := s.GetEntry(ctx, "user", "key", 0, 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.False(t, ok)
+		require.Equal(t, entry.Metadata{}, meta)
+		require.Nil(t, r)
+	})
+}
+
+// readSeekNopCloser adds a no-op Close to the io.ReadSeeker.
+type readSeekNopCloser struct {
+	io.ReadSeeker
+}
+
+func (readSeekNopCloser) Close() error {
+	return nil
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{Metadata: entry.Metadata{Key: "key", BlobName: "contents/3"}, Deleted: true}, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/3").Return(nil)
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "key", 0).Return([]string{"contents/2", "contents/1"}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(errors.New("not found"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("metadata delete err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{}, false, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(2)).Return(entry.Change{}, false, entry.ErrConflict)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 2)
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+
+	t.Run("blob delete err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{Metadata: entry.Metadata{Key: "key", BlobName: "contents/3"}, Deleted: true}, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/3").Return(errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("blob already deleted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{Metadata: entry.Metadata{Key: "key", BlobName: "contents/3"}, Deleted: true}, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/3").Return(fs.ErrNotExist)
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "key", 0).Return([]string{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("nothing deleted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		// neither the blobs nor the versions are touched
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+	})
+}
+
+func TestService_Rename(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "new").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().RenameMetadata(ctx, "user", "key", "new", "new name", []byte("envelope"), int64(3)).Return(entry.RenameResult{
+			Deleted:    entry.Change{Metadata: entry.Metadata{Key: "key", Version: 3}, Deleted: true},
+			Renamed:    entry.Change{Metadata: entry.Metadata{Key: "new", Name: "new name", Version: 4}},
+			StaleBlobs: []string{"contents/1", "contents/2"},
+		}, nil)
+		// the versions of a deleted entry with the new key were replaced
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(nil)
+		// the rename isn't failed because of them
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(errors.New("io error"))
+
+		expectChangeLog(metaRepo, "user",
+			entry.Change{Cursor: 1, Metadata: entry.Metadata{Key: "key", Version: 3}, Deleted: true},
+			entry.Change{Cursor: 2, Metadata: entry.Metadata{Key: "new", Name: "new name", Version: 4}},
+		)
+
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		defer cancelWatch()
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+
+		err = s.RenameEntry(ctx, "user", "key", "new", "new name", []byte("envelope"), 0)
+		require.NoError(t, err)
+
+		change := <-changes
+		require.Equal(t, "key", change.Metadata.Key)
+		require.True(t, change.Deleted)
+
+		change = <-changes
+		require.Equal(t, "new", change.Metadata.Key)
+		require.Equal(t, int64(4), change.Metadata.Version)
+		require.False(t, change.Deleted)
+	})
+
+	t.Run("same key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockMetadataRepository(ctrl), mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		err := s.RenameEntry(ctx, "user", "key", "key", "name", nil, 0)
+		require.ErrorIs(t, err, entry.ErrEntryExists)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		err := s.RenameEntry(ctx, "user", "key", "new", "new name", nil, 0)
+		require.ErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		err := s.RenameEntry(ctx, "user", "key", "new", "new name", nil, 2)
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+
+	t.Run("new key taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "new").Return(entry.Metadata{Key: "new", Version: 1}, true, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		err := s.RenameEntry(ctx, "user", "key", "new", "new name", nil, 0)
+		require.ErrorIs(t, err, entry.ErrEntryExists)
+	})
+
+	for name, tc := range map[string]struct {
+		repoErr  error
+		expected error
+	}{
+		"changed during rename": {repoErr: entry.ErrConflict, expected: entry.ErrConflict},
+		"taken during rename":   {repoErr: entry.ErrEntryExists, expected: entry.ErrEntryExists},
+		"metadata rename err":   {repoErr: errors.New("query failed"), expected: entry.ErrInternal},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metaRepo := mocks.NewMockMetadataRepository(ctrl)
+			blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+			metaRepo.EXPECT().GetMetadata(ctx, "user", "new").Return(entry.Metadata{}, false, nil)
+			metaRepo.EXPECT().RenameMetadata(ctx, "user", "key", "new", "new name", []byte(nil), int64(3)).Return(entry.RenameResult{}, tc.repoErr)
+
+			s := entry.New(metaRepo, blobRepo, defaultOptions)
+			err := s.RenameEntry(ctx, "user", "key", "new", "new name", nil, 0)
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestService_BatchDelete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	items := []entry.DeleteItem{{Key: "first", ExpectedVersion: 2}, {Key: "second"}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadataBatch(ctx, "user", items).Return([]entry.Change{
+			{Metadata: entry.Metadata{Key: "first", Version: 2, BlobName: "contents/2"}, Deleted: true},
+		}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(nil)
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "first", 0).Return([]string{"contents/1"}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(nil)
+
+		expectChangeLog(metaRepo, "user", entry.Change{Cursor: 1, Metadata: entry.Metadata{Key: "first", Version: 2}, Deleted: true})
+
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		defer cancelWatch()
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+
+		err = s.BatchDeleteEntries(ctx, "user", items)
+		require.NoError(t, err)
+
+		change := <-changes
+		require.Equal(t, "first", change.Metadata.Key)
+		require.True(t, change.Deleted)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		// nothing is deleted, so no blobs are touched
+		metaRepo.EXPECT().DeleteMetadataBatch(ctx, "user", items).Return(nil, entry.ErrConflict)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.BatchDeleteEntries(ctx, "user", items)
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+
+	t.Run("metadata delete err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadataBatch(ctx, "user", items).Return(nil, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.BatchDeleteEntries(ctx, "user", items)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("blob delete err doesn't stop others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadataBatch(ctx, "user", items).Return([]entry.Change{
+			{Metadata: entry.Metadata{Key: "first", Version: 2, BlobName: "contents/2"}, Deleted: true},
+			{Metadata: entry.Metadata{Key: "second", Version: 1, BlobName: "contents/1"}, Deleted: true},
+		}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(errors.New("io error"))
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "first", 0).Return([]string{}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(nil)
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "second", 0).Return([]string{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.BatchDeleteEntries(ctx, "user", items)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_BatchGetMetadata(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadataBatch(ctx, "user", []string{"first", "missing"}).Return([]entry.Metadata{{Key: "first"}}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		mds, err := s.BatchGetMetadata(ctx, "user", []string{"first", "missing"})
+		require.NoError(t, err)
+		require.Equal(t, []entry.Metadata{{Key: "first"}}, mds)
+	})
+
+	t.Run("repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadataBatch(ctx, "user", []string{"first"}).Return(nil, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.BatchGetMetadata(ctx, "user", []string{"first"})
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_WatchEntries(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	tombstone := entry.Change{Cursor: 5, Metadata: entry.Metadata{Key: "key", Version: 2, BlobName: "contents/2"}, Deleted: true}
+
+	t.Run("fan out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(tombstone, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(nil)
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "key", 0).Return([]string{}, nil)
+		expectChangeLog(metaRepo, "user", tombstone)
+		expectChangeLog(metaRepo, "other user")
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		defer watchCancel()
+
+		first, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+		second, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+		other, err := s.WatchEntries(watchCtx, "other user")
+		require.NoError(t, err)
+
+		err = s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+
+		require.Equal(t, tombstone, <-first)
+		require.Equal(t, tombstone, <-second)
+		require.Empty(t, other)
+	})
+
+	t.Run("nothing deleted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{}, false, nil)
+		expectChangeLog(metaRepo, "user")
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		defer watchCancel()
+
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+
+		err = s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("closed on disconnect", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		expectChangeLog(metaRepo, "user")
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+		watchCancel()
+
+		select {
+		case _, ok := <-changes:
+			require.False(t, ok)
+		case <-ctx.Done():
+			t.Fatal("channel wasn't closed")
+		}
+	})
+
+	t.Run("slow watcher is dropped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "key", int64(0)).Return(tombstone, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(nil)
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "key", 0).Return([]string{}, nil)
+
+		// more changes than a watcher can lag behind were made meanwhile
+		backlog := make([]entry.Change, 100)
+		for i := range backlog {
+			backlog[i] = entry.Change{Cursor: int64(i + 1), Metadata: entry.Metadata{Key: "key"}, Deleted: true}
+		}
+		metaRepo.EXPECT().GetLatestCursor(gomock.Any(), "user").Return(int64(0), nil)
+		metaRepo.EXPECT().ListChanges(gomock.Any(), "user", int64(0), 64).Return(backlog[:64], nil)
+		metaRepo.EXPECT().ListChanges(gomock.Any(), "user", int64(64), 64).Return(backlog[64:], nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		defer watchCancel()
+
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+
+		err = s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+
+		// never read until now, so the buffer overflows
+		received := 0
+		for range changes {
+			received++
+		}
+		require.Less(t, received, 100)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("last page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		mds := []entry.Metadata{{Key: "key1"}, {Key: "key2"}}
+		metaRepo.EXPECT().ListMetadata(ctx, "user", entry.ListOptions{After: "token", Limit: 3}).Return(mds, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, err := s.ListEntries(ctx, "user", "token", 2)
+		require.NoError(t, err)
+		require.Equal(t, mds, list)
+		require.Empty(t, next)
+	})
+
+	t.Run("has next page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListMetadata(ctx, "user", entry.ListOptions{Limit: 3}).Return([]entry.Metadata{{Key: "key1"}, {Key: "key2"}, {Key: "key3"}}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, err := s.ListEntries(ctx, "user", "", 2)
+		require.NoError(t, err)
+		require.Equal(t, []entry.Metadata{{Key: "key1"}, {Key: "key2"}}, list)
+		require.Equal(t, "key2", next)
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListMetadata(ctx, "user", entry.ListOptions{Limit: 101}).Return([]entry.Metadata{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, err := s.ListEntries(ctx, "user", "", 0)
+		require.NoError(t, err)
+		require.Empty(t, list)
+		require.Empty(t, next)
+	})
+
+	t.Run("repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListMetadata(ctx, "user", gomock.Any()).Return(nil, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, err := s.ListEntries(ctx, "user", "", 10)
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.Nil(t, list)
+		require.Empty(t, next)
+	})
+}
+
+func TestService_ListChanges(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("no more changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		changes := []entry.Change{{Cursor: 11}, {Cursor: 14, Deleted: true}}
+		metaRepo.EXPECT().ListChanges(ctx, "user", int64(10), 3).Return(changes, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, hasMore, err := s.ListChanges(ctx, "user", 10, 2)
+		require.NoError(t, err)
+		require.Equal(t, changes, list)
+		require.Equal(t, int64(14), next)
+		require.False(t, hasMore)
+	})
+
+	t.Run("has more", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListChanges(ctx, "user", int64(0), 3).Return([]entry.Change{{Cursor: 1}, {Cursor: 2}, {Cursor: 3}}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, hasMore, err := s.ListChanges(ctx, "user", 0, 2)
+		require.NoError(t, err)
+		require.Equal(t, []entry.Change{{Cursor: 1}, {Cursor: 2}}, list)
+		require.Equal(t, int64(2), next)
+		require.True(t, hasMore)
+	})
+
+	t.Run("no changes keeps cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListChanges(ctx, "user", int64(10), 101).Return([]entry.Change{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, next, hasMore, err := s.ListChanges(ctx, "user", 10, 0)
+		require.NoError(t, err)
+		require.Empty(t, list)
+		require.Equal(t, int64(10), next)
+		require.False(t, hasMore)
+	})
+
+	t.Run("repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListChanges(ctx, "user", int64(0), 3).Return(nil, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, _, _, err := s.ListChanges(ctx, "user", 0, 2)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+func TestService_ListVersions(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		mds := []entry.Metadata{{Key: "key", Version: 2}, {Key: "key", Version: 1}}
+		metaRepo.EXPECT().ListVersions(ctx, "user", "key").Return(mds, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, err := s.ListEntryVersions(ctx, "user", "key")
+		require.NoError(t, err)
+		require.Equal(t, mds, list)
+	})
+
+	t.Run("repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListVersions(ctx, "user", "key").Return(nil, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		list, err := s.ListEntryVersions(ctx, "user", "key")
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.Nil(t, list)
+	})
+}
+
+func TestService_GetVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	md := entry.Metadata{
+		Key:      "key",
+		Name:     "name",
+		Version:  2,
+		BlobName: "contents/2",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		reader := io.NopCloser(bytes.NewBuffer(nil))
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(2)).Return(md, true, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/contents/2").Return(reader, true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		meta, r, ok, err := s.GetEntryVersion(ctx, "user", "key", 2)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, md, meta)
+		require.Equal(t, reader, r)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(2)).Return(entry.Metadata{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, r, ok, err := s.GetEntryVersion(ctx, "user", "key", 2)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, r)
+	})
+
+	t.Run("blob not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(2)).Return(md, true, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/contents/2").Return(nil, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, r, ok, err := s.GetEntryVersion(ctx, "user", "key", 2)
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.False(t, ok)
+		require.Nil(t, r)
+	})
+}
+
+func TestService_RestoreVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	version := entry.Metadata{
+		Key:      "key",
+		Name:     "old name",
+		Size:     3,
+		Version:  1,
+		BlobName: "contents/1",
+	}
+
+	// the version is restored with a copy of its blob
+	restored := version
+	restored.BlobName = ""
+
+	current := entry.Metadata{
+		Key:      "key",
+		Name:     "name",
+		Size:     5,
+		Version:  3,
+		BlobName: "contents/3",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(version, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+
+		// restore, the current one becomes a version along with the commit
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/contents/1").Return(io.NopCloser(bytes.NewBufferString("old")), true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("old")).Return(3, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(restored), current.Version, true).Return(entry.Change{}, nil)
+
+		metaRepo.EXPECT().PruneVersions(ctx, "user", "key", 3).Return([]string{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("version not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(entry.Metadata{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.ErrorIs(t, err, entry.ErrVersionNotFound)
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(version, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 8, Entries: 1}, nil)
+
+		s := entry.New(metaRepo, blobRepo, entry.Options{MaxVersions: 3, MaxBytes: 10})
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+	})
+
+	t.Run("usage err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(version, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(current, true, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{}, errors.New("err"))
+
+		s := entry.New(metaRepo, blobRepo, entry.Options{MaxVersions: 3, MaxBytes: 10})
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("version blob not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(version, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/contents/1").Return(nil, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("set metadata err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(version, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/contents/1").Return(io.NopCloser(bytes.NewBufferString("old")), true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("old")).Return(3, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(restored), int64(0), false).Return(entry.Change{}, errors.New("query failed"))
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("changed during restore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetVersion(ctx, "user", "key", int64(1)).Return(version, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/contents/1").Return(io.NopCloser(bytes.NewBufferString("old")), true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("old")).Return(3, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", withContentBlob(restored), int64(0), false).Return(entry.Change{}, entry.ErrConflict)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.RestoreEntryVersion(ctx, "user", "key", 1)
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+}
+
+func TestService_CreateUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	md := entry.Metadata{Key: "key", Name: "name", Type: "file"}
+
+	t.Run("new entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().CreateUploadSession(ctx, "user", entry.UploadSession{Metadata: md, Size: 100}).
+			Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		session, err := s.CreateUploadSession(ctx, "user", md, 100, false, 0)
+		require.NoError(t, err)
+		require.Equal(t, "upload", session.ID)
+	})
+
+	t.Run("overwrite remembers current version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+		metaRepo.EXPECT().CreateUploadSession(ctx, "user", entry.UploadSession{Metadata: md, Size: 100, ExpectedVersion: 3}).
+			Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100, ExpectedVersion: 3}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		session, err := s.CreateUploadSession(ctx, "user", md, 100, true, 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), session.ExpectedVersion)
+	})
+
+	t.Run("entry exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.CreateUploadSession(ctx, "user", md, 100, false, 0)
+		require.ErrorIs(t, err, entry.ErrEntryExists)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Version: 3}, true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.CreateUploadSession(ctx, "user", md, 100, false, 2)
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().CreateUploadSession(ctx, "user", gomock.Any()).Return(entry.UploadSession{}, errors.New("query error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.CreateUploadSession(ctx, "user", md, 100, false, 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_GetUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(40), true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		session, ok, err := s.GetUploadSession(ctx, "user", "upload")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, entry.UploadSession{ID: "upload", Size: 100, Offset: 40}, session)
+	})
+
+	t.Run("nothing uploaded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(0), false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		session, ok, err := s.GetUploadSession(ctx, "user", "upload")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Zero(t, session.Offset)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, ok, err := s.GetUploadSession(ctx, "user", "upload")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{}, false, errors.New("query error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, _, err := s.GetUploadSession(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_AppendUpload(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		var token string
+		metaRepo.EXPECT().LeaseUploadSession(ctx, "user", "upload", gomock.Any(), time.Minute).
+			DoAndReturn(func(_ context.Context, _ string, _ string, leaseToken string, _ time.Duration) (bool, error) {
+				token = leaseToken
+
+				return true, nil
+			})
+		// released with the same token before the result is sent
+		metaRepo.EXPECT().ReleaseUploadSession(gomock.Any(), "user", "upload", gomock.Cond(func(releaseToken string) bool {
+			return releaseToken == token
+		})).Return(nil)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(40), true, nil)
+		blobRepo.EXPECT().OpenBlobAppender(gomock.Any(), "user/uploads/upload").Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.AppendUpload(ctx, "user", "upload", 40)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
+		require.Equal(t, int64(45), result.Session.Offset)
+	})
+
+	t.Run("chunk error keeps written data", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(0), false, nil)
+		blobRepo.EXPECT().OpenBlobAppender(gomock.Any(), "user/uploads/upload").Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		// the blob is never deleted
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.AppendUpload(ctx, "user", "upload", 0)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		uploadChan <- entry.UploadChunk{Err: errors.New("stream dropped")}
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrUploadChunk)
+		require.Equal(t, int64(5), result.Session.Offset)
+	})
+
+	t.Run("exceeds declared size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 8}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(0), false, nil)
+		blobRepo.EXPECT().OpenBlobAppender(gomock.Any(), "user/uploads/upload").Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.AppendUpload(ctx, "user", "upload", 0)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		// the second chunk isn't written
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrSizeMismatch)
+		require.Equal(t, int64(5), result.Session.Offset)
+	})
+
+	t.Run("busy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		// leased by another request, possibly through another server instance
+		metaRepo.EXPECT().LeaseUploadSession(ctx, "user", "upload", gomock.Any(), time.Minute).Return(false, nil).Times(2)
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 100}, true, nil).Times(2)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, _, err := s.AppendUpload(ctx, "user", "upload", 0)
+		require.ErrorIs(t, err, entry.ErrUploadBusy)
+
+		err = s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrUploadBusy)
+	})
+
+	t.Run("lease err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().LeaseUploadSession(ctx, "user", "upload", gomock.Any(), time.Minute).Return(false, errors.New("query failed"))
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		_, _, err := s.AppendUpload(ctx, "user", "upload", 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("offset mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().LeaseUploadSession(gomock.Any(), "user", "upload", gomock.Any(), time.Minute).Return(true, nil).Times(2)
+		metaRepo.EXPECT().ReleaseUploadSession(gomock.Any(), "user", "upload", gomock.Any()).Return(nil).Times(2)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Size: 100}, true, nil).Times(2)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(40), true, nil).Times(2)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, _, err := s.AppendUpload(ctx, "user", "upload", 50)
+		require.ErrorIs(t, err, entry.ErrOffsetMismatch)
+
+		// the session is released after the error
+		_, _, err = s.AppendUpload(ctx, "user", "upload", 0)
+		require.ErrorIs(t, err, entry.ErrOffsetMismatch)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().LeaseUploadSession(ctx, "user", "upload", gomock.Any(), time.Minute).Return(false, nil)
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, _, err := s.AppendUpload(ctx, "user", "upload", 0)
+		require.ErrorIs(t, err, entry.ErrUploadNotFound)
+	})
+}
+
+func TestService_FinalizeUpload(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	md := entry.Metadata{Key: "key", Name: "name", Type: "file"}
+
+	t.Run("new entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(100), true, nil)
+		metaRepo.EXPECT().GetMetadata(gomock.Any(), "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/uploads/upload").Return(io.NopCloser(bytes.NewBufferString("content")), true, nil)
+		// the uploaded blob becomes the content, the session is deleted along with the commit
+		metaRepo.EXPECT().CommitUpload(gomock.Any(), "user", "upload", entry.Metadata{Key: "key", Name: "name", Type: "file", Size: 100, Checksum: checksum("content"), BlobName: "uploads/upload"}, int64(0), false).
+			Return(entry.Change{Cursor: 1}, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.NoError(t, err)
+	})
+
+	t.Run("existing entry is archived", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		current := entry.Metadata{Key: "key", Name: "old", Version: 2, BlobName: "contents/2"}
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100, ExpectedVersion: 2}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(100), true, nil)
+		metaRepo.EXPECT().GetMetadata(gomock.Any(), "user", "key").Return(current, true, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/uploads/upload").Return(io.NopCloser(bytes.NewBufferString("content")), true, nil)
+
+		metaRepo.EXPECT().CommitUpload(gomock.Any(), "user", "upload", entry.Metadata{Key: "key", Name: "name", Type: "file", Size: 100, Checksum: checksum("content"), BlobName: "uploads/upload"}, int64(2), true).
+			Return(entry.Change{Cursor: 1}, nil)
+		metaRepo.EXPECT().PruneVersions(gomock.Any(), "user", "key", defaultOptions.MaxVersions).Return(nil, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.NoError(t, err)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(40), true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrSizeMismatch)
+	})
+
+	t.Run("entry changed during upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(100), true, nil)
+		metaRepo.EXPECT().GetMetadata(gomock.Any(), "user", "key").Return(entry.Metadata{Key: "key", Version: 1}, true, nil)
+		metaRepo.EXPECT().DeleteUploadSession(gomock.Any(), "user", "upload").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/uploads/upload").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+
+	t.Run("conflict on set metadata", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(100), true, nil)
+		metaRepo.EXPECT().GetMetadata(gomock.Any(), "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/uploads/upload").Return(io.NopCloser(bytes.NewBufferString("content")), true, nil)
+		// created by someone else in the meantime, its content is left alone
+		metaRepo.EXPECT().CommitUpload(gomock.Any(), "user", "upload", gomock.Any(), int64(0), false).Return(entry.Change{}, entry.ErrConflict)
+		metaRepo.EXPECT().DeleteUploadSession(gomock.Any(), "user", "upload").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/uploads/upload").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrConflict)
+	})
+
+	t.Run("cant read uploaded blob", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(100), true, nil)
+		metaRepo.EXPECT().GetMetadata(gomock.Any(), "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/uploads/upload").Return(nil, false, errors.New("error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().LeaseUploadSession(ctx, "user", "upload", gomock.Any(), time.Minute).Return(false, nil)
+		metaRepo.EXPECT().GetUploadSession(ctx, "user", "upload").Return(entry.UploadSession{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrUploadNotFound)
+	})
+
+	t.Run("commit err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		expectLease(metaRepo)
+		metaRepo.EXPECT().GetUploadSession(gomock.Any(), "user", "upload").Return(entry.UploadSession{ID: "upload", Metadata: md, Size: 100}, true, nil)
+		blobRepo.EXPECT().GetBlobSize(gomock.Any(), "user/uploads/upload").Return(int64(100), true, nil)
+		metaRepo.EXPECT().GetMetadata(gomock.Any(), "user", "key").Return(entry.Metadata{}, false, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "user/uploads/upload").Return(io.NopCloser(bytes.NewBufferString("content")), true, nil)
+		// the session and its blob are kept for a retry
+		metaRepo.EXPECT().CommitUpload(gomock.Any(), "user", "upload", gomock.Any(), int64(0), false).Return(entry.Change{}, errors.New("query failed"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		err := s.FinalizeUpload(ctx, "user", "upload")
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_Reconcile(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	old := time.Now().Add(-2 * time.Hour)
+	opts := entry.ReconcileOptions{GracePeriod: time.Hour}
+
+	entryRef := entry.BlobRef{Kind: entry.BlobRefEntry, UserID: "user", Key: "key", Version: 3, BlobName: "contents/3"}
+	versionRef := entry.BlobRef{Kind: entry.BlobRefVersion, UserID: "user", Key: "key", Version: 2, BlobName: "contents/2"}
+	uploadRef := entry.BlobRef{Kind: entry.BlobRefUpload, UserID: "user", Key: "key", Version: 3, UploadID: "upload"}
+	goneRef := entry.BlobRef{Kind: entry.BlobRefEntry, UserID: "user", Key: "gone", Version: 1, BlobName: "gone"}
+	refs := []entry.BlobRef{entryRef, versionRef, uploadRef, goneRef}
+
+	blobs := []blob.Info{
+		{Key: "user/contents/3", ModifiedAt: old},
+		{Key: "user/orphan", ModifiedAt: old},
+		// probably being written along with its metadata
+		{Key: "user/fresh", ModifiedAt: time.Now()},
+	}
+
+	walk := func(_ context.Context, fn func(blob.Info) error) error {
+		for _, info := range blobs {
+			err := fn(info)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	t.Run("report only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return(refs, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).DoAndReturn(walk)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		report, err := s.Reconcile(ctx, opts)
+		require.NoError(t, err)
+		require.Equal(t, entry.ReconcileReport{
+			Refs:         4,
+			Blobs:        3,
+			OrphanBlobs:  []string{"user/orphan"},
+			MissingBlobs: []entry.BlobRef{versionRef, goneRef},
+		}, report)
+	})
+
+	t.Run("repair", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return(refs, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).DoAndReturn(walk)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/orphan").Return(nil)
+		metaRepo.EXPECT().DeleteVersion(ctx, "user", "key", int64(2)).Return(nil)
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "gone", int64(1)).
+			Return(entry.Change{Cursor: 5, Metadata: entry.Metadata{Key: "gone", Version: 1}, Deleted: true}, true, nil)
+
+		expectChangeLog(metaRepo, "user", entry.Change{Cursor: 5, Metadata: entry.Metadata{Key: "gone", Version: 1}, Deleted: true})
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		defer cancelWatch()
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+
+		report, err := s.Reconcile(ctx, entry.ReconcileOptions{Repair: true, GracePeriod: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Repaired)
+
+		change := <-changes
+		require.True(t, change.Deleted)
+		require.Equal(t, "gone", change.Metadata.Key)
+	})
+
+	t.Run("repair cleans temporary blobs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := &tempCleaningBlobRepository{MockBlobRepository: mocks.NewMockBlobRepository(ctrl), cleaned: 2}
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return([]entry.BlobRef{entryRef}, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).DoAndReturn(walk)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/orphan").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		report, err := s.Reconcile(ctx, entry.ReconcileOptions{Repair: true, GracePeriod: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Repaired)
+		require.WithinDuration(t, old.Add(time.Hour), blobRepo.modifiedBefore, time.Minute)
+	})
+
+	t.Run("shares", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		shareRef := entry.BlobRef{Kind: entry.BlobRefShare, UserID: "user", Key: "key", RecipientID: "recipient"}
+		goneShareRef := entry.BlobRef{Kind: entry.BlobRefShare, UserID: "user", Key: "gone", RecipientID: "recipient"}
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return([]entry.BlobRef{shareRef, goneShareRef}, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(blob.Info) error) error {
+			return fn(blob.Info{Key: "user/shares/recipient/key", ModifiedAt: old})
+		})
+		metaRepo.EXPECT().DeleteShare(ctx, "user", "recipient", "gone").Return(true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		report, err := s.Reconcile(ctx, entry.ReconcileOptions{Repair: true, GracePeriod: time.Hour})
+		require.NoError(t, err)
+		require.Empty(t, report.OrphanBlobs)
+		require.Equal(t, []entry.BlobRef{goneShareRef}, report.MissingBlobs)
+		require.Equal(t, 1, report.Repaired)
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		goneTrashRef := entry.BlobRef{Kind: entry.BlobRefTrash, UserID: "user", Key: "gone", Version: 2, BlobName: "trash/gone"}
+		restoredTrashRef := entry.BlobRef{Kind: entry.BlobRefTrash, UserID: "user", Key: "restored", Version: 4, BlobName: "contents/4"}
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return([]entry.BlobRef{goneTrashRef, restoredTrashRef}, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).Return(nil)
+		// the previous versions are purged along with the entry
+		metaRepo.EXPECT().PurgeTrash(ctx, "user", "gone", int64(2)).Return([]string{"contents/1"}, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(nil)
+		// restored in the meantime
+		metaRepo.EXPECT().PurgeTrash(ctx, "user", "restored", int64(4)).Return(nil, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		report, err := s.Reconcile(ctx, entry.ReconcileOptions{Repair: true, GracePeriod: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, []entry.BlobRef{goneTrashRef, restoredTrashRef}, report.MissingBlobs)
+		require.Equal(t, 1, report.Repaired)
+	})
+
+	t.Run("repair skips changed and failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return(refs, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).DoAndReturn(walk)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/orphan").Return(errors.New("error"))
+		metaRepo.EXPECT().DeleteVersion(ctx, "user", "key", int64(2)).Return(errors.New("error"))
+		// changed since the scan
+		metaRepo.EXPECT().DeleteMetadata(ctx, "user", "gone", int64(1)).Return(entry.Change{}, false, entry.ErrConflict)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		report, err := s.Reconcile(ctx, entry.ReconcileOptions{Repair: true, GracePeriod: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, 0, report.Repaired)
+		require.Len(t, report.OrphanBlobs, 1)
+		require.Len(t, report.MissingBlobs, 2)
+	})
+
+	t.Run("cant list refs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return(nil, errors.New("error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.Reconcile(ctx, opts)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("cant walk blobs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListBlobRefs(ctx).Return(refs, nil)
+		blobRepo.EXPECT().WalkBlobs(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.Reconcile(ctx, opts)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+// tempCleaningBlobRepository is a blob repository mock cleaning the given number of temporary blobs.
+type tempCleaningBlobRepository struct {
+	*mocks.MockBlobRepository
+	cleaned        int
+	modifiedBefore time.Time
+}
+
+func (r *tempCleaningBlobRepository) CleanTemp(ctx context.Context, modifiedBefore time.Time) (int, error) {
+	r.modifiedBefore = modifiedBefore
+
+	return r.cleaned, nil
+}
+
+// checksum returns the SHA-256 checksum of the content.
+func checksum(content string) []byte {
+	sum := sha256.Sum256([]byte(content))
+
+	return sum[:]
+}
+
+// abortingWriter replaces the blob only on successful Close, like the file blob writer.
+type abortingWriter struct {
+	*mocks.MockWriteCloser
+	abortErr error
+	aborted  bool
+}
+
+func (w *abortingWriter) Abort() error {
+	w.aborted = true
+
+	return w.abortErr
+}
+
+func TestService_Quota(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	options := entry.Options{
+		MaxVersions: 0,
+		MaxBytes:    10,
+		MaxEntries:  2,
+	}
+
+	md := entry.Metadata{Key: "key", Name: "name", Type: "file"}
+
+	t.Run("set within quota", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 5, Entries: 1}, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", gomock.Any(), int64(0), false).Return(entry.Change{}, nil)
+
+		s := entry.New(metaRepo, blobRepo, options)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, false, 0)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
+	})
+
+	t.Run("set exceeds bytes while streaming", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 4, Entries: 1}, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), contentKey).Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, options)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, false, 0)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		// only 1 byte is left
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrQuotaExceeded)
+	})
+
+	t.Run("overwritten content isn't counted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key", Size: 8, Version: 1, BlobName: "contents/1"}, true, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 10, Entries: 2}, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), contentKey).Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetMetadata(ctx, "user", gomock.Any(), int64(1), false).Return(entry.Change{}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, options)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, true, 0)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
+	})
+
+	t.Run("no bytes left", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 10, Entries: 1}, nil)
+
+		s := entry.New(metaRepo, blobRepo, options)
+		uploadChan, resultChan, err := s.SetEntry(ctx, "user", md, false, 0)
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+		require.Nil(t, uploadChan)
+		require.Nil(t, resultChan)
+	})
+
+	t.Run("too many entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 0, Entries: 2}, nil)
+
+		s := entry.New(metaRepo, blobRepo, options)
+		_, err := s.CreateUploadSession(ctx, "user", md, 1, false, 0)
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+	})
+
+	t.Run("upload session too large", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 5, Entries: 1}, nil)
+
+		s := entry.New(metaRepo, blobRepo, options)
+		_, err := s.CreateUploadSession(ctx, "user", md, 6, false, 0)
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+	})
+
+	t.Run("usage err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{}, errors.New("query error"))
+
+		s := entry.New(metaRepo, blobRepo, options)
+		_, err := s.CreateUploadSession(ctx, "user", md, 1, false, 0)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_GetUsage(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 5, Entries: 1}, nil)
+
+		s := entry.New(metaRepo, blobRepo, entry.Options{MaxBytes: 10, MaxEntries: 2})
+		usage, err := s.GetUsage(ctx, "user")
+		require.NoError(t, err)
+		require.Equal(t, entry.Usage{Bytes: 5, Entries: 1, MaxBytes: 10, MaxEntries: 2}, usage)
+	})
+
+	t.Run("repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{}, errors.New("query error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.GetUsage(ctx, "user")
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_ListUsage(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListUsage(ctx).Return(map[string]entry.Usage{"user": {Bytes: 5, Entries: 1}}, nil)
+
+		s := entry.New(metaRepo, blobRepo, entry.Options{MaxBytes: 10, MaxEntries: 2})
+		usages, err := s.ListUsage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]entry.Usage{"user": {Bytes: 5, Entries: 1, MaxBytes: 10, MaxEntries: 2}}, usages)
+	})
+
+	t.Run("repo err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().ListUsage(ctx).Return(nil, errors.New("query error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		_, err := s.ListUsage(ctx)
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+}
+
+func TestService_Trash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	trashOptions := entry.Options{
+		MaxVersions:    3,
+		TrashRetention: time.Hour,
+	}
+
+	t.Run("delete moves to trash", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		// the entry keeps its blob in the trash, only the one of the entry it replaced there is released
+		metaRepo.EXPECT().TrashMetadata(ctx, "user", "key", int64(2)).Return(entry.Change{
+			Metadata: entry.Metadata{Key: "key", Version: 2, BlobName: "contents/old"},
+			Deleted:  true,
+		}, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/old").Return(nil)
+		// the versions are kept along with the entry, they aren't pruned
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete missing entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().TrashMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{}, false, nil)
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("batch delete moves to trash", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		items := []entry.DeleteItem{{Key: "first"}, {Key: "missing"}}
+
+		metaRepo.EXPECT().TrashMetadataBatch(ctx, "user", items).Return([]entry.Change{
+			{Metadata: entry.Metadata{Key: "first", Version: 2}, Deleted: true},
+		}, nil)
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		err := s.BatchDeleteEntries(ctx, "user", items)
+		require.NoError(t, err)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+
+		deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		metaRepo.EXPECT().ListTrash(ctx, "user").Return([]entry.TrashItem{
+			{Metadata: entry.Metadata{Key: "key"}, DeletedAt: deletedAt},
+		}, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), trashOptions)
+		items, err := s.ListTrash(ctx, "user")
+		require.NoError(t, err)
+		require.Equal(t, []entry.TrashItem{
+			{Metadata: entry.Metadata{Key: "key"}, DeletedAt: deletedAt, ExpiresAt: deletedAt.Add(time.Hour)},
+		}, items)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().RestoreMetadata(ctx, "user", "key").Return(entry.Change{
+			Metadata: entry.Metadata{Key: "key", Version: 3},
+		}, nil)
+
+		expectChangeLog(metaRepo, "user", entry.Change{Cursor: 1, Metadata: entry.Metadata{Key: "key", Version: 3}})
+
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		defer cancelWatch()
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		changes, err := s.WatchEntries(watchCtx, "user")
+		require.NoError(t, err)
+
+		err = s.RestoreEntry(ctx, "user", "key")
+		require.NoError(t, err)
+
+		change := <-changes
+		require.Equal(t, "key", change.Metadata.Key)
+		require.False(t, change.Deleted)
+	})
+
+	t.Run("versions are kept through trash and restore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		versions := []entry.Metadata{{Key: "key", Version: 2, BlobName: "contents/2"}, {Key: "key", Version: 1, BlobName: "contents/1"}}
+
+		// no versions are pruned or blobs deleted along the way
+		metaRepo.EXPECT().TrashMetadata(ctx, "user", "key", int64(0)).Return(entry.Change{
+			Metadata: entry.Metadata{Key: "key", Version: 3},
+			Deleted:  true,
+		}, true, nil)
+		metaRepo.EXPECT().RestoreMetadata(ctx, "user", "key").Return(entry.Change{
+			Metadata: entry.Metadata{Key: "key", Version: 3},
+		}, nil)
+		metaRepo.EXPECT().ListVersions(ctx, "user", "key").Return(versions, nil)
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		err := s.DeleteEntry(ctx, "user", "key", 0)
+		require.NoError(t, err)
+
+		err = s.RestoreEntry(ctx, "user", "key")
+		require.NoError(t, err)
+
+		list, err := s.ListEntryVersions(ctx, "user", "key")
+		require.NoError(t, err)
+		require.Equal(t, versions, list)
+	})
+
+	t.Run("restore over existing entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().RestoreMetadata(ctx, "user", "key").Return(entry.Change{}, entry.ErrEntryExists)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), trashOptions)
+		err := s.RestoreEntry(ctx, "user", "key")
+		require.ErrorIs(t, err, entry.ErrEntryExists)
+	})
+
+	t.Run("restore missing entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().RestoreMetadata(ctx, "user", "key").Return(entry.Change{}, entry.ErrEntryNotFound)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), trashOptions)
+		err := s.RestoreEntry(ctx, "user", "key")
+		require.ErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+
+	t.Run("restore over quota", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Entries: 2}, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), entry.Options{TrashRetention: time.Hour, MaxEntries: 2})
+		err := s.RestoreEntry(ctx, "user", "key")
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+	})
+
+	t.Run("restore metadata err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().RestoreMetadata(ctx, "user", "key").Return(entry.Change{}, errors.New("query failed"))
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), trashOptions)
+		err := s.RestoreEntry(ctx, "user", "key")
+		require.ErrorIs(t, err, entry.ErrInternal)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		// the previous versions are purged along with the entry
+		metaRepo.EXPECT().PurgeTrash(ctx, "user", "key", int64(0)).Return([]string{"contents/2", "contents/1"}, true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/2").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(fs.ErrNotExist)
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		err := s.PurgeEntry(ctx, "user", "key")
+		require.NoError(t, err)
+	})
+
+	t.Run("purge missing entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().PurgeTrash(ctx, "user", "key", int64(0)).Return(nil, false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), trashOptions)
+		err := s.PurgeEntry(ctx, "user", "key")
+		require.ErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+
+	t.Run("purge expired", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().PurgeExpiredTrash(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) ([]entry.BlobRef, error) {
+			require.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+
+			return []entry.BlobRef{
+				{Kind: entry.BlobRefTrash, UserID: "user", Key: "first", BlobName: "trash/first"},
+				{Kind: entry.BlobRefTrash, UserID: "other", Key: "second", BlobName: "contents/2"},
+				{Kind: entry.BlobRefVersion, UserID: "other", Key: "second", Version: 1, BlobName: "contents/1"},
+			}, nil
+		})
+		// the rest are deleted anyway
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/trash/first").Return(errors.New("io error"))
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "other/contents/2").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "other/contents/1").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, trashOptions)
+		purged, err := s.PurgeExpiredTrash(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, purged)
+	})
+
+	t.Run("purge owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().DeleteOwnerMetadata(ctx, "user").Return([]entry.BlobRef{
+			{Kind: entry.BlobRefEntry, UserID: "user", Key: "key", BlobName: "contents/1"},
+			{Kind: entry.BlobRefUpload, UserID: "user", Key: "key", UploadID: "upload"},
+			{Kind: entry.BlobRefShare, UserID: "other", Key: "key", RecipientID: "user"},
+		}, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/contents/1").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/uploads/upload").Return(fs.ErrNotExist)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "other/shares/user/key").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		purged, err := s.PurgeOwner(ctx, "user")
+		require.NoError(t, err)
+		require.Equal(t, 3, purged)
+	})
+
+	t.Run("purge owner error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().DeleteOwnerMetadata(ctx, "user").Return(nil, errors.New("error"))
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		purged, err := s.PurgeOwner(ctx, "user")
+		require.ErrorIs(t, err, entry.ErrInternal)
+		require.Zero(t, purged)
+	})
+
+	t.Run("discard blobs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		// the rest are deleted anyway
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "vault/contents/1").Return(errors.New("io error"))
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "vault/contents/2").Return(nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "vault/uploads/upload").Return(fs.ErrNotExist)
+
+		s := entry.New(mocks.NewMockMetadataRepository(ctrl), blobRepo, defaultOptions)
+		s.DiscardBlobs(ctx, []entry.BlobRef{
+			{Kind: entry.BlobRefVersion, UserID: "vault", Key: "key", Version: 1, BlobName: "contents/1"},
+			{Kind: entry.BlobRefVersion, UserID: "vault", Key: "key", Version: 2, BlobName: "contents/2"},
+			{Kind: entry.BlobRefUpload, UserID: "vault", Key: "key", UploadID: "upload"},
+		})
+	})
+
+	t.Run("purge expired without trash", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockMetadataRepository(ctrl), mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		purged, err := s.PurgeExpiredTrash(ctx)
+		require.NoError(t, err)
+		require.Zero(t, purged)
+	})
+}
+
+func TestService_KeyPair(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	pair := entry.KeyPair{PublicKey: "public", EncryptedPrivateKey: []byte("private")}
+
+	t.Run("set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().SetKeyPair(ctx, "user", pair).Return(nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		require.NoError(t, s.SetKeyPair(ctx, "user", pair))
+	})
+
+	t.Run("set exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().SetKeyPair(ctx, "user", pair).Return(entry.ErrKeyPairExists)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		require.ErrorIs(t, s.SetKeyPair(ctx, "user", pair), entry.ErrKeyPairExists)
+	})
+
+	t.Run("set error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().SetKeyPair(ctx, "user", pair).Return(errors.New("query error"))
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		require.ErrorIs(t, s.SetKeyPair(ctx, "user", pair), entry.ErrInternal)
+	})
+
+	t.Run("get public key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(entry.KeyPair{UserID: "recipient", PublicKey: "public"}, true, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		key, ok, err := s.GetPublicKey(ctx, "bob")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "public", key)
+	})
+}
+
+func TestService_ShareEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	share := entry.Share{RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope")}
+	recipient := entry.KeyPair{UserID: "recipient", PublicKey: "public"}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		stored := entry.Share{
+			OwnerID:        "user",
+			RecipientID:    "recipient",
+			RecipientLogin: "bob",
+			Key:            "key",
+			Envelope:       []byte("envelope"),
+			Size:           5,
+			Checksum:       checksum("chunk"),
+		}
+
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(recipient, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key"}, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "user/shares/recipient/key").Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetShare(ctx, stored).DoAndReturn(func(_ context.Context, share entry.Share) (entry.Share, error) {
+			share.ID = "share"
+			return share, nil
+		})
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.ShareEntry(ctx, "user", share)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.NoError(t, result.Err)
+		require.Equal(t, "share", result.Share.ID)
+	})
+
+	t.Run("recipient not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(entry.KeyPair{}, false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		_, _, err := s.ShareEntry(ctx, "user", share)
+		require.ErrorIs(t, err, entry.ErrRecipientNotFound)
+	})
+
+	t.Run("with self", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(entry.KeyPair{UserID: "user", PublicKey: "public"}, true, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		_, _, err := s.ShareEntry(ctx, "user", share)
+		require.ErrorIs(t, err, entry.ErrShareWithSelf)
+	})
+
+	t.Run("entry not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(recipient, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{}, false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		_, _, err := s.ShareEntry(ctx, "user", share)
+		require.ErrorIs(t, err, entry.ErrEntryNotFound)
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(recipient, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key"}, true, nil)
+		metaRepo.EXPECT().GetUsage(ctx, "user").Return(entry.Usage{Bytes: 100, Entries: 10}, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), entry.Options{MaxBytes: 100, MaxEntries: 10})
+		_, _, err := s.ShareEntry(ctx, "user", share)
+		require.ErrorIs(t, err, entry.ErrQuotaExceeded)
+	})
+
+	t.Run("set share error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+		writer := mocks.NewMockWriteCloser(ctrl)
+
+		metaRepo.EXPECT().FindPublicKey(ctx, "bob").Return(recipient, true, nil)
+		metaRepo.EXPECT().GetMetadata(ctx, "user", "key").Return(entry.Metadata{Key: "key"}, true, nil)
+		blobRepo.EXPECT().OpenBlobWriter(gomock.Any(), "user/shares/recipient/key").Return(writer, nil)
+		writer.EXPECT().Write([]byte("chunk")).Return(5, nil)
+		writer.EXPECT().Close().Return(nil)
+		metaRepo.EXPECT().SetShare(ctx, gomock.Any()).Return(entry.Share{}, errors.New("query error"))
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		uploadChan, resultChan, err := s.ShareEntry(ctx, "user", share)
+		require.NoError(t, err)
+
+		uploadChan <- entry.UploadChunk{Content: []byte("chunk")}
+		close(uploadChan)
+
+		result := <-resultChan
+		require.ErrorIs(t, result.Err, entry.ErrInternal)
+	})
+}
+
+func TestService_GetSharedEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	share := entry.Share{ID: "share", OwnerID: "owner", RecipientID: "user", Key: "key"}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().GetShare(ctx, "share").Return(share, true, nil)
+		blobRepo.EXPECT().OpenBlobReader(gomock.Any(), "owner/shares/user/key").Return(io.NopCloser(bytes.NewBufferString("content")), true, nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		got, rc, ok, err := s.GetSharedEntry(ctx, "user", "share")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, share, got)
+
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+	})
+
+	t.Run("not a recipient", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().GetShare(ctx, "share").Return(share, true, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		_, rc, ok, err := s.GetSharedEntry(ctx, "owner", "share")
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, rc)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().GetShare(ctx, "share").Return(entry.Share{}, false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		_, _, ok, err := s.GetSharedEntry(ctx, "user", "share")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestService_RevokeShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		blobRepo := mocks.NewMockBlobRepository(ctrl)
+
+		metaRepo.EXPECT().FindShare(ctx, "user", "bob", "key").Return(entry.Share{OwnerID: "user", RecipientID: "recipient", Key: "key"}, true, nil)
+		metaRepo.EXPECT().DeleteShare(ctx, "user", "recipient", "key").Return(true, nil)
+		blobRepo.EXPECT().DeleteBlob(gomock.Any(), "user/shares/recipient/key").Return(nil)
+
+		s := entry.New(metaRepo, blobRepo, defaultOptions)
+		require.NoError(t, s.RevokeShare(ctx, "user", "key", "bob"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindShare(ctx, "user", "bob", "key").Return(entry.Share{}, false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		require.ErrorIs(t, s.RevokeShare(ctx, "user", "key", "bob"), entry.ErrShareNotFound)
+	})
+
+	t.Run("deleted concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		metaRepo := mocks.NewMockMetadataRepository(ctrl)
+		metaRepo.EXPECT().FindShare(ctx, "user", "bob", "key").Return(entry.Share{OwnerID: "user", RecipientID: "recipient", Key: "key"}, true, nil)
+		metaRepo.EXPECT().DeleteShare(ctx, "user", "recipient", "key").Return(false, nil)
+
+		s := entry.New(metaRepo, mocks.NewMockBlobRepository(ctrl), defaultOptions)
+		require.ErrorIs(t, s.RevokeShare(ctx, "user", "key", "bob"), entry.ErrShareNotFound)
 	})
 }
@@ -0,0 +1,229 @@
+package entry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+)
+
+func (s *service) SetKeyPair(ctx context.Context, userID string, pair KeyPair) error {
+	llog := s.log.WithLazy("userID", userID, "method", "SetKeyPair")
+
+	err := s.metaRepo.SetKeyPair(ctx, userID, pair)
+	if errors.Is(err, ErrKeyPairExists) {
+		llog.Debug("key pair already exists")
+
+		return ErrKeyPairExists
+	}
+	if err != nil {
+		llog.Errorw("cant set key pair", "err", err)
+
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (s *service) GetKeyPair(ctx context.Context, userID string) (KeyPair, bool, error) {
+	pair, ok, err := s.metaRepo.GetKeyPair(ctx, userID)
+	if err != nil {
+		s.log.Errorw("cant get key pair", "userID", userID, "err", err)
+
+		return KeyPair{}, false, ErrInternal
+	}
+
+	return pair, ok, nil
+}
+
+func (s *service) GetPublicKey(ctx context.Context, login string) (string, bool, error) {
+	pair, ok, err := s.metaRepo.FindPublicKey(ctx, login)
+	if err != nil {
+		s.log.Errorw("cant find public key", "login", login, "err", err)
+
+		return "", false, ErrInternal
+	}
+
+	return pair.PublicKey, ok, nil
+}
+
+func (s *service) ShareEntry(ctx context.Context, userID string, share Share) (chan<- UploadChunk, <-chan ShareResult, error) {
+	llog := s.log.WithLazy("userID", userID, "key", share.Key, "recipient", share.RecipientLogin, "method", "ShareEntry")
+
+	recipient, ok, err := s.metaRepo.FindPublicKey(ctx, share.RecipientLogin)
+	if err != nil {
+		llog.Errorw("cant find public key", "err", err)
+
+		return nil, nil, ErrInternal
+	}
+	if !ok {
+		llog.Debug("recipient not found")
+
+		return nil, nil, ErrRecipientNotFound
+	}
+	if recipient.UserID == userID {
+		return nil, nil, ErrShareWithSelf
+	}
+
+	_, ok, err = s.metaRepo.GetMetadata(ctx, userID, share.Key)
+	if err != nil {
+		llog.Errorw("cant get metadata", "err", err)
+
+		return nil, nil, ErrInternal
+	}
+	if !ok {
+		llog.Debug("entry not found")
+
+		return nil, nil, ErrEntryNotFound
+	}
+
+	// a share isn't an entry, so only the bytes are limited
+	limit, err := s.getUploadLimit(ctx, userID, Metadata{}, true, llog)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	share.OwnerID = userID
+	share.RecipientID = recipient.UserID
+	blobKey := s.getShareBlobKey(userID, recipient.UserID, share.Key)
+
+	dst, err := s.blobRepo.OpenBlobWriter(ctx, blobKey)
+	if err != nil {
+		llog.Errorw("cant get writer", "err", err)
+
+		return nil, nil, ErrInternal
+	}
+
+	uploadChan := make(chan UploadChunk)
+	// don't wait for caller to read the result
+	resultChan := make(chan ShareResult, 1)
+
+	go func() {
+		defer close(resultChan)
+
+		share.Size, share.Checksum, err = s.processUpload(ctx, uploadChan, dst, limit, llog.Named("upload"))
+		if errors.Is(err, errLimitExceeded) {
+			err = ErrQuotaExceeded
+		}
+		if err != nil {
+			cderr := s.closeAndDelete(ctx, dst, blobKey, llog)
+			if cderr != nil {
+				resultChan <- ShareResult{Err: ErrInternal}
+				return
+			}
+
+			resultChan <- ShareResult{Err: err}
+			return
+		}
+
+		err = dst.Close()
+		if err != nil {
+			llog.Errorw("cant close writer", "err", err)
+
+			// aborter discards the written data itself, and the blob still has the previous copy
+			if _, ok := dst.(blob.Aborter); !ok {
+				err = s.blobRepo.DeleteBlob(context.WithoutCancel(ctx), blobKey)
+				if err != nil {
+					llog.Errorw("cant delete blob", "err", err)
+				}
+			}
+
+			resultChan <- ShareResult{Err: ErrInternal}
+			return
+		}
+
+		// the blob isn't deleted on error, it may already be the content of the previous share
+		stored, err := s.metaRepo.SetShare(ctx, share)
+		if err != nil {
+			llog.Errorw("cant set share", "err", err)
+
+			resultChan <- ShareResult{Err: ErrInternal}
+			return
+		}
+
+		resultChan <- ShareResult{Share: stored}
+	}()
+
+	return uploadChan, resultChan, nil
+}
+
+func (s *service) ListShares(ctx context.Context, userID string, incoming bool) ([]Share, error) {
+	shares, err := s.metaRepo.ListShares(ctx, userID, incoming)
+	if err != nil {
+		s.log.Errorw("cant list shares", "userID", userID, "incoming", incoming, "err", err)
+
+		return nil, ErrInternal
+	}
+
+	return shares, nil
+}
+
+func (s *service) GetSharedEntry(ctx context.Context, userID string, shareID string) (Share, io.ReadCloser, bool, error) {
+	llog := s.log.WithLazy("userID", userID, "shareID", shareID, "method", "GetSharedEntry")
+
+	share, ok, err := s.metaRepo.GetShare(ctx, shareID)
+	if err != nil {
+		llog.Errorw("cant get share", "err", err)
+
+		return Share{}, nil, false, ErrInternal
+	}
+
+	// the owner can't read the copy anyway, so it is only available to the recipient
+	if !ok || share.RecipientID != userID {
+		return Share{}, nil, false, nil
+	}
+
+	rc, ok, err := s.blobRepo.OpenBlobReader(ctx, s.getShareBlobKey(share.OwnerID, share.RecipientID, share.Key))
+	if err != nil {
+		llog.Errorw("cant get blob reader", "err", err)
+
+		return Share{}, nil, false, ErrInternal
+	}
+	if !ok {
+		llog.Errorw("blob not found")
+
+		return Share{}, nil, false, ErrInternal
+	}
+
+	return share, rc, true, nil
+}
+
+func (s *service) RevokeShare(ctx context.Context, userID string, key string, recipientLogin string) error {
+	llog := s.log.WithLazy("userID", userID, "key", key, "recipient", recipientLogin, "method", "RevokeShare")
+
+	share, ok, err := s.metaRepo.FindShare(ctx, userID, recipientLogin, key)
+	if err != nil {
+		llog.Errorw("cant find share", "err", err)
+
+		return ErrInternal
+	}
+	if !ok {
+		return ErrShareNotFound
+	}
+
+	deleted, err := s.metaRepo.DeleteShare(ctx, userID, share.RecipientID, key)
+	if err != nil {
+		llog.Errorw("cant delete share", "err", err)
+
+		return ErrInternal
+	}
+	if !deleted {
+		return ErrShareNotFound
+	}
+
+	// the metadata is already gone, so an undeleted blob is only an orphan
+	err = s.blobRepo.DeleteBlob(ctx, s.getShareBlobKey(userID, share.RecipientID, key))
+	if err != nil {
+		llog.Errorw("cant delete share blob", "err", err)
+	}
+
+	return nil
+}
+
+// getShareBlobKey returns the blob key of the copy of the entry shared with the recipient.
+// Entry keys are hex hashes, so shares dir never clashes with them.
+func (s *service) getShareBlobKey(ownerID string, recipientID string, key string) string {
+	return fmt.Sprintf("%s/shares/%s/%s", ownerID, recipientID, key)
+}
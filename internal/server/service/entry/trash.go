@@ -0,0 +1,154 @@
+package entry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+func (s *service) ListTrash(ctx context.Context, userID string) ([]TrashItem, error) {
+	llog := s.log.WithLazy("userID", userID, "method", "ListTrash")
+
+	items, err := s.metaRepo.ListTrash(ctx, userID)
+	if err != nil {
+		llog.Errorw("cant list trash", "err", err)
+
+		return nil, ErrInternal
+	}
+
+	for i := range items {
+		items[i].ExpiresAt = items[i].DeletedAt.Add(s.options.TrashRetention)
+	}
+
+	return items, nil
+}
+
+func (s *service) RestoreEntry(ctx context.Context, userID string, key string) error {
+	llog := s.log.WithLazy("userID", userID, "key", key, "method", "RestoreEntry")
+
+	if s.options.MaxEntries > 0 {
+		usage, err := s.metaRepo.GetUsage(ctx, userID)
+		if err != nil {
+			llog.Errorw("cant get usage", "err", err)
+
+			return ErrInternal
+		}
+
+		// the content is already counted in the trash, so only the number of entries grows
+		if usage.Entries >= s.options.MaxEntries {
+			llog.Debugw("entries quota exceeded", "entries", usage.Entries)
+
+			return ErrQuotaExceeded
+		}
+	}
+
+	// the entry keeps the blob it had in the trash, so there is nothing to move
+	_, err := s.metaRepo.RestoreMetadata(ctx, userID, key)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrEntryNotFound):
+			llog.Debug("entry isn't in the trash")
+
+			return ErrEntryNotFound
+		case errors.Is(err, ErrEntryExists):
+			llog.Debug("entry already exists")
+
+			return ErrEntryExists
+		default:
+			llog.Errorw("cant restore metadata", "err", err)
+
+			return ErrInternal
+		}
+	}
+
+	s.broker.notify(userID)
+
+	return nil
+}
+
+func (s *service) PurgeEntry(ctx context.Context, userID string, key string) error {
+	llog := s.log.WithLazy("userID", userID, "key", key, "method", "PurgeEntry")
+
+	blobNames, deleted, err := s.metaRepo.PurgeTrash(ctx, userID, key, 0)
+	if err != nil {
+		llog.Errorw("cant purge metadata", "err", err)
+
+		return ErrInternal
+	}
+	if !deleted {
+		return ErrEntryNotFound
+	}
+
+	// the metadata is already gone, so try to delete all the blobs, the ones left are orphans for the reconciler
+	var blobErr error
+	for _, blobName := range blobNames {
+		err = s.deleteBlob(ctx, userID, blobName)
+		if err != nil {
+			llog.Errorw("cant delete blob", "blobName", blobName, "err", err)
+
+			blobErr = ErrInternal
+		}
+	}
+
+	return blobErr
+}
+
+func (s *service) PurgeExpiredTrash(ctx context.Context) (int, error) {
+	llog := s.log.WithLazy("retention", s.options.TrashRetention, "method", "PurgeExpiredTrash")
+
+	if s.options.TrashRetention <= 0 {
+		return 0, nil
+	}
+
+	refs, err := s.metaRepo.PurgeExpiredTrash(ctx, time.Now().Add(-s.options.TrashRetention))
+	if err != nil {
+		llog.Errorw("cant purge expired metadata", "err", err)
+
+		return 0, ErrInternal
+	}
+
+	s.DiscardBlobs(ctx, refs)
+
+	// the previous versions purged along with the entries aren't counted
+	purged := 0
+	for _, ref := range refs {
+		if ref.Kind == BlobRefTrash {
+			purged++
+		}
+	}
+
+	if purged > 0 {
+		llog.Infow("purged expired trash", "purged", purged)
+	}
+
+	return purged, nil
+}
+
+func (s *service) PurgeOwner(ctx context.Context, ownerID string) (int, error) {
+	llog := s.log.WithLazy("ownerID", ownerID, "method", "PurgeOwner")
+
+	refs, err := s.metaRepo.DeleteOwnerMetadata(ctx, ownerID)
+	if err != nil {
+		llog.Errorw("cant delete owner metadata", "err", err)
+
+		return 0, ErrInternal
+	}
+
+	s.DiscardBlobs(ctx, refs)
+
+	llog.Infow("purged owner", "purged", len(refs))
+
+	return len(refs), nil
+}
+
+func (s *service) DiscardBlobs(ctx context.Context, refs []BlobRef) {
+	// the metadata is already gone, the blobs left are orphans for the reconciler
+	for _, ref := range refs {
+		// an upload session has no blob until the first chunk is written
+		err := s.blobRepo.DeleteBlob(ctx, s.getRefBlobKey(ref))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			s.log.Errorw("cant delete blob", "userID", ref.UserID, "kind", ref.Kind, "key", ref.Key, "err", err)
+		}
+	}
+}
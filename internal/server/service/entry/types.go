@@ -7,13 +7,163 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 )
 
 // Metadata represents the metadata associated with an entry.
 type Metadata struct {
-	Key   string // Key is the unique identifier for the entry.
-	Name  string // Name is the human-readable name of the entry.
-	Notes []byte // Notes contains additional information about the entry.
+	Key       string    // Key is the unique identifier for the entry.
+	Name      string    // Name is the human-readable name of the entry.
+	Notes     []byte    // Notes contains additional information about the entry.
+	Type      string    // Type is the kind of the entry, e.g. "login" or "file".
+	Size      int64     // Size is the size of the stored content in bytes.
+	CreatedAt time.Time // CreatedAt is the time the entry was first stored. It is set by the repository.
+	UpdatedAt time.Time // UpdatedAt is the time the entry was last stored. It is set by the repository.
+	Version   int64     // Version is the revision of the entry, incremented on every change. It is set by the repository.
+	Checksum  []byte    // Checksum is the SHA-256 of the stored content. It is set by the service.
+	// BlobName is the name of the blob of the content among the blobs of the owner. It is set by the service.
+	// Every content is written to a blob of its own, which is never changed, so the metadata always matches it.
+	BlobName string
+	// Envelope is the name and type of the entry encrypted by the client, opaque to the server.
+	// Name and Type are empty when it's set, they are only kept for entries stored before envelopes were introduced.
+	Envelope []byte
+}
+
+// Options configures the entry service.
+type Options struct {
+	// MaxVersions is the number of previous versions kept for every entry. Zero disables version history.
+	MaxVersions int
+	// MaxBytes is the limit of the storage used by every user, see Usage. Zero means unlimited.
+	MaxBytes int64
+	// MaxEntries is the limit of the number of entries of every user. Zero means unlimited.
+	MaxEntries int64
+	// TrashRetention is how long deleted entries are kept in the trash before they are purged.
+	// Zero disables the trash, so entries are deleted right away.
+	TrashRetention time.Duration
+	// WatchInterval is how often the change log is polled for the watchers of the entries, so they get the changes
+	// made through the other server instances sharing the database. Zero means defaultWatchInterval.
+	WatchInterval time.Duration
+	// UploadLease is how long an upload session stays leased to the server instance writing to it or finalizing it,
+	// the lease is renewed while it's needed. Zero means defaultUploadLease.
+	UploadLease time.Duration
+}
+
+// Usage represents the storage used by a user, along with the vaults the user owns.
+// The limits are checked before writes, so concurrent writes can exceed them slightly.
+type Usage struct {
+	Bytes      int64 // Bytes is the size of the content of the entries, their kept versions, the trash, the shared copies and the declared size of the upload sessions.
+	Entries    int64 // Entries is the number of the entries.
+	MaxBytes   int64 // MaxBytes is the limit of Bytes, zero means unlimited. It is set by the service.
+	MaxEntries int64 // MaxEntries is the limit of Entries, zero means unlimited. It is set by the service.
+}
+
+// DeleteItem identifies an entry to delete in a batch.
+type DeleteItem struct {
+	Key             string // Key is the key of the entry.
+	ExpectedVersion int64  // ExpectedVersion is the version the entry must have to be deleted, zero means no expectation.
+}
+
+// RenameResult is the result of moving metadata of an entry to a new key.
+type RenameResult struct {
+	Deleted    Change   // Deleted is the tombstone recorded for the old key.
+	Renamed    Change   // Renamed is the change recorded for the new key, with the stored metadata.
+	StaleBlobs []string // StaleBlobs are the blob names of the versions left under the new key by a deleted entry, deleted by the rename.
+}
+
+// TrashItem represents a deleted entry kept in the trash.
+// Only the current version of the entry is in the trash, its previous versions are kept until it is purged.
+type TrashItem struct {
+	Metadata  Metadata  // Metadata of the entry at the time it was deleted.
+	DeletedAt time.Time // DeletedAt is the time the entry was deleted. It is set by the repository.
+	ExpiresAt time.Time // ExpiresAt is the time the entry is purged from the trash. It is set by the service.
+}
+
+// KeyPair is the public key of a user along with the private key encrypted by the client.
+// Entries are shared with the user by encrypting them to the public key.
+type KeyPair struct {
+	UserID              string // UserID is the owner of the key pair.
+	PublicKey           string // PublicKey is published to the other users.
+	EncryptedPrivateKey []byte // EncryptedPrivateKey is opaque to the server, only its owner can read it.
+}
+
+// Share represents a copy of an entry shared by its owner with another user.
+// The copy is encrypted by the owner's client to the recipient's public key, so only the recipient can read it.
+// Sharing the same entry with the same recipient again replaces the copy.
+type Share struct {
+	ID             string    // ID is the unique identifier of the share. It is set by the repository.
+	OwnerID        string    // OwnerID is the user who shared the entry.
+	OwnerLogin     string    // OwnerLogin is the login of the owner. It is set by the repository.
+	RecipientID    string    // RecipientID is the user the entry is shared with. It is set by the service.
+	RecipientLogin string    // RecipientLogin is the login of the recipient.
+	Key            string    // Key is the key of the shared entry among the owner's entries.
+	Envelope       []byte    // Envelope is the name, type and notes of the entry encrypted to the recipient.
+	Size           int64     // Size is the size of the stored content in bytes. It is set by the service.
+	Checksum       []byte    // Checksum is the SHA-256 of the stored content. It is set by the service.
+	CreatedAt      time.Time // CreatedAt is the time the entry was last shared. It is set by the repository.
+}
+
+// ListOptions defines pagination parameters for listing entries.
+type ListOptions struct {
+	After string // After is the key after which the listing starts. Empty means from the beginning.
+	Limit int    // Limit is the maximum number of entries to return.
+}
+
+// Change represents the latest change of an entry in the user's change log.
+type Change struct {
+	Cursor    int64     // Cursor is the position of the change in the log. It only grows.
+	Metadata  Metadata  // Metadata of the entry after the change. Only Key and Version are set for deletions, and BlobName if the repository released a blob.
+	Deleted   bool      // Deleted is set if the entry was deleted.
+	ChangedAt time.Time // ChangedAt is the time of the change.
+}
+
+// UploadSession represents a resumable upload of an entry's content.
+type UploadSession struct {
+	ID              string    // ID is the unique identifier of the session. It is set by the repository.
+	Metadata        Metadata  // Metadata is stored when the upload is finalized.
+	Size            int64     // Size is the total size of the content in bytes.
+	ExpectedVersion int64     // ExpectedVersion is the version of the entry seen when the session was created, zero for a new entry.
+	Offset          int64     // Offset is the number of bytes committed so far. It is set by the service.
+	CreatedAt       time.Time // CreatedAt is the time the session was created. It is set by the repository.
+}
+
+// BlobRefKind is the kind of metadata referencing a blob.
+type BlobRefKind string
+
+const (
+	BlobRefEntry   BlobRefKind = "entry"   // BlobRefEntry is the current version of an entry.
+	BlobRefVersion BlobRefKind = "version" // BlobRefVersion is a previous version of an entry.
+	BlobRefUpload  BlobRefKind = "upload"  // BlobRefUpload is an upload session.
+	BlobRefTrash   BlobRefKind = "trash"   // BlobRefTrash is a deleted entry kept in the trash.
+	BlobRefShare   BlobRefKind = "share"   // BlobRefShare is a copy of an entry shared with another user.
+)
+
+// BlobRef represents metadata of any kind referencing a blob.
+type BlobRef struct {
+	Kind        BlobRefKind // Kind of the metadata.
+	UserID      string      // UserID is the owner of the metadata.
+	Key         string      // Key is the key of the entry.
+	Version     int64       // Version is the version of the entry, or the expected version for upload sessions.
+	BlobName    string      // BlobName is the name of the blob among the blobs of the owner. Not set for uploads and shares.
+	UploadID    string      // UploadID is the ID of the upload session. Only set for uploads.
+	RecipientID string      // RecipientID is the user the entry is shared with. Only set for shares.
+}
+
+// ReconcileOptions configures a reconciliation of the metadata with the blob store.
+type ReconcileOptions struct {
+	// Repair makes the reconciliation delete the orphans it has found, otherwise they are only reported.
+	Repair bool
+	// GracePeriod is how long a blob must stay unmodified before it can be considered an orphan,
+	// so blobs written right before their metadata aren't deleted.
+	GracePeriod time.Duration
+}
+
+// ReconcileReport is the result of a reconciliation of the metadata with the blob store.
+type ReconcileReport struct {
+	Refs         int       // Refs is the number of blobs referenced by the metadata.
+	Blobs        int       // Blobs is the number of stored blobs.
+	OrphanBlobs  []string  // OrphanBlobs are the keys of the blobs not referenced by any metadata.
+	MissingBlobs []BlobRef // MissingBlobs are the metadata referencing blobs that don't exist. Upload sessions are never reported.
+	Repaired     int       // Repaired is the number of orphans deleted, including stale temporary blobs. Always zero if repair wasn't requested.
 }
 
 // UploadChunk represents a chunk of data being uploaded.
@@ -27,6 +177,18 @@ type SetEntryResult struct {
 	Err error // Err indicates any error encountered during the operation.
 }
 
+// ShareResult represents the result of a ShareEntry operation.
+type ShareResult struct {
+	Share Share // Share is the stored share. It is only set if no error occurred.
+	Err   error // Err indicates any error encountered during the operation.
+}
+
+// UploadResult represents the result of an AppendUpload operation.
+type UploadResult struct {
+	Session UploadSession // Session with the committed offset. It is set even if an error occurred.
+	Err     error         // Err indicates any error encountered during the operation.
+}
+
 // ErrInternal is returned when an internal error occurs.
 var ErrInternal = errors.New("internal error")
 
@@ -39,29 +201,350 @@ var ErrNoUpload = errors.New("upload closed with no data")
 // ErrEntryExists is returned when an entry with the same key already exists.
 var ErrEntryExists = errors.New("entry already exists")
 
+// ErrEntryNotFound is returned when the entry to change doesn't exist.
+var ErrEntryNotFound = errors.New("entry not found")
+
+// ErrConflict is returned when the entry was changed concurrently, i.e. its current version isn't the expected one.
+var ErrConflict = errors.New("entry was changed concurrently")
+
+// ErrVersionNotFound is returned when the requested version of an entry doesn't exist.
+var ErrVersionNotFound = errors.New("version not found")
+
+// ErrInvalidRange is returned when the requested range of the content is out of its bounds.
+var ErrInvalidRange = errors.New("range is out of content bounds")
+
+// ErrUploadNotFound is returned when the upload session doesn't exist.
+var ErrUploadNotFound = errors.New("upload session not found")
+
+// ErrUploadBusy is returned when the upload session is already being written to or finalized.
+var ErrUploadBusy = errors.New("upload session is busy")
+
+// ErrOffsetMismatch is returned when an upload is continued from an offset other than the committed one.
+var ErrOffsetMismatch = errors.New("offset doesn't match the committed one")
+
+// ErrQuotaExceeded is returned when a write would exceed the storage limits of the user.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// ErrSizeMismatch is returned when an upload is finalized while the size of the uploaded content differs from the declared one,
+// or when more content than declared is uploaded.
+var ErrSizeMismatch = errors.New("uploaded content size doesn't match the declared one")
+
+// ErrKeyPairExists is returned when the user already has a key pair.
+var ErrKeyPairExists = errors.New("key pair already exists")
+
+// ErrRecipientNotFound is returned when there is no user to share an entry with, or the user has no key pair yet.
+var ErrRecipientNotFound = errors.New("recipient not found")
+
+// ErrShareWithSelf is returned when a user tries to share an entry with themselves.
+var ErrShareWithSelf = errors.New("cant share entry with yourself")
+
+// ErrShareNotFound is returned when the share doesn't exist.
+var ErrShareNotFound = errors.New("share not found")
+
 // Service defines the interface for managing entries.
+// The entries are stored under the userID of their owner, which is the ID of the vault for the entries of a vault.
+// The access to the vaults is checked before calling the service, and the limits apply to every vault separately.
 type Service interface {
 	// SetEntry starts the process of uploading an entry.
 	// It returns channels for uploading chunks and receiving the result.
-	SetEntry(ctx context.Context, userID string, md Metadata, overwrite bool) (chan<- UploadChunk, <-chan SetEntryResult, error)
+	// ErrQuotaExceeded is returned, either right away or as the result, if the upload would exceed the user's limits.
+	// If expectedVersion is not zero, the entry must exist with that version, otherwise ErrConflict is returned.
+	// If it is zero, ErrEntryExists is returned for an existing entry unless overwrite is set.
+	// ErrConflict is also returned if the entry is changed by someone else during the upload.
+	SetEntry(ctx context.Context, userID string, md Metadata, overwrite bool, expectedVersion int64) (chan<- UploadChunk, <-chan SetEntryResult, error)
+
+	// CreateUploadSession starts a resumable upload of an entry with the given metadata and content size.
+	// The entry is checked the same way as in SetEntry, and it is checked again when the upload is finalized.
+	// The declared size counts towards the user's usage until the session is finalized or deleted,
+	// ErrQuotaExceeded is returned if it would exceed the limits.
+	CreateUploadSession(ctx context.Context, userID string, md Metadata, size int64, overwrite bool, expectedVersion int64) (UploadSession, error)
+
+	// GetUploadSession retrieves an upload session along with its committed offset.
+	// It returns the session, a boolean indicating existence, and an error if any.
+	GetUploadSession(ctx context.Context, userID string, uploadID string) (UploadSession, bool, error)
+
+	// AppendUpload continues the upload from the offset, which must be equal to the committed one.
+	// It returns channels for uploading chunks and receiving the result, like SetEntry does,
+	// but chunks written before an error are kept, so the upload can be continued later.
+	// Returns ErrUploadNotFound, ErrUploadBusy or ErrOffsetMismatch if the upload can't be continued.
+	// The result has ErrSizeMismatch if more content than declared is uploaded.
+	AppendUpload(ctx context.Context, userID string, uploadID string, offset int64) (chan<- UploadChunk, <-chan UploadResult, error)
+
+	// FinalizeUpload stores the uploaded content and the metadata of the session as the entry, and deletes the session.
+	// Returns ErrSizeMismatch if the content isn't fully uploaded, and ErrConflict if the entry was changed
+	// since the session was created, in which case the session is deleted too.
+	FinalizeUpload(ctx context.Context, userID string, uploadID string) error
 
 	// GetEntry retrieves an entry's metadata and content.
+	// The reader returns length bytes of the content starting from offset, zero length means up to the end.
+	// ErrInvalidRange is returned if the offset is greater than the size of the content.
 	// It returns the metadata, a reader for the content, a boolean indicating existence, and an error if any.
-	GetEntry(ctx context.Context, userID string, key string) (Metadata, io.ReadCloser, bool, error)
+	GetEntry(ctx context.Context, userID string, key string, offset int64, length int64) (Metadata, io.ReadCloser, bool, error)
+
+	// DeleteEntry deletes an entry by its key. If the trash is enabled, the entry is moved there and can be restored
+	// with RestoreEntry until the retention period ends, replacing an entry deleted earlier with the same key.
+	// The previous versions are kept along with the trashed entry, and are deleted with the entry otherwise.
+	// If expectedVersion is not zero, the entry must exist with that version, otherwise ErrConflict is returned.
+	DeleteEntry(ctx context.Context, userID string, key string, expectedVersion int64) error
+
+	// BatchDeleteEntries deletes the entries in one transaction, moving them to the trash the same way as DeleteEntry does.
+	// If the expected version of any of them doesn't match, ErrConflict is returned and nothing is deleted.
+	BatchDeleteEntries(ctx context.Context, userID string, items []DeleteItem) error
+
+	// BatchGetMetadata returns metadata of the entries with the given keys, read at the same point in time.
+	// Missing entries are omitted, the rest are in the order of keys.
+	BatchGetMetadata(ctx context.Context, userID string, keys []string) ([]Metadata, error)
+
+	// RenameEntry moves an entry along with its previous versions to the new key, name and envelope, keeping the content.
+	// Returns ErrEntryNotFound if there is no such entry and ErrEntryExists if there is an entry with the new key.
+	// If expectedVersion is not zero, the entry must have that version, otherwise ErrConflict is returned.
+	RenameEntry(ctx context.Context, userID string, key string, newKey string, newName string, newEnvelope []byte, expectedVersion int64) error
+
+	// ListEntries returns a page of entries' metadata ordered by key.
+	// It returns the metadata, a token for the next page (empty if there are no more entries), and an error if any.
+	ListEntries(ctx context.Context, userID string, pageToken string, pageSize int) ([]Metadata, string, error)
+
+	// ListChanges returns the latest changes of the entries changed after the cursor, oldest first.
+	// It returns the changes, the cursor for the next call, a boolean indicating if there are more changes, and an error if any.
+	ListChanges(ctx context.Context, userID string, cursor int64, pageSize int) ([]Change, int64, bool, error)
 
-	// DeleteEntry deletes an entry by its key.
-	DeleteEntry(ctx context.Context, userID string, key string) error
+	// WatchEntries subscribes to the changes of the user's entries made after the call, through any server instance.
+	// The channel is closed when the context is done, or earlier if the watcher doesn't keep up with the changes.
+	// In the latter case the missed changes can be fetched with ListChanges.
+	WatchEntries(ctx context.Context, userID string) (<-chan Change, error)
+
+	// ListEntryVersions returns metadata of the kept previous versions of an entry, newest first.
+	ListEntryVersions(ctx context.Context, userID string, key string) ([]Metadata, error)
+
+	// GetEntryVersion retrieves a previous version of an entry.
+	// It returns the metadata, a reader for the content, a boolean indicating existence, and an error if any.
+	GetEntryVersion(ctx context.Context, userID string, key string, version int64) (Metadata, io.ReadCloser, bool, error)
+
+	// RestoreEntryVersion makes a previous version the current one. The replaced version is kept in the history.
+	// Returns ErrVersionNotFound if there is no such version and ErrConflict if the entry is changed concurrently.
+	RestoreEntryVersion(ctx context.Context, userID string, key string, version int64) error
+
+	// Reconcile compares the metadata of all the users with the blob store and reports the orphans on both sides:
+	// blobs no metadata references, and entries and versions whose blobs are missing, so they can't be read.
+	// If repair is requested, orphan blobs are deleted, and so are the metadata of the unreadable entries and versions.
+	Reconcile(ctx context.Context, opts ReconcileOptions) (ReconcileReport, error)
+
+	// GetUsage returns the storage used by the user along with the limits.
+	// The vaults the user owns are counted too, and the usage of a vault is the one of its most loaded owner.
+	GetUsage(ctx context.Context, userID string) (Usage, error)
+
+	// ListUsage returns the storage used by every user along with the limits, keyed by the ID of the user.
+	// It's counted the same way as by GetUsage.
+	ListUsage(ctx context.Context) (map[string]Usage, error)
+
+	// ListTrash returns the user's deleted entries kept in the trash, most recently deleted first.
+	ListTrash(ctx context.Context, userID string) ([]TrashItem, error)
+
+	// RestoreEntry moves a deleted entry back from the trash, with its version incremented.
+	// Returns ErrEntryNotFound if the entry isn't in the trash and ErrEntryExists if an entry with the same key was stored since.
+	// ErrQuotaExceeded is returned if the user has reached the limit of entries.
+	RestoreEntry(ctx context.Context, userID string, key string) error
+
+	// PurgeEntry deletes an entry from the trash for good. Returns ErrEntryNotFound if the entry isn't in the trash.
+	PurgeEntry(ctx context.Context, userID string, key string) error
+
+	// PurgeExpiredTrash deletes the entries of all the users kept in the trash longer than the retention period.
+	// It returns the number of purged entries.
+	PurgeExpiredTrash(ctx context.Context) (int, error)
+
+	// PurgeOwner deletes everything stored by the owner, a user or a vault, for good: the entries with their history,
+	// the trash, the unfinished uploads, and the shares by or with the owner. It returns the number of deleted blobs.
+	PurgeOwner(ctx context.Context, ownerID string) (int, error)
+
+	// DiscardBlobs deletes the blobs of metadata another service has deleted, like the old versions of a deleted vault.
+	// The blobs it fails to delete are logged and left as orphans for the reconciler.
+	DiscardBlobs(ctx context.Context, refs []BlobRef)
+
+	// SetKeyPair publishes the user's key pair. A key pair can't be replaced, since the entries shared
+	// with the user would become unreadable, so ErrKeyPairExists is returned if the user already has one.
+	SetKeyPair(ctx context.Context, userID string, pair KeyPair) error
+
+	// GetKeyPair retrieves the user's key pair. It returns the key pair, a boolean indicating existence, and an error if any.
+	GetKeyPair(ctx context.Context, userID string) (KeyPair, bool, error)
+
+	// GetPublicKey retrieves the public key of the user with the given login.
+	// It returns the key, a boolean indicating if the user exists and has a key pair, and an error if any.
+	GetPublicKey(ctx context.Context, login string) (string, bool, error)
+
+	// ShareEntry starts uploading a copy of the user's entry encrypted to the recipient, replacing the previous copy if any.
+	// It returns channels for uploading chunks and receiving the result, like SetEntry does.
+	// ErrEntryNotFound is returned if the user has no entry with the key, ErrRecipientNotFound if the recipient doesn't exist
+	// or has no key pair, and ErrShareWithSelf if the recipient is the user. The copy counts towards the user's usage,
+	// ErrQuotaExceeded is returned, either right away or as the result, if it would exceed the limits.
+	ShareEntry(ctx context.Context, userID string, share Share) (chan<- UploadChunk, <-chan ShareResult, error)
+
+	// ListShares returns the entries shared by the user, or shared with the user if incoming is set, most recently shared first.
+	ListShares(ctx context.Context, userID string, incoming bool) ([]Share, error)
+
+	// GetSharedEntry retrieves a copy of an entry shared with the user.
+	// It returns the share, a reader for the content, a boolean indicating existence, and an error if any.
+	GetSharedEntry(ctx context.Context, userID string, shareID string) (Share, io.ReadCloser, bool, error)
+
+	// RevokeShare deletes the copy of the user's entry shared with the recipient.
+	// Returns ErrShareNotFound if the entry isn't shared with the recipient.
+	RevokeShare(ctx context.Context, userID string, key string, recipientLogin string) error
 }
 
 // MetadataRepository defines the interface for managing metadata storage.
 type MetadataRepository interface {
-	// SetMetadata stores metadata for an entry.
-	SetMetadata(ctx context.Context, userID string, md Metadata) error
+	// SetMetadata stores metadata for an entry and records the change in the user's change log.
+	// Creation and update times and the version are maintained by the repository, values in md are ignored.
+	// If expectedVersion is zero the entry must not exist, otherwise it must exist with that version.
+	// ErrConflict is returned if the expectation isn't met. If keepVersion is set, the replaced metadata
+	// is kept as a previous version in the same transaction, so its blob stays referenced.
+	// It returns the recorded change with the stored metadata.
+	SetMetadata(ctx context.Context, userID string, md Metadata, expectedVersion int64, keepVersion bool) (Change, error)
 
 	// GetMetadata retrieves metadata for an entry by its key.
 	// It returns the metadata, a boolean indicating existence, and an error if any.
 	GetMetadata(ctx context.Context, userID string, key string) (Metadata, bool, error)
 
-	// DeleteMetadata deletes metadata for an entry by its key.
-	DeleteMetadata(ctx context.Context, userID string, key string) error
+	// DeleteMetadata deletes metadata for an entry by its key and records a tombstone in the user's change log.
+	// If expectedVersion is not zero, the entry must exist with that version, otherwise ErrConflict is returned.
+	// It returns the recorded tombstone and a boolean indicating if anything was deleted.
+	// The blob name of the tombstone is the one of the deleted entry, which isn't referenced anymore.
+	DeleteMetadata(ctx context.Context, userID string, key string, expectedVersion int64) (Change, bool, error)
+
+	// GetMetadataBatch retrieves metadata of the entries with the given keys in one transaction.
+	// Missing entries are omitted, the rest are in the order of keys.
+	GetMetadataBatch(ctx context.Context, userID string, keys []string) ([]Metadata, error)
+
+	// DeleteMetadataBatch deletes metadata of the entries in one transaction, the same way as DeleteMetadata does.
+	// If the version of any of them doesn't match, ErrConflict is returned and nothing is deleted.
+	// It returns the recorded tombstones of the deleted entries.
+	DeleteMetadataBatch(ctx context.Context, userID string, items []DeleteItem) ([]Change, error)
+
+	// TrashMetadata deletes metadata for an entry the same way as DeleteMetadata does, and keeps it in the trash,
+	// replacing the metadata of an entry deleted earlier with the same key. The blob name of the tombstone is
+	// the one of the replaced entry, which isn't referenced anymore, or empty if nothing was replaced.
+	TrashMetadata(ctx context.Context, userID string, key string, expectedVersion int64) (Change, bool, error)
+
+	// TrashMetadataBatch deletes metadata of the entries in one transaction, the same way as TrashMetadata does.
+	// If the version of any of them doesn't match, ErrConflict is returned and nothing is deleted.
+	TrashMetadataBatch(ctx context.Context, userID string, items []DeleteItem) ([]Change, error)
+
+	// ListTrash retrieves metadata of the user's entries kept in the trash, most recently deleted first.
+	ListTrash(ctx context.Context, userID string) ([]TrashItem, error)
+
+	// RestoreMetadata moves metadata of an entry from the trash back to the entries in one transaction,
+	// with the version incremented, and records the change in the user's change log.
+	// ErrEntryNotFound is returned if the entry isn't in the trash, and ErrEntryExists if there is an entry with the same key.
+	// It returns the recorded change with the stored metadata.
+	RestoreMetadata(ctx context.Context, userID string, key string) (Change, error)
+
+	// PurgeTrash deletes metadata of an entry from the trash if it has the expected version, or any if expectedVersion is 0.
+	// The previous versions of the entry are deleted along with it, unless an entry with the same key was stored since.
+	// It returns the blob names of the deleted metadata and a boolean indicating if anything was deleted.
+	PurgeTrash(ctx context.Context, userID string, key string, expectedVersion int64) ([]string, bool, error)
+
+	// PurgeExpiredTrash deletes metadata of the entries of all the users deleted before the given time from the trash,
+	// along with their previous versions the same way as PurgeTrash does.
+	// It returns references to the blobs of the purged metadata.
+	PurgeExpiredTrash(ctx context.Context, before time.Time) ([]BlobRef, error)
+
+	// RenameMetadata moves metadata of an entry and its previous versions to the new key, name and envelope in one transaction.
+	// The entry and its versions keep their blobs and creation time, and the version of the entry is incremented.
+	// The type is cleared if the envelope is set.
+	// The entry must exist with expectedVersion, otherwise ErrConflict is returned,
+	// and ErrEntryExists is returned if there is an entry with the new key.
+	RenameMetadata(ctx context.Context, userID string, key string, newKey string, newName string, newEnvelope []byte, expectedVersion int64) (RenameResult, error)
+
+	// ListMetadata retrieves metadata of the user's entries ordered by key, according to the list options.
+	ListMetadata(ctx context.Context, userID string, opts ListOptions) ([]Metadata, error)
+
+	// ListChanges retrieves the latest change of every entry changed after the cursor, ordered by cursor.
+	// At most limit changes are returned.
+	ListChanges(ctx context.Context, userID string, after int64, limit int) ([]Change, error)
+
+	// GetLatestCursor retrieves the cursor of the latest change of the user's entries, zero if there are none.
+	GetLatestCursor(ctx context.Context, userID string) (int64, error)
+
+	// GetVersion retrieves metadata of a previous version of an entry.
+	// It returns the metadata, a boolean indicating existence, and an error if any.
+	GetVersion(ctx context.Context, userID string, key string, version int64) (Metadata, bool, error)
+
+	// ListVersions retrieves metadata of the previous versions of an entry, newest first.
+	ListVersions(ctx context.Context, userID string, key string) ([]Metadata, error)
+
+	// DeleteVersion deletes metadata of a previous version of an entry. Deleting a missing version is not an error.
+	DeleteVersion(ctx context.Context, userID string, key string, version int64) error
+
+	// PruneVersions deletes all but the newest keep versions of an entry.
+	// It returns the blob names of the deleted versions.
+	PruneVersions(ctx context.Context, userID string, key string, keep int) ([]string, error)
+
+	// CreateUploadSession stores a new upload session. It returns the session with the ID and the creation time set.
+	CreateUploadSession(ctx context.Context, userID string, session UploadSession) (UploadSession, error)
+
+	// GetUploadSession retrieves an upload session by its ID. The offset isn't stored by the repository.
+	// It returns the session, a boolean indicating existence, and an error if any.
+	GetUploadSession(ctx context.Context, userID string, uploadID string) (UploadSession, bool, error)
+
+	// DeleteUploadSession deletes an upload session by its ID.
+	DeleteUploadSession(ctx context.Context, userID string, uploadID string) error
+
+	// LeaseUploadSession leases an upload session to the holder of the token for the period, unless it's leased
+	// to another holder and the lease hasn't expired yet. Leasing it again with the same token extends the lease.
+	// It returns false if the session doesn't exist or is leased to another holder.
+	LeaseUploadSession(ctx context.Context, userID string, uploadID string, token string, period time.Duration) (bool, error)
+
+	// ReleaseUploadSession ends the lease of an upload session held with the token.
+	// Releasing a session that is missing or leased to another holder is not an error.
+	ReleaseUploadSession(ctx context.Context, userID string, uploadID string, token string) error
+
+	// CommitUpload stores metadata of the uploaded entry the same way as SetMetadata does and deletes the upload session
+	// in the same transaction, so the uploaded blob is referenced either by the session or by the entry.
+	CommitUpload(ctx context.Context, userID string, uploadID string, md Metadata, expectedVersion int64, keepVersion bool) (Change, error)
+
+	// ListBlobRefs retrieves references to blobs from the metadata of all the users:
+	// the entries, their previous versions, the trash, the upload sessions and the shares.
+	ListBlobRefs(ctx context.Context) ([]BlobRef, error)
+
+	// GetUsage calculates the storage charged to the user, including the vaults the user owns.
+	// For a vault it's the usage of its most loaded owner. The limits aren't set.
+	GetUsage(ctx context.Context, userID string) (Usage, error)
+
+	// ListUsage calculates the storage charged to every user, including the vaults the user owns, with a single query.
+	// The usage is keyed by the ID of the user. The limits aren't set.
+	ListUsage(ctx context.Context) (map[string]Usage, error)
+
+	// DeleteOwnerMetadata deletes all the metadata of the owner, a user or a vault: the entries, their previous versions,
+	// the trash, the upload sessions, the change log, and the shares by or with the owner.
+	// It returns references to the blobs of the deleted metadata.
+	DeleteOwnerMetadata(ctx context.Context, ownerID string) ([]BlobRef, error)
+
+	// SetKeyPair stores the user's key pair. ErrKeyPairExists is returned if the user already has one.
+	SetKeyPair(ctx context.Context, userID string, pair KeyPair) error
+
+	// GetKeyPair retrieves the user's key pair. It returns the key pair, a boolean indicating existence, and an error if any.
+	GetKeyPair(ctx context.Context, userID string) (KeyPair, bool, error)
+
+	// FindPublicKey retrieves the public key of the user with the given login. Only UserID and PublicKey are set.
+	// It returns the key pair, a boolean indicating if the user exists and has a key pair, and an error if any.
+	FindPublicKey(ctx context.Context, login string) (KeyPair, bool, error)
+
+	// SetShare stores the share, replacing the share of the same entry with the same recipient if any.
+	// It returns the share with the ID and the creation time set.
+	SetShare(ctx context.Context, share Share) (Share, error)
+
+	// GetShare retrieves a share by its ID along with the logins of the owner and the recipient.
+	// It returns the share, a boolean indicating existence, and an error if any.
+	GetShare(ctx context.Context, shareID string) (Share, bool, error)
+
+	// FindShare retrieves the share of the owner's entry with the recipient with the given login.
+	// It returns the share, a boolean indicating existence, and an error if any.
+	FindShare(ctx context.Context, ownerID string, recipientLogin string, key string) (Share, bool, error)
+
+	// ListShares retrieves the shares of the user's entries, or the shares with the user if incoming is set,
+	// most recently shared first.
+	ListShares(ctx context.Context, userID string, incoming bool) ([]Share, error)
+
+	// DeleteShare deletes the share of the owner's entry with the recipient.
+	// It returns a boolean indicating if anything was deleted.
+	DeleteShare(ctx context.Context, ownerID string, recipientID string, key string) (bool, error)
 }
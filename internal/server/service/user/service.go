@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,6 +23,7 @@ func NewService(repo Repository, options Options) Service {
 		repo:    repo,
 		options: options,
 		logger:  log.Logger().Named("userService"),
+		users:   make(map[string]cachedUser),
 	}
 }
 
@@ -29,6 +31,16 @@ type service struct {
 	repo    Repository
 	options Options
 	logger  *zap.SugaredLogger
+
+	// users caches the users the tokens are checked against, there is one item per user at most
+	usersMu sync.Mutex
+	users   map[string]cachedUser
+}
+
+type cachedUser struct {
+	info      UserInfo
+	found     bool
+	expiresAt time.Time
 }
 
 func (s *service) RegisterUser(ctx context.Context, login string, password string) error {
@@ -68,6 +80,10 @@ func (s *service) LoginUser(ctx context.Context, login string, password string)
 		return "", ErrInvalidPair
 	}
 
+	if userInfo.Disabled {
+		return "", ErrUserDisabled
+	}
+
 	token, err := s.issueToken(userInfo.ID)
 	if err != nil {
 		s.logger.Errorw("failed to issue token", "login", login, "error", err)
@@ -75,10 +91,15 @@ func (s *service) LoginUser(ctx context.Context, login string, password string)
 		return "", ErrInternal
 	}
 
+	// the cached user may be a disabled one, which would reject the new token until the cache expires
+	s.usersMu.Lock()
+	delete(s.users, userInfo.ID)
+	s.usersMu.Unlock()
+
 	return token, nil
 }
 
-func (s *service) ParseAuthToken(_ context.Context, token string) (*TokenInfo, error) {
+func (s *service) ParseAuthToken(ctx context.Context, token string) (*TokenInfo, error) {
 	claims := new(jwt.RegisteredClaims)
 
 	parsedToken, err := jwt.ParseWithClaims(
@@ -108,9 +129,54 @@ func (s *service) ParseAuthToken(_ context.Context, token string) (*TokenInfo, e
 		return nil, ErrInvalidToken
 	}
 
+	// the token is valid until it expires, unless the user is disabled, deleted or its sessions are reset
+	userInfo, found, err := s.getUser(ctx, claims.Subject)
+	if err != nil {
+		s.logger.Errorw("failed to fetch user", "userID", claims.Subject, "error", err)
+
+		return nil, ErrInternal
+	}
+
+	if !found || userInfo.Disabled {
+		return nil, ErrInvalidToken
+	}
+
+	// issue times are in whole seconds, so the tokens issued right after the reset are still accepted
+	if claims.IssuedAt == nil || claims.IssuedAt.Before(userInfo.SessionsResetAt.Truncate(time.Second)) {
+		return nil, ErrInvalidToken
+	}
+
 	return &TokenInfo{UserID: claims.Subject}, nil
 }
 
+// getUser retrieves the user from the repository, or from the cache if it was retrieved less than UserCacheTTL ago.
+func (s *service) getUser(ctx context.Context, userID string) (UserInfo, bool, error) {
+	if s.options.UserCacheTTL <= 0 {
+		return s.repo.GetUser(ctx, userID)
+	}
+
+	now := time.Now()
+
+	s.usersMu.Lock()
+	cached, ok := s.users[userID]
+	s.usersMu.Unlock()
+
+	if ok && now.Before(cached.expiresAt) {
+		return cached.info, cached.found, nil
+	}
+
+	info, found, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return UserInfo{}, false, err
+	}
+
+	s.usersMu.Lock()
+	s.users[userID] = cachedUser{info: info, found: found, expiresAt: now.Add(s.options.UserCacheTTL)}
+	s.usersMu.Unlock()
+
+	return info, found, nil
+}
+
 func (s *service) hashPassword(password string) string {
 	withSalt := password + s.options.PasswordSalt
 
@@ -141,6 +141,24 @@ func TestService_Login(t *testing.T) {
 		require.ErrorIs(t, err, user.ErrInvalidPair)
 		require.Empty(t, token)
 	})
+
+	t.Run("user disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockUserRepository(ctrl)
+
+		repo.EXPECT().FindUser(ctx, "login").Return(user.UserInfo{
+			ID:           uuid.New().String(),
+			PasswordHash: "7a37b85c8918eac19a9089c0fa5a2ab4dce3f90528dcdeec108b23ddf3607b99",
+			Disabled:     true,
+		}, true, nil)
+
+		s := user.NewService(repo, defaultOptions)
+		token, err := s.LoginUser(ctx, "login", "password")
+		require.ErrorIs(t, err, user.ErrUserDisabled)
+		require.Empty(t, token)
+	})
 }
 
 func TestService_ParseToken(t *testing.T) {
@@ -161,12 +179,100 @@ func TestService_ParseToken(t *testing.T) {
 		tokenString, err := token.SignedString(defaultOptions.TokenSecret)
 		require.NoError(t, err)
 
-		s := user.NewService(nil, defaultOptions)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockUserRepository(ctrl)
+		repo.EXPECT().GetUser(ctx, userID).Return(user.UserInfo{ID: userID, SessionsResetAt: now.Add(-time.Hour)}, true, nil)
+
+		s := user.NewService(repo, defaultOptions)
 		info, err := s.ParseAuthToken(ctx, tokenString)
 		require.NoError(t, err)
 		require.Equal(t, userID, info.UserID)
 	})
 
+	rejected := []struct {
+		name     string
+		userInfo user.UserInfo
+		found    bool
+	}{
+		{name: "user deleted", found: false},
+		{name: "user disabled", userInfo: user.UserInfo{Disabled: true}, found: true},
+		{name: "sessions reset", userInfo: user.UserInfo{SessionsResetAt: time.Now().Add(time.Minute)}, found: true},
+	}
+
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+
+			userID := uuid.New().String()
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+				Subject:   userID,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(defaultOptions.TokenExpirationPeriod)),
+			})
+
+			tokenString, err := token.SignedString(defaultOptions.TokenSecret)
+			require.NoError(t, err)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockUserRepository(ctrl)
+			repo.EXPECT().GetUser(ctx, userID).Return(tc.userInfo, tc.found, nil)
+
+			s := user.NewService(repo, defaultOptions)
+			info, err := s.ParseAuthToken(ctx, tokenString)
+			require.ErrorIs(t, err, user.ErrInvalidToken)
+			require.Nil(t, info)
+		})
+	}
+
+	t.Run("user cached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userID := uuid.New().String()
+
+		repo := mocks.NewMockUserRepository(ctrl)
+		// disabled in the cache until the user logs in again
+		repo.EXPECT().GetUser(ctx, userID).Return(user.UserInfo{ID: userID, Disabled: true}, true, nil)
+		repo.EXPECT().FindUser(ctx, "login").Return(user.UserInfo{
+			ID:           userID,
+			PasswordHash: "7a37b85c8918eac19a9089c0fa5a2ab4dce3f90528dcdeec108b23ddf3607b99",
+		}, true, nil)
+		repo.EXPECT().GetUser(ctx, userID).Return(user.UserInfo{ID: userID}, true, nil)
+
+		options := defaultOptions
+		options.UserCacheTTL = time.Hour
+		s := user.NewService(repo, options)
+
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(defaultOptions.TokenExpirationPeriod)),
+		})
+
+		tokenString, err := token.SignedString(defaultOptions.TokenSecret)
+		require.NoError(t, err)
+
+		for range 2 {
+			_, err = s.ParseAuthToken(ctx, tokenString)
+			require.ErrorIs(t, err, user.ErrInvalidToken)
+		}
+
+		tokenString, err = s.LoginUser(ctx, "login", "password")
+		require.NoError(t, err)
+
+		for range 2 {
+			info, err := s.ParseAuthToken(ctx, tokenString)
+			require.NoError(t, err)
+			require.Equal(t, userID, info.UserID)
+		}
+	})
+
 	t.Run("invalid string", func(t *testing.T) {
 		s := user.NewService(nil, defaultOptions)
 		info, err := s.ParseAuthToken(ctx, "its definitely a valid token, trust me")
@@ -20,6 +20,9 @@ var ErrInvalidPair = errors.New("login/password pair is invalid")
 // ErrInvalidToken is returned when the provided token is invalid.
 var ErrInvalidToken = errors.New("invalid token")
 
+// ErrUserDisabled is returned when a disabled user logs in.
+var ErrUserDisabled = errors.New("user is disabled")
+
 // ErrInternal is returned when an internal error occurs.
 var ErrInternal = errors.New("internal error")
 
@@ -36,6 +39,7 @@ type Service interface {
 	// LoginUser authenticates a user and returns an authentication token on success.
 	LoginUser(ctx context.Context, login string, password string) (string, error)
 	// ParseAuthToken parses and validates an authentication token, returning the associated TokenInfo.
+	// The token is checked against the user, which costs a database query unless the user is cached, see Options.UserCacheTTL.
 	ParseAuthToken(ctx context.Context, token string) (*TokenInfo, error)
 }
 
@@ -47,6 +51,10 @@ type Options struct {
 	PasswordSalt string
 	// TokenExpirationPeriod defines the duration for which an authentication token is valid.
 	TokenExpirationPeriod time.Duration
+	// UserCacheTTL is how long the user a token is checked against is cached, instead of querying the database
+	// on every request. The tokens of the disabled and deleted users, and the ones issued before the sessions are reset,
+	// are still accepted for up to this long. Zero disables the cache.
+	UserCacheTTL time.Duration
 }
 
 // ErrLoginNotUnique is returned when a user with the given login already exists.
@@ -56,8 +64,30 @@ var ErrLoginNotUnique = errors.New("user with this login already exists")
 type UserInfo struct {
 	// ID is the unique identifier of the user.
 	ID string
+	// Login is the login of the user.
+	Login string
 	// PasswordHash is the hashed password of the user.
 	PasswordHash string
+	// Disabled users can't log in, and their tokens are rejected.
+	Disabled bool
+	// SessionsResetAt is the time the tokens issued before are rejected, zero if the sessions were never reset.
+	SessionsResetAt time.Time
+	// CreatedAt is the time the user registered.
+	CreatedAt time.Time
+}
+
+// Membership represents the membership of a user in a vault, along with the number of the members of the vault.
+type Membership struct {
+	// VaultID is the ID of the vault.
+	VaultID string
+	// VaultName is the name of the vault.
+	VaultName string
+	// Role is the role of the user in the vault.
+	Role string
+	// Members is the number of the members of the vault, including the user.
+	Members int
+	// Owners is the number of the owners of the vault, including the user if it's one.
+	Owners int
 }
 
 // Repository defines the interface for user data storage operations.
@@ -66,4 +96,19 @@ type Repository interface {
 	AddUser(ctx context.Context, login string, passwordHash string) error
 	// FindUser retrieves a user by login. Returns the user info, a boolean indicating if the user was found, and an error if any.
 	FindUser(ctx context.Context, login string) (UserInfo, bool, error)
+	// GetUser retrieves a user by ID. Returns the user info, a boolean indicating if the user was found, and an error if any.
+	GetUser(ctx context.Context, userID string) (UserInfo, bool, error)
+	// ListUsers retrieves all the users sorted by login. Password hashes aren't set.
+	ListUsers(ctx context.Context) ([]UserInfo, error)
+	// SetDisabled disables or enables the user with the login. Returns a boolean indicating if the user was found.
+	SetDisabled(ctx context.Context, login string, disabled bool) (bool, error)
+	// ResetSessions makes the tokens of the user with the login issued before the given time invalid.
+	// Returns a boolean indicating if the user was found.
+	ResetSessions(ctx context.Context, login string, at time.Time) (bool, error)
+	// ListMemberships retrieves the vault memberships of the user.
+	ListMemberships(ctx context.Context, userID string) ([]Membership, error)
+	// DeleteUser deletes the user along with everything referencing it: the key pair, the shares, the vault memberships,
+	// the emergency grants and the vaults the user is the only member of. The entries stored under the ID of the user
+	// or of the deleted vaults aren't deleted. Returns a boolean indicating if the user was found.
+	DeleteUser(ctx context.Context, userID string) (bool, error)
 }
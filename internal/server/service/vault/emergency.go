@@ -0,0 +1,166 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func (s *service) GrantEmergencyAccess(ctx context.Context, userID string, vaultID string, grant EmergencyGrant) (EmergencyGrant, error) {
+	llog := s.log.WithLazy("userID", userID, "vaultID", vaultID, "login", grant.ContactLogin, "method", "GrantEmergencyAccess")
+
+	err := s.authorizeGrant(ctx, userID, vaultID)
+	if err != nil {
+		return EmergencyGrant{}, err
+	}
+
+	created, err := s.repo.CreateEmergencyGrant(ctx, userID, vaultID, grant)
+	if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrGrantExists) {
+		return EmergencyGrant{}, err
+	}
+	if err != nil {
+		llog.Errorw("cant create emergency grant", "err", err)
+
+		return EmergencyGrant{}, ErrInternal
+	}
+
+	return created, nil
+}
+
+func (s *service) RevokeEmergencyAccess(ctx context.Context, userID string, vaultID string, login string) error {
+	err := s.authorizeGrant(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteEmergencyGrant(ctx, vaultID, login)
+	if err != nil {
+		s.log.Errorw("cant delete emergency grant", "userID", userID, "vaultID", vaultID, "login", login, "err", err)
+
+		return ErrInternal
+	}
+
+	if vaultID == userID {
+		// there is no member to remove for the own entries
+		removed, err := s.repo.DeleteOwnerContact(ctx, userID, login)
+		if err != nil {
+			s.log.Errorw("cant delete owner contact", "userID", userID, "login", login, "err", err)
+
+			return ErrInternal
+		}
+
+		deleted = deleted || removed
+	}
+
+	if !deleted {
+		return ErrGrantNotFound
+	}
+
+	return nil
+}
+
+func (s *service) ListEmergencyGrants(ctx context.Context, userID string, incoming bool) ([]EmergencyGrant, error) {
+	grants, err := s.repo.ListEmergencyGrants(ctx, userID, incoming)
+	if err != nil {
+		s.log.Errorw("cant list emergency grants", "userID", userID, "incoming", incoming, "err", err)
+
+		return nil, ErrInternal
+	}
+
+	return grants, nil
+}
+
+func (s *service) RequestEmergencyAccess(ctx context.Context, userID string, vaultID string) (EmergencyGrant, error) {
+	grant, ok, err := s.repo.RequestEmergencyAccess(ctx, vaultID, userID)
+	if err != nil {
+		s.log.Errorw("cant request emergency access", "userID", userID, "vaultID", vaultID, "err", err)
+
+		return EmergencyGrant{}, ErrInternal
+	}
+
+	if !ok {
+		return EmergencyGrant{}, ErrGrantNotFound
+	}
+
+	return grant, nil
+}
+
+func (s *service) RejectEmergencyAccess(ctx context.Context, userID string, vaultID string, login string) error {
+	err := s.authorizeGrant(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.repo.RejectEmergencyAccess(ctx, vaultID, login)
+	if err != nil {
+		s.log.Errorw("cant reject emergency access", "userID", userID, "vaultID", vaultID, "login", login, "err", err)
+
+		return ErrInternal
+	}
+
+	if !found {
+		return ErrGrantNotFound
+	}
+
+	return nil
+}
+
+func (s *service) OpenEmergencyAccess(ctx context.Context, userID string, vaultID string) (Vault, error) {
+	llog := s.log.WithLazy("userID", userID, "vaultID", vaultID, "method", "OpenEmergencyAccess")
+
+	grant, ok, err := s.repo.GetEmergencyGrant(ctx, vaultID, userID)
+	if err != nil {
+		llog.Errorw("cant get emergency grant", "err", err)
+
+		return Vault{}, ErrInternal
+	}
+
+	if !ok {
+		return Vault{}, ErrGrantNotFound
+	}
+
+	if !grant.Requested() {
+		return Vault{}, ErrNotRequested
+	}
+
+	now := time.Now()
+	if now.Before(grant.AvailableAt()) {
+		return Vault{}, ErrWaitingPeriod
+	}
+
+	released, err := s.repo.ReleaseEmergencyGrant(ctx, vaultID, userID, now)
+	if err != nil {
+		llog.Errorw("cant release emergency grant", "err", err)
+
+		return Vault{}, ErrInternal
+	}
+
+	if !released {
+		// rejected or revoked in the meantime
+		return Vault{}, ErrNotRequested
+	}
+
+	v, ok, err := s.repo.GetVault(ctx, vaultID, userID)
+	if err != nil {
+		llog.Errorw("cant get vault", "err", err)
+
+		return Vault{}, ErrInternal
+	}
+
+	if !ok {
+		// deleted in the meantime
+		return Vault{}, ErrVaultNotFound
+	}
+
+	return v, nil
+}
+
+// authorizeGrant checks that the user can manage the emergency contacts of the vault:
+// an owner of it, or the user itself if the vault ID is the one of the user, for the own entries.
+func (s *service) authorizeGrant(ctx context.Context, userID string, vaultID string) error {
+	if vaultID == userID {
+		return nil
+	}
+
+	return s.Authorize(ctx, userID, vaultID, RoleOwner)
+}
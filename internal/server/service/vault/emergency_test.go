@@ -0,0 +1,285 @@
+package vault_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestService_GrantEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	grant := vault.EmergencyGrant{ContactLogin: "bob", WrappedKey: []byte("key"), Wait: 72 * time.Hour}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().CreateEmergencyGrant(ctx, "user", "vault", grant).Return(vault.EmergencyGrant{VaultID: "vault", ContactLogin: "bob"}, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		created, err := s.GrantEmergencyAccess(ctx, "user", "vault", grant)
+		require.NoError(t, err)
+		require.Equal(t, vault.EmergencyGrant{VaultID: "vault", ContactLogin: "bob"}, created)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleEditor, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.GrantEmergencyAccess(ctx, "user", "vault", grant)
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+
+	t.Run("own entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CreateEmergencyGrant(ctx, "user", "user", grant).Return(vault.EmergencyGrant{VaultID: "user", ContactLogin: "bob"}, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		created, err := s.GrantEmergencyAccess(ctx, "user", "user", grant)
+		require.NoError(t, err)
+		require.Equal(t, vault.EmergencyGrant{VaultID: "user", ContactLogin: "bob"}, created)
+	})
+
+	t.Run("grant exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().CreateEmergencyGrant(ctx, "user", "vault", grant).Return(vault.EmergencyGrant{}, vault.ErrGrantExists)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.GrantEmergencyAccess(ctx, "user", "vault", grant)
+		require.ErrorIs(t, err, vault.ErrGrantExists)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().CreateEmergencyGrant(ctx, "user", "vault", grant).Return(vault.EmergencyGrant{}, errors.New("error"))
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.GrantEmergencyAccess(ctx, "user", "vault", grant)
+		require.ErrorIs(t, err, vault.ErrInternal)
+	})
+}
+
+func TestService_RevokeEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().DeleteEmergencyGrant(ctx, "vault", "bob").Return(true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RevokeEmergencyAccess(ctx, "user", "vault", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().DeleteEmergencyGrant(ctx, "vault", "bob").Return(false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RevokeEmergencyAccess(ctx, "user", "vault", "bob")
+		require.ErrorIs(t, err, vault.ErrGrantNotFound)
+	})
+
+	t.Run("opened access to own entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().DeleteEmergencyGrant(ctx, "user", "bob").Return(false, nil)
+		repo.EXPECT().DeleteOwnerContact(ctx, "user", "bob").Return(true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RevokeEmergencyAccess(ctx, "user", "user", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("not a contact of own entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().DeleteEmergencyGrant(ctx, "user", "bob").Return(false, nil)
+		repo.EXPECT().DeleteOwnerContact(ctx, "user", "bob").Return(false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RevokeEmergencyAccess(ctx, "user", "user", "bob")
+		require.ErrorIs(t, err, vault.ErrGrantNotFound)
+	})
+}
+
+func TestService_RequestEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		requestedAt := time.Now()
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().RequestEmergencyAccess(ctx, "vault", "user").Return(vault.EmergencyGrant{VaultID: "vault", RequestedAt: requestedAt}, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		grant, err := s.RequestEmergencyAccess(ctx, "user", "vault")
+		require.NoError(t, err)
+		require.Equal(t, requestedAt, grant.RequestedAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().RequestEmergencyAccess(ctx, "vault", "user").Return(vault.EmergencyGrant{}, false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.RequestEmergencyAccess(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrGrantNotFound)
+	})
+}
+
+func TestService_RejectEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().RejectEmergencyAccess(ctx, "vault", "bob").Return(true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RejectEmergencyAccess(ctx, "user", "vault", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleViewer, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RejectEmergencyAccess(ctx, "user", "vault", "bob")
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+}
+
+func TestService_OpenEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetEmergencyGrant(ctx, "vault", "user").Return(vault.EmergencyGrant{
+			VaultID:     "vault",
+			Wait:        time.Hour,
+			RequestedAt: time.Now().Add(-2 * time.Hour),
+		}, true, nil)
+		repo.EXPECT().ReleaseEmergencyGrant(ctx, "vault", "user", gomock.Any()).Return(true, nil)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{ID: "vault", Role: vault.RoleViewer, WrappedKey: []byte("key")}, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		v, err := s.OpenEmergencyAccess(ctx, "user", "vault")
+		require.NoError(t, err)
+		require.Equal(t, vault.Vault{ID: "vault", Role: vault.RoleViewer, WrappedKey: []byte("key")}, v)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetEmergencyGrant(ctx, "vault", "user").Return(vault.EmergencyGrant{}, false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.OpenEmergencyAccess(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrGrantNotFound)
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetEmergencyGrant(ctx, "vault", "user").Return(vault.EmergencyGrant{VaultID: "vault", Wait: time.Hour}, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.OpenEmergencyAccess(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrNotRequested)
+	})
+
+	t.Run("waiting period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetEmergencyGrant(ctx, "vault", "user").Return(vault.EmergencyGrant{
+			VaultID:     "vault",
+			Wait:        time.Hour,
+			RequestedAt: time.Now().Add(-time.Minute),
+		}, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.OpenEmergencyAccess(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrWaitingPeriod)
+	})
+
+	t.Run("rejected in the meantime", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetEmergencyGrant(ctx, "vault", "user").Return(vault.EmergencyGrant{
+			VaultID:     "vault",
+			Wait:        time.Hour,
+			RequestedAt: time.Now().Add(-2 * time.Hour),
+		}, true, nil)
+		repo.EXPECT().ReleaseEmergencyGrant(ctx, "vault", "user", gomock.Any()).Return(false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.OpenEmergencyAccess(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrNotRequested)
+	})
+}
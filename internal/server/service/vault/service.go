@@ -0,0 +1,229 @@
+package vault
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+// New creates a new instance of the vault service with the given repository, the blobs of the deleted vaults and options.
+func New(repo Repository, blobs Blobs, options Options) Service {
+	return &service{
+		repo:    repo,
+		blobs:   blobs,
+		options: options,
+		log:     log.Logger().Named("service.vault"),
+	}
+}
+
+type service struct {
+	repo    Repository
+	blobs   Blobs
+	options Options
+	log     *zap.SugaredLogger
+}
+
+func (s *service) CreateVault(ctx context.Context, userID string, name string, personal bool, wrappedKey []byte) (Vault, error) {
+	if personal && s.options.MaxPersonalVaults > 0 {
+		// concurrent requests can exceed the limit slightly, like the storage quota
+		count, err := s.repo.CountPersonalVaults(ctx, userID)
+		if err != nil {
+			s.log.Errorw("cant count personal vaults", "userID", userID, "err", err)
+
+			return Vault{}, ErrInternal
+		}
+
+		if count >= s.options.MaxPersonalVaults {
+			return Vault{}, ErrTooManyVaults
+		}
+	}
+
+	v, err := s.repo.CreateVault(ctx, userID, name, personal, wrappedKey)
+	if err != nil {
+		s.log.Errorw("cant create vault", "userID", userID, "err", err)
+
+		return Vault{}, ErrInternal
+	}
+
+	return v, nil
+}
+
+func (s *service) ListVaults(ctx context.Context, userID string) ([]Vault, error) {
+	vaults, err := s.repo.ListVaults(ctx, userID)
+	if err != nil {
+		s.log.Errorw("cant list vaults", "userID", userID, "err", err)
+
+		return nil, ErrInternal
+	}
+
+	return vaults, nil
+}
+
+func (s *service) RenameVault(ctx context.Context, userID string, vaultID string, name string) error {
+	err := s.Authorize(ctx, userID, vaultID, RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.repo.RenameVault(ctx, vaultID, name)
+	if err != nil {
+		s.log.Errorw("cant rename vault", "userID", userID, "vaultID", vaultID, "err", err)
+
+		return ErrInternal
+	}
+
+	if !found {
+		// deleted in the meantime
+		return ErrVaultNotFound
+	}
+
+	return nil
+}
+
+func (s *service) DeleteVault(ctx context.Context, userID string, vaultID string) error {
+	err := s.Authorize(ctx, userID, vaultID, RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	refs, deleted, err := s.repo.DeleteVault(ctx, vaultID)
+	if err != nil {
+		s.log.Errorw("cant delete vault", "userID", userID, "vaultID", vaultID, "err", err)
+
+		return ErrInternal
+	}
+
+	if !deleted {
+		return ErrVaultNotEmpty
+	}
+
+	s.blobs.DiscardBlobs(ctx, refs)
+
+	return nil
+}
+
+func (s *service) AddMember(ctx context.Context, userID string, vaultID string, member Member) (Member, error) {
+	llog := s.log.WithLazy("userID", userID, "vaultID", vaultID, "login", member.Login, "method", "AddMember")
+
+	if !member.Role.Valid() {
+		return Member{}, ErrInvalidRole
+	}
+
+	v, ok, err := s.repo.GetVault(ctx, vaultID, userID)
+	if err != nil {
+		llog.Errorw("cant get vault", "err", err)
+
+		return Member{}, ErrInternal
+	}
+
+	if !ok {
+		return Member{}, ErrVaultNotFound
+	}
+
+	if v.Role != RoleOwner {
+		return Member{}, ErrPermissionDenied
+	}
+
+	if v.Personal {
+		return Member{}, ErrPersonalVault
+	}
+
+	added, err := s.repo.AddMember(ctx, vaultID, member)
+	if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrMemberExists) {
+		return Member{}, err
+	}
+	if err != nil {
+		llog.Errorw("cant add member", "err", err)
+
+		return Member{}, ErrInternal
+	}
+
+	return added, nil
+}
+
+func (s *service) ListMembers(ctx context.Context, userID string, vaultID string) ([]Member, error) {
+	err := s.Authorize(ctx, userID, vaultID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, vaultID)
+	if err != nil {
+		s.log.Errorw("cant list members", "userID", userID, "vaultID", vaultID, "err", err)
+
+		return nil, ErrInternal
+	}
+
+	return members, nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, userID string, vaultID string, login string) error {
+	llog := s.log.WithLazy("userID", userID, "vaultID", vaultID, "login", login, "method", "RemoveMember")
+
+	members, err := s.ListMembers(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	var self, member Member
+	found := false
+	for _, m := range members {
+		if m.UserID == userID {
+			self = m
+		}
+
+		if m.Login == login {
+			member = m
+			found = true
+		}
+	}
+
+	if !found {
+		return ErrMemberNotFound
+	}
+
+	// anyone can leave, but only owners can remove others
+	if member.UserID != userID && self.Role != RoleOwner {
+		return ErrPermissionDenied
+	}
+
+	deleted, err := s.repo.RemoveMember(ctx, vaultID, member.UserID)
+	if err != nil {
+		llog.Errorw("cant remove member", "err", err)
+
+		return ErrInternal
+	}
+
+	if !deleted {
+		if member.Role == RoleOwner {
+			return ErrLastOwner
+		}
+
+		// removed in the meantime
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (s *service) Authorize(ctx context.Context, userID string, vaultID string, role Role) error {
+	actual, ok, err := s.repo.GetRole(ctx, vaultID, userID)
+	if err != nil {
+		s.log.Errorw("cant get role", "userID", userID, "vaultID", vaultID, "err", err)
+
+		return ErrInternal
+	}
+
+	if !ok {
+		return ErrVaultNotFound
+	}
+
+	if !actual.Includes(role) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,407 @@
+package vault_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestRole_Includes(t *testing.T) {
+	require.True(t, vault.RoleOwner.Includes(vault.RoleEditor))
+	require.True(t, vault.RoleEditor.Includes(vault.RoleViewer))
+	require.True(t, vault.RoleViewer.Includes(vault.RoleViewer))
+	require.False(t, vault.RoleViewer.Includes(vault.RoleEditor))
+	require.False(t, vault.RoleEditor.Includes(vault.RoleOwner))
+	require.False(t, vault.Role("admin").Includes(vault.RoleViewer))
+}
+
+func TestService_CreateVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CreateVault(ctx, "user", "team", false, []byte("key")).Return(vault.Vault{ID: "vault", Name: "team", Role: vault.RoleOwner}, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		v, err := s.CreateVault(ctx, "user", "team", false, []byte("key"))
+		require.NoError(t, err)
+		require.Equal(t, vault.Vault{ID: "vault", Name: "team", Role: vault.RoleOwner}, v)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CreateVault(ctx, "user", "team", false, []byte("key")).Return(vault.Vault{}, errors.New("error"))
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.CreateVault(ctx, "user", "team", false, []byte("key"))
+		require.ErrorIs(t, err, vault.ErrInternal)
+	})
+
+	t.Run("personal under the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CountPersonalVaults(ctx, "user").Return(1, nil)
+		repo.EXPECT().CreateVault(ctx, "user", "mine", true, []byte("key")).Return(vault.Vault{ID: "vault", Personal: true}, nil)
+
+		s := vault.New(repo, nil, vault.Options{MaxPersonalVaults: 2})
+		v, err := s.CreateVault(ctx, "user", "mine", true, []byte("key"))
+		require.NoError(t, err)
+		require.Equal(t, vault.Vault{ID: "vault", Personal: true}, v)
+	})
+
+	t.Run("too many personal vaults", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CountPersonalVaults(ctx, "user").Return(2, nil)
+
+		s := vault.New(repo, nil, vault.Options{MaxPersonalVaults: 2})
+		_, err := s.CreateVault(ctx, "user", "mine", true, []byte("key"))
+		require.ErrorIs(t, err, vault.ErrTooManyVaults)
+	})
+
+	t.Run("shared vaults arent limited", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CreateVault(ctx, "user", "team", false, []byte("key")).Return(vault.Vault{ID: "vault"}, nil)
+
+		s := vault.New(repo, nil, vault.Options{MaxPersonalVaults: 2})
+		_, err := s.CreateVault(ctx, "user", "team", false, []byte("key"))
+		require.NoError(t, err)
+	})
+
+	t.Run("count error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().CountPersonalVaults(ctx, "user").Return(0, errors.New("error"))
+
+		s := vault.New(repo, nil, vault.Options{MaxPersonalVaults: 2})
+		_, err := s.CreateVault(ctx, "user", "mine", true, []byte("key"))
+		require.ErrorIs(t, err, vault.ErrInternal)
+	})
+}
+
+func TestService_RenameVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().RenameVault(ctx, "vault", "work").Return(true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RenameVault(ctx, "user", "vault", "work")
+		require.NoError(t, err)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleEditor, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RenameVault(ctx, "user", "vault", "work")
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().RenameVault(ctx, "vault", "work").Return(false, errors.New("error"))
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RenameVault(ctx, "user", "vault", "work")
+		require.ErrorIs(t, err, vault.ErrInternal)
+	})
+}
+
+func TestService_DeleteVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		refs := []entry.BlobRef{{Kind: entry.BlobRefVersion, UserID: "vault", Key: "key", Version: 1}}
+		repo.EXPECT().DeleteVault(ctx, "vault").Return(refs, true, nil)
+
+		blobs := mocks.NewMockEntryService(ctrl)
+		blobs.EXPECT().DiscardBlobs(ctx, refs)
+
+		s := vault.New(repo, blobs, vault.Options{})
+		err := s.DeleteVault(ctx, "user", "vault")
+		require.NoError(t, err)
+	})
+
+	t.Run("has entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().DeleteVault(ctx, "vault").Return(nil, false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.DeleteVault(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrVaultNotEmpty)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.Role(""), false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.DeleteVault(ctx, "user", "vault")
+		require.ErrorIs(t, err, vault.ErrVaultNotFound)
+	})
+}
+
+func TestService_AddMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	member := vault.Member{Login: "bob", Role: vault.RoleEditor, WrappedKey: []byte("key")}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{ID: "vault", Role: vault.RoleOwner}, true, nil)
+		repo.EXPECT().AddMember(ctx, "vault", member).Return(vault.Member{UserID: "bob-id", Login: "bob", Role: vault.RoleEditor}, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		added, err := s.AddMember(ctx, "user", "vault", member)
+		require.NoError(t, err)
+		require.Equal(t, "bob-id", added.UserID)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.AddMember(ctx, "user", "vault", vault.Member{Login: "bob", Role: "admin"})
+		require.ErrorIs(t, err, vault.ErrInvalidRole)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{ID: "vault", Role: vault.RoleEditor}, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.AddMember(ctx, "user", "vault", member)
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{}, false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.AddMember(ctx, "user", "vault", member)
+		require.ErrorIs(t, err, vault.ErrVaultNotFound)
+	})
+
+	t.Run("personal vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{ID: "vault", Role: vault.RoleOwner, Personal: true}, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.AddMember(ctx, "user", "vault", member)
+		require.ErrorIs(t, err, vault.ErrPersonalVault)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{ID: "vault", Role: vault.RoleOwner}, true, nil)
+		repo.EXPECT().AddMember(ctx, "vault", member).Return(vault.Member{}, vault.ErrUserNotFound)
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.AddMember(ctx, "user", "vault", member)
+		require.ErrorIs(t, err, vault.ErrUserNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetVault(ctx, "vault", "user").Return(vault.Vault{ID: "vault", Role: vault.RoleOwner}, true, nil)
+		repo.EXPECT().AddMember(ctx, "vault", member).Return(vault.Member{}, errors.New("error"))
+
+		s := vault.New(repo, nil, vault.Options{})
+		_, err := s.AddMember(ctx, "user", "vault", member)
+		require.ErrorIs(t, err, vault.ErrInternal)
+	})
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	members := []vault.Member{
+		{UserID: "user", Login: "alice", Role: vault.RoleOwner},
+		{UserID: "bob-id", Login: "bob", Role: vault.RoleViewer},
+		{UserID: "carol-id", Login: "carol", Role: vault.RoleEditor},
+	}
+
+	t.Run("owner removes member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().ListMembers(ctx, "vault").Return(members, nil)
+		repo.EXPECT().RemoveMember(ctx, "vault", "bob-id").Return(true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RemoveMember(ctx, "user", "vault", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("member leaves", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "bob-id").Return(vault.RoleViewer, true, nil)
+		repo.EXPECT().ListMembers(ctx, "vault").Return(members, nil)
+		repo.EXPECT().RemoveMember(ctx, "vault", "bob-id").Return(true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RemoveMember(ctx, "bob-id", "vault", "bob")
+		require.NoError(t, err)
+	})
+
+	t.Run("non owner removes other member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "carol-id").Return(vault.RoleEditor, true, nil)
+		repo.EXPECT().ListMembers(ctx, "vault").Return(members, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RemoveMember(ctx, "carol-id", "vault", "bob")
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+
+	t.Run("member not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().ListMembers(ctx, "vault").Return(members, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RemoveMember(ctx, "user", "vault", "dave")
+		require.ErrorIs(t, err, vault.ErrMemberNotFound)
+	})
+
+	t.Run("last owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleOwner, true, nil)
+		repo.EXPECT().ListMembers(ctx, "vault").Return(members, nil)
+		repo.EXPECT().RemoveMember(ctx, "vault", "user").Return(false, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.RemoveMember(ctx, "user", "vault", "alice")
+		require.ErrorIs(t, err, vault.ErrLastOwner)
+	})
+}
+
+func TestService_Authorize(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("allowed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleEditor, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.Authorize(ctx, "user", "vault", vault.RoleViewer)
+		require.NoError(t, err)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.RoleViewer, true, nil)
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.Authorize(ctx, "user", "vault", vault.RoleEditor)
+		require.ErrorIs(t, err, vault.ErrPermissionDenied)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockVaultRepository(ctrl)
+		repo.EXPECT().GetRole(ctx, "vault", "user").Return(vault.Role(""), false, errors.New("error"))
+
+		s := vault.New(repo, nil, vault.Options{})
+		err := s.Authorize(ctx, "user", "vault", vault.RoleViewer)
+		require.ErrorIs(t, err, vault.ErrInternal)
+	})
+}
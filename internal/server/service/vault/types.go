@@ -0,0 +1,290 @@
+// Package vault provides core application logic for vaults: named sets of entries with their own keys,
+// either shared by several users with a role each, or personal ones with the creator as the only member.
+// The owners of the vaults can name emergency contacts, and so can any user for the own entries kept outside of the vaults.
+// Those grants use the ID of the user in place of a vault one, and once opened the contact sees the own entries
+// of the user as a personal vault with the viewer role, named after the login of the user.
+// The entries of a vault are managed by the entry service under the ID of the vault instead of a user's,
+// the vault service only manages the vaults, their members and the access.
+package vault
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+)
+
+// Options configures the vault service.
+type Options struct {
+	// MaxPersonalVaults is the number of personal vaults every user can create. Zero means unlimited.
+	// Their content counts toward the storage limits of the user either way.
+	MaxPersonalVaults int
+}
+
+// Role is the access level of a member of a vault.
+type Role string
+
+const (
+	RoleViewer Role = "viewer" // RoleViewer can read the entries of the vault.
+	RoleEditor Role = "editor" // RoleEditor can also change the entries.
+	RoleOwner  Role = "owner"  // RoleOwner can also manage the members.
+)
+
+// rank orders the roles by the access they grant.
+var rank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Valid reports whether the role is one of the known ones.
+func (r Role) Valid() bool {
+	_, ok := rank[r]
+
+	return ok
+}
+
+// Includes reports whether the role grants at least the access of the other one.
+func (r Role) Includes(other Role) bool {
+	return r.Valid() && rank[r] >= rank[other]
+}
+
+// Vault represents a vault as seen by one of its members.
+type Vault struct {
+	ID         string    // ID is the unique identifier of the vault. It is set by the repository.
+	Name       string    // Name is chosen by the creator, it isn't unique.
+	Role       Role      // Role is the role of the member in the vault.
+	WrappedKey []byte    // WrappedKey is the vault key encrypted to the public key of the member, opaque to the server.
+	Personal   bool      // Personal vaults can't have members besides the creator and the emergency contacts who opened the access.
+	CreatedAt  time.Time // CreatedAt is the time the vault was created. It is set by the repository.
+}
+
+// Member represents a user with access to a vault.
+type Member struct {
+	UserID     string    // UserID is the ID of the member.
+	Login      string    // Login is the login of the member.
+	Role       Role      // Role is the role of the member in the vault.
+	WrappedKey []byte    // WrappedKey is the vault key encrypted to the public key of the member, opaque to the server.
+	AddedAt    time.Time // AddedAt is the time the member was added. It is set by the repository.
+}
+
+// EmergencyGrant lets a trusted contact get access to a vault, or the own entries of a user, after a waiting period,
+// unless an owner rejects the request.
+type EmergencyGrant struct {
+	VaultID      string        // VaultID is the ID of the vault, or the one of the user for the own entries.
+	VaultName    string        // VaultName is the name of the vault. It is set by the repository.
+	ContactID    string        // ContactID is the ID of the contact. It is set by the repository.
+	ContactLogin string        // ContactLogin is the login of the contact.
+	OwnerLogin   string        // OwnerLogin is the login of the owner who granted the access. It is set by the repository.
+	WrappedKey   []byte        // WrappedKey is the vault key encrypted to the public key of the contact, opaque to the server.
+	Wait         time.Duration // Wait is the time the request must stay unrejected before the access can be opened.
+	RequestedAt  time.Time     // RequestedAt is the time the contact requested the access, zero if not requested.
+	CreatedAt    time.Time     // CreatedAt is the time the access was granted. It is set by the repository.
+}
+
+// Requested reports whether the contact has requested the access.
+func (g EmergencyGrant) Requested() bool {
+	return !g.RequestedAt.IsZero()
+}
+
+// AvailableAt returns the time the contact can open the access, or zero time if it wasn't requested.
+func (g EmergencyGrant) AvailableAt() time.Time {
+	if !g.Requested() {
+		return time.Time{}
+	}
+
+	return g.RequestedAt.Add(g.Wait)
+}
+
+// ErrInternal is returned when an internal error occurs.
+var ErrInternal = errors.New("internal error")
+
+// ErrVaultNotFound is returned when the vault doesn't exist or the user isn't a member of it.
+var ErrVaultNotFound = errors.New("vault not found")
+
+// ErrUserNotFound is returned when there is no user with the given login.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrMemberNotFound is returned when the user isn't a member of the vault.
+var ErrMemberNotFound = errors.New("member not found")
+
+// ErrMemberExists is returned when the user is already a member of the vault.
+var ErrMemberExists = errors.New("member already exists")
+
+// ErrPermissionDenied is returned when the role of the user in the vault doesn't allow the operation.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrLastOwner is returned when the last owner of a vault is removed from it.
+var ErrLastOwner = errors.New("vault must have an owner")
+
+// ErrInvalidRole is returned when the role isn't one of the known ones.
+var ErrInvalidRole = errors.New("invalid role")
+
+// ErrPersonalVault is returned when a member is added to a personal vault.
+var ErrPersonalVault = errors.New("personal vault can't have members")
+
+// ErrTooManyVaults is returned when the user has reached the limit of personal vaults.
+var ErrTooManyVaults = errors.New("too many personal vaults")
+
+// ErrVaultNotEmpty is returned when a vault with entries, including the trashed ones, is deleted.
+var ErrVaultNotEmpty = errors.New("vault has entries")
+
+// ErrGrantNotFound is returned when there is no emergency grant for the contact.
+var ErrGrantNotFound = errors.New("emergency grant not found")
+
+// ErrGrantExists is returned when the user is an emergency contact of the vault already.
+var ErrGrantExists = errors.New("emergency grant already exists")
+
+// ErrNotRequested is returned when an emergency access is opened without requesting it first, or after the request was rejected.
+var ErrNotRequested = errors.New("emergency access not requested")
+
+// ErrWaitingPeriod is returned when an emergency access is opened before the waiting period has passed.
+var ErrWaitingPeriod = errors.New("waiting period hasn't passed")
+
+// Service defines the interface for managing vaults and their members.
+type Service interface {
+	// CreateVault creates a vault with the user as its owner. The wrapped key is the vault key encrypted to the user.
+	// ErrTooManyVaults is returned if the vault is personal and the user has reached the limit of them.
+	CreateVault(ctx context.Context, userID string, name string, personal bool, wrappedKey []byte) (Vault, error)
+
+	// ListVaults returns the vaults the user is a member of, sorted by name.
+	ListVaults(ctx context.Context, userID string) ([]Vault, error)
+
+	// RenameVault changes the name of the vault. Only owners can rename it.
+	// ErrVaultNotFound is returned if the user isn't a member of the vault and ErrPermissionDenied if the user isn't an owner.
+	RenameVault(ctx context.Context, userID string, vaultID string, name string) error
+
+	// DeleteVault deletes the vault along with its members. Only owners can delete it, and only once it has no entries.
+	// ErrVaultNotFound is returned if the user isn't a member of the vault, ErrPermissionDenied if the user isn't an owner
+	// and ErrVaultNotEmpty if the vault still has entries.
+	DeleteVault(ctx context.Context, userID string, vaultID string) error
+
+	// AddMember adds the user with the given login to the vault. Only owners can add members.
+	// ErrVaultNotFound is returned if the user isn't a member of the vault, ErrPermissionDenied if the user isn't an owner,
+	// ErrUserNotFound if there is no user with the login, ErrMemberExists if the user is a member already
+	// and ErrPersonalVault if the vault is personal.
+	AddMember(ctx context.Context, userID string, vaultID string, member Member) (Member, error)
+
+	// ListMembers returns the members of the vault, sorted by login.
+	// ErrVaultNotFound is returned if the user isn't a member of the vault.
+	ListMembers(ctx context.Context, userID string, vaultID string) ([]Member, error)
+
+	// RemoveMember removes the member with the given login from the vault. Owners can remove anyone, other members only themselves.
+	// ErrVaultNotFound is returned if the user isn't a member of the vault, ErrPermissionDenied if the user can't remove the member,
+	// ErrMemberNotFound if there is no such member and ErrLastOwner if the member is the last owner.
+	RemoveMember(ctx context.Context, userID string, vaultID string, login string) error
+
+	// Authorize checks that the user has at least the role in the vault.
+	// ErrVaultNotFound is returned if the user isn't a member of the vault and ErrPermissionDenied if the role is lower.
+	Authorize(ctx context.Context, userID string, vaultID string, role Role) error
+
+	// GrantEmergencyAccess makes the user with the login of the grant an emergency contact of the vault. Only owners can grant.
+	// The own entries of the user are granted if the vault ID is the ID of the user.
+	// ErrUserNotFound is returned if there is no user with the login and ErrGrantExists if the user is a contact already.
+	GrantEmergencyAccess(ctx context.Context, userID string, vaultID string, grant EmergencyGrant) (EmergencyGrant, error)
+
+	// RevokeEmergencyAccess removes the emergency contact with the login from the vault. Only owners can revoke.
+	// For the own entries of the user the opened access is removed as well, a member of a vault is removed with RemoveMember instead.
+	// ErrGrantNotFound is returned if the user isn't a contact of the vault.
+	RevokeEmergencyAccess(ctx context.Context, userID string, vaultID string, login string) error
+
+	// ListEmergencyGrants returns the grants of the vaults the user owns and of the own entries of the user,
+	// or the ones the user is the contact of if incoming is true.
+	// Wrapped keys aren't set.
+	ListEmergencyGrants(ctx context.Context, userID string, incoming bool) ([]EmergencyGrant, error)
+
+	// RequestEmergencyAccess starts the waiting period of the grant the user is the contact of, unless it's started already.
+	// ErrGrantNotFound is returned if there is no such grant.
+	RequestEmergencyAccess(ctx context.Context, userID string, vaultID string) (EmergencyGrant, error)
+
+	// RejectEmergencyAccess cancels the request of the contact with the login, keeping the grant. Only owners can reject.
+	// ErrGrantNotFound is returned if the user isn't a contact of the vault.
+	RejectEmergencyAccess(ctx context.Context, userID string, vaultID string, login string) error
+
+	// OpenEmergencyAccess adds the user as a viewer of the vault with the wrapped key of the grant and removes the grant.
+	// For the own entries of a user the returned vault is a personal one named after the login of the user.
+	// ErrGrantNotFound is returned if there is no such grant, ErrNotRequested if the access wasn't requested
+	// and ErrWaitingPeriod if the waiting period hasn't passed yet.
+	OpenEmergencyAccess(ctx context.Context, userID string, vaultID string) (Vault, error)
+}
+
+// Repository defines the interface for vault storage operations.
+type Repository interface {
+	// CreateVault stores the vault along with the user as its owner. It returns the vault with the ID and the creation time set.
+	CreateVault(ctx context.Context, userID string, name string, personal bool, wrappedKey []byte) (Vault, error)
+
+	// ListVaults retrieves the vaults the user is a member of, sorted by name.
+	ListVaults(ctx context.Context, userID string) ([]Vault, error)
+
+	// CountPersonalVaults retrieves the number of personal vaults of the user.
+	CountPersonalVaults(ctx context.Context, userID string) (int, error)
+
+	// GetVault retrieves the vault as seen by the user.
+	// It returns the vault, a boolean indicating if the user is a member of it, and an error if any.
+	GetVault(ctx context.Context, vaultID string, userID string) (Vault, bool, error)
+
+	// RenameVault changes the name of the vault. It returns a boolean indicating if the vault exists.
+	RenameVault(ctx context.Context, vaultID string, name string) (bool, error)
+
+	// DeleteVault deletes the vault along with its members, unless it has entries, including the trashed ones.
+	// The old versions of the entries, the unfinished uploads and the shares go along with it.
+	// It returns references to their blobs, a boolean indicating if anything was deleted, and an error if any.
+	DeleteVault(ctx context.Context, vaultID string) ([]entry.BlobRef, bool, error)
+
+	// GetRole retrieves the role of the user in the vault.
+	// It returns the role, a boolean indicating if the user is a member of the vault, and an error if any.
+	GetRole(ctx context.Context, vaultID string, userID string) (Role, bool, error)
+
+	// AddMember stores the user with the login as a member of the vault. Only Login, Role and WrappedKey of the member are used.
+	// It returns the member with the ID and the time it was added set.
+	// ErrUserNotFound is returned if there is no user with the login and ErrMemberExists if the user is a member already.
+	AddMember(ctx context.Context, vaultID string, member Member) (Member, error)
+
+	// ListMembers retrieves the members of the vault, sorted by login. Wrapped keys aren't set.
+	ListMembers(ctx context.Context, vaultID string) ([]Member, error)
+
+	// RemoveMember deletes the member from the vault, unless it's the last owner of it.
+	// It returns a boolean indicating if anything was deleted.
+	RemoveMember(ctx context.Context, vaultID string, userID string) (bool, error)
+
+	// CreateEmergencyGrant stores the grant of the user to the contact with the login. Only ContactLogin, WrappedKey and Wait are used.
+	// It returns the grant with the rest set.
+	// ErrUserNotFound is returned if there is no user with the login and ErrGrantExists if the user is a contact already.
+	CreateEmergencyGrant(ctx context.Context, userID string, vaultID string, grant EmergencyGrant) (EmergencyGrant, error)
+
+	// GetEmergencyGrant retrieves the grant of the vault to the contact.
+	// It returns the grant, a boolean indicating if it exists, and an error if any.
+	GetEmergencyGrant(ctx context.Context, vaultID string, contactID string) (EmergencyGrant, bool, error)
+
+	// ListEmergencyGrants retrieves the grants of the vaults the user owns, or the ones the user is the contact of if incoming is true,
+	// sorted by the vault name. Wrapped keys aren't selected.
+	ListEmergencyGrants(ctx context.Context, userID string, incoming bool) ([]EmergencyGrant, error)
+
+	// DeleteEmergencyGrant deletes the grant of the vault to the contact with the login.
+	// It returns a boolean indicating if anything was deleted.
+	DeleteEmergencyGrant(ctx context.Context, vaultID string, login string) (bool, error)
+
+	// RequestEmergencyAccess sets the request time of the grant to the contact, unless it's set already.
+	// It returns the grant, a boolean indicating if it exists, and an error if any.
+	RequestEmergencyAccess(ctx context.Context, vaultID string, contactID string) (EmergencyGrant, bool, error)
+
+	// RejectEmergencyAccess clears the request time of the grant to the contact with the login.
+	// It returns a boolean indicating if the grant exists.
+	RejectEmergencyAccess(ctx context.Context, vaultID string, login string) (bool, error)
+
+	// ReleaseEmergencyGrant deletes the grant to the contact and adds the contact as a viewer of the vault with the wrapped key,
+	// or an owner contact of the user for the own entries, if the grant is requested and its waiting period ends before the given time.
+	// It returns a boolean indicating if the grant was released.
+	ReleaseEmergencyGrant(ctx context.Context, vaultID string, contactID string, availableBefore time.Time) (bool, error)
+
+	// DeleteOwnerContact deletes the opened emergency access of the contact with the login to the own entries of the owner.
+	// It returns a boolean indicating if anything was deleted.
+	DeleteOwnerContact(ctx context.Context, ownerID string, login string) (bool, error)
+}
+
+// Blobs deletes the blobs left by the metadata of a deleted vault, implemented by the entry service.
+type Blobs interface {
+	// DiscardBlobs deletes the blobs referenced by the metadata deleted already.
+	DiscardBlobs(ctx context.Context, refs []entry.BlobRef)
+}
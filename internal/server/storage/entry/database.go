@@ -7,35 +7,75 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
 )
 
+// metadataColumns lists the columns scanned by scanMetadata, in order.
+const metadataColumns = "key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name"
+
 // NewDatabaseMetadataRepository creates a new instance of DatabaseMetadataRepository.
-// It requires a database connection as input.
-func NewDatabaseMetadataRepository(db *sql.DB) *DatabaseMetadataRepository {
+// It requires a database connection, and the role of the vault members the usage of the vault is charged to.
+func NewDatabaseMetadataRepository(db *sql.DB, ownerRole string) *DatabaseMetadataRepository {
 	return &DatabaseMetadataRepository{
-		db: db,
+		db:        db,
+		ownerRole: ownerRole,
 	}
 }
 
 // DatabaseMetadataRepository is a repository for managing metadata entries in a database.
 type DatabaseMetadataRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	ownerRole string // ownerRole is the role of the vault members charged for the vault in GetUsage and ListUsage.
 }
 
 // GetMetadata retrieves metadata for a given user and key from the database.
 // It returns the metadata, a boolean indicating if the metadata was found, and an error if any occurred.
 func (d *DatabaseMetadataRepository) GetMetadata(ctx context.Context, userID string, key string) (entry.Metadata, bool, error) {
-	row := d.db.QueryRowContext(
+	return getMetadata(ctx, d.db, userID, key)
+}
+
+// GetMetadataBatch retrieves metadata of the entries with the given keys in one read-only transaction,
+// so all of them are read at the same point in time. Missing entries are omitted, the rest are in the order of keys.
+func (d *DatabaseMetadataRepository) GetMetadataBatch(ctx context.Context, userID string, keys []string) (mds []entry.Metadata, err error) {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		// nothing is written, so committing only ends the transaction
+		commitErr := tx.Commit()
+		if err == nil && commitErr != nil {
+			err = fmt.Errorf("cant commit transaction: %w", commitErr)
+		}
+	}()
+
+	mds = make([]entry.Metadata, 0, len(keys))
+	for _, key := range keys {
+		md, ok, err := getMetadata(ctx, tx, userID, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			mds = append(mds, md)
+		}
+	}
+
+	return mds, nil
+}
+
+// getMetadata retrieves metadata for a given user and key with q.
+func getMetadata(ctx context.Context, q queryRower, userID string, key string) (entry.Metadata, bool, error) {
+	row := q.QueryRowContext(
 		ctx,
-		"SELECT key, name, notes FROM entries WHERE user_id = $1 AND key = $2",
+		"SELECT "+metadataColumns+" FROM entries WHERE user_id = $1 AND key = $2",
 		userID,
 		key,
 	)
 
-	var md entry.Metadata
-	err := row.Scan(&md.Key, &md.Name, &md.Notes)
+	md, err := scanMetadata(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entry.Metadata{}, false, nil
@@ -48,11 +88,605 @@ func (d *DatabaseMetadataRepository) GetMetadata(ctx context.Context, userID str
 }
 
 // SetMetadata inserts or updates metadata for a given user and key in the database.
-// If the key already exists, the metadata is updated.
-func (d *DatabaseMetadataRepository) SetMetadata(ctx context.Context, userID string, md entry.Metadata) error {
+// If expectedVersion is zero, a new entry is inserted. Otherwise the entry with that version is updated:
+// the update time is set to now and the version is incremented, while the creation time is kept.
+// If keepVersion is set, the replaced row is copied to the previous versions in the same statement,
+// replacing a stale version with the same number left from a deleted entry.
+// entry.ErrConflict is returned if there is no row to insert or update, so the check and the write are atomic.
+// The change is recorded to the change log in the same statement and returned along with the stored metadata.
+func (d *DatabaseMetadataRepository) SetMetadata(ctx context.Context, userID string, md entry.Metadata, expectedVersion int64, keepVersion bool) (entry.Change, error) {
+	return d.setMetadata(ctx, userID, md, expectedVersion, keepVersion, "")
+}
+
+// CommitUpload stores metadata of the uploaded entry the same way as SetMetadata does,
+// deleting the upload session in the same statement if the metadata is stored.
+func (d *DatabaseMetadataRepository) CommitUpload(ctx context.Context, userID string, uploadID string, md entry.Metadata, expectedVersion int64, keepVersion bool) (entry.Change, error) {
+	return d.setMetadata(ctx, userID, md, expectedVersion, keepVersion, uploadID)
+}
+
+// setMetadata stores metadata as described by SetMetadata. The upload session is deleted along with it if uploadID is set.
+func (d *DatabaseMetadataRepository) setMetadata(ctx context.Context, userID string, md entry.Metadata, expectedVersion int64, keepVersion bool, uploadID string) (entry.Change, error) {
+	var query string
+	var args []any
+
+	if expectedVersion == 0 {
+		query = "WITH e AS (INSERT INTO entries (user_id, key, name, notes, type, size, checksum, envelope, blob_name) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (user_id, key) DO NOTHING RETURNING user_id, key, version, created_at, updated_at), "
+		args = []any{userID, md.Key, md.Name, md.Notes, md.Type, md.Size, md.Checksum, md.Envelope, md.BlobName}
+	} else {
+		// p is read from the snapshot the update starts from, the update only succeeds if it's still the same row
+		query = "WITH p AS (SELECT user_id, " + metadataColumns + " FROM entries WHERE user_id = $1 AND key = $2 AND version = $10), " +
+			"e AS (UPDATE entries SET name = $3, notes = $4, type = $5, size = $6, checksum = $7, envelope = $8, blob_name = $9, updated_at = now(), version = version + 1 WHERE user_id = $1 AND key = $2 AND version = $10 RETURNING user_id, key, version, created_at, updated_at), " +
+			"v AS (INSERT INTO entry_versions (user_id, " + metadataColumns + ") " +
+			"SELECT p.user_id, p.key, p.name, p.notes, p.type, p.size, p.created_at, p.updated_at, p.version, p.checksum, p.envelope, p.blob_name FROM p, e WHERE $11::boolean " +
+			"ON CONFLICT (user_id, key, version) DO UPDATE SET name = EXCLUDED.name, notes = EXCLUDED.notes, type = EXCLUDED.type, size = EXCLUDED.size, " +
+			"created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, checksum = EXCLUDED.checksum, envelope = EXCLUDED.envelope, blob_name = EXCLUDED.blob_name), "
+		args = []any{userID, md.Key, md.Name, md.Notes, md.Type, md.Size, md.Checksum, md.Envelope, md.BlobName, expectedVersion, keepVersion}
+	}
+
+	if uploadID != "" {
+		args = append(args, uploadID)
+		query += fmt.Sprintf("u AS (DELETE FROM upload_sessions WHERE user_id = $1 AND id = $%d AND EXISTS (SELECT 1 FROM e)), ", len(args))
+	}
+
+	row := d.db.QueryRowContext(
+		ctx,
+		query+
+			"c AS (INSERT INTO entry_changes (user_id, key, version) SELECT user_id, key, version FROM e RETURNING id, changed_at) "+
+			"SELECT c.id, c.changed_at, e.version, e.created_at, e.updated_at FROM e, c",
+		args...,
+	)
+
+	change := entry.Change{Metadata: md}
+	err := row.Scan(&change.Cursor, &change.ChangedAt, &change.Metadata.Version, &change.Metadata.CreatedAt, &change.Metadata.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.Change{}, entry.ErrConflict
+		}
+
+		return entry.Change{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return change, nil
+}
+
+// DeleteMetadata removes metadata for a given user and key from the database.
+// If expectedVersion is not zero, only the entry with that version is deleted, and entry.ErrConflict
+// is returned if there is no such entry. It returns an error if the operation fails.
+// A tombstone is recorded to the change log in the same statement and returned if anything was deleted.
+func (d *DatabaseMetadataRepository) DeleteMetadata(ctx context.Context, userID string, key string, expectedVersion int64) (entry.Change, bool, error) {
+	return deleteMetadata(ctx, d.db, userID, key, expectedVersion, false)
+}
+
+// TrashMetadata removes metadata the same way as DeleteMetadata does, copying the deleted row to the trash
+// in the same statement. A row deleted earlier with the same key is replaced in the trash.
+func (d *DatabaseMetadataRepository) TrashMetadata(ctx context.Context, userID string, key string, expectedVersion int64) (entry.Change, bool, error) {
+	return deleteMetadata(ctx, d.db, userID, key, expectedVersion, true)
+}
+
+// DeleteMetadataBatch deletes metadata of the entries in one transaction, the same way as DeleteMetadata does.
+// If any of them is rejected with entry.ErrConflict, the transaction is rolled back and nothing is deleted.
+// It returns the recorded tombstones of the deleted entries.
+func (d *DatabaseMetadataRepository) DeleteMetadataBatch(ctx context.Context, userID string, items []entry.DeleteItem) ([]entry.Change, error) {
+	return d.deleteMetadataBatch(ctx, userID, items, false)
+}
+
+// TrashMetadataBatch moves metadata of the entries to the trash in one transaction, the same way as TrashMetadata does.
+// If any of them is rejected with entry.ErrConflict, the transaction is rolled back and nothing is deleted.
+func (d *DatabaseMetadataRepository) TrashMetadataBatch(ctx context.Context, userID string, items []entry.DeleteItem) ([]entry.Change, error) {
+	return d.deleteMetadataBatch(ctx, userID, items, true)
+}
+
+// deleteMetadataBatch deletes metadata of the entries in one transaction, keeping them in the trash if requested.
+func (d *DatabaseMetadataRepository) deleteMetadataBatch(ctx context.Context, userID string, items []entry.DeleteItem, trash bool) (changes []entry.Change, err error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	changes = make([]entry.Change, 0, len(items))
+	for _, item := range items {
+		change, deleted, err := deleteMetadata(ctx, tx, userID, item.Key, item.ExpectedVersion, trash)
+		if err != nil {
+			return nil, err
+		}
+
+		if deleted {
+			changes = append(changes, change)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("cant commit transaction: %w", err)
+	}
+
+	return changes, nil
+}
+
+// RenameMetadata moves metadata of an entry and its previous versions to the new key, name and envelope in one transaction.
+// The entry is deleted and inserted under the new key with the version incremented, recording both changes to the change log.
+// entry.ErrConflict is returned if there is no entry with expectedVersion,
+// and entry.ErrEntryExists if there is an entry with the new key.
+func (d *DatabaseMetadataRepository) RenameMetadata(ctx context.Context, userID string, key string, newKey string, newName string, newEnvelope []byte, expectedVersion int64) (result entry.RenameResult, err error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entry.RenameResult{}, fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	md := &result.Renamed.Metadata
+	result.Deleted = entry.Change{Metadata: entry.Metadata{Key: key, Version: expectedVersion}, Deleted: true}
+
+	err = tx.QueryRowContext(
+		ctx,
+		"WITH e AS (DELETE FROM entries WHERE user_id = $1 AND key = $2 AND version = $3 RETURNING user_id, "+metadataColumns+"), "+
+			"c AS (INSERT INTO entry_changes (user_id, key, version, deleted) SELECT user_id, key, version, true FROM e RETURNING id, changed_at) "+
+			"SELECT e.notes, e.type, e.size, e.created_at, e.checksum, e.blob_name, c.id, c.changed_at FROM e, c",
+		userID,
+		key,
+		expectedVersion,
+	).Scan(&md.Notes, &md.Type, &md.Size, &md.CreatedAt, &md.Checksum, &md.BlobName, &result.Deleted.Cursor, &result.Deleted.ChangedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.RenameResult{}, entry.ErrConflict
+		}
+
+		return entry.RenameResult{}, fmt.Errorf("delete query error: %w", err)
+	}
+
+	md.Key = newKey
+	md.Name = newName
+	md.Envelope = newEnvelope
+	if newEnvelope != nil {
+		// the envelope carries the type, so it isn't kept in plain text anymore
+		md.Type = ""
+	}
+	md.Version = expectedVersion + 1
+
+	result.Renamed, err = insertMetadata(ctx, tx, userID, *md)
+	if err != nil {
+		return entry.RenameResult{}, err
+	}
+
+	// versions left from a deleted entry with the new key would clash with the moved ones
+	result.StaleBlobs, err = deleteVersions(ctx, tx, userID, newKey)
+	if err != nil {
+		return entry.RenameResult{}, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE entry_versions SET key = $3, name = $4, envelope = $5, type = CASE WHEN $5::bytea IS NULL THEN type ELSE '' END "+
+			"WHERE user_id = $1 AND key = $2",
+		userID,
+		key,
+		newKey,
+		newName,
+		newEnvelope,
+	)
+	if err != nil {
+		return entry.RenameResult{}, fmt.Errorf("move versions query error: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entry.RenameResult{}, fmt.Errorf("cant commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// insertMetadata inserts md keeping its creation time and version, sets the update time to now,
+// and records the change to the change log in the same statement.
+// entry.ErrEntryExists is returned if there is an entry with the same key.
+func insertMetadata(ctx context.Context, q queryRower, userID string, md entry.Metadata) (entry.Change, error) {
+	change := entry.Change{Metadata: md}
+
+	err := q.QueryRowContext(
+		ctx,
+		"WITH e AS (INSERT INTO entries (user_id, key, name, notes, type, size, checksum, envelope, created_at, version, blob_name) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (user_id, key) DO NOTHING RETURNING user_id, key, version, updated_at), "+
+			"c AS (INSERT INTO entry_changes (user_id, key, version) SELECT user_id, key, version FROM e RETURNING id, changed_at) "+
+			"SELECT c.id, c.changed_at, e.updated_at FROM e, c",
+		userID,
+		md.Key,
+		md.Name,
+		md.Notes,
+		md.Type,
+		md.Size,
+		md.Checksum,
+		md.Envelope,
+		md.CreatedAt,
+		md.Version,
+		md.BlobName,
+	).Scan(&change.Cursor, &change.ChangedAt, &change.Metadata.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.Change{}, entry.ErrEntryExists
+		}
+
+		return entry.Change{}, fmt.Errorf("insert query error: %w", err)
+	}
+
+	return change, nil
+}
+
+// deleteVersions deletes all the previous versions of the entry with tx and returns their blob names.
+func deleteVersions(ctx context.Context, tx *sql.Tx, userID string, key string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "DELETE FROM entry_versions WHERE user_id = $1 AND key = $2 RETURNING blob_name", userID, key)
+	if err != nil {
+		return nil, fmt.Errorf("delete versions query error: %w", err)
+	}
+
+	return scanBlobNames(rows)
+}
+
+// deleteMetadata removes metadata for a given user and key with q, copying it to the trash if requested.
+// The blob name of the returned tombstone is the one no longer referenced: of the deleted entry,
+// or of the entry replaced in the trash.
+func deleteMetadata(ctx context.Context, q queryRower, userID string, key string, expectedVersion int64, trash bool) (entry.Change, bool, error) {
+	query := "WITH e AS (DELETE FROM entries WHERE user_id = $1 AND key = $2 AND ($3 = 0 OR version = $3) RETURNING user_id, key, version, blob_name), "
+	released := "e.blob_name"
+	if trash {
+		// p is the entry deleted earlier, read before it's replaced
+		query = "WITH p AS (SELECT blob_name FROM entry_trash WHERE user_id = $1 AND key = $2), " +
+			"e AS (DELETE FROM entries WHERE user_id = $1 AND key = $2 AND ($3 = 0 OR version = $3) RETURNING user_id, " + metadataColumns + "), " +
+			"t AS (INSERT INTO entry_trash (user_id, " + metadataColumns + ") SELECT user_id, " + metadataColumns + " FROM e " +
+			"ON CONFLICT (user_id, key) DO UPDATE SET name = EXCLUDED.name, notes = EXCLUDED.notes, type = EXCLUDED.type, size = EXCLUDED.size, " +
+			"created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, version = EXCLUDED.version, checksum = EXCLUDED.checksum, " +
+			"envelope = EXCLUDED.envelope, blob_name = EXCLUDED.blob_name, deleted_at = now()), "
+		released = "COALESCE((SELECT blob_name FROM p), '')"
+	}
+
+	row := q.QueryRowContext(
+		ctx,
+		query+
+			"c AS (INSERT INTO entry_changes (user_id, key, version, deleted) SELECT user_id, key, version, true FROM e RETURNING id, version, changed_at) "+
+			"SELECT c.id, c.version, c.changed_at, "+released+" FROM c, e",
+		userID,
+		key,
+		expectedVersion,
+	)
+
+	change := entry.Change{Metadata: entry.Metadata{Key: key}, Deleted: true}
+	err := row.Scan(&change.Cursor, &change.Metadata.Version, &change.ChangedAt, &change.Metadata.BlobName)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return entry.Change{}, false, fmt.Errorf("query error: %w", err)
+		}
+
+		if expectedVersion != 0 {
+			return entry.Change{}, false, entry.ErrConflict
+		}
+
+		// deleting a missing entry is fine
+		return entry.Change{}, false, nil
+	}
+
+	return change, true, nil
+}
+
+// ListTrash retrieves metadata of the user's entries kept in the trash, most recently deleted first.
+func (d *DatabaseMetadataRepository) ListTrash(ctx context.Context, userID string) ([]entry.TrashItem, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT "+metadataColumns+", deleted_at FROM entry_trash WHERE user_id = $1 ORDER BY deleted_at DESC, key",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]entry.TrashItem, 0)
+	for rows.Next() {
+		var item entry.TrashItem
+		md := &item.Metadata
+
+		err = rows.Scan(&md.Key, &md.Name, &md.Notes, &md.Type, &md.Size, &md.CreatedAt, &md.UpdatedAt, &md.Version, &md.Checksum, &md.Envelope, &md.BlobName, &item.DeletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreMetadata moves metadata of an entry from the trash back to the entries in one transaction.
+// The entry keeps its creation time, and its version is incremented, so it is newer than the tombstone in the change log.
+// entry.ErrEntryNotFound is returned if the entry isn't in the trash, and entry.ErrEntryExists if there is an entry with the same key.
+func (d *DatabaseMetadataRepository) RestoreMetadata(ctx context.Context, userID string, key string) (change entry.Change, err error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entry.Change{}, fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	md := entry.Metadata{Key: key}
+	err = tx.QueryRowContext(
+		ctx,
+		"DELETE FROM entry_trash WHERE user_id = $1 AND key = $2 RETURNING name, notes, type, size, created_at, version, checksum, envelope, blob_name",
+		userID,
+		key,
+	).Scan(&md.Name, &md.Notes, &md.Type, &md.Size, &md.CreatedAt, &md.Version, &md.Checksum, &md.Envelope, &md.BlobName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.Change{}, entry.ErrEntryNotFound
+		}
+
+		return entry.Change{}, fmt.Errorf("delete query error: %w", err)
+	}
+
+	md.Version++
+
+	change, err = insertMetadata(ctx, tx, userID, md)
+	if err != nil {
+		return entry.Change{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entry.Change{}, fmt.Errorf("cant commit transaction: %w", err)
+	}
+
+	return change, nil
+}
+
+// PurgeTrash deletes metadata of an entry from the trash if it has the expected version, or any if expectedVersion is 0.
+// The previous versions of the entry are deleted along with it, unless an entry with the same key was stored since.
+// It returns the blob names of the deleted metadata and a boolean indicating if anything was deleted.
+func (d *DatabaseMetadataRepository) PurgeTrash(ctx context.Context, userID string, key string, expectedVersion int64) ([]string, bool, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"WITH t AS (DELETE FROM entry_trash WHERE user_id = $1 AND key = $2 AND ($3 = 0 OR version = $3) RETURNING blob_name), "+
+			"v AS (DELETE FROM entry_versions WHERE user_id = $1 AND key = $2 AND EXISTS (SELECT 1 FROM t) "+
+			"AND NOT EXISTS (SELECT 1 FROM entries WHERE user_id = $1 AND key = $2) RETURNING blob_name) "+
+			"SELECT blob_name FROM t UNION ALL SELECT blob_name FROM v",
+		userID,
+		key,
+		expectedVersion,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("query error: %w", err)
+	}
+
+	names, err := scanBlobNames(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the trash row itself is always returned when it's deleted
+	return names, len(names) > 0, nil
+}
+
+// PurgeExpiredTrash deletes metadata of the entries of all the users deleted before the given time from the trash,
+// along with their previous versions the same way as PurgeTrash does.
+// It returns references to the blobs of the purged metadata.
+func (d *DatabaseMetadataRepository) PurgeExpiredTrash(ctx context.Context, before time.Time) ([]entry.BlobRef, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"WITH t AS (DELETE FROM entry_trash WHERE deleted_at < $1 RETURNING user_id, key, version, blob_name), "+
+			"v AS (DELETE FROM entry_versions ev USING t WHERE ev.user_id = t.user_id AND ev.key = t.key "+
+			"AND NOT EXISTS (SELECT 1 FROM entries e WHERE e.user_id = t.user_id AND e.key = t.key) "+
+			"RETURNING ev.user_id, ev.key, ev.version, ev.blob_name) "+
+			"SELECT $2::text, user_id, key, version, blob_name FROM t "+
+			"UNION ALL SELECT $3::text, user_id, key, version, blob_name FROM v",
+		before,
+		entry.BlobRefTrash,
+		entry.BlobRefVersion,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	refs := make([]entry.BlobRef, 0)
+	for rows.Next() {
+		var ref entry.BlobRef
+		err = rows.Scan(&ref.Kind, &ref.UserID, &ref.Key, &ref.Version, &ref.BlobName)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return refs, nil
+}
+
+// ListMetadata retrieves metadata of the user's entries ordered by key.
+// Only entries with keys greater than opts.After are returned, at most opts.Limit of them.
+func (d *DatabaseMetadataRepository) ListMetadata(ctx context.Context, userID string, opts entry.ListOptions) ([]entry.Metadata, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT "+metadataColumns+" FROM entries WHERE user_id = $1 AND key > $2 ORDER BY key LIMIT $3",
+		userID,
+		opts.After,
+		opts.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	mds := make([]entry.Metadata, 0)
+	for rows.Next() {
+		md, err := scanMetadata(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		mds = append(mds, md)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return mds, nil
+}
+
+// ListChanges retrieves the latest change of every entry of the user changed after the given cursor, ordered by cursor.
+// Metadata of the changed entries is joined from the entries table, tombstones only have the key and the version.
+func (d *DatabaseMetadataRepository) ListChanges(ctx context.Context, userID string, after int64, limit int) ([]entry.Change, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT c.id, c.key, c.version, c.deleted, c.changed_at, e.name, e.notes, e.type, e.size, e.created_at, e.updated_at, e.checksum, e.envelope "+
+			"FROM entry_changes c LEFT JOIN entries e ON e.user_id = c.user_id AND e.key = c.key "+
+			"WHERE c.user_id = $1 AND c.id > $2 "+
+			"AND NOT EXISTS (SELECT 1 FROM entry_changes n WHERE n.user_id = c.user_id AND n.key = c.key AND n.id > c.id) "+
+			"ORDER BY c.id LIMIT $3",
+		userID,
+		after,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]entry.Change, 0)
+	for rows.Next() {
+		var change entry.Change
+		var name, entryType sql.NullString
+		var size sql.NullInt64
+		var createdAt, updatedAt sql.NullTime
+
+		err = rows.Scan(
+			&change.Cursor,
+			&change.Metadata.Key,
+			&change.Metadata.Version,
+			&change.Deleted,
+			&change.ChangedAt,
+			&name,
+			&change.Metadata.Notes,
+			&entryType,
+			&size,
+			&createdAt,
+			&updatedAt,
+			&change.Metadata.Checksum,
+			&change.Metadata.Envelope,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		change.Metadata.Name = name.String
+		change.Metadata.Type = entryType.String
+		change.Metadata.Size = size.Int64
+		change.Metadata.CreatedAt = createdAt.Time
+		change.Metadata.UpdatedAt = updatedAt.Time
+
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetLatestCursor retrieves the cursor of the latest change of the user's entries, zero if there are none.
+func (d *DatabaseMetadataRepository) GetLatestCursor(ctx context.Context, userID string) (int64, error) {
+	var cursor int64
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(MAX(id), 0) FROM entry_changes WHERE user_id = $1",
+		userID,
+	).Scan(&cursor)
+	if err != nil {
+		return 0, fmt.Errorf("query error: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// GetVersion retrieves metadata of a previous version of the entry.
+// It returns the metadata, a boolean indicating if the version was found, and an error if any occurred.
+func (d *DatabaseMetadataRepository) GetVersion(ctx context.Context, userID string, key string, version int64) (entry.Metadata, bool, error) {
+	row := d.db.QueryRowContext(
+		ctx,
+		"SELECT "+metadataColumns+" FROM entry_versions WHERE user_id = $1 AND key = $2 AND version = $3",
+		userID,
+		key,
+		version,
+	)
+
+	md, err := scanMetadata(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.Metadata{}, false, nil
+		}
+
+		return entry.Metadata{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return md, true, nil
+}
+
+// ListVersions retrieves metadata of the previous versions of the entry, newest first.
+func (d *DatabaseMetadataRepository) ListVersions(ctx context.Context, userID string, key string) ([]entry.Metadata, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT "+metadataColumns+" FROM entry_versions WHERE user_id = $1 AND key = $2 ORDER BY version DESC",
+		userID,
+		key,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	mds := make([]entry.Metadata, 0)
+	for rows.Next() {
+		md, err := scanMetadata(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		mds = append(mds, md)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return mds, nil
+}
+
+// DeleteVersion deletes metadata of a previous version of the entry. Deleting a missing version is not an error.
+func (d *DatabaseMetadataRepository) DeleteVersion(ctx context.Context, userID string, key string, version int64) error {
 	_, err := d.db.ExecContext(
 		ctx,
-		"INSERT INTO entries (user_id, key, name, notes) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, key) DO UPDATE SET name = excluded.name, notes = excluded.notes", userID, md.Key, md.Name, md.Notes)
+		"DELETE FROM entry_versions WHERE user_id = $1 AND key = $2 AND version = $3",
+		userID,
+		key,
+		version,
+	)
 	if err != nil {
 		return fmt.Errorf("query error: %w", err)
 	}
@@ -60,19 +694,498 @@ func (d *DatabaseMetadataRepository) SetMetadata(ctx context.Context, userID str
 	return nil
 }
 
-// DeleteMetadata removes metadata for a given user and key from the database.
-// It returns an error if the operation fails.
-func (d *DatabaseMetadataRepository) DeleteMetadata(ctx context.Context, userID string, key string) error {
-	_, err := d.db.ExecContext(
+// PruneVersions deletes all but the newest keep versions of the entry.
+// It returns the blob names of the deleted versions.
+func (d *DatabaseMetadataRepository) PruneVersions(ctx context.Context, userID string, key string, keep int) ([]string, error) {
+	rows, err := d.db.QueryContext(
 		ctx,
-		"DELETE FROM entries WHERE user_id = $1 AND key = $2",
+		"DELETE FROM entry_versions WHERE user_id = $1 AND key = $2 AND version NOT IN (SELECT version FROM entry_versions WHERE user_id = $1 AND key = $2 ORDER BY version DESC LIMIT $3) RETURNING blob_name",
 		userID,
 		key,
+		keep,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	return scanBlobNames(rows)
+}
+
+// CreateUploadSession stores a new upload session. The ID and the creation time are generated by the database.
+func (d *DatabaseMetadataRepository) CreateUploadSession(ctx context.Context, userID string, session entry.UploadSession) (entry.UploadSession, error) {
+	md := session.Metadata
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"INSERT INTO upload_sessions (user_id, key, name, notes, type, envelope, size, expected_version) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at",
+		userID,
+		md.Key,
+		md.Name,
+		md.Notes,
+		md.Type,
+		md.Envelope,
+		session.Size,
+		session.ExpectedVersion,
+	).Scan(&session.ID, &session.CreatedAt)
+	if err != nil {
+		return entry.UploadSession{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetUploadSession retrieves an upload session of the user by its ID.
+// It returns the session, a boolean indicating if the session was found, and an error if any occurred.
+func (d *DatabaseMetadataRepository) GetUploadSession(ctx context.Context, userID string, uploadID string) (entry.UploadSession, bool, error) {
+	var session entry.UploadSession
+	md := &session.Metadata
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT id, key, name, notes, type, envelope, size, expected_version, created_at FROM upload_sessions WHERE user_id = $1 AND id = $2",
+		userID,
+		uploadID,
+	).Scan(&session.ID, &md.Key, &md.Name, &md.Notes, &md.Type, &md.Envelope, &session.Size, &session.ExpectedVersion, &session.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.UploadSession{}, false, nil
+		}
+
+		return entry.UploadSession{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return session, true, nil
+}
+
+// DeleteUploadSession deletes an upload session of the user by its ID. Deleting a missing session is not an error.
+func (d *DatabaseMetadataRepository) DeleteUploadSession(ctx context.Context, userID string, uploadID string) error {
+	_, err := d.db.ExecContext(
+		ctx,
+		"DELETE FROM upload_sessions WHERE user_id = $1 AND id = $2",
+		userID,
+		uploadID,
+	)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+
+	return nil
+}
+
+// LeaseUploadSession leases an upload session of the user to the holder of the token for the period,
+// unless it's leased to another holder and the lease hasn't expired yet. Leasing it again with the same token extends the lease.
+// The time is taken from the database, so the clocks of the server instances don't matter.
+// It returns false if the session doesn't exist or is leased to another holder.
+func (d *DatabaseMetadataRepository) LeaseUploadSession(ctx context.Context, userID string, uploadID string, token string, period time.Duration) (bool, error) {
+	res, err := d.db.ExecContext(
+		ctx,
+		"UPDATE upload_sessions SET lease_token = $3, leased_until = now() + make_interval(secs => $4) "+
+			"WHERE user_id = $1 AND id = $2 AND (lease_token IS NULL OR lease_token = $3 OR leased_until < now())",
+		userID,
+		uploadID,
+		token,
+		period.Seconds(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReleaseUploadSession ends the lease of an upload session of the user held with the token.
+// Releasing a session that is missing or leased to another holder is not an error.
+func (d *DatabaseMetadataRepository) ReleaseUploadSession(ctx context.Context, userID string, uploadID string, token string) error {
+	_, err := d.db.ExecContext(
+		ctx,
+		"UPDATE upload_sessions SET lease_token = NULL, leased_until = NULL WHERE user_id = $1 AND id = $2 AND lease_token = $3",
+		userID,
+		uploadID,
+		token,
+	)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+
+	return nil
+}
+
+// ListBlobRefs retrieves references to blobs from the entries, their previous versions, the trash, the upload sessions
+// and the shares of all the users.
+func (d *DatabaseMetadataRepository) ListBlobRefs(ctx context.Context) ([]entry.BlobRef, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT $1::text, user_id, key, version, blob_name, '', '' FROM entries "+
+			"UNION ALL SELECT $2::text, user_id, key, version, blob_name, '', '' FROM entry_versions "+
+			"UNION ALL SELECT $3::text, user_id, key, expected_version, '', id::text, '' FROM upload_sessions "+
+			"UNION ALL SELECT $4::text, user_id, key, version, blob_name, '', '' FROM entry_trash "+
+			"UNION ALL SELECT $5::text, owner_id, key, 0, '', '', recipient_id::text FROM entry_shares",
+		entry.BlobRefEntry,
+		entry.BlobRefVersion,
+		entry.BlobRefUpload,
+		entry.BlobRefTrash,
+		entry.BlobRefShare,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	refs := make([]entry.BlobRef, 0)
+	for rows.Next() {
+		var ref entry.BlobRef
+		err = rows.Scan(&ref.Kind, &ref.UserID, &ref.Key, &ref.Version, &ref.BlobName, &ref.UploadID, &ref.RecipientID)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return refs, nil
+}
+
+// DeleteOwnerMetadata deletes the entries, their previous versions, the trash, the upload sessions and the change log
+// of the owner, along with the shares by or with the owner, in one statement.
+// It returns references to the blobs of the deleted metadata.
+func (d *DatabaseMetadataRepository) DeleteOwnerMetadata(ctx context.Context, ownerID string) ([]entry.BlobRef, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"WITH deleted_entries AS (DELETE FROM entries WHERE user_id = $1 RETURNING user_id, key, version, blob_name), "+
+			"deleted_versions AS (DELETE FROM entry_versions WHERE user_id = $1 RETURNING user_id, key, version, blob_name), "+
+			"deleted_uploads AS (DELETE FROM upload_sessions WHERE user_id = $1 RETURNING user_id, key, expected_version, id), "+
+			"deleted_trash AS (DELETE FROM entry_trash WHERE user_id = $1 RETURNING user_id, key, version, blob_name), "+
+			"deleted_shares AS (DELETE FROM entry_shares WHERE owner_id = $1 OR recipient_id = $1 RETURNING owner_id, key, recipient_id), "+
+			"deleted_changes AS (DELETE FROM entry_changes WHERE user_id = $1) "+
+			"SELECT $2::text, user_id, key, version, blob_name, '', '' FROM deleted_entries "+
+			"UNION ALL SELECT $3::text, user_id, key, version, blob_name, '', '' FROM deleted_versions "+
+			"UNION ALL SELECT $4::text, user_id, key, expected_version, '', id::text, '' FROM deleted_uploads "+
+			"UNION ALL SELECT $5::text, user_id, key, version, blob_name, '', '' FROM deleted_trash "+
+			"UNION ALL SELECT $6::text, owner_id, key, 0, '', '', recipient_id::text FROM deleted_shares",
+		ownerID,
+		entry.BlobRefEntry,
+		entry.BlobRefVersion,
+		entry.BlobRefUpload,
+		entry.BlobRefTrash,
+		entry.BlobRefShare,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	refs := make([]entry.BlobRef, 0)
+	for rows.Next() {
+		var ref entry.BlobRef
+		err = rows.Scan(&ref.Kind, &ref.UserID, &ref.Key, &ref.Version, &ref.BlobName, &ref.UploadID, &ref.RecipientID)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return refs, nil
+}
+
+// GetUsage calculates the storage charged to the owner from the sizes of the entries, their previous versions,
+// the trash, the upload sessions and the copies shared. A user is charged for the own entries and for those of
+// every vault the user is an owner of, so creating vaults doesn't get around the limits. The usage of a vault
+// is the one of its most loaded owner, as a write to it counts against all of them. Only the usage is set, not the limits.
+func (d *DatabaseMetadataRepository) GetUsage(ctx context.Context, ownerID string) (entry.Usage, error) {
+	row := d.db.QueryRowContext(
+		ctx,
+		"WITH accounts AS ("+
+			"SELECT user_id AS id FROM vault_members WHERE vault_id = $1::uuid AND role = $2 "+
+			"UNION SELECT $1::uuid WHERE NOT EXISTS (SELECT 1 FROM vaults WHERE id = $1::uuid)), "+
+			"spaces AS ("+
+			"SELECT id AS account_id, id AS owner_id FROM accounts "+
+			"UNION SELECT a.id, m.vault_id FROM accounts a JOIN vault_members m ON m.user_id = a.id AND m.role = $2), "+
+			"usage AS (SELECT s.account_id, "+ownerUsage+" FROM spaces s) "+
+			"SELECT COALESCE(MAX(entries), 0)::bigint, COALESCE(MAX(bytes), 0)::bigint FROM "+
+			"(SELECT SUM(entries) AS entries, SUM(bytes) AS bytes FROM usage GROUP BY account_id) t",
+		ownerID,
+		d.ownerRole,
 	)
 
+	var usage entry.Usage
+	err := row.Scan(&usage.Entries, &usage.Bytes)
 	if err != nil {
+		return entry.Usage{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return usage, nil
+}
+
+// ListUsage calculates the storage charged to every user the same way as GetUsage, with a single query.
+// The usage is keyed by the ID of the user. Only the usage is set, not the limits.
+func (d *DatabaseMetadataRepository) ListUsage(ctx context.Context) (map[string]entry.Usage, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"WITH spaces AS ("+
+			"SELECT id AS account_id, id AS owner_id FROM users "+
+			"UNION SELECT user_id, vault_id FROM vault_members WHERE role = $1), "+
+			"usage AS (SELECT s.account_id, "+ownerUsage+" FROM spaces s) "+
+			"SELECT account_id, SUM(entries)::bigint, SUM(bytes)::bigint FROM usage GROUP BY account_id",
+		d.ownerRole,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	usages := make(map[string]entry.Usage)
+	for rows.Next() {
+		var userID string
+		var usage entry.Usage
+		err = rows.Scan(&userID, &usage.Entries, &usage.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		usages[userID] = usage
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return usages, nil
+}
+
+// ownerUsage selects the number of entries and the bytes stored under s.owner_id.
+const ownerUsage = "(SELECT COUNT(*) FROM entries WHERE user_id = s.owner_id) AS entries, " +
+	"(SELECT COALESCE(SUM(size), 0) FROM entries WHERE user_id = s.owner_id) + " +
+	"(SELECT COALESCE(SUM(size), 0) FROM entry_versions WHERE user_id = s.owner_id) + " +
+	"(SELECT COALESCE(SUM(size), 0) FROM entry_trash WHERE user_id = s.owner_id) + " +
+	"(SELECT COALESCE(SUM(size), 0) FROM upload_sessions WHERE user_id = s.owner_id) + " +
+	"(SELECT COALESCE(SUM(size), 0) FROM entry_shares WHERE owner_id = s.owner_id) AS bytes"
+
+// SetKeyPair stores the user's key pair. entry.ErrKeyPairExists is returned if the user already has one.
+func (d *DatabaseMetadataRepository) SetKeyPair(ctx context.Context, userID string, pair entry.KeyPair) error {
+	var id string
+	err := d.db.QueryRowContext(
+		ctx,
+		"INSERT INTO user_keys (user_id, public_key, encrypted_private_key) VALUES ($1, $2, $3) ON CONFLICT (user_id) DO NOTHING RETURNING user_id",
+		userID,
+		pair.PublicKey,
+		pair.EncryptedPrivateKey,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.ErrKeyPairExists
+		}
+
 		return fmt.Errorf("query error: %w", err)
 	}
 
 	return nil
 }
+
+// GetKeyPair retrieves the user's key pair.
+// It returns the key pair, a boolean indicating if the key pair was found, and an error if any occurred.
+func (d *DatabaseMetadataRepository) GetKeyPair(ctx context.Context, userID string) (entry.KeyPair, bool, error) {
+	pair := entry.KeyPair{UserID: userID}
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT public_key, encrypted_private_key FROM user_keys WHERE user_id = $1",
+		userID,
+	).Scan(&pair.PublicKey, &pair.EncryptedPrivateKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.KeyPair{}, false, nil
+		}
+
+		return entry.KeyPair{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return pair, true, nil
+}
+
+// FindPublicKey retrieves the ID and the public key of the user with the given login.
+// It returns them as a key pair without the private key, a boolean indicating if the key pair was found, and an error if any occurred.
+func (d *DatabaseMetadataRepository) FindPublicKey(ctx context.Context, login string) (entry.KeyPair, bool, error) {
+	var pair entry.KeyPair
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT k.user_id, k.public_key FROM user_keys k JOIN users u ON u.id = k.user_id WHERE u.login = $1",
+		login,
+	).Scan(&pair.UserID, &pair.PublicKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.KeyPair{}, false, nil
+		}
+
+		return entry.KeyPair{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return pair, true, nil
+}
+
+// shareSelect selects the columns scanned by scanShare, along with the logins of the owner and the recipient.
+const shareSelect = "SELECT s.id, s.owner_id, o.login, s.recipient_id, r.login, s.key, s.envelope, s.size, s.checksum, s.created_at " +
+	"FROM entry_shares s JOIN users o ON o.id = s.owner_id JOIN users r ON r.id = s.recipient_id"
+
+// SetShare stores the share, replacing the share of the same entry with the same recipient, which keeps its ID.
+// It returns the share with the ID and the creation time set.
+func (d *DatabaseMetadataRepository) SetShare(ctx context.Context, share entry.Share) (entry.Share, error) {
+	err := d.db.QueryRowContext(
+		ctx,
+		"INSERT INTO entry_shares (owner_id, recipient_id, key, envelope, size, checksum) VALUES ($1, $2, $3, $4, $5, $6) "+
+			"ON CONFLICT (owner_id, recipient_id, key) DO UPDATE SET envelope = EXCLUDED.envelope, size = EXCLUDED.size, checksum = EXCLUDED.checksum, created_at = now() "+
+			"RETURNING id, created_at",
+		share.OwnerID,
+		share.RecipientID,
+		share.Key,
+		share.Envelope,
+		share.Size,
+		share.Checksum,
+	).Scan(&share.ID, &share.CreatedAt)
+	if err != nil {
+		return entry.Share{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return share, nil
+}
+
+// GetShare retrieves a share by its ID.
+// It returns the share, a boolean indicating if the share was found, and an error if any occurred.
+func (d *DatabaseMetadataRepository) GetShare(ctx context.Context, shareID string) (entry.Share, bool, error) {
+	return getShare(ctx, d.db, shareSelect+" WHERE s.id = $1", shareID)
+}
+
+// FindShare retrieves the share of the owner's entry with the recipient with the given login.
+// It returns the share, a boolean indicating if the share was found, and an error if any occurred.
+func (d *DatabaseMetadataRepository) FindShare(ctx context.Context, ownerID string, recipientLogin string, key string) (entry.Share, bool, error) {
+	return getShare(ctx, d.db, shareSelect+" WHERE s.owner_id = $1 AND r.login = $2 AND s.key = $3", ownerID, recipientLogin, key)
+}
+
+// getShare retrieves a share with the query selecting shareSelect.
+func getShare(ctx context.Context, q queryRower, query string, args ...any) (entry.Share, bool, error) {
+	share, err := scanShare(q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry.Share{}, false, nil
+		}
+
+		return entry.Share{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return share, true, nil
+}
+
+// ListShares retrieves the shares of the user's entries, or the shares with the user if incoming is set,
+// most recently shared first.
+func (d *DatabaseMetadataRepository) ListShares(ctx context.Context, userID string, incoming bool) ([]entry.Share, error) {
+	column := "s.owner_id"
+	if incoming {
+		column = "s.recipient_id"
+	}
+
+	rows, err := d.db.QueryContext(ctx, shareSelect+" WHERE "+column+" = $1 ORDER BY s.created_at DESC, s.id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	shares := make([]entry.Share, 0)
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		shares = append(shares, share)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return shares, nil
+}
+
+// DeleteShare deletes the share of the owner's entry with the recipient.
+// It returns a boolean indicating if anything was deleted.
+func (d *DatabaseMetadataRepository) DeleteShare(ctx context.Context, ownerID string, recipientID string, key string) (bool, error) {
+	res, err := d.db.ExecContext(
+		ctx,
+		"DELETE FROM entry_shares WHERE owner_id = $1 AND recipient_id = $2 AND key = $3",
+		ownerID,
+		recipientID,
+		key,
+	)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanMetadata scans a row selected with metadataColumns into entry.Metadata.
+func scanMetadata(row scanner) (entry.Metadata, error) {
+	var md entry.Metadata
+	err := row.Scan(&md.Key, &md.Name, &md.Notes, &md.Type, &md.Size, &md.CreatedAt, &md.UpdatedAt, &md.Version, &md.Checksum, &md.Envelope, &md.BlobName)
+
+	return md, err
+}
+
+// scanBlobNames scans the blob names selected by rows and closes them.
+func scanBlobNames(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return names, nil
+}
+
+// scanShare scans a row selected with shareSelect into entry.Share.
+func scanShare(row scanner) (entry.Share, error) {
+	var share entry.Share
+	err := row.Scan(&share.ID, &share.OwnerID, &share.OwnerLogin, &share.RecipientID, &share.RecipientLogin, &share.Key, &share.Envelope, &share.Size, &share.Checksum, &share.CreatedAt)
+
+	return share, err
+}
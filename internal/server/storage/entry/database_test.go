@@ -2,8 +2,10 @@ package entry_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,13 @@ import (
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
+// ownerRole is the role of the vault owners passed to the repository.
+const ownerRole = "owner"
+
 func TestDatabaseMetadataRepository_Get(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
 	t.Run("success", func(t *testing.T) {
 		ctx, cancel := utils.TestContext(t)
 		defer cancel()
@@ -26,20 +34,29 @@ func TestDatabaseMetadataRepository_Get(t *testing.T) {
 		}()
 
 		mock.
-			ExpectQuery("SELECT key, name, notes FROM entries WHERE user_id = \\$1 AND key = \\$2").
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key = \\$2").
 			WithArgs("user", "key").
 			WillReturnRows(
-				sqlmock.NewRows([]string{"key", "name", "notes"}).AddRow("key", "name", []byte("notes")),
+				sqlmock.NewRows([]string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name"}).
+					AddRow("key", "name", []byte("notes"), "login", 10, createdAt, updatedAt, 3, []byte("checksum"), []byte("envelope"), "contents/1"),
 			)
 
-		repo := entry.NewDatabaseMetadataRepository(db)
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
 		md, ok, err := repo.GetMetadata(ctx, "user", "key")
 		require.NoError(t, err)
 		assert.True(t, ok)
 		assert.Equal(t, entryService.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
+			Key:       "key",
+			Name:      "name",
+			Notes:     []byte("notes"),
+			Type:      "login",
+			Size:      10,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+			Version:   3,
+			Checksum:  []byte("checksum"),
+			Envelope:  []byte("envelope"),
+			BlobName:  "contents/1",
 		}, md)
 	})
 
@@ -54,11 +71,11 @@ func TestDatabaseMetadataRepository_Get(t *testing.T) {
 		}()
 
 		mock.
-			ExpectQuery("SELECT key, name, notes FROM entries WHERE user_id = \\$1 AND key = \\$2").
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key = \\$2").
 			WithArgs("user", "key").
 			WillReturnError(sql.ErrNoRows)
 
-		repo := entry.NewDatabaseMetadataRepository(db)
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
 		md, ok, err := repo.GetMetadata(ctx, "user", "key")
 		require.NoError(t, err)
 		assert.False(t, ok)
@@ -76,11 +93,11 @@ func TestDatabaseMetadataRepository_Get(t *testing.T) {
 		}()
 
 		mock.
-			ExpectQuery("SELECT key, name, notes FROM entries WHERE user_id = \\$1 AND key = \\$2").
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key = \\$2").
 			WithArgs("user", "key").
 			WillReturnError(errors.New("query error"))
 
-		repo := entry.NewDatabaseMetadataRepository(db)
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
 		md, ok, err := repo.GetMetadata(ctx, "user", "key")
 		require.Error(t, err)
 		assert.False(t, ok)
@@ -92,7 +109,27 @@ func TestDatabaseMetadataRepository_Set(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
 
-	t.Run("success", func(t *testing.T) {
+	md := entryService.Metadata{
+		Key:      "key",
+		Name:     "name",
+		Notes:    []byte("notes"),
+		Type:     "login",
+		Size:     10,
+		Checksum: []byte("checksum"),
+		Envelope: []byte("envelope"),
+		BlobName: "contents/1",
+	}
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	changeColumns := []string{"id", "changed_at", "version", "created_at", "updated_at"}
+
+	insertQuery := "WITH e AS \\(INSERT INTO entries \\(user_id, key, name, notes, type, size, checksum, envelope, blob_name\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, \\$9\\) ON CONFLICT \\(user_id, key\\) DO NOTHING RETURNING user_id, key, version, created_at, updated_at\\), c AS \\(INSERT INTO entry_changes \\(user_id, key, version\\) SELECT user_id, key, version FROM e RETURNING id, changed_at\\) SELECT c\\.id, c\\.changed_at, e\\.version, e\\.created_at, e\\.updated_at FROM e, c"
+	updateQuery := "WITH p AS \\(SELECT user_id, key, .+ FROM entries WHERE user_id = \\$1 AND key = \\$2 AND version = \\$10\\), " +
+		"e AS \\(UPDATE entries SET name = \\$3, notes = \\$4, type = \\$5, size = \\$6, checksum = \\$7, envelope = \\$8, blob_name = \\$9, updated_at = now\\(\\), version = version \\+ 1 WHERE user_id = \\$1 AND key = \\$2 AND version = \\$10 RETURNING user_id, key, version, created_at, updated_at\\), " +
+		"v AS \\(INSERT INTO entry_versions \\(user_id, key, .+\\) SELECT p\\.user_id, .+, p\\.blob_name FROM p, e WHERE \\$11::boolean ON CONFLICT \\(user_id, key, version\\) DO UPDATE SET .+\\), c AS \\(INSERT INTO entry_changes \\(user_id, key, version\\) SELECT user_id, key, version FROM e RETURNING id, changed_at\\) SELECT c\\.id, c\\.changed_at, e\\.version, e\\.created_at, e\\.updated_at FROM e, c"
+
+	t.Run("insert", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
 		defer func() {
@@ -100,17 +137,139 @@ func TestDatabaseMetadataRepository_Set(t *testing.T) {
 		}()
 
 		mock.
-			ExpectExec("INSERT INTO entries \\(user_id, key, name, notes\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) ON CONFLICT \\(user_id, key\\) DO UPDATE SET name = excluded\\.name, notes = excluded\\.notes").
-			WithArgs("user", "key", "name", []byte("notes")).
-			WillReturnResult(sqlmock.NewResult(0, 1))
+			ExpectQuery(insertQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1").
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(5, createdAt, 1, createdAt, createdAt))
 
-		repo := entry.NewDatabaseMetadataRepository(db)
-		err = repo.SetMetadata(ctx, "user", entryService.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
-		})
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, err := repo.SetMetadata(ctx, "user", md, 0, false)
+		require.NoError(t, err)
+		assert.Equal(t, entryService.Change{
+			Cursor: 5,
+			Metadata: entryService.Metadata{
+				Key:       "key",
+				Name:      "name",
+				Notes:     []byte("notes"),
+				Type:      "login",
+				Size:      10,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Version:   1,
+				Checksum:  []byte("checksum"),
+				Envelope:  []byte("envelope"),
+				BlobName:  "contents/1",
+			},
+			ChangedAt: createdAt,
+		}, change)
+	})
+
+	t.Run("insert existing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(insertQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1").
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.SetMetadata(ctx, "user", md, 0, false)
+		require.ErrorIs(t, err, entryService.ErrConflict)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(updateQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1", int64(3), false).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(7, updatedAt, 4, createdAt, updatedAt))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, err := repo.SetMetadata(ctx, "user", md, 3, false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), change.Cursor)
+		assert.Equal(t, int64(4), change.Metadata.Version)
+		assert.Equal(t, createdAt, change.Metadata.CreatedAt)
+		assert.Equal(t, updatedAt, change.Metadata.UpdatedAt)
+		assert.False(t, change.Deleted)
+	})
+
+	t.Run("update version mismatch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(updateQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1", int64(3), false).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.SetMetadata(ctx, "user", md, 3, false)
+		require.ErrorIs(t, err, entryService.ErrConflict)
+	})
+
+	t.Run("update keeping version", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(updateQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1", int64(3), true).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(7, updatedAt, 4, createdAt, updatedAt))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, err := repo.SetMetadata(ctx, "user", md, 3, true)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), change.Metadata.Version)
+	})
+
+	t.Run("commit upload", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH e AS \\(INSERT INTO entries .+\\), u AS \\(DELETE FROM upload_sessions WHERE user_id = \\$1 AND id = \\$10 AND EXISTS \\(SELECT 1 FROM e\\)\\), c AS \\(INSERT INTO entry_changes").
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1", "upload").
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(5, createdAt, 1, createdAt, createdAt))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, err := repo.CommitUpload(ctx, "user", "upload", md, 0, false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), change.Metadata.Version)
+	})
+
+	t.Run("commit upload version mismatch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH p AS .+, u AS \\(DELETE FROM upload_sessions WHERE user_id = \\$1 AND id = \\$12 AND EXISTS \\(SELECT 1 FROM e\\)\\), c AS").
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1", int64(3), true, "upload").
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.CommitUpload(ctx, "user", "upload", md, 3, true)
+		require.ErrorIs(t, err, entryService.ErrConflict)
 	})
 
 	t.Run("query error", func(t *testing.T) {
@@ -121,17 +280,14 @@ func TestDatabaseMetadataRepository_Set(t *testing.T) {
 		}()
 
 		mock.
-			ExpectExec("INSERT INTO entries \\(user_id, key, name, notes\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) ON CONFLICT \\(user_id, key\\) DO UPDATE SET name = excluded\\.name, notes = excluded\\.notes").
-			WithArgs("user", "key", "name", []byte("notes")).
+			ExpectQuery(insertQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("checksum"), []byte("envelope"), "contents/1").
 			WillReturnError(errors.New("query error"))
 
-		repo := entry.NewDatabaseMetadataRepository(db)
-		err = repo.SetMetadata(ctx, "user", entryService.Metadata{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("notes"),
-		})
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.SetMetadata(ctx, "user", md, 0, false)
 		require.Error(t, err)
+		require.NotErrorIs(t, err, entryService.ErrConflict)
 	})
 }
 
@@ -139,6 +295,11 @@ func TestDatabaseMetadataRepository_Delete(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
 
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	changeColumns := []string{"id", "version", "changed_at", "blob_name"}
+
+	deleteQuery := "WITH e AS \\(DELETE FROM entries WHERE user_id = \\$1 AND key = \\$2 AND \\(\\$3 = 0 OR version = \\$3\\) RETURNING user_id, key, version, blob_name\\), c AS \\(INSERT INTO entry_changes \\(user_id, key, version, deleted\\) SELECT user_id, key, version, true FROM e RETURNING id, version, changed_at\\) SELECT c\\.id, c\\.version, c\\.changed_at, e\\.blob_name FROM c, e"
+
 	t.Run("success", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
@@ -147,13 +308,73 @@ func TestDatabaseMetadataRepository_Delete(t *testing.T) {
 		}()
 
 		mock.
-			ExpectExec("DELETE FROM entries WHERE user_id = \\$1 AND key = \\$2").
-			WithArgs("user", "key").
-			WillReturnResult(sqlmock.NewResult(0, 1))
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(0)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(8, 3, deletedAt, "contents/3"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, deleted, err := repo.DeleteMetadata(ctx, "user", "key", 0)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, entryService.Change{
+			Cursor:    8,
+			Metadata:  entryService.Metadata{Key: "key", Version: 3, BlobName: "contents/3"},
+			Deleted:   true,
+			ChangedAt: deletedAt,
+		}, change)
+	})
+
+	t.Run("nothing deleted without expected version", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(0)).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, deleted, err := repo.DeleteMetadata(ctx, "user", "key", 0)
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
 
-		repo := entry.NewDatabaseMetadataRepository(db)
-		err = repo.DeleteMetadata(ctx, "user", "key")
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(2)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(8, 2, deletedAt, "contents/2"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, deleted, err := repo.DeleteMetadata(ctx, "user", "key", 2)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(2)).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, _, err = repo.DeleteMetadata(ctx, "user", "key", 2)
+		require.ErrorIs(t, err, entryService.ErrConflict)
 	})
 
 	t.Run("query error", func(t *testing.T) {
@@ -164,13 +385,1966 @@ func TestDatabaseMetadataRepository_Delete(t *testing.T) {
 		}()
 
 		mock.
-			ExpectExec("DELETE FROM entries WHERE user_id = \\$1 AND key = \\$2").
-			WithArgs("user", "key").
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(0)).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, _, err = repo.DeleteMetadata(ctx, "user", "key", 0)
+		require.Error(t, err)
+	})
+
+}
+
+func TestDatabaseMetadataRepository_DeleteBatch(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	changeColumns := []string{"id", "version", "changed_at", "blob_name"}
+
+	deleteQuery := "WITH e AS \\(DELETE FROM entries WHERE user_id = \\$1 AND key = \\$2 AND \\(\\$3 = 0 OR version = \\$3\\) RETURNING user_id, key, version, blob_name\\), c AS \\(INSERT INTO entry_changes \\(user_id, key, version, deleted\\) SELECT user_id, key, version, true FROM e RETURNING id, version, changed_at\\) SELECT c\\.id, c\\.version, c\\.changed_at, e\\.blob_name FROM c, e"
+
+	items := []entryService.DeleteItem{
+		{Key: "first", ExpectedVersion: 3},
+		{Key: "missing"},
+		{Key: "second", ExpectedVersion: 1},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "first", int64(3)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(8, 3, deletedAt, "contents/3"))
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "missing", int64(0)).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "second", int64(1)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(9, 1, deletedAt, "contents/1"))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		changes, err := repo.DeleteMetadataBatch(ctx, "user", items)
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.Change{
+			{Cursor: 8, Metadata: entryService.Metadata{Key: "first", Version: 3, BlobName: "contents/3"}, Deleted: true, ChangedAt: deletedAt},
+			{Cursor: 9, Metadata: entryService.Metadata{Key: "second", Version: 1, BlobName: "contents/1"}, Deleted: true, ChangedAt: deletedAt},
+		}, changes)
+	})
+
+	t.Run("version mismatch rolls back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "first", int64(3)).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.DeleteMetadataBatch(ctx, "user", items)
+		require.ErrorIs(t, err, entryService.ErrConflict)
+	})
+
+	t.Run("query error rolls back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "first", int64(3)).
+			WillReturnError(errors.New("query error"))
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.DeleteMetadataBatch(ctx, "user", items)
+		require.Error(t, err)
+	})
+
+	t.Run("commit error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "first", int64(0)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(8, 3, deletedAt, "contents/3"))
+		mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.DeleteMetadataBatch(ctx, "user", []entryService.DeleteItem{{Key: "first"}})
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_Rename(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	deleteQuery := "WITH e AS \\(DELETE FROM entries WHERE user_id = \\$1 AND key = \\$2 AND version = \\$3 RETURNING user_id, key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name\\), c AS \\(INSERT INTO entry_changes \\(user_id, key, version, deleted\\) SELECT user_id, key, version, true FROM e RETURNING id, changed_at\\) SELECT e.notes, e.type, e.size, e.created_at, e.checksum, e.blob_name, c.id, c.changed_at FROM e, c"
+	deleteColumns := []string{"notes", "type", "size", "created_at", "checksum", "blob_name", "id", "changed_at"}
+	insertQuery := "WITH e AS \\(INSERT INTO entries \\(user_id, key, name, notes, type, size, checksum, envelope, created_at, version, blob_name\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, \\$9, \\$10, \\$11\\) ON CONFLICT \\(user_id, key\\) DO NOTHING RETURNING user_id, key, version, updated_at\\), c AS \\(INSERT INTO entry_changes \\(user_id, key, version\\) SELECT user_id, key, version FROM e RETURNING id, changed_at\\) SELECT c.id, c.changed_at, e.updated_at FROM e, c"
+	insertColumns := []string{"id", "changed_at", "updated_at"}
+	deleteVersionsQuery := "DELETE FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 RETURNING blob_name"
+	moveVersionsQuery := "UPDATE entry_versions SET key = \\$3, name = \\$4, envelope = \\$5, type = CASE WHEN \\$5::bytea IS NULL THEN type ELSE '' END " +
+		"WHERE user_id = \\$1 AND key = \\$2"
+
+	expectRenamed := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows(deleteColumns).AddRow([]byte("notes"), "login", 10, createdAt, []byte("sum"), "contents/1", 8, changedAt))
+		mock.
+			ExpectQuery(insertQuery).
+			WithArgs("user", "new", "new name", []byte("notes"), "login", int64(10), []byte("sum"), []byte(nil), createdAt, int64(4), "contents/1").
+			WillReturnRows(sqlmock.NewRows(insertColumns).AddRow(9, changedAt, changedAt))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		expectRenamed(mock)
+		mock.
+			ExpectQuery(deleteVersionsQuery).
+			WithArgs("user", "new").
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}).AddRow("contents/stale"))
+		mock.
+			ExpectExec(moveVersionsQuery).
+			WithArgs("user", "key", "new", "new name", []byte(nil)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		result, err := repo.RenameMetadata(ctx, "user", "key", "new", "new name", nil, 3)
+		require.NoError(t, err)
+		assert.Equal(t, entryService.RenameResult{
+			Deleted: entryService.Change{Cursor: 8, Metadata: entryService.Metadata{Key: "key", Version: 3}, Deleted: true, ChangedAt: changedAt},
+			Renamed: entryService.Change{
+				Cursor: 9,
+				Metadata: entryService.Metadata{
+					Key:       "new",
+					Name:      "new name",
+					Notes:     []byte("notes"),
+					Type:      "login",
+					Size:      10,
+					Checksum:  []byte("sum"),
+					CreatedAt: createdAt,
+					UpdatedAt: changedAt,
+					Version:   4,
+					BlobName:  "contents/1",
+				},
+				ChangedAt: changedAt,
+			},
+			StaleBlobs: []string{"contents/stale"},
+		}, result)
+	})
+
+	t.Run("envelope replaces plain type", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows(deleteColumns).AddRow([]byte("notes"), "login", 10, createdAt, []byte("sum"), "contents/1", 8, changedAt))
+		mock.
+			ExpectQuery(insertQuery).
+			WithArgs("user", "new", "", []byte("notes"), "", int64(10), []byte("sum"), []byte("envelope"), createdAt, int64(4), "contents/1").
+			WillReturnRows(sqlmock.NewRows(insertColumns).AddRow(9, changedAt, changedAt))
+		mock.
+			ExpectQuery(deleteVersionsQuery).
+			WithArgs("user", "new").
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}))
+		mock.
+			ExpectExec(moveVersionsQuery).
+			WithArgs("user", "key", "new", "", []byte("envelope")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		result, err := repo.RenameMetadata(ctx, "user", "key", "new", "", []byte("envelope"), 3)
+		require.NoError(t, err)
+		assert.Empty(t, result.Renamed.Metadata.Type)
+		assert.Equal(t, []byte("envelope"), result.Renamed.Metadata.Envelope)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows(deleteColumns))
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.RenameMetadata(ctx, "user", "key", "new", "new name", nil, 3)
+		require.ErrorIs(t, err, entryService.ErrConflict)
+	})
+
+	t.Run("new key taken", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows(deleteColumns).AddRow([]byte("notes"), "login", 10, createdAt, []byte("sum"), "contents/1", 8, changedAt))
+		mock.
+			ExpectQuery(insertQuery).
+			WithArgs("user", "new", "new name", []byte("notes"), "login", int64(10), []byte("sum"), []byte(nil), createdAt, int64(4), "contents/1").
+			WillReturnRows(sqlmock.NewRows(insertColumns))
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.RenameMetadata(ctx, "user", "key", "new", "new name", nil, 3)
+		require.ErrorIs(t, err, entryService.ErrEntryExists)
+	})
+
+	t.Run("move versions error rolls back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		expectRenamed(mock)
+		mock.
+			ExpectQuery(deleteVersionsQuery).
+			WithArgs("user", "new").
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}))
+		mock.
+			ExpectExec(moveVersionsQuery).
+			WithArgs("user", "key", "new", "new name", []byte(nil)).
 			WillReturnError(errors.New("query error"))
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.RenameMetadata(ctx, "user", "key", "new", "new name", nil, 3)
+		require.Error(t, err)
+	})
+
+	t.Run("commit error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		expectRenamed(mock)
+		mock.
+			ExpectQuery(deleteVersionsQuery).
+			WithArgs("user", "new").
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}))
+		mock.
+			ExpectExec(moveVersionsQuery).
+			WithArgs("user", "key", "new", "new name", []byte(nil)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 
-		repo := entry.NewDatabaseMetadataRepository(db)
-		err = repo.DeleteMetadata(ctx, "user", "key")
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.RenameMetadata(ctx, "user", "key", "new", "new name", nil, 3)
 		require.Error(t, err)
 	})
+}
+
+func TestDatabaseMetadataRepository_Trash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	changeColumns := []string{"id", "version", "changed_at", "blob_name"}
+
+	trashQuery := "WITH p AS \\(SELECT blob_name FROM entry_trash WHERE user_id = \\$1 AND key = \\$2\\), " +
+		"e AS \\(DELETE FROM entries WHERE user_id = \\$1 AND key = \\$2 AND \\(\\$3 = 0 OR version = \\$3\\) RETURNING user_id, key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name\\), " +
+		"t AS \\(INSERT INTO entry_trash \\(user_id, key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name\\) SELECT user_id, key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM e " +
+		"ON CONFLICT \\(user_id, key\\) DO UPDATE SET .+, blob_name = EXCLUDED\\.blob_name, deleted_at = now\\(\\)\\), " +
+		"c AS \\(INSERT INTO entry_changes \\(user_id, key, version, deleted\\) SELECT user_id, key, version, true FROM e RETURNING id, version, changed_at\\) " +
+		"SELECT c\\.id, c\\.version, c\\.changed_at, COALESCE\\(\\(SELECT blob_name FROM p\\), ''\\) FROM c, e"
 
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(trashQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(8, 3, deletedAt, "trash/key"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, deleted, err := repo.TrashMetadata(ctx, "user", "key", 3)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, entryService.Change{
+			Cursor:    8,
+			Metadata:  entryService.Metadata{Key: "key", Version: 3, BlobName: "trash/key"},
+			Deleted:   true,
+			ChangedAt: deletedAt,
+		}, change)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(trashQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, _, err = repo.TrashMetadata(ctx, "user", "key", 3)
+		require.ErrorIs(t, err, entryService.ErrConflict)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(trashQuery).
+			WithArgs("user", "first", int64(0)).
+			WillReturnRows(sqlmock.NewRows(changeColumns).AddRow(8, 3, deletedAt, "trash/key"))
+		mock.
+			ExpectQuery(trashQuery).
+			WithArgs("user", "missing", int64(0)).
+			WillReturnRows(sqlmock.NewRows(changeColumns))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		changes, err := repo.TrashMetadataBatch(ctx, "user", []entryService.DeleteItem{{Key: "first"}, {Key: "missing"}})
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.Change{
+			{Cursor: 8, Metadata: entryService.Metadata{Key: "first", Version: 3, BlobName: "trash/key"}, Deleted: true, ChangedAt: deletedAt},
+		}, changes)
+	})
+}
+
+func TestDatabaseMetadataRepository_ListTrash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name, deleted_at FROM entry_trash WHERE user_id = \\$1 ORDER BY deleted_at DESC, key").
+			WithArgs("user").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name", "deleted_at"}).
+					AddRow("key", "name", []byte("notes"), "login", 10, createdAt, createdAt, 2, []byte("sum"), nil, "trash/key", deletedAt),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		items, err := repo.ListTrash(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.TrashItem{
+			{
+				Metadata: entryService.Metadata{
+					Key:       "key",
+					Name:      "name",
+					Notes:     []byte("notes"),
+					Type:      "login",
+					Size:      10,
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+					Version:   2,
+					Checksum:  []byte("sum"),
+					BlobName:  "trash/key",
+				},
+				DeletedAt: deletedAt,
+			},
+		}, items)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_trash").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		items, err := repo.ListTrash(ctx, "user")
+		require.Error(t, err)
+		assert.Nil(t, items)
+	})
+}
+
+func TestDatabaseMetadataRepository_RestoreMetadata(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	deleteQuery := "DELETE FROM entry_trash WHERE user_id = \\$1 AND key = \\$2 RETURNING name, notes, type, size, created_at, version, checksum, envelope, blob_name"
+	deleteColumns := []string{"name", "notes", "type", "size", "created_at", "version", "checksum", "envelope", "blob_name"}
+	insertQuery := "WITH e AS \\(INSERT INTO entries .+ ON CONFLICT \\(user_id, key\\) DO NOTHING .+\\) SELECT c.id, c.changed_at, e.updated_at FROM e, c"
+	insertColumns := []string{"id", "changed_at", "updated_at"}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key").
+			WillReturnRows(sqlmock.NewRows(deleteColumns).AddRow("name", []byte("notes"), "login", 10, createdAt, 3, []byte("sum"), nil, "contents/1"))
+		mock.
+			ExpectQuery(insertQuery).
+			WithArgs("user", "key", "name", []byte("notes"), "login", int64(10), []byte("sum"), []byte(nil), createdAt, int64(4), "contents/1").
+			WillReturnRows(sqlmock.NewRows(insertColumns).AddRow(9, changedAt, changedAt))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		change, err := repo.RestoreMetadata(ctx, "user", "key")
+		require.NoError(t, err)
+		assert.Equal(t, entryService.Change{
+			Cursor: 9,
+			Metadata: entryService.Metadata{
+				Key:       "key",
+				Name:      "name",
+				Notes:     []byte("notes"),
+				Type:      "login",
+				Size:      10,
+				Checksum:  []byte("sum"),
+				CreatedAt: createdAt,
+				UpdatedAt: changedAt,
+				Version:   4,
+				BlobName:  "contents/1",
+			},
+			ChangedAt: changedAt,
+		}, change)
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key").
+			WillReturnRows(sqlmock.NewRows(deleteColumns))
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.RestoreMetadata(ctx, "user", "key")
+		require.ErrorIs(t, err, entryService.ErrEntryNotFound)
+	})
+
+	t.Run("entry exists", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("user", "key").
+			WillReturnRows(sqlmock.NewRows(deleteColumns).AddRow("name", []byte("notes"), "login", 10, createdAt, 3, []byte("sum"), nil, "contents/1"))
+		mock.
+			ExpectQuery(insertQuery).
+			WillReturnRows(sqlmock.NewRows(insertColumns))
+		// the entry is kept in the trash
+		mock.ExpectRollback()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.RestoreMetadata(ctx, "user", "key")
+		require.ErrorIs(t, err, entryService.ErrEntryExists)
+	})
+}
+
+func TestDatabaseMetadataRepository_PurgeTrash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	purgeQuery := "WITH t AS \\(DELETE FROM entry_trash WHERE user_id = \\$1 AND key = \\$2 AND \\(\\$3 = 0 OR version = \\$3\\) RETURNING blob_name\\), " +
+		"v AS \\(DELETE FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 AND EXISTS \\(SELECT 1 FROM t\\) " +
+		"AND NOT EXISTS \\(SELECT 1 FROM entries WHERE user_id = \\$1 AND key = \\$2\\) RETURNING blob_name\\) " +
+		"SELECT blob_name FROM t UNION ALL SELECT blob_name FROM v"
+
+	t.Run("deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(purgeQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}).AddRow("contents/3").AddRow("contents/2"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		blobNames, deleted, err := repo.PurgeTrash(ctx, "user", "key", 3)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, []string{"contents/3", "contents/2"}, blobNames)
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(purgeQuery).
+			WithArgs("user", "key", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		blobNames, deleted, err := repo.PurgeTrash(ctx, "user", "key", 3)
+		require.NoError(t, err)
+		assert.False(t, deleted)
+		assert.Empty(t, blobNames)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		before := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		mock.
+			ExpectQuery("WITH t AS \\(DELETE FROM entry_trash WHERE deleted_at < \\$1 RETURNING user_id, key, version, blob_name\\), " +
+				"v AS \\(DELETE FROM entry_versions ev USING t WHERE ev.user_id = t.user_id AND ev.key = t.key .+\\) " +
+				"SELECT \\$2::text, user_id, key, version, blob_name FROM t UNION ALL SELECT \\$3::text, user_id, key, version, blob_name FROM v").
+			WithArgs(before, entryService.BlobRefTrash, entryService.BlobRefVersion).
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "user_id", "key", "version", "blob_name"}).
+				AddRow("trash", "user", "key", 3, "contents/3").
+				AddRow("version", "user", "key", 2, "contents/2"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		refs, err := repo.PurgeExpiredTrash(ctx, before)
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.BlobRef{
+			{Kind: entryService.BlobRefTrash, UserID: "user", Key: "key", Version: 3, BlobName: "contents/3"},
+			{Kind: entryService.BlobRefVersion, UserID: "user", Key: "key", Version: 2, BlobName: "contents/2"},
+		}, refs)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetBatch(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name"}
+	getQuery := "SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key = \\$2"
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(getQuery).
+			WithArgs("user", "second").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("second", "name 2", nil, "text", 5, createdAt, createdAt, 2, nil, nil, "contents/2"))
+		mock.
+			ExpectQuery(getQuery).
+			WithArgs("user", "missing").
+			WillReturnError(sql.ErrNoRows)
+		mock.
+			ExpectQuery(getQuery).
+			WithArgs("user", "first").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("first", "name 1", nil, "login", 10, createdAt, createdAt, 1, nil, nil, "contents/1"))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		mds, err := repo.GetMetadataBatch(ctx, "user", []string{"second", "missing", "first"})
+		require.NoError(t, err)
+		require.Len(t, mds, 2)
+		assert.Equal(t, "second", mds[0].Key)
+		assert.Equal(t, "first", mds[1].Key)
+		assert.Equal(t, int64(10), mds[1].Size)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(getQuery).
+			WithArgs("user", "first").
+			WillReturnError(errors.New("query error"))
+		mock.ExpectCommit()
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.GetMetadataBatch(ctx, "user", []string{"first"})
+		require.Error(t, err)
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.GetMetadataBatch(ctx, "user", []string{"first"})
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_List(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key > \\$2 ORDER BY key LIMIT \\$3").
+			WithArgs("user", "after", 2).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name"}).
+					AddRow("key1", "name1", []byte("notes"), "login", 10, createdAt, createdAt, 2, nil, nil, "contents/1").
+					AddRow("key2", "name2", nil, "", 0, createdAt, createdAt, 1, nil, nil, "contents/2"),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		mds, err := repo.ListMetadata(ctx, "user", entryService.ListOptions{After: "after", Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.Metadata{
+			{Key: "key1", Name: "name1", Notes: []byte("notes"), Type: "login", Size: 10, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 2, BlobName: "contents/1"},
+			{Key: "key2", Name: "name2", CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1, BlobName: "contents/2"},
+		}, mds)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key > \\$2 ORDER BY key LIMIT \\$3").
+			WithArgs("user", "", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name"}))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		mds, err := repo.ListMetadata(ctx, "user", entryService.ListOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, mds)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entries WHERE user_id = \\$1 AND key > \\$2 ORDER BY key LIMIT \\$3").
+			WithArgs("user", "", 10).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		mds, err := repo.ListMetadata(ctx, "user", entryService.ListOptions{Limit: 10})
+		require.Error(t, err)
+		assert.Nil(t, mds)
+	})
+}
+
+func TestDatabaseMetadataRepository_ListChanges(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT c\\.id, c\\.key, c\\.version, c\\.deleted, c\\.changed_at, e\\.name, e\\.notes, e\\.type, e\\.size, e\\.created_at, e\\.updated_at, e\\.checksum, e\\.envelope FROM entry_changes c LEFT JOIN entries e .+ WHERE c\\.user_id = \\$1 AND c\\.id > \\$2 AND NOT EXISTS .+ ORDER BY c\\.id LIMIT \\$3").
+			WithArgs("user", int64(10), 2).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "key", "version", "deleted", "changed_at", "name", "notes", "type", "size", "created_at", "updated_at", "checksum", "envelope"}).
+					AddRow(11, "key1", 3, false, changedAt, "name", []byte("notes"), "login", 10, changedAt, changedAt, []byte("checksum"), nil).
+					AddRow(13, "key2", 1, true, changedAt, nil, nil, nil, nil, nil, nil, nil, nil),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		changes, err := repo.ListChanges(ctx, "user", 10, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.Change{
+			{
+				Cursor: 11,
+				Metadata: entryService.Metadata{
+					Key:       "key1",
+					Name:      "name",
+					Notes:     []byte("notes"),
+					Type:      "login",
+					Size:      10,
+					CreatedAt: changedAt,
+					UpdatedAt: changedAt,
+					Version:   3,
+					Checksum:  []byte("checksum"),
+				},
+				ChangedAt: changedAt,
+			},
+			{
+				Cursor:    13,
+				Metadata:  entryService.Metadata{Key: "key2", Version: 1},
+				Deleted:   true,
+				ChangedAt: changedAt,
+			},
+		}, changes)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_changes").
+			WithArgs("user", int64(0), 2).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		changes, err := repo.ListChanges(ctx, "user", 0, 2)
+		require.Error(t, err)
+		require.Nil(t, changes)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetLatestCursor(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT COALESCE\\(MAX\\(id\\), 0\\) FROM entry_changes WHERE user_id = \\$1").
+			WithArgs("user").
+			WillReturnRows(sqlmock.NewRows([]string{"cursor"}).AddRow(int64(7)))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		cursor, err := repo.GetLatestCursor(ctx, "user")
+		require.NoError(t, err)
+		require.Equal(t, int64(7), cursor)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT COALESCE").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.GetLatestCursor(ctx, "user")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 AND version = \\$3").
+			WithArgs("user", "key", int64(2)).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name"}).
+					AddRow("key", "name", nil, "text", 5, createdAt, createdAt, 2, nil, nil, "contents/2"),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		md, ok, err := repo.GetVersion(ctx, "user", "key", 2)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, entryService.Metadata{
+			Key:       "key",
+			Name:      "name",
+			Type:      "text",
+			Size:      5,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Version:   2,
+			BlobName:  "contents/2",
+		}, md)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_versions").
+			WithArgs("user", "key", int64(2)).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.GetVersion(ctx, "user", "key", 2)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_versions").
+			WithArgs("user", "key", int64(2)).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.GetVersion(ctx, "user", "key", 2)
+		require.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDatabaseMetadataRepository_ListVersions(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT key, name, notes, type, size, created_at, updated_at, version, checksum, envelope, blob_name FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 ORDER BY version DESC").
+			WithArgs("user", "key").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "name", "notes", "type", "size", "created_at", "updated_at", "version", "checksum", "envelope", "blob_name"}).
+					AddRow("key", "name", nil, "text", 5, createdAt, createdAt, 2, nil, nil, "contents/2").
+					AddRow("key", "name", nil, "text", 3, createdAt, createdAt, 1, nil, nil, "contents/1"),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		mds, err := repo.ListVersions(ctx, "user", "key")
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.Metadata{
+			{Key: "key", Name: "name", Type: "text", Size: 5, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 2, BlobName: "contents/2"},
+			{Key: "key", Name: "name", Type: "text", Size: 3, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1, BlobName: "contents/1"},
+		}, mds)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_versions").
+			WithArgs("user", "key").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		mds, err := repo.ListVersions(ctx, "user", "key")
+		require.Error(t, err)
+		assert.Nil(t, mds)
+	})
+}
+
+func TestDatabaseMetadataRepository_PruneVersions(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("DELETE FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 AND version NOT IN \\(SELECT version FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 ORDER BY version DESC LIMIT \\$3\\) RETURNING blob_name").
+			WithArgs("user", "key", 2).
+			WillReturnRows(sqlmock.NewRows([]string{"blob_name"}).AddRow("contents/2").AddRow("contents/1"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		blobNames, err := repo.PruneVersions(ctx, "user", "key", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contents/2", "contents/1"}, blobNames)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("DELETE FROM entry_versions").
+			WithArgs("user", "key", 0).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		blobNames, err := repo.PruneVersions(ctx, "user", "key", 0)
+		require.Error(t, err)
+		assert.Nil(t, blobNames)
+	})
+}
+
+func TestDatabaseMetadataRepository_DeleteVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM entry_versions WHERE user_id = \\$1 AND key = \\$2 AND version = \\$3").
+			WithArgs("user", "key", int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.DeleteVersion(ctx, "user", "key", 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM entry_versions").
+			WithArgs("user", "key", int64(2)).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.DeleteVersion(ctx, "user", "key", 2)
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_CreateUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	session := entryService.UploadSession{
+		Metadata:        entryService.Metadata{Key: "key", Name: "name", Notes: []byte("notes"), Type: "file"},
+		Size:            100,
+		ExpectedVersion: 2,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO upload_sessions \\(user_id, key, name, notes, type, envelope, size, expected_version\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8\\) RETURNING id, created_at").
+			WithArgs("user", "key", "name", []byte("notes"), "file", []byte(nil), int64(100), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("upload", createdAt))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		created, err := repo.CreateUploadSession(ctx, "user", session)
+		require.NoError(t, err)
+
+		expected := session
+		expected.ID = "upload"
+		expected.CreatedAt = createdAt
+		assert.Equal(t, expected, created)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO upload_sessions").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.CreateUploadSession(ctx, "user", session)
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT id, key, name, notes, type, envelope, size, expected_version, created_at FROM upload_sessions WHERE user_id = \\$1 AND id = \\$2").
+			WithArgs("user", "upload").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "key", "name", "notes", "type", "envelope", "size", "expected_version", "created_at"}).
+					AddRow("upload", "key", "name", nil, "file", nil, 100, 0, createdAt),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		session, ok, err := repo.GetUploadSession(ctx, "user", "upload")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, entryService.UploadSession{
+			ID:        "upload",
+			Metadata:  entryService.Metadata{Key: "key", Name: "name", Type: "file"},
+			Size:      100,
+			CreatedAt: createdAt,
+		}, session)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM upload_sessions").
+			WithArgs("user", "upload").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.GetUploadSession(ctx, "user", "upload")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM upload_sessions").
+			WithArgs("user", "upload").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.GetUploadSession(ctx, "user", "upload")
+		require.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDatabaseMetadataRepository_DeleteUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM upload_sessions WHERE user_id = \\$1 AND id = \\$2").
+			WithArgs("user", "upload").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.DeleteUploadSession(ctx, "user", "upload")
+		require.NoError(t, err)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM upload_sessions").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.DeleteUploadSession(ctx, "user", "upload")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_LeaseUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	query := "UPDATE upload_sessions SET lease_token = \\$3, leased_until = now\\(\\) \\+ make_interval\\(secs => \\$4\\) " +
+		"WHERE user_id = \\$1 AND id = \\$2 AND \\(lease_token IS NULL OR lease_token = \\$3 OR leased_until < now\\(\\)\\)"
+
+	t.Run("leased", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec(query).
+			WithArgs("user", "upload", "token", float64(60)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		ok, err := repo.LeaseUploadSession(ctx, "user", "upload", "token", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("leased by another holder", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec(query).
+			WithArgs("user", "upload", "token", float64(60)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		ok, err := repo.LeaseUploadSession(ctx, "user", "upload", "token", time.Minute)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("UPDATE upload_sessions").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.LeaseUploadSession(ctx, "user", "upload", "token", time.Minute)
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_ReleaseUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("UPDATE upload_sessions SET lease_token = NULL, leased_until = NULL WHERE user_id = \\$1 AND id = \\$2 AND lease_token = \\$3").
+			WithArgs("user", "upload", "token").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.ReleaseUploadSession(ctx, "user", "upload", "token")
+		require.NoError(t, err)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("UPDATE upload_sessions").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.ReleaseUploadSession(ctx, "user", "upload", "token")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_ListBlobRefs(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT \\$1::text, user_id, key, version, blob_name, '', '' FROM entries " +
+				"UNION ALL SELECT \\$2::text, user_id, key, version, blob_name, '', '' FROM entry_versions " +
+				"UNION ALL SELECT \\$3::text, user_id, key, expected_version, '', id::text, '' FROM upload_sessions " +
+				"UNION ALL SELECT \\$4::text, user_id, key, version, blob_name, '', '' FROM entry_trash " +
+				"UNION ALL SELECT \\$5::text, owner_id, key, 0, '', '', recipient_id::text FROM entry_shares").
+			WithArgs("entry", "version", "upload", "trash", "share").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"kind", "user_id", "key", "version", "blob_name", "upload_id", "recipient_id"}).
+					AddRow("entry", "user", "key", 3, "contents/3", "", "").
+					AddRow("version", "user", "key", 2, "contents/2", "", "").
+					AddRow("upload", "user", "key", 3, "", "upload", "").
+					AddRow("trash", "user", "deleted", 5, "contents/5", "", "").
+					AddRow("share", "user", "key", 0, "", "", "recipient"),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		refs, err := repo.ListBlobRefs(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.BlobRef{
+			{Kind: entryService.BlobRefEntry, UserID: "user", Key: "key", Version: 3, BlobName: "contents/3"},
+			{Kind: entryService.BlobRefVersion, UserID: "user", Key: "key", Version: 2, BlobName: "contents/2"},
+			{Kind: entryService.BlobRefUpload, UserID: "user", Key: "key", Version: 3, UploadID: "upload"},
+			{Kind: entryService.BlobRefTrash, UserID: "user", Key: "deleted", Version: 5, BlobName: "contents/5"},
+			{Kind: entryService.BlobRefShare, UserID: "user", Key: "key", RecipientID: "recipient"},
+		}, refs)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entries").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		refs, err := repo.ListBlobRefs(ctx)
+		require.Error(t, err)
+		assert.Nil(t, refs)
+	})
+}
+
+func TestDatabaseMetadataRepository_DeleteOwnerMetadata(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH deleted_entries AS \\(DELETE FROM entries WHERE user_id = \\$1 RETURNING user_id, key, version, blob_name\\), " +
+				"deleted_versions AS \\(DELETE FROM entry_versions WHERE user_id = \\$1 RETURNING user_id, key, version, blob_name\\), " +
+				"deleted_uploads AS \\(DELETE FROM upload_sessions WHERE user_id = \\$1 RETURNING user_id, key, expected_version, id\\), " +
+				"deleted_trash AS \\(DELETE FROM entry_trash WHERE user_id = \\$1 RETURNING user_id, key, version, blob_name\\), " +
+				"deleted_shares AS \\(DELETE FROM entry_shares WHERE owner_id = \\$1 OR recipient_id = \\$1 RETURNING owner_id, key, recipient_id\\), " +
+				"deleted_changes AS \\(DELETE FROM entry_changes WHERE user_id = \\$1\\) " +
+				"SELECT \\$2::text, user_id, key, version, blob_name, '', '' FROM deleted_entries " +
+				"UNION ALL SELECT \\$3::text, user_id, key, version, blob_name, '', '' FROM deleted_versions " +
+				"UNION ALL SELECT \\$4::text, user_id, key, expected_version, '', id::text, '' FROM deleted_uploads " +
+				"UNION ALL SELECT \\$5::text, user_id, key, version, blob_name, '', '' FROM deleted_trash " +
+				"UNION ALL SELECT \\$6::text, owner_id, key, 0, '', '', recipient_id::text FROM deleted_shares").
+			WithArgs("user", "entry", "version", "upload", "trash", "share").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"kind", "user_id", "key", "version", "blob_name", "upload_id", "recipient_id"}).
+					AddRow("entry", "user", "key", 3, "contents/3", "", "").
+					AddRow("share", "other", "key", 0, "", "", "user"),
+			)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		refs, err := repo.DeleteOwnerMetadata(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, []entryService.BlobRef{
+			{Kind: entryService.BlobRefEntry, UserID: "user", Key: "key", Version: 3, BlobName: "contents/3"},
+			{Kind: entryService.BlobRefShare, UserID: "other", Key: "key", RecipientID: "user"},
+		}, refs)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH deleted_entries AS .+").
+			WithArgs("user", "entry", "version", "upload", "trash", "share").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		refs, err := repo.DeleteOwnerMetadata(ctx, "user")
+		require.Error(t, err)
+		assert.Nil(t, refs)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetUsage(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH accounts AS \\(" +
+				"SELECT user_id AS id FROM vault_members WHERE vault_id = \\$1::uuid AND role = \\$2 " +
+				"UNION SELECT \\$1::uuid WHERE NOT EXISTS \\(SELECT 1 FROM vaults WHERE id = \\$1::uuid\\)\\), " +
+				"spaces AS \\(" +
+				"SELECT id AS account_id, id AS owner_id FROM accounts " +
+				"UNION SELECT a.id, m.vault_id FROM accounts a JOIN vault_members m ON m.user_id = a.id AND m.role = \\$2\\), " +
+				"usage AS \\(SELECT s.account_id, " +
+				"\\(SELECT COUNT\\(\\*\\) FROM entries WHERE user_id = s.owner_id\\) AS entries, " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entries WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entry_versions WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entry_trash WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM upload_sessions WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entry_shares WHERE owner_id = s.owner_id\\) AS bytes " +
+				"FROM spaces s\\) " +
+				"SELECT COALESCE\\(MAX\\(entries\\), 0\\)::bigint, COALESCE\\(MAX\\(bytes\\), 0\\)::bigint FROM " +
+				"\\(SELECT SUM\\(entries\\) AS entries, SUM\\(bytes\\) AS bytes FROM usage GROUP BY account_id\\) t").
+			WithArgs("user", ownerRole).
+			WillReturnRows(sqlmock.NewRows([]string{"entries", "bytes"}).AddRow(3, 100))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		usage, err := repo.GetUsage(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, entryService.Usage{Bytes: 100, Entries: 3}, usage)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH accounts AS .+ FROM entries").
+			WithArgs("user", ownerRole).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.GetUsage(ctx, "user")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_ListUsage(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH spaces AS \\(" +
+				"SELECT id AS account_id, id AS owner_id FROM users " +
+				"UNION SELECT user_id, vault_id FROM vault_members WHERE role = \\$1\\), " +
+				"usage AS \\(SELECT s.account_id, " +
+				"\\(SELECT COUNT\\(\\*\\) FROM entries WHERE user_id = s.owner_id\\) AS entries, " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entries WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entry_versions WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entry_trash WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM upload_sessions WHERE user_id = s.owner_id\\) \\+ " +
+				"\\(SELECT COALESCE\\(SUM\\(size\\), 0\\) FROM entry_shares WHERE owner_id = s.owner_id\\) AS bytes " +
+				"FROM spaces s\\) " +
+				"SELECT account_id, SUM\\(entries\\)::bigint, SUM\\(bytes\\)::bigint FROM usage GROUP BY account_id").
+			WithArgs(ownerRole).
+			WillReturnRows(sqlmock.NewRows([]string{"account_id", "entries", "bytes"}).
+				AddRow("first", 3, 100).
+				AddRow("second", 0, 0))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		usages, err := repo.ListUsage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]entryService.Usage{
+			"first":  {Bytes: 100, Entries: 3},
+			"second": {},
+		}, usages)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH spaces AS .+ FROM entries").
+			WithArgs(ownerRole).
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.ListUsage(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_SetKeyPair(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	pair := entryService.KeyPair{PublicKey: "public", EncryptedPrivateKey: []byte("private")}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO user_keys \\(user_id, public_key, encrypted_private_key\\) VALUES \\(\\$1, \\$2, \\$3\\) ON CONFLICT \\(user_id\\) DO NOTHING RETURNING user_id").
+			WithArgs("user", "public", []byte("private")).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.SetKeyPair(ctx, "user", pair)
+		require.NoError(t, err)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO user_keys").
+			WithArgs("user", "public", []byte("private")).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.SetKeyPair(ctx, "user", pair)
+		require.ErrorIs(t, err, entryService.ErrKeyPairExists)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO user_keys").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		err = repo.SetKeyPair(ctx, "user", pair)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, entryService.ErrKeyPairExists)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetKeyPair(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT public_key, encrypted_private_key FROM user_keys WHERE user_id = \\$1").
+			WithArgs("user").
+			WillReturnRows(sqlmock.NewRows([]string{"public_key", "encrypted_private_key"}).AddRow("public", []byte("private")))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		pair, ok, err := repo.GetKeyPair(ctx, "user")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, entryService.KeyPair{UserID: "user", PublicKey: "public", EncryptedPrivateKey: []byte("private")}, pair)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM user_keys").
+			WithArgs("user").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.GetKeyPair(ctx, "user")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDatabaseMetadataRepository_FindPublicKey(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT k.user_id, k.public_key FROM user_keys k JOIN users u ON u.id = k.user_id WHERE u.login = \\$1").
+			WithArgs("login").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "public_key"}).AddRow("user", "public"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		pair, ok, err := repo.FindPublicKey(ctx, "login")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, entryService.KeyPair{UserID: "user", PublicKey: "public"}, pair)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM user_keys").
+			WithArgs("login").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.FindPublicKey(ctx, "login")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+// shareQuery is the escaped select of a share along with the logins.
+const shareQuery = "SELECT s.id, s.owner_id, o.login, s.recipient_id, r.login, s.key, s.envelope, s.size, s.checksum, s.created_at " +
+	"FROM entry_shares s JOIN users o ON o.id = s.owner_id JOIN users r ON r.id = s.recipient_id"
+
+var shareColumns = []string{"id", "owner_id", "owner_login", "recipient_id", "recipient_login", "key", "envelope", "size", "checksum", "created_at"}
+
+func TestDatabaseMetadataRepository_SetShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	share := entryService.Share{
+		OwnerID:        "owner",
+		RecipientID:    "recipient",
+		RecipientLogin: "bob",
+		Key:            "key",
+		Envelope:       []byte("envelope"),
+		Size:           10,
+		Checksum:       []byte("checksum"),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO entry_shares \\(owner_id, recipient_id, key, envelope, size, checksum\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\) " +
+				"ON CONFLICT \\(owner_id, recipient_id, key\\) DO UPDATE SET envelope = EXCLUDED.envelope, size = EXCLUDED.size, checksum = EXCLUDED.checksum, created_at = now\\(\\) " +
+				"RETURNING id, created_at").
+			WithArgs("owner", "recipient", "key", []byte("envelope"), int64(10), []byte("checksum")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("share", createdAt))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		stored, err := repo.SetShare(ctx, share)
+		require.NoError(t, err)
+
+		expected := share
+		expected.ID = "share"
+		expected.CreatedAt = createdAt
+		assert.Equal(t, expected, stored)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO entry_shares").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.SetShare(ctx, share)
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_GetShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expected := entryService.Share{
+		ID:             "share",
+		OwnerID:        "owner",
+		OwnerLogin:     "alice",
+		RecipientID:    "recipient",
+		RecipientLogin: "bob",
+		Key:            "key",
+		Envelope:       []byte("envelope"),
+		Size:           10,
+		Checksum:       []byte("checksum"),
+		CreatedAt:      createdAt,
+	}
+	row := []driver.Value{"share", "owner", "alice", "recipient", "bob", "key", []byte("envelope"), 10, []byte("checksum"), createdAt}
+
+	t.Run("by id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(shareQuery + " WHERE s.id = \\$1").
+			WithArgs("share").
+			WillReturnRows(sqlmock.NewRows(shareColumns).AddRow(row...))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		share, ok, err := repo.GetShare(ctx, "share")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected, share)
+	})
+
+	t.Run("by recipient", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(shareQuery+" WHERE s.owner_id = \\$1 AND r.login = \\$2 AND s.key = \\$3").
+			WithArgs("owner", "bob", "key").
+			WillReturnRows(sqlmock.NewRows(shareColumns).AddRow(row...))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		share, ok, err := repo.FindShare(ctx, "owner", "bob", "key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected, share)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_shares").
+			WithArgs("share").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, ok, err := repo.GetShare(ctx, "share")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_shares").
+			WithArgs("share").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, _, err = repo.GetShare(ctx, "share")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseMetadataRepository_ListShares(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		incoming bool
+		column   string
+	}{
+		{name: "outgoing", incoming: false, column: "s.owner_id"},
+		{name: "incoming", incoming: true, column: "s.recipient_id"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}()
+
+			mock.
+				ExpectQuery(shareQuery+" WHERE "+tc.column+" = \\$1 ORDER BY s.created_at DESC, s.id").
+				WithArgs("user").
+				WillReturnRows(
+					sqlmock.NewRows(shareColumns).
+						AddRow("share", "user", "alice", "recipient", "bob", "key", []byte("envelope"), 10, nil, createdAt),
+				)
+
+			repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+			shares, err := repo.ListShares(ctx, "user", tc.incoming)
+			require.NoError(t, err)
+			assert.Equal(t, []entryService.Share{{
+				ID:             "share",
+				OwnerID:        "user",
+				OwnerLogin:     "alice",
+				RecipientID:    "recipient",
+				RecipientLogin: "bob",
+				Key:            "key",
+				Envelope:       []byte("envelope"),
+				Size:           10,
+				CreatedAt:      createdAt,
+			}}, shares)
+		})
+	}
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM entry_shares").
+			WithArgs("user").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		shares, err := repo.ListShares(ctx, "user", false)
+		require.Error(t, err)
+		assert.Nil(t, shares)
+	})
+}
+
+func TestDatabaseMetadataRepository_DeleteShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	for _, tc := range []struct {
+		name     string
+		affected int64
+	}{
+		{name: "deleted", affected: 1},
+		{name: "not found", affected: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}()
+
+			mock.
+				ExpectExec("DELETE FROM entry_shares WHERE owner_id = \\$1 AND recipient_id = \\$2 AND key = \\$3").
+				WithArgs("owner", "recipient", "key").
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+			deleted, err := repo.DeleteShare(ctx, "owner", "recipient", "key")
+			require.NoError(t, err)
+			assert.Equal(t, tc.affected > 0, deleted)
+		})
+	}
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM entry_shares").
+			WillReturnError(errors.New("query error"))
+
+		repo := entry.NewDatabaseMetadataRepository(db, ownerRole)
+		_, err = repo.DeleteShare(ctx, "owner", "recipient", "key")
+		require.Error(t, err)
+	})
 }
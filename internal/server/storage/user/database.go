@@ -6,13 +6,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 
 	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
 )
 
+// userColumns lists the columns scanned by scanUser, in order.
+const userColumns = "id, login, password_hash, disabled, sessions_reset_at, created_at"
+
 type dbRepo struct {
 	db *sql.DB
 }
@@ -41,10 +46,17 @@ func (d *dbRepo) AddUser(ctx context.Context, login string, passwordHash string)
 // FindUser retrieves a user's information from the database by their login.
 // Returns the user's information, a boolean indicating if the user was found, and an error if the operation fails.
 func (d *dbRepo) FindUser(ctx context.Context, login string) (user.UserInfo, bool, error) {
-	row := d.db.QueryRowContext(ctx, "SELECT id, password_hash FROM users WHERE login = $1", login)
+	return d.getUser(ctx, "SELECT "+userColumns+" FROM users WHERE login = $1", login)
+}
+
+// GetUser retrieves a user's information from the database by their ID.
+// Returns the user's information, a boolean indicating if the user was found, and an error if the operation fails.
+func (d *dbRepo) GetUser(ctx context.Context, userID string) (user.UserInfo, bool, error) {
+	return d.getUser(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", userID)
+}
 
-	info := user.UserInfo{}
-	err := row.Scan(&info.ID, &info.PasswordHash)
+func (d *dbRepo) getUser(ctx context.Context, query string, args ...any) (user.UserInfo, bool, error) {
+	info, err := scanUser(d.db.QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return user.UserInfo{}, false, nil
@@ -56,6 +68,157 @@ func (d *dbRepo) FindUser(ctx context.Context, login string) (user.UserInfo, boo
 	return info, true, nil
 }
 
+// ListUsers retrieves all the users sorted by login. Password hashes aren't selected.
+func (d *dbRepo) ListUsers(ctx context.Context) ([]user.UserInfo, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT id, login, '', disabled, sessions_reset_at, created_at FROM users ORDER BY login")
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]user.UserInfo, 0)
+	for rows.Next() {
+		info, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		users = append(users, info)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return users, nil
+}
+
+// SetDisabled disables or enables the user with the login. Returns a boolean indicating if the user was found.
+func (d *dbRepo) SetDisabled(ctx context.Context, login string, disabled bool) (bool, error) {
+	return d.updateUser(ctx, "UPDATE users SET disabled = $2 WHERE login = $1", login, disabled)
+}
+
+// ResetSessions stores the time the tokens of the user with the login issued before are rejected.
+// Returns a boolean indicating if the user was found.
+func (d *dbRepo) ResetSessions(ctx context.Context, login string, at time.Time) (bool, error) {
+	// the column has no time zone
+	return d.updateUser(ctx, "UPDATE users SET sessions_reset_at = $2 WHERE login = $1", login, at.UTC())
+}
+
+func (d *dbRepo) updateUser(ctx context.Context, query string, args ...any) (bool, error) {
+	res, err := d.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ListMemberships retrieves the vault memberships of the user sorted by vault name,
+// along with the numbers of the members and the owners of each vault.
+func (d *dbRepo) ListMemberships(ctx context.Context, userID string) ([]user.Membership, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT v.id, v.name, m.role, "+
+			"(SELECT COUNT(*) FROM vault_members WHERE vault_id = v.id), "+
+			"(SELECT COUNT(*) FROM vault_members WHERE vault_id = v.id AND role = $2) "+
+			"FROM vault_members m JOIN vaults v ON v.id = m.vault_id WHERE m.user_id = $1 ORDER BY v.name",
+		userID,
+		vault.RoleOwner,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	memberships := make([]user.Membership, 0)
+	for rows.Next() {
+		var membership user.Membership
+		err = rows.Scan(&membership.VaultID, &membership.VaultName, &membership.Role, &membership.Members, &membership.Owners)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		memberships = append(memberships, membership)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return memberships, nil
+}
+
+// DeleteUser deletes the user in one transaction, along with the rows referencing it: the vaults the user
+// is the only member of, the vault memberships, the emergency grants and the opened emergency access to or by the user,
+// the shares and the key pair.
+// Returns a boolean indicating if the user was found.
+func (d *dbRepo) DeleteUser(ctx context.Context, userID string) (deleted bool, err error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	queries := []string{
+		"DELETE FROM emergency_grants WHERE vault_id IN (SELECT vault_id FROM vault_members GROUP BY vault_id HAVING bool_and(user_id = $1))",
+		// the members of the vaults are deleted by the cascade
+		"DELETE FROM vaults WHERE id IN (SELECT vault_id FROM vault_members GROUP BY vault_id HAVING bool_and(user_id = $1))",
+		"DELETE FROM vault_members WHERE user_id = $1",
+		"DELETE FROM emergency_grants WHERE vault_id = $1 OR contact_id = $1 OR granted_by = $1",
+		"DELETE FROM owner_contacts WHERE owner_id = $1 OR contact_id = $1",
+		"DELETE FROM entry_shares WHERE owner_id = $1 OR recipient_id = $1",
+		"DELETE FROM user_keys WHERE user_id = $1",
+	}
+	for _, query := range queries {
+		_, err = tx.ExecContext(ctx, query, userID)
+		if err != nil {
+			return false, fmt.Errorf("query error: %w", err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", userID)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("cant commit transaction: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanUser scans a row selected with userColumns into user.UserInfo.
+func scanUser(row scanner) (user.UserInfo, error) {
+	var info user.UserInfo
+	var sessionsResetAt sql.NullTime
+	err := row.Scan(&info.ID, &info.Login, &info.PasswordHash, &info.Disabled, &sessionsResetAt, &info.CreatedAt)
+	info.SessionsResetAt = sessionsResetAt.Time
+
+	return info, err
+}
+
 // isUniqueViolation checks if the given error is a PostgreSQL unique constraint violation error.
 // Returns true if the error is a unique violation, otherwise false.
 func isUniqueViolation(err error) bool {
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jackc/pgerrcode"
@@ -12,10 +13,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	userService "github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
 	"github.com/kuvalkin/gophkeeper/internal/server/storage/user"
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
+var userColumns = []string{"id", "login", "password_hash", "disabled", "sessions_reset_at", "created_at"}
+
 func TestDatabaseRepository_Add(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
@@ -76,6 +80,9 @@ func TestDatabaseRepository_Find(t *testing.T) {
 	ctx, cancel := utils.TestContext(t)
 	defer cancel()
 
+	findQuery := "SELECT id, login, password_hash, disabled, sessions_reset_at, created_at FROM users WHERE login = \\$1"
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
 	t.Run("success", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
@@ -84,16 +91,15 @@ func TestDatabaseRepository_Find(t *testing.T) {
 		}()
 
 		mock.
-			ExpectQuery("SELECT id, password_hash FROM users WHERE login = \\$1").
+			ExpectQuery(findQuery).
 			WithArgs("login").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "password_hash"}).AddRow("id", "hash"))
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow("id", "login", "hash", true, nil, createdAt))
 
 		repo := user.NewDatabaseRepository(db)
 		info, ok, err := repo.FindUser(ctx, "login")
 		require.NoError(t, err)
 		require.True(t, ok)
-		assert.Equal(t, "id", info.ID)
-		assert.Equal(t, "hash", info.PasswordHash)
+		assert.Equal(t, userService.UserInfo{ID: "id", Login: "login", PasswordHash: "hash", Disabled: true, CreatedAt: createdAt}, info)
 	})
 
 	t.Run("not found", func(t *testing.T) {
@@ -104,7 +110,7 @@ func TestDatabaseRepository_Find(t *testing.T) {
 		}()
 
 		mock.
-			ExpectQuery("SELECT id, password_hash FROM users WHERE login = \\$1").
+			ExpectQuery(findQuery).
 			WithArgs("login").
 			WillReturnError(sql.ErrNoRows)
 
@@ -123,7 +129,7 @@ func TestDatabaseRepository_Find(t *testing.T) {
 		}()
 
 		mock.
-			ExpectQuery("SELECT id, password_hash FROM users WHERE login = \\$1").
+			ExpectQuery(findQuery).
 			WithArgs("login").
 			WillReturnError(errors.New("error"))
 
@@ -134,3 +140,269 @@ func TestDatabaseRepository_Find(t *testing.T) {
 		assert.Empty(t, info)
 	})
 }
+
+func TestDatabaseRepository_Get(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	getQuery := "SELECT id, login, password_hash, disabled, sessions_reset_at, created_at FROM users WHERE id = \\$1"
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	resetAt := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(getQuery).
+			WithArgs("id").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow("id", "login", "hash", false, resetAt, createdAt))
+
+		repo := user.NewDatabaseRepository(db)
+		info, ok, err := repo.GetUser(ctx, "id")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, userService.UserInfo{ID: "id", Login: "login", PasswordHash: "hash", SessionsResetAt: resetAt, CreatedAt: createdAt}, info)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery(getQuery).
+			WithArgs("id").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := user.NewDatabaseRepository(db)
+		info, ok, err := repo.GetUser(ctx, "id")
+		require.NoError(t, err)
+		require.False(t, ok)
+		assert.Empty(t, info)
+	})
+}
+
+func TestDatabaseRepository_List(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT id, login, '', disabled, sessions_reset_at, created_at FROM users ORDER BY login").
+			WillReturnRows(
+				sqlmock.NewRows(userColumns).
+					AddRow("first", "alice", "", false, nil, createdAt).
+					AddRow("second", "bob", "", true, nil, createdAt),
+			)
+
+		repo := user.NewDatabaseRepository(db)
+		users, err := repo.ListUsers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []userService.UserInfo{
+			{ID: "first", Login: "alice", CreatedAt: createdAt},
+			{ID: "second", Login: "bob", Disabled: true, CreatedAt: createdAt},
+		}, users)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT .+ FROM users").
+			WillReturnError(errors.New("error"))
+
+		repo := user.NewDatabaseRepository(db)
+		users, err := repo.ListUsers(ctx)
+		require.Error(t, err)
+		assert.Nil(t, users)
+	})
+}
+
+func TestDatabaseRepository_Update(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	for _, affected := range []int64{0, 1} {
+		t.Run("set disabled", func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}()
+
+			mock.
+				ExpectExec("UPDATE users SET disabled = \\$2 WHERE login = \\$1").
+				WithArgs("login", true).
+				WillReturnResult(sqlmock.NewResult(0, affected))
+
+			repo := user.NewDatabaseRepository(db)
+			found, err := repo.SetDisabled(ctx, "login", true)
+			require.NoError(t, err)
+			assert.Equal(t, affected > 0, found)
+		})
+	}
+
+	t.Run("reset sessions", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		mock.
+			ExpectExec("UPDATE users SET sessions_reset_at = \\$2 WHERE login = \\$1").
+			WithArgs("login", at).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := user.NewDatabaseRepository(db)
+		found, err := repo.ResetSessions(ctx, "login", at.In(time.FixedZone("UTC+3", 3*60*60)))
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("UPDATE users").
+			WithArgs("login", false).
+			WillReturnError(errors.New("error"))
+
+		repo := user.NewDatabaseRepository(db)
+		found, err := repo.SetDisabled(ctx, "login", false)
+		require.Error(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestDatabaseRepository_ListMemberships(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}()
+
+	mock.
+		ExpectQuery("SELECT v.id, v.name, m.role, "+
+			"\\(SELECT COUNT\\(\\*\\) FROM vault_members WHERE vault_id = v.id\\), "+
+			"\\(SELECT COUNT\\(\\*\\) FROM vault_members WHERE vault_id = v.id AND role = \\$2\\) "+
+			"FROM vault_members m JOIN vaults v ON v.id = m.vault_id WHERE m.user_id = \\$1 ORDER BY v.name").
+		WithArgs("user", vault.RoleOwner).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "role", "members", "owners"}).
+				AddRow("first", "family", "owner", 1, 1).
+				AddRow("second", "team", "viewer", 3, 1),
+		)
+
+	repo := user.NewDatabaseRepository(db)
+	memberships, err := repo.ListMemberships(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, []userService.Membership{
+		{VaultID: "first", VaultName: "family", Role: "owner", Members: 1, Owners: 1},
+		{VaultID: "second", VaultName: "team", Role: "viewer", Members: 3, Owners: 1},
+	}, memberships)
+}
+
+func TestDatabaseRepository_Delete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	expectCascade := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.
+			ExpectExec("DELETE FROM emergency_grants WHERE vault_id IN \\(SELECT vault_id FROM vault_members GROUP BY vault_id HAVING bool_and\\(user_id = \\$1\\)\\)").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectExec("DELETE FROM vaults WHERE id IN \\(SELECT vault_id FROM vault_members GROUP BY vault_id HAVING bool_and\\(user_id = \\$1\\)\\)").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectExec("DELETE FROM vault_members WHERE user_id = \\$1").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.
+			ExpectExec("DELETE FROM emergency_grants WHERE vault_id = \\$1 OR contact_id = \\$1 OR granted_by = \\$1").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectExec("DELETE FROM owner_contacts WHERE owner_id = \\$1 OR contact_id = \\$1").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectExec("DELETE FROM entry_shares WHERE owner_id = \\$1 OR recipient_id = \\$1").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.
+			ExpectExec("DELETE FROM user_keys WHERE user_id = \\$1").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		expectCascade(mock)
+		mock.
+			ExpectExec("DELETE FROM users WHERE id = \\$1").
+			WithArgs("user").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := user.NewDatabaseRepository(db)
+		found, err := repo.DeleteUser(ctx, "user")
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		expectCascade(mock)
+		mock.
+			ExpectExec("DELETE FROM users WHERE id = \\$1").
+			WithArgs("user").
+			WillReturnError(errors.New("error"))
+		mock.ExpectRollback()
+
+		repo := user.NewDatabaseRepository(db)
+		found, err := repo.DeleteUser(ctx, "user")
+		require.Error(t, err)
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,330 @@
+// Package vault provides the implementation of the vault repository for database storage.
+package vault
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+)
+
+type dbRepo struct {
+	db *sql.DB
+}
+
+// NewDatabaseRepository creates a new instance of Repository.
+// It takes a database connection pool as input and returns a vault.Repository implementation.
+func NewDatabaseRepository(db *sql.DB) vault.Repository {
+	return &dbRepo{db: db}
+}
+
+// CreateVault inserts the vault and its owner in one statement.
+// Returns the vault with the ID and the creation time set.
+func (d *dbRepo) CreateVault(ctx context.Context, userID string, name string, personal bool, wrappedKey []byte) (vault.Vault, error) {
+	v := vault.Vault{
+		Name:       name,
+		Role:       vault.RoleOwner,
+		WrappedKey: wrappedKey,
+		Personal:   personal,
+	}
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"WITH v AS (INSERT INTO vaults (name, personal) VALUES ($2, $5) RETURNING id, created_at), "+
+			"m AS (INSERT INTO vault_members (vault_id, user_id, role, wrapped_key) SELECT id, $1, $3, $4 FROM v) "+
+			"SELECT id, created_at FROM v",
+		userID,
+		name,
+		vault.RoleOwner,
+		wrappedKey,
+		personal,
+	).Scan(&v.ID, &v.CreatedAt)
+	if err != nil {
+		return vault.Vault{}, fmt.Errorf("query error: %w", err)
+	}
+
+	return v, nil
+}
+
+// ListVaults retrieves the vaults the user is a member of along with the role and the wrapped key of the user, sorted by name.
+// The own entries of other users the user opened the emergency access to are listed too.
+func (d *dbRepo) ListVaults(ctx context.Context, userID string) ([]vault.Vault, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT v.id, v.name, m.role, m.wrapped_key, v.personal, v.created_at FROM vaults v JOIN vault_members m ON m.vault_id = v.id "+
+			"WHERE m.user_id = $1 "+
+			"UNION ALL SELECT c.owner_id, u.login, $2, c.wrapped_key, true, c.added_at FROM owner_contacts c JOIN users u ON u.id = c.owner_id "+
+			"WHERE c.contact_id = $1 ORDER BY 2, 1",
+		userID,
+		vault.RoleViewer,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	vaults := make([]vault.Vault, 0)
+	for rows.Next() {
+		var v vault.Vault
+		err = rows.Scan(&v.ID, &v.Name, &v.Role, &v.WrappedKey, &v.Personal, &v.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		vaults = append(vaults, v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return vaults, nil
+}
+
+// CountPersonalVaults retrieves the number of personal vaults the user is a member of.
+func (d *dbRepo) CountPersonalVaults(ctx context.Context, userID string) (int, error) {
+	var count int
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM vaults v JOIN vault_members m ON m.vault_id = v.id WHERE m.user_id = $1 AND v.personal",
+		userID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("query error: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetVault retrieves the vault along with the role and the wrapped key of the user.
+// The own entries of another user the user opened the emergency access to are returned as a personal vault.
+// Returns the vault, a boolean indicating if the user is a member of it, and an error if the operation fails.
+func (d *dbRepo) GetVault(ctx context.Context, vaultID string, userID string) (vault.Vault, bool, error) {
+	var v vault.Vault
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT v.id, v.name, m.role, m.wrapped_key, v.personal, v.created_at FROM vaults v JOIN vault_members m ON m.vault_id = v.id "+
+			"WHERE v.id = $1 AND m.user_id = $2 "+
+			"UNION ALL SELECT c.owner_id, u.login, $3, c.wrapped_key, true, c.added_at FROM owner_contacts c JOIN users u ON u.id = c.owner_id "+
+			"WHERE c.owner_id = $1 AND c.contact_id = $2",
+		vaultID,
+		userID,
+		vault.RoleViewer,
+	).Scan(&v.ID, &v.Name, &v.Role, &v.WrappedKey, &v.Personal, &v.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return vault.Vault{}, false, nil
+		}
+
+		return vault.Vault{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return v, true, nil
+}
+
+// RenameVault updates the name of the vault.
+// Returns a boolean indicating if the vault exists.
+func (d *dbRepo) RenameVault(ctx context.Context, vaultID string, name string) (bool, error) {
+	res, err := d.db.ExecContext(ctx, "UPDATE vaults SET name = $2 WHERE id = $1", vaultID, name)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteVault deletes the vault, unless there are entries or trashed entries stored under its ID.
+// The members are deleted by the foreign key, the emergency grants, the change log, the old versions of the entries,
+// the unfinished uploads and the shares of its entries along with the vault, in one transaction.
+// Returns references to the blobs of the deleted metadata and a boolean indicating if anything was deleted.
+func (d *dbRepo) DeleteVault(ctx context.Context, vaultID string) (refs []entry.BlobRef, deleted bool, err error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil || !deleted {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var count int
+	err = tx.QueryRowContext(
+		ctx,
+		"WITH v AS (DELETE FROM vaults WHERE id = $1 "+
+			"AND NOT EXISTS (SELECT 1 FROM entries WHERE user_id = $1) AND NOT EXISTS (SELECT 1 FROM entry_trash WHERE user_id = $1) RETURNING id), "+
+			"g AS (DELETE FROM emergency_grants WHERE vault_id IN (SELECT id FROM v)), "+
+			"c AS (DELETE FROM entry_changes WHERE user_id IN (SELECT id FROM v)) "+
+			"SELECT COUNT(*) FROM v",
+		vaultID,
+	).Scan(&count)
+	if err != nil {
+		return nil, false, fmt.Errorf("query error: %w", err)
+	}
+
+	if count == 0 {
+		return nil, false, nil
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		"WITH deleted_versions AS (DELETE FROM entry_versions WHERE user_id = $1 RETURNING user_id, key, version, blob_name), "+
+			"deleted_uploads AS (DELETE FROM upload_sessions WHERE user_id = $1 RETURNING user_id, key, expected_version, id), "+
+			"deleted_shares AS (DELETE FROM entry_shares WHERE owner_id = $1 RETURNING owner_id, key, recipient_id) "+
+			"SELECT $2::text, user_id, key, version, blob_name, '', '' FROM deleted_versions "+
+			"UNION ALL SELECT $3::text, user_id, key, expected_version, '', id::text, '' FROM deleted_uploads "+
+			"UNION ALL SELECT $4::text, owner_id, key, 0, '', '', recipient_id::text FROM deleted_shares",
+		vaultID,
+		entry.BlobRefVersion,
+		entry.BlobRefUpload,
+		entry.BlobRefShare,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	refs = make([]entry.BlobRef, 0)
+	for rows.Next() {
+		var ref entry.BlobRef
+		err = rows.Scan(&ref.Kind, &ref.UserID, &ref.Key, &ref.Version, &ref.BlobName, &ref.UploadID, &ref.RecipientID)
+		if err != nil {
+			return nil, false, fmt.Errorf("scan error: %w", err)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("cant commit transaction: %w", err)
+	}
+
+	return refs, true, nil
+}
+
+// GetRole retrieves the role of the user in the vault, the viewer one for the own entries of another user
+// the user opened the emergency access to.
+// Returns the role, a boolean indicating if the user is a member of the vault, and an error if the operation fails.
+func (d *dbRepo) GetRole(ctx context.Context, vaultID string, userID string) (vault.Role, bool, error) {
+	var role vault.Role
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"SELECT role FROM vault_members WHERE vault_id = $1 AND user_id = $2 "+
+			"UNION ALL SELECT $3 FROM owner_contacts WHERE owner_id = $1 AND contact_id = $2",
+		vaultID,
+		userID,
+		vault.RoleViewer,
+	).Scan(&role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("query error: %w", err)
+	}
+
+	return role, true, nil
+}
+
+// AddMember inserts the user with the login as a member of the vault.
+// Returns vault.ErrUserNotFound if there is no such user and vault.ErrMemberExists if the user is a member already.
+func (d *dbRepo) AddMember(ctx context.Context, vaultID string, member vault.Member) (vault.Member, error) {
+	err := d.db.QueryRowContext(
+		ctx,
+		"INSERT INTO vault_members (vault_id, user_id, role, wrapped_key) SELECT $1, id, $3, $4 FROM users WHERE login = $2 "+
+			"ON CONFLICT (vault_id, user_id) DO NOTHING RETURNING user_id, added_at",
+		vaultID,
+		member.Login,
+		member.Role,
+		member.WrappedKey,
+	).Scan(&member.UserID, &member.AddedAt)
+	if err == nil {
+		return member, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return vault.Member{}, fmt.Errorf("query error: %w", err)
+	}
+
+	// nothing was inserted, either there is no such user or it's a member already
+	var exists bool
+	err = d.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE login = $1)", member.Login).Scan(&exists)
+	if err != nil {
+		return vault.Member{}, fmt.Errorf("query error: %w", err)
+	}
+
+	if !exists {
+		return vault.Member{}, vault.ErrUserNotFound
+	}
+
+	return vault.Member{}, vault.ErrMemberExists
+}
+
+// ListMembers retrieves the members of the vault with their logins, sorted by login. Wrapped keys aren't selected.
+func (d *dbRepo) ListMembers(ctx context.Context, vaultID string) ([]vault.Member, error) {
+	rows, err := d.db.QueryContext(
+		ctx,
+		"SELECT m.user_id, u.login, m.role, m.added_at FROM vault_members m JOIN users u ON u.id = m.user_id "+
+			"WHERE m.vault_id = $1 ORDER BY u.login",
+		vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	members := make([]vault.Member, 0)
+	for rows.Next() {
+		var m vault.Member
+		err = rows.Scan(&m.UserID, &m.Login, &m.Role, &m.AddedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return members, nil
+}
+
+// RemoveMember deletes the member from the vault in one statement, unless it's the last owner of it.
+// Returns a boolean indicating if anything was deleted.
+func (d *dbRepo) RemoveMember(ctx context.Context, vaultID string, userID string) (bool, error) {
+	res, err := d.db.ExecContext(
+		ctx,
+		"DELETE FROM vault_members WHERE vault_id = $1 AND user_id = $2 "+
+			"AND (role <> $3 OR (SELECT COUNT(*) FROM vault_members WHERE vault_id = $1 AND role = $3) > 1)",
+		vaultID,
+		userID,
+		vault.RoleOwner,
+	)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,460 @@
+package vault_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	vaultService "github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/storage/vault"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+func TestDatabaseRepository_CreateVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH v AS \\(INSERT INTO vaults").
+			WithArgs("user", "team", vaultService.RoleOwner, []byte("key"), true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("vault", createdAt))
+
+		repo := vault.NewDatabaseRepository(db)
+		v, err := repo.CreateVault(ctx, "user", "team", true, []byte("key"))
+		require.NoError(t, err)
+		require.Equal(t, vaultService.Vault{
+			ID:         "vault",
+			Name:       "team",
+			Role:       vaultService.RoleOwner,
+			WrappedKey: []byte("key"),
+			Personal:   true,
+			CreatedAt:  createdAt,
+		}, v)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH v AS \\(INSERT INTO vaults").
+			WillReturnError(errors.New("error"))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.CreateVault(ctx, "user", "team", false, []byte("key"))
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseRepository_CountPersonalVaults(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT COUNT\\(\\*\\) FROM vaults v JOIN vault_members m ON m.vault_id = v.id WHERE m.user_id = \\$1 AND v.personal").
+			WithArgs("user").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		repo := vault.NewDatabaseRepository(db)
+		count, err := repo.CountPersonalVaults(ctx, "user")
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT COUNT").
+			WillReturnError(errors.New("error"))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.CountPersonalVaults(ctx, "user")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseRepository_GetVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("member", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT v.id, v.name, m.role, m.wrapped_key, v.personal, v.created_at FROM vaults v JOIN vault_members m ON m.vault_id = v.id WHERE v.id = \\$1 AND m.user_id = \\$2 "+
+				"UNION ALL SELECT c.owner_id, u.login, \\$3, c.wrapped_key, true, c.added_at FROM owner_contacts c JOIN users u ON u.id = c.owner_id "+
+				"WHERE c.owner_id = \\$1 AND c.contact_id = \\$2").
+			WithArgs("vault", "user", vaultService.RoleViewer).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "wrapped_key", "personal", "created_at"}).
+				AddRow("vault", "work", "owner", []byte("key"), true, createdAt))
+
+		repo := vault.NewDatabaseRepository(db)
+		v, ok, err := repo.GetVault(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, vaultService.Vault{
+			ID:         "vault",
+			Name:       "work",
+			Role:       vaultService.RoleOwner,
+			WrappedKey: []byte("key"),
+			Personal:   true,
+			CreatedAt:  createdAt,
+		}, v)
+	})
+
+	t.Run("owner contact", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT v.id, v.name.+FROM owner_contacts").
+			WithArgs("owner", "user", vaultService.RoleViewer).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "wrapped_key", "personal", "created_at"}).
+				AddRow("owner", "alice", "viewer", []byte("key"), true, createdAt))
+
+		repo := vault.NewDatabaseRepository(db)
+		v, ok, err := repo.GetVault(ctx, "owner", "user")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, vaultService.Vault{
+			ID:         "owner",
+			Name:       "alice",
+			Role:       vaultService.RoleViewer,
+			WrappedKey: []byte("key"),
+			Personal:   true,
+			CreatedAt:  createdAt,
+		}, v)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT v.id, v.name").
+			WithArgs("vault", "user", vaultService.RoleViewer).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := vault.NewDatabaseRepository(db)
+		_, ok, err := repo.GetVault(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestDatabaseRepository_RenameVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("UPDATE vaults SET name = \\$2 WHERE id = \\$1").
+			WithArgs("vault", "work").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := vault.NewDatabaseRepository(db)
+		found, err := repo.RenameVault(ctx, "vault", "work")
+		require.NoError(t, err)
+		require.True(t, found)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("UPDATE vaults").
+			WillReturnError(errors.New("error"))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.RenameVault(ctx, "vault", "work")
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseRepository_DeleteVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	deleteQuery := "WITH v AS \\(DELETE FROM vaults WHERE id = \\$1 " +
+		"AND NOT EXISTS \\(SELECT 1 FROM entries WHERE user_id = \\$1\\) AND NOT EXISTS \\(SELECT 1 FROM entry_trash WHERE user_id = \\$1\\) RETURNING id\\)"
+	refsQuery := "WITH deleted_versions AS \\(DELETE FROM entry_versions WHERE user_id = \\$1 RETURNING user_id, key, version, blob_name\\)"
+
+	t.Run("deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("vault").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.
+			ExpectQuery(refsQuery).
+			WithArgs("vault", entry.BlobRefVersion, entry.BlobRefUpload, entry.BlobRefShare).
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "user_id", "key", "version", "blob_name", "upload_id", "recipient_id"}).
+				AddRow("version", "vault", "key", 1, "contents/1", "", "").
+				AddRow("upload", "vault", "key", 2, "", "upload", "").
+				AddRow("share", "vault", "key", 0, "", "", "bob"))
+		mock.ExpectCommit()
+
+		repo := vault.NewDatabaseRepository(db)
+		refs, deleted, err := repo.DeleteVault(ctx, "vault")
+		require.NoError(t, err)
+		require.True(t, deleted)
+		require.Equal(t, []entry.BlobRef{
+			{Kind: entry.BlobRefVersion, UserID: "vault", Key: "key", Version: 1, BlobName: "contents/1"},
+			{Kind: entry.BlobRefUpload, UserID: "vault", Key: "key", Version: 2, UploadID: "upload"},
+			{Kind: entry.BlobRefShare, UserID: "vault", Key: "key", RecipientID: "bob"},
+		}, refs)
+	})
+
+	t.Run("has entries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("vault").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		repo := vault.NewDatabaseRepository(db)
+		refs, deleted, err := repo.DeleteVault(ctx, "vault")
+		require.NoError(t, err)
+		require.False(t, deleted)
+		require.Empty(t, refs)
+	})
+
+	t.Run("refs error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(deleteQuery).
+			WithArgs("vault").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.
+			ExpectQuery(refsQuery).
+			WillReturnError(errors.New("error"))
+		mock.ExpectRollback()
+
+		repo := vault.NewDatabaseRepository(db)
+		_, deleted, err := repo.DeleteVault(ctx, "vault")
+		require.Error(t, err)
+		require.False(t, deleted)
+	})
+}
+
+func TestDatabaseRepository_GetRole(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("member", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT role FROM vault_members WHERE vault_id = \\$1 AND user_id = \\$2 "+
+				"UNION ALL SELECT \\$3 FROM owner_contacts WHERE owner_id = \\$1 AND contact_id = \\$2").
+			WithArgs("vault", "user", vaultService.RoleViewer).
+			WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
+
+		repo := vault.NewDatabaseRepository(db)
+		role, ok, err := repo.GetRole(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, vaultService.RoleEditor, role)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("SELECT role FROM vault_members").
+			WithArgs("vault", "user", vaultService.RoleViewer).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := vault.NewDatabaseRepository(db)
+		_, ok, err := repo.GetRole(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestDatabaseRepository_AddMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	member := vaultService.Member{Login: "bob", Role: vaultService.RoleViewer, WrappedKey: []byte("key")}
+	addedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO vault_members \\(vault_id, user_id, role, wrapped_key\\) SELECT").
+			WithArgs("vault", "bob", vaultService.RoleViewer, []byte("key")).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "added_at"}).AddRow("bob-id", addedAt))
+
+		repo := vault.NewDatabaseRepository(db)
+		added, err := repo.AddMember(ctx, "vault", member)
+		require.NoError(t, err)
+		require.Equal(t, "bob-id", added.UserID)
+		require.Equal(t, addedAt, added.AddedAt)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO vault_members").
+			WillReturnError(sql.ErrNoRows)
+		mock.
+			ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM users WHERE login = \\$1\\)").
+			WithArgs("bob").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.AddMember(ctx, "vault", member)
+		require.ErrorIs(t, err, vaultService.ErrUserNotFound)
+	})
+
+	t.Run("member exists", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("INSERT INTO vault_members").
+			WillReturnError(sql.ErrNoRows)
+		mock.
+			ExpectQuery("SELECT EXISTS").
+			WithArgs("bob").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.AddMember(ctx, "vault", member)
+		require.ErrorIs(t, err, vaultService.ErrMemberExists)
+	})
+}
+
+func TestDatabaseRepository_RemoveMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM vault_members WHERE vault_id = \\$1 AND user_id = \\$2").
+			WithArgs("vault", "user", vaultService.RoleOwner).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := vault.NewDatabaseRepository(db)
+		deleted, err := repo.RemoveMember(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.True(t, deleted)
+	})
+
+	t.Run("last owner", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectExec("DELETE FROM vault_members").
+			WithArgs("vault", "user", vaultService.RoleOwner).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := vault.NewDatabaseRepository(db)
+		deleted, err := repo.RemoveMember(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.False(t, deleted)
+	})
+}
@@ -0,0 +1,250 @@
+package vault
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+)
+
+// grantSelect selects the grants from the g relation along with the names of the vaults and the logins of the users.
+// The grants of the own entries of a user have the ID of the user in place of a vault one and are named after the login.
+const grantSelect = "SELECT g.vault_id, COALESCE(v.name, o.login) AS vault_name, g.contact_id, c.login, o.login, g.wrapped_key, g.wait_seconds, g.requested_at, g.created_at " +
+	"FROM g LEFT JOIN vaults v ON v.id = g.vault_id JOIN users c ON c.id = g.contact_id JOIN users o ON o.id = g.granted_by"
+
+// CreateEmergencyGrant inserts the grant of the user to the contact with the login.
+// Returns vault.ErrUserNotFound if there is no such user and vault.ErrGrantExists if the user is a contact of the vault already.
+func (d *dbRepo) CreateEmergencyGrant(ctx context.Context, userID string, vaultID string, grant vault.EmergencyGrant) (vault.EmergencyGrant, error) {
+	created, err := scanGrant(d.db.QueryRowContext(
+		ctx,
+		"WITH g AS (INSERT INTO emergency_grants (vault_id, contact_id, granted_by, wrapped_key, wait_seconds) "+
+			"SELECT $2, id, $1, $4, $5 FROM users WHERE login = $3 ON CONFLICT (vault_id, contact_id) DO NOTHING RETURNING *) "+
+			grantSelect,
+		userID,
+		vaultID,
+		grant.ContactLogin,
+		grant.WrappedKey,
+		int64(grant.Wait/time.Second),
+	))
+	if err == nil {
+		return created, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return vault.EmergencyGrant{}, fmt.Errorf("query error: %w", err)
+	}
+
+	// nothing was inserted, either there is no such user or it's a contact already
+	var exists bool
+	err = d.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE login = $1)", grant.ContactLogin).Scan(&exists)
+	if err != nil {
+		return vault.EmergencyGrant{}, fmt.Errorf("query error: %w", err)
+	}
+
+	if !exists {
+		return vault.EmergencyGrant{}, vault.ErrUserNotFound
+	}
+
+	return vault.EmergencyGrant{}, vault.ErrGrantExists
+}
+
+// GetEmergencyGrant retrieves the grant of the vault to the contact.
+// Returns the grant, a boolean indicating if it exists, and an error if the operation fails.
+func (d *dbRepo) GetEmergencyGrant(ctx context.Context, vaultID string, contactID string) (vault.EmergencyGrant, bool, error) {
+	grant, err := scanGrant(d.db.QueryRowContext(
+		ctx,
+		"WITH g AS (SELECT * FROM emergency_grants WHERE vault_id = $1 AND contact_id = $2) "+grantSelect,
+		vaultID,
+		contactID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return vault.EmergencyGrant{}, false, nil
+		}
+
+		return vault.EmergencyGrant{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return grant, true, nil
+}
+
+// ListEmergencyGrants retrieves the grants of the vaults the user owns and of the own entries of the user,
+// or the ones the user is the contact of if incoming is true, sorted by the vault name and the login of the contact.
+// Wrapped keys aren't returned.
+func (d *dbRepo) ListEmergencyGrants(ctx context.Context, userID string, incoming bool) ([]vault.EmergencyGrant, error) {
+	filter := "SELECT * FROM emergency_grants WHERE vault_id IN (SELECT vault_id FROM vault_members WHERE user_id = $1 AND role = $2) OR vault_id = $1"
+	args := []any{userID, vault.RoleOwner}
+	if incoming {
+		filter = "SELECT * FROM emergency_grants WHERE contact_id = $1"
+		args = args[:1]
+	}
+
+	rows, err := d.db.QueryContext(ctx, "WITH g AS ("+filter+") "+grantSelect+" ORDER BY vault_name, c.login", args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	grants := make([]vault.EmergencyGrant, 0)
+	for rows.Next() {
+		grant, err := scanGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		grant.WrappedKey = nil
+		grants = append(grants, grant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return grants, nil
+}
+
+// DeleteEmergencyGrant deletes the grant of the vault to the contact with the login.
+// Returns a boolean indicating if anything was deleted.
+func (d *dbRepo) DeleteEmergencyGrant(ctx context.Context, vaultID string, login string) (bool, error) {
+	res, err := d.db.ExecContext(
+		ctx,
+		"DELETE FROM emergency_grants WHERE vault_id = $1 AND contact_id = (SELECT id FROM users WHERE login = $2)",
+		vaultID,
+		login,
+	)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// RequestEmergencyAccess sets the request time of the grant to the contact to now, unless it's set already.
+// Returns the grant, a boolean indicating if it exists, and an error if the operation fails.
+func (d *dbRepo) RequestEmergencyAccess(ctx context.Context, vaultID string, contactID string) (vault.EmergencyGrant, bool, error) {
+	grant, err := scanGrant(d.db.QueryRowContext(
+		ctx,
+		"WITH g AS (UPDATE emergency_grants SET requested_at = COALESCE(requested_at, now()) "+
+			"WHERE vault_id = $1 AND contact_id = $2 RETURNING *) "+grantSelect,
+		vaultID,
+		contactID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return vault.EmergencyGrant{}, false, nil
+		}
+
+		return vault.EmergencyGrant{}, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return grant, true, nil
+}
+
+// RejectEmergencyAccess clears the request time of the grant to the contact with the login.
+// Returns a boolean indicating if the grant exists.
+func (d *dbRepo) RejectEmergencyAccess(ctx context.Context, vaultID string, login string) (bool, error) {
+	res, err := d.db.ExecContext(
+		ctx,
+		"UPDATE emergency_grants SET requested_at = NULL WHERE vault_id = $1 AND contact_id = (SELECT id FROM users WHERE login = $2)",
+		vaultID,
+		login,
+	)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReleaseEmergencyGrant deletes the grant and adds the contact as a viewer of the vault with its wrapped key in one statement,
+// if the grant is requested and its waiting period ends before the given time. The contact of the own entries of a user
+// is added to the owner contacts instead. Returns a boolean indicating if the grant was released.
+func (d *dbRepo) ReleaseEmergencyGrant(ctx context.Context, vaultID string, contactID string, availableBefore time.Time) (bool, error) {
+	var released int
+
+	err := d.db.QueryRowContext(
+		ctx,
+		"WITH g AS (DELETE FROM emergency_grants WHERE vault_id = $1 AND contact_id = $2 "+
+			"AND requested_at IS NOT NULL AND requested_at + wait_seconds * interval '1 second' <= $3 RETURNING *), "+
+			"m AS (INSERT INTO vault_members (vault_id, user_id, role, wrapped_key) SELECT vault_id, contact_id, $4, wrapped_key FROM g "+
+			"WHERE vault_id IN (SELECT id FROM vaults) ON CONFLICT (vault_id, user_id) DO NOTHING), "+
+			"o AS (INSERT INTO owner_contacts (owner_id, contact_id, wrapped_key) SELECT vault_id, contact_id, wrapped_key FROM g "+
+			"WHERE vault_id NOT IN (SELECT id FROM vaults) ON CONFLICT (owner_id, contact_id) DO UPDATE SET wrapped_key = EXCLUDED.wrapped_key) "+
+			"SELECT COUNT(*) FROM g",
+		vaultID,
+		contactID,
+		availableBefore,
+		vault.RoleViewer,
+	).Scan(&released)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	return released > 0, nil
+}
+
+// DeleteOwnerContact deletes the access of the contact with the login to the own entries of the owner.
+// Returns a boolean indicating if anything was deleted.
+func (d *dbRepo) DeleteOwnerContact(ctx context.Context, ownerID string, login string) (bool, error) {
+	res, err := d.db.ExecContext(
+		ctx,
+		"DELETE FROM owner_contacts WHERE owner_id = $1 AND contact_id = (SELECT id FROM users WHERE login = $2)",
+		ownerID,
+		login,
+	)
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGrant(row scanner) (vault.EmergencyGrant, error) {
+	var grant vault.EmergencyGrant
+	var waitSeconds int64
+	var requestedAt sql.NullTime
+
+	err := row.Scan(
+		&grant.VaultID,
+		&grant.VaultName,
+		&grant.ContactID,
+		&grant.ContactLogin,
+		&grant.OwnerLogin,
+		&grant.WrappedKey,
+		&waitSeconds,
+		&requestedAt,
+		&grant.CreatedAt,
+	)
+	if err != nil {
+		return vault.EmergencyGrant{}, err
+	}
+
+	grant.Wait = time.Duration(waitSeconds) * time.Second
+	if requestedAt.Valid {
+		grant.RequestedAt = requestedAt.Time
+	}
+
+	return grant, nil
+}
@@ -0,0 +1,272 @@
+package vault_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vaultService "github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/storage/vault"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+var grantColumns = []string{"vault_id", "name", "contact_id", "login", "login", "wrapped_key", "wait_seconds", "requested_at", "created_at"}
+
+func TestDatabaseRepository_CreateEmergencyGrant(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	grant := vaultService.EmergencyGrant{ContactLogin: "bob", WrappedKey: []byte("key"), Wait: 72 * time.Hour}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(INSERT INTO emergency_grants").
+			WithArgs("user", "vault", "bob", []byte("key"), int64(72*3600)).
+			WillReturnRows(sqlmock.NewRows(grantColumns).AddRow("vault", "team", "contact", "bob", "alice", []byte("key"), 72*3600, nil, createdAt))
+
+		repo := vault.NewDatabaseRepository(db)
+		created, err := repo.CreateEmergencyGrant(ctx, "user", "vault", grant)
+		require.NoError(t, err)
+		require.Equal(t, vaultService.EmergencyGrant{
+			VaultID:      "vault",
+			VaultName:    "team",
+			ContactID:    "contact",
+			ContactLogin: "bob",
+			OwnerLogin:   "alice",
+			WrappedKey:   []byte("key"),
+			Wait:         72 * time.Hour,
+			CreatedAt:    createdAt,
+		}, created)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(INSERT INTO emergency_grants").
+			WillReturnError(sql.ErrNoRows)
+		mock.
+			ExpectQuery("SELECT EXISTS").
+			WithArgs("bob").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.CreateEmergencyGrant(ctx, "user", "vault", grant)
+		require.ErrorIs(t, err, vaultService.ErrUserNotFound)
+	})
+
+	t.Run("grant exists", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(INSERT INTO emergency_grants").
+			WillReturnError(sql.ErrNoRows)
+		mock.
+			ExpectQuery("SELECT EXISTS").
+			WithArgs("bob").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.CreateEmergencyGrant(ctx, "user", "vault", grant)
+		require.ErrorIs(t, err, vaultService.ErrGrantExists)
+	})
+}
+
+func TestDatabaseRepository_ListEmergencyGrants(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	requestedAt := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("outgoing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(SELECT \\* FROM emergency_grants WHERE vault_id IN .+ OR vault_id = \\$1\\) .+ ORDER BY vault_name, c.login").
+			WithArgs("user", vaultService.RoleOwner).
+			WillReturnRows(sqlmock.NewRows(grantColumns).AddRow("vault", "team", "contact", "bob", "alice", []byte("key"), 3600, requestedAt, createdAt))
+
+		repo := vault.NewDatabaseRepository(db)
+		grants, err := repo.ListEmergencyGrants(ctx, "user", false)
+		require.NoError(t, err)
+		require.Equal(t, []vaultService.EmergencyGrant{{
+			VaultID:      "vault",
+			VaultName:    "team",
+			ContactID:    "contact",
+			ContactLogin: "bob",
+			OwnerLogin:   "alice",
+			Wait:         time.Hour,
+			RequestedAt:  requestedAt,
+			CreatedAt:    createdAt,
+		}}, grants)
+	})
+
+	t.Run("incoming", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(SELECT \\* FROM emergency_grants WHERE contact_id = \\$1\\)").
+			WithArgs("user").
+			WillReturnRows(sqlmock.NewRows(grantColumns))
+
+		repo := vault.NewDatabaseRepository(db)
+		grants, err := repo.ListEmergencyGrants(ctx, "user", true)
+		require.NoError(t, err)
+		require.Empty(t, grants)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS").
+			WillReturnError(errors.New("error"))
+
+		repo := vault.NewDatabaseRepository(db)
+		_, err = repo.ListEmergencyGrants(ctx, "user", true)
+		require.Error(t, err)
+	})
+}
+
+func TestDatabaseRepository_RequestEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(UPDATE emergency_grants SET requested_at = COALESCE\\(requested_at, now\\(\\)\\)").
+			WithArgs("vault", "user").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := vault.NewDatabaseRepository(db)
+		_, ok, err := repo.RequestEmergencyAccess(ctx, "vault", "user")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestDatabaseRepository_RejectEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}()
+
+	mock.
+		ExpectExec("UPDATE emergency_grants SET requested_at = NULL").
+		WithArgs("vault", "bob").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := vault.NewDatabaseRepository(db)
+	found, err := repo.RejectEmergencyAccess(ctx, "vault", "bob")
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestDatabaseRepository_ReleaseEmergencyGrant(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	now := time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("released", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(DELETE FROM emergency_grants .+ "+
+				"m AS \\(INSERT INTO vault_members .+ WHERE vault_id IN \\(SELECT id FROM vaults\\) .+ "+
+				"o AS \\(INSERT INTO owner_contacts .+ WHERE vault_id NOT IN \\(SELECT id FROM vaults\\)").
+			WithArgs("vault", "user", now, vaultService.RoleViewer).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		repo := vault.NewDatabaseRepository(db)
+		released, err := repo.ReleaseEmergencyGrant(ctx, "vault", "user", now)
+		require.NoError(t, err)
+		require.True(t, released)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet())
+		}()
+
+		mock.
+			ExpectQuery("WITH g AS \\(DELETE FROM emergency_grants").
+			WithArgs("vault", "user", now, vaultService.RoleViewer).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		repo := vault.NewDatabaseRepository(db)
+		released, err := repo.ReleaseEmergencyGrant(ctx, "vault", "user", now)
+		require.NoError(t, err)
+		require.False(t, released)
+	})
+}
+
+func TestDatabaseRepository_DeleteOwnerContact(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}()
+
+	mock.
+		ExpectExec("DELETE FROM owner_contacts WHERE owner_id = \\$1 AND contact_id = \\(SELECT id FROM users WHERE login = \\$2\\)").
+		WithArgs("owner", "bob").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := vault.NewDatabaseRepository(db)
+	deleted, err := repo.DeleteOwnerContact(ctx, "owner", "bob")
+	require.NoError(t, err)
+	require.True(t, deleted)
+}
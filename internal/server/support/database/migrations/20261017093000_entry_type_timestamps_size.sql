@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE entries
+    ADD COLUMN type VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS type,
+    DROP COLUMN IF EXISTS size,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE entries
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS entry_versions (
+    user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    key TEXT NOT NULL,
+    version BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    notes BYTEA DEFAULT NULL,
+    type VARCHAR(50) NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, key, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS entry_versions;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS entry_changes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    key TEXT NOT NULL,
+    version BIGINT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT false,
+    changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS entry_changes_user_id_id_idx ON entry_changes (user_id, id);
+CREATE INDEX IF NOT EXISTS entry_changes_user_id_key_idx ON entry_changes (user_id, key, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS entry_changes;
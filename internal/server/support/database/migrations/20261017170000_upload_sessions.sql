@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    notes BYTEA DEFAULT NULL,
+    type TEXT NOT NULL DEFAULT '',
+    size BIGINT NOT NULL,
+    expected_version BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS upload_sessions_user_id_idx ON upload_sessions (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS upload_sessions;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE entries
+    ADD COLUMN checksum BYTEA DEFAULT NULL;
+
+ALTER TABLE entry_versions
+    ADD COLUMN checksum BYTEA DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE entry_versions
+    DROP COLUMN IF EXISTS checksum;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS checksum;
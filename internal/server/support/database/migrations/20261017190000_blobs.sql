@@ -0,0 +1,19 @@
+-- +goose Up
+-- used only by the database blob backend, blobs being written have no key yet
+CREATE TABLE IF NOT EXISTS blobs (
+    id BIGSERIAL PRIMARY KEY,
+    key TEXT UNIQUE,
+    size BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS blob_chunks (
+    blob_id BIGINT NOT NULL REFERENCES blobs(id) ON DELETE CASCADE,
+    start_offset BIGINT NOT NULL,
+    data BYTEA NOT NULL,
+    PRIMARY KEY (blob_id, start_offset)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS blob_chunks;
+DROP TABLE IF EXISTS blobs;
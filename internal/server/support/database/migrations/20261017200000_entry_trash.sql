@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS entry_trash (
+    user_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    notes BYTEA DEFAULT NULL,
+    type VARCHAR(50) NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    version BIGINT NOT NULL,
+    checksum BYTEA DEFAULT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS entry_trash_deleted_at_idx ON entry_trash (deleted_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS entry_trash;
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE entries
+    ADD COLUMN envelope BYTEA DEFAULT NULL;
+
+ALTER TABLE entry_versions
+    ADD COLUMN envelope BYTEA DEFAULT NULL;
+
+ALTER TABLE entry_trash
+    ADD COLUMN envelope BYTEA DEFAULT NULL;
+
+ALTER TABLE upload_sessions
+    ADD COLUMN envelope BYTEA DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE upload_sessions
+    DROP COLUMN IF EXISTS envelope;
+
+ALTER TABLE entry_trash
+    DROP COLUMN IF EXISTS envelope;
+
+ALTER TABLE entry_versions
+    DROP COLUMN IF EXISTS envelope;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS envelope;
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_keys (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE RESTRICT,
+    public_key TEXT NOT NULL,
+    encrypted_private_key BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS entry_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    key TEXT NOT NULL,
+    envelope BYTEA NOT NULL,
+    size BIGINT NOT NULL,
+    checksum BYTEA DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    UNIQUE (owner_id, recipient_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS entry_shares_recipient_id_idx ON entry_shares (recipient_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS entry_shares;
+DROP TABLE IF EXISTS user_keys;
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS vaults (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(250) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS vault_members (
+    vault_id UUID NOT NULL REFERENCES vaults(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    role VARCHAR(20) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (vault_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS vault_members_user_id_idx ON vault_members (user_id);
+
+-- the entries of a vault are stored under the vault id in place of a user id
+ALTER TABLE entries DROP CONSTRAINT IF EXISTS entries_user_id_fkey;
+ALTER TABLE entry_versions DROP CONSTRAINT IF EXISTS entry_versions_user_id_fkey;
+ALTER TABLE entry_changes DROP CONSTRAINT IF EXISTS entry_changes_user_id_fkey;
+ALTER TABLE upload_sessions DROP CONSTRAINT IF EXISTS upload_sessions_user_id_fkey;
+ALTER TABLE entry_trash DROP CONSTRAINT IF EXISTS entry_trash_user_id_fkey;
+
+-- +goose Down
+-- not validated, the entries of the vaults are still there
+ALTER TABLE entry_trash ADD CONSTRAINT entry_trash_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT NOT VALID;
+ALTER TABLE upload_sessions ADD CONSTRAINT upload_sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT NOT VALID;
+ALTER TABLE entry_changes ADD CONSTRAINT entry_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT NOT VALID;
+ALTER TABLE entry_versions ADD CONSTRAINT entry_versions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT NOT VALID;
+ALTER TABLE entries ADD CONSTRAINT entries_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT NOT VALID;
+
+DROP TABLE IF EXISTS vault_members;
+DROP TABLE IF EXISTS vaults;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE vaults ADD COLUMN IF NOT EXISTS personal BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE vaults DROP COLUMN IF EXISTS personal;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS emergency_grants (
+    vault_id UUID NOT NULL REFERENCES vaults(id) ON DELETE CASCADE,
+    contact_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    granted_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    wrapped_key BYTEA NOT NULL,
+    wait_seconds BIGINT NOT NULL,
+    requested_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (vault_id, contact_id)
+);
+
+CREATE INDEX IF NOT EXISTS emergency_grants_contact_id_idx ON emergency_grants (contact_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS emergency_grants;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT false;
+-- the tokens issued before it are rejected
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_reset_at TIMESTAMP DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS sessions_reset_at;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- the grants of the own entries of a user are stored under the user id in place of a vault id, like the entries
+ALTER TABLE emergency_grants DROP CONSTRAINT IF EXISTS emergency_grants_vault_id_fkey;
+
+-- the contacts who opened the emergency access to the own entries of a user, they can read them with the wrapped secret
+CREATE TABLE IF NOT EXISTS owner_contacts (
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    contact_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    wrapped_key BYTEA NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (owner_id, contact_id)
+);
+
+CREATE INDEX IF NOT EXISTS owner_contacts_contact_id_idx ON owner_contacts (contact_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS owner_contacts;
+
+DELETE FROM emergency_grants WHERE vault_id NOT IN (SELECT id FROM vaults);
+ALTER TABLE emergency_grants ADD CONSTRAINT emergency_grants_vault_id_fkey FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE;
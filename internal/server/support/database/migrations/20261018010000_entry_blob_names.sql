@@ -0,0 +1,36 @@
+-- +goose Up
+-- every content is written to a blob of its own, which the metadata points at, so it's never changed in place.
+-- the blobs stored before are named after the keys they were stored under
+ALTER TABLE entries
+    ADD COLUMN blob_name TEXT;
+
+UPDATE entries SET blob_name = key;
+
+ALTER TABLE entries
+    ALTER COLUMN blob_name SET NOT NULL;
+
+ALTER TABLE entry_versions
+    ADD COLUMN blob_name TEXT;
+
+UPDATE entry_versions SET blob_name = 'versions/' || key || '/' || version;
+
+ALTER TABLE entry_versions
+    ALTER COLUMN blob_name SET NOT NULL;
+
+ALTER TABLE entry_trash
+    ADD COLUMN blob_name TEXT;
+
+UPDATE entry_trash SET blob_name = 'trash/' || key;
+
+ALTER TABLE entry_trash
+    ALTER COLUMN blob_name SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE entry_trash
+    DROP COLUMN IF EXISTS blob_name;
+
+ALTER TABLE entry_versions
+    DROP COLUMN IF EXISTS blob_name;
+
+ALTER TABLE entries
+    DROP COLUMN IF EXISTS blob_name;
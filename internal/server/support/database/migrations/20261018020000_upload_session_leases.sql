@@ -0,0 +1,11 @@
+-- +goose Up
+-- the session is leased to the server instance writing to it or finalizing it,
+-- so the instances sharing the database and the blob storage never do it at the same time
+ALTER TABLE upload_sessions
+    ADD COLUMN lease_token TEXT DEFAULT NULL,
+    ADD COLUMN leased_until TIMESTAMP DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE upload_sessions
+    DROP COLUMN IF EXISTS leased_until,
+    DROP COLUMN IF EXISTS lease_token;
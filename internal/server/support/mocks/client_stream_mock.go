@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: google.golang.org/grpc (interfaces: ClientStreamingServer)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./client_stream_mock.go -package=mocks google.golang.org/grpc ClientStreamingServer
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	metadata "google.golang.org/grpc/metadata"
+)
+
+// MockClientStreamingServer is a mock of ClientStreamingServer interface.
+type MockClientStreamingServer[Req any, Res any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientStreamingServerMockRecorder[Req, Res]
+	isgomock struct{}
+}
+
+// MockClientStreamingServerMockRecorder is the mock recorder for MockClientStreamingServer.
+type MockClientStreamingServerMockRecorder[Req any, Res any] struct {
+	mock *MockClientStreamingServer[Req, Res]
+}
+
+// NewMockClientStreamingServer creates a new mock instance.
+func NewMockClientStreamingServer[Req any, Res any](ctrl *gomock.Controller) *MockClientStreamingServer[Req, Res] {
+	mock := &MockClientStreamingServer[Req, Res]{ctrl: ctrl}
+	mock.recorder = &MockClientStreamingServerMockRecorder[Req, Res]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientStreamingServer[Req, Res]) EXPECT() *MockClientStreamingServerMockRecorder[Req, Res] {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockClientStreamingServer[Req, Res]) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockClientStreamingServer[Req, Res]) Recv() (*Req, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*Req)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockClientStreamingServer[Req, Res]) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).RecvMsg), m)
+}
+
+// SendAndClose mocks base method.
+func (m *MockClientStreamingServer[Req, Res]) SendAndClose(arg0 *Res) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) SendAndClose(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockClientStreamingServer[Req, Res]) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockClientStreamingServer[Req, Res]) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockClientStreamingServer[Req, Res]) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockClientStreamingServer[Req, Res]) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockClientStreamingServerMockRecorder[Req, Res]) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockClientStreamingServer[Req, Res])(nil).SetTrailer), arg0)
+}
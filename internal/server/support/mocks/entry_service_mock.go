@@ -42,24 +42,110 @@ func (m *MockEntryService) EXPECT() *MockEntryServiceMockRecorder {
 	return m.recorder
 }
 
+// AppendUpload mocks base method.
+func (m *MockEntryService) AppendUpload(ctx context.Context, userID, uploadID string, offset int64) (chan<- entry.UploadChunk, <-chan entry.UploadResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendUpload", ctx, userID, uploadID, offset)
+	ret0, _ := ret[0].(chan<- entry.UploadChunk)
+	ret1, _ := ret[1].(<-chan entry.UploadResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AppendUpload indicates an expected call of AppendUpload.
+func (mr *MockEntryServiceMockRecorder) AppendUpload(ctx, userID, uploadID, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUpload", reflect.TypeOf((*MockEntryService)(nil).AppendUpload), ctx, userID, uploadID, offset)
+}
+
+// BatchDeleteEntries mocks base method.
+func (m *MockEntryService) BatchDeleteEntries(ctx context.Context, userID string, items []entry.DeleteItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeleteEntries", ctx, userID, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchDeleteEntries indicates an expected call of BatchDeleteEntries.
+func (mr *MockEntryServiceMockRecorder) BatchDeleteEntries(ctx, userID, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteEntries", reflect.TypeOf((*MockEntryService)(nil).BatchDeleteEntries), ctx, userID, items)
+}
+
+// BatchGetMetadata mocks base method.
+func (m *MockEntryService) BatchGetMetadata(ctx context.Context, userID string, keys []string) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGetMetadata", ctx, userID, keys)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetMetadata indicates an expected call of BatchGetMetadata.
+func (mr *MockEntryServiceMockRecorder) BatchGetMetadata(ctx, userID, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetMetadata", reflect.TypeOf((*MockEntryService)(nil).BatchGetMetadata), ctx, userID, keys)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockEntryService) CreateUploadSession(ctx context.Context, userID string, md entry.Metadata, size int64, overwrite bool, expectedVersion int64) (entry.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", ctx, userID, md, size, overwrite, expectedVersion)
+	ret0, _ := ret[0].(entry.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockEntryServiceMockRecorder) CreateUploadSession(ctx, userID, md, size, overwrite, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockEntryService)(nil).CreateUploadSession), ctx, userID, md, size, overwrite, expectedVersion)
+}
+
 // DeleteEntry mocks base method.
-func (m *MockEntryService) DeleteEntry(ctx context.Context, userID, key string) error {
+func (m *MockEntryService) DeleteEntry(ctx context.Context, userID, key string, expectedVersion int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntry", ctx, userID, key)
+	ret := m.ctrl.Call(m, "DeleteEntry", ctx, userID, key, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEntry indicates an expected call of DeleteEntry.
-func (mr *MockEntryServiceMockRecorder) DeleteEntry(ctx, userID, key any) *gomock.Call {
+func (mr *MockEntryServiceMockRecorder) DeleteEntry(ctx, userID, key, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockEntryService)(nil).DeleteEntry), ctx, userID, key, expectedVersion)
+}
+
+// DiscardBlobs mocks base method.
+func (m *MockEntryService) DiscardBlobs(ctx context.Context, refs []entry.BlobRef) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DiscardBlobs", ctx, refs)
+}
+
+// DiscardBlobs indicates an expected call of DiscardBlobs.
+func (mr *MockEntryServiceMockRecorder) DiscardBlobs(ctx, refs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockEntryService)(nil).DeleteEntry), ctx, userID, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardBlobs", reflect.TypeOf((*MockEntryService)(nil).DiscardBlobs), ctx, refs)
+}
+
+// FinalizeUpload mocks base method.
+func (m *MockEntryService) FinalizeUpload(ctx context.Context, userID, uploadID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizeUpload", ctx, userID, uploadID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinalizeUpload indicates an expected call of FinalizeUpload.
+func (mr *MockEntryServiceMockRecorder) FinalizeUpload(ctx, userID, uploadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeUpload", reflect.TypeOf((*MockEntryService)(nil).FinalizeUpload), ctx, userID, uploadID)
 }
 
 // GetEntry mocks base method.
-func (m *MockEntryService) GetEntry(ctx context.Context, userID, key string) (entry.Metadata, io.ReadCloser, bool, error) {
+func (m *MockEntryService) GetEntry(ctx context.Context, userID, key string, offset, length int64) (entry.Metadata, io.ReadCloser, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", ctx, userID, key)
+	ret := m.ctrl.Call(m, "GetEntry", ctx, userID, key, offset, length)
 	ret0, _ := ret[0].(entry.Metadata)
 	ret1, _ := ret[1].(io.ReadCloser)
 	ret2, _ := ret[2].(bool)
@@ -68,15 +154,320 @@ func (m *MockEntryService) GetEntry(ctx context.Context, userID, key string) (en
 }
 
 // GetEntry indicates an expected call of GetEntry.
-func (mr *MockEntryServiceMockRecorder) GetEntry(ctx, userID, key any) *gomock.Call {
+func (mr *MockEntryServiceMockRecorder) GetEntry(ctx, userID, key, offset, length any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockEntryService)(nil).GetEntry), ctx, userID, key, offset, length)
+}
+
+// GetEntryVersion mocks base method.
+func (m *MockEntryService) GetEntryVersion(ctx context.Context, userID, key string, version int64) (entry.Metadata, io.ReadCloser, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryVersion", ctx, userID, key, version)
+	ret0, _ := ret[0].(entry.Metadata)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetEntryVersion indicates an expected call of GetEntryVersion.
+func (mr *MockEntryServiceMockRecorder) GetEntryVersion(ctx, userID, key, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryVersion", reflect.TypeOf((*MockEntryService)(nil).GetEntryVersion), ctx, userID, key, version)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockEntryService) GetKeyPair(ctx context.Context, userID string) (entry.KeyPair, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx, userID)
+	ret0, _ := ret[0].(entry.KeyPair)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockEntryServiceMockRecorder) GetKeyPair(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockEntryService)(nil).GetKeyPair), ctx, userID)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockEntryService) GetPublicKey(ctx context.Context, login string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", ctx, login)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockEntryServiceMockRecorder) GetPublicKey(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockEntryService)(nil).GetPublicKey), ctx, login)
+}
+
+// GetSharedEntry mocks base method.
+func (m *MockEntryService) GetSharedEntry(ctx context.Context, userID, shareID string) (entry.Share, io.ReadCloser, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedEntry", ctx, userID, shareID)
+	ret0, _ := ret[0].(entry.Share)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetSharedEntry indicates an expected call of GetSharedEntry.
+func (mr *MockEntryServiceMockRecorder) GetSharedEntry(ctx, userID, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedEntry", reflect.TypeOf((*MockEntryService)(nil).GetSharedEntry), ctx, userID, shareID)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockEntryService) GetUploadSession(ctx context.Context, userID, uploadID string) (entry.UploadSession, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", ctx, userID, uploadID)
+	ret0, _ := ret[0].(entry.UploadSession)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockEntryServiceMockRecorder) GetUploadSession(ctx, userID, uploadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockEntryService)(nil).GetUploadSession), ctx, userID, uploadID)
+}
+
+// GetUsage mocks base method.
+func (m *MockEntryService) GetUsage(ctx context.Context, userID string) (entry.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx, userID)
+	ret0, _ := ret[0].(entry.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockEntryServiceMockRecorder) GetUsage(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockEntryService)(nil).GetUsage), ctx, userID)
+}
+
+// ListChanges mocks base method.
+func (m *MockEntryService) ListChanges(ctx context.Context, userID string, cursor int64, pageSize int) ([]entry.Change, int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChanges", ctx, userID, cursor, pageSize)
+	ret0, _ := ret[0].([]entry.Change)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// ListChanges indicates an expected call of ListChanges.
+func (mr *MockEntryServiceMockRecorder) ListChanges(ctx, userID, cursor, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChanges", reflect.TypeOf((*MockEntryService)(nil).ListChanges), ctx, userID, cursor, pageSize)
+}
+
+// ListEntries mocks base method.
+func (m *MockEntryService) ListEntries(ctx context.Context, userID, pageToken string, pageSize int) ([]entry.Metadata, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", ctx, userID, pageToken, pageSize)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockEntryServiceMockRecorder) ListEntries(ctx, userID, pageToken, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockEntryService)(nil).ListEntries), ctx, userID, pageToken, pageSize)
+}
+
+// ListEntryVersions mocks base method.
+func (m *MockEntryService) ListEntryVersions(ctx context.Context, userID, key string) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryVersions", ctx, userID, key)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryVersions indicates an expected call of ListEntryVersions.
+func (mr *MockEntryServiceMockRecorder) ListEntryVersions(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryVersions", reflect.TypeOf((*MockEntryService)(nil).ListEntryVersions), ctx, userID, key)
+}
+
+// ListShares mocks base method.
+func (m *MockEntryService) ListShares(ctx context.Context, userID string, incoming bool) ([]entry.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, userID, incoming)
+	ret0, _ := ret[0].([]entry.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockEntryServiceMockRecorder) ListShares(ctx, userID, incoming any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockEntryService)(nil).ListShares), ctx, userID, incoming)
+}
+
+// ListTrash mocks base method.
+func (m *MockEntryService) ListTrash(ctx context.Context, userID string) ([]entry.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]entry.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockEntryServiceMockRecorder) ListTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockEntryService)(nil).ListTrash), ctx, userID)
+}
+
+// ListUsage mocks base method.
+func (m *MockEntryService) ListUsage(ctx context.Context) (map[string]entry.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsage", ctx)
+	ret0, _ := ret[0].(map[string]entry.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsage indicates an expected call of ListUsage.
+func (mr *MockEntryServiceMockRecorder) ListUsage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsage", reflect.TypeOf((*MockEntryService)(nil).ListUsage), ctx)
+}
+
+// PurgeEntry mocks base method.
+func (m *MockEntryService) PurgeEntry(ctx context.Context, userID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeEntry", ctx, userID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeEntry indicates an expected call of PurgeEntry.
+func (mr *MockEntryServiceMockRecorder) PurgeEntry(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeEntry", reflect.TypeOf((*MockEntryService)(nil).PurgeEntry), ctx, userID, key)
+}
+
+// PurgeExpiredTrash mocks base method.
+func (m *MockEntryService) PurgeExpiredTrash(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredTrash", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredTrash indicates an expected call of PurgeExpiredTrash.
+func (mr *MockEntryServiceMockRecorder) PurgeExpiredTrash(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredTrash", reflect.TypeOf((*MockEntryService)(nil).PurgeExpiredTrash), ctx)
+}
+
+// PurgeOwner mocks base method.
+func (m *MockEntryService) PurgeOwner(ctx context.Context, ownerID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeOwner", ctx, ownerID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeOwner indicates an expected call of PurgeOwner.
+func (mr *MockEntryServiceMockRecorder) PurgeOwner(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeOwner", reflect.TypeOf((*MockEntryService)(nil).PurgeOwner), ctx, ownerID)
+}
+
+// Reconcile mocks base method.
+func (m *MockEntryService) Reconcile(ctx context.Context, opts entry.ReconcileOptions) (entry.ReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, opts)
+	ret0, _ := ret[0].(entry.ReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockEntryServiceMockRecorder) Reconcile(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockEntryService)(nil).Reconcile), ctx, opts)
+}
+
+// RenameEntry mocks base method.
+func (m *MockEntryService) RenameEntry(ctx context.Context, userID, key, newKey, newName string, newEnvelope []byte, expectedVersion int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameEntry", ctx, userID, key, newKey, newName, newEnvelope, expectedVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameEntry indicates an expected call of RenameEntry.
+func (mr *MockEntryServiceMockRecorder) RenameEntry(ctx, userID, key, newKey, newName, newEnvelope, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameEntry", reflect.TypeOf((*MockEntryService)(nil).RenameEntry), ctx, userID, key, newKey, newName, newEnvelope, expectedVersion)
+}
+
+// RestoreEntry mocks base method.
+func (m *MockEntryService) RestoreEntry(ctx context.Context, userID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEntry", ctx, userID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEntry indicates an expected call of RestoreEntry.
+func (mr *MockEntryServiceMockRecorder) RestoreEntry(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntry", reflect.TypeOf((*MockEntryService)(nil).RestoreEntry), ctx, userID, key)
+}
+
+// RestoreEntryVersion mocks base method.
+func (m *MockEntryService) RestoreEntryVersion(ctx context.Context, userID, key string, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEntryVersion", ctx, userID, key, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEntryVersion indicates an expected call of RestoreEntryVersion.
+func (mr *MockEntryServiceMockRecorder) RestoreEntryVersion(ctx, userID, key, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntryVersion", reflect.TypeOf((*MockEntryService)(nil).RestoreEntryVersion), ctx, userID, key, version)
+}
+
+// RevokeShare mocks base method.
+func (m *MockEntryService) RevokeShare(ctx context.Context, userID, key, recipientLogin string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeShare", ctx, userID, key, recipientLogin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeShare indicates an expected call of RevokeShare.
+func (mr *MockEntryServiceMockRecorder) RevokeShare(ctx, userID, key, recipientLogin any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockEntryService)(nil).GetEntry), ctx, userID, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeShare", reflect.TypeOf((*MockEntryService)(nil).RevokeShare), ctx, userID, key, recipientLogin)
 }
 
 // SetEntry mocks base method.
-func (m *MockEntryService) SetEntry(ctx context.Context, userID string, md entry.Metadata, overwrite bool) (chan<- entry.UploadChunk, <-chan entry.SetEntryResult, error) {
+func (m *MockEntryService) SetEntry(ctx context.Context, userID string, md entry.Metadata, overwrite bool, expectedVersion int64) (chan<- entry.UploadChunk, <-chan entry.SetEntryResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetEntry", ctx, userID, md, overwrite)
+	ret := m.ctrl.Call(m, "SetEntry", ctx, userID, md, overwrite, expectedVersion)
 	ret0, _ := ret[0].(chan<- entry.UploadChunk)
 	ret1, _ := ret[1].(<-chan entry.SetEntryResult)
 	ret2, _ := ret[2].(error)
@@ -84,7 +475,52 @@ func (m *MockEntryService) SetEntry(ctx context.Context, userID string, md entry
 }
 
 // SetEntry indicates an expected call of SetEntry.
-func (mr *MockEntryServiceMockRecorder) SetEntry(ctx, userID, md, overwrite any) *gomock.Call {
+func (mr *MockEntryServiceMockRecorder) SetEntry(ctx, userID, md, overwrite, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntry", reflect.TypeOf((*MockEntryService)(nil).SetEntry), ctx, userID, md, overwrite, expectedVersion)
+}
+
+// SetKeyPair mocks base method.
+func (m *MockEntryService) SetKeyPair(ctx context.Context, userID string, pair entry.KeyPair) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeyPair", ctx, userID, pair)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKeyPair indicates an expected call of SetKeyPair.
+func (mr *MockEntryServiceMockRecorder) SetKeyPair(ctx, userID, pair any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyPair", reflect.TypeOf((*MockEntryService)(nil).SetKeyPair), ctx, userID, pair)
+}
+
+// ShareEntry mocks base method.
+func (m *MockEntryService) ShareEntry(ctx context.Context, userID string, share entry.Share) (chan<- entry.UploadChunk, <-chan entry.ShareResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareEntry", ctx, userID, share)
+	ret0, _ := ret[0].(chan<- entry.UploadChunk)
+	ret1, _ := ret[1].(<-chan entry.ShareResult)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ShareEntry indicates an expected call of ShareEntry.
+func (mr *MockEntryServiceMockRecorder) ShareEntry(ctx, userID, share any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareEntry", reflect.TypeOf((*MockEntryService)(nil).ShareEntry), ctx, userID, share)
+}
+
+// WatchEntries mocks base method.
+func (m *MockEntryService) WatchEntries(ctx context.Context, userID string) (<-chan entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchEntries", ctx, userID)
+	ret0, _ := ret[0].(<-chan entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchEntries indicates an expected call of WatchEntries.
+func (mr *MockEntryServiceMockRecorder) WatchEntries(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntry", reflect.TypeOf((*MockEntryService)(nil).SetEntry), ctx, userID, md, overwrite)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchEntries", reflect.TypeOf((*MockEntryService)(nil).WatchEntries), ctx, userID)
 }
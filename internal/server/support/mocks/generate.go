@@ -9,3 +9,7 @@ package mocks
 //go:generate mockgen -destination=./entry_service_mock.go -package=mocks -mock_names Service=MockEntryService github.com/kuvalkin/gophkeeper/internal/server/service/entry Service
 //go:generate mockgen -destination=./bidi_stream_mock.go -package=mocks google.golang.org/grpc BidiStreamingServer
 //go:generate mockgen -destination=./server_stream_mock.go -package=mocks google.golang.org/grpc ServerStreamingServer
+//go:generate mockgen -destination=./client_stream_mock.go -package=mocks google.golang.org/grpc ClientStreamingServer
+//go:generate mockgen -destination=./read_seek_closer_mock.go -package=mocks io ReadSeekCloser
+//go:generate mockgen -destination=./vault_service_mock.go -package=mocks -mock_names Service=MockVaultService github.com/kuvalkin/gophkeeper/internal/server/service/vault Service
+//go:generate mockgen -destination=./vault_repository_mock.go -package=mocks -mock_names Repository=MockVaultRepository github.com/kuvalkin/gophkeeper/internal/server/service/vault Repository
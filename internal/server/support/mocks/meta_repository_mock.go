@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entry "github.com/kuvalkin/gophkeeper/internal/server/service/entry"
 	gomock "go.uber.org/mock/gomock"
@@ -41,18 +42,186 @@ func (m *MockMetadataRepository) EXPECT() *MockMetadataRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CommitUpload mocks base method.
+func (m *MockMetadataRepository) CommitUpload(ctx context.Context, userID, uploadID string, md entry.Metadata, expectedVersion int64, keepVersion bool) (entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitUpload", ctx, userID, uploadID, md, expectedVersion, keepVersion)
+	ret0, _ := ret[0].(entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitUpload indicates an expected call of CommitUpload.
+func (mr *MockMetadataRepositoryMockRecorder) CommitUpload(ctx, userID, uploadID, md, expectedVersion, keepVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitUpload", reflect.TypeOf((*MockMetadataRepository)(nil).CommitUpload), ctx, userID, uploadID, md, expectedVersion, keepVersion)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockMetadataRepository) CreateUploadSession(ctx context.Context, userID string, session entry.UploadSession) (entry.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", ctx, userID, session)
+	ret0, _ := ret[0].(entry.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockMetadataRepositoryMockRecorder) CreateUploadSession(ctx, userID, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockMetadataRepository)(nil).CreateUploadSession), ctx, userID, session)
+}
+
 // DeleteMetadata mocks base method.
-func (m *MockMetadataRepository) DeleteMetadata(ctx context.Context, userID, key string) error {
+func (m *MockMetadataRepository) DeleteMetadata(ctx context.Context, userID, key string, expectedVersion int64) (entry.Change, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMetadata", ctx, userID, key, expectedVersion)
+	ret0, _ := ret[0].(entry.Change)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteMetadata indicates an expected call of DeleteMetadata.
+func (mr *MockMetadataRepositoryMockRecorder) DeleteMetadata(ctx, userID, key, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteMetadata), ctx, userID, key, expectedVersion)
+}
+
+// DeleteMetadataBatch mocks base method.
+func (m *MockMetadataRepository) DeleteMetadataBatch(ctx context.Context, userID string, items []entry.DeleteItem) ([]entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMetadataBatch", ctx, userID, items)
+	ret0, _ := ret[0].([]entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMetadataBatch indicates an expected call of DeleteMetadataBatch.
+func (mr *MockMetadataRepositoryMockRecorder) DeleteMetadataBatch(ctx, userID, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetadataBatch", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteMetadataBatch), ctx, userID, items)
+}
+
+// DeleteOwnerMetadata mocks base method.
+func (m *MockMetadataRepository) DeleteOwnerMetadata(ctx context.Context, ownerID string) ([]entry.BlobRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOwnerMetadata", ctx, ownerID)
+	ret0, _ := ret[0].([]entry.BlobRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOwnerMetadata indicates an expected call of DeleteOwnerMetadata.
+func (mr *MockMetadataRepositoryMockRecorder) DeleteOwnerMetadata(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwnerMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteOwnerMetadata), ctx, ownerID)
+}
+
+// DeleteShare mocks base method.
+func (m *MockMetadataRepository) DeleteShare(ctx context.Context, ownerID, recipientID, key string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMetadata", ctx, userID, key)
+	ret := m.ctrl.Call(m, "DeleteShare", ctx, ownerID, recipientID, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteShare indicates an expected call of DeleteShare.
+func (mr *MockMetadataRepositoryMockRecorder) DeleteShare(ctx, ownerID, recipientID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShare", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteShare), ctx, ownerID, recipientID, key)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockMetadataRepository) DeleteUploadSession(ctx context.Context, userID, uploadID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadSession", ctx, userID, uploadID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteMetadata indicates an expected call of DeleteMetadata.
-func (mr *MockMetadataRepositoryMockRecorder) DeleteMetadata(ctx, userID, key any) *gomock.Call {
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockMetadataRepositoryMockRecorder) DeleteUploadSession(ctx, userID, uploadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteUploadSession), ctx, userID, uploadID)
+}
+
+// DeleteVersion mocks base method.
+func (m *MockMetadataRepository) DeleteVersion(ctx context.Context, userID, key string, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVersion", ctx, userID, key, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVersion indicates an expected call of DeleteVersion.
+func (mr *MockMetadataRepositoryMockRecorder) DeleteVersion(ctx, userID, key, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVersion", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteVersion), ctx, userID, key, version)
+}
+
+// FindPublicKey mocks base method.
+func (m *MockMetadataRepository) FindPublicKey(ctx context.Context, login string) (entry.KeyPair, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPublicKey", ctx, login)
+	ret0, _ := ret[0].(entry.KeyPair)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPublicKey indicates an expected call of FindPublicKey.
+func (mr *MockMetadataRepositoryMockRecorder) FindPublicKey(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPublicKey", reflect.TypeOf((*MockMetadataRepository)(nil).FindPublicKey), ctx, login)
+}
+
+// FindShare mocks base method.
+func (m *MockMetadataRepository) FindShare(ctx context.Context, ownerID, recipientLogin, key string) (entry.Share, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindShare", ctx, ownerID, recipientLogin, key)
+	ret0, _ := ret[0].(entry.Share)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindShare indicates an expected call of FindShare.
+func (mr *MockMetadataRepositoryMockRecorder) FindShare(ctx, ownerID, recipientLogin, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindShare", reflect.TypeOf((*MockMetadataRepository)(nil).FindShare), ctx, ownerID, recipientLogin, key)
+}
+
+// GetKeyPair mocks base method.
+func (m *MockMetadataRepository) GetKeyPair(ctx context.Context, userID string) (entry.KeyPair, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyPair", ctx, userID)
+	ret0, _ := ret[0].(entry.KeyPair)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetKeyPair indicates an expected call of GetKeyPair.
+func (mr *MockMetadataRepositoryMockRecorder) GetKeyPair(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPair", reflect.TypeOf((*MockMetadataRepository)(nil).GetKeyPair), ctx, userID)
+}
+
+// GetLatestCursor mocks base method.
+func (m *MockMetadataRepository) GetLatestCursor(ctx context.Context, userID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestCursor", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestCursor indicates an expected call of GetLatestCursor.
+func (mr *MockMetadataRepositoryMockRecorder) GetLatestCursor(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).DeleteMetadata), ctx, userID, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestCursor", reflect.TypeOf((*MockMetadataRepository)(nil).GetLatestCursor), ctx, userID)
 }
 
 // GetMetadata mocks base method.
@@ -71,16 +240,365 @@ func (mr *MockMetadataRepositoryMockRecorder) GetMetadata(ctx, userID, key any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).GetMetadata), ctx, userID, key)
 }
 
-// SetMetadata mocks base method.
-func (m *MockMetadataRepository) SetMetadata(ctx context.Context, userID string, md entry.Metadata) error {
+// GetMetadataBatch mocks base method.
+func (m *MockMetadataRepository) GetMetadataBatch(ctx context.Context, userID string, keys []string) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetadataBatch", ctx, userID, keys)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetadataBatch indicates an expected call of GetMetadataBatch.
+func (mr *MockMetadataRepositoryMockRecorder) GetMetadataBatch(ctx, userID, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetadataBatch", reflect.TypeOf((*MockMetadataRepository)(nil).GetMetadataBatch), ctx, userID, keys)
+}
+
+// GetShare mocks base method.
+func (m *MockMetadataRepository) GetShare(ctx context.Context, shareID string) (entry.Share, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShare", ctx, shareID)
+	ret0, _ := ret[0].(entry.Share)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetShare indicates an expected call of GetShare.
+func (mr *MockMetadataRepositoryMockRecorder) GetShare(ctx, shareID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShare", reflect.TypeOf((*MockMetadataRepository)(nil).GetShare), ctx, shareID)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockMetadataRepository) GetUploadSession(ctx context.Context, userID, uploadID string) (entry.UploadSession, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", ctx, userID, uploadID)
+	ret0, _ := ret[0].(entry.UploadSession)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockMetadataRepositoryMockRecorder) GetUploadSession(ctx, userID, uploadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockMetadataRepository)(nil).GetUploadSession), ctx, userID, uploadID)
+}
+
+// GetUsage mocks base method.
+func (m *MockMetadataRepository) GetUsage(ctx context.Context, userID string) (entry.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx, userID)
+	ret0, _ := ret[0].(entry.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockMetadataRepositoryMockRecorder) GetUsage(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockMetadataRepository)(nil).GetUsage), ctx, userID)
+}
+
+// GetVersion mocks base method.
+func (m *MockMetadataRepository) GetVersion(ctx context.Context, userID, key string, version int64) (entry.Metadata, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, userID, key, version)
+	ret0, _ := ret[0].(entry.Metadata)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockMetadataRepositoryMockRecorder) GetVersion(ctx, userID, key, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockMetadataRepository)(nil).GetVersion), ctx, userID, key, version)
+}
+
+// LeaseUploadSession mocks base method.
+func (m *MockMetadataRepository) LeaseUploadSession(ctx context.Context, userID, uploadID, token string, period time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseUploadSession", ctx, userID, uploadID, token, period)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaseUploadSession indicates an expected call of LeaseUploadSession.
+func (mr *MockMetadataRepositoryMockRecorder) LeaseUploadSession(ctx, userID, uploadID, token, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseUploadSession", reflect.TypeOf((*MockMetadataRepository)(nil).LeaseUploadSession), ctx, userID, uploadID, token, period)
+}
+
+// ListBlobRefs mocks base method.
+func (m *MockMetadataRepository) ListBlobRefs(ctx context.Context) ([]entry.BlobRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlobRefs", ctx)
+	ret0, _ := ret[0].([]entry.BlobRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlobRefs indicates an expected call of ListBlobRefs.
+func (mr *MockMetadataRepositoryMockRecorder) ListBlobRefs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlobRefs", reflect.TypeOf((*MockMetadataRepository)(nil).ListBlobRefs), ctx)
+}
+
+// ListChanges mocks base method.
+func (m *MockMetadataRepository) ListChanges(ctx context.Context, userID string, after int64, limit int) ([]entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChanges", ctx, userID, after, limit)
+	ret0, _ := ret[0].([]entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChanges indicates an expected call of ListChanges.
+func (mr *MockMetadataRepositoryMockRecorder) ListChanges(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChanges", reflect.TypeOf((*MockMetadataRepository)(nil).ListChanges), ctx, userID, after, limit)
+}
+
+// ListMetadata mocks base method.
+func (m *MockMetadataRepository) ListMetadata(ctx context.Context, userID string, opts entry.ListOptions) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetadata", ctx, userID, opts)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMetadata indicates an expected call of ListMetadata.
+func (mr *MockMetadataRepositoryMockRecorder) ListMetadata(ctx, userID, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).ListMetadata), ctx, userID, opts)
+}
+
+// ListShares mocks base method.
+func (m *MockMetadataRepository) ListShares(ctx context.Context, userID string, incoming bool) ([]entry.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShares", ctx, userID, incoming)
+	ret0, _ := ret[0].([]entry.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShares indicates an expected call of ListShares.
+func (mr *MockMetadataRepositoryMockRecorder) ListShares(ctx, userID, incoming any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShares", reflect.TypeOf((*MockMetadataRepository)(nil).ListShares), ctx, userID, incoming)
+}
+
+// ListTrash mocks base method.
+func (m *MockMetadataRepository) ListTrash(ctx context.Context, userID string) ([]entry.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, userID)
+	ret0, _ := ret[0].([]entry.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockMetadataRepositoryMockRecorder) ListTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockMetadataRepository)(nil).ListTrash), ctx, userID)
+}
+
+// ListUsage mocks base method.
+func (m *MockMetadataRepository) ListUsage(ctx context.Context) (map[string]entry.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsage", ctx)
+	ret0, _ := ret[0].(map[string]entry.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsage indicates an expected call of ListUsage.
+func (mr *MockMetadataRepositoryMockRecorder) ListUsage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsage", reflect.TypeOf((*MockMetadataRepository)(nil).ListUsage), ctx)
+}
+
+// ListVersions mocks base method.
+func (m *MockMetadataRepository) ListVersions(ctx context.Context, userID, key string) ([]entry.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, userID, key)
+	ret0, _ := ret[0].([]entry.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockMetadataRepositoryMockRecorder) ListVersions(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockMetadataRepository)(nil).ListVersions), ctx, userID, key)
+}
+
+// PruneVersions mocks base method.
+func (m *MockMetadataRepository) PruneVersions(ctx context.Context, userID, key string, keep int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneVersions", ctx, userID, key, keep)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneVersions indicates an expected call of PruneVersions.
+func (mr *MockMetadataRepositoryMockRecorder) PruneVersions(ctx, userID, key, keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneVersions", reflect.TypeOf((*MockMetadataRepository)(nil).PruneVersions), ctx, userID, key, keep)
+}
+
+// PurgeExpiredTrash mocks base method.
+func (m *MockMetadataRepository) PurgeExpiredTrash(ctx context.Context, before time.Time) ([]entry.BlobRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredTrash", ctx, before)
+	ret0, _ := ret[0].([]entry.BlobRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredTrash indicates an expected call of PurgeExpiredTrash.
+func (mr *MockMetadataRepositoryMockRecorder) PurgeExpiredTrash(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredTrash", reflect.TypeOf((*MockMetadataRepository)(nil).PurgeExpiredTrash), ctx, before)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockMetadataRepository) PurgeTrash(ctx context.Context, userID, key string, expectedVersion int64) ([]string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, userID, key, expectedVersion)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockMetadataRepositoryMockRecorder) PurgeTrash(ctx, userID, key, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockMetadataRepository)(nil).PurgeTrash), ctx, userID, key, expectedVersion)
+}
+
+// ReleaseUploadSession mocks base method.
+func (m *MockMetadataRepository) ReleaseUploadSession(ctx context.Context, userID, uploadID, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseUploadSession", ctx, userID, uploadID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseUploadSession indicates an expected call of ReleaseUploadSession.
+func (mr *MockMetadataRepositoryMockRecorder) ReleaseUploadSession(ctx, userID, uploadID, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseUploadSession", reflect.TypeOf((*MockMetadataRepository)(nil).ReleaseUploadSession), ctx, userID, uploadID, token)
+}
+
+// RenameMetadata mocks base method.
+func (m *MockMetadataRepository) RenameMetadata(ctx context.Context, userID, key, newKey, newName string, newEnvelope []byte, expectedVersion int64) (entry.RenameResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameMetadata", ctx, userID, key, newKey, newName, newEnvelope, expectedVersion)
+	ret0, _ := ret[0].(entry.RenameResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameMetadata indicates an expected call of RenameMetadata.
+func (mr *MockMetadataRepositoryMockRecorder) RenameMetadata(ctx, userID, key, newKey, newName, newEnvelope, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).RenameMetadata), ctx, userID, key, newKey, newName, newEnvelope, expectedVersion)
+}
+
+// RestoreMetadata mocks base method.
+func (m *MockMetadataRepository) RestoreMetadata(ctx context.Context, userID, key string) (entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreMetadata", ctx, userID, key)
+	ret0, _ := ret[0].(entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreMetadata indicates an expected call of RestoreMetadata.
+func (mr *MockMetadataRepositoryMockRecorder) RestoreMetadata(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).RestoreMetadata), ctx, userID, key)
+}
+
+// SetKeyPair mocks base method.
+func (m *MockMetadataRepository) SetKeyPair(ctx context.Context, userID string, pair entry.KeyPair) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetMetadata", ctx, userID, md)
+	ret := m.ctrl.Call(m, "SetKeyPair", ctx, userID, pair)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// SetKeyPair indicates an expected call of SetKeyPair.
+func (mr *MockMetadataRepositoryMockRecorder) SetKeyPair(ctx, userID, pair any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyPair", reflect.TypeOf((*MockMetadataRepository)(nil).SetKeyPair), ctx, userID, pair)
+}
+
+// SetMetadata mocks base method.
+func (m *MockMetadataRepository) SetMetadata(ctx context.Context, userID string, md entry.Metadata, expectedVersion int64, keepVersion bool) (entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMetadata", ctx, userID, md, expectedVersion, keepVersion)
+	ret0, _ := ret[0].(entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // SetMetadata indicates an expected call of SetMetadata.
-func (mr *MockMetadataRepositoryMockRecorder) SetMetadata(ctx, userID, md any) *gomock.Call {
+func (mr *MockMetadataRepositoryMockRecorder) SetMetadata(ctx, userID, md, expectedVersion, keepVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).SetMetadata), ctx, userID, md, expectedVersion, keepVersion)
+}
+
+// SetShare mocks base method.
+func (m *MockMetadataRepository) SetShare(ctx context.Context, share entry.Share) (entry.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShare", ctx, share)
+	ret0, _ := ret[0].(entry.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetShare indicates an expected call of SetShare.
+func (mr *MockMetadataRepositoryMockRecorder) SetShare(ctx, share any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShare", reflect.TypeOf((*MockMetadataRepository)(nil).SetShare), ctx, share)
+}
+
+// TrashMetadata mocks base method.
+func (m *MockMetadataRepository) TrashMetadata(ctx context.Context, userID, key string, expectedVersion int64) (entry.Change, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashMetadata", ctx, userID, key, expectedVersion)
+	ret0, _ := ret[0].(entry.Change)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TrashMetadata indicates an expected call of TrashMetadata.
+func (mr *MockMetadataRepositoryMockRecorder) TrashMetadata(ctx, userID, key, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).TrashMetadata), ctx, userID, key, expectedVersion)
+}
+
+// TrashMetadataBatch mocks base method.
+func (m *MockMetadataRepository) TrashMetadataBatch(ctx context.Context, userID string, items []entry.DeleteItem) ([]entry.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashMetadataBatch", ctx, userID, items)
+	ret0, _ := ret[0].([]entry.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrashMetadataBatch indicates an expected call of TrashMetadataBatch.
+func (mr *MockMetadataRepositoryMockRecorder) TrashMetadataBatch(ctx, userID, items any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMetadata", reflect.TypeOf((*MockMetadataRepository)(nil).SetMetadata), ctx, userID, md)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashMetadataBatch", reflect.TypeOf((*MockMetadataRepository)(nil).TrashMetadataBatch), ctx, userID, items)
 }
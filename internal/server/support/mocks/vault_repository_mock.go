@@ -0,0 +1,319 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/internal/server/service/vault (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_repository_mock.go -package=mocks -mock_names Repository=MockVaultRepository github.com/kuvalkin/gophkeeper/internal/server/service/vault Repository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entry "github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	vault "github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVaultRepository is a mock of Repository interface.
+type MockVaultRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultRepositoryMockRecorder is the mock recorder for MockVaultRepository.
+type MockVaultRepositoryMockRecorder struct {
+	mock *MockVaultRepository
+}
+
+// NewMockVaultRepository creates a new mock instance.
+func NewMockVaultRepository(ctrl *gomock.Controller) *MockVaultRepository {
+	mock := &MockVaultRepository{ctrl: ctrl}
+	mock.recorder = &MockVaultRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultRepository) EXPECT() *MockVaultRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockVaultRepository) AddMember(ctx context.Context, vaultID string, member vault.Member) (vault.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, vaultID, member)
+	ret0, _ := ret[0].(vault.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockVaultRepositoryMockRecorder) AddMember(ctx, vaultID, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockVaultRepository)(nil).AddMember), ctx, vaultID, member)
+}
+
+// CountPersonalVaults mocks base method.
+func (m *MockVaultRepository) CountPersonalVaults(ctx context.Context, userID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPersonalVaults", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPersonalVaults indicates an expected call of CountPersonalVaults.
+func (mr *MockVaultRepositoryMockRecorder) CountPersonalVaults(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPersonalVaults", reflect.TypeOf((*MockVaultRepository)(nil).CountPersonalVaults), ctx, userID)
+}
+
+// CreateEmergencyGrant mocks base method.
+func (m *MockVaultRepository) CreateEmergencyGrant(ctx context.Context, userID, vaultID string, grant vault.EmergencyGrant) (vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmergencyGrant", ctx, userID, vaultID, grant)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmergencyGrant indicates an expected call of CreateEmergencyGrant.
+func (mr *MockVaultRepositoryMockRecorder) CreateEmergencyGrant(ctx, userID, vaultID, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmergencyGrant", reflect.TypeOf((*MockVaultRepository)(nil).CreateEmergencyGrant), ctx, userID, vaultID, grant)
+}
+
+// CreateVault mocks base method.
+func (m *MockVaultRepository) CreateVault(ctx context.Context, userID, name string, personal bool, wrappedKey []byte) (vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVault", ctx, userID, name, personal, wrappedKey)
+	ret0, _ := ret[0].(vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVault indicates an expected call of CreateVault.
+func (mr *MockVaultRepositoryMockRecorder) CreateVault(ctx, userID, name, personal, wrappedKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVault", reflect.TypeOf((*MockVaultRepository)(nil).CreateVault), ctx, userID, name, personal, wrappedKey)
+}
+
+// DeleteEmergencyGrant mocks base method.
+func (m *MockVaultRepository) DeleteEmergencyGrant(ctx context.Context, vaultID, login string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEmergencyGrant", ctx, vaultID, login)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEmergencyGrant indicates an expected call of DeleteEmergencyGrant.
+func (mr *MockVaultRepositoryMockRecorder) DeleteEmergencyGrant(ctx, vaultID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmergencyGrant", reflect.TypeOf((*MockVaultRepository)(nil).DeleteEmergencyGrant), ctx, vaultID, login)
+}
+
+// DeleteOwnerContact mocks base method.
+func (m *MockVaultRepository) DeleteOwnerContact(ctx context.Context, ownerID, login string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOwnerContact", ctx, ownerID, login)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOwnerContact indicates an expected call of DeleteOwnerContact.
+func (mr *MockVaultRepositoryMockRecorder) DeleteOwnerContact(ctx, ownerID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwnerContact", reflect.TypeOf((*MockVaultRepository)(nil).DeleteOwnerContact), ctx, ownerID, login)
+}
+
+// DeleteVault mocks base method.
+func (m *MockVaultRepository) DeleteVault(ctx context.Context, vaultID string) ([]entry.BlobRef, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVault", ctx, vaultID)
+	ret0, _ := ret[0].([]entry.BlobRef)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteVault indicates an expected call of DeleteVault.
+func (mr *MockVaultRepositoryMockRecorder) DeleteVault(ctx, vaultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVault", reflect.TypeOf((*MockVaultRepository)(nil).DeleteVault), ctx, vaultID)
+}
+
+// GetEmergencyGrant mocks base method.
+func (m *MockVaultRepository) GetEmergencyGrant(ctx context.Context, vaultID, contactID string) (vault.EmergencyGrant, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmergencyGrant", ctx, vaultID, contactID)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEmergencyGrant indicates an expected call of GetEmergencyGrant.
+func (mr *MockVaultRepositoryMockRecorder) GetEmergencyGrant(ctx, vaultID, contactID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmergencyGrant", reflect.TypeOf((*MockVaultRepository)(nil).GetEmergencyGrant), ctx, vaultID, contactID)
+}
+
+// GetRole mocks base method.
+func (m *MockVaultRepository) GetRole(ctx context.Context, vaultID, userID string) (vault.Role, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", ctx, vaultID, userID)
+	ret0, _ := ret[0].(vault.Role)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockVaultRepositoryMockRecorder) GetRole(ctx, vaultID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockVaultRepository)(nil).GetRole), ctx, vaultID, userID)
+}
+
+// GetVault mocks base method.
+func (m *MockVaultRepository) GetVault(ctx context.Context, vaultID, userID string) (vault.Vault, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVault", ctx, vaultID, userID)
+	ret0, _ := ret[0].(vault.Vault)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVault indicates an expected call of GetVault.
+func (mr *MockVaultRepositoryMockRecorder) GetVault(ctx, vaultID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVault", reflect.TypeOf((*MockVaultRepository)(nil).GetVault), ctx, vaultID, userID)
+}
+
+// ListEmergencyGrants mocks base method.
+func (m *MockVaultRepository) ListEmergencyGrants(ctx context.Context, userID string, incoming bool) ([]vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmergencyGrants", ctx, userID, incoming)
+	ret0, _ := ret[0].([]vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyGrants indicates an expected call of ListEmergencyGrants.
+func (mr *MockVaultRepositoryMockRecorder) ListEmergencyGrants(ctx, userID, incoming any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyGrants", reflect.TypeOf((*MockVaultRepository)(nil).ListEmergencyGrants), ctx, userID, incoming)
+}
+
+// ListMembers mocks base method.
+func (m *MockVaultRepository) ListMembers(ctx context.Context, vaultID string) ([]vault.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, vaultID)
+	ret0, _ := ret[0].([]vault.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockVaultRepositoryMockRecorder) ListMembers(ctx, vaultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockVaultRepository)(nil).ListMembers), ctx, vaultID)
+}
+
+// ListVaults mocks base method.
+func (m *MockVaultRepository) ListVaults(ctx context.Context, userID string) ([]vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVaults", ctx, userID)
+	ret0, _ := ret[0].([]vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVaults indicates an expected call of ListVaults.
+func (mr *MockVaultRepositoryMockRecorder) ListVaults(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVaults", reflect.TypeOf((*MockVaultRepository)(nil).ListVaults), ctx, userID)
+}
+
+// RejectEmergencyAccess mocks base method.
+func (m *MockVaultRepository) RejectEmergencyAccess(ctx context.Context, vaultID, login string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectEmergencyAccess", ctx, vaultID, login)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectEmergencyAccess indicates an expected call of RejectEmergencyAccess.
+func (mr *MockVaultRepositoryMockRecorder) RejectEmergencyAccess(ctx, vaultID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectEmergencyAccess", reflect.TypeOf((*MockVaultRepository)(nil).RejectEmergencyAccess), ctx, vaultID, login)
+}
+
+// ReleaseEmergencyGrant mocks base method.
+func (m *MockVaultRepository) ReleaseEmergencyGrant(ctx context.Context, vaultID, contactID string, availableBefore time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseEmergencyGrant", ctx, vaultID, contactID, availableBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseEmergencyGrant indicates an expected call of ReleaseEmergencyGrant.
+func (mr *MockVaultRepositoryMockRecorder) ReleaseEmergencyGrant(ctx, vaultID, contactID, availableBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEmergencyGrant", reflect.TypeOf((*MockVaultRepository)(nil).ReleaseEmergencyGrant), ctx, vaultID, contactID, availableBefore)
+}
+
+// RemoveMember mocks base method.
+func (m *MockVaultRepository) RemoveMember(ctx context.Context, vaultID, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, vaultID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockVaultRepositoryMockRecorder) RemoveMember(ctx, vaultID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockVaultRepository)(nil).RemoveMember), ctx, vaultID, userID)
+}
+
+// RenameVault mocks base method.
+func (m *MockVaultRepository) RenameVault(ctx context.Context, vaultID, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameVault", ctx, vaultID, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameVault indicates an expected call of RenameVault.
+func (mr *MockVaultRepositoryMockRecorder) RenameVault(ctx, vaultID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameVault", reflect.TypeOf((*MockVaultRepository)(nil).RenameVault), ctx, vaultID, name)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockVaultRepository) RequestEmergencyAccess(ctx context.Context, vaultID, contactID string) (vault.EmergencyGrant, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", ctx, vaultID, contactID)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockVaultRepositoryMockRecorder) RequestEmergencyAccess(ctx, vaultID, contactID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockVaultRepository)(nil).RequestEmergencyAccess), ctx, vaultID, contactID)
+}
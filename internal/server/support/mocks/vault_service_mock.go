@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kuvalkin/gophkeeper/internal/server/service/vault (interfaces: Service)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./vault_service_mock.go -package=mocks -mock_names Service=MockVaultService github.com/kuvalkin/gophkeeper/internal/server/service/vault Service
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	vault "github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVaultService is a mock of Service interface.
+type MockVaultService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockVaultServiceMockRecorder is the mock recorder for MockVaultService.
+type MockVaultServiceMockRecorder struct {
+	mock *MockVaultService
+}
+
+// NewMockVaultService creates a new mock instance.
+func NewMockVaultService(ctrl *gomock.Controller) *MockVaultService {
+	mock := &MockVaultService{ctrl: ctrl}
+	mock.recorder = &MockVaultServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultService) EXPECT() *MockVaultServiceMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockVaultService) AddMember(ctx context.Context, userID, vaultID string, member vault.Member) (vault.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, userID, vaultID, member)
+	ret0, _ := ret[0].(vault.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockVaultServiceMockRecorder) AddMember(ctx, userID, vaultID, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockVaultService)(nil).AddMember), ctx, userID, vaultID, member)
+}
+
+// Authorize mocks base method.
+func (m *MockVaultService) Authorize(ctx context.Context, userID, vaultID string, role vault.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, userID, vaultID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockVaultServiceMockRecorder) Authorize(ctx, userID, vaultID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockVaultService)(nil).Authorize), ctx, userID, vaultID, role)
+}
+
+// CreateVault mocks base method.
+func (m *MockVaultService) CreateVault(ctx context.Context, userID, name string, personal bool, wrappedKey []byte) (vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVault", ctx, userID, name, personal, wrappedKey)
+	ret0, _ := ret[0].(vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVault indicates an expected call of CreateVault.
+func (mr *MockVaultServiceMockRecorder) CreateVault(ctx, userID, name, personal, wrappedKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVault", reflect.TypeOf((*MockVaultService)(nil).CreateVault), ctx, userID, name, personal, wrappedKey)
+}
+
+// DeleteVault mocks base method.
+func (m *MockVaultService) DeleteVault(ctx context.Context, userID, vaultID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVault", ctx, userID, vaultID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVault indicates an expected call of DeleteVault.
+func (mr *MockVaultServiceMockRecorder) DeleteVault(ctx, userID, vaultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVault", reflect.TypeOf((*MockVaultService)(nil).DeleteVault), ctx, userID, vaultID)
+}
+
+// GrantEmergencyAccess mocks base method.
+func (m *MockVaultService) GrantEmergencyAccess(ctx context.Context, userID, vaultID string, grant vault.EmergencyGrant) (vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantEmergencyAccess", ctx, userID, vaultID, grant)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantEmergencyAccess indicates an expected call of GrantEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) GrantEmergencyAccess(ctx, userID, vaultID, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).GrantEmergencyAccess), ctx, userID, vaultID, grant)
+}
+
+// ListEmergencyGrants mocks base method.
+func (m *MockVaultService) ListEmergencyGrants(ctx context.Context, userID string, incoming bool) ([]vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmergencyGrants", ctx, userID, incoming)
+	ret0, _ := ret[0].([]vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyGrants indicates an expected call of ListEmergencyGrants.
+func (mr *MockVaultServiceMockRecorder) ListEmergencyGrants(ctx, userID, incoming any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyGrants", reflect.TypeOf((*MockVaultService)(nil).ListEmergencyGrants), ctx, userID, incoming)
+}
+
+// ListMembers mocks base method.
+func (m *MockVaultService) ListMembers(ctx context.Context, userID, vaultID string) ([]vault.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, userID, vaultID)
+	ret0, _ := ret[0].([]vault.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockVaultServiceMockRecorder) ListMembers(ctx, userID, vaultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockVaultService)(nil).ListMembers), ctx, userID, vaultID)
+}
+
+// ListVaults mocks base method.
+func (m *MockVaultService) ListVaults(ctx context.Context, userID string) ([]vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVaults", ctx, userID)
+	ret0, _ := ret[0].([]vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVaults indicates an expected call of ListVaults.
+func (mr *MockVaultServiceMockRecorder) ListVaults(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVaults", reflect.TypeOf((*MockVaultService)(nil).ListVaults), ctx, userID)
+}
+
+// OpenEmergencyAccess mocks base method.
+func (m *MockVaultService) OpenEmergencyAccess(ctx context.Context, userID, vaultID string) (vault.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenEmergencyAccess", ctx, userID, vaultID)
+	ret0, _ := ret[0].(vault.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenEmergencyAccess indicates an expected call of OpenEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) OpenEmergencyAccess(ctx, userID, vaultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).OpenEmergencyAccess), ctx, userID, vaultID)
+}
+
+// RejectEmergencyAccess mocks base method.
+func (m *MockVaultService) RejectEmergencyAccess(ctx context.Context, userID, vaultID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectEmergencyAccess", ctx, userID, vaultID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectEmergencyAccess indicates an expected call of RejectEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) RejectEmergencyAccess(ctx, userID, vaultID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).RejectEmergencyAccess), ctx, userID, vaultID, login)
+}
+
+// RemoveMember mocks base method.
+func (m *MockVaultService) RemoveMember(ctx context.Context, userID, vaultID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, userID, vaultID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockVaultServiceMockRecorder) RemoveMember(ctx, userID, vaultID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockVaultService)(nil).RemoveMember), ctx, userID, vaultID, login)
+}
+
+// RenameVault mocks base method.
+func (m *MockVaultService) RenameVault(ctx context.Context, userID, vaultID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameVault", ctx, userID, vaultID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameVault indicates an expected call of RenameVault.
+func (mr *MockVaultServiceMockRecorder) RenameVault(ctx, userID, vaultID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameVault", reflect.TypeOf((*MockVaultService)(nil).RenameVault), ctx, userID, vaultID, name)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockVaultService) RequestEmergencyAccess(ctx context.Context, userID, vaultID string) (vault.EmergencyGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", ctx, userID, vaultID)
+	ret0, _ := ret[0].(vault.EmergencyGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) RequestEmergencyAccess(ctx, userID, vaultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).RequestEmergencyAccess), ctx, userID, vaultID)
+}
+
+// RevokeEmergencyAccess mocks base method.
+func (m *MockVaultService) RevokeEmergencyAccess(ctx context.Context, userID, vaultID, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeEmergencyAccess", ctx, userID, vaultID, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeEmergencyAccess indicates an expected call of RevokeEmergencyAccess.
+func (mr *MockVaultServiceMockRecorder) RevokeEmergencyAccess(ctx, userID, vaultID, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeEmergencyAccess", reflect.TypeOf((*MockVaultService)(nil).RevokeEmergencyAccess), ctx, userID, vaultID, login)
+}
@@ -18,25 +18,29 @@ import (
 
 	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
 	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
 	authServer "github.com/kuvalkin/gophkeeper/internal/server/transport/servers/auth"
 	entryServer "github.com/kuvalkin/gophkeeper/internal/server/transport/servers/entry"
+	vaultServer "github.com/kuvalkin/gophkeeper/internal/server/transport/servers/vault"
 	"github.com/kuvalkin/gophkeeper/internal/support/log"
 	authpb "github.com/kuvalkin/gophkeeper/pkg/proto/auth/v1"
 	entypb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+	vaultpb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
 )
 
-// Services encapsulates the user and entry services required by the gRPC server.
+// Services encapsulates the user, entry and vault services required by the gRPC server.
 type Services struct {
 	User  user.Service  // User service for handling user-related operations.
 	Entry entry.Service // Entry service for handling entry-related operations.
+	Vault vault.Service // Vault service for handling vaults and the access to them.
 }
 
 // NewServer initializes and returns a new gRPC server configured with the provided services and chunk size.
 // It sets up middleware for logging, authentication, validation, and recovery.
 //
 // Parameters:
-//   - services: The Services struct containing the user and entry services.
+//   - services: The Services struct containing the user, entry and vault services.
 //   - chunkSize: The size of chunks for entry service operations.
 //
 // Returns:
@@ -79,7 +83,8 @@ func NewServer(services Services, chunkSize int64) (*grpc.Server, error) {
 	)
 
 	authpb.RegisterAuthServiceServer(srv, authServer.New(services.User))
-	entypb.RegisterEntryServiceServer(srv, entryServer.New(services.Entry, chunkSize))
+	entypb.RegisterEntryServiceServer(srv, entryServer.New(services.Entry, services.Vault, chunkSize))
+	vaultpb.RegisterVaultServiceServer(srv, vaultServer.New(services.Vault))
 
 	return srv, nil
 }
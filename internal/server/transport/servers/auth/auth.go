@@ -78,6 +78,10 @@ func (s *server) login(ctx context.Context, login string, password string) (stri
 			return "", status.Error(codes.Unauthenticated, err.Error())
 		}
 
+		if errors.Is(err, user.ErrUserDisabled) {
+			return "", status.Error(codes.PermissionDenied, err.Error())
+		}
+
 		return "", status.Error(codes.Internal, "internal error")
 	}
 
@@ -129,6 +129,18 @@ func TestAuth_Login(t *testing.T) {
 		require.Equal(t, codes.Unauthenticated, status.Code(err))
 	})
 
+	t.Run("user disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockUserService(ctrl)
+		service.EXPECT().LoginUser(ctx, "login", "password").Return("", userService.ErrUserDisabled)
+
+		server := auth.New(service)
+		_, err := server.Login(ctx, &pb.LoginRequest{Login: "login", Password: "password"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
 	t.Run("internal error", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -8,26 +8,30 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
-	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
 	"github.com/kuvalkin/gophkeeper/internal/support/log"
 	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 	pb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
 )
 
 // New creates a new instance of the EntryServiceServer.
-// It takes an entry.Service implementation and a chunk size for streaming data.
+// It takes an entry.Service implementation, a vault.Service implementation to check the access to the vaults,
+// and a chunk size for streaming data.
 // Returns an implementation of pb.EntryServiceServer.
-func New(service entry.Service, chunkSize int64) pb.EntryServiceServer {
+func New(service entry.Service, vaults vault.Service, chunkSize int64) pb.EntryServiceServer {
 	return &server{
 		service:   service,
+		vaults:    vaults,
 		chunkSize: chunkSize,
 		log:       log.Logger().Named("server.entry"),
 	}
@@ -36,22 +40,26 @@ func New(service entry.Service, chunkSize int64) pb.EntryServiceServer {
 type server struct {
 	pb.UnsafeEntryServiceServer
 	service   entry.Service
+	vaults    vault.Service
 	chunkSize int64 // Size of chunks for streaming data.
 	log       *zap.SugaredLogger
 }
 
 // GetEntry streams an entry's metadata and content to the client.
-// It retrieves the entry by key and streams the data in chunks.
+// It retrieves the entry by key and streams the data in chunks, only the requested range of it if it's set.
 // Returns an error if the operation fails.
 func (s *server) GetEntry(request *pb.GetEntryRequest, stream grpc.ServerStreamingServer[pb.Entry]) error {
-	tokenInfo, ok := auth.GetTokenInfo(stream.Context())
-	if !ok {
-		return status.Error(codes.Unauthenticated, "no token info")
+	ownerID, err := s.authorize(stream.Context(), vault.RoleViewer)
+	if err != nil {
+		return err
 	}
 
-	llog := s.log.WithLazy("userID", tokenInfo.UserID, "method", "GetEntry", "key", request.Key)
+	llog := s.log.WithLazy("ownerID", ownerID, "method", "GetEntry", "key", request.Key)
 
-	md, reader, ok, err := s.service.GetEntry(stream.Context(), tokenInfo.UserID, request.Key)
+	md, reader, ok, err := s.service.GetEntry(stream.Context(), ownerID, request.Key, request.Offset, request.Length)
+	if errors.Is(err, entry.ErrInvalidRange) {
+		return status.Error(codes.OutOfRange, "offset is greater than the content size")
+	}
 	if err != nil {
 		return status.Errorf(codes.Internal, "cant get entry")
 	}
@@ -59,19 +67,20 @@ func (s *server) GetEntry(request *pb.GetEntryRequest, stream grpc.ServerStreami
 		return status.Errorf(codes.NotFound, "entry not found")
 	}
 
-	err = stream.Send(&pb.Entry{
-		Key:   md.Key,
-		Name:  md.Name,
-		Notes: md.Notes,
-	})
+	return s.sendEntry(stream, md, reader, llog)
+}
+
+// sendEntry sends the metadata and then the content in chunks to the stream. The reader is closed afterwards.
+func (s *server) sendEntry(stream grpc.ServerStreamingServer[pb.Entry], md entry.Metadata, reader io.ReadCloser, llog *zap.SugaredLogger) error {
+	defer utils.CloseAndLogError(reader, llog)
+
+	err := stream.Send(toPbEntry(md))
 	if err != nil {
 		llog.Errorw("cant send metadata", "err", err)
 
 		return status.Error(codes.Internal, "cant send metadata")
 	}
 
-	defer utils.CloseAndLogError(reader, llog)
-
 	buf := make([]byte, s.chunkSize)
 	for {
 		n, err := reader.Read(buf)
@@ -102,12 +111,12 @@ func (s *server) GetEntry(request *pb.GetEntryRequest, stream grpc.ServerStreami
 // It receives metadata and content chunks from the client and stores them.
 // Returns an error if the operation fails.
 func (s *server) SetEntry(stream grpc.BidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse]) error {
-	tokenInfo, ok := auth.GetTokenInfo(stream.Context())
-	if !ok {
-		return status.Error(codes.Unauthenticated, "no token info")
+	ownerID, err := s.authorize(stream.Context(), vault.RoleEditor)
+	if err != nil {
+		return err
 	}
 
-	llog := s.log.WithLazy("userID", tokenInfo.UserID, "method", "UpdateEntry")
+	llog := s.log.WithLazy("ownerID", ownerID, "method", "UpdateEntry")
 
 	// get the metadata
 	request, err := stream.Recv()
@@ -120,15 +129,26 @@ func (s *server) SetEntry(stream grpc.BidiStreamingServer[pb.SetEntryRequest, pb
 
 		return status.Error(codes.Internal, "cant get metadata")
 	}
-	if request.Entry == nil || request.Entry.Key == "" || request.Entry.Name == "" {
+	if request.Entry == nil || request.Entry.Key == "" || (request.Entry.Name == "" && len(request.Entry.Envelope) == 0) {
 		return status.Error(codes.InvalidArgument, "metadata is empty")
 	}
+	if isReservedKey(request.Entry.Key) {
+		return status.Error(codes.InvalidArgument, "key is reserved")
+	}
 
-	uploadChan, resultChan, err := s.service.SetEntry(stream.Context(), tokenInfo.UserID, entry.Metadata{
-		Key:   request.Entry.Key,
-		Name:  request.Entry.Name,
-		Notes: request.Entry.Notes,
-	}, request.Overwrite)
+	uploadChan, resultChan, err := s.service.SetEntry(stream.Context(), ownerID, entry.Metadata{
+		Key:      request.Entry.Key,
+		Name:     request.Entry.Name,
+		Notes:    request.Entry.Notes,
+		Type:     request.Entry.Type,
+		Envelope: request.Entry.Envelope,
+	}, request.Overwrite, request.ExpectedVersion)
+	if errors.Is(err, entry.ErrConflict) {
+		return status.Error(codes.FailedPrecondition, "entry version mismatch")
+	}
+	if errors.Is(err, entry.ErrQuotaExceeded) {
+		return status.Error(codes.ResourceExhausted, "storage quota exceeded")
+	}
 	if err != nil && !errors.Is(err, entry.ErrEntryExists) {
 		return status.Error(codes.Internal, "cant set entry")
 	}
@@ -165,11 +185,19 @@ func (s *server) SetEntry(stream grpc.BidiStreamingServer[pb.SetEntryRequest, pb
 		llog.Debug("client sent overwrite signal")
 
 		// continue and overwrite
-		uploadChan, resultChan, err = s.service.SetEntry(stream.Context(), tokenInfo.UserID, entry.Metadata{
-			Key:   request.Entry.Key,
-			Name:  request.Entry.Name,
-			Notes: request.Entry.Notes,
-		}, true)
+		uploadChan, resultChan, err = s.service.SetEntry(stream.Context(), ownerID, entry.Metadata{
+			Key:      request.Entry.Key,
+			Name:     request.Entry.Name,
+			Notes:    request.Entry.Notes,
+			Type:     request.Entry.Type,
+			Envelope: request.Entry.Envelope,
+		}, true, 0)
+		if errors.Is(err, entry.ErrConflict) {
+			return status.Error(codes.FailedPrecondition, "entry version mismatch")
+		}
+		if errors.Is(err, entry.ErrQuotaExceeded) {
+			return status.Error(codes.ResourceExhausted, "storage quota exceeded")
+		}
 		if err != nil {
 			llog.Errorw("cant set entry with overwrite", "err", err)
 
@@ -191,7 +219,15 @@ func (s *server) SetEntry(stream grpc.BidiStreamingServer[pb.SetEntryRequest, pb
 
 	llog.Debug("metadata received, preparing to receive chunks")
 
-	go s.downloadContentChunks(stream.Context(), stream, uploadChan, llog.Named("upload"))
+	received := make(chan struct{})
+	go s.downloadContentChunks(stream.Context(), func() ([]byte, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		return req.Entry.GetContent(), nil
+	}, uploadChan, received, llog.Named("upload"))
 
 	select {
 	case <-stream.Context().Done():
@@ -199,30 +235,223 @@ func (s *server) SetEntry(stream grpc.BidiStreamingServer[pb.SetEntryRequest, pb
 
 	case result := <-resultChan:
 		if result.Err != nil {
-			if errors.Is(err, context.Canceled) {
+			if errors.Is(result.Err, context.Canceled) {
 				return status.Error(codes.Canceled, "client closed connection")
 			}
 
-			if errors.Is(err, entry.ErrUploadChunk) {
+			if errors.Is(result.Err, entry.ErrUploadChunk) {
 				return status.Error(codes.Internal, "cant upload chunk")
 			}
 
+			if errors.Is(result.Err, entry.ErrConflict) {
+				return status.Error(codes.FailedPrecondition, "entry was changed during the upload")
+			}
+
+			if errors.Is(result.Err, entry.ErrQuotaExceeded) {
+				return status.Error(codes.ResourceExhausted, "storage quota exceeded")
+			}
+
 			return status.Error(codes.Internal, "internal error during upload")
 		}
 
+		// the service succeeds only once the upload channel is closed, so this doesn't block,
+		// but the handler must not return while the stream is still read
+		<-received
+
 		return nil
 	}
 }
 
+// CreateUploadSession starts a resumable upload of an entry.
+// It requires authentication and returns AlreadyExists for an existing entry unless overwrite is set,
+// FailedPrecondition if the expected version is set and doesn't match,
+// and ResourceExhausted if the declared size would exceed the user's quota.
+func (s *server) CreateUploadSession(ctx context.Context, request *pb.CreateUploadSessionRequest) (*pb.UploadSession, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Entry == nil || request.Entry.Key == "" || (request.Entry.Name == "" && len(request.Entry.Envelope) == 0) {
+		return nil, status.Error(codes.InvalidArgument, "metadata is empty")
+	}
+	if isReservedKey(request.Entry.Key) {
+		return nil, status.Error(codes.InvalidArgument, "key is reserved")
+	}
+
+	session, err := s.service.CreateUploadSession(ctx, ownerID, entry.Metadata{
+		Key:      request.Entry.Key,
+		Name:     request.Entry.Name,
+		Notes:    request.Entry.Notes,
+		Type:     request.Entry.Type,
+		Envelope: request.Entry.Envelope,
+	}, request.Size, request.Overwrite, request.ExpectedVersion)
+	if err != nil {
+		if errors.Is(err, entry.ErrEntryExists) {
+			return nil, status.Error(codes.AlreadyExists, "entry already exists")
+		}
+
+		if errors.Is(err, entry.ErrConflict) {
+			return nil, status.Error(codes.FailedPrecondition, "entry version mismatch")
+		}
+
+		if errors.Is(err, entry.ErrQuotaExceeded) {
+			return nil, status.Error(codes.ResourceExhausted, "storage quota exceeded")
+		}
+
+		return nil, status.Error(codes.Internal, "cant create upload session")
+	}
+
+	return toPbUploadSession(session), nil
+}
+
+// GetUploadSession returns the upload session with its committed offset.
+// It requires authentication and returns NotFound if there is no such session.
+func (s *server) GetUploadSession(ctx context.Context, request *pb.GetUploadSessionRequest) (*pb.UploadSession, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	session, ok, err := s.service.GetUploadSession(ctx, ownerID, request.UploadId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant get upload session")
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "upload session not found")
+	}
+
+	return toPbUploadSession(session), nil
+}
+
+// UploadChunks receives content chunks of the upload session from the client and appends them to the uploaded content.
+// The first message identifies the session and the offset to continue from.
+// It responds with the committed offset. If an error occurs, the chunks received before it are kept.
+// OutOfRange is returned if more content than declared is uploaded.
+func (s *server) UploadChunks(stream grpc.ClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession]) error {
+	ownerID, err := s.authorize(stream.Context(), vault.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	llog := s.log.WithLazy("ownerID", ownerID, "method", "UploadChunks")
+
+	request, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.Canceled, "client closed connection")
+		}
+
+		llog.Errorw("cant get upload id", "err", err)
+
+		return status.Error(codes.Internal, "cant get upload id")
+	}
+	if request.UploadId == "" {
+		return status.Error(codes.InvalidArgument, "upload id is empty")
+	}
+
+	llog = llog.WithLazy("uploadID", request.UploadId)
+
+	uploadChan, resultChan, err := s.service.AppendUpload(stream.Context(), ownerID, request.UploadId, request.Offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, entry.ErrUploadNotFound):
+			return status.Error(codes.NotFound, "upload session not found")
+		case errors.Is(err, entry.ErrOffsetMismatch):
+			return status.Error(codes.OutOfRange, "offset doesn't match the committed one")
+		case errors.Is(err, entry.ErrUploadBusy):
+			return status.Error(codes.Aborted, "upload session is busy")
+		default:
+			return status.Error(codes.Internal, "cant continue upload")
+		}
+	}
+
+	received := make(chan struct{})
+	go s.downloadContentChunks(stream.Context(), func() ([]byte, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		return req.Content, nil
+	}, uploadChan, received, llog.Named("upload"))
+
+	select {
+	case <-stream.Context().Done():
+		return status.Error(codes.Canceled, "client closed connection")
+
+	case result := <-resultChan:
+		if result.Err != nil {
+			if errors.Is(result.Err, context.Canceled) {
+				return status.Error(codes.Canceled, "client closed connection")
+			}
+
+			if errors.Is(result.Err, entry.ErrUploadChunk) {
+				return status.Error(codes.Internal, "cant upload chunk")
+			}
+
+			if errors.Is(result.Err, entry.ErrSizeMismatch) {
+				return status.Error(codes.OutOfRange, "upload exceeds the declared size")
+			}
+
+			return status.Error(codes.Internal, "internal error during upload")
+		}
+
+		// the service succeeds only once the upload channel is closed, see SetEntry
+		<-received
+
+		err = stream.SendAndClose(toPbUploadSession(result.Session))
+		if err != nil {
+			llog.Errorw("cant send committed offset", "err", err)
+
+			return status.Error(codes.Internal, "cant send committed offset")
+		}
+
+		return nil
+	}
+}
+
+// FinalizeUpload stores the uploaded content as the entry.
+// It requires authentication and returns OutOfRange if the content isn't fully uploaded,
+// and FailedPrecondition if the entry was changed since the session was created.
+func (s *server) FinalizeUpload(ctx context.Context, request *pb.FinalizeUploadRequest) (*emptypb.Empty, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.FinalizeUpload(ctx, ownerID, request.UploadId)
+	if err != nil {
+		switch {
+		case errors.Is(err, entry.ErrUploadNotFound):
+			return nil, status.Error(codes.NotFound, "upload session not found")
+		case errors.Is(err, entry.ErrSizeMismatch):
+			return nil, status.Error(codes.OutOfRange, "upload is incomplete")
+		case errors.Is(err, entry.ErrConflict):
+			return nil, status.Error(codes.FailedPrecondition, "entry was changed during the upload")
+		case errors.Is(err, entry.ErrUploadBusy):
+			return nil, status.Error(codes.Aborted, "upload session is busy")
+		default:
+			return nil, status.Error(codes.Internal, "cant finalize upload")
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
 // DeleteEntry deletes an entry identified by its key.
 // It requires authentication and returns an error if the operation fails.
+// FailedPrecondition is returned if the expected version is set and doesn't match.
 func (s *server) DeleteEntry(ctx context.Context, request *pb.DeleteEntryRequest) (*emptypb.Empty, error) {
-	tokenInfo, ok := auth.GetTokenInfo(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "no token info")
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.service.DeleteEntry(ctx, tokenInfo.UserID, request.Key)
+	err = s.service.DeleteEntry(ctx, ownerID, request.Key, request.ExpectedVersion)
+	if errors.Is(err, entry.ErrConflict) {
+		return nil, status.Error(codes.FailedPrecondition, "entry version mismatch")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "cant delete entry")
 	}
@@ -230,8 +459,400 @@ func (s *server) DeleteEntry(ctx context.Context, request *pb.DeleteEntryRequest
 	return &emptypb.Empty{}, nil
 }
 
-// downloadContentChunks reads content chunks from the client and sends them to the upload channel.
-func (s *server) downloadContentChunks(ctx context.Context, stream grpc.BidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse], uploadChan chan<- entry.UploadChunk, llog *zap.SugaredLogger) {
+// RenameEntry moves an entry to a new key and name, keeping its content.
+// It requires authentication and returns InvalidArgument if the new key is empty or reserved, NotFound if there is no such entry,
+// AlreadyExists if the new key is taken, and FailedPrecondition if the expected version is set and doesn't match.
+func (s *server) RenameEntry(ctx context.Context, request *pb.RenameEntryRequest) (*emptypb.Empty, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.NewKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "new key is empty")
+	}
+	if isReservedKey(request.NewKey) {
+		return nil, status.Error(codes.InvalidArgument, "new key is reserved")
+	}
+	if request.NewName == "" && len(request.NewEnvelope) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "new name is empty")
+	}
+
+	err = s.service.RenameEntry(ctx, ownerID, request.Key, request.NewKey, request.NewName, request.NewEnvelope, request.ExpectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, entry.ErrEntryNotFound):
+			return nil, status.Error(codes.NotFound, "entry not found")
+		case errors.Is(err, entry.ErrEntryExists):
+			return nil, status.Error(codes.AlreadyExists, "entry with the new key already exists")
+		case errors.Is(err, entry.ErrConflict):
+			return nil, status.Error(codes.FailedPrecondition, "entry version mismatch")
+		default:
+			return nil, status.Error(codes.Internal, "cant rename entry")
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// BatchDeleteEntries deletes the entries in one transaction.
+// It requires authentication and returns FailedPrecondition, deleting nothing, if any expected version doesn't match.
+func (s *server) BatchDeleteEntries(ctx context.Context, request *pb.BatchDeleteEntriesRequest) (*emptypb.Empty, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]entry.DeleteItem, 0, len(request.Entries))
+	for _, e := range request.Entries {
+		items = append(items, entry.DeleteItem{Key: e.Key, ExpectedVersion: e.ExpectedVersion})
+	}
+
+	err = s.service.BatchDeleteEntries(ctx, ownerID, items)
+	if errors.Is(err, entry.ErrConflict) {
+		return nil, status.Error(codes.FailedPrecondition, "entry version mismatch")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant delete entries")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// BatchGetMetadata returns the entries with the given keys without their content.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) BatchGetMetadata(ctx context.Context, request *pb.BatchGetMetadataRequest) (*pb.BatchGetMetadataResponse, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	mds, err := s.service.BatchGetMetadata(ctx, ownerID, request.Keys)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant get metadata")
+	}
+
+	response := &pb.BatchGetMetadataResponse{
+		Entries: make([]*pb.Entry, 0, len(mds)),
+	}
+
+	for _, md := range mds {
+		response.Entries = append(response.Entries, toPbEntry(md))
+	}
+
+	return response, nil
+}
+
+// ListEntries returns a page of the user's entries without their content.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListEntries(ctx context.Context, request *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	mds, next, err := s.service.ListEntries(ctx, ownerID, request.PageToken, int(request.PageSize))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list entries")
+	}
+
+	response := &pb.ListEntriesResponse{
+		Entries:       make([]*pb.Entry, 0, len(mds)),
+		NextPageToken: next,
+	}
+
+	for _, md := range mds {
+		response.Entries = append(response.Entries, toPbEntry(md))
+	}
+
+	return response, nil
+}
+
+// ListChanges returns the latest changes of the user's entries made after the cursor, including deletions.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListChanges(ctx context.Context, request *pb.ListChangesRequest) (*pb.ListChangesResponse, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, next, hasMore, err := s.service.ListChanges(ctx, ownerID, request.SinceCursor, int(request.PageSize))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list changes")
+	}
+
+	response := &pb.ListChangesResponse{
+		Changes:    make([]*pb.Change, 0, len(changes)),
+		NextCursor: next,
+		HasMore:    hasMore,
+	}
+
+	for _, change := range changes {
+		response.Changes = append(response.Changes, toPbChange(change))
+	}
+
+	return response, nil
+}
+
+// WatchEntries streams changes of the user's entries to the client as they happen, until the client disconnects.
+// If the client doesn't keep up, the stream is ended with Aborted.
+func (s *server) WatchEntries(_ *pb.WatchEntriesRequest, stream grpc.ServerStreamingServer[pb.Change]) error {
+	ownerID, err := s.authorize(stream.Context(), vault.RoleViewer)
+	if err != nil {
+		return err
+	}
+
+	llog := s.log.WithLazy("ownerID", ownerID, "method", "WatchEntries")
+
+	changes, err := s.service.WatchEntries(stream.Context(), ownerID)
+	if err != nil {
+		return status.Error(codes.Internal, "cant watch entries")
+	}
+
+	for change := range changes {
+		err := stream.Send(toPbChange(change))
+		if err != nil {
+			llog.Errorw("cant send change", "err", err)
+
+			return status.Error(codes.Internal, "cant send change")
+		}
+	}
+
+	if stream.Context().Err() != nil {
+		return status.Error(codes.Canceled, "client closed connection")
+	}
+
+	llog.Warn("watcher was dropped")
+
+	return status.Error(codes.Aborted, "too many changes, use ListChanges to catch up")
+}
+
+// ListEntryVersions returns metadata of the kept previous versions of an entry, newest first.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListEntryVersions(ctx context.Context, request *pb.ListEntryVersionsRequest) (*pb.ListEntryVersionsResponse, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	mds, err := s.service.ListEntryVersions(ctx, ownerID, request.Key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list versions")
+	}
+
+	response := &pb.ListEntryVersionsResponse{
+		Versions: make([]*pb.Entry, 0, len(mds)),
+	}
+
+	for _, md := range mds {
+		response.Versions = append(response.Versions, toPbEntry(md))
+	}
+
+	return response, nil
+}
+
+// GetEntryVersion streams a previous version of an entry to the client, the same way as GetEntry does.
+// Returns an error if the operation fails.
+func (s *server) GetEntryVersion(request *pb.GetEntryVersionRequest, stream grpc.ServerStreamingServer[pb.Entry]) error {
+	ownerID, err := s.authorize(stream.Context(), vault.RoleViewer)
+	if err != nil {
+		return err
+	}
+
+	llog := s.log.WithLazy("ownerID", ownerID, "method", "GetEntryVersion", "key", request.Key, "version", request.Version)
+
+	md, reader, ok, err := s.service.GetEntryVersion(stream.Context(), ownerID, request.Key, request.Version)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cant get version")
+	}
+	if !ok {
+		return status.Errorf(codes.NotFound, "version not found")
+	}
+
+	return s.sendEntry(stream, md, reader, llog)
+}
+
+// RestoreEntryVersion makes a previous version of an entry the current one.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) RestoreEntryVersion(ctx context.Context, request *pb.RestoreEntryVersionRequest) (*emptypb.Empty, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.RestoreEntryVersion(ctx, ownerID, request.Key, request.Version)
+	if err != nil {
+		if errors.Is(err, entry.ErrVersionNotFound) {
+			return nil, status.Error(codes.NotFound, "version not found")
+		}
+
+		if errors.Is(err, entry.ErrConflict) {
+			return nil, status.Error(codes.FailedPrecondition, "entry was changed during the restore")
+		}
+
+		return nil, status.Error(codes.Internal, "cant restore version")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetUsage returns the storage used by the user along with the limits.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) GetUsage(ctx context.Context, _ *pb.GetUsageRequest) (*pb.Usage, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.service.GetUsage(ctx, ownerID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant get usage")
+	}
+
+	return &pb.Usage{
+		Bytes:      usage.Bytes,
+		Entries:    usage.Entries,
+		MaxBytes:   usage.MaxBytes,
+		MaxEntries: usage.MaxEntries,
+	}, nil
+}
+
+// ListTrash returns the user's deleted entries kept in the trash.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListTrash(ctx context.Context, _ *pb.ListTrashRequest) (*pb.ListTrashResponse, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.service.ListTrash(ctx, ownerID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list trash")
+	}
+
+	response := &pb.ListTrashResponse{
+		Entries: make([]*pb.TrashedEntry, 0, len(items)),
+	}
+
+	for _, item := range items {
+		response.Entries = append(response.Entries, &pb.TrashedEntry{
+			Entry:     toPbEntry(item.Metadata),
+			DeletedAt: timestamppb.New(item.DeletedAt),
+			ExpiresAt: timestamppb.New(item.ExpiresAt),
+		})
+	}
+
+	return response, nil
+}
+
+// RestoreEntry moves a deleted entry back from the trash.
+// It requires authentication and returns NotFound if the entry isn't in the trash,
+// AlreadyExists if an entry with the same key was stored since, and ResourceExhausted if the user has too many entries.
+func (s *server) RestoreEntry(ctx context.Context, request *pb.RestoreEntryRequest) (*emptypb.Empty, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.RestoreEntry(ctx, ownerID, request.Key)
+	if err != nil {
+		switch {
+		case errors.Is(err, entry.ErrEntryNotFound):
+			return nil, status.Error(codes.NotFound, "entry not found in trash")
+		case errors.Is(err, entry.ErrEntryExists):
+			return nil, status.Error(codes.AlreadyExists, "entry already exists")
+		case errors.Is(err, entry.ErrQuotaExceeded):
+			return nil, status.Error(codes.ResourceExhausted, "storage quota exceeded")
+		default:
+			return nil, status.Error(codes.Internal, "cant restore entry")
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// PurgeEntry deletes an entry from the trash for good.
+// It requires authentication and returns NotFound if the entry isn't in the trash.
+func (s *server) PurgeEntry(ctx context.Context, request *pb.PurgeEntryRequest) (*emptypb.Empty, error) {
+	ownerID, err := s.authorize(ctx, vault.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.service.PurgeEntry(ctx, ownerID, request.Key)
+	if err != nil {
+		if errors.Is(err, entry.ErrEntryNotFound) {
+			return nil, status.Error(codes.NotFound, "entry not found in trash")
+		}
+
+		return nil, status.Error(codes.Internal, "cant purge entry")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// toPbChange converts the change to the protobuf change.
+// reservedKeyPrefixes are the prefixes of the keys used by the server for its own records,
+// entries can't be written under them.
+var reservedKeyPrefixes = []string{"trash/", "versions/", "staging/", "uploads/"}
+
+func isReservedKey(key string) bool {
+	for _, prefix := range reservedKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toPbChange(change entry.Change) *pb.Change {
+	return &pb.Change{
+		Entry:     toPbEntry(change.Metadata),
+		Deleted:   change.Deleted,
+		ChangedAt: timestamppb.New(change.ChangedAt),
+		Cursor:    change.Cursor,
+	}
+}
+
+// toPbUploadSession converts the upload session to the protobuf one.
+func toPbUploadSession(session entry.UploadSession) *pb.UploadSession {
+	return &pb.UploadSession{
+		UploadId:        session.ID,
+		CommittedOffset: session.Offset,
+		Size:            session.Size,
+	}
+}
+
+// toPbEntry converts entry metadata to the protobuf entry without content.
+// Zero timestamps are left unset.
+func toPbEntry(md entry.Metadata) *pb.Entry {
+	e := &pb.Entry{
+		Key:      md.Key,
+		Name:     md.Name,
+		Notes:    md.Notes,
+		Type:     md.Type,
+		Size:     md.Size,
+		Version:  md.Version,
+		Checksum: md.Checksum,
+		Envelope: md.Envelope,
+	}
+
+	if !md.CreatedAt.IsZero() {
+		e.CreatedAt = timestamppb.New(md.CreatedAt)
+	}
+
+	if !md.UpdatedAt.IsZero() {
+		e.UpdatedAt = timestamppb.New(md.UpdatedAt)
+	}
+
+	return e
+}
+
+// downloadContentChunks reads content chunks from the client with recv and sends them to the upload channel.
+// The received channel is closed once it's done with the stream.
+func (s *server) downloadContentChunks(ctx context.Context, recv func() ([]byte, error), uploadChan chan<- entry.UploadChunk, received chan<- struct{}, llog *zap.SugaredLogger) {
+	defer close(received)
 	defer close(uploadChan)
 
 	for {
@@ -242,7 +863,7 @@ func (s *server) downloadContentChunks(ctx context.Context, stream grpc.BidiStre
 		default:
 			llog.Debug("waiting for chunk")
 
-			req, err := stream.Recv()
+			content, err := recv()
 			if errors.Is(err, io.EOF) {
 				llog.Debug("uploaded ended")
 
@@ -257,14 +878,29 @@ func (s *server) downloadContentChunks(ctx context.Context, stream grpc.BidiStre
 			if err != nil {
 				llog.Errorw("cant get chunk", "err", err)
 
-				uploadChan <- entry.UploadChunk{Err: fmt.Errorf("cant get chunk: %w", err)}
+				s.sendChunk(ctx, uploadChan, entry.UploadChunk{Err: fmt.Errorf("cant get chunk: %w", err)})
 
 				return
 			}
 
 			llog.Debug("uploading chunk")
-			uploadChan <- entry.UploadChunk{Content: req.Entry.Content}
+			if !s.sendChunk(ctx, uploadChan, entry.UploadChunk{Content: content}) {
+				llog.Debug("context done")
+
+				return
+			}
 			llog.Debug("chunk uploaded")
 		}
 	}
 }
+
+// sendChunk sends the chunk to the upload channel unless the context is done first,
+// which happens when the service stops reading the upload early, e.g. because the quota is exceeded.
+func (s *server) sendChunk(ctx context.Context, uploadChan chan<- entry.UploadChunk, chunk entry.UploadChunk) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case uploadChan <- chunk:
+		return true
+	}
+}
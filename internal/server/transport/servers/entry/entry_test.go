@@ -1,14 +1,17 @@
 package entry_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	gomock "go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	entryService "github.com/kuvalkin/gophkeeper/internal/server/service/entry"
 	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
@@ -37,11 +40,19 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().GetEntry(ctxWithToken, "user", "key").Return(
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		updatedAt := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(0), int64(0)).Return(
 			entryService.Metadata{
-				Key:   "key",
-				Name:  "name",
-				Notes: []byte("encrypted notes"),
+				Key:       "key",
+				Name:      "name",
+				Notes:     []byte("encrypted notes"),
+				Type:      "login",
+				Size:      17,
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt,
+				Checksum:  []byte("checksum"),
 			},
 			content,
 			true,
@@ -50,9 +61,14 @@ func TestServer_GetEntry(t *testing.T) {
 
 		// send metadata
 		stream.EXPECT().Send(&pb.Entry{
-			Key:   "key",
-			Name:  "name",
-			Notes: []byte("encrypted notes"),
+			Key:       "key",
+			Name:      "name",
+			Notes:     []byte("encrypted notes"),
+			Type:      "login",
+			Size:      17,
+			CreatedAt: timestamppb.New(createdAt),
+			UpdatedAt: timestamppb.New(updatedAt),
+			Checksum:  []byte("checksum"),
 		}).Return(nil)
 
 		// stream content
@@ -63,7 +79,7 @@ func TestServer_GetEntry(t *testing.T) {
 		content.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
 		content.EXPECT().Close().Return(nil)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -79,7 +95,7 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctx).AnyTimes()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -96,14 +112,14 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().GetEntry(ctxWithToken, "user", "key").Return(
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(0), int64(0)).Return(
 			entryService.Metadata{},
 			nil,
 			false,
 			errors.New("cant get entry"),
 		)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -111,6 +127,32 @@ func TestServer_GetEntry(t *testing.T) {
 		require.Equal(t, codes.Internal, status.Code(err))
 	})
 
+	t.Run("offset out of range", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Entry](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(100), int64(10)).Return(
+			entryService.Metadata{},
+			nil,
+			false,
+			entryService.ErrInvalidRange,
+		)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetEntry(&pb.GetEntryRequest{
+			Key:    "key",
+			Offset: 100,
+			Length: 10,
+		}, stream)
+		require.Error(t, err)
+		require.Equal(t, codes.OutOfRange, status.Code(err))
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -120,14 +162,14 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().GetEntry(ctxWithToken, "user", "key").Return(
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(0), int64(0)).Return(
 			entryService.Metadata{},
 			nil,
 			false,
 			nil,
 		)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -145,7 +187,7 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().GetEntry(ctxWithToken, "user", "key").Return(
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(0), int64(0)).Return(
 			entryService.Metadata{
 				Key:   "key",
 				Name:  "name",
@@ -162,8 +204,9 @@ func TestServer_GetEntry(t *testing.T) {
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
 		}).Return(errors.New("cant send metadata"))
+		content.EXPECT().Close().Return(nil)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -181,7 +224,7 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().GetEntry(ctxWithToken, "user", "key").Return(
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(0), int64(0)).Return(
 			entryService.Metadata{
 				Key:   "key",
 				Name:  "name",
@@ -203,7 +246,7 @@ func TestServer_GetEntry(t *testing.T) {
 		content.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, errors.New("cant read entry"))
 		content.EXPECT().Close().Return(nil)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -221,7 +264,7 @@ func TestServer_GetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().GetEntry(ctxWithToken, "user", "key").Return(
+		service.EXPECT().GetEntry(ctxWithToken, "user", "key", int64(0), int64(0)).Return(
 			entryService.Metadata{
 				Key:   "key",
 				Name:  "name",
@@ -246,7 +289,7 @@ func TestServer_GetEntry(t *testing.T) {
 		}).Return(errors.New("cant send entry"))
 		content.EXPECT().Close().Return(nil)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.GetEntry(&pb.GetEntryRequest{
 			Key: "key",
 		}, stream)
@@ -278,6 +321,7 @@ func TestServer_SetEntry(t *testing.T) {
 				Key:   "key",
 				Name:  "name",
 				Notes: []byte("encrypted notes"),
+				Type:  "login",
 			},
 		}, nil)
 
@@ -288,7 +332,8 @@ func TestServer_SetEntry(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
-		}, false).Return(uploadChan, resultChan, nil)
+			Type:  "login",
+		}, false, int64(0)).Return(uploadChan, resultChan, nil)
 
 		// send upload signal
 		stream.EXPECT().Send(&pb.SetEntryResponse{}).Return(nil)
@@ -309,7 +354,7 @@ func TestServer_SetEntry(t *testing.T) {
 			close(resultChan)
 		}()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.NoError(t, err)
 	})
@@ -323,7 +368,7 @@ func TestServer_SetEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctx).AnyTimes()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.Error(t, err)
 		require.Equal(t, codes.Unauthenticated, status.Code(err))
@@ -341,7 +386,7 @@ func TestServer_SetEntry(t *testing.T) {
 		// get metadata
 		stream.EXPECT().Recv().Return(nil, errors.New("cant get metadata"))
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.Error(t, err)
 		require.Equal(t, codes.Internal, status.Code(err))
@@ -359,7 +404,25 @@ func TestServer_SetEntry(t *testing.T) {
 		// get metadata
 		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{}, nil)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.SetEntry(stream)
+		require.Error(t, err)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("key is reserved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockBidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		// get metadata
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{Entry: &pb.Entry{Key: "trash/key", Name: "name"}}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.Error(t, err)
 		require.Equal(t, codes.InvalidArgument, status.Code(err))
@@ -388,14 +451,14 @@ func TestServer_SetEntry(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
-		}, false).Return(nil, nil, entryService.ErrEntryExists)
+		}, false, int64(0)).Return(nil, nil, entryService.ErrEntryExists)
 
 		stream.EXPECT().Send(&pb.SetEntryResponse{AlreadyExists: true}).Return(nil)
 
 		// client closed connection
 		stream.EXPECT().Recv().Return(nil, io.EOF)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.Error(t, err)
 		require.Equal(t, codes.Canceled, status.Code(err))
@@ -424,7 +487,7 @@ func TestServer_SetEntry(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
-		}, false).Return(nil, nil, entryService.ErrEntryExists)
+		}, false, int64(0)).Return(nil, nil, entryService.ErrEntryExists)
 
 		stream.EXPECT().Send(&pb.SetEntryResponse{AlreadyExists: true}).Return(nil)
 
@@ -438,7 +501,7 @@ func TestServer_SetEntry(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
-		}, true).Return(uploadChan, resultChan, nil)
+		}, true, int64(0)).Return(uploadChan, resultChan, nil)
 
 		// receive chunks
 		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
@@ -456,7 +519,7 @@ func TestServer_SetEntry(t *testing.T) {
 			close(resultChan)
 		}()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.NoError(t, err)
 	})
@@ -486,7 +549,7 @@ func TestServer_SetEntry(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
-		}, false).Return(uploadChan, resultChan, nil)
+		}, false, int64(0)).Return(uploadChan, resultChan, nil)
 
 		// send upload signal
 		stream.EXPECT().Send(&pb.SetEntryResponse{}).Return(nil)
@@ -497,7 +560,8 @@ func TestServer_SetEntry(t *testing.T) {
 				Content: []byte("encrypted content"),
 			},
 		}, nil)
-		stream.EXPECT().Recv().Return(nil, io.EOF)
+		// the handler returns on the error right away, without waiting for the end of the upload
+		stream.EXPECT().Recv().Return(nil, io.EOF).MaxTimes(1)
 
 		go func() {
 			uploaded := <-uploadChan
@@ -509,7 +573,7 @@ func TestServer_SetEntry(t *testing.T) {
 			close(resultChan)
 		}()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.Error(t, err)
 		require.Equal(t, codes.Internal, status.Code(err))
@@ -540,7 +604,7 @@ func TestServer_SetEntry(t *testing.T) {
 			Key:   "key",
 			Name:  "name",
 			Notes: []byte("encrypted notes"),
-		}, false).Return(uploadChan, resultChan, nil)
+		}, false, int64(0)).Return(uploadChan, resultChan, nil)
 
 		// send upload signal
 		stream.EXPECT().Send(&pb.SetEntryResponse{}).Return(nil)
@@ -558,11 +622,161 @@ func TestServer_SetEntry(t *testing.T) {
 			close(resultChan)
 		}()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		err := s.SetEntry(stream)
 		require.Error(t, err)
 		require.Equal(t, codes.Internal, status.Code(err))
 	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockBidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
+			Entry: &pb.Entry{
+				Key:  "key",
+				Name: "name",
+			},
+			ExpectedVersion: 2,
+		}, nil)
+
+		service.EXPECT().SetEntry(ctxWithToken, "user", entryService.Metadata{
+			Key:  "key",
+			Name: "name",
+		}, false, int64(2)).Return(nil, nil, entryService.ErrConflict)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.SetEntry(stream)
+		require.Error(t, err)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("changed during upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockBidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
+			Entry: &pb.Entry{
+				Key:  "key",
+				Name: "name",
+			},
+			ExpectedVersion: 2,
+		}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.SetEntryResult)
+		service.EXPECT().SetEntry(ctxWithToken, "user", entryService.Metadata{
+			Key:  "key",
+			Name: "name",
+		}, false, int64(2)).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Send(&pb.SetEntryResponse{}).Return(nil)
+
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
+			Entry: &pb.Entry{
+				Content: []byte("encrypted content"),
+			},
+		}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+
+		go func() {
+			for range uploadChan {
+			}
+
+			resultChan <- entryService.SetEntryResult{
+				Err: entryService.ErrConflict,
+			}
+			close(resultChan)
+		}()
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.SetEntry(stream)
+		require.Error(t, err)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockBidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
+			Entry: &pb.Entry{
+				Key:  "key",
+				Name: "name",
+			},
+		}, nil)
+
+		service.EXPECT().SetEntry(ctxWithToken, "user", entryService.Metadata{
+			Key:  "key",
+			Name: "name",
+		}, false, int64(0)).Return(nil, nil, entryService.ErrQuotaExceeded)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.SetEntry(stream)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("quota exceeded during upload", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockBidiStreamingServer[pb.SetEntryRequest, pb.SetEntryResponse](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
+			Entry: &pb.Entry{
+				Key:  "key",
+				Name: "name",
+			},
+		}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.SetEntryResult)
+		service.EXPECT().SetEntry(ctxWithToken, "user", entryService.Metadata{
+			Key:  "key",
+			Name: "name",
+		}, false, int64(0)).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Send(&pb.SetEntryResponse{}).Return(nil)
+
+		stream.EXPECT().Recv().Return(&pb.SetEntryRequest{
+			Entry: &pb.Entry{
+				Content: []byte("encrypted content"),
+			},
+		}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+
+		go func() {
+			for range uploadChan {
+			}
+
+			resultChan <- entryService.SetEntryResult{
+				Err: entryService.ErrQuotaExceeded,
+			}
+			close(resultChan)
+		}()
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.SetEntry(stream)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
 }
 
 func TestServer_DeleteEntry(t *testing.T) {
@@ -582,9 +796,9 @@ func TestServer_DeleteEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().DeleteEntry(ctxWithToken, "user", "key").Return(nil)
+		service.EXPECT().DeleteEntry(ctxWithToken, "user", "key", int64(0)).Return(nil)
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		_, err := s.DeleteEntry(ctxWithToken, &pb.DeleteEntryRequest{
 			Key: "key",
 		})
@@ -600,7 +814,7 @@ func TestServer_DeleteEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctx).AnyTimes()
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		_, err := s.DeleteEntry(ctx, &pb.DeleteEntryRequest{
 			Key: "key",
 		})
@@ -617,13 +831,1256 @@ func TestServer_DeleteEntry(t *testing.T) {
 
 		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
 
-		service.EXPECT().DeleteEntry(ctxWithToken, "user", "key").Return(errors.New("cant delete entry"))
+		service.EXPECT().DeleteEntry(ctxWithToken, "user", "key", int64(0)).Return(errors.New("cant delete entry"))
 
-		s := entry.New(service, 1024)
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
 		_, err := s.DeleteEntry(ctxWithToken, &pb.DeleteEntryRequest{
 			Key: "key",
 		})
 		require.Error(t, err)
 		require.Equal(t, codes.Internal, status.Code(err))
 	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		service.EXPECT().DeleteEntry(ctxWithToken, "user", "key", int64(2)).Return(entryService.ErrConflict)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.DeleteEntry(ctxWithToken, &pb.DeleteEntryRequest{
+			Key:             "key",
+			ExpectedVersion: 2,
+		})
+		require.Error(t, err)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestServer_BatchDeleteEntries(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	request := &pb.BatchDeleteEntriesRequest{Entries: []*pb.DeleteEntryRequest{
+		{Key: "first", ExpectedVersion: 2},
+		{Key: "second"},
+	}}
+	items := []entryService.DeleteItem{{Key: "first", ExpectedVersion: 2}, {Key: "second"}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().BatchDeleteEntries(ctxWithToken, "user", items).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.BatchDeleteEntries(ctxWithToken, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.BatchDeleteEntries(ctx, request)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().BatchDeleteEntries(ctxWithToken, "user", items).Return(entryService.ErrConflict)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.BatchDeleteEntries(ctxWithToken, request)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().BatchDeleteEntries(ctxWithToken, "user", items).Return(entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.BatchDeleteEntries(ctxWithToken, request)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_RenameEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	request := &pb.RenameEntryRequest{Key: "key", NewKey: "new key", NewName: "new name", ExpectedVersion: 2}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RenameEntry(ctxWithToken, "user", "key", "new key", "new name", []byte(nil), int64(2)).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RenameEntry(ctxWithToken, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RenameEntry(ctxWithToken, "user", "key", "new key", "", []byte("envelope"), int64(0)).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RenameEntry(ctxWithToken, &pb.RenameEntryRequest{Key: "key", NewKey: "new key", NewEnvelope: []byte("envelope")})
+		require.NoError(t, err)
+	})
+
+	t.Run("no name", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RenameEntry(ctxWithToken, &pb.RenameEntryRequest{Key: "key", NewKey: "new key"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	for name, newKey := range map[string]string{
+		"no new key":   "",
+		"trash key":    "trash/key",
+		"versions key": "versions/key",
+		"staging key":  "staging/key",
+		"uploads key":  "uploads/key",
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+			_, err := s.RenameEntry(ctxWithToken, &pb.RenameEntryRequest{Key: "key", NewKey: newKey, NewName: "new name"})
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RenameEntry(ctx, request)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"not found":        {err: entryService.ErrEntryNotFound, code: codes.NotFound},
+		"new key taken":    {err: entryService.ErrEntryExists, code: codes.AlreadyExists},
+		"version mismatch": {err: entryService.ErrConflict, code: codes.FailedPrecondition},
+		"service error":    {err: entryService.ErrInternal, code: codes.Internal},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockEntryService(ctrl)
+			service.EXPECT().RenameEntry(ctxWithToken, "user", "key", "new key", "new name", []byte(nil), int64(2)).Return(tc.err)
+
+			s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+			_, err := s.RenameEntry(ctxWithToken, request)
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestServer_BatchGetMetadata(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	request := &pb.BatchGetMetadataRequest{Keys: []string{"first", "missing"}}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().BatchGetMetadata(ctxWithToken, "user", []string{"first", "missing"}).
+			Return([]entryService.Metadata{{Key: "first", Name: "name", Version: 2}}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		resp, err := s.BatchGetMetadata(ctxWithToken, request)
+		require.NoError(t, err)
+		require.Len(t, resp.Entries, 1)
+		require.Equal(t, "first", resp.Entries[0].Key)
+		require.Equal(t, int64(2), resp.Entries[0].Version)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.BatchGetMetadata(ctx, request)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().BatchGetMetadata(ctxWithToken, "user", gomock.Any()).Return(nil, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.BatchGetMetadata(ctxWithToken, request)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_ListChanges(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		service.EXPECT().ListChanges(ctxWithToken, "user", int64(10), 2).Return(
+			[]entryService.Change{
+				{Cursor: 11, Metadata: entryService.Metadata{Key: "key1", Name: "name1", Version: 3}, ChangedAt: changedAt},
+				{Cursor: 12, Metadata: entryService.Metadata{Key: "key2", Version: 1}, Deleted: true, ChangedAt: changedAt},
+			},
+			int64(12),
+			true,
+			nil,
+		)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		resp, err := s.ListChanges(ctxWithToken, &pb.ListChangesRequest{
+			SinceCursor: 10,
+			PageSize:    2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(12), resp.NextCursor)
+		require.True(t, resp.HasMore)
+		require.Len(t, resp.Changes, 2)
+		require.Equal(t, "key1", resp.Changes[0].Entry.Key)
+		require.Equal(t, "name1", resp.Changes[0].Entry.Name)
+		require.Equal(t, int64(3), resp.Changes[0].Entry.Version)
+		require.False(t, resp.Changes[0].Deleted)
+		require.Equal(t, changedAt, resp.Changes[0].ChangedAt.AsTime())
+		require.Equal(t, "key2", resp.Changes[1].Entry.Key)
+		require.True(t, resp.Changes[1].Deleted)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListChanges(ctx, &pb.ListChangesRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("cant list changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		service.EXPECT().ListChanges(ctxWithToken, "user", int64(0), 0).Return(nil, int64(0), false, errors.New("cant list"))
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListChanges(ctxWithToken, &pb.ListChangesRequest{})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_WatchEntries(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	change := entryService.Change{Cursor: 11, Metadata: entryService.Metadata{Key: "key", Name: "name", Version: 2}, ChangedAt: changedAt}
+
+	t.Run("client disconnects", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Change](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		streamCtx, streamCancel := context.WithCancel(ctxWithToken)
+		defer streamCancel()
+		stream.EXPECT().Context().Return(streamCtx).AnyTimes()
+
+		changes := make(chan entryService.Change, 1)
+		service.EXPECT().WatchEntries(streamCtx, "user").Return(changes, nil)
+
+		stream.EXPECT().Send(&pb.Change{
+			Entry:     &pb.Entry{Key: "key", Name: "name", Version: 2},
+			ChangedAt: timestamppb.New(changedAt),
+			Cursor:    11,
+		}).DoAndReturn(func(*pb.Change) error {
+			// as the real service does on disconnect
+			streamCancel()
+			close(changes)
+
+			return nil
+		})
+
+		changes <- change
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.WatchEntries(&pb.WatchEntriesRequest{}, stream)
+		require.Equal(t, codes.Canceled, status.Code(err))
+	})
+
+	t.Run("watcher dropped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Change](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		changes := make(chan entryService.Change)
+		close(changes)
+		service.EXPECT().WatchEntries(ctxWithToken, "user").Return(changes, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.WatchEntries(&pb.WatchEntriesRequest{}, stream)
+		require.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("service err", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Change](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		service.EXPECT().WatchEntries(ctxWithToken, "user").Return(nil, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.WatchEntries(&pb.WatchEntriesRequest{}, stream)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("cant send", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Change](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		changes := make(chan entryService.Change, 1)
+		changes <- change
+		service.EXPECT().WatchEntries(ctxWithToken, "user").Return(changes, nil)
+		stream.EXPECT().Send(gomock.Any()).Return(errors.New("cant send"))
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.WatchEntries(&pb.WatchEntriesRequest{}, stream)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Change](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.WatchEntries(&pb.WatchEntriesRequest{}, stream)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestServer_ListEntries(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		service.EXPECT().ListEntries(ctxWithToken, "user", "token", 10).Return(
+			[]entryService.Metadata{
+				{Key: "key1", Name: "name1", Notes: []byte("encrypted notes")},
+				{Key: "key2", Name: "name2"},
+			},
+			"key2",
+			nil,
+		)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		resp, err := s.ListEntries(ctxWithToken, &pb.ListEntriesRequest{
+			PageSize:  10,
+			PageToken: "token",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "key2", resp.NextPageToken)
+		require.Len(t, resp.Entries, 2)
+		require.Equal(t, "key1", resp.Entries[0].Key)
+		require.Equal(t, "name1", resp.Entries[0].Name)
+		require.Equal(t, []byte("encrypted notes"), resp.Entries[0].Notes)
+		require.Equal(t, "key2", resp.Entries[1].Key)
+		require.Equal(t, "name2", resp.Entries[1].Name)
+		require.Nil(t, resp.Entries[1].Notes)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListEntries(ctx, &pb.ListEntriesRequest{})
+		require.Error(t, err)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("cant list entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		service.EXPECT().ListEntries(ctxWithToken, "user", "", 0).Return(nil, "", errors.New("cant list"))
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListEntries(ctxWithToken, &pb.ListEntriesRequest{})
+		require.Error(t, err)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("exceeds declared size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		// the service stops reading, so the rest of the chunks are never received
+		streamCtx, cancelStream := context.WithCancel(ctxWithToken)
+		defer cancelStream()
+
+		stream.EXPECT().Context().Return(streamCtx).AnyTimes()
+		stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{UploadId: "upload"}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.UploadResult, 1)
+		service.EXPECT().AppendUpload(streamCtx, "user", "upload", int64(0)).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{Content: []byte("chunk")}, nil).AnyTimes()
+
+		resultChan <- entryService.UploadResult{Err: entryService.ErrSizeMismatch}
+		close(resultChan)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.UploadChunks(stream)
+		require.Equal(t, codes.OutOfRange, status.Code(err))
+
+		// the chunks reader stops once the stream is closed
+		cancelStream()
+		require.Eventually(t, func() bool {
+			_, ok := <-uploadChan
+			return !ok
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestServer_ListEntryVersions(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().ListEntryVersions(ctxWithToken, "user", "key").Return([]entryService.Metadata{
+			{Key: "key", Name: "name", Version: 2},
+			{Key: "key", Name: "old name", Version: 1},
+		}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		resp, err := s.ListEntryVersions(ctxWithToken, &pb.ListEntryVersionsRequest{Key: "key"})
+		require.NoError(t, err)
+		require.Len(t, resp.Versions, 2)
+		require.Equal(t, int64(2), resp.Versions[0].Version)
+		require.Equal(t, "old name", resp.Versions[1].Name)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListEntryVersions(ctx, &pb.ListEntryVersionsRequest{Key: "key"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().ListEntryVersions(ctxWithToken, "user", "key").Return(nil, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListEntryVersions(ctxWithToken, &pb.ListEntryVersionsRequest{Key: "key"})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_GetEntryVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Entry](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+		content := mocks.NewMockReadCloser(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		service.EXPECT().GetEntryVersion(ctxWithToken, "user", "key", int64(2)).Return(
+			entryService.Metadata{Key: "key", Name: "name", Version: 2},
+			content,
+			true,
+			nil,
+		)
+
+		stream.EXPECT().Send(&pb.Entry{Key: "key", Name: "name", Version: 2}).Return(nil)
+		content.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted content")).Return(17, nil)
+		stream.EXPECT().Send(&pb.Entry{
+			Content: []byte("encrypted content"),
+		}).Return(nil)
+		content.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
+		content.EXPECT().Close().Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetEntryVersion(&pb.GetEntryVersionRequest{Key: "key", Version: 2}, stream)
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Entry](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		service.EXPECT().GetEntryVersion(ctxWithToken, "user", "key", int64(2)).Return(entryService.Metadata{}, nil, false, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetEntryVersion(&pb.GetEntryVersionRequest{Key: "key", Version: 2}, stream)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.Entry](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		service.EXPECT().GetEntryVersion(ctxWithToken, "user", "key", int64(2)).Return(entryService.Metadata{}, nil, false, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetEntryVersion(&pb.GetEntryVersionRequest{Key: "key", Version: 2}, stream)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_RestoreEntryVersion(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RestoreEntryVersion(ctxWithToken, "user", "key", int64(1)).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RestoreEntryVersion(ctxWithToken, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RestoreEntryVersion(ctxWithToken, "user", "key", int64(1)).Return(entryService.ErrVersionNotFound)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RestoreEntryVersion(ctxWithToken, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RestoreEntryVersion(ctxWithToken, "user", "key", int64(1)).Return(entryService.ErrConflict)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RestoreEntryVersion(ctxWithToken, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RestoreEntryVersion(ctxWithToken, "user", "key", int64(1)).Return(entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RestoreEntryVersion(ctxWithToken, &pb.RestoreEntryVersionRequest{Key: "key", Version: 1})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_CreateUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	request := &pb.CreateUploadSessionRequest{
+		Entry: &pb.Entry{Key: "key", Name: "name", Notes: []byte("encrypted notes"), Type: "file"},
+		Size:  100,
+	}
+	md := entryService.Metadata{Key: "key", Name: "name", Notes: []byte("encrypted notes"), Type: "file"}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().CreateUploadSession(ctxWithToken, "user", md, int64(100), false, int64(0)).
+			Return(entryService.UploadSession{ID: "upload", Metadata: md, Size: 100}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		session, err := s.CreateUploadSession(ctxWithToken, request)
+		require.NoError(t, err)
+		require.Equal(t, &pb.UploadSession{UploadId: "upload", Size: 100}, session)
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		envelopeMd := entryService.Metadata{Key: "key", Notes: []byte("encrypted notes"), Envelope: []byte("envelope")}
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().CreateUploadSession(ctxWithToken, "user", envelopeMd, int64(100), false, int64(0)).
+			Return(entryService.UploadSession{ID: "upload", Metadata: envelopeMd, Size: 100}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, &pb.CreateUploadSessionRequest{
+			Entry: &pb.Entry{Key: "key", Notes: []byte("encrypted notes"), Envelope: []byte("envelope")},
+			Size:  100,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctx, request)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("metadata is empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, &pb.CreateUploadSessionRequest{Entry: &pb.Entry{Key: "key"}, Size: 100})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("key is reserved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, &pb.CreateUploadSessionRequest{Entry: &pb.Entry{Key: "uploads/key", Name: "name"}, Size: 100})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().CreateUploadSession(ctxWithToken, "user", md, int64(100), false, int64(0)).
+			Return(entryService.UploadSession{}, entryService.ErrEntryExists)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, request)
+		require.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().CreateUploadSession(ctxWithToken, "user", md, int64(100), false, int64(0)).
+			Return(entryService.UploadSession{}, entryService.ErrConflict)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, request)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().CreateUploadSession(ctxWithToken, "user", md, int64(100), false, int64(0)).
+			Return(entryService.UploadSession{}, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, request)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().CreateUploadSession(ctxWithToken, "user", md, int64(100), false, int64(0)).
+			Return(entryService.UploadSession{}, entryService.ErrQuotaExceeded)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.CreateUploadSession(ctxWithToken, request)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestServer_GetUploadSession(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetUploadSession(ctxWithToken, "user", "upload").
+			Return(entryService.UploadSession{ID: "upload", Size: 100, Offset: 40}, true, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		session, err := s.GetUploadSession(ctxWithToken, &pb.GetUploadSessionRequest{UploadId: "upload"})
+		require.NoError(t, err)
+		require.Equal(t, &pb.UploadSession{UploadId: "upload", CommittedOffset: 40, Size: 100}, session)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetUploadSession(ctxWithToken, "user", "upload").Return(entryService.UploadSession{}, false, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.GetUploadSession(ctxWithToken, &pb.GetUploadSessionRequest{UploadId: "upload"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetUploadSession(ctxWithToken, "user", "upload").Return(entryService.UploadSession{}, false, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.GetUploadSession(ctxWithToken, &pb.GetUploadSessionRequest{UploadId: "upload"})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_UploadChunks(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{UploadId: "upload", Offset: 40}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.UploadResult, 1)
+		service.EXPECT().AppendUpload(ctxWithToken, "user", "upload", int64(40)).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{Content: []byte("chunk")}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+
+		go func() {
+			uploaded := <-uploadChan
+			require.Equal(t, []byte("chunk"), uploaded.Content)
+
+			for range uploadChan {
+			}
+
+			resultChan <- entryService.UploadResult{Session: entryService.UploadSession{ID: "upload", Size: 100, Offset: 45}}
+			close(resultChan)
+		}()
+
+		stream.EXPECT().SendAndClose(&pb.UploadSession{UploadId: "upload", CommittedOffset: 45, Size: 100}).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.UploadChunks(stream)
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		err := s.UploadChunks(stream)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("no upload id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+		stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{Content: []byte("chunk")}, nil)
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		err := s.UploadChunks(stream)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("cant continue", func(t *testing.T) {
+		tests := []struct {
+			err  error
+			code codes.Code
+		}{
+			{entryService.ErrUploadNotFound, codes.NotFound},
+			{entryService.ErrOffsetMismatch, codes.OutOfRange},
+			{entryService.ErrUploadBusy, codes.Aborted},
+			{entryService.ErrInternal, codes.Internal},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.err.Error(), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				stream := mocks.NewMockClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+				service := mocks.NewMockEntryService(ctrl)
+
+				stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+				stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{UploadId: "upload", Offset: 40}, nil)
+				service.EXPECT().AppendUpload(ctxWithToken, "user", "upload", int64(40)).Return(nil, nil, tt.err)
+
+				s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+				err := s.UploadChunks(stream)
+				require.Equal(t, tt.code, status.Code(err))
+			})
+		}
+	})
+
+	t.Run("err reading chunk", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.UploadChunkRequest, pb.UploadSession](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+		stream.EXPECT().Recv().Return(&pb.UploadChunkRequest{UploadId: "upload"}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.UploadResult, 1)
+		service.EXPECT().AppendUpload(ctxWithToken, "user", "upload", int64(0)).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Recv().Return(nil, errors.New("cant read chunk"))
+
+		go func() {
+			uploaded := <-uploadChan
+			require.Error(t, uploaded.Err)
+
+			resultChan <- entryService.UploadResult{Err: entryService.ErrUploadChunk}
+			close(resultChan)
+		}()
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.UploadChunks(stream)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_FinalizeUpload(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().FinalizeUpload(ctxWithToken, "user", "upload").Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.FinalizeUpload(ctxWithToken, &pb.FinalizeUploadRequest{UploadId: "upload"})
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.FinalizeUpload(ctx, &pb.FinalizeUploadRequest{UploadId: "upload"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			err  error
+			code codes.Code
+		}{
+			{entryService.ErrUploadNotFound, codes.NotFound},
+			{entryService.ErrSizeMismatch, codes.OutOfRange},
+			{entryService.ErrConflict, codes.FailedPrecondition},
+			{entryService.ErrUploadBusy, codes.Aborted},
+			{entryService.ErrInternal, codes.Internal},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.err.Error(), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				service := mocks.NewMockEntryService(ctrl)
+				service.EXPECT().FinalizeUpload(ctxWithToken, "user", "upload").Return(tt.err)
+
+				s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+				_, err := s.FinalizeUpload(ctxWithToken, &pb.FinalizeUploadRequest{UploadId: "upload"})
+				require.Equal(t, tt.code, status.Code(err))
+			})
+		}
+	})
+}
+
+func TestServer_GetUsage(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetUsage(ctxWithToken, "user").Return(entryService.Usage{Bytes: 5, Entries: 1, MaxBytes: 10}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		usage, err := s.GetUsage(ctxWithToken, &pb.GetUsageRequest{})
+		require.NoError(t, err)
+		require.Equal(t, int64(5), usage.Bytes)
+		require.Equal(t, int64(1), usage.Entries)
+		require.Equal(t, int64(10), usage.MaxBytes)
+		require.Zero(t, usage.MaxEntries)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.GetUsage(ctx, &pb.GetUsageRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetUsage(ctxWithToken, "user").Return(entryService.Usage{}, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.GetUsage(ctxWithToken, &pb.GetUsageRequest{})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_ListTrash(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().ListTrash(ctxWithToken, "user").Return([]entryService.TrashItem{
+			{
+				Metadata:  entryService.Metadata{Key: "key", Name: "name", Type: "login", Version: 2},
+				DeletedAt: deletedAt,
+				ExpiresAt: deletedAt.Add(time.Hour),
+			},
+		}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		response, err := s.ListTrash(ctxWithToken, &pb.ListTrashRequest{})
+		require.NoError(t, err)
+		require.Len(t, response.Entries, 1)
+		require.Equal(t, "key", response.Entries[0].Entry.Key)
+		require.Equal(t, "name", response.Entries[0].Entry.Name)
+		require.Equal(t, deletedAt, response.Entries[0].DeletedAt.AsTime())
+		require.Equal(t, deletedAt.Add(time.Hour), response.Entries[0].ExpiresAt.AsTime())
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListTrash(ctx, &pb.ListTrashRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().ListTrash(ctxWithToken, "user").Return(nil, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListTrash(ctxWithToken, &pb.ListTrashRequest{})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_RestoreEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().RestoreEntry(ctxWithToken, "user", "key").Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RestoreEntry(ctxWithToken, &pb.RestoreEntryRequest{Key: "key"})
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RestoreEntry(ctx, &pb.RestoreEntryRequest{Key: "key"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"not in trash":   {err: entryService.ErrEntryNotFound, code: codes.NotFound},
+		"entry exists":   {err: entryService.ErrEntryExists, code: codes.AlreadyExists},
+		"quota exceeded": {err: entryService.ErrQuotaExceeded, code: codes.ResourceExhausted},
+		"service error":  {err: entryService.ErrInternal, code: codes.Internal},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockEntryService(ctrl)
+			service.EXPECT().RestoreEntry(ctxWithToken, "user", "key").Return(tc.err)
+
+			s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+			_, err := s.RestoreEntry(ctxWithToken, &pb.RestoreEntryRequest{Key: "key"})
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestServer_PurgeEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().PurgeEntry(ctxWithToken, "user", "key").Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.PurgeEntry(ctxWithToken, &pb.PurgeEntryRequest{Key: "key"})
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.PurgeEntry(ctx, &pb.PurgeEntryRequest{Key: "key"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().PurgeEntry(ctxWithToken, "user", "key").Return(entryService.ErrEntryNotFound)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.PurgeEntry(ctxWithToken, &pb.PurgeEntryRequest{Key: "key"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().PurgeEntry(ctxWithToken, "user", "key").Return(entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.PurgeEntry(ctxWithToken, &pb.PurgeEntryRequest{Key: "key"})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
 }
@@ -0,0 +1,300 @@
+package entry
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+)
+
+// SetKeyPair publishes the user's key pair.
+// It requires authentication and returns AlreadyExists if the user already has one.
+func (s *server) SetKeyPair(ctx context.Context, request *pb.SetKeyPairRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.SetKeyPair(ctx, tokenInfo.UserID, entry.KeyPair{
+		PublicKey:           request.KeyPair.PublicKey,
+		EncryptedPrivateKey: request.KeyPair.EncryptedPrivateKey,
+	})
+	if err != nil {
+		if errors.Is(err, entry.ErrKeyPairExists) {
+			return nil, status.Error(codes.AlreadyExists, "key pair already exists")
+		}
+
+		return nil, status.Error(codes.Internal, "cant set key pair")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetKeyPair returns the user's key pair.
+// It requires authentication and returns NotFound if the user has none.
+func (s *server) GetKeyPair(ctx context.Context, _ *pb.GetKeyPairRequest) (*pb.KeyPair, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	pair, ok, err := s.service.GetKeyPair(ctx, tokenInfo.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant get key pair")
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "key pair not found")
+	}
+
+	return &pb.KeyPair{
+		PublicKey:           pair.PublicKey,
+		EncryptedPrivateKey: pair.EncryptedPrivateKey,
+	}, nil
+}
+
+// GetPublicKey returns the public key of the user with the given login.
+// It requires authentication and returns NotFound if there is no such user or the user has no key pair.
+func (s *server) GetPublicKey(ctx context.Context, request *pb.GetPublicKeyRequest) (*pb.PublicKey, error) {
+	_, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	key, ok, err := s.service.GetPublicKey(ctx, request.Login)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant get public key")
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "public key not found")
+	}
+
+	return &pb.PublicKey{Login: request.Login, PublicKey: key}, nil
+}
+
+// ShareEntry receives the share and then the content of the copy in chunks, and stores them.
+// It requires authentication and returns NotFound if the entry or the recipient doesn't exist,
+// InvalidArgument if the recipient is the user, and ResourceExhausted if the user's quota is exceeded.
+// Only the user's own entries can be shared, FailedPrecondition is returned if a vault is selected.
+func (s *server) ShareEntry(stream grpc.ClientStreamingServer[pb.ShareEntryRequest, pb.Share]) error {
+	tokenInfo, ok := auth.GetTokenInfo(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	if getVaultID(stream.Context()) != "" {
+		return status.Error(codes.FailedPrecondition, "entries of vaults cant be shared")
+	}
+
+	llog := s.log.WithLazy("userID", tokenInfo.UserID, "method", "ShareEntry")
+
+	request, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.Canceled, "client closed connection")
+		}
+
+		llog.Errorw("cant get share", "err", err)
+
+		return status.Error(codes.Internal, "cant get share")
+	}
+	if request.RecipientLogin == "" || request.Key == "" || len(request.Envelope) == 0 {
+		return status.Error(codes.InvalidArgument, "recipient login, key and envelope must be set")
+	}
+
+	llog = llog.WithLazy("key", request.Key, "recipient", request.RecipientLogin)
+
+	uploadChan, resultChan, err := s.service.ShareEntry(stream.Context(), tokenInfo.UserID, entry.Share{
+		RecipientLogin: request.RecipientLogin,
+		Key:            request.Key,
+		Envelope:       request.Envelope,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, entry.ErrEntryNotFound):
+			return status.Error(codes.NotFound, "entry not found")
+		case errors.Is(err, entry.ErrRecipientNotFound):
+			return status.Error(codes.NotFound, "recipient not found")
+		case errors.Is(err, entry.ErrShareWithSelf):
+			return status.Error(codes.InvalidArgument, "cant share entry with yourself")
+		case errors.Is(err, entry.ErrQuotaExceeded):
+			return status.Error(codes.ResourceExhausted, "storage quota exceeded")
+		default:
+			return status.Error(codes.Internal, "cant share entry")
+		}
+	}
+
+	received := make(chan struct{})
+	go s.downloadContentChunks(stream.Context(), func() ([]byte, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		return req.Content, nil
+	}, uploadChan, received, llog.Named("upload"))
+
+	select {
+	case <-stream.Context().Done():
+		return status.Error(codes.Canceled, "client closed connection")
+
+	case result := <-resultChan:
+		if result.Err != nil {
+			switch {
+			case errors.Is(result.Err, context.Canceled):
+				return status.Error(codes.Canceled, "client closed connection")
+			case errors.Is(result.Err, entry.ErrUploadChunk):
+				return status.Error(codes.Internal, "cant upload chunk")
+			case errors.Is(result.Err, entry.ErrNoUpload):
+				return status.Error(codes.InvalidArgument, "no content uploaded")
+			case errors.Is(result.Err, entry.ErrQuotaExceeded):
+				return status.Error(codes.ResourceExhausted, "storage quota exceeded")
+			default:
+				return status.Error(codes.Internal, "internal error during upload")
+			}
+		}
+
+		// the service succeeds only once the upload channel is closed, see SetEntry
+		<-received
+
+		err = stream.SendAndClose(toPbShare(result.Share))
+		if err != nil {
+			llog.Errorw("cant send share", "err", err)
+
+			return status.Error(codes.Internal, "cant send share")
+		}
+
+		return nil
+	}
+}
+
+// ListShares returns the entries shared by the user or with the user.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListShares(ctx context.Context, request *pb.ListSharesRequest) (*pb.ListSharesResponse, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	shares, err := s.service.ListShares(ctx, tokenInfo.UserID, request.Incoming)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list shares")
+	}
+
+	response := &pb.ListSharesResponse{
+		Shares: make([]*pb.Share, 0, len(shares)),
+	}
+
+	for _, share := range shares {
+		response.Shares = append(response.Shares, toPbShare(share))
+	}
+
+	return response, nil
+}
+
+// GetSharedEntry streams the share and then the content of the copy shared with the user in chunks.
+// It requires authentication and returns NotFound if the entry isn't shared with the user.
+func (s *server) GetSharedEntry(request *pb.GetSharedEntryRequest, stream grpc.ServerStreamingServer[pb.SharedEntry]) error {
+	tokenInfo, ok := auth.GetTokenInfo(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	llog := s.log.WithLazy("userID", tokenInfo.UserID, "method", "GetSharedEntry", "shareID", request.ShareId)
+
+	share, reader, ok, err := s.service.GetSharedEntry(stream.Context(), tokenInfo.UserID, request.ShareId)
+	if err != nil {
+		return status.Error(codes.Internal, "cant get shared entry")
+	}
+	if !ok {
+		return status.Error(codes.NotFound, "shared entry not found")
+	}
+
+	return s.sendSharedEntry(stream, share, reader, llog)
+}
+
+// sendSharedEntry sends the share and then the content in chunks to the stream. The reader is closed afterwards.
+func (s *server) sendSharedEntry(stream grpc.ServerStreamingServer[pb.SharedEntry], share entry.Share, reader io.ReadCloser, llog *zap.SugaredLogger) error {
+	defer utils.CloseAndLogError(reader, llog)
+
+	err := stream.Send(&pb.SharedEntry{Share: toPbShare(share)})
+	if err != nil {
+		llog.Errorw("cant send share", "err", err)
+
+		return status.Error(codes.Internal, "cant send share")
+	}
+
+	buf := make([]byte, s.chunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			llog.Errorw("cant read shared entry", "err", err)
+
+			return status.Error(codes.Internal, "cant read shared entry")
+		}
+
+		err = stream.Send(&pb.SharedEntry{
+			Content: buf[:n],
+		})
+		if err != nil {
+			llog.Errorw("cant send shared entry", "err", err)
+
+			return status.Error(codes.Internal, "cant send shared entry")
+		}
+	}
+
+	return nil
+}
+
+// RevokeShare deletes the copy of the user's entry shared with the recipient.
+// It requires authentication and returns NotFound if the entry isn't shared with the recipient.
+func (s *server) RevokeShare(ctx context.Context, request *pb.RevokeShareRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.RevokeShare(ctx, tokenInfo.UserID, request.Key, request.RecipientLogin)
+	if err != nil {
+		if errors.Is(err, entry.ErrShareNotFound) {
+			return nil, status.Error(codes.NotFound, "share not found")
+		}
+
+		return nil, status.Error(codes.Internal, "cant revoke share")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// toPbShare converts the share to the protobuf one. Zero creation time is left unset.
+func toPbShare(share entry.Share) *pb.Share {
+	s := &pb.Share{
+		Id:             share.ID,
+		OwnerLogin:     share.OwnerLogin,
+		RecipientLogin: share.RecipientLogin,
+		Key:            share.Key,
+		Envelope:       share.Envelope,
+		Size:           share.Size,
+		Checksum:       share.Checksum,
+	}
+
+	if !share.CreatedAt.IsZero() {
+		s.CreatedAt = timestamppb.New(share.CreatedAt)
+	}
+
+	return s
+}
@@ -0,0 +1,428 @@
+package entry_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	entryService "github.com/kuvalkin/gophkeeper/internal/server/service/entry"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/servers/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+)
+
+func TestServer_KeyPair(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().SetKeyPair(ctxWithToken, "user", entryService.KeyPair{PublicKey: "public", EncryptedPrivateKey: []byte("private")}).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.SetKeyPair(ctxWithToken, &pb.SetKeyPairRequest{
+			KeyPair: &pb.KeyPair{PublicKey: "public", EncryptedPrivateKey: []byte("private")},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("set exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().SetKeyPair(ctxWithToken, "user", gomock.Any()).Return(entryService.ErrKeyPairExists)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.SetKeyPair(ctxWithToken, &pb.SetKeyPairRequest{KeyPair: &pb.KeyPair{PublicKey: "public"}})
+		require.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetKeyPair(ctxWithToken, "user").Return(entryService.KeyPair{PublicKey: "public", EncryptedPrivateKey: []byte("private")}, true, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		pair, err := s.GetKeyPair(ctxWithToken, &pb.GetKeyPairRequest{})
+		require.NoError(t, err)
+		require.Equal(t, "public", pair.PublicKey)
+		require.Equal(t, []byte("private"), pair.EncryptedPrivateKey)
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetKeyPair(ctxWithToken, "user").Return(entryService.KeyPair{}, false, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.GetKeyPair(ctxWithToken, &pb.GetKeyPairRequest{})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("get public key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().GetPublicKey(ctxWithToken, "bob").Return("public", true, nil)
+		service.EXPECT().GetPublicKey(ctxWithToken, "alice").Return("", false, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		key, err := s.GetPublicKey(ctxWithToken, &pb.GetPublicKeyRequest{Login: "bob"})
+		require.NoError(t, err)
+		require.Equal(t, &pb.PublicKey{Login: "bob", PublicKey: "public"}, key)
+
+		_, err = s.GetPublicKey(ctxWithToken, &pb.GetPublicKeyRequest{Login: "alice"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+
+		_, err := s.SetKeyPair(ctx, &pb.SetKeyPairRequest{KeyPair: &pb.KeyPair{}})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = s.GetKeyPair(ctx, &pb.GetKeyPairRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = s.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: "bob"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestServer_ShareEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.ShareEntryRequest, pb.Share](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		stream.EXPECT().Recv().Return(&pb.ShareEntryRequest{RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope")}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.ShareResult, 1)
+		service.EXPECT().ShareEntry(ctxWithToken, "user", entryService.Share{
+			RecipientLogin: "bob",
+			Key:            "key",
+			Envelope:       []byte("envelope"),
+		}).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Recv().Return(&pb.ShareEntryRequest{Content: []byte("chunk")}, nil)
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		go func() {
+			uploaded := <-uploadChan
+			require.Equal(t, []byte("chunk"), uploaded.Content)
+
+			for range uploadChan {
+			}
+
+			resultChan <- entryService.ShareResult{Share: entryService.Share{
+				ID:             "share",
+				OwnerLogin:     "alice",
+				RecipientLogin: "bob",
+				Key:            "key",
+				Envelope:       []byte("envelope"),
+				Size:           5,
+				Checksum:       []byte("checksum"),
+				CreatedAt:      createdAt,
+			}}
+			close(resultChan)
+		}()
+
+		stream.EXPECT().SendAndClose(&pb.Share{
+			Id:             "share",
+			OwnerLogin:     "alice",
+			RecipientLogin: "bob",
+			Key:            "key",
+			Envelope:       []byte("envelope"),
+			Size:           5,
+			Checksum:       []byte("checksum"),
+			CreatedAt:      timestamppb.New(createdAt),
+		}).Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.ShareEntry(stream)
+		require.NoError(t, err)
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.ShareEntryRequest, pb.Share](ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		err := s.ShareEntry(stream)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("no envelope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.ShareEntryRequest, pb.Share](ctrl)
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+		stream.EXPECT().Recv().Return(&pb.ShareEntryRequest{RecipientLogin: "bob", Key: "key"}, nil)
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		err := s.ShareEntry(stream)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("cant share", func(t *testing.T) {
+		tests := []struct {
+			err  error
+			code codes.Code
+		}{
+			{entryService.ErrEntryNotFound, codes.NotFound},
+			{entryService.ErrRecipientNotFound, codes.NotFound},
+			{entryService.ErrShareWithSelf, codes.InvalidArgument},
+			{entryService.ErrQuotaExceeded, codes.ResourceExhausted},
+			{entryService.ErrInternal, codes.Internal},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.err.Error(), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				stream := mocks.NewMockClientStreamingServer[pb.ShareEntryRequest, pb.Share](ctrl)
+				service := mocks.NewMockEntryService(ctrl)
+
+				stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+				stream.EXPECT().Recv().Return(&pb.ShareEntryRequest{RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope")}, nil)
+				service.EXPECT().ShareEntry(ctxWithToken, "user", gomock.Any()).Return(nil, nil, tt.err)
+
+				s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+				err := s.ShareEntry(stream)
+				require.Equal(t, tt.code, status.Code(err))
+			})
+		}
+	})
+
+	t.Run("err reading chunk", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.ShareEntryRequest, pb.Share](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+		stream.EXPECT().Recv().Return(&pb.ShareEntryRequest{RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope")}, nil)
+
+		uploadChan := make(chan entryService.UploadChunk)
+		resultChan := make(chan entryService.ShareResult, 1)
+		service.EXPECT().ShareEntry(ctxWithToken, "user", gomock.Any()).Return(uploadChan, resultChan, nil)
+
+		stream.EXPECT().Recv().Return(nil, errors.New("cant read chunk"))
+
+		go func() {
+			uploaded := <-uploadChan
+			require.Error(t, uploaded.Err)
+
+			resultChan <- entryService.ShareResult{Err: entryService.ErrUploadChunk}
+			close(resultChan)
+		}()
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.ShareEntry(stream)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestServer_ListShares(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().ListShares(ctxWithToken, "user", true).Return([]entryService.Share{
+			{ID: "share", OwnerLogin: "alice", RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope"), Size: 5},
+		}, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		response, err := s.ListShares(ctxWithToken, &pb.ListSharesRequest{Incoming: true})
+		require.NoError(t, err)
+		require.Equal(t, []*pb.Share{
+			{Id: "share", OwnerLogin: "alice", RecipientLogin: "bob", Key: "key", Envelope: []byte("envelope"), Size: 5},
+		}, response.Shares)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		service.EXPECT().ListShares(ctxWithToken, "user", false).Return(nil, entryService.ErrInternal)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListShares(ctxWithToken, &pb.ListSharesRequest{})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.ListShares(ctx, &pb.ListSharesRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestServer_GetSharedEntry(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.SharedEntry](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+		content := mocks.NewMockReadCloser(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+
+		service.EXPECT().GetSharedEntry(ctxWithToken, "user", "share").Return(
+			entryService.Share{ID: "share", OwnerLogin: "alice", Key: "key", Envelope: []byte("envelope")},
+			content,
+			true,
+			nil,
+		)
+
+		stream.EXPECT().Send(&pb.SharedEntry{
+			Share: &pb.Share{Id: "share", OwnerLogin: "alice", Key: "key", Envelope: []byte("envelope")},
+		}).Return(nil)
+
+		content.EXPECT().Read(gomock.Any()).SetArg(0, []byte("encrypted content")).Return(17, nil)
+		stream.EXPECT().Send(&pb.SharedEntry{
+			Content: []byte("encrypted content"),
+		}).Return(nil)
+		content.EXPECT().Read(gomock.Any()).SetArg(0, []byte{}).Return(0, io.EOF)
+		content.EXPECT().Close().Return(nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetSharedEntry(&pb.GetSharedEntryRequest{ShareId: "share"}, stream)
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.SharedEntry](ctrl)
+		service := mocks.NewMockEntryService(ctrl)
+
+		stream.EXPECT().Context().Return(ctxWithToken).AnyTimes()
+		service.EXPECT().GetSharedEntry(ctxWithToken, "user", "share").Return(entryService.Share{}, nil, false, nil)
+
+		s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetSharedEntry(&pb.GetSharedEntryRequest{ShareId: "share"}, stream)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockServerStreamingServer[pb.SharedEntry](ctrl)
+		stream.EXPECT().Context().Return(ctx).AnyTimes()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		err := s.GetSharedEntry(&pb.GetSharedEntryRequest{ShareId: "share"}, stream)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestServer_RevokeShare(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"success", nil, codes.OK},
+		{"not found", entryService.ErrShareNotFound, codes.NotFound},
+		{"error", entryService.ErrInternal, codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockEntryService(ctrl)
+			service.EXPECT().RevokeShare(ctxWithToken, "user", "key", "bob").Return(tt.err)
+
+			s := entry.New(service, mocks.NewMockVaultService(ctrl), 1024)
+			_, err := s.RevokeShare(ctxWithToken, &pb.RevokeShareRequest{Key: "key", RecipientLogin: "bob"})
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+
+	t.Run("no token info", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.RevokeShare(ctx, &pb.RevokeShareRequest{Key: "key", RecipientLogin: "bob"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
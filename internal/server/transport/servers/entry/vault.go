@@ -0,0 +1,65 @@
+package entry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+)
+
+// vaultIDKey is the request metadata key selecting the vault whose entries are managed instead of the user's.
+const vaultIDKey = "x-vault-id"
+
+// authorize returns the ID the entries of the request are stored under: the ID of the vault selected
+// in the request metadata if the user has at least the role in it, or the ID of the user if no vault is selected.
+// The returned error is a status error ready to be returned to the client.
+func (s *server) authorize(ctx context.Context, role vault.Role) (string, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	vaultID := getVaultID(ctx)
+	if vaultID == "" {
+		return tokenInfo.UserID, nil
+	}
+
+	if uuid.Validate(vaultID) != nil {
+		return "", status.Error(codes.InvalidArgument, "invalid vault id")
+	}
+
+	err := s.vaults.Authorize(ctx, tokenInfo.UserID, vaultID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, vault.ErrVaultNotFound):
+			return "", status.Error(codes.NotFound, "vault not found")
+		case errors.Is(err, vault.ErrPermissionDenied):
+			return "", status.Error(codes.PermissionDenied, "not allowed by the role in the vault")
+		default:
+			return "", status.Error(codes.Internal, "cant check access to the vault")
+		}
+	}
+
+	return vaultID, nil
+}
+
+// getVaultID returns the ID of the vault selected in the request metadata, or an empty string if there is none.
+func getVaultID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(vaultIDKey)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,121 @@
+package entry_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/servers/entry"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/entry/v1"
+)
+
+const vaultID = "6f1c1d4e-8a56-4a8e-9a3b-0d7f3c2b1a90"
+
+func TestServer_Vault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+	ctxWithVault := metadata.NewIncomingContext(ctxWithToken, metadata.Pairs("x-vault-id", vaultID))
+
+	t.Run("entries of vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+
+		vaults.EXPECT().Authorize(ctxWithVault, "user", vaultID, vault.RoleEditor).Return(nil)
+		service.EXPECT().DeleteEntry(ctxWithVault, vaultID, "key", int64(0)).Return(nil)
+
+		s := entry.New(service, vaults, 1024)
+		_, err := s.DeleteEntry(ctxWithVault, &pb.DeleteEntryRequest{Key: "key"})
+		require.NoError(t, err)
+	})
+
+	t.Run("reading requires viewer role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockEntryService(ctrl)
+		vaults := mocks.NewMockVaultService(ctrl)
+
+		vaults.EXPECT().Authorize(ctxWithVault, "user", vaultID, vault.RoleViewer).Return(nil)
+		service.EXPECT().ListEntries(ctxWithVault, vaultID, "", 0).Return(nil, "", nil)
+
+		s := entry.New(service, vaults, 1024)
+		_, err := s.ListEntries(ctxWithVault, &pb.ListEntriesRequest{})
+		require.NoError(t, err)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		vaults := mocks.NewMockVaultService(ctrl)
+		vaults.EXPECT().Authorize(ctxWithVault, "user", vaultID, vault.RoleEditor).Return(vault.ErrVaultNotFound)
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), vaults, 1024)
+		_, err := s.DeleteEntry(ctxWithVault, &pb.DeleteEntryRequest{Key: "key"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("role too low", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		vaults := mocks.NewMockVaultService(ctrl)
+		vaults.EXPECT().Authorize(ctxWithVault, "user", vaultID, vault.RoleEditor).Return(vault.ErrPermissionDenied)
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), vaults, 1024)
+		_, err := s.DeleteEntry(ctxWithVault, &pb.DeleteEntryRequest{Key: "key"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("cant check access", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		vaults := mocks.NewMockVaultService(ctrl)
+		vaults.EXPECT().Authorize(ctxWithVault, "user", vaultID, vault.RoleEditor).Return(errors.New("error"))
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), vaults, 1024)
+		_, err := s.DeleteEntry(ctxWithVault, &pb.DeleteEntryRequest{Key: "key"})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("invalid vault id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctxWithInvalid := metadata.NewIncomingContext(ctxWithToken, metadata.Pairs("x-vault-id", "not a uuid"))
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		_, err := s.DeleteEntry(ctxWithInvalid, &pb.DeleteEntryRequest{Key: "key"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("sharing entries of vault", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mocks.NewMockClientStreamingServer[pb.ShareEntryRequest, pb.Share](ctrl)
+		stream.EXPECT().Context().Return(ctxWithVault).AnyTimes()
+
+		s := entry.New(mocks.NewMockEntryService(ctrl), mocks.NewMockVaultService(ctrl), 1024)
+		err := s.ShareEntry(stream)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
@@ -0,0 +1,177 @@
+package vault
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+// GrantEmergencyAccess makes a user an emergency contact of the vault, or of the own entries of the user if no vault is set.
+// It requires authentication and the owner role in the vault.
+func (s *server) GrantEmergencyAccess(ctx context.Context, request *pb.GrantEmergencyAccessRequest) (*pb.EmergencyGrant, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	grant, err := s.service.GrantEmergencyAccess(ctx, tokenInfo.UserID, grantVaultID(request.VaultId, tokenInfo.UserID), vault.EmergencyGrant{
+		ContactLogin: request.Login,
+		WrappedKey:   request.WrappedKey,
+		Wait:         request.Wait.AsDuration(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, vault.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, vault.ErrGrantExists):
+			return nil, status.Error(codes.AlreadyExists, "user is an emergency contact already")
+		default:
+			return nil, toStatusError(err, "cant grant emergency access")
+		}
+	}
+
+	return toPbGrant(grant), nil
+}
+
+// RevokeEmergencyAccess removes an emergency contact from the vault, or from the own entries of the user if no vault is set.
+// It requires authentication and the owner role in the vault.
+func (s *server) RevokeEmergencyAccess(ctx context.Context, request *pb.RevokeEmergencyAccessRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.RevokeEmergencyAccess(ctx, tokenInfo.UserID, grantVaultID(request.VaultId, tokenInfo.UserID), request.Login)
+	if err != nil {
+		return nil, toGrantStatusError(err, "cant revoke emergency access")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListEmergencyGrants returns the grants of the vaults the user owns, or the ones the user is the contact of.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListEmergencyGrants(ctx context.Context, request *pb.ListEmergencyGrantsRequest) (*pb.ListEmergencyGrantsResponse, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	grants, err := s.service.ListEmergencyGrants(ctx, tokenInfo.UserID, request.Incoming)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list emergency grants")
+	}
+
+	response := &pb.ListEmergencyGrantsResponse{
+		Grants: make([]*pb.EmergencyGrant, 0, len(grants)),
+	}
+
+	for _, grant := range grants {
+		response.Grants = append(response.Grants, toPbGrant(grant))
+	}
+
+	return response, nil
+}
+
+// RequestEmergencyAccess starts the waiting period of the grant the user is the contact of.
+// It requires authentication and returns an error if there is no such grant.
+func (s *server) RequestEmergencyAccess(ctx context.Context, request *pb.RequestEmergencyAccessRequest) (*pb.EmergencyGrant, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	grant, err := s.service.RequestEmergencyAccess(ctx, tokenInfo.UserID, request.VaultId)
+	if err != nil {
+		return nil, toGrantStatusError(err, "cant request emergency access")
+	}
+
+	return toPbGrant(grant), nil
+}
+
+// RejectEmergencyAccess cancels the request of an emergency contact of the vault, or of the own entries of the user if no vault is set.
+// It requires authentication and the owner role in the vault.
+func (s *server) RejectEmergencyAccess(ctx context.Context, request *pb.RejectEmergencyAccessRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.RejectEmergencyAccess(ctx, tokenInfo.UserID, grantVaultID(request.VaultId, tokenInfo.UserID), request.Login)
+	if err != nil {
+		return nil, toGrantStatusError(err, "cant reject emergency access")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// OpenEmergencyAccess makes the user a viewer of the vault once the waiting period of the request has passed.
+// It requires authentication and returns the vault with the key wrapped to the user.
+func (s *server) OpenEmergencyAccess(ctx context.Context, request *pb.OpenEmergencyAccessRequest) (*pb.Vault, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	v, err := s.service.OpenEmergencyAccess(ctx, tokenInfo.UserID, request.VaultId)
+	if err != nil {
+		switch {
+		case errors.Is(err, vault.ErrNotRequested):
+			return nil, status.Error(codes.FailedPrecondition, "emergency access not requested")
+		case errors.Is(err, vault.ErrWaitingPeriod):
+			return nil, status.Error(codes.FailedPrecondition, "waiting period hasn't passed")
+		default:
+			return nil, toGrantStatusError(err, "cant open emergency access")
+		}
+	}
+
+	return toPbVault(v), nil
+}
+
+// grantVaultID returns the ID of the vault of the request, or the one of the user for the own entries if the vault isn't set.
+func grantVaultID(vaultID string, userID string) string {
+	if vaultID == "" {
+		return userID
+	}
+
+	return vaultID
+}
+
+// toGrantStatusError converts the error of a missing grant to the status error, other errors are converted by toStatusError.
+func toGrantStatusError(err error, msg string) error {
+	if errors.Is(err, vault.ErrGrantNotFound) {
+		return status.Error(codes.NotFound, "emergency grant not found")
+	}
+
+	return toStatusError(err, msg)
+}
+
+func toPbGrant(grant vault.EmergencyGrant) *pb.EmergencyGrant {
+	result := &pb.EmergencyGrant{
+		VaultId:      grant.VaultID,
+		VaultName:    grant.VaultName,
+		ContactLogin: grant.ContactLogin,
+		OwnerLogin:   grant.OwnerLogin,
+		Wait:         durationpb.New(grant.Wait),
+	}
+
+	if grant.Requested() {
+		result.RequestedAt = timestamppb.New(grant.RequestedAt)
+		result.AvailableAt = timestamppb.New(grant.AvailableAt())
+	}
+
+	if !grant.CreatedAt.IsZero() {
+		result.CreatedAt = timestamppb.New(grant.CreatedAt)
+	}
+
+	return result
+}
@@ -0,0 +1,231 @@
+package vault_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	vaultService "github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/servers/vault"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+func TestServer_GrantEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	request := &pb.GrantEmergencyAccessRequest{
+		VaultId:    "vault",
+		Login:      "bob",
+		WrappedKey: []byte("key"),
+		Wait:       durationpb.New(72 * time.Hour),
+	}
+	grant := vaultService.EmergencyGrant{ContactLogin: "bob", WrappedKey: []byte("key"), Wait: 72 * time.Hour}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().GrantEmergencyAccess(ctxWithToken, "user", "vault", grant).Return(vaultService.EmergencyGrant{
+			VaultID:      "vault",
+			VaultName:    "team",
+			ContactLogin: "bob",
+			OwnerLogin:   "alice",
+			Wait:         72 * time.Hour,
+		}, nil)
+
+		s := vault.New(service)
+		resp, err := s.GrantEmergencyAccess(ctxWithToken, request)
+		require.NoError(t, err)
+		require.Equal(t, "team", resp.VaultName)
+		require.Equal(t, "alice", resp.OwnerLogin)
+		require.Equal(t, 72*time.Hour, resp.Wait.AsDuration())
+		require.Nil(t, resp.RequestedAt)
+		require.Nil(t, resp.AvailableAt)
+	})
+
+	t.Run("own entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().GrantEmergencyAccess(ctxWithToken, "user", "user", grant).Return(vaultService.EmergencyGrant{
+			VaultID:      "user",
+			VaultName:    "alice",
+			ContactLogin: "bob",
+			OwnerLogin:   "alice",
+			Wait:         72 * time.Hour,
+		}, nil)
+
+		s := vault.New(service)
+		resp, err := s.GrantEmergencyAccess(ctxWithToken, &pb.GrantEmergencyAccessRequest{
+			Login:      "bob",
+			WrappedKey: []byte("key"),
+			Wait:       durationpb.New(72 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "user", resp.VaultId)
+		require.Equal(t, "alice", resp.VaultName)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().GrantEmergencyAccess(ctxWithToken, "user", "vault", grant).Return(vaultService.EmergencyGrant{}, vaultService.ErrUserNotFound)
+
+		s := vault.New(service)
+		_, err := s.GrantEmergencyAccess(ctxWithToken, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("grant exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().GrantEmergencyAccess(ctxWithToken, "user", "vault", grant).Return(vaultService.EmergencyGrant{}, vaultService.ErrGrantExists)
+
+		s := vault.New(service)
+		_, err := s.GrantEmergencyAccess(ctxWithToken, request)
+		require.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := vault.New(mocks.NewMockVaultService(ctrl))
+		_, err := s.GrantEmergencyAccess(ctx, request)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestServer_RequestEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		requestedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RequestEmergencyAccess(ctxWithToken, "user", "vault").Return(vaultService.EmergencyGrant{
+			VaultID:     "vault",
+			Wait:        time.Hour,
+			RequestedAt: requestedAt,
+		}, nil)
+
+		s := vault.New(service)
+		resp, err := s.RequestEmergencyAccess(ctxWithToken, &pb.RequestEmergencyAccessRequest{VaultId: "vault"})
+		require.NoError(t, err)
+		require.Equal(t, timestamppb.New(requestedAt), resp.RequestedAt)
+		require.Equal(t, timestamppb.New(requestedAt.Add(time.Hour)), resp.AvailableAt)
+	})
+
+	t.Run("grant not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RequestEmergencyAccess(ctxWithToken, "user", "vault").Return(vaultService.EmergencyGrant{}, vaultService.ErrGrantNotFound)
+
+		s := vault.New(service)
+		_, err := s.RequestEmergencyAccess(ctxWithToken, &pb.RequestEmergencyAccessRequest{VaultId: "vault"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_RejectEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RejectEmergencyAccess(ctxWithToken, "user", "vault", "bob").Return(vaultService.ErrPermissionDenied)
+
+		s := vault.New(service)
+		_, err := s.RejectEmergencyAccess(ctxWithToken, &pb.RejectEmergencyAccessRequest{VaultId: "vault", Login: "bob"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestServer_OpenEmergencyAccess(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().OpenEmergencyAccess(ctxWithToken, "user", "vault").Return(vaultService.Vault{
+			ID:         "vault",
+			Name:       "team",
+			Role:       vaultService.RoleViewer,
+			WrappedKey: []byte("key"),
+		}, nil)
+
+		s := vault.New(service)
+		resp, err := s.OpenEmergencyAccess(ctxWithToken, &pb.OpenEmergencyAccessRequest{VaultId: "vault"})
+		require.NoError(t, err)
+		require.Equal(t, "viewer", resp.Role)
+		require.Equal(t, []byte("key"), resp.WrappedKey)
+	})
+
+	t.Run("waiting period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().OpenEmergencyAccess(ctxWithToken, "user", "vault").Return(vaultService.Vault{}, vaultService.ErrWaitingPeriod)
+
+		s := vault.New(service)
+		_, err := s.OpenEmergencyAccess(ctxWithToken, &pb.OpenEmergencyAccessRequest{VaultId: "vault"})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().OpenEmergencyAccess(ctxWithToken, "user", "vault").Return(vaultService.Vault{}, vaultService.ErrNotRequested)
+
+		s := vault.New(service)
+		_, err := s.OpenEmergencyAccess(ctxWithToken, &pb.OpenEmergencyAccessRequest{VaultId: "vault"})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
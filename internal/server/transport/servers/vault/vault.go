@@ -0,0 +1,228 @@
+// Package vault provides the gRPC server implementation for managing team vaults and their members.
+package vault
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+// New creates a new instance of the VaultServiceServer.
+// It takes a vault.Service implementation and returns an implementation of pb.VaultServiceServer.
+func New(service vault.Service) pb.VaultServiceServer {
+	return &server{
+		service: service,
+	}
+}
+
+type server struct {
+	pb.UnsafeVaultServiceServer
+	service vault.Service
+}
+
+// CreateVault creates a vault with the user as its owner.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) CreateVault(ctx context.Context, request *pb.CreateVaultRequest) (*pb.Vault, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	v, err := s.service.CreateVault(ctx, tokenInfo.UserID, request.Name, request.Personal, request.WrappedKey)
+	if errors.Is(err, vault.ErrTooManyVaults) {
+		return nil, status.Error(codes.ResourceExhausted, "too many personal vaults")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant create vault")
+	}
+
+	return toPbVault(v), nil
+}
+
+// ListVaults returns the vaults the user is a member of.
+// It requires authentication and returns an error if the operation fails.
+func (s *server) ListVaults(ctx context.Context, _ *pb.ListVaultsRequest) (*pb.ListVaultsResponse, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	vaults, err := s.service.ListVaults(ctx, tokenInfo.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "cant list vaults")
+	}
+
+	response := &pb.ListVaultsResponse{
+		Vaults: make([]*pb.Vault, 0, len(vaults)),
+	}
+
+	for _, v := range vaults {
+		response.Vaults = append(response.Vaults, toPbVault(v))
+	}
+
+	return response, nil
+}
+
+// RenameVault changes the name of the vault.
+// It requires authentication and the owner role in the vault.
+func (s *server) RenameVault(ctx context.Context, request *pb.RenameVaultRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.RenameVault(ctx, tokenInfo.UserID, request.VaultId, request.Name)
+	if err != nil {
+		return nil, toStatusError(err, "cant rename vault")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteVault deletes the vault along with its members.
+// It requires authentication and the owner role in the vault, and the vault must have no entries.
+func (s *server) DeleteVault(ctx context.Context, request *pb.DeleteVaultRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.DeleteVault(ctx, tokenInfo.UserID, request.VaultId)
+	if err != nil {
+		if errors.Is(err, vault.ErrVaultNotEmpty) {
+			return nil, status.Error(codes.FailedPrecondition, "vault has entries")
+		}
+
+		return nil, toStatusError(err, "cant delete vault")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// AddMember adds a user to the vault.
+// It requires authentication and the owner role in the vault.
+func (s *server) AddMember(ctx context.Context, request *pb.AddMemberRequest) (*pb.Member, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	member, err := s.service.AddMember(ctx, tokenInfo.UserID, request.VaultId, vault.Member{
+		Login:      request.Login,
+		Role:       vault.Role(request.Role),
+		WrappedKey: request.WrappedKey,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, vault.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, vault.ErrMemberExists):
+			return nil, status.Error(codes.AlreadyExists, "user is a member already")
+		case errors.Is(err, vault.ErrInvalidRole):
+			return nil, status.Error(codes.InvalidArgument, "invalid role")
+		case errors.Is(err, vault.ErrPersonalVault):
+			return nil, status.Error(codes.FailedPrecondition, "personal vault can't have members")
+		default:
+			return nil, toStatusError(err, "cant add member")
+		}
+	}
+
+	return toPbMember(member), nil
+}
+
+// ListMembers returns the members of the vault.
+// It requires authentication and any role in the vault.
+func (s *server) ListMembers(ctx context.Context, request *pb.ListMembersRequest) (*pb.ListMembersResponse, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	members, err := s.service.ListMembers(ctx, tokenInfo.UserID, request.VaultId)
+	if err != nil {
+		return nil, toStatusError(err, "cant list members")
+	}
+
+	response := &pb.ListMembersResponse{
+		Members: make([]*pb.Member, 0, len(members)),
+	}
+
+	for _, member := range members {
+		response.Members = append(response.Members, toPbMember(member))
+	}
+
+	return response, nil
+}
+
+// RemoveMember removes a user from the vault.
+// It requires authentication and the owner role in the vault, unless the user removes themselves.
+func (s *server) RemoveMember(ctx context.Context, request *pb.RemoveMemberRequest) (*emptypb.Empty, error) {
+	tokenInfo, ok := auth.GetTokenInfo(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no token info")
+	}
+
+	err := s.service.RemoveMember(ctx, tokenInfo.UserID, request.VaultId, request.Login)
+	if err != nil {
+		switch {
+		case errors.Is(err, vault.ErrMemberNotFound):
+			return nil, status.Error(codes.NotFound, "member not found")
+		case errors.Is(err, vault.ErrLastOwner):
+			return nil, status.Error(codes.FailedPrecondition, "cant remove the last owner")
+		default:
+			return nil, toStatusError(err, "cant remove member")
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// toStatusError converts the errors of checking the access to the vault to the status errors,
+// other errors are reported as internal ones with the message.
+func toStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, vault.ErrVaultNotFound):
+		return status.Error(codes.NotFound, "vault not found")
+	case errors.Is(err, vault.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "not allowed by the role in the vault")
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func toPbVault(v vault.Vault) *pb.Vault {
+	result := &pb.Vault{
+		Id:         v.ID,
+		Name:       v.Name,
+		Role:       string(v.Role),
+		WrappedKey: v.WrappedKey,
+		Personal:   v.Personal,
+	}
+
+	if !v.CreatedAt.IsZero() {
+		result.CreatedAt = timestamppb.New(v.CreatedAt)
+	}
+
+	return result
+}
+
+func toPbMember(member vault.Member) *pb.Member {
+	result := &pb.Member{
+		Login: member.Login,
+		Role:  string(member.Role),
+	}
+
+	if !member.AddedAt.IsZero() {
+		result.AddedAt = timestamppb.New(member.AddedAt)
+	}
+
+	return result
+}
@@ -0,0 +1,303 @@
+package vault_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kuvalkin/gophkeeper/internal/server/service/user"
+	vaultService "github.com/kuvalkin/gophkeeper/internal/server/service/vault"
+	"github.com/kuvalkin/gophkeeper/internal/server/support/mocks"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/auth"
+	"github.com/kuvalkin/gophkeeper/internal/server/transport/servers/vault"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+	pb "github.com/kuvalkin/gophkeeper/pkg/proto/vault/v1"
+)
+
+func TestServer_CreateVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+
+		createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		service.EXPECT().CreateVault(ctxWithToken, "user", "team", true, []byte("key")).Return(vaultService.Vault{
+			ID:         "vault",
+			Name:       "team",
+			Role:       vaultService.RoleOwner,
+			WrappedKey: []byte("key"),
+			Personal:   true,
+			CreatedAt:  createdAt,
+		}, nil)
+
+		s := vault.New(service)
+		resp, err := s.CreateVault(ctxWithToken, &pb.CreateVaultRequest{Name: "team", WrappedKey: []byte("key"), Personal: true})
+		require.NoError(t, err)
+		require.Equal(t, "vault", resp.Id)
+		require.Equal(t, "owner", resp.Role)
+		require.True(t, resp.Personal)
+		require.Equal(t, timestamppb.New(createdAt), resp.CreatedAt)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := vault.New(mocks.NewMockVaultService(ctrl))
+		_, err := s.CreateVault(ctx, &pb.CreateVaultRequest{Name: "team"})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().CreateVault(ctxWithToken, "user", "team", false, []byte("key")).Return(vaultService.Vault{}, vaultService.ErrInternal)
+
+		s := vault.New(service)
+		_, err := s.CreateVault(ctxWithToken, &pb.CreateVaultRequest{Name: "team", WrappedKey: []byte("key")})
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("too many personal vaults", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().CreateVault(ctxWithToken, "user", "mine", true, []byte("key")).Return(vaultService.Vault{}, vaultService.ErrTooManyVaults)
+
+		s := vault.New(service)
+		_, err := s.CreateVault(ctxWithToken, &pb.CreateVaultRequest{Name: "mine", WrappedKey: []byte("key"), Personal: true})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestServer_RenameVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RenameVault(ctxWithToken, "user", "vault", "work").Return(nil)
+
+		s := vault.New(service)
+		_, err := s.RenameVault(ctxWithToken, &pb.RenameVaultRequest{VaultId: "vault", Name: "work"})
+		require.NoError(t, err)
+	})
+
+	t.Run("not an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RenameVault(ctxWithToken, "user", "vault", "work").Return(vaultService.ErrPermissionDenied)
+
+		s := vault.New(service)
+		_, err := s.RenameVault(ctxWithToken, &pb.RenameVaultRequest{VaultId: "vault", Name: "work"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestServer_DeleteVault(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().DeleteVault(ctxWithToken, "user", "vault").Return(nil)
+
+		s := vault.New(service)
+		_, err := s.DeleteVault(ctxWithToken, &pb.DeleteVaultRequest{VaultId: "vault"})
+		require.NoError(t, err)
+	})
+
+	t.Run("has entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().DeleteVault(ctxWithToken, "user", "vault").Return(vaultService.ErrVaultNotEmpty)
+
+		s := vault.New(service)
+		_, err := s.DeleteVault(ctxWithToken, &pb.DeleteVaultRequest{VaultId: "vault"})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().DeleteVault(ctxWithToken, "user", "vault").Return(vaultService.ErrVaultNotFound)
+
+		s := vault.New(service)
+		_, err := s.DeleteVault(ctxWithToken, &pb.DeleteVaultRequest{VaultId: "vault"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_AddMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	request := &pb.AddMemberRequest{VaultId: "vault", Login: "bob", Role: "viewer", WrappedKey: []byte("key")}
+	member := vaultService.Member{Login: "bob", Role: vaultService.RoleViewer, WrappedKey: []byte("key")}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().AddMember(ctxWithToken, "user", "vault", member).Return(vaultService.Member{Login: "bob", Role: vaultService.RoleViewer}, nil)
+
+		s := vault.New(service)
+		resp, err := s.AddMember(ctxWithToken, request)
+		require.NoError(t, err)
+		require.Equal(t, "bob", resp.Login)
+		require.Equal(t, "viewer", resp.Role)
+	})
+
+	errorCases := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"user not found", vaultService.ErrUserNotFound, codes.NotFound},
+		{"member exists", vaultService.ErrMemberExists, codes.AlreadyExists},
+		{"invalid role", vaultService.ErrInvalidRole, codes.InvalidArgument},
+		{"vault not found", vaultService.ErrVaultNotFound, codes.NotFound},
+		{"personal vault", vaultService.ErrPersonalVault, codes.FailedPrecondition},
+		{"permission denied", vaultService.ErrPermissionDenied, codes.PermissionDenied},
+		{"internal", errors.New("error"), codes.Internal},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockVaultService(ctrl)
+			service.EXPECT().AddMember(ctxWithToken, "user", "vault", member).Return(vaultService.Member{}, tc.err)
+
+			s := vault.New(service)
+			_, err := s.AddMember(ctxWithToken, request)
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestServer_ListMembers(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().ListMembers(ctxWithToken, "user", "vault").Return([]vaultService.Member{
+			{Login: "alice", Role: vaultService.RoleOwner},
+			{Login: "bob", Role: vaultService.RoleViewer},
+		}, nil)
+
+		s := vault.New(service)
+		resp, err := s.ListMembers(ctxWithToken, &pb.ListMembersRequest{VaultId: "vault"})
+		require.NoError(t, err)
+		require.Len(t, resp.Members, 2)
+		require.Equal(t, "bob", resp.Members[1].Login)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().ListMembers(ctxWithToken, "user", "vault").Return(nil, vaultService.ErrVaultNotFound)
+
+		s := vault.New(service)
+		_, err := s.ListMembers(ctxWithToken, &pb.ListMembersRequest{VaultId: "vault"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_RemoveMember(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	ctxWithToken := auth.SetTokenInfo(ctx, user.TokenInfo{
+		UserID: "user",
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RemoveMember(ctxWithToken, "user", "vault", "bob").Return(nil)
+
+		s := vault.New(service)
+		_, err := s.RemoveMember(ctxWithToken, &pb.RemoveMemberRequest{VaultId: "vault", Login: "bob"})
+		require.NoError(t, err)
+	})
+
+	t.Run("last owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RemoveMember(ctxWithToken, "user", "vault", "alice").Return(vaultService.ErrLastOwner)
+
+		s := vault.New(service)
+		_, err := s.RemoveMember(ctxWithToken, &pb.RemoveMemberRequest{VaultId: "vault", Login: "alice"})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("member not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mocks.NewMockVaultService(ctrl)
+		service.EXPECT().RemoveMember(ctxWithToken, "user", "vault", "dave").Return(vaultService.ErrMemberNotFound)
+
+		s := vault.New(service)
+		_, err := s.RemoveMember(ctxWithToken, &pb.RemoveMemberRequest{VaultId: "vault", Login: "dave"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
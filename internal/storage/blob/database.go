@@ -0,0 +1,584 @@
+package blob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+// dbChunkSize is the size of the rows the blobs are split into. Every writer buffers a chunk in memory.
+const dbChunkSize = 1024 * 1024
+
+// dbTouchInterval is how often a writer touches its staging blob while it's written to slower than a chunk at a time,
+// so CleanTemp doesn't take it for a leftover of a crashed server. It must be well below the age CleanTemp is called with.
+var dbTouchInterval = time.Minute
+
+// NewDatabaseBlobRepository creates a new instance of DatabaseBlobRepository.
+// The database must have the blobs and blob_chunks tables created by the server migrations.
+func NewDatabaseBlobRepository(db *sql.DB) *DatabaseBlobRepository {
+	return &DatabaseBlobRepository{
+		db:  db,
+		log: log.Logger().Named("blobs"),
+	}
+}
+
+// DatabaseBlobRepository is an implementation of the blob.Repository interface storing blobs in PostgreSQL,
+// so the whole vault lives in one database. Blobs are split into chunks stored as bytea rows.
+//
+// Writers insert the chunks into a staging blob without a key, each chunk with its own statement, and swap it in
+// with a short transaction on Close, so no connection is held while the data is uploaded, and a crashed
+// or failed write never leaves a torn blob. The staging blobs left by crashed servers are deleted by CleanTemp.
+type DatabaseBlobRepository struct {
+	db  *sql.DB
+	log *zap.SugaredLogger
+}
+
+// OpenBlobWriter opens a writer for the blob identified by the given key.
+// The blob is replaced only on successful Close, the returned writer implements Aborter.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (d *DatabaseBlobRepository) OpenBlobWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("cant clean key: %w", err)
+	}
+
+	d.log.Debugw("opening for write", "key", key)
+
+	id, err := d.insertStaging(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbWriter{ctx: ctx, db: d.db, id: id, key: key, replace: true, touched: time.Now(), log: d.log}, nil
+}
+
+// OpenBlobAppender opens a writer appending to the blob identified by the given key.
+// If the blob does not exist, it will be created. The appended data is visible only after Close,
+// which fails if the blob was changed in the meantime. The returned writer implements Aborter.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (d *DatabaseBlobRepository) OpenBlobAppender(ctx context.Context, key string) (io.WriteCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("cant clean key: %w", err)
+	}
+
+	d.log.Debugw("opening for append", "key", key)
+
+	var size int64
+	err = d.db.QueryRowContext(ctx, "SELECT size FROM blobs WHERE key = $1", key).Scan(&size)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cant get blob: %w", err)
+	}
+
+	id, err := d.insertStaging(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the chunks are stored at their offsets in the blob, so they can be moved to it as they are
+	return &dbWriter{ctx: ctx, db: d.db, id: id, key: key, start: size, offset: size, touched: time.Now(), log: d.log}, nil
+}
+
+// OpenBlobReader opens a reader for the blob identified by the given key.
+// Returns an io.ReadCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (d *DatabaseBlobRepository) OpenBlobReader(ctx context.Context, key string) (io.ReadCloser, bool, error) {
+	return d.OpenBlobSeeker(ctx, key)
+}
+
+// OpenBlobSeeker opens a seekable reader for the blob identified by the given key.
+// The chunks are queried as they are read, so reading fails if the blob is replaced or deleted in the meantime.
+// Returns an io.ReadSeekCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (d *DatabaseBlobRepository) OpenBlobSeeker(ctx context.Context, key string) (io.ReadSeekCloser, bool, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("cant clean key: %w", err)
+	}
+
+	d.log.Debugw("opening for read", "key", key)
+
+	var id, size int64
+	err = d.db.QueryRowContext(ctx, "SELECT id, size FROM blobs WHERE key = $1", key).Scan(&id, &size)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return &dbReader{ctx: ctx, db: d.db, id: id, size: size}, true, nil
+}
+
+// GetBlobSize returns the size of the blob identified by the given key in bytes,
+// a boolean indicating if the blob exists, and an error if the operation fails.
+func (d *DatabaseBlobRepository) GetBlobSize(ctx context.Context, key string) (int64, bool, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return 0, false, fmt.Errorf("cant clean key: %w", err)
+	}
+
+	var size int64
+	err = d.db.QueryRowContext(ctx, "SELECT size FROM blobs WHERE key = $1", key).Scan(&size)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("query error: %w", err)
+	}
+
+	return size, true, nil
+}
+
+// MoveBlob moves the blob identified by srcKey to dstKey, replacing the destination blob if it exists.
+// Only the key is changed, the content isn't copied.
+// Returns an error if the operation fails.
+func (d *DatabaseBlobRepository) MoveBlob(ctx context.Context, srcKey string, dstKey string) error {
+	srcKey, err := cleanKey(srcKey)
+	if err != nil {
+		return fmt.Errorf("cant clean source key: %w", err)
+	}
+
+	dstKey, err = cleanKey(dstKey)
+	if err != nil {
+		return fmt.Errorf("cant clean destination key: %w", err)
+	}
+
+	d.log.Debugw("moving", "from", srcKey, "to", dstKey)
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		// no-op after commit
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM blobs WHERE key = $1", dstKey)
+	if err != nil {
+		return fmt.Errorf("cant delete destination blob: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "UPDATE blobs SET key = $1 WHERE key = $2", dstKey, srcKey)
+	if err != nil {
+		return fmt.Errorf("cant update blob: %w", err)
+	}
+
+	err = requireAffected(result)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("cant commit: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlob deletes the blob identified by the given key.
+// Returns an error if the operation fails, wrapping os.ErrNotExist if there is no such blob.
+func (d *DatabaseBlobRepository) DeleteBlob(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return fmt.Errorf("cant clean key: %w", err)
+	}
+
+	d.log.Debugw("deleting", "key", key)
+
+	// chunks are deleted by cascade
+	result, err := d.db.ExecContext(ctx, "DELETE FROM blobs WHERE key = $1", key)
+	if err != nil {
+		return fmt.Errorf("cant delete blob: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// WalkBlobs calls fn for every stored blob, in lexical order of the keys. Walking stops at the first error returned by fn.
+// Blobs being written are not walked.
+// Returns the error of fn or an error if the operation fails.
+func (d *DatabaseBlobRepository) WalkBlobs(ctx context.Context, fn func(info Info) error) error {
+	rows, err := d.db.QueryContext(ctx, "SELECT key, size, updated_at FROM blobs WHERE key IS NOT NULL ORDER BY key")
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var info Info
+		err = rows.Scan(&info.Key, &info.Size, &info.ModifiedAt)
+		if err != nil {
+			return fmt.Errorf("scan error: %w", err)
+		}
+
+		err = fn(info)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	return nil
+}
+
+// CleanTemp deletes the staging blobs of the writers of crashed servers, last written before modifiedBefore.
+// Returns the number of deleted blobs or an error if the operation fails.
+func (d *DatabaseBlobRepository) CleanTemp(ctx context.Context, modifiedBefore time.Time) (int, error) {
+	// chunks are deleted by cascade
+	result, err := d.db.ExecContext(ctx, "DELETE FROM blobs WHERE key IS NULL AND updated_at < $1", modifiedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("cant delete staging blobs: %w", err)
+	}
+
+	cleaned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	return int(cleaned), nil
+}
+
+// insertStaging inserts a blob without a key for a writer, it isn't visible until the writer is closed.
+func (d *DatabaseBlobRepository) insertStaging(ctx context.Context) (int64, error) {
+	var id int64
+	err := d.db.QueryRowContext(ctx, "INSERT INTO blobs DEFAULT VALUES RETURNING id").Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("cant insert blob: %w", err)
+	}
+
+	return id, nil
+}
+
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cant get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("blob not found: %w", os.ErrNotExist)
+	}
+
+	return nil
+}
+
+// errBlobChanged is returned by Close of an appender if the blob was changed since it was opened.
+var errBlobChanged = errors.New("blob was changed during the append")
+
+// dbWriter splits the written data into chunks inserted into the staging blob, which is swapped in on Close.
+type dbWriter struct {
+	ctx context.Context
+	db  *sql.DB
+	// id of the staging blob
+	id int64
+	// key is set on the blob on Close
+	key string
+	// replace means the blob with the key is replaced by the staging one on Close,
+	// otherwise the chunks of the staging blob are appended to it
+	replace bool
+	// start is the size of the blob the chunks are appended to when it was opened
+	start int64
+	// offset of the buffered chunk in the blob
+	offset int64
+	buf    []byte
+	// touched is when the updated_at of the staging blob was last set
+	touched time.Time
+	// err is the error of the last flush, the staging blob can't be swapped in after it
+	err  error
+	log  *zap.SugaredLogger
+	done bool
+}
+
+func (w *dbWriter) Write(p []byte) (int, error) {
+	if w.done {
+		return 0, os.ErrClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	written := 0
+	for written < len(p) {
+		n := min(dbChunkSize-len(w.buf), len(p)-written)
+		w.buf = append(w.buf, p[written:written+n]...)
+		written += n
+
+		if len(w.buf) == dbChunkSize {
+			err := w.flush()
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	if time.Since(w.touched) >= dbTouchInterval {
+		w.touch()
+	}
+
+	return written, nil
+}
+
+// touch keeps the staging blob from being cleaned up while only the buffer is written to.
+// Errors are only logged, the touch is retried on the next write.
+func (w *dbWriter) touch() {
+	_, err := w.db.ExecContext(w.ctx, "UPDATE blobs SET updated_at = now() WHERE id = $1", w.id)
+	if err != nil {
+		w.log.Errorw("cant touch staging blob", "key", w.key, "err", err)
+
+		return
+	}
+
+	w.touched = time.Now()
+}
+
+func (w *dbWriter) flush() error {
+	// touching the staging blob keeps it from being cleaned up while the write is in progress
+	_, err := w.db.ExecContext(
+		w.ctx,
+		"WITH c AS (INSERT INTO blob_chunks (blob_id, start_offset, data) VALUES ($1, $2, $3)) "+
+			"UPDATE blobs SET updated_at = now() WHERE id = $1",
+		w.id,
+		w.offset,
+		w.buf,
+	)
+	if err != nil {
+		w.err = fmt.Errorf("cant insert chunk: %w", err)
+
+		return w.err
+	}
+
+	w.offset += int64(len(w.buf))
+	w.buf = w.buf[:0]
+	w.touched = time.Now()
+
+	return nil
+}
+
+// Close stores the rest of the data and swaps the staging blob in.
+// If anything fails, the staging blob is deleted and the blob is left intact.
+func (w *dbWriter) Close() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	err := w.commit()
+	if err != nil {
+		w.discard()
+
+		return err
+	}
+
+	return nil
+}
+
+func (w *dbWriter) commit() (err error) {
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.buf) > 0 {
+		err = w.flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := w.db.BeginTx(w.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cant begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if w.replace {
+		err = w.swap(tx)
+	} else {
+		err = w.append(tx)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("cant commit: %w", err)
+	}
+
+	return nil
+}
+
+// swap replaces the blob with the key by the staging one.
+func (w *dbWriter) swap(tx *sql.Tx) error {
+	_, err := tx.ExecContext(w.ctx, "DELETE FROM blobs WHERE key = $1", w.key)
+	if err != nil {
+		return fmt.Errorf("cant delete replaced blob: %w", err)
+	}
+
+	_, err = tx.ExecContext(w.ctx, "UPDATE blobs SET key = $1, size = $2, updated_at = now() WHERE id = $3", w.key, w.offset, w.id)
+	if err != nil {
+		return fmt.Errorf("cant update blob: %w", err)
+	}
+
+	return nil
+}
+
+// append moves the chunks of the staging blob to the blob with the key, which must still have the size it had
+// when the writer was opened. If there is no such blob, the staging one becomes it.
+func (w *dbWriter) append(tx *sql.Tx) error {
+	var id, size int64
+	err := tx.QueryRowContext(w.ctx, "SELECT id, size FROM blobs WHERE key = $1 FOR UPDATE", w.key).Scan(&id, &size)
+	if errors.Is(err, sql.ErrNoRows) {
+		if w.start != 0 {
+			return errBlobChanged
+		}
+
+		return w.swap(tx)
+	}
+	if err != nil {
+		return fmt.Errorf("cant get blob: %w", err)
+	}
+
+	if size != w.start {
+		return errBlobChanged
+	}
+
+	_, err = tx.ExecContext(w.ctx, "UPDATE blob_chunks SET blob_id = $1 WHERE blob_id = $2", id, w.id)
+	if err != nil {
+		return fmt.Errorf("cant move chunks: %w", err)
+	}
+
+	_, err = tx.ExecContext(w.ctx, "UPDATE blobs SET size = $1, updated_at = now() WHERE id = $2", w.offset, id)
+	if err != nil {
+		return fmt.Errorf("cant update blob: %w", err)
+	}
+
+	_, err = tx.ExecContext(w.ctx, "DELETE FROM blobs WHERE id = $1", w.id)
+	if err != nil {
+		return fmt.Errorf("cant delete staging blob: %w", err)
+	}
+
+	return nil
+}
+
+// Abort deletes the staging blob, the blob is left intact.
+func (w *dbWriter) Abort() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	w.log.Debugw("aborting write", "key", w.key)
+
+	w.discard()
+
+	return nil
+}
+
+// discard deletes the staging blob along with its chunks. Errors are only logged, CleanTemp deletes it later.
+func (w *dbWriter) discard() {
+	_, err := w.db.ExecContext(context.WithoutCancel(w.ctx), "DELETE FROM blobs WHERE id = $1", w.id)
+	if err != nil {
+		w.log.Errorw("cant delete staging blob", "key", w.key, "err", err)
+	}
+}
+
+// dbReader reads the chunks of a blob one by one.
+type dbReader struct {
+	ctx  context.Context
+	db   *sql.DB
+	id   int64
+	size int64
+	pos  int64
+	// chunk is the last read chunk starting at chunkOffset
+	chunk       []byte
+	chunkOffset int64
+	done        bool
+}
+
+func (r *dbReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, os.ErrClosed
+	}
+
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.pos < r.chunkOffset || r.pos >= r.chunkOffset+int64(len(r.chunk)) {
+		err := r.db.QueryRowContext(
+			r.ctx,
+			"SELECT start_offset, data FROM blob_chunks WHERE blob_id = $1 AND start_offset <= $2 ORDER BY start_offset DESC LIMIT 1",
+			r.id,
+			r.pos,
+		).Scan(&r.chunkOffset, &r.chunk)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("blob was replaced or deleted during reading")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("cant query chunk: %w", err)
+		}
+
+		if r.pos >= r.chunkOffset+int64(len(r.chunk)) {
+			return 0, fmt.Errorf("blob was replaced or deleted during reading")
+		}
+	}
+
+	n := copy(p, r.chunk[r.pos-r.chunkOffset:])
+	r.pos += int64(n)
+
+	return n, nil
+}
+
+func (r *dbReader) Seek(offset int64, whence int) (int64, error) {
+	if r.done {
+		return 0, os.ErrClosed
+	}
+
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if pos < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+
+	r.pos = pos
+
+	return pos, nil
+}
+
+func (r *dbReader) Close() error {
+	if r.done {
+		return os.ErrClosed
+	}
+	r.done = true
+
+	return nil
+}
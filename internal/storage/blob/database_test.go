@@ -0,0 +1,520 @@
+package blob_test
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+// chunkInsert is the statement storing a chunk of a staging blob.
+const chunkInsert = "WITH c AS \\(INSERT INTO blob_chunks \\(blob_id, start_offset, data\\) VALUES \\(\\$1, \\$2, \\$3\\)\\) UPDATE blobs SET updated_at = now\\(\\) WHERE id = \\$1"
+
+func TestDatabase_Writer(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobWriter(ctx, "test/key")
+		require.NoError(t, err)
+
+		// a full chunk is inserted right away
+		first := strings.Repeat("a", 1024*1024)
+		mock.ExpectExec(chunkInsert).
+			WithArgs(2, 0, []byte(first)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		n, err := wc.Write([]byte(first + "rest"))
+		require.NoError(t, err)
+		require.Equal(t, len(first)+4, n)
+
+		mock.ExpectExec(chunkInsert).
+			WithArgs(2, len(first), []byte("rest")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// the staging blob is swapped in with a short transaction
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("test/key").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE blobs SET key = \\$1, size = \\$2, updated_at = now\\(\\) WHERE id = \\$3").
+			WithArgs("test/key", len(first)+4, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, wc.Close())
+		require.ErrorIs(t, wc.Close(), os.ErrClosed)
+	})
+
+	t.Run("slow write touches staging blob", func(t *testing.T) {
+		defer blob.SetDBTouchInterval(0)()
+
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobWriter(ctx, "key")
+		require.NoError(t, err)
+
+		// nothing is flushed, but the staging blob must not look abandoned
+		mock.ExpectExec("UPDATE blobs SET updated_at = now\\(\\) WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err = wc.Write([]byte("content"))
+		require.NoError(t, err)
+
+		// a failed touch doesn't fail the write, it's retried on the next one
+		mock.ExpectExec("UPDATE blobs SET updated_at = now\\(\\) WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnError(errors.New("db error"))
+		_, err = wc.Write([]byte("content"))
+		require.NoError(t, err)
+
+		mock.ExpectExec("DELETE FROM blobs WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		require.NoError(t, wc.(blob.Aborter).Abort())
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM blobs WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobWriter(ctx, "key")
+		require.NoError(t, err)
+
+		_, err = wc.Write([]byte("content"))
+		require.NoError(t, err)
+
+		aborter, ok := wc.(blob.Aborter)
+		require.True(t, ok)
+		require.NoError(t, aborter.Abort())
+
+		require.ErrorIs(t, wc.Close(), os.ErrClosed)
+	})
+
+	t.Run("failed close deletes staging blob", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec(chunkInsert).
+			WillReturnError(errors.New("db error"))
+		mock.ExpectExec("DELETE FROM blobs WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobWriter(ctx, "key")
+		require.NoError(t, err)
+
+		_, err = wc.Write([]byte("content"))
+		require.NoError(t, err)
+
+		require.Error(t, wc.Close())
+	})
+
+	t.Run("failed swap is rolled back", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+		mock.ExpectExec("DELETE FROM blobs WHERE id = \\$1").
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobWriter(ctx, "key")
+		require.NoError(t, err)
+
+		require.Error(t, wc.Close())
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		db, _ := newBlobsDB(t)
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobWriter(ctx, "../test")
+		require.Error(t, err)
+		require.Nil(t, wc)
+	})
+}
+
+func TestDatabase_Appender(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("existing", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(10))
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(chunkInsert).
+			WithArgs(3, 10, []byte("content")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, size FROM blobs WHERE key = \\$1 FOR UPDATE").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "size"}).AddRow(2, 10))
+		mock.ExpectExec("UPDATE blob_chunks SET blob_id = \\$1 WHERE blob_id = \\$2").
+			WithArgs(2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE blobs SET size = \\$1, updated_at = now\\(\\) WHERE id = \\$2").
+			WithArgs(17, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM blobs WHERE id = \\$1").
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobAppender(ctx, "key")
+		require.NoError(t, err)
+
+		_, err = wc.Write([]byte("content"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	})
+
+	t.Run("new", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, size FROM blobs WHERE key = \\$1 FOR UPDATE").
+			WithArgs("key").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE blobs SET key = \\$1, size = \\$2, updated_at = now\\(\\) WHERE id = \\$3").
+			WithArgs("key", 0, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobAppender(ctx, "key")
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	})
+
+	t.Run("changed in the meantime", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(10))
+		mock.ExpectQuery("INSERT INTO blobs DEFAULT VALUES RETURNING id").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, size FROM blobs WHERE key = \\$1 FOR UPDATE").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "size"}).AddRow(2, 12))
+		mock.ExpectRollback()
+		mock.ExpectExec("DELETE FROM blobs WHERE id = \\$1").
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobAppender(ctx, "key")
+		require.NoError(t, err)
+		require.Error(t, wc.Close())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnError(errors.New("db error"))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		wc, err := repo.OpenBlobAppender(ctx, "key")
+		require.Error(t, err)
+		require.Nil(t, wc)
+	})
+}
+
+func TestDatabase_CleanTemp(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	db, mock := newBlobsDB(t)
+
+	before := time.Now().Add(-time.Hour)
+	mock.ExpectExec("DELETE FROM blobs WHERE key IS NULL AND updated_at < \\$1").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := blob.NewDatabaseBlobRepository(db)
+	cleaned, err := repo.CleanTemp(ctx, before)
+	require.NoError(t, err)
+	require.Equal(t, 2, cleaned)
+}
+
+func TestDatabase_Seeker(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	chunkQuery := "SELECT start_offset, data FROM blob_chunks WHERE blob_id = \\$1 AND start_offset <= \\$2 ORDER BY start_offset DESC LIMIT 1"
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT id, size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "size"}).AddRow(2, 12))
+		mock.ExpectQuery(chunkQuery).
+			WithArgs(2, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"start_offset", "data"}).AddRow(0, []byte("test ")))
+		mock.ExpectQuery(chunkQuery).
+			WithArgs(2, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"start_offset", "data"}).AddRow(5, []byte("content")))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		_, err = rsc.Seek(3, io.SeekStart)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(rsc)
+		require.NoError(t, err)
+		require.Equal(t, "t content", string(content))
+
+		require.NoError(t, rsc.Close())
+	})
+
+	t.Run("replaced during reading", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT id, size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "size"}).AddRow(2, 12))
+		mock.ExpectQuery(chunkQuery).
+			WithArgs(2, 0).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		_, err = io.ReadAll(rsc)
+		require.Error(t, err)
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT id, size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "key")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+	})
+}
+
+func TestDatabase_Size(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(7))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		size, exists, err := repo.GetBlobSize(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, int64(7), size)
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT size FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		size, exists, err := repo.GetBlobSize(ctx, "key")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Zero(t, size)
+	})
+}
+
+func TestDatabase_Move(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("dst").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE blobs SET key = \\$1 WHERE key = \\$2").
+			WithArgs("dst", "src").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		require.NoError(t, repo.MoveBlob(ctx, "src", "dst"))
+	})
+
+	t.Run("source not exists", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("dst").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE blobs SET key = \\$1 WHERE key = \\$2").
+			WithArgs("dst", "src").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		require.ErrorIs(t, repo.MoveBlob(ctx, "src", "dst"), os.ErrNotExist)
+	})
+}
+
+func TestDatabase_Delete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		require.NoError(t, repo.DeleteBlob(ctx, "key"))
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectExec("DELETE FROM blobs WHERE key = \\$1").
+			WithArgs("key").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repo := blob.NewDatabaseBlobRepository(db)
+		require.ErrorIs(t, repo.DeleteBlob(ctx, "key"), os.ErrNotExist)
+	})
+}
+
+func TestDatabase_Walk(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	modifiedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT key, size, updated_at FROM blobs WHERE key IS NOT NULL ORDER BY key").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "size", "updated_at"}).
+					AddRow("a/b", 7, modifiedAt).
+					AddRow("c", 0, modifiedAt),
+			)
+
+		repo := blob.NewDatabaseBlobRepository(db)
+
+		var infos []blob.Info
+		err := repo.WalkBlobs(ctx, func(info blob.Info) error {
+			infos = append(infos, info)
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []blob.Info{
+			{Key: "a/b", Size: 7, ModifiedAt: modifiedAt},
+			{Key: "c", Size: 0, ModifiedAt: modifiedAt},
+		}, infos)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		db, mock := newBlobsDB(t)
+
+		mock.ExpectQuery("SELECT key, size, updated_at FROM blobs WHERE key IS NOT NULL ORDER BY key").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"key", "size", "updated_at"}).
+					AddRow("a/b", 7, modifiedAt).
+					AddRow("c", 0, modifiedAt),
+			)
+
+		repo := blob.NewDatabaseBlobRepository(db)
+
+		testErr := errors.New("test")
+		calls := 0
+		err := repo.WalkBlobs(ctx, func(info blob.Info) error {
+			calls++
+
+			return testErr
+		})
+		require.ErrorIs(t, err, testErr)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func newBlobsDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	return db, mock
+}
@@ -0,0 +1,925 @@
+package blob
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+// Encrypted blobs start with a header holding the data key of the blob, wrapped by a master key:
+//
+//	magic (4) | version (1) | master key ID (8) | nonce (12) | wrapped data key (32 + 16)
+//
+// followed by the content written in parts, one by the writer and one by every appender, so appending never changes
+// the stored data. A part is split into segments of segmentSize bytes, each sealed separately, so the blob can be read
+// from an offset, and ends with a sealed trailer holding the index and the size of the part:
+//
+//	segment: nonce (12) | ciphertext (up to segmentSize) | tag (16)
+//	trailer: nonce (12) | part index (8) | part size (8) | tag (16)
+//
+// The indexes of a segment and of its part are authenticated, so the segments can't be reordered, and the parts are
+// found by their trailers from the end of the blob, so the blob can't be cut off, except back to the end of a part,
+// which is the blob as it was before an append.
+//
+// Blobs of the legacy version are a single part without a trailer, whose last segment is marked instead.
+const (
+	headerMagic   = "GKEB"
+	legacyVersion = 1
+	headerVersion = 2
+	keyIDSize     = 8
+	dataKeySize   = 32
+	nonceSize     = 12
+	tagSize       = 16
+	headerSize    = len(headerMagic) + 1 + keyIDSize + nonceSize + dataKeySize + tagSize
+
+	segmentSize      = 64 * 1024
+	segmentOverhead  = nonceSize + tagSize
+	sealedSegmentLen = segmentSize + segmentOverhead
+	trailerSize      = nonceSize + 16 + tagSize
+)
+
+// errNotEncrypted is returned for the blobs without a header, unless plaintext blobs are allowed.
+var errNotEncrypted = errors.New("blob isn't encrypted")
+
+// NewEncryptedBlobRepository creates a new instance of EncryptedBlobRepository storing the blobs in the inner repository.
+// masterKey is the 32 bytes key wrapping the data keys of the written blobs. The blobs wrapped by oldKeys can still be read,
+// until they are re-wrapped with RewrapBlobs.
+func NewEncryptedBlobRepository(inner Repository, masterKey []byte, oldKeys ...[]byte) (*EncryptedBlobRepository, error) {
+	current, err := newMasterKey(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+
+	keys := map[[keyIDSize]byte]cipher.AEAD{current.id: current.aead}
+	for i, oldKey := range oldKeys {
+		old, err := newMasterKey(oldKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid old master key #%d: %w", i+1, err)
+		}
+
+		keys[old.id] = old.aead
+	}
+
+	return &EncryptedBlobRepository{
+		inner:   inner,
+		current: current,
+		keys:    keys,
+		log:     log.Logger().Named("blobs"),
+	}, nil
+}
+
+// EncryptedBlobRepository is a blob.Repository decorator encrypting the blobs at rest.
+// Every blob is encrypted with its own random data key, stored in the blob wrapped by the master key.
+//
+// Blobs without a header fail to be read, unless AllowPlaintext is called to migrate the blobs stored
+// before the encryption was enabled. Sizes reported by WalkBlobs are the sizes of the stored, encrypted blobs.
+type EncryptedBlobRepository struct {
+	inner   Repository
+	current masterKey
+	// keys contains all known master keys, including the current one, by their IDs
+	keys           map[[keyIDSize]byte]cipher.AEAD
+	allowPlaintext bool
+	log            *zap.SugaredLogger
+}
+
+// AllowPlaintext makes the blobs stored before the encryption was enabled, which don't start with a header,
+// readable and appendable as they are, and lets RewrapBlobs encrypt them. It's meant only for the migration,
+// otherwise a blob whose header was damaged or stripped would be served as plaintext.
+func (e *EncryptedBlobRepository) AllowPlaintext() {
+	e.allowPlaintext = true
+}
+
+type masterKey struct {
+	id   [keyIDSize]byte
+	aead cipher.AEAD
+}
+
+func newMasterKey(key []byte) (masterKey, error) {
+	if len(key) != 32 {
+		return masterKey{}, fmt.Errorf("key must be 32 bytes long, got %d", len(key))
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return masterKey{}, err
+	}
+
+	// the key is identified by its hash, so the keys don't have to be named in the config
+	sum := sha256.Sum256(key)
+
+	mk := masterKey{aead: aead}
+	copy(mk.id[:], sum[:keyIDSize])
+
+	return mk, nil
+}
+
+// OpenBlobWriter opens a writer for the blob identified by the given key, encrypting the written data with a new data key.
+// The returned writer implements Aborter, aborting the inner writer if it supports it.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (e *EncryptedBlobRepository) OpenBlobWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	dataKey := make([]byte, dataKeySize)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cant generate data key: %w", err)
+	}
+
+	header, err := e.wrap(dataKey, headerVersion)
+	if err != nil {
+		return nil, fmt.Errorf("cant wrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cant create cipher: %w", err)
+	}
+
+	dst, err := e.inner.OpenBlobWriter(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cant open inner writer: %w", err)
+	}
+
+	_, err = dst.Write(header)
+	if err != nil {
+		abort(dst)
+
+		return nil, fmt.Errorf("cant write header: %w", err)
+	}
+
+	return &encryptingWriter{dst: dst, aead: aead}, nil
+}
+
+// OpenBlobAppender opens a writer appending to the blob identified by the given key.
+// If the blob does not exist, it will be created. The written data is appended to the inner blob as a new part,
+// so only the trailers of the existing parts are read. If the inner appender doesn't implement Aborter,
+// a failed append leaves a torn part, and the blob fails to be read.
+// Blobs of the legacy version are copied into a new blob replacing them on Close, once.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (e *EncryptedBlobRepository) OpenBlobAppender(ctx context.Context, key string) (io.WriteCloser, error) {
+	src, ok, err := e.inner.OpenBlobSeeker(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cant open inner reader: %w", err)
+	}
+	if !ok {
+		return e.OpenBlobWriter(ctx, key)
+	}
+	defer src.Close()
+
+	eb, err := e.readBlob(src)
+	if err != nil {
+		return nil, err
+	}
+	if eb == nil {
+		return e.inner.OpenBlobAppender(ctx, key)
+	}
+
+	if eb.version == legacyVersion {
+		e.log.Debugw("copying legacy blob to append", "key", key)
+
+		return e.copyForAppend(ctx, key, src, eb)
+	}
+
+	dst, err := e.inner.OpenBlobAppender(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cant open inner appender: %w", err)
+	}
+
+	return &encryptingWriter{dst: dst, aead: eb.aead, part: uint64(len(eb.parts))}, nil
+}
+
+// copyForAppend copies the decrypted content of the blob into a new blob, which continues to be written by the returned writer.
+func (e *EncryptedBlobRepository) copyForAppend(ctx context.Context, key string, src io.ReadSeeker, eb *encryptedBlob) (io.WriteCloser, error) {
+	dst, err := e.OpenBlobWriter(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.Copy(dst, newDecryptingReader(src, eb))
+	if err != nil {
+		abort(dst)
+
+		return nil, fmt.Errorf("cant copy blob: %w", err)
+	}
+
+	return dst, nil
+}
+
+// OpenBlobReader opens a reader for the blob identified by the given key.
+// Returns an io.ReadCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (e *EncryptedBlobRepository) OpenBlobReader(ctx context.Context, key string) (io.ReadCloser, bool, error) {
+	return e.OpenBlobSeeker(ctx, key)
+}
+
+// OpenBlobSeeker opens a seekable reader for the blob identified by the given key, decrypting the blob segment by segment.
+// Reading fails if the blob was tampered with.
+// Returns an io.ReadSeekCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (e *EncryptedBlobRepository) OpenBlobSeeker(ctx context.Context, key string) (io.ReadSeekCloser, bool, error) {
+	src, ok, err := e.inner.OpenBlobSeeker(ctx, key)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	r, err := e.newReader(src)
+	if err != nil {
+		_ = src.Close()
+
+		return nil, false, err
+	}
+
+	return r, true, nil
+}
+
+func (e *EncryptedBlobRepository) newReader(src io.ReadSeekCloser) (io.ReadSeekCloser, error) {
+	eb, err := e.readBlob(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if eb == nil {
+		_, err = src.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, fmt.Errorf("cant seek: %w", err)
+		}
+
+		return src, nil
+	}
+
+	return newDecryptingReader(src, eb), nil
+}
+
+// GetBlobSize returns the size of the decrypted blob identified by the given key in bytes,
+// a boolean indicating if the blob exists, and an error if the operation fails.
+func (e *EncryptedBlobRepository) GetBlobSize(ctx context.Context, key string) (int64, bool, error) {
+	src, ok, err := e.inner.OpenBlobSeeker(ctx, key)
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+	defer src.Close()
+
+	eb, err := e.readBlob(src)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if eb == nil {
+		size, err := src.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false, fmt.Errorf("cant seek: %w", err)
+		}
+
+		return size, true, nil
+	}
+
+	return eb.size, true, nil
+}
+
+// MoveBlob moves the blob identified by srcKey to dstKey, replacing the destination blob if it exists.
+// The blob isn't bound to its key, so it's moved as is.
+// Returns an error if the operation fails.
+func (e *EncryptedBlobRepository) MoveBlob(ctx context.Context, srcKey string, dstKey string) error {
+	return e.inner.MoveBlob(ctx, srcKey, dstKey)
+}
+
+// DeleteBlob deletes the blob identified by the given key.
+// Returns an error if the operation fails.
+func (e *EncryptedBlobRepository) DeleteBlob(ctx context.Context, key string) error {
+	return e.inner.DeleteBlob(ctx, key)
+}
+
+// WalkBlobs calls fn for every stored blob. Walking stops at the first error returned by fn.
+// Returns the error of fn or an error if the operation fails.
+func (e *EncryptedBlobRepository) WalkBlobs(ctx context.Context, fn func(info Info) error) error {
+	return e.inner.WalkBlobs(ctx, fn)
+}
+
+// CleanTemp deletes the temporary data of the inner repository last written before modifiedBefore.
+// Returns zero if the inner repository doesn't implement TempCleaner.
+func (e *EncryptedBlobRepository) CleanTemp(ctx context.Context, modifiedBefore time.Time) (int, error) {
+	cleaner, ok := e.inner.(TempCleaner)
+	if !ok {
+		return 0, nil
+	}
+
+	return cleaner.CleanTemp(ctx, modifiedBefore)
+}
+
+// RewrapBlobs wraps the data keys of the blobs wrapped by the old master keys with the current one,
+// and encrypts the blobs stored before the encryption was enabled if AllowPlaintext was called,
+// otherwise it fails on them. It returns the number of changed blobs.
+// Only the headers are re-wrapped, the content is copied as is. The blobs are replaced by new ones,
+// so it must not be run while the blobs are written by a server, or newer content may be overwritten.
+func (e *EncryptedBlobRepository) RewrapBlobs(ctx context.Context) (int, error) {
+	// collected first, because rewriting the blobs may change the listing
+	var keys []string
+	err := e.inner.WalkBlobs(ctx, func(info Info) error {
+		keys = append(keys, info.Key)
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cant walk blobs: %w", err)
+	}
+
+	rewrapped := 0
+	for _, key := range keys {
+		changed, err := e.rewrapBlob(ctx, key)
+		if err != nil {
+			return rewrapped, fmt.Errorf("cant rewrap blob %s: %w", key, err)
+		}
+
+		if changed {
+			rewrapped++
+		}
+	}
+
+	e.log.Infow("rewrapped blobs", "blobs", len(keys), "rewrapped", rewrapped)
+
+	return rewrapped, nil
+}
+
+func (e *EncryptedBlobRepository) rewrapBlob(ctx context.Context, key string) (bool, error) {
+	src, ok, err := e.inner.OpenBlobSeeker(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("cant open inner reader: %w", err)
+	}
+	if !ok {
+		// deleted since listed
+		return false, nil
+	}
+	defer src.Close()
+
+	eb, err := e.readBlob(src)
+	if err != nil {
+		return false, err
+	}
+
+	var dst io.WriteCloser
+	var offset int64
+	if eb != nil {
+		if bytes.Equal(eb.header[len(headerMagic)+1:len(headerMagic)+1+keyIDSize], e.current.id[:]) {
+			return false, nil
+		}
+
+		offset = int64(headerSize)
+		dst, err = e.rewrapHeader(ctx, key, eb.dataKey, eb.version)
+	} else {
+		dst, err = e.OpenBlobWriter(ctx, key)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	e.log.Debugw("rewrapping", "key", key, "encrypted", eb != nil)
+
+	_, err = src.Seek(offset, io.SeekStart)
+	if err != nil {
+		abort(dst)
+
+		return false, fmt.Errorf("cant seek: %w", err)
+	}
+
+	// the rest of the encrypted blob is copied as is, while a plain blob is encrypted
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		abort(dst)
+
+		return false, fmt.Errorf("cant copy blob: %w", err)
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return false, fmt.Errorf("cant close writer: %w", err)
+	}
+
+	return true, nil
+}
+
+// rewrapHeader opens the inner writer of the blob and writes the header with the data key wrapped by the current master key.
+// The version is kept, as the content is copied as is.
+func (e *EncryptedBlobRepository) rewrapHeader(ctx context.Context, key string, dataKey []byte, version byte) (io.WriteCloser, error) {
+	header, err := e.wrap(dataKey, version)
+	if err != nil {
+		return nil, fmt.Errorf("cant wrap data key: %w", err)
+	}
+
+	dst, err := e.inner.OpenBlobWriter(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cant open inner writer: %w", err)
+	}
+
+	_, err = dst.Write(header)
+	if err != nil {
+		abort(dst)
+
+		return nil, fmt.Errorf("cant write header: %w", err)
+	}
+
+	return dst, nil
+}
+
+// wrap returns the header of the version with the data key wrapped by the current master key.
+func (e *EncryptedBlobRepository) wrap(dataKey []byte, version byte) ([]byte, error) {
+	header := make([]byte, 0, headerSize)
+	header = append(header, headerMagic...)
+	header = append(header, version)
+	header = append(header, e.current.id[:]...)
+
+	nonce := make([]byte, nonceSize)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("cant generate nonce: %w", err)
+	}
+
+	// the beginning of the header is authenticated, so the key ID can't be changed
+	return e.current.aead.Seal(append(header, nonce...), nonce, dataKey, header), nil
+}
+
+// readHeader reads the header from the beginning of the blob and unwraps its data key.
+// If the blob doesn't start with a header, it was stored before the encryption was enabled and encrypted is false.
+func (e *EncryptedBlobRepository) readHeader(src io.Reader) (header []byte, dataKey []byte, encrypted bool, err error) {
+	header = make([]byte, headerSize)
+	_, err = io.ReadFull(src, header)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("cant read: %w", err)
+	}
+
+	if string(header[:len(headerMagic)]) != headerMagic {
+		return nil, nil, false, nil
+	}
+
+	if header[len(headerMagic)] != legacyVersion && header[len(headerMagic)] != headerVersion {
+		return nil, nil, false, fmt.Errorf("unsupported version %d", header[len(headerMagic)])
+	}
+
+	idEnd := len(headerMagic) + 1 + keyIDSize
+	var id [keyIDSize]byte
+	copy(id[:], header[len(headerMagic)+1:idEnd])
+
+	masterAEAD, ok := e.keys[id]
+	if !ok {
+		return nil, nil, false, fmt.Errorf("blob is encrypted with unknown master key %x", id)
+	}
+
+	nonce := header[idEnd : idEnd+nonceSize]
+	dataKey, err = masterAEAD.Open(nil, nonce, header[idEnd+nonceSize:], header[:idEnd])
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("cant unwrap data key: %w", err)
+	}
+
+	return header, dataKey, true, nil
+}
+
+// encryptedBlob is the layout of an encrypted blob.
+type encryptedBlob struct {
+	header  []byte
+	version byte
+	dataKey []byte
+	aead    cipher.AEAD
+	parts   []blobPart
+	// size of the decrypted content
+	size int64
+}
+
+// blobPart is where a part of the blob is stored.
+type blobPart struct {
+	index int64
+	// start is the offset of the part in the decrypted content
+	start int64
+	// sealedStart is the offset of the first segment of the part in the stored blob
+	sealedStart int64
+	size        int64
+}
+
+// readBlob reads the header and finds the parts of the blob.
+// It returns nil if the blob has no header and plaintext blobs are allowed.
+func (e *EncryptedBlobRepository) readBlob(src io.ReadSeeker) (*encryptedBlob, error) {
+	header, dataKey, encrypted, err := e.readHeader(src)
+	if err != nil {
+		return nil, fmt.Errorf("cant read header: %w", err)
+	}
+	if !encrypted {
+		if !e.allowPlaintext {
+			return nil, errNotEncrypted
+		}
+
+		return nil, nil
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cant create cipher: %w", err)
+	}
+
+	end, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("cant seek: %w", err)
+	}
+
+	eb := &encryptedBlob{header: header, version: header[len(headerMagic)], dataKey: dataKey, aead: aead}
+
+	if eb.version == legacyVersion {
+		_, size, err := countSegments(end - int64(headerSize))
+		if err != nil {
+			return nil, err
+		}
+
+		eb.parts = []blobPart{{sealedStart: int64(headerSize), size: size}}
+	} else {
+		eb.parts, err = readParts(src, aead, end)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range eb.parts {
+		eb.parts[i].start = eb.size
+		eb.size += eb.parts[i].size
+	}
+
+	return eb, nil
+}
+
+// readParts finds the parts of the blob by their trailers, from the last one to the first one.
+func readParts(src io.ReadSeeker, aead cipher.AEAD, end int64) ([]blobPart, error) {
+	var parts []blobPart
+	trailer := make([]byte, trailerSize)
+
+	for pos := end; pos > int64(headerSize); {
+		if pos-int64(headerSize) < trailerSize {
+			return nil, fmt.Errorf("blob is truncated")
+		}
+
+		_, err := src.Seek(pos-trailerSize, io.SeekStart)
+		if err != nil {
+			return nil, fmt.Errorf("cant seek: %w", err)
+		}
+
+		_, err = io.ReadFull(src, trailer)
+		if err != nil {
+			return nil, fmt.Errorf("cant read trailer: %w", err)
+		}
+
+		plain, err := aead.Open(nil, trailer[:nonceSize], trailer[nonceSize:], trailerAD)
+		if err != nil {
+			return nil, fmt.Errorf("cant decrypt trailer, blob is probably truncated: %w", err)
+		}
+
+		part := blobPart{
+			index: int64(binary.BigEndian.Uint64(plain[:8])),
+			size:  int64(binary.BigEndian.Uint64(plain[8:])),
+		}
+		if len(parts) > 0 && part.index != parts[len(parts)-1].index-1 {
+			return nil, fmt.Errorf("part %d is followed by part %d", part.index, parts[len(parts)-1].index)
+		}
+
+		part.sealedStart = pos - trailerSize - sealedPartSize(part.size)
+		if part.sealedStart < int64(headerSize) {
+			return nil, fmt.Errorf("blob is truncated")
+		}
+
+		parts = append(parts, part)
+		pos = part.sealedStart
+	}
+
+	if len(parts) == 0 || parts[len(parts)-1].index != 0 {
+		return nil, fmt.Errorf("blob is truncated")
+	}
+
+	slices.Reverse(parts)
+
+	return parts, nil
+}
+
+// sealedPartSize returns the size of the stored segments of a part of the size.
+func sealedPartSize(size int64) int64 {
+	return size + (size+segmentSize-1)/segmentSize*segmentOverhead
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// getPayloadSize returns the size of the blob after the header.
+func getPayloadSize(src io.Seeker) (int64, error) {
+	end, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("cant seek: %w", err)
+	}
+
+	return end - int64(headerSize), nil
+}
+
+// countSegments returns the number of the segments and the size of the decrypted content.
+// Every segment but the last one is full and the last one is never empty, unless it's the only one.
+func countSegments(payloadSize int64) (int64, int64, error) {
+	if payloadSize < segmentOverhead {
+		return 0, 0, fmt.Errorf("blob is truncated")
+	}
+
+	segments := (payloadSize + sealedSegmentLen - 1) / sealedSegmentLen
+	if payloadSize-(segments-1)*sealedSegmentLen < segmentOverhead {
+		return 0, 0, fmt.Errorf("blob is truncated")
+	}
+
+	return segments, payloadSize - segments*segmentOverhead, nil
+}
+
+// trailerAD is authenticated along with the trailers, so they can't be mistaken for the segments.
+var trailerAD = []byte("trailer")
+
+// partSegmentAD is authenticated along with a segment of a part.
+func partSegmentAD(part int64, index int64) []byte {
+	ad := binary.BigEndian.AppendUint64(nil, uint64(part))
+
+	return binary.BigEndian.AppendUint64(ad, uint64(index))
+}
+
+// segmentAD is authenticated along with a segment of a legacy blob.
+func segmentAD(index int64, last bool) []byte {
+	ad := binary.BigEndian.AppendUint64(nil, uint64(index))
+	if last {
+		return append(ad, 1)
+	}
+
+	return append(ad, 0)
+}
+
+func openSegment(aead cipher.AEAD, sealed []byte, index int64, last bool) ([]byte, error) {
+	plain, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], segmentAD(index, last))
+	if err != nil {
+		return nil, fmt.Errorf("cant decrypt segment %d: %w", index, err)
+	}
+
+	return plain, nil
+}
+
+// abort discards the written data if the writer supports it, otherwise just closes it.
+func abort(w io.WriteCloser) {
+	if aborter, ok := w.(Aborter); ok {
+		_ = aborter.Abort()
+
+		return
+	}
+
+	_ = w.Close()
+}
+
+// encryptingWriter seals the written data segment by segment as a part of the blob. A segment is sealed only when
+// the next one is started, so a part never ends with an empty segment, and the trailer is written on Close.
+type encryptingWriter struct {
+	dst  io.WriteCloser
+	aead cipher.AEAD
+	buf  []byte
+	part uint64
+	// index of the next segment in the part
+	index uint64
+	// size of the part written so far, including the buffer
+	size uint64
+	done bool
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	if w.done {
+		return 0, os.ErrClosed
+	}
+
+	written := 0
+	for written < len(p) {
+		if len(w.buf) == segmentSize {
+			err := w.seal()
+			if err != nil {
+				return written, err
+			}
+		}
+
+		n := min(segmentSize-len(w.buf), len(p)-written)
+		w.buf = append(w.buf, p[written:written+n]...)
+		written += n
+		w.size += uint64(n)
+	}
+
+	return written, nil
+}
+
+func (w *encryptingWriter) seal() error {
+	sealed, err := sealRecord(w.aead, w.buf, partSegmentAD(int64(w.part), int64(w.index)))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.dst.Write(sealed)
+	if err != nil {
+		return fmt.Errorf("cant write segment: %w", err)
+	}
+
+	w.index++
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+// Close seals the last segment, writes the trailer of the part and closes the inner writer.
+func (w *encryptingWriter) Close() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	err := w.finish()
+	if err != nil {
+		abort(w.dst)
+
+		return err
+	}
+
+	return w.dst.Close()
+}
+
+func (w *encryptingWriter) finish() error {
+	if len(w.buf) > 0 {
+		err := w.seal()
+		if err != nil {
+			return err
+		}
+	}
+
+	plain := binary.BigEndian.AppendUint64(nil, w.part)
+	plain = binary.BigEndian.AppendUint64(plain, w.size)
+
+	trailer, err := sealRecord(w.aead, plain, trailerAD)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.dst.Write(trailer)
+	if err != nil {
+		return fmt.Errorf("cant write trailer: %w", err)
+	}
+
+	return nil
+}
+
+// Abort aborts the inner writer if it supports it. Otherwise it's closed without the trailer,
+// so the blob fails to be read instead of being silently truncated.
+func (w *encryptingWriter) Abort() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	if aborter, ok := w.dst.(Aborter); ok {
+		return aborter.Abort()
+	}
+
+	return w.dst.Close()
+}
+
+// sealRecord seals the data with a random nonce, which is prepended to it.
+func sealRecord(aead cipher.AEAD, data []byte, ad []byte) ([]byte, error) {
+	sealed := make([]byte, nonceSize, nonceSize+len(data)+tagSize)
+	_, err := rand.Read(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("cant generate nonce: %w", err)
+	}
+
+	return aead.Seal(sealed, sealed, data, ad), nil
+}
+
+// decryptingReader reads the blob segment by segment, decrypting them.
+type decryptingReader struct {
+	src  io.ReadSeekCloser
+	blob *encryptedBlob
+	pos  int64
+	// seg is the decrypted segment with segIndex of the part with segPart, segPart is -1 if none is read yet
+	seg      []byte
+	segPart  int64
+	segIndex int64
+	// srcPos is the position of the inner reader, so it's seeked only when needed, -1 if unknown
+	srcPos int64
+}
+
+func newDecryptingReader(src io.ReadSeeker, eb *encryptedBlob) *decryptingReader {
+	rsc, ok := src.(io.ReadSeekCloser)
+	if !ok {
+		rsc = nopCloser{src}
+	}
+
+	return &decryptingReader{src: rsc, blob: eb, segPart: -1, srcPos: -1}
+}
+
+// nopCloser is a reader whose closing is up to its owner.
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	if r.pos >= r.blob.size {
+		return 0, io.EOF
+	}
+
+	// the part holding the position, empty parts are skipped
+	i, _ := slices.BinarySearchFunc(r.blob.parts, r.pos, func(part blobPart, pos int64) int {
+		return cmp.Compare(part.start+part.size, pos+1)
+	})
+	part := r.blob.parts[i]
+
+	index := (r.pos - part.start) / segmentSize
+	if part.index != r.segPart || index != r.segIndex {
+		err := r.readSegment(part, index)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.seg[r.pos-part.start-index*segmentSize:])
+	r.pos += int64(n)
+
+	return n, nil
+}
+
+func (r *decryptingReader) readSegment(part blobPart, index int64) error {
+	offset := part.sealedStart + index*sealedSegmentLen
+	if r.srcPos != offset {
+		_, err := r.src.Seek(offset, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("cant seek: %w", err)
+		}
+	}
+
+	sealed := make([]byte, min(segmentSize, part.size-index*segmentSize)+segmentOverhead)
+	n, err := io.ReadFull(r.src, sealed)
+	r.srcPos = offset + int64(n)
+	if err != nil {
+		return fmt.Errorf("cant read segment: %w", err)
+	}
+
+	var ad []byte
+	if r.blob.version == legacyVersion {
+		ad = segmentAD(index, index == max(part.size+segmentSize-1, segmentSize)/segmentSize-1)
+	} else {
+		ad = partSegmentAD(part.index, index)
+	}
+
+	r.seg, err = r.blob.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], ad)
+	if err != nil {
+		return fmt.Errorf("cant decrypt segment %d of part %d: %w", index, part.index, err)
+	}
+	r.segPart = part.index
+	r.segIndex = index
+
+	return nil
+}
+
+func (r *decryptingReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.blob.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if pos < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+
+	r.pos = pos
+
+	return pos, nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.src.Close()
+}
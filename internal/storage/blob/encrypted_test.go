@@ -0,0 +1,390 @@
+package blob_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+// larger than a segment, so the content spans several of them
+var largeContent = strings.Repeat("0123456789", 20*1024)
+
+func TestEncrypted_New(t *testing.T) {
+	inner, _ := newFileRepo(t)
+
+	t.Run("success", func(t *testing.T) {
+		repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(1), masterKey(2))
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		repo, err := blob.NewEncryptedBlobRepository(inner, []byte("short"))
+		require.Error(t, err)
+		require.Nil(t, repo)
+	})
+
+	t.Run("invalid old key", func(t *testing.T) {
+		repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(1), []byte("short"))
+		require.Error(t, err)
+		require.Nil(t, repo)
+	})
+}
+
+func TestEncrypted_Writer(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	inner, path := newFileRepo(t)
+	repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(1))
+	require.NoError(t, err)
+
+	for name, content := range map[string]string{"empty": "", "small": "content", "large": largeContent} {
+		t.Run(name, func(t *testing.T) {
+			writeBlob(t, repo, name, content)
+			requireBlob(t, repo, name, content)
+
+			size, exists, err := repo.GetBlobSize(ctx, name)
+			require.NoError(t, err)
+			require.True(t, exists)
+			require.Equal(t, int64(len(content)), size)
+
+			stored, err := os.ReadFile(filepath.Join(path, name))
+			require.NoError(t, err)
+			if content != "" {
+				require.NotContains(t, string(stored), content)
+			}
+		})
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		writeBlob(t, repo, "aborted", "old")
+
+		wc, err := repo.OpenBlobWriter(ctx, "aborted")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("new"))
+		require.NoError(t, err)
+
+		aborter, ok := wc.(blob.Aborter)
+		require.True(t, ok)
+		require.NoError(t, aborter.Abort())
+
+		requireBlob(t, repo, "aborted", "old")
+		require.ErrorIs(t, wc.Close(), os.ErrClosed)
+	})
+}
+
+func TestEncrypted_Seeker(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	inner, path := newFileRepo(t)
+	repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(1))
+	require.NoError(t, err)
+
+	writeBlob(t, repo, "test", largeContent)
+
+	t.Run("success", func(t *testing.T) {
+		for _, offset := range []int64{0, 5, 64*1024 - 1, 64 * 1024, 150000, int64(len(largeContent))} {
+			rsc, exists, err := repo.OpenBlobSeeker(ctx, "test")
+			require.NoError(t, err)
+			require.True(t, exists)
+
+			_, err = rsc.Seek(offset, io.SeekStart)
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(rsc)
+			require.NoError(t, err)
+			require.Equal(t, largeContent[offset:], string(content), "offset %d", offset)
+
+			require.NoError(t, rsc.Close())
+		}
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "not-exists")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		writeBlob(t, repo, "tampered", largeContent)
+
+		stored, err := os.ReadFile(filepath.Join(path, "tampered"))
+		require.NoError(t, err)
+		stored[len(stored)/2] ^= 1
+		require.NoError(t, os.WriteFile(filepath.Join(path, "tampered"), stored, 0600))
+
+		rc, exists, err := repo.OpenBlobReader(ctx, "tampered")
+		require.NoError(t, err)
+		require.True(t, exists)
+		defer rc.Close()
+
+		_, err = io.ReadAll(rc)
+		require.Error(t, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		writeBlob(t, repo, "truncated", largeContent)
+
+		// cut exactly at a segment boundary, so the trailer of the part is missing
+		stored, err := os.ReadFile(filepath.Join(path, "truncated"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(path, "truncated"), stored[:73+64*1024+28], 0600))
+
+		rc, exists, err := repo.OpenBlobReader(ctx, "truncated")
+		require.Error(t, err)
+		require.False(t, exists)
+		require.Nil(t, rc)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		for name, content := range map[string]string{"empty": "", "full segment": largeContent[:64*1024], "large": largeContent} {
+			require.NoError(t, repo.WriteLegacyBlob(ctx, "legacy", []byte(content)), name)
+			requireBlob(t, repo, "legacy", content)
+		}
+
+		// the last segment is marked
+		stored, err := os.ReadFile(filepath.Join(path, "legacy"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(path, "legacy"), stored[:73+64*1024+28], 0600))
+
+		rc, exists, err := repo.OpenBlobReader(ctx, "legacy")
+		require.NoError(t, err)
+		require.True(t, exists)
+		defer rc.Close()
+
+		_, err = io.ReadAll(rc)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown master key", func(t *testing.T) {
+		other, err := blob.NewEncryptedBlobRepository(inner, masterKey(2))
+		require.NoError(t, err)
+
+		rsc, exists, err := other.OpenBlobSeeker(ctx, "test")
+		require.Error(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+	})
+}
+
+func TestEncrypted_Appender(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	inner, path := newFileRepo(t)
+	repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(1))
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		// the parts end in the middle of a segment and exactly at its end
+		parts := []string{largeContent[:100000], largeContent[100000 : 128*1024], largeContent[128*1024:], ""}
+
+		stored := []byte{}
+		for _, part := range parts {
+			wc, err := repo.OpenBlobAppender(ctx, "test")
+			require.NoError(t, err)
+			_, err = wc.Write([]byte(part))
+			require.NoError(t, err)
+			require.NoError(t, wc.Close())
+
+			// the stored data is only appended to
+			appended, err := os.ReadFile(filepath.Join(path, "test"))
+			require.NoError(t, err)
+			require.Equal(t, stored, appended[:len(stored)])
+			stored = appended
+		}
+
+		requireBlob(t, repo, "test", largeContent)
+
+		size, _, err := repo.GetBlobSize(ctx, "test")
+		require.NoError(t, err)
+		require.Equal(t, int64(len(largeContent)), size)
+
+		rsc, _, err := repo.OpenBlobSeeker(ctx, "test")
+		require.NoError(t, err)
+		defer rsc.Close()
+
+		// across the parts
+		_, err = rsc.Seek(99990, io.SeekStart)
+		require.NoError(t, err)
+		content := make([]byte, 20)
+		_, err = io.ReadFull(rsc, content)
+		require.NoError(t, err)
+		require.Equal(t, largeContent[99990:100010], string(content))
+	})
+
+	t.Run("parts out of order", func(t *testing.T) {
+		writeBlob(t, repo, "swapped", "first")
+		for _, part := range []string{"aaa", "bbb"} {
+			wc, err := repo.OpenBlobAppender(ctx, "swapped")
+			require.NoError(t, err)
+			_, err = wc.Write([]byte(part))
+			require.NoError(t, err)
+			require.NoError(t, wc.Close())
+		}
+
+		// the appended parts are the same size, so they can be swapped
+		stored, err := os.ReadFile(filepath.Join(path, "swapped"))
+		require.NoError(t, err)
+		second := 73 + 28 + 5 + 44
+		third := second + 28 + 3 + 44
+		swapped := append(append(append([]byte{}, stored[:second]...), stored[third:]...), stored[second:third]...)
+		require.NoError(t, os.WriteFile(filepath.Join(path, "swapped"), swapped, 0600))
+
+		_, _, err = repo.OpenBlobReader(ctx, "swapped")
+		require.Error(t, err)
+	})
+
+	t.Run("legacy blob", func(t *testing.T) {
+		require.NoError(t, repo.WriteLegacyBlob(ctx, "legacy", []byte(largeContent[:100000])))
+
+		wc, err := repo.OpenBlobAppender(ctx, "legacy")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte(largeContent[100000:]))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		requireBlob(t, repo, "legacy", largeContent)
+	})
+
+	t.Run("plain blob", func(t *testing.T) {
+		writeBlob(t, inner, "plain", "first ")
+
+		wc, err := repo.OpenBlobAppender(ctx, "plain")
+		require.Error(t, err)
+		require.Nil(t, wc)
+
+		requireBlob(t, inner, "plain", "first ")
+	})
+
+	t.Run("plain blob allowed", func(t *testing.T) {
+		migrating, err := blob.NewEncryptedBlobRepository(inner, masterKey(1))
+		require.NoError(t, err)
+		migrating.AllowPlaintext()
+
+		writeBlob(t, inner, "plain", "first ")
+
+		wc, err := migrating.OpenBlobAppender(ctx, "plain")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("second"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		requireBlob(t, inner, "plain", "first second")
+	})
+}
+
+func TestEncrypted_PlainBlobs(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	inner, _ := newFileRepo(t)
+	repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(1))
+	require.NoError(t, err)
+
+	// stored before the encryption was enabled
+	writeBlob(t, inner, "plain", "content")
+	writeBlob(t, inner, "short", "GK")
+
+	// or with a stripped header, so it isn't served until the migration is explicitly allowed
+	for _, key := range []string{"plain", "short"} {
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, key)
+		require.Error(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+
+		_, _, err = repo.GetBlobSize(ctx, key)
+		require.Error(t, err)
+	}
+
+	repo.AllowPlaintext()
+
+	requireBlob(t, repo, "plain", "content")
+	requireBlob(t, repo, "short", "GK")
+
+	size, exists, err := repo.GetBlobSize(ctx, "plain")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, int64(7), size)
+}
+
+func TestEncrypted_Rewrap(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	inner, path := newFileRepo(t)
+
+	old, err := blob.NewEncryptedBlobRepository(inner, masterKey(1))
+	require.NoError(t, err)
+	writeBlob(t, old, "old", largeContent)
+	before, err := os.ReadFile(filepath.Join(path, "old"))
+	require.NoError(t, err)
+	require.NoError(t, old.WriteLegacyBlob(ctx, "legacy", []byte(largeContent)))
+
+	writeBlob(t, inner, "plain", "content")
+
+	repo, err := blob.NewEncryptedBlobRepository(inner, masterKey(2), masterKey(1))
+	require.NoError(t, err)
+	writeBlob(t, repo, "current", "current")
+
+	// readable before the rewrap with the old key
+	requireBlob(t, repo, "old", largeContent)
+
+	// the plain blob isn't encrypted unless it's explicitly allowed
+	rewrapped, err := repo.RewrapBlobs(ctx)
+	require.Error(t, err)
+	require.Equal(t, 2, rewrapped)
+
+	repo.AllowPlaintext()
+	rewrapped, err = repo.RewrapBlobs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, rewrapped)
+
+	// only the header is changed
+	after, err := os.ReadFile(filepath.Join(path, "old"))
+	require.NoError(t, err)
+	require.NotEqual(t, before[:73], after[:73])
+	require.Equal(t, before[73:], after[73:])
+
+	stored, err := os.ReadFile(filepath.Join(path, "plain"))
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(stored, []byte("content")))
+
+	// readable without the old key
+	current, err := blob.NewEncryptedBlobRepository(inner, masterKey(2))
+	require.NoError(t, err)
+	requireBlob(t, current, "old", largeContent)
+	requireBlob(t, current, "legacy", largeContent)
+	requireBlob(t, current, "plain", "content")
+	requireBlob(t, current, "current", "current")
+
+	rewrapped, err = current.RewrapBlobs(ctx)
+	require.NoError(t, err)
+	require.Zero(t, rewrapped)
+}
+
+func newFileRepo(t *testing.T) (*blob.FileBlobRepository, string) {
+	t.Helper()
+
+	path := t.TempDir()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	return repo, path
+}
+
+func masterKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
@@ -0,0 +1,64 @@
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+)
+
+// SetDBTouchInterval changes how often the database writers touch their staging blobs until the returned func is called.
+func SetDBTouchInterval(interval time.Duration) func() {
+	old := dbTouchInterval
+	dbTouchInterval = interval
+
+	return func() {
+		dbTouchInterval = old
+	}
+}
+
+// WriteLegacyBlob writes the content as a blob of the legacy version, as it was written before the blobs were appended in parts.
+func (e *EncryptedBlobRepository) WriteLegacyBlob(ctx context.Context, key string, content []byte) error {
+	dataKey := make([]byte, dataKeySize)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return err
+	}
+
+	header, err := e.wrap(dataKey, legacyVersion)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	stored := header
+	for index := int64(0); ; index++ {
+		segment := content[min(index*segmentSize, int64(len(content))):min((index+1)*segmentSize, int64(len(content)))]
+		last := (index+1)*segmentSize >= int64(len(content))
+
+		sealed, err := sealRecord(aead, segment, segmentAD(index, last))
+		if err != nil {
+			return err
+		}
+		stored = append(stored, sealed...)
+
+		if last {
+			break
+		}
+	}
+
+	w, err := e.inner.OpenBlobWriter(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(stored)
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
@@ -1,11 +1,14 @@
 package blob
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -35,23 +38,53 @@ type FileBlobRepository struct {
 const dirPerms = os.FileMode(0700)
 const filePerms = os.FileMode(0600)
 
+// tempSuffix marks temporary files of writers in progress, the random part is appended to it.
+const tempSuffix = ".tmp-*"
+
 // OpenBlobWriter opens a writer for the blob identified by the given key.
-// If the blob does not exist, it will be created. If it exists, it will be truncated.
+// The data is written to a temporary file in the same directory, which replaces the blob only on successful Close,
+// so a crash or a failed write never leaves a torn blob. The returned writer implements Aborter.
 // Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
-func (f *FileBlobRepository) OpenBlobWriter(key string) (io.WriteCloser, error) {
+func (f *FileBlobRepository) OpenBlobWriter(ctx context.Context, key string) (io.WriteCloser, error) {
 	fullPath, err := f.getFullPath(key)
 	if err != nil {
 		return nil, fmt.Errorf("cant get full path: %w", err)
 	}
 
-	err = os.MkdirAll(filepath.Dir(fullPath), dirPerms)
+	dir := filepath.Dir(fullPath)
+	err = os.MkdirAll(dir, dirPerms)
 	if err != nil {
 		return nil, fmt.Errorf("cant create directory: %w", err)
 	}
 
 	f.log.Debugw("opening for write", "path", fullPath)
 
-	file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePerms)
+	// created with filePerms
+	file, err := os.CreateTemp(dir, "."+filepath.Base(fullPath)+tempSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("cant create temporary file: %w", err)
+	}
+
+	return &atomicFile{File: file, path: fullPath, log: f.log}, nil
+}
+
+// OpenBlobAppender opens a writer appending to the blob identified by the given key.
+// If the blob does not exist, it will be created.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (f *FileBlobRepository) OpenBlobAppender(ctx context.Context, key string) (io.WriteCloser, error) {
+	fullPath, err := f.getFullPath(key)
+	if err != nil {
+		return nil, fmt.Errorf("cant get full path: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(fullPath), dirPerms)
+	if err != nil {
+		return nil, fmt.Errorf("cant create directory: %w", err)
+	}
+
+	f.log.Debugw("opening for append", "path", fullPath)
+
+	file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerms)
 	if err != nil {
 		return nil, fmt.Errorf("cant open file: %w", err)
 	}
@@ -62,7 +95,14 @@ func (f *FileBlobRepository) OpenBlobWriter(key string) (io.WriteCloser, error)
 // OpenBlobReader opens a reader for the blob identified by the given key.
 // Returns an io.ReadCloser for reading the blob, a boolean indicating if the blob exists,
 // and an error if the operation fails.
-func (f *FileBlobRepository) OpenBlobReader(key string) (io.ReadCloser, bool, error) {
+func (f *FileBlobRepository) OpenBlobReader(ctx context.Context, key string) (io.ReadCloser, bool, error) {
+	return f.OpenBlobSeeker(ctx, key)
+}
+
+// OpenBlobSeeker opens a seekable reader for the blob identified by the given key.
+// Returns an io.ReadSeekCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (f *FileBlobRepository) OpenBlobSeeker(ctx context.Context, key string) (io.ReadSeekCloser, bool, error) {
 	fullPath, err := f.getFullPath(key)
 	if err != nil {
 		return nil, false, fmt.Errorf("cant get full path: %w", err)
@@ -81,9 +121,56 @@ func (f *FileBlobRepository) OpenBlobReader(key string) (io.ReadCloser, bool, er
 	return file, true, nil
 }
 
+// GetBlobSize returns the size of the blob identified by the given key in bytes,
+// a boolean indicating if the blob exists, and an error if the operation fails.
+func (f *FileBlobRepository) GetBlobSize(ctx context.Context, key string) (int64, bool, error) {
+	fullPath, err := f.getFullPath(key)
+	if err != nil {
+		return 0, false, fmt.Errorf("cant get full path: %w", err)
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("cant stat file: %w", err)
+	}
+
+	return info.Size(), true, nil
+}
+
+// MoveBlob moves the blob identified by srcKey to dstKey, replacing the destination blob if it exists.
+// Returns an error if the operation fails.
+func (f *FileBlobRepository) MoveBlob(ctx context.Context, srcKey string, dstKey string) error {
+	srcPath, err := f.getFullPath(srcKey)
+	if err != nil {
+		return fmt.Errorf("cant get full source path: %w", err)
+	}
+
+	dstPath, err := f.getFullPath(dstKey)
+	if err != nil {
+		return fmt.Errorf("cant get full destination path: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(dstPath), dirPerms)
+	if err != nil {
+		return fmt.Errorf("cant create directory: %w", err)
+	}
+
+	f.log.Debugw("moving", "from", srcPath, "to", dstPath)
+
+	err = os.Rename(srcPath, dstPath)
+	if err != nil {
+		return fmt.Errorf("cant rename file: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteBlob deletes the blob identified by the given key.
 // Returns an error if the operation fails.
-func (f *FileBlobRepository) DeleteBlob(key string) error {
+func (f *FileBlobRepository) DeleteBlob(ctx context.Context, key string) error {
 	fullPath, err := f.getFullPath(key)
 	if err != nil {
 		return fmt.Errorf("cant get full path: %w", err)
@@ -94,6 +181,95 @@ func (f *FileBlobRepository) DeleteBlob(key string) error {
 	return os.Remove(fullPath)
 }
 
+// WalkBlobs calls fn for every stored blob, in lexical order of the keys. Walking stops at the first error returned by fn.
+// Temporary files of the writers in progress, or left by crashed ones, are skipped, see CleanTemp.
+// Returns the error of fn or an error if the operation fails.
+func (f *FileBlobRepository) WalkBlobs(ctx context.Context, fn func(info Info) error) error {
+	return f.walk(func(key string, info fs.FileInfo) error {
+		if isTempFile(info.Name()) {
+			return nil
+		}
+
+		return fn(Info{
+			Key:        key,
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+	})
+}
+
+// CleanTemp deletes the temporary files of the writers last written before modifiedBefore.
+// Writers in progress keep their files modified, so only the ones left by crashed writers are old enough.
+// Returns the number of deleted files or an error if the operation fails.
+func (f *FileBlobRepository) CleanTemp(ctx context.Context, modifiedBefore time.Time) (int, error) {
+	deleted := 0
+	err := f.walk(func(key string, info fs.FileInfo) error {
+		if !isTempFile(info.Name()) || !info.ModTime().Before(modifiedBefore) {
+			return nil
+		}
+
+		f.log.Debugw("deleting stale temporary file", "key", key)
+
+		err := os.Remove(filepath.Join(f.path, filepath.FromSlash(key)))
+		if os.IsNotExist(err) {
+			// finished or cleaned up since the directory was read
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cant remove temporary file: %w", err)
+		}
+
+		deleted++
+
+		return nil
+	})
+
+	return deleted, err
+}
+
+// walk calls fn for every file under the root, in lexical order of the keys.
+func (f *FileBlobRepository) walk(fn func(key string, info fs.FileInfo) error) error {
+	_, err := os.Stat(f.path)
+	if os.IsNotExist(err) {
+		// nothing has been written yet
+		return nil
+	}
+
+	return filepath.WalkDir(f.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("cant walk %s: %w", path, err)
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if os.IsNotExist(err) {
+			// deleted since the directory was read
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cant get file info: %w", err)
+		}
+
+		rel, err := filepath.Rel(f.path, path)
+		if err != nil {
+			return fmt.Errorf("cant get relative path: %w", err)
+		}
+
+		return fn(filepath.ToSlash(rel), info)
+	})
+}
+
+// isTempFile reports whether the file name is the one of a temporary file created by OpenBlobWriter.
+func isTempFile(name string) bool {
+	// the pattern is valid, so there is never an error
+	matched, _ := filepath.Match(".*"+tempSuffix, name)
+
+	return matched
+}
+
 func (f *FileBlobRepository) getFullPath(key string) (string, error) {
 	full := filepath.Join(f.path, key)
 
@@ -106,5 +282,100 @@ func (f *FileBlobRepository) getFullPath(key string) (string, error) {
 		return "", fmt.Errorf("path traversal detected")
 	}
 
+	if rel == "." {
+		return "", fmt.Errorf("key is empty")
+	}
+
 	return full, nil
 }
+
+// atomicFile is a temporary file that replaces the file at path when closed.
+type atomicFile struct {
+	*os.File
+	path string
+	log  *zap.SugaredLogger
+	done bool
+}
+
+// Close flushes the temporary file to disk and renames it to the target path.
+// If anything fails, the temporary file is removed and the target is left intact.
+func (a *atomicFile) Close() error {
+	if a.done {
+		return os.ErrClosed
+	}
+	a.done = true
+
+	err := a.commit()
+	if err != nil {
+		removeErr := os.Remove(a.File.Name())
+		if removeErr != nil {
+			a.log.Errorw("cant remove temporary file", "path", a.File.Name(), "err", removeErr)
+		}
+
+		return err
+	}
+
+	// the blob is already replaced, so failing to make the rename durable isn't an error of the write
+	err = syncDir(filepath.Dir(a.path))
+	if err != nil {
+		a.log.Errorw("cant sync directory", "path", a.path, "err", err)
+	}
+
+	return nil
+}
+
+func (a *atomicFile) commit() error {
+	err := a.File.Sync()
+	if err != nil {
+		_ = a.File.Close()
+
+		return fmt.Errorf("cant sync file: %w", err)
+	}
+
+	err = a.File.Close()
+	if err != nil {
+		return fmt.Errorf("cant close file: %w", err)
+	}
+
+	a.log.Debugw("replacing", "path", a.path)
+
+	err = os.Rename(a.File.Name(), a.path)
+	if err != nil {
+		return fmt.Errorf("cant rename file: %w", err)
+	}
+
+	return nil
+}
+
+// syncDir flushes the directory entries to disk, making renames in it durable.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cant open directory: %w", err)
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+// Abort closes and removes the temporary file, the target is left intact.
+func (a *atomicFile) Abort() error {
+	if a.done {
+		return os.ErrClosed
+	}
+	a.done = true
+
+	a.log.Debugw("aborting write", "path", a.path)
+
+	err := a.File.Close()
+	if err != nil {
+		a.log.Errorw("cant close temporary file", "path", a.File.Name(), "err", err)
+	}
+
+	err = os.Remove(a.File.Name())
+	if err != nil {
+		return fmt.Errorf("cant remove temporary file: %w", err)
+	}
+
+	return nil
+}
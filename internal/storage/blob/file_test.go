@@ -1,12 +1,16 @@
 package blob_test
 
 import (
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
 )
 
 func TestFile_New(t *testing.T) {
@@ -30,6 +34,9 @@ func TestFile_New(t *testing.T) {
 }
 
 func TestFile_Writer(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
 	path, err := os.MkdirTemp("", "test-*")
 	require.NoError(t, err)
 	defer func() {
@@ -41,50 +48,102 @@ func TestFile_Writer(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		t.Run("simple key", func(t *testing.T) {
-			wc, err := repo.OpenBlobWriter("test-simple-key")
+			wc, err := repo.OpenBlobWriter(ctx, "test-simple-key")
 			require.NoError(t, err)
 			require.NotNil(t, wc)
 		})
 
 		t.Run("with subdirs", func(t *testing.T) {
-			wc, err := repo.OpenBlobWriter("test/test-with-subdirs")
+			wc, err := repo.OpenBlobWriter(ctx, "test/test-with-subdirs")
 			require.NoError(t, err)
 			require.NotNil(t, wc)
 		})
 
 		t.Run("file already exists", func(t *testing.T) {
-			wc, err := repo.OpenBlobWriter("test-already-exists")
+			wc, err := repo.OpenBlobWriter(ctx, "test-already-exists")
 			require.NoError(t, err)
 			require.NotNil(t, wc)
 
-			wc, err = repo.OpenBlobWriter("test-already-exists")
+			wc, err = repo.OpenBlobWriter(ctx, "test-already-exists")
 			require.NoError(t, err)
 			require.NotNil(t, wc)
 		})
 	})
 
+	t.Run("replaced on close", func(t *testing.T) {
+		writeBlob(t, repo, "atomic", "old")
+
+		wc, err := repo.OpenBlobWriter(ctx, "atomic")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("new"))
+		require.NoError(t, err)
+
+		// not replaced until closed
+		requireBlob(t, repo, "atomic", "old")
+
+		require.NoError(t, wc.Close())
+		requireBlob(t, repo, "atomic", "new")
+		requireNoTempFiles(t, path, "atomic")
+
+		require.Error(t, wc.Close())
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		writeBlob(t, repo, "aborted", "old")
+
+		wc, err := repo.OpenBlobWriter(ctx, "aborted")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("new"))
+		require.NoError(t, err)
+
+		aborter, ok := wc.(blob.Aborter)
+		require.True(t, ok)
+		require.NoError(t, aborter.Abort())
+
+		requireBlob(t, repo, "aborted", "old")
+		requireNoTempFiles(t, path, "aborted")
+
+		// can't be committed after abort
+		require.Error(t, wc.Close())
+		requireBlob(t, repo, "aborted", "old")
+	})
+
+	t.Run("abort new blob", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "aborted-new")
+		require.NoError(t, err)
+		require.NoError(t, wc.(blob.Aborter).Abort())
+
+		_, exists, err := repo.OpenBlobReader(ctx, "aborted-new")
+		require.NoError(t, err)
+		require.False(t, exists)
+		requireNoTempFiles(t, path, "aborted-new")
+	})
+
 	t.Run("path traversal", func(t *testing.T) {
-		wc, err := repo.OpenBlobWriter("../test")
+		wc, err := repo.OpenBlobWriter(ctx, "../test")
 		require.Error(t, err)
 		require.Nil(t, wc)
 
-		wc, err = repo.OpenBlobWriter("test/../../test")
+		wc, err = repo.OpenBlobWriter(ctx, "test/../../test")
 		require.Error(t, err)
 		require.Nil(t, wc)
 
-		wc, err = repo.OpenBlobWriter("test/../../../../test")
+		wc, err = repo.OpenBlobWriter(ctx, "test/../../../../test")
 		require.Error(t, err)
 		require.Nil(t, wc)
 	})
 
 	t.Run("invalid path", func(t *testing.T) {
-		wc, err := repo.OpenBlobWriter("")
+		wc, err := repo.OpenBlobWriter(ctx, "")
 		require.Error(t, err)
 		require.Nil(t, wc)
 	})
 }
 
 func TestFile_Reader(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
 	path, err := os.MkdirTemp("", "test-*")
 	require.NoError(t, err)
 	defer func() {
@@ -95,41 +154,93 @@ func TestFile_Reader(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		_, err := repo.OpenBlobWriter("test")
+		wc, err := repo.OpenBlobWriter(ctx, "test")
 		require.NoError(t, err)
+		require.NoError(t, wc.Close())
 
-		rc, exists, err := repo.OpenBlobReader("test")
+		rc, exists, err := repo.OpenBlobReader(ctx, "test")
 		require.NoError(t, err)
 		require.True(t, exists)
 		require.NotNil(t, rc)
 	})
 
 	t.Run("not exists", func(t *testing.T) {
-		rc, exists, err := repo.OpenBlobReader("not-exists")
+		rc, exists, err := repo.OpenBlobReader(ctx, "not-exists")
 		require.NoError(t, err)
 		require.False(t, exists)
 		require.Nil(t, rc)
 	})
 
 	t.Run("path traversal", func(t *testing.T) {
-		rc, exists, err := repo.OpenBlobReader("../test")
+		rc, exists, err := repo.OpenBlobReader(ctx, "../test")
 		require.Error(t, err)
 		require.False(t, exists)
 		require.Nil(t, rc)
 
-		rc, exists, err = repo.OpenBlobReader("test/../../test")
+		rc, exists, err = repo.OpenBlobReader(ctx, "test/../../test")
 		require.Error(t, err)
 		require.False(t, exists)
 		require.Nil(t, rc)
 
-		rc, exists, err = repo.OpenBlobReader("test/../../../../test")
+		rc, exists, err = repo.OpenBlobReader(ctx, "test/../../../../test")
 		require.Error(t, err)
 		require.False(t, exists)
 		require.Nil(t, rc)
 	})
 }
 
+func TestFile_Seeker(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	path, err := os.MkdirTemp("", "test-*")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(path))
+	}()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "test")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("hello world"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "test")
+		require.NoError(t, err)
+		require.True(t, exists)
+		defer rsc.Close()
+
+		_, err = rsc.Seek(6, io.SeekStart)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(rsc)
+		require.NoError(t, err)
+		require.Equal(t, "world", string(content))
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "not-exists")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "../test")
+		require.Error(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+	})
+}
+
 func TestFile_Delete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
 	path, err := os.MkdirTemp("", "test-*")
 	require.NoError(t, err)
 	defer func() {
@@ -140,30 +251,314 @@ func TestFile_Delete(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		_, err := repo.OpenBlobWriter("test")
+		wc, err := repo.OpenBlobWriter(ctx, "test")
 		require.NoError(t, err)
+		require.NoError(t, wc.Close())
 
-		rc, exists, err := repo.OpenBlobReader("test")
+		rc, exists, err := repo.OpenBlobReader(ctx, "test")
 		require.NoError(t, err)
 		require.True(t, exists)
 		require.NotNil(t, rc)
 
-		err = repo.DeleteBlob("test")
+		err = repo.DeleteBlob(ctx, "test")
 		require.NoError(t, err)
 
-		_, exists, err = repo.OpenBlobReader("test")
+		_, exists, err = repo.OpenBlobReader(ctx, "test")
 		require.NoError(t, err)
 		require.False(t, exists)
 	})
 
 	t.Run("path traversal", func(t *testing.T) {
-		err := repo.DeleteBlob("../test")
+		err := repo.DeleteBlob(ctx, "../test")
 		require.Error(t, err)
 
-		err = repo.DeleteBlob("test/../../test")
+		err = repo.DeleteBlob(ctx, "test/../../test")
 		require.Error(t, err)
 
-		err = repo.DeleteBlob("test/../../../../test")
+		err = repo.DeleteBlob(ctx, "test/../../../../test")
 		require.Error(t, err)
 	})
 }
+
+func TestFile_Appender(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	path, err := os.MkdirTemp("", "test-*")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(path))
+	}()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		wc, err := repo.OpenBlobAppender(ctx, "test/append")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("hello "))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		wc, err = repo.OpenBlobAppender(ctx, "test/append")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("world"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		rc, exists, err := repo.OpenBlobReader(ctx, "test/append")
+		require.NoError(t, err)
+		require.True(t, exists)
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(content))
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		wc, err := repo.OpenBlobAppender(ctx, "../test")
+		require.Error(t, err)
+		require.Nil(t, wc)
+	})
+}
+
+func TestFile_Size(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	path, err := os.MkdirTemp("", "test-*")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(path))
+	}()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "test")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		size, exists, err := repo.GetBlobSize(ctx, "test")
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, int64(5), size)
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		size, exists, err := repo.GetBlobSize(ctx, "not-exists")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Zero(t, size)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		_, _, err := repo.GetBlobSize(ctx, "../test")
+		require.Error(t, err)
+	})
+}
+
+func TestFile_Move(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	path, err := os.MkdirTemp("", "test-*")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(path))
+	}()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "src")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("new"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		wc, err = repo.OpenBlobWriter(ctx, "dst/blob")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("old content"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		err = repo.MoveBlob(ctx, "src", "dst/blob")
+		require.NoError(t, err)
+
+		_, exists, err := repo.GetBlobSize(ctx, "src")
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		rc, exists, err := repo.OpenBlobReader(ctx, "dst/blob")
+		require.NoError(t, err)
+		require.True(t, exists)
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.Equal(t, "new", string(content))
+	})
+
+	t.Run("source not exists", func(t *testing.T) {
+		err := repo.MoveBlob(ctx, "not-exists", "dst")
+		require.Error(t, err)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		err := repo.MoveBlob(ctx, "../test", "dst")
+		require.Error(t, err)
+
+		err = repo.MoveBlob(ctx, "src", "../dst")
+		require.Error(t, err)
+	})
+}
+
+func TestFile_Walk(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	path, err := os.MkdirTemp("", "test-*")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(path))
+	}()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		writeBlob(t, repo, "user/key", "content")
+		writeBlob(t, repo, "user/versions/key/1", "old")
+
+		// a write in progress
+		wc, err := repo.OpenBlobWriter(ctx, "user/new")
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, wc.(blob.Aborter).Abort())
+		}()
+
+		infos := make([]blob.Info, 0)
+		err = repo.WalkBlobs(ctx, func(info blob.Info) error {
+			infos = append(infos, info)
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, infos, 2)
+		require.Equal(t, "user/key", infos[0].Key)
+		require.Equal(t, int64(7), infos[0].Size)
+		require.False(t, infos[0].ModifiedAt.IsZero())
+		require.Equal(t, "user/versions/key/1", infos[1].Key)
+		require.Equal(t, int64(3), infos[1].Size)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		calls := 0
+		err := repo.WalkBlobs(ctx, func(info blob.Info) error {
+			calls++
+
+			return io.ErrUnexpectedEOF
+		})
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("root not exists", func(t *testing.T) {
+		repo, err := blob.NewFileBlobRepository(filepath.Join(path, "not-exists"))
+		require.NoError(t, err)
+
+		err = repo.WalkBlobs(ctx, func(info blob.Info) error {
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestFile_CleanTemp(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	path, err := os.MkdirTemp("", "test-*")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(path))
+	}()
+
+	repo, err := blob.NewFileBlobRepository(path)
+	require.NoError(t, err)
+
+	writeBlob(t, repo, "user/key", "content")
+
+	// left by a crashed writer
+	stale := filepath.Join(path, "user", ".key.tmp-123")
+	require.NoError(t, os.WriteFile(stale, []byte("stale"), 0600))
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(stale, old, old))
+
+	// a write in progress
+	wc, err := repo.OpenBlobWriter(ctx, "user/key")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, wc.(blob.Aborter).Abort())
+	}()
+
+	cleaned, err := repo.CleanTemp(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, cleaned)
+
+	_, err = os.Stat(stale)
+	require.True(t, os.IsNotExist(err))
+	requireBlob(t, repo, "user/key", "content")
+
+	t.Run("root not exists", func(t *testing.T) {
+		repo, err := blob.NewFileBlobRepository(filepath.Join(path, "not-exists"))
+		require.NoError(t, err)
+
+		cleaned, err := repo.CleanTemp(ctx, time.Now())
+		require.NoError(t, err)
+		require.Zero(t, cleaned)
+	})
+}
+
+func writeBlob(t *testing.T, repo blob.Repository, key string, content string) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Helper()
+
+	wc, err := repo.OpenBlobWriter(ctx, key)
+	require.NoError(t, err)
+	_, err = wc.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+}
+
+func requireBlob(t *testing.T, repo blob.Repository, key string, content string) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	t.Helper()
+
+	rc, exists, err := repo.OpenBlobReader(ctx, key)
+	require.NoError(t, err)
+	require.True(t, exists)
+	defer rc.Close()
+
+	actual, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, content, string(actual))
+}
+
+func requireNoTempFiles(t *testing.T, path string, key string) {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(path, "."+key+".tmp-*"))
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
@@ -0,0 +1,539 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+
+	"github.com/kuvalkin/gophkeeper/internal/support/log"
+)
+
+// S3Options holds the settings of an S3-compatible storage.
+type S3Options struct {
+	Endpoint  string // Endpoint is the host and optionally the port of the storage, without the scheme.
+	Region    string // Region of the bucket, can be empty if the storage doesn't need it.
+	Bucket    string // Bucket where blobs will be stored, it must already exist.
+	AccessKey string // AccessKey of the storage credentials.
+	SecretKey string // SecretKey of the storage credentials.
+	UseSSL    bool   // UseSSL enables HTTPS.
+}
+
+// s3PartSize is the size of the parts of multipart uploads. Every writer buffers a part in memory,
+// and it limits blobs to 10000 parts, i.e. about 160GB.
+const s3PartSize = 16 * 1024 * 1024
+
+// s3MaxCopySize is the maximum size of an object that can be copied with a single request.
+const s3MaxCopySize = 5 * 1024 * 1024 * 1024
+
+// s3MinComposeSize is the minimum size of every part of a multipart upload but the last one,
+// so smaller objects can't be the beginning of a composed object.
+const s3MinComposeSize = 5 * 1024 * 1024
+
+// s3TempPrefix is the prefix of the objects with the data appended to the blobs until it's composed with them.
+// They aren't blobs, so they aren't walked.
+const s3TempPrefix = ".tmp/"
+
+// NewS3BlobRepository creates a new instance of S3BlobRepository.
+// It checks that the bucket exists, so misconfiguration is detected on start.
+func NewS3BlobRepository(ctx context.Context, opts S3Options) (*S3BlobRepository, error) {
+	if opts.Bucket == "" {
+		return nil, fmt.Errorf("bucket must be set")
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cant create client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("cant check bucket: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("bucket %s doesn't exist", opts.Bucket)
+	}
+
+	return &S3BlobRepository{
+		client: client,
+		bucket: opts.Bucket,
+		log:    log.Logger().Named("blobs"),
+	}, nil
+}
+
+// S3BlobRepository is an implementation of the blob.Repository interface backed by an S3-compatible storage,
+// so the blobs can be shared by several servers. Blobs are stored as objects of a bucket under their keys.
+//
+// Writes are streamed with multipart uploads. Uploads of a crashed server stay incomplete in the bucket,
+// they should be cleaned up with a lifecycle rule of the bucket. The appended data a crashed server leaves
+// under s3TempPrefix is cleaned up by CleanTemp.
+type S3BlobRepository struct {
+	client *minio.Client
+	bucket string
+	log    *zap.SugaredLogger
+}
+
+// OpenBlobWriter opens a writer for the blob identified by the given key.
+// The object is replaced only on successful Close, the returned writer implements Aborter.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (s *S3BlobRepository) OpenBlobWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	objectKey, err := cleanKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("cant get object key: %w", err)
+	}
+
+	s.log.Debugw("opening for write", "key", objectKey)
+
+	return s.upload(ctx, objectKey, nil), nil
+}
+
+// OpenBlobAppender opens a writer appending to the blob identified by the given key.
+// If the blob does not exist, it will be created.
+// Objects can't be appended to, so the written data is uploaded to a temporary object, and on successful Close
+// the storage composes the existing object and the temporary one into the new object, without downloading anything.
+// Objects smaller than s3MinComposeSize can't be composed, their content is uploaded again before the written data.
+// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+func (s *S3BlobRepository) OpenBlobAppender(ctx context.Context, key string) (io.WriteCloser, error) {
+	objectKey, err := cleanKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("cant get object key: %w", err)
+	}
+
+	s.log.Debugw("opening for append", "key", objectKey)
+
+	info, err := s.client.StatObject(ctx, s.bucket, objectKey, minio.StatObjectOptions{})
+	if isNotFound(err) {
+		return s.upload(ctx, objectKey, nil), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cant stat object: %w", err)
+	}
+
+	if info.Size < s3MinComposeSize {
+		existing, ok, err := s.openObject(ctx, objectKey)
+		if err != nil {
+			return nil, fmt.Errorf("cant open object: %w", err)
+		}
+		if !ok {
+			return s.upload(ctx, objectKey, nil), nil
+		}
+
+		return s.upload(ctx, objectKey, existing), nil
+	}
+
+	tempKey := s3TempPrefix + uuid.NewString()
+	w := s.upload(ctx, tempKey, nil)
+	w.commit = func() error {
+		return s.composeAppended(ctx, objectKey, info.ETag, tempKey, w.written)
+	}
+	w.discard = func() {
+		s.removeTemp(ctx, tempKey)
+	}
+
+	return w, nil
+}
+
+// OpenBlobReader opens a reader for the blob identified by the given key.
+// Returns an io.ReadCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (s *S3BlobRepository) OpenBlobReader(ctx context.Context, key string) (io.ReadCloser, bool, error) {
+	return s.OpenBlobSeeker(ctx, key)
+}
+
+// OpenBlobSeeker opens a seekable reader for the blob identified by the given key.
+// After a seek the object is read with a ranged request, so the skipped content isn't downloaded.
+// Returns an io.ReadSeekCloser for reading the blob, a boolean indicating if the blob exists,
+// and an error if the operation fails.
+func (s *S3BlobRepository) OpenBlobSeeker(ctx context.Context, key string) (io.ReadSeekCloser, bool, error) {
+	objectKey, err := cleanKey(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("cant get object key: %w", err)
+	}
+
+	s.log.Debugw("opening for read", "key", objectKey)
+
+	object, ok, err := s.openObject(ctx, objectKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("cant open object: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	return object, true, nil
+}
+
+// GetBlobSize returns the size of the blob identified by the given key in bytes,
+// a boolean indicating if the blob exists, and an error if the operation fails.
+func (s *S3BlobRepository) GetBlobSize(ctx context.Context, key string) (int64, bool, error) {
+	objectKey, err := cleanKey(key)
+	if err != nil {
+		return 0, false, fmt.Errorf("cant get object key: %w", err)
+	}
+
+	info, err := s.client.StatObject(ctx, s.bucket, objectKey, minio.StatObjectOptions{})
+	if isNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("cant stat object: %w", err)
+	}
+
+	return info.Size, true, nil
+}
+
+// MoveBlob moves the blob identified by srcKey to dstKey, replacing the destination blob if it exists.
+// The object is copied on the storage side and then the source is deleted.
+// Returns an error if the operation fails.
+func (s *S3BlobRepository) MoveBlob(ctx context.Context, srcKey string, dstKey string) error {
+	srcObjectKey, err := cleanKey(srcKey)
+	if err != nil {
+		return fmt.Errorf("cant get source object key: %w", err)
+	}
+
+	dstObjectKey, err := cleanKey(dstKey)
+	if err != nil {
+		return fmt.Errorf("cant get destination object key: %w", err)
+	}
+
+	s.log.Debugw("moving", "from", srcObjectKey, "to", dstObjectKey)
+
+	size, ok, err := s.GetBlobSize(ctx, srcKey)
+	if err != nil {
+		return fmt.Errorf("cant get source size: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("cant copy object: %w", os.ErrNotExist)
+	}
+
+	dst := minio.CopyDestOptions{Bucket: s.bucket, Object: dstObjectKey}
+	src := minio.CopySrcOptions{Bucket: s.bucket, Object: srcObjectKey}
+
+	// a single copy is limited to 5GB, larger objects are copied by parts
+	if size <= s3MaxCopySize {
+		_, err = s.client.CopyObject(ctx, dst, src)
+	} else {
+		_, err = s.client.ComposeObject(ctx, dst, src)
+	}
+	if err != nil {
+		return fmt.Errorf("cant copy object: %w", err)
+	}
+
+	err = s.client.RemoveObject(ctx, s.bucket, srcObjectKey, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("cant remove source object: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlob deletes the blob identified by the given key.
+// Removing a missing object succeeds in S3, so the object is checked first, like the other backends report a missing blob.
+// Returns an error if the operation fails, wrapping os.ErrNotExist if there is no such blob.
+func (s *S3BlobRepository) DeleteBlob(ctx context.Context, key string) error {
+	objectKey, err := cleanKey(key)
+	if err != nil {
+		return fmt.Errorf("cant get object key: %w", err)
+	}
+
+	s.log.Debugw("deleting", "key", objectKey)
+
+	_, err = s.client.StatObject(ctx, s.bucket, objectKey, minio.StatObjectOptions{})
+	if isNotFound(err) {
+		return fmt.Errorf("blob not found: %w", os.ErrNotExist)
+	}
+	if err != nil {
+		return fmt.Errorf("cant stat object: %w", err)
+	}
+
+	err = s.client.RemoveObject(ctx, s.bucket, objectKey, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("cant remove object: %w", err)
+	}
+
+	return nil
+}
+
+// WalkBlobs calls fn for every stored blob, in lexical order of the keys. Walking stops at the first error returned by fn.
+// Incomplete uploads are not walked.
+// Returns the error of fn or an error if the operation fails.
+func (s *S3BlobRepository) WalkBlobs(ctx context.Context, fn func(info Info) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// stops the listing if fn fails
+	defer cancel()
+
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("cant list objects: %w", object.Err)
+		}
+
+		if strings.HasPrefix(object.Key, s3TempPrefix) {
+			continue
+		}
+
+		err := fn(Info{
+			Key:        object.Key,
+			Size:       object.Size,
+			ModifiedAt: object.LastModified,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CleanTemp deletes the appended data left by the writers of crashed servers, uploaded before modifiedBefore.
+// Returns the number of deleted objects or an error if the operation fails.
+func (s *S3BlobRepository) CleanTemp(ctx context.Context, modifiedBefore time.Time) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	// stops the listing if a removal fails
+	defer cancel()
+
+	cleaned := 0
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s3TempPrefix, Recursive: true}) {
+		if object.Err != nil {
+			return cleaned, fmt.Errorf("cant list objects: %w", object.Err)
+		}
+
+		if !object.LastModified.Before(modifiedBefore) {
+			continue
+		}
+
+		s.log.Debugw("deleting stale temporary object", "key", object.Key)
+
+		err := s.client.RemoveObject(ctx, s.bucket, object.Key, minio.RemoveObjectOptions{})
+		if err != nil {
+			return cleaned, fmt.Errorf("cant remove object: %w", err)
+		}
+
+		cleaned++
+	}
+
+	return cleaned, nil
+}
+
+// composeAppended replaces the object with the composition of its content, which must still have the etag,
+// and the appended data uploaded to the temporary object. The temporary object is removed either way.
+func (s *S3BlobRepository) composeAppended(ctx context.Context, objectKey string, etag string, tempKey string, appended int64) error {
+	defer s.removeTemp(ctx, tempKey)
+
+	if appended == 0 {
+		return nil
+	}
+
+	_, err := s.client.ComposeObject(
+		ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: objectKey},
+		// the object must not be replaced while the data is appended, or the data would follow another content
+		minio.CopySrcOptions{Bucket: s.bucket, Object: objectKey, MatchETag: etag},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: tempKey},
+	)
+	if err != nil {
+		return fmt.Errorf("cant compose object: %w", err)
+	}
+
+	return nil
+}
+
+// removeTemp removes the temporary object, errors are only logged, CleanTemp removes it later.
+func (s *S3BlobRepository) removeTemp(ctx context.Context, tempKey string) {
+	err := s.client.RemoveObject(context.WithoutCancel(ctx), s.bucket, tempKey, minio.RemoveObjectOptions{})
+	if err != nil {
+		s.log.Errorw("cant remove temporary object", "key", tempKey, "err", err)
+	}
+}
+
+// openObject opens the object for reading, checking that it exists. The object isn't downloaded until read.
+func (s *S3BlobRepository) openObject(ctx context.Context, objectKey string) (*minio.Object, bool, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, false, fmt.Errorf("cant get object: %w", err)
+	}
+
+	_, err = object.Stat()
+	if isNotFound(err) {
+		_ = object.Close()
+
+		return nil, false, nil
+	}
+	if err != nil {
+		_ = object.Close()
+
+		return nil, false, fmt.Errorf("cant stat object: %w", err)
+	}
+
+	return object, true, nil
+}
+
+// upload starts uploading the object from the returned writer. If prefix is not nil, it's uploaded before the written data.
+func (s *S3BlobRepository) upload(ctx context.Context, objectKey string, prefix io.ReadCloser) *s3Writer {
+	pr, pw := io.Pipe()
+	w := &s3Writer{
+		pw:     pw,
+		result: make(chan error, 1),
+		key:    objectKey,
+		log:    s.log,
+	}
+
+	var src io.Reader = pr
+	if prefix != nil {
+		src = io.MultiReader(prefix, pr)
+	}
+
+	go func() {
+		err := s.putObject(ctx, objectKey, src)
+		if prefix != nil {
+			_ = prefix.Close()
+		}
+
+		// unblocks the writes if the upload failed before reading everything
+		if err != nil {
+			_ = pr.CloseWithError(err)
+		} else {
+			_ = pr.Close()
+		}
+
+		w.result <- err
+	}()
+
+	return w
+}
+
+// putObject uploads everything read from src. Content smaller than a part is uploaded with a single request,
+// larger one is streamed with a multipart upload.
+func (s *S3BlobRepository) putObject(ctx context.Context, objectKey string, src io.Reader) error {
+	opts := minio.PutObjectOptions{
+		PartSize: s3PartSize,
+		// the parts are still protected by CRC32C checksums, signing them would only add a pass over the data
+		DisableContentSha256: true,
+	}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, src, s3PartSize)
+	if errors.Is(err, io.EOF) {
+		_, err = s.client.PutObject(ctx, s.bucket, objectKey, &buf, n, opts)
+
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, objectKey, io.MultiReader(&buf, src), -1, opts)
+
+	return err
+}
+
+// errAborted is passed to the upload to make it fail when the writer is aborted.
+var errAborted = errors.New("write aborted")
+
+// s3Writer streams the written data to an upload running in the background.
+type s3Writer struct {
+	pw      *io.PipeWriter
+	result  chan error
+	key     string
+	written int64
+	// commit is called on Close after the upload succeeds, if set
+	commit func() error
+	// discard is called if the upload fails or is aborted, if set
+	discard func()
+	log     *zap.SugaredLogger
+	done    bool
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	n, err := w.pw.Write(p)
+	w.written += int64(n)
+
+	return n, err
+}
+
+// Close finishes the upload and waits for the object to be stored.
+// If the upload fails, the previous object is left intact.
+func (w *s3Writer) Close() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	_ = w.pw.Close()
+
+	err := <-w.result
+	if err != nil {
+		if w.discard != nil {
+			w.discard()
+		}
+
+		return fmt.Errorf("cant upload object: %w", err)
+	}
+
+	if w.commit != nil {
+		return w.commit()
+	}
+
+	return nil
+}
+
+// Abort cancels the upload, the previous object is left intact.
+func (w *s3Writer) Abort() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+
+	w.log.Debugw("aborting write", "key", w.key)
+
+	_ = w.pw.CloseWithError(errAborted)
+
+	err := <-w.result
+	if err != nil && !errors.Is(err, errAborted) {
+		w.log.Errorw("upload failed while aborting", "key", w.key, "err", err)
+	}
+
+	// the upload may have finished before it was aborted
+	if w.discard != nil {
+		w.discard()
+	}
+
+	return nil
+}
+
+// cleanKey validates the key the same way the files do, so the blobs can be moved between the backends.
+func cleanKey(key string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(key, "/"))
+
+	if strings.Contains(clean, "..") {
+		return "", fmt.Errorf("path traversal detected")
+	}
+
+	if clean == "." {
+		return "", fmt.Errorf("key is empty")
+	}
+
+	return clean, nil
+}
+
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return minio.ToErrorResponse(err).StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,461 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kuvalkin/gophkeeper/internal/storage/blob"
+	"github.com/kuvalkin/gophkeeper/internal/support/utils"
+)
+
+const testBucket = "blobs"
+
+func TestS3_New(t *testing.T) {
+	opts := newFakeS3(t)
+
+	t.Run("success", func(t *testing.T) {
+		repo, err := blob.NewS3BlobRepository(context.Background(), opts)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+	})
+
+	t.Run("bucket not exists", func(t *testing.T) {
+		opts := opts
+		opts.Bucket = "missing"
+
+		repo, err := blob.NewS3BlobRepository(context.Background(), opts)
+		require.Error(t, err)
+		require.Nil(t, repo)
+	})
+
+	t.Run("no bucket", func(t *testing.T) {
+		opts := opts
+		opts.Bucket = ""
+
+		repo, err := blob.NewS3BlobRepository(context.Background(), opts)
+		require.Error(t, err)
+		require.Nil(t, repo)
+	})
+}
+
+func TestS3_Writer(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+
+	t.Run("success", func(t *testing.T) {
+		writeBlob(t, repo, "test/test-with-subdirs", "content")
+		requireBlob(t, repo, "test/test-with-subdirs", "content")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		writeBlob(t, repo, "empty", "")
+		requireBlob(t, repo, "empty", "")
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		content := strings.Repeat("a", 20*1024*1024)
+
+		writeBlob(t, repo, "large", content)
+		requireBlob(t, repo, "large", content)
+	})
+
+	t.Run("replaced on close", func(t *testing.T) {
+		writeBlob(t, repo, "atomic", "old")
+
+		wc, err := repo.OpenBlobWriter(ctx, "atomic")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("new"))
+		require.NoError(t, err)
+
+		// not replaced until closed
+		requireBlob(t, repo, "atomic", "old")
+
+		require.NoError(t, wc.Close())
+		requireBlob(t, repo, "atomic", "new")
+
+		require.ErrorIs(t, wc.Close(), os.ErrClosed)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		writeBlob(t, repo, "aborted", "old")
+
+		wc, err := repo.OpenBlobWriter(ctx, "aborted")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("new"))
+		require.NoError(t, err)
+
+		aborter, ok := wc.(blob.Aborter)
+		require.True(t, ok)
+		require.NoError(t, aborter.Abort())
+
+		requireBlob(t, repo, "aborted", "old")
+
+		require.ErrorIs(t, wc.Close(), os.ErrClosed)
+	})
+
+	t.Run("abort new blob", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "aborted-new")
+		require.NoError(t, err)
+
+		require.NoError(t, wc.(blob.Aborter).Abort())
+
+		_, exists, err := repo.OpenBlobReader(ctx, "aborted-new")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "../test")
+		require.Error(t, err)
+		require.Nil(t, wc)
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		wc, err := repo.OpenBlobWriter(ctx, "")
+		require.Error(t, err)
+		require.Nil(t, wc)
+	})
+}
+
+func TestS3_Seeker(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+	writeBlob(t, repo, "test", "test content")
+
+	t.Run("success", func(t *testing.T) {
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "test")
+		require.NoError(t, err)
+		require.True(t, exists)
+		defer rsc.Close()
+
+		_, err = rsc.Seek(5, io.SeekStart)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(rsc)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		rsc, exists, err := repo.OpenBlobSeeker(ctx, "not-exists")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Nil(t, rsc)
+	})
+}
+
+func TestS3_Delete(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+
+	t.Run("success", func(t *testing.T) {
+		writeBlob(t, repo, "test", "content")
+
+		require.NoError(t, repo.DeleteBlob(ctx, "test"))
+
+		_, exists, err := repo.OpenBlobReader(ctx, "test")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		require.ErrorIs(t, repo.DeleteBlob(ctx, "not-exists"), os.ErrNotExist)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		require.Error(t, repo.DeleteBlob(ctx, "../test"))
+	})
+}
+
+func TestS3_Appender(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+
+	t.Run("success", func(t *testing.T) {
+		for _, part := range []string{"first ", "second"} {
+			wc, err := repo.OpenBlobAppender(ctx, "test")
+			require.NoError(t, err)
+			_, err = wc.Write([]byte(part))
+			require.NoError(t, err)
+			require.NoError(t, wc.Close())
+		}
+
+		requireBlob(t, repo, "test", "first second")
+	})
+
+	t.Run("composed", func(t *testing.T) {
+		// large enough to be composed with the appended data on the storage side
+		existing := strings.Repeat("a", 5*1024*1024)
+		writeBlob(t, repo, "large", existing)
+
+		wc, err := repo.OpenBlobAppender(ctx, "large")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("tail"))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		requireBlob(t, repo, "large", existing+"tail")
+		requireNoS3TempObjects(t, repo)
+	})
+
+	t.Run("composed abort", func(t *testing.T) {
+		existing := strings.Repeat("b", 5*1024*1024)
+		writeBlob(t, repo, "aborted", existing)
+
+		wc, err := repo.OpenBlobAppender(ctx, "aborted")
+		require.NoError(t, err)
+		_, err = wc.Write([]byte("tail"))
+		require.NoError(t, err)
+		require.NoError(t, wc.(blob.Aborter).Abort())
+
+		requireBlob(t, repo, "aborted", existing)
+		requireNoS3TempObjects(t, repo)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		wc, err := repo.OpenBlobAppender(ctx, "../test")
+		require.Error(t, err)
+		require.Nil(t, wc)
+	})
+}
+
+func TestS3_CleanTemp(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+	writeBlob(t, repo, ".tmp/stale", "appended")
+	writeBlob(t, repo, "blob", "content")
+
+	t.Run("skips recent", func(t *testing.T) {
+		cleaned, err := repo.CleanTemp(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, cleaned)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		cleaned, err := repo.CleanTemp(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 1, cleaned)
+
+		requireNoS3TempObjects(t, repo)
+		requireBlob(t, repo, "blob", "content")
+	})
+}
+
+func TestS3_Size(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+
+	t.Run("success", func(t *testing.T) {
+		writeBlob(t, repo, "test", "content")
+
+		size, exists, err := repo.GetBlobSize(ctx, "test")
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, int64(7), size)
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		size, exists, err := repo.GetBlobSize(ctx, "not-exists")
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Zero(t, size)
+	})
+}
+
+func TestS3_Move(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+
+	t.Run("success", func(t *testing.T) {
+		writeBlob(t, repo, "src", "new")
+		writeBlob(t, repo, "dst/blob", "old")
+
+		require.NoError(t, repo.MoveBlob(ctx, "src", "dst/blob"))
+
+		requireBlob(t, repo, "dst/blob", "new")
+
+		_, exists, err := repo.OpenBlobReader(ctx, "src")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("source not exists", func(t *testing.T) {
+		require.ErrorIs(t, repo.MoveBlob(ctx, "not-exists", "dst"), os.ErrNotExist)
+	})
+}
+
+func TestS3_Walk(t *testing.T) {
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	repo := newS3Repo(t)
+	writeBlob(t, repo, "a/b", "content")
+	writeBlob(t, repo, "c", "")
+	writeBlob(t, repo, ".tmp/appended", "content")
+
+	t.Run("success", func(t *testing.T) {
+		var infos []blob.Info
+		err := repo.WalkBlobs(ctx, func(info blob.Info) error {
+			infos = append(infos, info)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		// the temporary objects aren't blobs
+		require.Len(t, infos, 2)
+		require.Equal(t, "a/b", infos[0].Key)
+		require.Equal(t, int64(7), infos[0].Size)
+		require.False(t, infos[0].ModifiedAt.IsZero())
+		require.Equal(t, "c", infos[1].Key)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		testErr := errors.New("test")
+		calls := 0
+
+		err := repo.WalkBlobs(ctx, func(info blob.Info) error {
+			calls++
+
+			return testErr
+		})
+		require.ErrorIs(t, err, testErr)
+		require.Equal(t, 1, calls)
+	})
+}
+
+// newFakeS3 starts an in-memory S3-compatible server with an empty bucket.
+func newFakeS3(t *testing.T) blob.S3Options {
+	t.Helper()
+
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket(testBucket))
+
+	server := httptest.NewServer(copyPartHandler(backend, gofakes3.New(backend).Server()))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return blob.S3Options{
+		Endpoint:  u.Host,
+		Region:    "us-east-1",
+		Bucket:    testBucket,
+		AccessKey: "access",
+		SecretKey: "secret",
+	}
+}
+
+func newS3Repo(t *testing.T) *blob.S3BlobRepository {
+	t.Helper()
+
+	repo, err := blob.NewS3BlobRepository(context.Background(), newFakeS3(t))
+	require.NoError(t, err)
+
+	return repo
+}
+
+// copyPartHandler adds the copying of parts, which the fake server doesn't support, by uploading the copied range instead.
+func copyPartHandler(backend *s3mem.Backend, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		source := r.Header.Get("X-Amz-Copy-Source")
+		if r.Method != http.MethodPut || source == "" || !r.URL.Query().Has("partNumber") {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		source, err := url.PathUnescape(strings.TrimPrefix(source, "/"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+		bucket, object, _ := strings.Cut(source, "/")
+
+		var rangeRequest *gofakes3.ObjectRangeRequest
+		if copyRange := r.Header.Get("X-Amz-Copy-Source-Range"); copyRange != "" {
+			rangeRequest = &gofakes3.ObjectRangeRequest{}
+			_, err = fmt.Sscanf(copyRange, "bytes=%d-%d", &rangeRequest.Start, &rangeRequest.End)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		obj, err := backend.GetObject(bucket, object, rangeRequest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		}
+		defer obj.Contents.Close()
+
+		content, err := io.ReadAll(obj.Contents)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		upload := r.Clone(r.Context())
+		upload.Header.Del("X-Amz-Copy-Source")
+		upload.Header.Del("X-Amz-Copy-Source-Range")
+		upload.Body = io.NopCloser(bytes.NewReader(content))
+		upload.ContentLength = int64(len(content))
+		upload.Header.Set("Content-Length", strconv.Itoa(len(content)))
+
+		recorder := httptest.NewRecorder()
+		next.ServeHTTP(recorder, upload)
+		if recorder.Code != http.StatusOK {
+			w.WriteHeader(recorder.Code)
+			_, _ = w.Write(recorder.Body.Bytes())
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, "<CopyPartResult><ETag>%s</ETag></CopyPartResult>", recorder.Header().Get("ETag"))
+	})
+}
+
+func requireNoS3TempObjects(t *testing.T, repo *blob.S3BlobRepository) {
+	t.Helper()
+
+	ctx, cancel := utils.TestContext(t)
+	defer cancel()
+
+	// every temporary object is older than a time in the future
+	cleaned, err := repo.CleanTemp(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Zero(t, cleaned)
+}
@@ -2,20 +2,71 @@
 // It provides abstractions for storing, retrieving, and deleting binary large objects (blobs).
 package blob
 
-import "io"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 // Repository defines the interface for a blob storage repository.
+// The writers and readers it opens stay bound to the context they were opened with.
 type Repository interface {
 	// OpenBlobWriter opens a writer for the blob identified by the given key.
+	// The writer may implement Aborter, in which case the blob is replaced only on successful Close.
 	// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
-	OpenBlobWriter(key string) (io.WriteCloser, error)
+	OpenBlobWriter(ctx context.Context, key string) (io.WriteCloser, error)
+
+	// OpenBlobAppender opens a writer appending to the blob identified by the given key.
+	// If the blob does not exist, it will be created.
+	// Returns an io.WriteCloser for writing to the blob or an error if the operation fails.
+	OpenBlobAppender(ctx context.Context, key string) (io.WriteCloser, error)
 
 	// OpenBlobReader opens a reader for the blob identified by the given key.
 	// Returns an io.ReadCloser for reading the blob, a boolean indicating if the blob exists,
 	// and an error if the operation fails.
-	OpenBlobReader(key string) (io.ReadCloser, bool, error)
+	OpenBlobReader(ctx context.Context, key string) (io.ReadCloser, bool, error)
+
+	// OpenBlobSeeker opens a seekable reader for the blob identified by the given key, so it can be read from an offset.
+	// Returns an io.ReadSeekCloser for reading the blob, a boolean indicating if the blob exists,
+	// and an error if the operation fails.
+	OpenBlobSeeker(ctx context.Context, key string) (io.ReadSeekCloser, bool, error)
+
+	// GetBlobSize returns the size of the blob identified by the given key in bytes,
+	// a boolean indicating if the blob exists, and an error if the operation fails.
+	GetBlobSize(ctx context.Context, key string) (int64, bool, error)
+
+	// MoveBlob moves the blob identified by srcKey to dstKey, replacing the destination blob if it exists.
+	// Returns an error if the operation fails.
+	MoveBlob(ctx context.Context, srcKey string, dstKey string) error
 
 	// DeleteBlob deletes the blob identified by the given key.
 	// Returns an error if the operation fails.
-	DeleteBlob(key string) error
+	DeleteBlob(ctx context.Context, key string) error
+
+	// WalkBlobs calls fn for every stored blob, in no particular order. Walking stops at the first error returned by fn.
+	// Returns the error of fn or an error if the operation fails.
+	WalkBlobs(ctx context.Context, fn func(info Info) error) error
+}
+
+// Info describes a stored blob.
+type Info struct {
+	Key        string    // Key identifies the blob, it can be passed to the other methods of the repository.
+	Size       int64     // Size is the size of the blob in bytes.
+	ModifiedAt time.Time // ModifiedAt is the time the blob was last written.
+}
+
+// Aborter is implemented by blob writers that replace the blob only on successful Close,
+// so a failed write doesn't destroy the previous content of the blob.
+type Aborter interface {
+	// Abort discards everything written so far, leaving the previous content of the blob intact.
+	// The writer can't be used after it.
+	Abort() error
+}
+
+// TempCleaner is implemented by blob repositories whose writers keep temporary data apart from the blobs,
+// which is left behind if a writer crashes before it's closed or aborted.
+type TempCleaner interface {
+	// CleanTemp deletes the temporary data last written before modifiedBefore, so the writers in progress aren't affected.
+	// Returns the number of deleted items or an error if the operation fails.
+	CleanTemp(ctx context.Context, modifiedBefore time.Time) (int, error)
 }
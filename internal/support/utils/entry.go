@@ -8,6 +8,8 @@ import (
 
 // GetEntryKey generates a SHA-256 hash of the concatenation of the prefix and name,
 // and returns it as a hexadecimal string.
+// It's the legacy unkeyed entry key, new entries are stored under the keys from the entry service,
+// this one is only used to recognize the entries stored before.
 func GetEntryKey(prefix string, name string) string {
 	hash := sha256.Sum256([]byte(prefix + name))
 
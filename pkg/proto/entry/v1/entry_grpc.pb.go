@@ -20,9 +20,33 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	EntryService_GetEntry_FullMethodName    = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetEntry"
-	EntryService_SetEntry_FullMethodName    = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/SetEntry"
-	EntryService_DeleteEntry_FullMethodName = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/DeleteEntry"
+	EntryService_GetEntry_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetEntry"
+	EntryService_SetEntry_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/SetEntry"
+	EntryService_DeleteEntry_FullMethodName         = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/DeleteEntry"
+	EntryService_RenameEntry_FullMethodName         = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/RenameEntry"
+	EntryService_BatchDeleteEntries_FullMethodName  = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/BatchDeleteEntries"
+	EntryService_BatchGetMetadata_FullMethodName    = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/BatchGetMetadata"
+	EntryService_ListEntries_FullMethodName         = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/ListEntries"
+	EntryService_ListEntryVersions_FullMethodName   = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/ListEntryVersions"
+	EntryService_GetEntryVersion_FullMethodName     = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetEntryVersion"
+	EntryService_RestoreEntryVersion_FullMethodName = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/RestoreEntryVersion"
+	EntryService_ListChanges_FullMethodName         = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/ListChanges"
+	EntryService_WatchEntries_FullMethodName        = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/WatchEntries"
+	EntryService_CreateUploadSession_FullMethodName = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/CreateUploadSession"
+	EntryService_GetUploadSession_FullMethodName    = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetUploadSession"
+	EntryService_UploadChunks_FullMethodName        = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/UploadChunks"
+	EntryService_FinalizeUpload_FullMethodName      = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/FinalizeUpload"
+	EntryService_ListTrash_FullMethodName           = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/ListTrash"
+	EntryService_RestoreEntry_FullMethodName        = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/RestoreEntry"
+	EntryService_PurgeEntry_FullMethodName          = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/PurgeEntry"
+	EntryService_GetUsage_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetUsage"
+	EntryService_SetKeyPair_FullMethodName          = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/SetKeyPair"
+	EntryService_GetKeyPair_FullMethodName          = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetKeyPair"
+	EntryService_GetPublicKey_FullMethodName        = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetPublicKey"
+	EntryService_ShareEntry_FullMethodName          = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/ShareEntry"
+	EntryService_ListShares_FullMethodName          = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/ListShares"
+	EntryService_GetSharedEntry_FullMethodName      = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/GetSharedEntry"
+	EntryService_RevokeShare_FullMethodName         = "/com.kuvalkin.gophkeeper.proto.entry.v1.EntryService/RevokeShare"
 )
 
 // EntryServiceClient is the client API for EntryService service.
@@ -31,7 +55,60 @@ const (
 type EntryServiceClient interface {
 	GetEntry(ctx context.Context, in *GetEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Entry], error)
 	SetEntry(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SetEntryRequest, SetEntryResponse], error)
+	// DeleteEntry moves the entry to the trash, unless the trash is disabled on the server.
+	// The previous versions of the entry are deleted for good.
 	DeleteEntry(ctx context.Context, in *DeleteEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RenameEntry moves the entry and its previous versions to a new key and name without re-uploading the content.
+	// It fails with NOT_FOUND if there is no such entry, ALREADY_EXISTS if the new key is taken,
+	// and FAILED_PRECONDITION if expected_version doesn't match.
+	RenameEntry(ctx context.Context, in *RenameEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchDeleteEntries deletes the entries in one transaction. If any of them is rejected
+	// with FAILED_PRECONDITION because of its expected_version, nothing is deleted.
+	BatchDeleteEntries(ctx context.Context, in *BatchDeleteEntriesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchGetMetadata returns the entries with the given keys without content. Missing entries are omitted.
+	BatchGetMetadata(ctx context.Context, in *BatchGetMetadataRequest, opts ...grpc.CallOption) (*BatchGetMetadataResponse, error)
+	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
+	ListEntryVersions(ctx context.Context, in *ListEntryVersionsRequest, opts ...grpc.CallOption) (*ListEntryVersionsResponse, error)
+	GetEntryVersion(ctx context.Context, in *GetEntryVersionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Entry], error)
+	RestoreEntryVersion(ctx context.Context, in *RestoreEntryVersionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error)
+	// WatchEntries streams changes of the user's entries as they happen, until the client disconnects.
+	// The stream is ended with ABORTED if the client doesn't keep up, missed changes can be fetched with ListChanges.
+	WatchEntries(ctx context.Context, in *WatchEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Change], error)
+	// Upload sessions make uploading of large content resumable. A session is created with the metadata and the size
+	// of the content, the content is uploaded with UploadChunks, possibly with several calls continuing from the
+	// committed offset, and then the entry is stored with FinalizeUpload.
+	CreateUploadSession(ctx context.Context, in *CreateUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	GetUploadSession(ctx context.Context, in *GetUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	UploadChunks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunkRequest, UploadSession], error)
+	FinalizeUpload(ctx context.Context, in *FinalizeUploadRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListTrash returns the deleted entries kept in the trash, most recently deleted first.
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	// RestoreEntry moves a deleted entry back from the trash. It fails with NOT_FOUND if the entry isn't in the trash,
+	// and ALREADY_EXISTS if another entry with the same key has been stored since.
+	RestoreEntry(ctx context.Context, in *RestoreEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// PurgeEntry deletes an entry from the trash for good. It fails with NOT_FOUND if the entry isn't in the trash.
+	PurgeEntry(ctx context.Context, in *PurgeEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetUsage returns the storage used by the user and the limits of it.
+	// Writes exceeding the limits are rejected with RESOURCE_EXHAUSTED.
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error)
+	// Entries are shared by uploading a copy encrypted to the recipient's public key, so every user sharing
+	// or receiving entries needs a key pair. SetKeyPair fails with ALREADY_EXISTS if the user already has one,
+	// GetKeyPair and GetPublicKey fail with NOT_FOUND if there is none.
+	SetKeyPair(ctx context.Context, in *SetKeyPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetKeyPair(ctx context.Context, in *GetKeyPairRequest, opts ...grpc.CallOption) (*KeyPair, error)
+	GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*PublicKey, error)
+	// ShareEntry stores a copy of the user's entry for the recipient, replacing the previous copy if any.
+	// It fails with NOT_FOUND if the user has no such entry or the recipient has no key pair,
+	// and INVALID_ARGUMENT if the recipient is the user. The copy counts towards the user's usage.
+	ShareEntry(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ShareEntryRequest, Share], error)
+	// ListShares returns the entries shared by the user, or with the user if incoming is set, most recently shared first.
+	ListShares(ctx context.Context, in *ListSharesRequest, opts ...grpc.CallOption) (*ListSharesResponse, error)
+	// GetSharedEntry streams a copy of an entry shared with the user. The first message has the share, the rest have content.
+	GetSharedEntry(ctx context.Context, in *GetSharedEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SharedEntry], error)
+	// RevokeShare deletes the copy of the user's entry shared with the recipient.
+	// It fails with NOT_FOUND if the entry isn't shared with the recipient.
+	RevokeShare(ctx context.Context, in *RevokeShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type entryServiceClient struct {
@@ -84,13 +161,339 @@ func (c *entryServiceClient) DeleteEntry(ctx context.Context, in *DeleteEntryReq
 	return out, nil
 }
 
+func (c *entryServiceClient) RenameEntry(ctx context.Context, in *RenameEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_RenameEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) BatchDeleteEntries(ctx context.Context, in *BatchDeleteEntriesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_BatchDeleteEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) BatchGetMetadata(ctx context.Context, in *BatchGetMetadataRequest, opts ...grpc.CallOption) (*BatchGetMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetMetadataResponse)
+	err := c.cc.Invoke(ctx, EntryService_BatchGetMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEntriesResponse)
+	err := c.cc.Invoke(ctx, EntryService_ListEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) ListEntryVersions(ctx context.Context, in *ListEntryVersionsRequest, opts ...grpc.CallOption) (*ListEntryVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEntryVersionsResponse)
+	err := c.cc.Invoke(ctx, EntryService_ListEntryVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetEntryVersion(ctx context.Context, in *GetEntryVersionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Entry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[2], EntryService_GetEntryVersion_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetEntryVersionRequest, Entry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_GetEntryVersionClient = grpc.ServerStreamingClient[Entry]
+
+func (c *entryServiceClient) RestoreEntryVersion(ctx context.Context, in *RestoreEntryVersionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_RestoreEntryVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChangesResponse)
+	err := c.cc.Invoke(ctx, EntryService_ListChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) WatchEntries(ctx context.Context, in *WatchEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Change], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[3], EntryService_WatchEntries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEntriesRequest, Change]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_WatchEntriesClient = grpc.ServerStreamingClient[Change]
+
+func (c *entryServiceClient) CreateUploadSession(ctx context.Context, in *CreateUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, EntryService_CreateUploadSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetUploadSession(ctx context.Context, in *GetUploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, EntryService_GetUploadSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) UploadChunks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunkRequest, UploadSession], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[4], EntryService_UploadChunks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadChunkRequest, UploadSession]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_UploadChunksClient = grpc.ClientStreamingClient[UploadChunkRequest, UploadSession]
+
+func (c *entryServiceClient) FinalizeUpload(ctx context.Context, in *FinalizeUploadRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_FinalizeUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, EntryService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) RestoreEntry(ctx context.Context, in *RestoreEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_RestoreEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) PurgeEntry(ctx context.Context, in *PurgeEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_PurgeEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Usage)
+	err := c.cc.Invoke(ctx, EntryService_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) SetKeyPair(ctx context.Context, in *SetKeyPairRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_SetKeyPair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetKeyPair(ctx context.Context, in *GetKeyPairRequest, opts ...grpc.CallOption) (*KeyPair, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeyPair)
+	err := c.cc.Invoke(ctx, EntryService_GetKeyPair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*PublicKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublicKey)
+	err := c.cc.Invoke(ctx, EntryService_GetPublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) ShareEntry(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ShareEntryRequest, Share], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[5], EntryService_ShareEntry_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ShareEntryRequest, Share]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_ShareEntryClient = grpc.ClientStreamingClient[ShareEntryRequest, Share]
+
+func (c *entryServiceClient) ListShares(ctx context.Context, in *ListSharesRequest, opts ...grpc.CallOption) (*ListSharesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSharesResponse)
+	err := c.cc.Invoke(ctx, EntryService_ListShares_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetSharedEntry(ctx context.Context, in *GetSharedEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SharedEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[6], EntryService_GetSharedEntry_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetSharedEntryRequest, SharedEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_GetSharedEntryClient = grpc.ServerStreamingClient[SharedEntry]
+
+func (c *entryServiceClient) RevokeShare(ctx context.Context, in *RevokeShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EntryService_RevokeShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EntryServiceServer is the server API for EntryService service.
 // All implementations must embed UnimplementedEntryServiceServer
 // for forward compatibility.
 type EntryServiceServer interface {
 	GetEntry(*GetEntryRequest, grpc.ServerStreamingServer[Entry]) error
 	SetEntry(grpc.BidiStreamingServer[SetEntryRequest, SetEntryResponse]) error
+	// DeleteEntry moves the entry to the trash, unless the trash is disabled on the server.
+	// The previous versions of the entry are deleted for good.
 	DeleteEntry(context.Context, *DeleteEntryRequest) (*emptypb.Empty, error)
+	// RenameEntry moves the entry and its previous versions to a new key and name without re-uploading the content.
+	// It fails with NOT_FOUND if there is no such entry, ALREADY_EXISTS if the new key is taken,
+	// and FAILED_PRECONDITION if expected_version doesn't match.
+	RenameEntry(context.Context, *RenameEntryRequest) (*emptypb.Empty, error)
+	// BatchDeleteEntries deletes the entries in one transaction. If any of them is rejected
+	// with FAILED_PRECONDITION because of its expected_version, nothing is deleted.
+	BatchDeleteEntries(context.Context, *BatchDeleteEntriesRequest) (*emptypb.Empty, error)
+	// BatchGetMetadata returns the entries with the given keys without content. Missing entries are omitted.
+	BatchGetMetadata(context.Context, *BatchGetMetadataRequest) (*BatchGetMetadataResponse, error)
+	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
+	ListEntryVersions(context.Context, *ListEntryVersionsRequest) (*ListEntryVersionsResponse, error)
+	GetEntryVersion(*GetEntryVersionRequest, grpc.ServerStreamingServer[Entry]) error
+	RestoreEntryVersion(context.Context, *RestoreEntryVersionRequest) (*emptypb.Empty, error)
+	ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error)
+	// WatchEntries streams changes of the user's entries as they happen, until the client disconnects.
+	// The stream is ended with ABORTED if the client doesn't keep up, missed changes can be fetched with ListChanges.
+	WatchEntries(*WatchEntriesRequest, grpc.ServerStreamingServer[Change]) error
+	// Upload sessions make uploading of large content resumable. A session is created with the metadata and the size
+	// of the content, the content is uploaded with UploadChunks, possibly with several calls continuing from the
+	// committed offset, and then the entry is stored with FinalizeUpload.
+	CreateUploadSession(context.Context, *CreateUploadSessionRequest) (*UploadSession, error)
+	GetUploadSession(context.Context, *GetUploadSessionRequest) (*UploadSession, error)
+	UploadChunks(grpc.ClientStreamingServer[UploadChunkRequest, UploadSession]) error
+	FinalizeUpload(context.Context, *FinalizeUploadRequest) (*emptypb.Empty, error)
+	// ListTrash returns the deleted entries kept in the trash, most recently deleted first.
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	// RestoreEntry moves a deleted entry back from the trash. It fails with NOT_FOUND if the entry isn't in the trash,
+	// and ALREADY_EXISTS if another entry with the same key has been stored since.
+	RestoreEntry(context.Context, *RestoreEntryRequest) (*emptypb.Empty, error)
+	// PurgeEntry deletes an entry from the trash for good. It fails with NOT_FOUND if the entry isn't in the trash.
+	PurgeEntry(context.Context, *PurgeEntryRequest) (*emptypb.Empty, error)
+	// GetUsage returns the storage used by the user and the limits of it.
+	// Writes exceeding the limits are rejected with RESOURCE_EXHAUSTED.
+	GetUsage(context.Context, *GetUsageRequest) (*Usage, error)
+	// Entries are shared by uploading a copy encrypted to the recipient's public key, so every user sharing
+	// or receiving entries needs a key pair. SetKeyPair fails with ALREADY_EXISTS if the user already has one,
+	// GetKeyPair and GetPublicKey fail with NOT_FOUND if there is none.
+	SetKeyPair(context.Context, *SetKeyPairRequest) (*emptypb.Empty, error)
+	GetKeyPair(context.Context, *GetKeyPairRequest) (*KeyPair, error)
+	GetPublicKey(context.Context, *GetPublicKeyRequest) (*PublicKey, error)
+	// ShareEntry stores a copy of the user's entry for the recipient, replacing the previous copy if any.
+	// It fails with NOT_FOUND if the user has no such entry or the recipient has no key pair,
+	// and INVALID_ARGUMENT if the recipient is the user. The copy counts towards the user's usage.
+	ShareEntry(grpc.ClientStreamingServer[ShareEntryRequest, Share]) error
+	// ListShares returns the entries shared by the user, or with the user if incoming is set, most recently shared first.
+	ListShares(context.Context, *ListSharesRequest) (*ListSharesResponse, error)
+	// GetSharedEntry streams a copy of an entry shared with the user. The first message has the share, the rest have content.
+	GetSharedEntry(*GetSharedEntryRequest, grpc.ServerStreamingServer[SharedEntry]) error
+	// RevokeShare deletes the copy of the user's entry shared with the recipient.
+	// It fails with NOT_FOUND if the entry isn't shared with the recipient.
+	RevokeShare(context.Context, *RevokeShareRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedEntryServiceServer()
 }
 
@@ -110,6 +513,78 @@ func (UnimplementedEntryServiceServer) SetEntry(grpc.BidiStreamingServer[SetEntr
 func (UnimplementedEntryServiceServer) DeleteEntry(context.Context, *DeleteEntryRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntry not implemented")
 }
+func (UnimplementedEntryServiceServer) RenameEntry(context.Context, *RenameEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameEntry not implemented")
+}
+func (UnimplementedEntryServiceServer) BatchDeleteEntries(context.Context, *BatchDeleteEntriesRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteEntries not implemented")
+}
+func (UnimplementedEntryServiceServer) BatchGetMetadata(context.Context, *BatchGetMetadataRequest) (*BatchGetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetMetadata not implemented")
+}
+func (UnimplementedEntryServiceServer) ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEntries not implemented")
+}
+func (UnimplementedEntryServiceServer) ListEntryVersions(context.Context, *ListEntryVersionsRequest) (*ListEntryVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEntryVersions not implemented")
+}
+func (UnimplementedEntryServiceServer) GetEntryVersion(*GetEntryVersionRequest, grpc.ServerStreamingServer[Entry]) error {
+	return status.Errorf(codes.Unimplemented, "method GetEntryVersion not implemented")
+}
+func (UnimplementedEntryServiceServer) RestoreEntryVersion(context.Context, *RestoreEntryVersionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEntryVersion not implemented")
+}
+func (UnimplementedEntryServiceServer) ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChanges not implemented")
+}
+func (UnimplementedEntryServiceServer) WatchEntries(*WatchEntriesRequest, grpc.ServerStreamingServer[Change]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEntries not implemented")
+}
+func (UnimplementedEntryServiceServer) CreateUploadSession(context.Context, *CreateUploadSessionRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUploadSession not implemented")
+}
+func (UnimplementedEntryServiceServer) GetUploadSession(context.Context, *GetUploadSessionRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadSession not implemented")
+}
+func (UnimplementedEntryServiceServer) UploadChunks(grpc.ClientStreamingServer[UploadChunkRequest, UploadSession]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadChunks not implemented")
+}
+func (UnimplementedEntryServiceServer) FinalizeUpload(context.Context, *FinalizeUploadRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalizeUpload not implemented")
+}
+func (UnimplementedEntryServiceServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedEntryServiceServer) RestoreEntry(context.Context, *RestoreEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEntry not implemented")
+}
+func (UnimplementedEntryServiceServer) PurgeEntry(context.Context, *PurgeEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeEntry not implemented")
+}
+func (UnimplementedEntryServiceServer) GetUsage(context.Context, *GetUsageRequest) (*Usage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedEntryServiceServer) SetKeyPair(context.Context, *SetKeyPairRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeyPair not implemented")
+}
+func (UnimplementedEntryServiceServer) GetKeyPair(context.Context, *GetKeyPairRequest) (*KeyPair, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyPair not implemented")
+}
+func (UnimplementedEntryServiceServer) GetPublicKey(context.Context, *GetPublicKeyRequest) (*PublicKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
+}
+func (UnimplementedEntryServiceServer) ShareEntry(grpc.ClientStreamingServer[ShareEntryRequest, Share]) error {
+	return status.Errorf(codes.Unimplemented, "method ShareEntry not implemented")
+}
+func (UnimplementedEntryServiceServer) ListShares(context.Context, *ListSharesRequest) (*ListSharesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShares not implemented")
+}
+func (UnimplementedEntryServiceServer) GetSharedEntry(*GetSharedEntryRequest, grpc.ServerStreamingServer[SharedEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method GetSharedEntry not implemented")
+}
+func (UnimplementedEntryServiceServer) RevokeShare(context.Context, *RevokeShareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShare not implemented")
+}
 func (UnimplementedEntryServiceServer) mustEmbedUnimplementedEntryServiceServer() {}
 func (UnimplementedEntryServiceServer) testEmbeddedByValue()                      {}
 
@@ -167,29 +642,519 @@ func _EntryService_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-// EntryService_ServiceDesc is the grpc.ServiceDesc for EntryService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var EntryService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "com.kuvalkin.gophkeeper.proto.entry.v1.EntryService",
-	HandlerType: (*EntryServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "DeleteEntry",
-			Handler:    _EntryService_DeleteEntry_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "GetEntry",
-			Handler:       _EntryService_GetEntry_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "SetEntry",
-			Handler:       _EntryService_SetEntry_Handler,
-			ServerStreams: true,
-			ClientStreams: true,
+func _EntryService_RenameEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).RenameEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_RenameEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).RenameEntry(ctx, req.(*RenameEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_BatchDeleteEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).BatchDeleteEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_BatchDeleteEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).BatchDeleteEntries(ctx, req.(*BatchDeleteEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_BatchGetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).BatchGetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_BatchGetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).BatchGetMetadata(ctx, req.(*BatchGetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).ListEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_ListEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).ListEntries(ctx, req.(*ListEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_ListEntryVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEntryVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).ListEntryVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_ListEntryVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).ListEntryVersions(ctx, req.(*ListEntryVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetEntryVersion_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEntryVersionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).GetEntryVersion(m, &grpc.GenericServerStream[GetEntryVersionRequest, Entry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_GetEntryVersionServer = grpc.ServerStreamingServer[Entry]
+
+func _EntryService_RestoreEntryVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEntryVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).RestoreEntryVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_RestoreEntryVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).RestoreEntryVersion(ctx, req.(*RestoreEntryVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_ListChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).ListChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_ListChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).ListChanges(ctx, req.(*ListChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_WatchEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEntriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).WatchEntries(m, &grpc.GenericServerStream[WatchEntriesRequest, Change]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_WatchEntriesServer = grpc.ServerStreamingServer[Change]
+
+func _EntryService_CreateUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).CreateUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_CreateUploadSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).CreateUploadSession(ctx, req.(*CreateUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetUploadSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetUploadSession(ctx, req.(*GetUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_UploadChunks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EntryServiceServer).UploadChunks(&grpc.GenericServerStream[UploadChunkRequest, UploadSession]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_UploadChunksServer = grpc.ClientStreamingServer[UploadChunkRequest, UploadSession]
+
+func _EntryService_FinalizeUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizeUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).FinalizeUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_FinalizeUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).FinalizeUpload(ctx, req.(*FinalizeUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_RestoreEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).RestoreEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_RestoreEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).RestoreEntry(ctx, req.(*RestoreEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_PurgeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).PurgeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_PurgeEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).PurgeEntry(ctx, req.(*PurgeEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_SetKeyPair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).SetKeyPair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_SetKeyPair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).SetKeyPair(ctx, req.(*SetKeyPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetKeyPair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetKeyPair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetKeyPair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetKeyPair(ctx, req.(*GetKeyPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetPublicKey(ctx, req.(*GetPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_ShareEntry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EntryServiceServer).ShareEntry(&grpc.GenericServerStream[ShareEntryRequest, Share]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_ShareEntryServer = grpc.ClientStreamingServer[ShareEntryRequest, Share]
+
+func _EntryService_ListShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSharesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).ListShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_ListShares_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).ListShares(ctx, req.(*ListSharesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetSharedEntry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetSharedEntryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).GetSharedEntry(m, &grpc.GenericServerStream[GetSharedEntryRequest, SharedEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_GetSharedEntryServer = grpc.ServerStreamingServer[SharedEntry]
+
+func _EntryService_RevokeShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).RevokeShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_RevokeShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).RevokeShare(ctx, req.(*RevokeShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EntryService_ServiceDesc is the grpc.ServiceDesc for EntryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EntryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "com.kuvalkin.gophkeeper.proto.entry.v1.EntryService",
+	HandlerType: (*EntryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DeleteEntry",
+			Handler:    _EntryService_DeleteEntry_Handler,
+		},
+		{
+			MethodName: "RenameEntry",
+			Handler:    _EntryService_RenameEntry_Handler,
+		},
+		{
+			MethodName: "BatchDeleteEntries",
+			Handler:    _EntryService_BatchDeleteEntries_Handler,
+		},
+		{
+			MethodName: "BatchGetMetadata",
+			Handler:    _EntryService_BatchGetMetadata_Handler,
+		},
+		{
+			MethodName: "ListEntries",
+			Handler:    _EntryService_ListEntries_Handler,
+		},
+		{
+			MethodName: "ListEntryVersions",
+			Handler:    _EntryService_ListEntryVersions_Handler,
+		},
+		{
+			MethodName: "RestoreEntryVersion",
+			Handler:    _EntryService_RestoreEntryVersion_Handler,
+		},
+		{
+			MethodName: "ListChanges",
+			Handler:    _EntryService_ListChanges_Handler,
+		},
+		{
+			MethodName: "CreateUploadSession",
+			Handler:    _EntryService_CreateUploadSession_Handler,
+		},
+		{
+			MethodName: "GetUploadSession",
+			Handler:    _EntryService_GetUploadSession_Handler,
+		},
+		{
+			MethodName: "FinalizeUpload",
+			Handler:    _EntryService_FinalizeUpload_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _EntryService_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreEntry",
+			Handler:    _EntryService_RestoreEntry_Handler,
+		},
+		{
+			MethodName: "PurgeEntry",
+			Handler:    _EntryService_PurgeEntry_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _EntryService_GetUsage_Handler,
+		},
+		{
+			MethodName: "SetKeyPair",
+			Handler:    _EntryService_SetKeyPair_Handler,
+		},
+		{
+			MethodName: "GetKeyPair",
+			Handler:    _EntryService_GetKeyPair_Handler,
+		},
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _EntryService_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "ListShares",
+			Handler:    _EntryService_ListShares_Handler,
+		},
+		{
+			MethodName: "RevokeShare",
+			Handler:    _EntryService_RevokeShare_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetEntry",
+			Handler:       _EntryService_GetEntry_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SetEntry",
+			Handler:       _EntryService_SetEntry_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetEntryVersion",
+			Handler:       _EntryService_GetEntryVersion_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEntries",
+			Handler:       _EntryService_WatchEntries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadChunks",
+			Handler:       _EntryService_UploadChunks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ShareEntry",
+			Handler:       _EntryService_ShareEntry_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetSharedEntry",
+			Handler:       _EntryService_GetSharedEntry_Handler,
+			ServerStreams: true,
 		},
 	},
 	Metadata: "api/proto/entry/v1/entry.proto",
@@ -0,0 +1,632 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: api/proto/vault/v1/vault.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VaultService_CreateVault_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/CreateVault"
+	VaultService_ListVaults_FullMethodName             = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/ListVaults"
+	VaultService_RenameVault_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/RenameVault"
+	VaultService_DeleteVault_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/DeleteVault"
+	VaultService_AddMember_FullMethodName              = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/AddMember"
+	VaultService_ListMembers_FullMethodName            = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/ListMembers"
+	VaultService_RemoveMember_FullMethodName           = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/RemoveMember"
+	VaultService_GrantEmergencyAccess_FullMethodName   = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/GrantEmergencyAccess"
+	VaultService_RevokeEmergencyAccess_FullMethodName  = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/RevokeEmergencyAccess"
+	VaultService_ListEmergencyGrants_FullMethodName    = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/ListEmergencyGrants"
+	VaultService_RequestEmergencyAccess_FullMethodName = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/RequestEmergencyAccess"
+	VaultService_RejectEmergencyAccess_FullMethodName  = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/RejectEmergencyAccess"
+	VaultService_OpenEmergencyAccess_FullMethodName    = "/com.kuvalkin.gophkeeper.proto.vault.v1.VaultService/OpenEmergencyAccess"
+)
+
+// VaultServiceClient is the client API for VaultService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VaultService manages vaults, separate sets of entries with their own keys: team vaults shared by several users,
+// and personal vaults, which only ever have their creator as a member.
+// The entries of a vault are managed with EntryService by setting the vault id in the x-vault-id request metadata.
+// Viewers can read the entries, editors can change them too, and owners can also manage the members.
+// The entries are encrypted with the vault key, which the server never sees: every member has it wrapped to their public key.
+type VaultServiceClient interface {
+	// CreateVault creates a vault with the user as its owner.
+	CreateVault(ctx context.Context, in *CreateVaultRequest, opts ...grpc.CallOption) (*Vault, error)
+	// ListVaults returns the vaults the user is a member of.
+	ListVaults(ctx context.Context, in *ListVaultsRequest, opts ...grpc.CallOption) (*ListVaultsResponse, error)
+	// RenameVault changes the name of the vault. Only owners can rename it, others get PERMISSION_DENIED.
+	RenameVault(ctx context.Context, in *RenameVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeleteVault deletes the vault along with its members. Only owners can delete it, others get PERMISSION_DENIED.
+	// It fails with FAILED_PRECONDITION if the vault still has entries, including the ones in the trash.
+	DeleteVault(ctx context.Context, in *DeleteVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// AddMember adds a user to the vault. Only owners can add members, others get PERMISSION_DENIED.
+	// It fails with NOT_FOUND if there is no such vault or user, ALREADY_EXISTS if the user is a member already,
+	// and FAILED_PRECONDITION if the vault is personal.
+	AddMember(ctx context.Context, in *AddMemberRequest, opts ...grpc.CallOption) (*Member, error)
+	// ListMembers returns the members of the vault, it fails with NOT_FOUND unless the user is one of them.
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error)
+	// RemoveMember removes a user from the vault. Owners can remove anyone, other members only themselves.
+	// It fails with FAILED_PRECONDITION if the user is the last owner of the vault.
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GrantEmergencyAccess designates a trusted contact of the vault. Only owners can grant, others get PERMISSION_DENIED.
+	// It fails with NOT_FOUND if there is no such vault or user, and ALREADY_EXISTS if the user is a contact already.
+	GrantEmergencyAccess(ctx context.Context, in *GrantEmergencyAccessRequest, opts ...grpc.CallOption) (*EmergencyGrant, error)
+	// RevokeEmergencyAccess removes the contact of the vault, the pending request if any is lost. Only owners can revoke.
+	// For the own entries it removes the opened access as well.
+	RevokeEmergencyAccess(ctx context.Context, in *RevokeEmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListEmergencyGrants returns the grants of the vaults the user owns, or the ones the user is the contact of if incoming is set.
+	ListEmergencyGrants(ctx context.Context, in *ListEmergencyGrantsRequest, opts ...grpc.CallOption) (*ListEmergencyGrantsResponse, error)
+	// RequestEmergencyAccess starts the waiting period of the grant the user is the contact of.
+	// Requesting again doesn't restart it. It fails with NOT_FOUND if there is no such grant.
+	RequestEmergencyAccess(ctx context.Context, in *RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*EmergencyGrant, error)
+	// RejectEmergencyAccess cancels the pending request of the contact, the grant itself is kept. Only owners can reject.
+	RejectEmergencyAccess(ctx context.Context, in *RejectEmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// OpenEmergencyAccess releases the wrapped vault key to the contact, who becomes a viewer of the vault, and removes the grant.
+	// It fails with FAILED_PRECONDITION if the access wasn't requested or the waiting period hasn't passed yet.
+	OpenEmergencyAccess(ctx context.Context, in *OpenEmergencyAccessRequest, opts ...grpc.CallOption) (*Vault, error)
+}
+
+type vaultServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVaultServiceClient(cc grpc.ClientConnInterface) VaultServiceClient {
+	return &vaultServiceClient{cc}
+}
+
+func (c *vaultServiceClient) CreateVault(ctx context.Context, in *CreateVaultRequest, opts ...grpc.CallOption) (*Vault, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vault)
+	err := c.cc.Invoke(ctx, VaultService_CreateVault_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) ListVaults(ctx context.Context, in *ListVaultsRequest, opts ...grpc.CallOption) (*ListVaultsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVaultsResponse)
+	err := c.cc.Invoke(ctx, VaultService_ListVaults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) RenameVault(ctx context.Context, in *RenameVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, VaultService_RenameVault_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) DeleteVault(ctx context.Context, in *DeleteVaultRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, VaultService_DeleteVault_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) AddMember(ctx context.Context, in *AddMemberRequest, opts ...grpc.CallOption) (*Member, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Member)
+	err := c.cc.Invoke(ctx, VaultService_AddMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMembersResponse)
+	err := c.cc.Invoke(ctx, VaultService_ListMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, VaultService_RemoveMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) GrantEmergencyAccess(ctx context.Context, in *GrantEmergencyAccessRequest, opts ...grpc.CallOption) (*EmergencyGrant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmergencyGrant)
+	err := c.cc.Invoke(ctx, VaultService_GrantEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) RevokeEmergencyAccess(ctx context.Context, in *RevokeEmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, VaultService_RevokeEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) ListEmergencyGrants(ctx context.Context, in *ListEmergencyGrantsRequest, opts ...grpc.CallOption) (*ListEmergencyGrantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmergencyGrantsResponse)
+	err := c.cc.Invoke(ctx, VaultService_ListEmergencyGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) RequestEmergencyAccess(ctx context.Context, in *RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*EmergencyGrant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmergencyGrant)
+	err := c.cc.Invoke(ctx, VaultService_RequestEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) RejectEmergencyAccess(ctx context.Context, in *RejectEmergencyAccessRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, VaultService_RejectEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultServiceClient) OpenEmergencyAccess(ctx context.Context, in *OpenEmergencyAccessRequest, opts ...grpc.CallOption) (*Vault, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vault)
+	err := c.cc.Invoke(ctx, VaultService_OpenEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VaultServiceServer is the server API for VaultService service.
+// All implementations must embed UnimplementedVaultServiceServer
+// for forward compatibility.
+//
+// VaultService manages vaults, separate sets of entries with their own keys: team vaults shared by several users,
+// and personal vaults, which only ever have their creator as a member.
+// The entries of a vault are managed with EntryService by setting the vault id in the x-vault-id request metadata.
+// Viewers can read the entries, editors can change them too, and owners can also manage the members.
+// The entries are encrypted with the vault key, which the server never sees: every member has it wrapped to their public key.
+type VaultServiceServer interface {
+	// CreateVault creates a vault with the user as its owner.
+	CreateVault(context.Context, *CreateVaultRequest) (*Vault, error)
+	// ListVaults returns the vaults the user is a member of.
+	ListVaults(context.Context, *ListVaultsRequest) (*ListVaultsResponse, error)
+	// RenameVault changes the name of the vault. Only owners can rename it, others get PERMISSION_DENIED.
+	RenameVault(context.Context, *RenameVaultRequest) (*emptypb.Empty, error)
+	// DeleteVault deletes the vault along with its members. Only owners can delete it, others get PERMISSION_DENIED.
+	// It fails with FAILED_PRECONDITION if the vault still has entries, including the ones in the trash.
+	DeleteVault(context.Context, *DeleteVaultRequest) (*emptypb.Empty, error)
+	// AddMember adds a user to the vault. Only owners can add members, others get PERMISSION_DENIED.
+	// It fails with NOT_FOUND if there is no such vault or user, ALREADY_EXISTS if the user is a member already,
+	// and FAILED_PRECONDITION if the vault is personal.
+	AddMember(context.Context, *AddMemberRequest) (*Member, error)
+	// ListMembers returns the members of the vault, it fails with NOT_FOUND unless the user is one of them.
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error)
+	// RemoveMember removes a user from the vault. Owners can remove anyone, other members only themselves.
+	// It fails with FAILED_PRECONDITION if the user is the last owner of the vault.
+	RemoveMember(context.Context, *RemoveMemberRequest) (*emptypb.Empty, error)
+	// GrantEmergencyAccess designates a trusted contact of the vault. Only owners can grant, others get PERMISSION_DENIED.
+	// It fails with NOT_FOUND if there is no such vault or user, and ALREADY_EXISTS if the user is a contact already.
+	GrantEmergencyAccess(context.Context, *GrantEmergencyAccessRequest) (*EmergencyGrant, error)
+	// RevokeEmergencyAccess removes the contact of the vault, the pending request if any is lost. Only owners can revoke.
+	// For the own entries it removes the opened access as well.
+	RevokeEmergencyAccess(context.Context, *RevokeEmergencyAccessRequest) (*emptypb.Empty, error)
+	// ListEmergencyGrants returns the grants of the vaults the user owns, or the ones the user is the contact of if incoming is set.
+	ListEmergencyGrants(context.Context, *ListEmergencyGrantsRequest) (*ListEmergencyGrantsResponse, error)
+	// RequestEmergencyAccess starts the waiting period of the grant the user is the contact of.
+	// Requesting again doesn't restart it. It fails with NOT_FOUND if there is no such grant.
+	RequestEmergencyAccess(context.Context, *RequestEmergencyAccessRequest) (*EmergencyGrant, error)
+	// RejectEmergencyAccess cancels the pending request of the contact, the grant itself is kept. Only owners can reject.
+	RejectEmergencyAccess(context.Context, *RejectEmergencyAccessRequest) (*emptypb.Empty, error)
+	// OpenEmergencyAccess releases the wrapped vault key to the contact, who becomes a viewer of the vault, and removes the grant.
+	// It fails with FAILED_PRECONDITION if the access wasn't requested or the waiting period hasn't passed yet.
+	OpenEmergencyAccess(context.Context, *OpenEmergencyAccessRequest) (*Vault, error)
+	mustEmbedUnimplementedVaultServiceServer()
+}
+
+// UnimplementedVaultServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVaultServiceServer struct{}
+
+func (UnimplementedVaultServiceServer) CreateVault(context.Context, *CreateVaultRequest) (*Vault, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVault not implemented")
+}
+func (UnimplementedVaultServiceServer) ListVaults(context.Context, *ListVaultsRequest) (*ListVaultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVaults not implemented")
+}
+func (UnimplementedVaultServiceServer) RenameVault(context.Context, *RenameVaultRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameVault not implemented")
+}
+func (UnimplementedVaultServiceServer) DeleteVault(context.Context, *DeleteVaultRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVault not implemented")
+}
+func (UnimplementedVaultServiceServer) AddMember(context.Context, *AddMemberRequest) (*Member, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMember not implemented")
+}
+func (UnimplementedVaultServiceServer) ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedVaultServiceServer) RemoveMember(context.Context, *RemoveMemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedVaultServiceServer) GrantEmergencyAccess(context.Context, *GrantEmergencyAccessRequest) (*EmergencyGrant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantEmergencyAccess not implemented")
+}
+func (UnimplementedVaultServiceServer) RevokeEmergencyAccess(context.Context, *RevokeEmergencyAccessRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeEmergencyAccess not implemented")
+}
+func (UnimplementedVaultServiceServer) ListEmergencyGrants(context.Context, *ListEmergencyGrantsRequest) (*ListEmergencyGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmergencyGrants not implemented")
+}
+func (UnimplementedVaultServiceServer) RequestEmergencyAccess(context.Context, *RequestEmergencyAccessRequest) (*EmergencyGrant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmergencyAccess not implemented")
+}
+func (UnimplementedVaultServiceServer) RejectEmergencyAccess(context.Context, *RejectEmergencyAccessRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectEmergencyAccess not implemented")
+}
+func (UnimplementedVaultServiceServer) OpenEmergencyAccess(context.Context, *OpenEmergencyAccessRequest) (*Vault, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenEmergencyAccess not implemented")
+}
+func (UnimplementedVaultServiceServer) mustEmbedUnimplementedVaultServiceServer() {}
+func (UnimplementedVaultServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeVaultServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VaultServiceServer will
+// result in compilation errors.
+type UnsafeVaultServiceServer interface {
+	mustEmbedUnimplementedVaultServiceServer()
+}
+
+func RegisterVaultServiceServer(s grpc.ServiceRegistrar, srv VaultServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVaultServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VaultService_ServiceDesc, srv)
+}
+
+func _VaultService_CreateVault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).CreateVault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_CreateVault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).CreateVault(ctx, req.(*CreateVaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_ListVaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).ListVaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_ListVaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).ListVaults(ctx, req.(*ListVaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_RenameVault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameVaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).RenameVault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_RenameVault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).RenameVault(ctx, req.(*RenameVaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_DeleteVault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).DeleteVault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_DeleteVault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).DeleteVault(ctx, req.(*DeleteVaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_AddMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).AddMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_AddMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).AddMember(ctx, req.(*AddMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_ListMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).ListMembers(ctx, req.(*ListMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_RemoveMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_GrantEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).GrantEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_GrantEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).GrantEmergencyAccess(ctx, req.(*GrantEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_RevokeEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).RevokeEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_RevokeEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).RevokeEmergencyAccess(ctx, req.(*RevokeEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_ListEmergencyGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmergencyGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).ListEmergencyGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_ListEmergencyGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).ListEmergencyGrants(ctx, req.(*ListEmergencyGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_RequestEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).RequestEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_RequestEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).RequestEmergencyAccess(ctx, req.(*RequestEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_RejectEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).RejectEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_RejectEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).RejectEmergencyAccess(ctx, req.(*RejectEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VaultService_OpenEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServiceServer).OpenEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VaultService_OpenEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServiceServer).OpenEmergencyAccess(ctx, req.(*OpenEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VaultService_ServiceDesc is the grpc.ServiceDesc for VaultService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VaultService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "com.kuvalkin.gophkeeper.proto.vault.v1.VaultService",
+	HandlerType: (*VaultServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateVault",
+			Handler:    _VaultService_CreateVault_Handler,
+		},
+		{
+			MethodName: "ListVaults",
+			Handler:    _VaultService_ListVaults_Handler,
+		},
+		{
+			MethodName: "RenameVault",
+			Handler:    _VaultService_RenameVault_Handler,
+		},
+		{
+			MethodName: "DeleteVault",
+			Handler:    _VaultService_DeleteVault_Handler,
+		},
+		{
+			MethodName: "AddMember",
+			Handler:    _VaultService_AddMember_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _VaultService_ListMembers_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _VaultService_RemoveMember_Handler,
+		},
+		{
+			MethodName: "GrantEmergencyAccess",
+			Handler:    _VaultService_GrantEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "RevokeEmergencyAccess",
+			Handler:    _VaultService_RevokeEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "ListEmergencyGrants",
+			Handler:    _VaultService_ListEmergencyGrants_Handler,
+		},
+		{
+			MethodName: "RequestEmergencyAccess",
+			Handler:    _VaultService_RequestEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "RejectEmergencyAccess",
+			Handler:    _VaultService_RejectEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "OpenEmergencyAccess",
+			Handler:    _VaultService_OpenEmergencyAccess_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/proto/vault/v1/vault.proto",
+}